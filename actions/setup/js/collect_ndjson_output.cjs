@@ -8,6 +8,7 @@ const { ERR_API, ERR_PARSE } = require("./error_codes.cjs");
 const { isPayloadUserBot } = require("./resolve_mentions.cjs");
 const { parseIntTemplatable } = require("./templatable.cjs");
 const { parseAllowedRepos, validateTargetRepo } = require("./repo_helpers.cjs");
+const { checkToolConstraints } = require("./safe_output_constraints.cjs");
 
 async function main() {
   try {
@@ -305,6 +306,11 @@ async function main() {
           errors.push(`Line ${i + 1}: Too many items of type '${itemType}'. Maximum allowed: ${maxAllowed}.`);
           continue;
         }
+        const constraintViolation = checkToolConstraints(item, expectedOutputTypes[itemType].constraints);
+        if (constraintViolation) {
+          errors.push(`Line ${i + 1}: '${itemType}' rejected by constraint on '${constraintViolation.argument}': ${constraintViolation.detail}`);
+          continue;
+        }
         core.info(`Line ${i + 1}: type '${itemType}'`);
 
         // Use the validation engine to validate the item
@@ -2,12 +2,14 @@
 /// <reference types="@actions/github-script" />
 
 // interpolate_prompt.cjs
-// Interpolates GitHub Actions expressions and renders template conditionals in the prompt file.
-// This combines variable interpolation and template filtering into a single step.
+// Interpolates GitHub Actions expressions and renders template conditionals, partials,
+// loops and prompt variables in the prompt file.
+// This combines variable interpolation and template rendering into a single step.
 
 const fs = require("fs");
 const { isTruthy } = require("./is_truthy.cjs");
 const { processRuntimeImports } = require("./runtime_import.cjs");
+const { loadPromptVariables, renderPromptTemplate, restorePromptValues } = require("./prompt_template.cjs");
 const { getErrorMessage } = require("./error_helpers.cjs");
 const { ERR_API, ERR_CONFIG, ERR_VALIDATION } = require("./error_codes.cjs");
 
@@ -184,9 +186,22 @@ async function main() {
       core.info("No runtime import macros found, skipping runtime import processing");
     }
 
-    // Step 2: Interpolate variables
+    // Step 2: Render partials, loops and prompt variables
+    // This runs before interpolation so that GitHub Actions expression values are never
+    // interpreted as partials or prompt variables
     core.info("\n========================================");
-    core.info("[main] STEP 2: Variable Interpolation");
+    core.info("[main] STEP 2: Partials and Prompt Variables");
+    core.info("========================================");
+    const promptVariables = loadPromptVariables(process.env);
+    core.info(`Loaded ${Object.keys(promptVariables).length} prompt variable(s)`);
+    const beforeTemplates = content.length;
+    const rendered = renderPromptTemplate(content, promptVariables);
+    content = rendered.content;
+    core.info(`Content length change: ${beforeTemplates} -> ${content.length} (${content.length > beforeTemplates ? "+" : ""}${content.length - beforeTemplates})`);
+
+    // Step 3: Interpolate variables
+    core.info("\n========================================");
+    core.info("[main] STEP 3: Variable Interpolation");
     core.info("========================================");
     /** @type {Record<string, string>} */
     const variables = {};
@@ -214,9 +229,9 @@ async function main() {
       core.info("No expression variables found, skipping interpolation");
     }
 
-    // Step 3: Render template conditionals
+    // Step 4: Render template conditionals
     core.info("\n========================================");
-    core.info("[main] STEP 3: Template Rendering");
+    core.info("[main] STEP 4: Template Rendering");
     core.info("========================================");
     const hasConditionals = /{{#if\s+[^}]+}}/.test(content);
     if (hasConditionals) {
@@ -233,9 +248,21 @@ async function main() {
       core.info("No conditional blocks found in prompt, skipping template rendering");
     }
 
+    // Step 5: Insert prompt variable values
+    // Values are inserted last so that they are never rendered as templates
+    core.info("\n========================================");
+    core.info("[main] STEP 5: Prompt Variable Values");
+    core.info("========================================");
+    if (rendered.values.length > 0) {
+      content = restorePromptValues(content, rendered.values, rendered.placeholder);
+      core.info(`Inserted ${rendered.values.length} prompt variable value(s)`);
+    } else {
+      core.info("No prompt variable values to insert");
+    }
+
     // Write back to the same file
     core.info("\n========================================");
-    core.info("[main] STEP 4: Writing Output");
+    core.info("[main] STEP 6: Writing Output");
     core.info("========================================");
     core.info(`Writing processed content back to: ${promptPath}`);
     core.info(`Final content length: ${content.length} characters`);
@@ -35,7 +35,8 @@ function createShellHandler(server, toolName, scriptPath, timeoutSeconds = 60) {
     const env = { ...process.env };
     for (const [key, value] of Object.entries(args || {})) {
       const envKey = `INPUT_${key.toUpperCase().replace(/-/g, "_")}`;
-      env[envKey] = String(value);
+      // Object and array inputs are JSON-encoded so nested values survive the environment
+      env[envKey] = value !== null && typeof value === "object" ? JSON.stringify(value) : String(value);
       server.debug(`  [${toolName}] Set env: ${envKey}=${String(value).substring(0, 100)}${String(value).length > 100 ? "..." : ""}`);
     }
 
@@ -31,7 +31,7 @@ const fs = require("fs");
 const path = require("path");
 
 const { ReadBuffer } = require("./read_buffer.cjs");
-const { validateRequiredFields } = require("./safe_inputs_validation.cjs");
+const { validateRequiredFields, validateToolOutput } = require("./safe_inputs_validation.cjs");
 const { getErrorMessage } = require("./error_helpers.cjs");
 const { generateEnhancedErrorMessage } = require("./mcp_enhanced_errors.cjs");
 
@@ -48,6 +48,7 @@ const encoder = new TextEncoder();
  * @property {string} name - Tool name
  * @property {string} description - Tool description
  * @property {Object} inputSchema - JSON Schema for tool inputs
+ * @property {Object} [outputSchema] - Optional JSON Schema the tool result must conform to
  * @property {Function} [handler] - Tool handler function
  * @property {string} [handlerPath] - Optional file path to handler module (original path from config)
  * @property {number} [timeout] - Timeout in seconds for tool execution (default: 60)
@@ -631,6 +632,13 @@ async function handleRequest(server, request, defaultHandler) {
 
       // Call handler and await the result (supports both sync and async handlers)
       const handlerResult = await Promise.resolve(handler(args));
+      const outputErrors = validateToolOutput(handlerResult, tool.outputSchema);
+      if (outputErrors.length) {
+        throw {
+          code: -32603,
+          message: `Output of tool '${name}' does not match its output schema: ${outputErrors.join("; ")}`,
+        };
+      }
       const content = handlerResult && handlerResult.content ? handlerResult.content : [];
       result = { content, isError: false };
     } else if (/^notifications\//.test(method)) {
@@ -755,6 +763,11 @@ async function handleMessage(server, req, defaultHandler) {
       server.debug(`Calling handler for tool: ${name}`);
       const result = await Promise.resolve(handler(args));
       server.debug(`Handler returned for tool: ${name}`);
+      const outputErrors = validateToolOutput(result, tool.outputSchema);
+      if (outputErrors.length) {
+        server.replyError(id, -32603, `Output of tool '${name}' does not match its output schema: ${outputErrors.join("; ")}`);
+        return;
+      }
       const content = result && result.content ? result.content : [];
       server.replyResult(id, { content, isError: false });
     } else if (/^notifications\//.test(method)) {
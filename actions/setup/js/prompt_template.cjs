@@ -0,0 +1,404 @@
+// @ts-check
+/// <reference types="@actions/github-script" />
+
+// prompt_template.cjs
+// Logic-less prompt templates: partials, {{#each}} loops and typed prompt variables.
+//
+// Supported syntax:
+//   {{#partial name}} ... {{/partial}}   defines a partial (removed from the output)
+//   {{> name}}                           inserts a partial
+//   {{var.name}} / {{var.name.field}}    inserts a prompt variable
+//   {{#if var.name}}                     tests a prompt variable (resolved before conditionals are rendered)
+//   {{#each var.name}} ... {{/each}}     repeats a block for each item of an array variable
+//                                        ({{this}}, {{this.field}}, {{@index}}, {{@number}} inside the block)
+//
+// Templates are rendered with regular expressions only. Nothing is evaluated as code,
+// and substituted values are never re-scanned for template syntax: templates are rendered
+// before GitHub Actions expressions are interpolated, and variable values are held in
+// placeholders until the conditionals have been rendered.
+
+const crypto = require("crypto");
+const { isTruthy } = require("./is_truthy.cjs");
+const { ERR_CONFIG, ERR_VALIDATION } = require("./error_codes.cjs");
+
+const MAX_PARTIAL_DEPTH = 10;
+
+/**
+ * @typedef {Object} PromptVariableDeclaration
+ * @property {string} type - string, number, boolean, array or object
+ * @property {any} [default] - Default value
+ * @property {string} [env] - Environment variable holding the runtime value
+ */
+
+/**
+ * Checks whether content uses partials
+ * @param {string} content - The prompt content
+ * @returns {boolean}
+ */
+function hasPartials(content) {
+  return /\{\{#partial\s|\{\{>/.test(content);
+}
+
+/**
+ * Checks whether content references prompt variables or loops
+ * @param {string} content - The prompt content
+ * @returns {boolean}
+ */
+function hasPromptVariables(content) {
+  return /\{\{(#if\s+|#each\s+|\s*)var\./.test(content);
+}
+
+/**
+ * Coerces a raw runtime value to the declared variable type
+ * @param {string} raw - The raw value from the environment
+ * @param {string} type - The declared type
+ * @param {string} name - The variable name (for error messages)
+ * @returns {any}
+ */
+function coercePromptVariable(raw, type, name) {
+  switch (type) {
+    case "number": {
+      const value = Number(raw.trim());
+      if (Number.isNaN(value)) {
+        throw new Error(`${ERR_VALIDATION}: Prompt variable '${name}' expects a number, got "${raw}"`);
+      }
+      return value;
+    }
+    case "boolean": {
+      const normalized = raw.trim().toLowerCase();
+      if (normalized === "true" || normalized === "1" || normalized === "yes") {
+        return true;
+      }
+      if (normalized === "false" || normalized === "0" || normalized === "no") {
+        return false;
+      }
+      throw new Error(`${ERR_VALIDATION}: Prompt variable '${name}' expects a boolean, got "${raw}"`);
+    }
+    case "array": {
+      try {
+        const parsed = JSON.parse(raw);
+        if (Array.isArray(parsed)) {
+          return parsed;
+        }
+      } catch {
+        // Not JSON - fall back to a newline or comma separated list
+      }
+      return raw
+        .split(/[\n,]/)
+        .map(item => item.trim())
+        .filter(item => item !== "");
+    }
+    case "object": {
+      let parsed;
+      try {
+        parsed = JSON.parse(raw);
+      } catch {
+        throw new Error(`${ERR_VALIDATION}: Prompt variable '${name}' expects a JSON object, got "${raw}"`);
+      }
+      if (parsed === null || typeof parsed !== "object" || Array.isArray(parsed)) {
+        throw new Error(`${ERR_VALIDATION}: Prompt variable '${name}' expects a JSON object, got "${raw}"`);
+      }
+      return parsed;
+    }
+    default:
+      return raw;
+  }
+}
+
+/**
+ * Returns the empty value for a variable type
+ * @param {string} type - The declared type
+ * @returns {any}
+ */
+function emptyPromptVariable(type) {
+  switch (type) {
+    case "number":
+      return 0;
+    case "boolean":
+      return false;
+    case "array":
+      return [];
+    case "object":
+      return {};
+    default:
+      return "";
+  }
+}
+
+/**
+ * Loads the prompt variables declared by the compiler.
+ * GH_AW_PROMPT_VARIABLES holds the declarations; variables bound to an expression
+ * read their evaluated value from the environment variable named in the declaration
+ * and fall back to the default when it is empty.
+ * @param {Record<string, string | undefined>} env - The process environment
+ * @returns {Record<string, any>} - Map of variable names to typed values
+ */
+function loadPromptVariables(env) {
+  const declarationsJSON = env.GH_AW_PROMPT_VARIABLES;
+  if (!declarationsJSON) {
+    return {};
+  }
+
+  /** @type {Record<string, PromptVariableDeclaration>} */
+  let declarations;
+  try {
+    declarations = JSON.parse(declarationsJSON);
+  } catch (error) {
+    throw new Error(`${ERR_CONFIG}: GH_AW_PROMPT_VARIABLES is not valid JSON: ${error instanceof Error ? error.message : String(error)}`);
+  }
+
+  /** @type {Record<string, any>} */
+  const variables = {};
+  for (const [name, declaration] of Object.entries(declarations)) {
+    const raw = declaration.env ? env[declaration.env] : undefined;
+    if (raw !== undefined && raw !== "") {
+      variables[name] = coercePromptVariable(raw, declaration.type, name);
+    } else if (declaration.default !== undefined) {
+      variables[name] = declaration.default;
+    } else {
+      variables[name] = emptyPromptVariable(declaration.type);
+    }
+  }
+  return variables;
+}
+
+/**
+ * Removes partial definitions from the content and inserts partials where they are referenced.
+ * A reference on its own line is replaced by the partial body; inline references are
+ * replaced by the body without its trailing newline.
+ * @param {string} content - The prompt content
+ * @returns {string} - Content with partials expanded
+ */
+function expandPartials(content) {
+  /** @type {Map<string, string>} */
+  const partials = new Map();
+
+  const withoutDefinitions = content.replace(/^[ \t]*\{\{#partial\s+([A-Za-z0-9_-]+)\s*\}\}[ \t]*\n?([\s\S]*?)[ \t]*\{\{\/partial\}\}[ \t]*(?:\n|$)/gm, (_, name, body) => {
+    if (partials.has(name)) {
+      throw new Error(`${ERR_VALIDATION}: Partial '${name}' is defined more than once`);
+    }
+    partials.set(name, body);
+    return "";
+  });
+
+  if (partials.size > 0) {
+    core.info(`[expandPartials] Found ${partials.size} partial definition(s): ${Array.from(partials.keys()).join(", ")}`);
+  }
+
+  /**
+   * @param {string} text - Text that may contain partial references
+   * @param {string[]} stack - Partials currently being expanded
+   * @returns {string}
+   */
+  const expand = (text, stack) =>
+    text.replace(/^([ \t]*)\{\{>\s*([A-Za-z0-9_-]+)\s*\}\}[ \t]*(\n|$)|\{\{>\s*([^}]*?)\s*\}\}/gm, (_, indent, standaloneName, newline, inlineName) => {
+      const name = standaloneName || inlineName;
+      const body = partials.get(name);
+      if (body === undefined) {
+        throw new Error(`${ERR_VALIDATION}: Partial '${name}' is not defined`);
+      }
+      if (stack.includes(name)) {
+        throw new Error(`${ERR_VALIDATION}: Partial '${name}' includes itself: ${[...stack, name].join(" -> ")}`);
+      }
+      if (stack.length >= MAX_PARTIAL_DEPTH) {
+        throw new Error(`${ERR_VALIDATION}: Partials are nested more than ${MAX_PARTIAL_DEPTH} levels deep`);
+      }
+      const expanded = expand(body, [...stack, name]);
+      if (standaloneName) {
+        return expanded.endsWith("\n") || newline === "" ? expanded : expanded + newline;
+      }
+      return expanded.replace(/\n$/, "");
+    });
+
+  return expand(withoutDefinitions, []);
+}
+
+/**
+ * Looks up a dotted path (e.g. ".owner.login") in a value using own properties only
+ * @param {any} value - The root value
+ * @param {string} path - The path, starting with "." or empty
+ * @returns {any}
+ */
+function lookupPath(value, path) {
+  let current = value;
+  for (const key of path.split(".").filter(Boolean)) {
+    if (current === null || typeof current !== "object" || !Object.prototype.hasOwnProperty.call(current, key)) {
+      return undefined;
+    }
+    current = current[key];
+  }
+  return current;
+}
+
+/**
+ * Formats a value for insertion into the prompt
+ * @param {any} value - The value
+ * @returns {string}
+ */
+function formatValue(value) {
+  if (value === undefined || value === null) {
+    return "";
+  }
+  if (typeof value === "object") {
+    return JSON.stringify(value);
+  }
+  return String(value);
+}
+
+/**
+ * Evaluates the truthiness of a prompt variable value
+ * @param {any} value - The value
+ * @returns {boolean}
+ */
+function isValueTruthy(value) {
+  if (Array.isArray(value)) {
+    return value.length > 0;
+  }
+  if (value !== null && typeof value === "object") {
+    return Object.keys(value).length > 0;
+  }
+  return isTruthy(formatValue(value));
+}
+
+/**
+ * Returns a declared variable or throws if it is unknown
+ * @param {Record<string, any>} variables - The prompt variables
+ * @param {string} name - The variable name
+ * @returns {any}
+ */
+function getVariable(variables, name) {
+  if (!Object.prototype.hasOwnProperty.call(variables, name)) {
+    throw new Error(`${ERR_VALIDATION}: Prompt variable '${name}' is not declared in prompt-variables`);
+  }
+  return variables[name];
+}
+
+/**
+ * Replaces {{#if var.x}} conditions with literal true/false so that the
+ * conditional renderer can process them like any other condition
+ * @param {string} content - The prompt content
+ * @param {Record<string, any>} variables - The prompt variables
+ * @returns {string}
+ */
+function resolveVariableConditionals(content, variables) {
+  return content.replace(/\{\{#if\s+var\.([A-Za-z0-9_-]+)((?:\.[A-Za-z0-9_-]+)*)\s*\}\}/g, (_, name, path) => {
+    const value = lookupPath(getVariable(variables, name), path);
+    return `{{#if ${isValueTruthy(value) ? "true" : "false"}}}`;
+  });
+}
+
+/**
+ * Renders the body of an {{#each}} block once per item
+ * @param {string} body - The block body
+ * @param {any[]} items - The items to iterate over
+ * @param {Record<string, any>} variables - The prompt variables
+ * @param {(value: string) => string} substitute - Maps each formatted value to the text inserted in the prompt
+ * @returns {string}
+ */
+function renderEach(body, items, variables, substitute) {
+  return items
+    .map((item, index) =>
+      body.replace(/\{\{\s*(this|@index|@number|var\.([A-Za-z0-9_-]+))((?:\.[A-Za-z0-9_-]+)*)\s*\}\}/g, (_, token, varName, path) => {
+        if (token === "@index") {
+          return String(index);
+        }
+        if (token === "@number") {
+          return String(index + 1);
+        }
+        const root = varName ? getVariable(variables, varName) : item;
+        return substitute(formatValue(lookupPath(root, path)));
+      })
+    )
+    .join("");
+}
+
+/**
+ * Renders {{#each var.x}} blocks and {{var.x}} references in a single pass, so
+ * substituted values are never interpreted as template syntax
+ * @param {string} content - The prompt content
+ * @param {Record<string, any>} variables - The prompt variables
+ * @param {(value: string) => string} [substitute] - Maps each formatted value to the text inserted in the prompt
+ * @returns {string}
+ */
+function renderLoopsAndVariables(content, variables, substitute = value => value) {
+  let loops = 0;
+  let references = 0;
+
+  const pattern =
+    /^[ \t]*\{\{#each\s+var\.([A-Za-z0-9_-]+)\s*\}\}[ \t]*\n([\s\S]*?)^[ \t]*\{\{\/each\}\}[ \t]*(?:\n|$)|\{\{#each\s+var\.([A-Za-z0-9_-]+)\s*\}\}([\s\S]*?)\{\{\/each\}\}|\{\{\s*var\.([A-Za-z0-9_-]+)((?:\.[A-Za-z0-9_-]+)*)\s*\}\}/gm;
+
+  const result = content.replace(pattern, (_, blockName, blockBody, inlineName, inlineBody, varName, path) => {
+    if (varName) {
+      references++;
+      return substitute(formatValue(lookupPath(getVariable(variables, varName), path)));
+    }
+
+    const name = blockName || inlineName;
+    const items = getVariable(variables, name);
+    if (!Array.isArray(items)) {
+      throw new Error(`${ERR_VALIDATION}: {{#each var.${name}}} requires an array, got ${typeof items}`);
+    }
+    loops++;
+    core.info(`[renderLoopsAndVariables] Loop over '${name}': ${items.length} item(s)`);
+    return renderEach(blockName ? blockBody : inlineBody, items, variables, substitute);
+  });
+
+  core.info(`[renderLoopsAndVariables] Rendered ${loops} loop(s) and ${references} variable reference(s)`);
+  return result;
+}
+
+/**
+ * Renders partials, prompt variable conditionals, loops and variable references.
+ * This runs before GitHub Actions expressions are interpolated, so untrusted expression
+ * values (an issue body containing "{{> x}}", for example) are never rendered as templates.
+ * Substituted variable values are inserted as placeholders that restorePromptValues
+ * replaces once the remaining conditionals are rendered, so they are not re-scanned either.
+ * @param {string} content - The prompt content
+ * @param {Record<string, any>} variables - The prompt variables
+ * @returns {{content: string, values: string[], placeholder: string}}
+ */
+function renderPromptTemplate(content, variables) {
+  const placeholder = `__GH_AW_PROMPT_VALUE_${crypto.randomBytes(8).toString("hex")}_`;
+  /** @type {string[]} */
+  const values = [];
+
+  let result = content;
+  if (hasPartials(result)) {
+    result = expandPartials(result);
+  }
+  if (hasPromptVariables(result)) {
+    result = resolveVariableConditionals(result, variables);
+    result = renderLoopsAndVariables(result, variables, value => {
+      values.push(value);
+      return `${placeholder}${values.length - 1}__`;
+    });
+  }
+  return { content: result, values, placeholder };
+}
+
+/**
+ * Replaces the placeholders inserted by renderPromptTemplate with the variable values
+ * @param {string} content - The prompt content
+ * @param {string[]} values - The substituted values
+ * @param {string} placeholder - The placeholder prefix
+ * @returns {string}
+ */
+function restorePromptValues(content, values, placeholder) {
+  if (values.length === 0) {
+    return content;
+  }
+  const pattern = new RegExp(`${placeholder}(\\d+)__`, "g");
+  return content.replace(pattern, (match, index) => values[Number(index)] ?? match);
+}
+
+module.exports = {
+  hasPartials,
+  hasPromptVariables,
+  coercePromptVariable,
+  loadPromptVariables,
+  expandPartials,
+  resolveVariableConditionals,
+  renderLoopsAndVariables,
+  renderPromptTemplate,
+  restorePromptValues,
+};
@@ -0,0 +1,167 @@
+import { describe, it, expect, vi, beforeEach } from "vitest";
+
+const core = { info: vi.fn(), warning: vi.fn() };
+global.core = core;
+
+const { coercePromptVariable, loadPromptVariables, expandPartials, resolveVariableConditionals, renderLoopsAndVariables, renderPromptTemplate, restorePromptValues, hasPartials, hasPromptVariables } = require("./prompt_template.cjs");
+
+describe("prompt_template.cjs", () => {
+  beforeEach(() => {
+    vi.clearAllMocks();
+  });
+
+  describe("coercePromptVariable", () => {
+    it("should parse JSON arrays", () => {
+      expect(coercePromptVariable('["a","b"]', "array", "labels")).toEqual(["a", "b"]);
+    });
+
+    it("should split non-JSON arrays on commas and newlines", () => {
+      expect(coercePromptVariable("bug, docs\nfeature", "array", "labels")).toEqual(["bug", "docs", "feature"]);
+    });
+
+    it("should parse numbers and booleans", () => {
+      expect(coercePromptVariable(" 42 ", "number", "count")).toBe(42);
+      expect(coercePromptVariable("true", "boolean", "verbose")).toBe(true);
+      expect(coercePromptVariable("0", "boolean", "verbose")).toBe(false);
+    });
+
+    it("should reject values that do not match the type", () => {
+      expect(() => coercePromptVariable("many", "number", "count")).toThrow("Prompt variable 'count' expects a number");
+      expect(() => coercePromptVariable("maybe", "boolean", "verbose")).toThrow("expects a boolean");
+      expect(() => coercePromptVariable("[1]", "object", "repo")).toThrow("expects a JSON object");
+    });
+  });
+
+  describe("loadPromptVariables", () => {
+    it("should return an empty map when no variables are declared", () => {
+      expect(loadPromptVariables({})).toEqual({});
+    });
+
+    it("should use runtime values and fall back to defaults", () => {
+      const env = {
+        GH_AW_PROMPT_VARIABLES: JSON.stringify({
+          labels: { type: "array", default: ["bug"], env: "GH_AW_PROMPT_VAR_LABELS" },
+          focus: { type: "string", default: "security", env: "GH_AW_PROMPT_VAR_FOCUS" },
+          limit: { type: "number" },
+        }),
+        GH_AW_PROMPT_VAR_LABELS: '["docs","ci"]',
+        GH_AW_PROMPT_VAR_FOCUS: "",
+      };
+
+      expect(loadPromptVariables(env)).toEqual({ labels: ["docs", "ci"], focus: "security", limit: 0 });
+    });
+
+    it("should reject invalid declarations", () => {
+      expect(() => loadPromptVariables({ GH_AW_PROMPT_VARIABLES: "{" })).toThrow("GH_AW_PROMPT_VARIABLES is not valid JSON");
+    });
+  });
+
+  describe("expandPartials", () => {
+    it("should remove definitions and expand standalone references", () => {
+      const content = "{{#partial checklist}}\n- Check tests\n- Check docs\n{{/partial}}\n# Review\n{{> checklist}}\nDone\n";
+      expect(expandPartials(content)).toBe("# Review\n- Check tests\n- Check docs\nDone\n");
+    });
+
+    it("should expand inline references without the trailing newline", () => {
+      const content = "{{#partial name}}\nthe reviewer\n{{/partial}}\nYou are {{> name}}.\n";
+      expect(expandPartials(content)).toBe("You are the reviewer.\n");
+    });
+
+    it("should expand partials that reference other partials", () => {
+      const content = "{{#partial inner}}\ninner\n{{/partial}}\n{{#partial outer}}\nouter\n{{> inner}}\n{{/partial}}\n{{> outer}}\n";
+      expect(expandPartials(content)).toBe("outer\ninner\n");
+    });
+
+    it("should reject undefined, duplicate and recursive partials", () => {
+      expect(() => expandPartials("{{> missing}}\n")).toThrow("Partial 'missing' is not defined");
+      expect(() => expandPartials("{{#partial a}}\nx\n{{/partial}}\n{{#partial a}}\ny\n{{/partial}}\n")).toThrow("defined more than once");
+      expect(() => expandPartials("{{#partial a}}\n{{> a}}\n{{/partial}}\n{{> a}}\n")).toThrow("Partial 'a' includes itself: a -> a");
+    });
+  });
+
+  describe("resolveVariableConditionals", () => {
+    it("should resolve conditions to literal true or false", () => {
+      const variables = { verbose: true, labels: [], repo: { owner: "octo" } };
+      const content = "{{#if var.verbose}}A{{/if}}{{#if var.labels}}B{{/if}}{{#if var.repo.owner}}C{{/if}}";
+      expect(resolveVariableConditionals(content, variables)).toBe("{{#if true}}A{{/if}}{{#if false}}B{{/if}}{{#if true}}C{{/if}}");
+    });
+
+    it("should reject undeclared variables", () => {
+      expect(() => resolveVariableConditionals("{{#if var.missing}}x{{/if}}", {})).toThrow("Prompt variable 'missing' is not declared");
+    });
+  });
+
+  describe("renderLoopsAndVariables", () => {
+    const variables = {
+      focus: "security",
+      labels: ["bug", "docs"],
+      files: [{ path: "a.go", lines: 10 }, { path: "b.go" }],
+      repo: { owner: "octo" },
+    };
+
+    it("should substitute variables and nested fields", () => {
+      expect(renderLoopsAndVariables("Focus: {{var.focus}} in {{ var.repo.owner }}; all: {{var.labels}}", variables)).toBe('Focus: security in octo; all: ["bug","docs"]');
+    });
+
+    it("should render block loops one line per item", () => {
+      const content = "Labels:\n{{#each var.labels}}\n- {{@number}}. {{this}} ({{var.focus}})\n{{/each}}\nEnd\n";
+      expect(renderLoopsAndVariables(content, variables)).toBe("Labels:\n- 1. bug (security)\n- 2. docs (security)\nEnd\n");
+    });
+
+    it("should render inline loops and object items", () => {
+      const content = "Files: {{#each var.files}}[{{@index}}:{{this.path}}:{{this.lines}}]{{/each}}";
+      expect(renderLoopsAndVariables(content, variables)).toBe("Files: [0:a.go:10][1:b.go:]");
+    });
+
+    it("should not interpret template syntax inside substituted values", () => {
+      const content = "{{var.payload}} {{#each var.items}}{{this}}{{/each}}";
+      const result = renderLoopsAndVariables(content, { payload: "{{var.secret}}", items: ["{{var.secret}}"], secret: "leaked" });
+      expect(result).toBe("{{var.secret}} {{var.secret}}");
+    });
+
+    it("should reject loops over non-array values", () => {
+      expect(() => renderLoopsAndVariables("{{#each var.focus}}x{{/each}}", variables)).toThrow("{{#each var.focus}} requires an array");
+    });
+  });
+
+  describe("renderPromptTemplate", () => {
+    it("should render partials and variables behind placeholders", () => {
+      const content = "{{#partial intro}}\nFocus on {{var.focus}}.\n{{/partial}}\n{{> intro}}\n{{#if var.focus}}\nIssue: ${GH_AW_EXPR_BODY}\n{{/if}}\n";
+      const rendered = renderPromptTemplate(content, { focus: "security" });
+
+      expect(rendered.values).toEqual(["security"]);
+      expect(rendered.content).not.toContain("security");
+      expect(rendered.content).toBe(`Focus on ${rendered.placeholder}0__.\n{{#if true}}\nIssue: \${GH_AW_EXPR_BODY}\n{{/if}}\n`);
+      expect(restorePromptValues(rendered.content, rendered.values, rendered.placeholder)).toContain("Focus on security.");
+    });
+
+    it("should not render template syntax in values interpolated afterwards", () => {
+      const rendered = renderPromptTemplate("Focus: {{var.focus}}\nIssue: ${GH_AW_EXPR_BODY}\n", { focus: "bugs" });
+      // Simulates the expression interpolation step with an untrusted issue body
+      const interpolated = rendered.content.replace("${GH_AW_EXPR_BODY}", "{{> secrets}} {{var.nope}} {{#each var.focus}}x{{/each}}");
+
+      expect(restorePromptValues(interpolated, rendered.values, rendered.placeholder)).toBe("Focus: bugs\nIssue: {{> secrets}} {{var.nope}} {{#each var.focus}}x{{/each}}\n");
+    });
+
+    it("should not expose variable values to later rendering steps", () => {
+      const rendered = renderPromptTemplate("{{var.title}}", { title: "{{#if false}}hidden{{/if}} ${GH_AW_EXPR_SECRET}" });
+
+      expect(rendered.content).not.toContain("{{#if");
+      expect(rendered.content).not.toContain("GH_AW_EXPR_SECRET");
+      expect(restorePromptValues(rendered.content, rendered.values, rendered.placeholder)).toBe("{{#if false}}hidden{{/if}} ${GH_AW_EXPR_SECRET}");
+    });
+
+    it("should use a different placeholder for each render", () => {
+      expect(renderPromptTemplate("x", {}).placeholder).not.toBe(renderPromptTemplate("x", {}).placeholder);
+    });
+  });
+
+  describe("detection helpers", () => {
+    it("should detect partials and prompt variables", () => {
+      expect(hasPartials("{{> checklist}}")).toBe(true);
+      expect(hasPartials("{{#if true}}x{{/if}}")).toBe(false);
+      expect(hasPromptVariables("{{#each var.labels}}")).toBe(true);
+      expect(hasPromptVariables("${{ vars.FOO }}")).toBe(false);
+    });
+  });
+});
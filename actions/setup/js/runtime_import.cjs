@@ -621,6 +621,12 @@ function wrapExpressionsInTemplateConditionals(content) {
       return match;
     }
 
+    // If it's a prompt variable reference (starts with var.), return as-is
+    // These are resolved by the prompt template renderer
+    if (trimmed.startsWith("var.")) {
+      return match;
+    }
+
     // Only wrap expressions that look like GitHub Actions expressions
     // GitHub Actions expressions typically contain dots (e.g., github.actor, github.event.issue.number)
     // or specific keywords (true, false, null)
@@ -15,6 +15,7 @@ const { ERR_SYSTEM, ERR_VALIDATION } = require("./error_codes.cjs");
  * @property {string} name - Tool name
  * @property {string} description - Tool description
  * @property {Object} inputSchema - JSON Schema for tool inputs
+ * @property {Object} [outputSchema] - Optional JSON Schema for tool results
  * @property {string} [handler] - Path to handler file (.cjs, .sh, or .py)
  * @property {number} [timeout] - Timeout in seconds for tool execution (default: 60)
  */
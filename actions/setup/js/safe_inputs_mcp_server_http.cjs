@@ -25,7 +25,7 @@ require("./shim.cjs");
 const http = require("http");
 const { randomUUID } = require("crypto");
 const { MCPServer, MCPHTTPTransport } = require("./mcp_http_transport.cjs");
-const { validateRequiredFields } = require("./safe_inputs_validation.cjs");
+const { validateRequiredFields, validateAgainstSchema, validateToolOutput } = require("./safe_inputs_validation.cjs");
 const { generateEnhancedErrorMessage } = require("./mcp_enhanced_errors.cjs");
 const { createLogger } = require("./mcp_logger.cjs");
 const { bootstrapSafeInputsServer, cleanupConfigFile } = require("./safe_inputs_bootstrap.cjs");
@@ -94,10 +94,22 @@ function createMCPServer(configPath, options = {}) {
         throw new Error(generateEnhancedErrorMessage(missing, tool.name, tool.inputSchema));
       }
 
+      // Validate arguments against the declared input schema (nested types, enums, patterns, ranges)
+      const inputErrors = validateAgainstSchema(args || {}, tool.inputSchema);
+      if (inputErrors.length) {
+        throw new Error(`${ERR_VALIDATION}: Invalid arguments for tool '${tool.name}': ${inputErrors.join("; ")}`);
+      }
+
       // Call the handler
       const result = await Promise.resolve(tool.handler(args));
       logger.debug(`Handler returned for tool: ${tool.name}`);
 
+      // Validate the result against the declared output schema
+      const outputErrors = validateToolOutput(result, tool.outputSchema);
+      if (outputErrors.length) {
+        throw new Error(`${ERR_VALIDATION}: Output of tool '${tool.name}' does not match its output schema: ${outputErrors.join("; ")}`);
+      }
+
       // Normalize result to MCP format
       const content = result && result.content ? result.content : [];
       return { content, isError: false };
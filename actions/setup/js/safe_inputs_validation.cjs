@@ -27,6 +27,149 @@ function validateRequiredFields(args, inputSchema) {
   return missing;
 }
 
+/**
+ * Get the JSON Schema type name of a value
+ * @param {any} value - The value to inspect
+ * @returns {string} JSON Schema type name
+ */
+function jsonTypeOf(value) {
+  if (value === null) {
+    return "null";
+  }
+  if (Array.isArray(value)) {
+    return "array";
+  }
+  if (typeof value === "number") {
+    return Number.isInteger(value) ? "integer" : "number";
+  }
+  return typeof value;
+}
+
+/**
+ * Validate a value against the subset of JSON Schema used by safe-inputs tools
+ * (type, enum, const, pattern, minimum/maximum, minLength/maxLength,
+ * minItems/maxItems, items, properties, required, additionalProperties).
+ * The compiler rejects schemas using other keywords (supportedSchemaKeywords in
+ * pkg/workflow/safe_inputs_validation.go), so the two lists must stay in sync.
+ * @param {any} value - The value to validate
+ * @param {Object} schema - The JSON Schema to validate against
+ * @param {string} [path] - JSON pointer of the value, used in error messages
+ * @returns {string[]} Array of validation errors (empty if the value is valid)
+ */
+function validateAgainstSchema(value, schema, path = "") {
+  /** @type {string[]} */
+  const errors = [];
+  if (!schema || typeof schema !== "object") {
+    return errors;
+  }
+
+  const location = path || "/";
+  const actualType = jsonTypeOf(value);
+
+  if (schema.type) {
+    const allowedTypes = Array.isArray(schema.type) ? schema.type : [schema.type];
+    const matches = allowedTypes.some(t => t === actualType || (t === "number" && actualType === "integer"));
+    if (!matches) {
+      errors.push(`${location}: expected ${allowedTypes.join(" or ")}, got ${actualType}`);
+      return errors;
+    }
+  }
+
+  if (Array.isArray(schema.enum) && !schema.enum.some(v => JSON.stringify(v) === JSON.stringify(value))) {
+    errors.push(`${location}: value must be one of ${JSON.stringify(schema.enum)}`);
+  }
+  if (schema.const !== undefined && JSON.stringify(schema.const) !== JSON.stringify(value)) {
+    errors.push(`${location}: value must be ${JSON.stringify(schema.const)}`);
+  }
+
+  if (typeof value === "string") {
+    if (schema.pattern && !new RegExp(schema.pattern, "u").test(value)) {
+      errors.push(`${location}: does not match pattern ${schema.pattern}`);
+    }
+    if (typeof schema.minLength === "number" && value.length < schema.minLength) {
+      errors.push(`${location}: length must be >= ${schema.minLength}`);
+    }
+    if (typeof schema.maxLength === "number" && value.length > schema.maxLength) {
+      errors.push(`${location}: length must be <= ${schema.maxLength}`);
+    }
+  }
+
+  if (typeof value === "number") {
+    if (typeof schema.minimum === "number" && value < schema.minimum) {
+      errors.push(`${location}: must be >= ${schema.minimum}`);
+    }
+    if (typeof schema.maximum === "number" && value > schema.maximum) {
+      errors.push(`${location}: must be <= ${schema.maximum}`);
+    }
+  }
+
+  if (Array.isArray(value)) {
+    if (typeof schema.minItems === "number" && value.length < schema.minItems) {
+      errors.push(`${location}: must have at least ${schema.minItems} items`);
+    }
+    if (typeof schema.maxItems === "number" && value.length > schema.maxItems) {
+      errors.push(`${location}: must have at most ${schema.maxItems} items`);
+    }
+    if (schema.items && typeof schema.items === "object") {
+      value.forEach((item, index) => {
+        errors.push(...validateAgainstSchema(item, schema.items, `${path}/${index}`));
+      });
+    }
+  }
+
+  if (actualType === "object") {
+    const properties = schema.properties && typeof schema.properties === "object" ? schema.properties : {};
+    const required = Array.isArray(schema.required) ? schema.required : [];
+    for (const name of required) {
+      if (value[name] === undefined) {
+        errors.push(`${location}: missing required property '${name}'`);
+      }
+    }
+    for (const [name, propValue] of Object.entries(value)) {
+      if (properties[name]) {
+        errors.push(...validateAgainstSchema(propValue, properties[name], `${path}/${name}`));
+      } else if (schema.additionalProperties === false) {
+        errors.push(`${location}: additional property '${name}' is not allowed`);
+      } else if (schema.additionalProperties && typeof schema.additionalProperties === "object") {
+        errors.push(...validateAgainstSchema(propValue, schema.additionalProperties, `${path}/${name}`));
+      }
+    }
+  }
+
+  return errors;
+}
+
+/**
+ * Validate a tool handler result against the tool's declared output schema.
+ * Handlers return MCP content whose first text item holds the JSON-encoded result;
+ * text that is not valid JSON is validated as a plain string.
+ * @param {Object} handlerResult - The result returned by the tool handler
+ * @param {Object} outputSchema - The JSON Schema declared in the tool's output field
+ * @returns {string[]} Array of validation errors (empty if the result is valid)
+ */
+function validateToolOutput(handlerResult, outputSchema) {
+  if (!outputSchema) {
+    return [];
+  }
+
+  const content = handlerResult && Array.isArray(handlerResult.content) ? handlerResult.content : [];
+  const textItem = content.find(item => item && item.type === "text");
+  if (!textItem) {
+    return ["/: tool returned no text content to validate"];
+  }
+
+  let value;
+  try {
+    value = JSON.parse(textItem.text);
+  } catch {
+    value = textItem.text;
+  }
+
+  return validateAgainstSchema(value, outputSchema);
+}
+
 module.exports = {
   validateRequiredFields,
+  validateAgainstSchema,
+  validateToolOutput,
 };
@@ -135,4 +135,96 @@ describe("safe_inputs_validation.cjs", () => {
       expect(missing).toEqual([]);
     });
   });
+
+  describe("validateAgainstSchema", () => {
+    it("should accept values matching nested object and array schemas", async () => {
+      const { validateAgainstSchema } = await import("./safe_inputs_validation.cjs");
+
+      const schema = {
+        type: "object",
+        properties: {
+          labels: { type: "array", items: { type: "string", pattern: "^[a-z-]+$" }, maxItems: 3 },
+          options: {
+            type: "object",
+            properties: { limit: { type: "integer", minimum: 1, maximum: 100 } },
+            required: ["limit"],
+          },
+        },
+      };
+
+      const errors = validateAgainstSchema({ labels: ["bug", "needs-triage"], options: { limit: 10 } }, schema);
+
+      expect(errors).toEqual([]);
+    });
+
+    it("should report violations with JSON pointer locations", async () => {
+      const { validateAgainstSchema } = await import("./safe_inputs_validation.cjs");
+
+      const schema = {
+        type: "object",
+        properties: {
+          labels: { type: "array", items: { type: "string", pattern: "^[a-z-]+$" } },
+          options: {
+            type: "object",
+            properties: { limit: { type: "integer", maximum: 100 } },
+            required: ["limit", "order"],
+          },
+          state: { type: "string", enum: ["open", "closed"] },
+        },
+      };
+
+      const errors = validateAgainstSchema({ labels: ["Bug"], options: { limit: 500 }, state: "merged" }, schema);
+
+      expect(errors).toContain("/labels/0: does not match pattern ^[a-z-]+$");
+      expect(errors).toContain("/options: missing required property 'order'");
+      expect(errors).toContain("/options/limit: must be <= 100");
+      expect(errors).toContain('/state: value must be one of ["open","closed"]');
+    });
+
+    it("should report type mismatches", async () => {
+      const { validateAgainstSchema } = await import("./safe_inputs_validation.cjs");
+
+      expect(validateAgainstSchema(1.5, { type: "integer" })).toEqual(["/: expected integer, got number"]);
+      expect(validateAgainstSchema(3, { type: "number" })).toEqual([]);
+      expect(validateAgainstSchema("x", { type: "object" })).toEqual(["/: expected object, got string"]);
+    });
+
+    it("should reject additional properties when disallowed", async () => {
+      const { validateAgainstSchema } = await import("./safe_inputs_validation.cjs");
+
+      const errors = validateAgainstSchema({ a: 1, b: 2 }, { type: "object", properties: { a: { type: "integer" } }, additionalProperties: false });
+
+      expect(errors).toEqual(["/: additional property 'b' is not allowed"]);
+    });
+  });
+
+  describe("validateToolOutput", () => {
+    it("should return empty array when no output schema is declared", async () => {
+      const { validateToolOutput } = await import("./safe_inputs_validation.cjs");
+
+      expect(validateToolOutput({ content: [{ type: "text", text: "anything" }] }, undefined)).toEqual([]);
+    });
+
+    it("should validate the JSON-encoded text content", async () => {
+      const { validateToolOutput } = await import("./safe_inputs_validation.cjs");
+
+      const schema = { type: "object", properties: { count: { type: "integer" } }, required: ["count"] };
+
+      expect(validateToolOutput({ content: [{ type: "text", text: JSON.stringify({ count: 3 }) }] }, schema)).toEqual([]);
+      expect(validateToolOutput({ content: [{ type: "text", text: JSON.stringify({ total: 3 }) }] }, schema)).toEqual(["/: missing required property 'count'"]);
+    });
+
+    it("should validate non-JSON text as a string", async () => {
+      const { validateToolOutput } = await import("./safe_inputs_validation.cjs");
+
+      expect(validateToolOutput({ content: [{ type: "text", text: "plain" }] }, { type: "string" })).toEqual([]);
+      expect(validateToolOutput({ content: [{ type: "text", text: "plain" }] }, { type: "object" })).toEqual(["/: expected object, got string"]);
+    });
+
+    it("should report missing text content", async () => {
+      const { validateToolOutput } = await import("./safe_inputs_validation.cjs");
+
+      expect(validateToolOutput({ content: [] }, { type: "object" })).toEqual(["/: tool returned no text content to validate"]);
+    });
+  });
 });
@@ -0,0 +1,160 @@
+// @ts-check
+
+/**
+ * Safe Output Argument Constraints
+ *
+ * This module enforces the argument constraints of safe output tools
+ * (safe-outputs.constraints in the workflow frontmatter). The compiler writes the
+ * constraints of each tool to its entry in the safe outputs config.json:
+ *
+ *   { "create_issue": { "max": 1, "constraints": { "title": { "pattern": "\\[bot\\] .+" } } } }
+ *
+ * They are checked twice: by the safe outputs MCP server when the agent calls a tool
+ * (the call is rejected with JSON-RPC error -32004) and by the ingestion step
+ * (collect_ndjson_output.cjs), which drops items that were written around the server.
+ *
+ * Rules:
+ * - pattern: regular expression the whole value must match (implicitly anchored)
+ * - domains: allowed URL hosts; example.com also allows its subdomains,
+ *   *.example.com only allows subdomains
+ * - enum: exhaustive list of allowed values
+ *
+ * A constrained argument that is missing is a violation. Numbers and booleans are
+ * matched as their JSON text, arrays element by element, and objects never match.
+ */
+
+/** @type {number} JSON-RPC error code of a tool call rejected by a constraint */
+const CONSTRAINT_VIOLATION_CODE = -32004;
+
+/**
+ * @typedef {Object} ArgumentConstraint
+ * @property {string} [pattern] - Regular expression the whole value must match
+ * @property {string[]} [domains] - Allowed URL hosts
+ * @property {string[]} [enum] - Allowed values
+ */
+
+/**
+ * @typedef {Object} ConstraintViolation
+ * @property {string} argument - Name of the violated argument
+ * @property {string} detail - Description of the violated rule
+ */
+
+/**
+ * Checks whether a URL host is allowed by a domain rule
+ * @param {string} host - Lowercase URL host
+ * @param {string} domain - Allowed domain (example.com or *.example.com)
+ * @returns {boolean}
+ */
+function hostMatchesDomain(host, domain) {
+  const normalized = domain.toLowerCase();
+  if (normalized.startsWith("*.")) {
+    return host.endsWith(normalized.slice(1));
+  }
+  return host === normalized || host.endsWith(`.${normalized}`);
+}
+
+/**
+ * Checks a single scalar value against an argument rule
+ * @param {string} value - Value as text
+ * @param {ArgumentConstraint} rule - Argument rule
+ * @returns {string|null} Description of the violated rule, or null when the value matches
+ */
+function checkScalarValue(value, rule) {
+  if (rule.pattern && !new RegExp(`^(?:${rule.pattern})$`).test(value)) {
+    return `value ${JSON.stringify(value)} does not match pattern ${rule.pattern}`;
+  }
+  if (Array.isArray(rule.domains) && rule.domains.length > 0) {
+    let host;
+    try {
+      host = new URL(value).hostname.toLowerCase();
+    } catch {
+      return `value ${JSON.stringify(value)} is not a valid URL`;
+    }
+    if (!rule.domains.some(domain => hostMatchesDomain(host, domain))) {
+      return `host ${host} is not in the allowed domains ${rule.domains.join(", ")}`;
+    }
+  }
+  if (Array.isArray(rule.enum) && rule.enum.length > 0 && !rule.enum.includes(value)) {
+    return `value ${JSON.stringify(value)} is not one of ${rule.enum.join(", ")}`;
+  }
+  return null;
+}
+
+/**
+ * Checks an argument value against its rule
+ * @param {any} value - Argument value
+ * @param {ArgumentConstraint} rule - Argument rule
+ * @returns {string|null} Description of the violated rule, or null when the value matches
+ */
+function checkArgumentValue(value, rule) {
+  if (value === undefined || value === null) {
+    return "argument is required by a constraint";
+  }
+  if (Array.isArray(value)) {
+    for (const element of value) {
+      const detail = checkArgumentValue(element, rule);
+      if (detail) {
+        return detail;
+      }
+    }
+    return null;
+  }
+  if (typeof value === "object") {
+    return "object values cannot be matched against a constraint";
+  }
+  return checkScalarValue(typeof value === "string" ? value : JSON.stringify(value), rule);
+}
+
+/**
+ * Checks the arguments of a tool call against the constraints of the tool
+ * @param {Object} args - Tool call arguments (or a collected safe output item)
+ * @param {Object<string, ArgumentConstraint>|undefined} constraints - Constraints of the tool
+ * @returns {ConstraintViolation|null} The first violation, or null when all constraints pass
+ */
+function checkToolConstraints(args, constraints) {
+  if (!constraints || typeof constraints !== "object") {
+    return null;
+  }
+  for (const argument of Object.keys(constraints).sort()) {
+    const detail = checkArgumentValue(args?.[argument], constraints[argument]);
+    if (detail) {
+      return { argument, detail };
+    }
+  }
+  return null;
+}
+
+/**
+ * Wraps the handlers of constrained tools so that calls violating a constraint are
+ * rejected with JSON-RPC error -32004 before the safe output is recorded
+ * @param {Array<any>} tools - Tool definitions with handlers attached
+ * @param {Object} config - Safe outputs configuration
+ * @param {Function} defaultHandler - Factory for the handler of tools without one
+ * @returns {Array<any>} The tools
+ */
+function enforceToolConstraints(tools, config, defaultHandler) {
+  for (const tool of tools) {
+    const constraints = config?.[tool.name]?.constraints;
+    if (!constraints || tool._workflow_name) {
+      continue;
+    }
+    const handler = tool.handler || defaultHandler(tool.name);
+    tool.handler = args => {
+      const violation = checkToolConstraints(args, constraints);
+      if (violation) {
+        throw {
+          code: CONSTRAINT_VIOLATION_CODE,
+          message: `Tool call rejected by constraint: ${tool.name}(${violation.argument}): ${violation.detail}`,
+        };
+      }
+      return handler(args);
+    };
+  }
+  return tools;
+}
+
+module.exports = {
+  CONSTRAINT_VIOLATION_CODE,
+  checkToolConstraints,
+  enforceToolConstraints,
+};
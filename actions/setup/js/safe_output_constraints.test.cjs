@@ -0,0 +1,98 @@
+// @ts-check
+import { describe, it, expect, beforeEach } from "vitest";
+
+describe("safe_output_constraints", () => {
+  let CONSTRAINT_VIOLATION_CODE, checkToolConstraints, enforceToolConstraints;
+
+  beforeEach(async () => {
+    const module = await import("./safe_output_constraints.cjs");
+    CONSTRAINT_VIOLATION_CODE = module.CONSTRAINT_VIOLATION_CODE;
+    checkToolConstraints = module.checkToolConstraints;
+    enforceToolConstraints = module.enforceToolConstraints;
+  });
+
+  describe("checkToolConstraints", () => {
+    it("should pass when there are no constraints", () => {
+      expect(checkToolConstraints({ title: "anything" }, undefined)).toBeNull();
+    });
+
+    it("should anchor patterns to the whole value", () => {
+      const constraints = { title: { pattern: "\\[bot\\] .+" } };
+      expect(checkToolConstraints({ title: "[bot] Weekly report" }, constraints)).toBeNull();
+      expect(checkToolConstraints({ title: "Re: [bot] Weekly report" }, constraints)).toMatchObject({ argument: "title" });
+    });
+
+    it("should anchor alternations as a whole", () => {
+      const constraints = { title: { pattern: "foo|bar" } };
+      expect(checkToolConstraints({ title: "bar" }, constraints)).toBeNull();
+      expect(checkToolConstraints({ title: "foobar" }, constraints)).not.toBeNull();
+      expect(checkToolConstraints({ title: "barista" }, constraints)).not.toBeNull();
+    });
+
+    it("should reject missing constrained arguments", () => {
+      const violation = checkToolConstraints({}, { title: { pattern: ".*" } });
+      expect(violation).toEqual({ argument: "title", detail: "argument is required by a constraint" });
+    });
+
+    it("should check every element of array values", () => {
+      const constraints = { labels: { enum: ["bug", "enhancement"] } };
+      expect(checkToolConstraints({ labels: ["bug", "enhancement"] }, constraints)).toBeNull();
+      expect(checkToolConstraints({ labels: ["bug", "urgent"] }, constraints)?.detail).toContain('"urgent"');
+    });
+
+    it("should match numbers as text", () => {
+      const constraints = { item_number: { pattern: "[0-9]{1,3}" } };
+      expect(checkToolConstraints({ item_number: 42 }, constraints)).toBeNull();
+      expect(checkToolConstraints({ item_number: 4242 }, constraints)).not.toBeNull();
+    });
+
+    it("should reject object values", () => {
+      expect(checkToolConstraints({ title: { nested: "x" } }, { title: { pattern: ".*" } })).not.toBeNull();
+    });
+
+    it("should allow a domain and its subdomains", () => {
+      const constraints = { url: { domains: ["example.com"] } };
+      expect(checkToolConstraints({ url: "https://example.com/a" }, constraints)).toBeNull();
+      expect(checkToolConstraints({ url: "https://docs.example.com/a" }, constraints)).toBeNull();
+      expect(checkToolConstraints({ url: "https://badexample.com/a" }, constraints)).not.toBeNull();
+      expect(checkToolConstraints({ url: "https://example.com.evil.io/a" }, constraints)).not.toBeNull();
+    });
+
+    it("should only allow subdomains for wildcard domains", () => {
+      const constraints = { url: { domains: ["*.example.com"] } };
+      expect(checkToolConstraints({ url: "https://docs.example.com" }, constraints)).toBeNull();
+      expect(checkToolConstraints({ url: "https://example.com" }, constraints)).not.toBeNull();
+    });
+
+    it("should reject values that are not URLs for domain rules", () => {
+      expect(checkToolConstraints({ url: "example.com" }, { url: { domains: ["example.com"] } })?.detail).toContain("not a valid URL");
+    });
+  });
+
+  describe("enforceToolConstraints", () => {
+    it("should reject violating calls with the constraint violation code", () => {
+      const calls = [];
+      const tools = [{ name: "create_issue" }, { name: "noop" }];
+      const config = { create_issue: { constraints: { title: { pattern: "\\[bot\\] .+" } } }, noop: {} };
+      const defaultHandler = type => args => calls.push({ type, ...args });
+
+      enforceToolConstraints(tools, config, defaultHandler);
+
+      expect(() => tools[0].handler({ title: "Hello" })).toThrow(expect.objectContaining({ code: CONSTRAINT_VIOLATION_CODE }));
+      expect(calls).toHaveLength(0);
+
+      tools[0].handler({ title: "[bot] Hello" });
+      expect(calls).toEqual([{ type: "create_issue", title: "[bot] Hello" }]);
+      expect(tools[1].handler).toBeUndefined();
+    });
+
+    it("should wrap existing handlers", () => {
+      const handler = args => ({ content: [{ type: "text", text: args.title }] });
+      const tools = [{ name: "create_issue", handler }];
+      enforceToolConstraints(tools, { create_issue: { constraints: { title: { enum: ["ok"] } } } }, () => () => {});
+
+      expect(tools[0].handler({ title: "ok" })).toEqual({ content: [{ type: "text", text: "ok" }] });
+      expect(() => tools[0].handler({ title: "nope" })).toThrow(expect.objectContaining({ message: expect.stringContaining("create_issue(title)") }));
+    });
+  });
+});
@@ -425,6 +425,16 @@ function validateField(value, fieldName, validation, itemType, lineNum, options)
     return { isValid: true, normalizedValue: value };
   }
 
+  if (validation.type === "object") {
+    if (typeof value !== "object" || Array.isArray(value)) {
+      return {
+        isValid: false,
+        error: `Line ${lineNum}: ${itemType} '${fieldName}' must be an object`,
+      };
+    }
+    return { isValid: true, normalizedValue: value };
+  }
+
   // No specific type validation, return as-is
   return { isValid: true, normalizedValue: value };
 }
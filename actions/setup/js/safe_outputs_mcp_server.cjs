@@ -16,6 +16,7 @@ const { createServer, registerTool, normalizeTool, start } = require("./mcp_serv
 const { createAppendFunction } = require("./safe_outputs_append.cjs");
 const { createHandlers } = require("./safe_outputs_handlers.cjs");
 const { attachHandlers, registerPredefinedTools, registerDynamicTools } = require("./safe_outputs_tools_loader.cjs");
+const { enforceToolConstraints } = require("./safe_output_constraints.cjs");
 const { bootstrapSafeOutputsServer, cleanupConfigFile } = require("./safe_outputs_bootstrap.cjs");
 const { getErrorMessage } = require("./error_helpers.cjs");
 const { ERR_VALIDATION } = require("./error_codes.cjs");
@@ -47,6 +48,9 @@ function startSafeOutputsServer(options = {}) {
   // Attach handlers to tools
   const toolsWithHandlers = attachHandlers(ALL_TOOLS, handlers);
 
+  // Reject calls that violate the argument constraints of a tool (safe-outputs.constraints)
+  enforceToolConstraints(toolsWithHandlers, safeOutputsConfig, defaultHandler);
+
   server.debug(`  output file: ${outputFile}`);
   server.debug(`  config: ${JSON.stringify(safeOutputsConfig)}`);
 
@@ -40,6 +40,8 @@ const { createHandlers } = require("./safe_outputs_handlers.cjs");
 moduleLogger.debug("Loaded safe_outputs_handlers.cjs");
 const { attachHandlers, registerPredefinedTools, registerDynamicTools } = require("./safe_outputs_tools_loader.cjs");
 moduleLogger.debug("Loaded safe_outputs_tools_loader.cjs");
+const { enforceToolConstraints } = require("./safe_output_constraints.cjs");
+moduleLogger.debug("Loaded safe_output_constraints.cjs");
 const { getErrorMessage } = require("./error_helpers.cjs");
 moduleLogger.debug("All modules loaded successfully");
 
@@ -90,6 +92,9 @@ function createMCPServer(options = {}) {
   // Attach handlers to tools
   const toolsWithHandlers = attachHandlers(ALL_TOOLS, handlers);
 
+  // Reject calls that violate the argument constraints of a tool (safe-outputs.constraints)
+  enforceToolConstraints(toolsWithHandlers, safeOutputsConfig, defaultHandler);
+
   // Register predefined tools that are enabled in configuration
   logger.debug(`Registering predefined tools...`);
   let registeredCount = 0;
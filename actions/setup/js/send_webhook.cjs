@@ -0,0 +1,254 @@
+// @ts-check
+/// <reference types="@actions/github-script" />
+
+/**
+ * @typedef {import('./types/handler-factory').HandlerFactoryFunction} HandlerFactoryFunction
+ */
+
+const crypto = require("crypto");
+const { getErrorMessage } = require("./error_helpers.cjs");
+const { logStagedPreviewInfo } = require("./staged_preview.cjs");
+const { withRetry } = require("./error_recovery.cjs");
+const { validateAgainstSchema } = require("./safe_inputs_validation.cjs");
+
+/**
+ * Type constant for handler identification
+ */
+const HANDLER_TYPE = "send_webhook";
+
+/**
+ * Timeout for a single webhook request in milliseconds
+ */
+const REQUEST_TIMEOUT_MS = 30000;
+
+/**
+ * Check whether a host matches one of the allowed domain patterns.
+ * Patterns match the exact host or, with a '*.' prefix, any subdomain.
+ * Patterns restricted to http:// never match since webhooks are only sent over HTTPS.
+ * @param {string} host - Host name of the webhook URL
+ * @param {string[]} allowedDomains - Allowed domain patterns from safe-outputs.allowed-domains
+ * @returns {boolean} True if the host is allowed
+ */
+function isHostAllowed(host, allowedDomains) {
+  const normalizedHost = host.toLowerCase();
+  return allowedDomains.some(domain => {
+    if (domain.startsWith("http://")) {
+      return false;
+    }
+    const pattern = domain.replace(/^https:\/\//, "").toLowerCase();
+    if (pattern.startsWith("*.")) {
+      const baseDomain = pattern.substring(2);
+      return normalizedHost === baseDomain || normalizedHost.endsWith("." + baseDomain);
+    }
+    return normalizedHost === pattern;
+  });
+}
+
+/**
+ * Resolve and validate the URL of an endpoint from its environment variable.
+ * @param {string} urlEnv - Name of the environment variable holding the URL
+ * @param {string[]} allowedDomains - Allowed domain patterns
+ * @returns {{url?: URL, error?: string}} The parsed URL or an error
+ */
+function resolveEndpointURL(urlEnv, allowedDomains) {
+  const rawURL = process.env[urlEnv];
+  if (!rawURL) {
+    return { error: `Endpoint URL is not set (${urlEnv} is empty)` };
+  }
+
+  let url;
+  try {
+    url = new URL(rawURL);
+  } catch {
+    return { error: "Endpoint URL is not a valid URL" };
+  }
+
+  if (url.protocol !== "https:") {
+    return { error: "Endpoint URL must use https" };
+  }
+  if (!isHostAllowed(url.hostname, allowedDomains)) {
+    return { error: `Endpoint host ${url.hostname} is not listed in safe-outputs.allowed-domains` };
+  }
+  return { url };
+}
+
+/**
+ * Compute the HMAC-SHA256 signature header value for a request body.
+ * @param {string} secret - Signing secret
+ * @param {string} body - Request body
+ * @returns {string} Signature in the form sha256=<hex>
+ */
+function signPayload(secret, body) {
+  return "sha256=" + crypto.createHmac("sha256", secret).update(body).digest("hex");
+}
+
+/**
+ * POST a body to a webhook URL. Network errors, 429 and 5xx responses are
+ * retryable; other non-success responses fail immediately. Redirects are not
+ * followed, since the target would bypass the safe-outputs.allowed-domains check
+ * and receive the signed payload.
+ * @param {URL} url - Webhook URL
+ * @param {string} body - JSON request body
+ * @param {Record<string, string>} headers - Request headers
+ * @returns {Promise<number>} HTTP status of the successful response
+ */
+async function postWebhook(url, body, headers) {
+  const response = await fetch(url, {
+    method: "POST",
+    headers,
+    body,
+    redirect: "manual",
+    signal: AbortSignal.timeout(REQUEST_TIMEOUT_MS),
+  });
+
+  if (response.type === "opaqueredirect" || (response.status >= 300 && response.status < 400)) {
+    const error = new Error(`Webhook returned a redirect (HTTP ${response.status}); redirects are not followed`);
+    /** @type {any} */ (error).retryable = false;
+    throw error;
+  }
+
+  if (response.ok) {
+    return response.status;
+  }
+
+  const error = new Error(`Webhook returned HTTP ${response.status}`);
+  /** @type {any} */ (error).retryable = response.status === 429 || response.status >= 500;
+  throw error;
+}
+
+/**
+ * Main handler factory for send_webhook
+ * Returns a message handler function that processes individual send_webhook messages
+ * @type {HandlerFactoryFunction}
+ */
+async function main(config = {}) {
+  // Extract configuration
+  const endpoints = config.endpoints || {};
+  const allowedDomains = config.allowed_domains || [];
+  const schema = config.schema;
+  const maxCount = config.max || 1;
+  const retries = typeof config.retries === "number" ? config.retries : 3;
+
+  // Check if we're in staged mode
+  const isStaged = process.env.GH_AW_SAFE_OUTPUTS_STAGED === "true";
+
+  core.info(`Send webhook configuration: max=${maxCount}, retries=${retries}`);
+  core.info(`Endpoints: ${Object.keys(endpoints).join(", ")}`);
+
+  // Track how many items we've processed for max limit
+  let processedCount = 0;
+
+  /**
+   * Message handler function that processes a single send_webhook message
+   * @param {Object} message - The send_webhook message to process
+   * @param {Object} resolvedTemporaryIds - Map of temporary IDs to {repo, number}
+   * @returns {Promise<Object>} Result with success/error status
+   */
+  return async function handleSendWebhook(message, resolvedTemporaryIds) {
+    // Check if we've hit the max limit
+    if (processedCount >= maxCount) {
+      core.warning(`Skipping send_webhook: max count of ${maxCount} reached`);
+      return {
+        success: false,
+        error: `Max count of ${maxCount} reached`,
+      };
+    }
+
+    const item = message;
+    const endpointName = item.endpoint;
+    const endpoint = typeof endpointName === "string" && Object.prototype.hasOwnProperty.call(endpoints, endpointName) ? endpoints[endpointName] : undefined;
+    if (!endpoint) {
+      core.warning(`Endpoint "${endpointName}" is not configured. Available endpoints: ${Object.keys(endpoints).join(", ")}`);
+      return {
+        success: false,
+        error: `Endpoint "${endpointName}" is not configured`,
+      };
+    }
+
+    const payload = item.payload;
+    if (!payload || typeof payload !== "object" || Array.isArray(payload)) {
+      core.warning("payload is required and must be an object");
+      return {
+        success: false,
+        error: "payload is required and must be an object",
+      };
+    }
+
+    if (schema) {
+      const schemaErrors = validateAgainstSchema(payload, schema, "payload");
+      if (schemaErrors.length > 0) {
+        core.warning(`Payload does not match the configured schema: ${schemaErrors.join("; ")}`);
+        return {
+          success: false,
+          error: `Payload does not match the configured schema: ${schemaErrors.join("; ")}`,
+        };
+      }
+    }
+
+    // Only valid messages count toward the max limit
+    processedCount++;
+
+    const body = JSON.stringify(payload);
+
+    // If in staged mode, preview without sending. The endpoint URL is never logged.
+    if (isStaged) {
+      logStagedPreviewInfo(`Would send webhook to endpoint "${endpointName}" (${body.length} bytes)`);
+      core.info(body);
+      return {
+        success: true,
+        staged: true,
+        previewInfo: {
+          endpoint: endpointName,
+          payload,
+        },
+      };
+    }
+
+    const { url, error: urlError } = resolveEndpointURL(endpoint.url_env, allowedDomains);
+    if (!url) {
+      core.error(`Cannot send webhook to endpoint "${endpointName}": ${urlError}`);
+      return {
+        success: false,
+        error: urlError,
+      };
+    }
+
+    /** @type {Record<string, string>} */
+    const headers = {
+      "Content-Type": "application/json",
+      "User-Agent": "gh-aw-send-webhook",
+    };
+    if (endpoint.secret_env) {
+      const secret = process.env[endpoint.secret_env];
+      if (!secret) {
+        core.error(`Cannot send webhook to endpoint "${endpointName}": signing secret is not set (${endpoint.secret_env} is empty)`);
+        return {
+          success: false,
+          error: `Signing secret is not set (${endpoint.secret_env} is empty)`,
+        };
+      }
+      headers["X-Hub-Signature-256"] = signPayload(secret, body);
+    }
+
+    try {
+      core.info(`Sending webhook to endpoint "${endpointName}" (${body.length} bytes)`);
+      const status = await withRetry(() => postWebhook(url, body, headers), { maxRetries: retries, shouldRetry: error => error?.retryable !== false }, `send_webhook to ${endpointName}`);
+      core.info(`Successfully sent webhook to endpoint "${endpointName}" (HTTP ${status})`);
+      return {
+        success: true,
+        endpoint: endpointName,
+        status,
+      };
+    } catch (error) {
+      // Report the underlying HTTP or network error rather than the retry wrapper
+      const errorMessage = getErrorMessage(/** @type {any} */ (error).originalError || error);
+      core.error(`Failed to send webhook to endpoint "${endpointName}": ${errorMessage}`);
+      return {
+        success: false,
+        error: errorMessage,
+      };
+    }
+  };
+}
+
+module.exports = { main, HANDLER_TYPE, isHostAllowed, signPayload };
@@ -0,0 +1,206 @@
+// @ts-check
+import { describe, it, expect, beforeEach, afterEach, vi } from "vitest";
+import crypto from "crypto";
+import { main, isHostAllowed, signPayload } from "./send_webhook.cjs";
+
+// Mock dependencies
+global.core = {
+  info: vi.fn(),
+  warning: vi.fn(),
+  error: vi.fn(),
+  debug: vi.fn(),
+};
+
+const baseConfig = {
+  max: 5,
+  retries: 2,
+  allowed_domains: ["hooks.example.com"],
+  endpoints: {
+    "chat-ops": { url_env: "GH_AW_WEBHOOK_CHAT_OPS_URL", secret_env: "GH_AW_WEBHOOK_CHAT_OPS_SECRET" },
+    ticketing: { url_env: "GH_AW_WEBHOOK_TICKETING_URL" },
+  },
+  schema: {
+    type: "object",
+    required: ["text"],
+    properties: {
+      text: { type: "string", maxLength: 100 },
+    },
+  },
+};
+
+describe("send_webhook handler factory", () => {
+  /** @type {any} */
+  let fetchMock;
+
+  beforeEach(() => {
+    vi.clearAllMocks();
+    fetchMock = vi.fn().mockResolvedValue({ ok: true, status: 200 });
+    global.fetch = fetchMock;
+    process.env.GH_AW_WEBHOOK_CHAT_OPS_URL = "https://hooks.example.com/services/T000";
+    process.env.GH_AW_WEBHOOK_CHAT_OPS_SECRET = "s3cret";
+    process.env.GH_AW_WEBHOOK_TICKETING_URL = "https://tickets.other.com/hook";
+    delete process.env.GH_AW_SAFE_OUTPUTS_STAGED;
+  });
+
+  afterEach(() => {
+    vi.useRealTimers();
+    delete process.env.GH_AW_WEBHOOK_CHAT_OPS_URL;
+    delete process.env.GH_AW_WEBHOOK_CHAT_OPS_SECRET;
+    delete process.env.GH_AW_WEBHOOK_TICKETING_URL;
+  });
+
+  it("should send a signed payload to the configured endpoint", async () => {
+    const handler = await main(baseConfig);
+    const result = await handler({ type: "send_webhook", endpoint: "chat-ops", payload: { text: "Build is green" } }, {});
+
+    expect(result).toEqual({ success: true, endpoint: "chat-ops", status: 200 });
+    expect(fetchMock).toHaveBeenCalledTimes(1);
+
+    const [url, request] = fetchMock.mock.calls[0];
+    const body = JSON.stringify({ text: "Build is green" });
+    const expectedSignature = "sha256=" + crypto.createHmac("sha256", "s3cret").update(body).digest("hex");
+    expect(url.toString()).toBe("https://hooks.example.com/services/T000");
+    expect(request.method).toBe("POST");
+    expect(request.body).toBe(body);
+    expect(request.headers["Content-Type"]).toBe("application/json");
+    expect(request.headers["X-Hub-Signature-256"]).toBe(expectedSignature);
+  });
+
+  it("should reject unknown endpoints", async () => {
+    const handler = await main(baseConfig);
+    const result = await handler({ type: "send_webhook", endpoint: "constructor", payload: { text: "hi" } }, {});
+
+    expect(result.success).toBe(false);
+    expect(result.error).toContain("not configured");
+    expect(fetchMock).not.toHaveBeenCalled();
+  });
+
+  it("should reject payloads that do not match the schema", async () => {
+    const handler = await main(baseConfig);
+    const result = await handler({ type: "send_webhook", endpoint: "chat-ops", payload: { text: 42 } }, {});
+
+    expect(result.success).toBe(false);
+    expect(result.error).toContain("does not match the configured schema");
+    expect(fetchMock).not.toHaveBeenCalled();
+  });
+
+  it("should refuse endpoints whose host is not allowed", async () => {
+    const handler = await main(baseConfig);
+    const result = await handler({ type: "send_webhook", endpoint: "ticketing", payload: { text: "hi" } }, {});
+
+    expect(result.success).toBe(false);
+    expect(result.error).toContain("tickets.other.com is not listed in safe-outputs.allowed-domains");
+    expect(fetchMock).not.toHaveBeenCalled();
+  });
+
+  it("should refuse non-https endpoint URLs", async () => {
+    process.env.GH_AW_WEBHOOK_CHAT_OPS_URL = "http://hooks.example.com/services/T000";
+    const handler = await main(baseConfig);
+    const result = await handler({ type: "send_webhook", endpoint: "chat-ops", payload: { text: "hi" } }, {});
+
+    expect(result.success).toBe(false);
+    expect(result.error).toBe("Endpoint URL must use https");
+  });
+
+  it("should fail when the signing secret is missing", async () => {
+    delete process.env.GH_AW_WEBHOOK_CHAT_OPS_SECRET;
+    const handler = await main(baseConfig);
+    const result = await handler({ type: "send_webhook", endpoint: "chat-ops", payload: { text: "hi" } }, {});
+
+    expect(result.success).toBe(false);
+    expect(result.error).toContain("Signing secret is not set");
+    expect(fetchMock).not.toHaveBeenCalled();
+  });
+
+  it("should retry on server errors", async () => {
+    vi.useFakeTimers();
+    fetchMock.mockResolvedValueOnce({ ok: false, status: 503 }).mockResolvedValueOnce({ ok: false, status: 429 }).mockResolvedValueOnce({ ok: true, status: 202 });
+
+    const handler = await main(baseConfig);
+    const pending = handler({ type: "send_webhook", endpoint: "chat-ops", payload: { text: "hi" } }, {});
+    await vi.runAllTimersAsync();
+    const result = await pending;
+
+    expect(result).toEqual({ success: true, endpoint: "chat-ops", status: 202 });
+    expect(fetchMock).toHaveBeenCalledTimes(3);
+  });
+
+  it("should not retry on client errors", async () => {
+    fetchMock.mockResolvedValue({ ok: false, status: 400 });
+
+    const handler = await main(baseConfig);
+    const result = await handler({ type: "send_webhook", endpoint: "chat-ops", payload: { text: "hi" } }, {});
+
+    expect(result.success).toBe(false);
+    expect(result.error).toBe("Webhook returned HTTP 400");
+    expect(fetchMock).toHaveBeenCalledTimes(1);
+  });
+
+  it("should not follow redirects", async () => {
+    fetchMock.mockResolvedValue({ ok: false, status: 302, type: "basic" });
+
+    const handler = await main(baseConfig);
+    const result = await handler({ type: "send_webhook", endpoint: "chat-ops", payload: { text: "hi" } }, {});
+
+    expect(result.success).toBe(false);
+    expect(result.error).toBe("Webhook returned a redirect (HTTP 302); redirects are not followed");
+    expect(fetchMock).toHaveBeenCalledTimes(1);
+    expect(fetchMock.mock.calls[0][1].redirect).toBe("manual");
+  });
+
+  it("should not count invalid messages toward max count", async () => {
+    const handler = await main({ ...baseConfig, max: 1 });
+    const invalid = await handler({ type: "send_webhook", endpoint: "unknown", payload: { text: "one" } }, {});
+    const mismatched = await handler({ type: "send_webhook", endpoint: "chat-ops", payload: { message: "two" } }, {});
+    const valid = await handler({ type: "send_webhook", endpoint: "chat-ops", payload: { text: "three" } }, {});
+
+    expect(invalid.success).toBe(false);
+    expect(mismatched.success).toBe(false);
+    expect(valid.success).toBe(true);
+    expect(fetchMock).toHaveBeenCalledTimes(1);
+  });
+
+  it("should respect max count", async () => {
+    const handler = await main({ ...baseConfig, max: 1 });
+    const first = await handler({ type: "send_webhook", endpoint: "chat-ops", payload: { text: "one" } }, {});
+    const second = await handler({ type: "send_webhook", endpoint: "chat-ops", payload: { text: "two" } }, {});
+
+    expect(first.success).toBe(true);
+    expect(second.success).toBe(false);
+    expect(second.error).toContain("Max count of 1 reached");
+    expect(fetchMock).toHaveBeenCalledTimes(1);
+  });
+
+  it("should preview without sending in staged mode", async () => {
+    process.env.GH_AW_SAFE_OUTPUTS_STAGED = "true";
+    const handler = await main(baseConfig);
+    const result = await handler({ type: "send_webhook", endpoint: "chat-ops", payload: { text: "hi" } }, {});
+
+    expect(result.success).toBe(true);
+    expect(result.staged).toBe(true);
+    expect(result.previewInfo).toEqual({ endpoint: "chat-ops", payload: { text: "hi" } });
+    expect(fetchMock).not.toHaveBeenCalled();
+    expect(JSON.stringify(core.info.mock.calls)).not.toContain("hooks.example.com");
+  });
+});
+
+describe("isHostAllowed", () => {
+  it.each([
+    ["hooks.example.com", ["hooks.example.com"], true],
+    ["HOOKS.example.com", ["https://hooks.example.com"], true],
+    ["a.hooks.example.com", ["*.example.com"], true],
+    ["example.com", ["*.example.com"], true],
+    ["a.hooks.example.com", ["hooks.example.com"], false],
+    ["evilexample.com", ["*.example.com"], false],
+    ["hooks.example.com", ["http://hooks.example.com"], false],
+  ])("%s with %j should be %s", (host, allowed, expected) => {
+    expect(isHostAllowed(host, allowed)).toBe(expected);
+  });
+});
+
+describe("signPayload", () => {
+  it("should produce a GitHub-style sha256 signature", () => {
+    const expected = "sha256=" + crypto.createHmac("sha256", "key").update("{}").digest("hex");
+    expect(signPayload("key", "{}")).toBe(expected);
+  });
+});
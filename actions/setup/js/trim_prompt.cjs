@@ -0,0 +1,222 @@
+// @ts-check
+/// <reference types="@actions/github-script" />
+
+// trim_prompt.cjs
+// Keeps the rendered prompt within the prompt budget (prompt-budget.trim).
+//
+// The compiler writes a marker line before each prompt section:
+//   <!-- gh-aw-prompt-section name="shared/reference.md" priority="3" -->
+// A section extends to the next marker. When the estimated prompt size exceeds
+// GH_AW_PROMPT_MAX_TOKENS, sections with the highest priority value (imports first,
+// then GitHub context, then tool instructions) are replaced by an outline of their
+// headings until the prompt fits. Priority 0 sections are never trimmed.
+// The untrimmed prompt is written to GH_AW_PROMPT_FULL so the agent can read trimmed
+// sections on demand. Markers are always removed from the final prompt.
+//
+// Expressions such as issue titles and bodies are interpolated after the prompt is
+// created, so they could contain marker lines. Before interpolation, seal() adds a
+// random per-run nonce to the compiler's markers and writes it to
+// GH_AW_PROMPT_SECTION_NONCE_FILE; main() only recognizes markers with that nonce.
+
+const crypto = require("crypto");
+const fs = require("fs");
+const { estimateTokens } = require("./estimate_tokens.cjs");
+const { getErrorMessage } = require("./error_helpers.cjs");
+const { ERR_CONFIG, ERR_SYSTEM } = require("./error_codes.cjs");
+
+const UNSEALED_MARKER_PATTERN = /^<!-- gh-aw-prompt-section name="([^"]*)" priority="(\d+)" -->$/gm;
+const NONCE_PATTERN = /^[0-9a-f]{32}$/;
+const MAX_OUTLINE_HEADINGS = 20;
+
+/**
+ * @typedef {Object} PromptSection
+ * @property {string} name - Section name (built-in section, import path or workflow file)
+ * @property {number} priority - Trimming priority (0 = never trimmed)
+ * @property {string} text - Section content without the marker
+ * @property {boolean} [trimmed] - Whether the section was replaced by an outline
+ */
+
+/**
+ * Returns the pattern matching the section markers sealed with a nonce
+ * @param {string} nonce - The per-run nonce
+ * @returns {RegExp}
+ */
+function sealedMarkerPattern(nonce) {
+  if (!NONCE_PATTERN.test(nonce)) {
+    throw new Error(`invalid prompt section nonce "${nonce}"`);
+  }
+  return new RegExp(`^<!-- gh-aw-prompt-section nonce="${nonce}" name="([^"]*)" priority="(\\d+)" -->\\n?`, "gm");
+}
+
+/**
+ * Adds the nonce to the section markers written by the compiler
+ * @param {string} content - The prompt before interpolation
+ * @param {string} nonce - The per-run nonce
+ * @returns {string}
+ */
+function sealSectionMarkers(content, nonce) {
+  sealedMarkerPattern(nonce);
+  return content.replace(UNSEALED_MARKER_PATTERN, (_, name, priority) => `<!-- gh-aw-prompt-section nonce="${nonce}" name="${name}" priority="${priority}" -->`);
+}
+
+/**
+ * Splits a prompt into sections at the section markers sealed with the nonce.
+ * Content before the first marker becomes a required "preamble" section.
+ * @param {string} content - The rendered prompt
+ * @param {string} nonce - The per-run nonce
+ * @returns {PromptSection[]}
+ */
+function parsePromptSections(content, nonce) {
+  /** @type {PromptSection[]} */
+  const sections = [];
+  let current = { name: "preamble", priority: 0, text: "" };
+  let lastIndex = 0;
+
+  for (const match of content.matchAll(sealedMarkerPattern(nonce))) {
+    current.text = content.slice(lastIndex, match.index);
+    if (lastIndex > 0 || current.text !== "") {
+      sections.push(current);
+    }
+    current = { name: match[1], priority: parseInt(match[2], 10), text: "" };
+    lastIndex = (match.index ?? 0) + match[0].length;
+  }
+  current.text = content.slice(lastIndex);
+  sections.push(current);
+  return sections;
+}
+
+/**
+ * Builds the short replacement for a trimmed section: its heading outline and a
+ * pointer to the untrimmed prompt
+ * @param {PromptSection} section - The section to summarize
+ * @param {string} fullPromptPath - Path of the untrimmed prompt
+ * @returns {string}
+ */
+function summarizeSection(section, fullPromptPath) {
+  const headings = section.text
+    .split("\n")
+    .filter(line => /^#{1,6}\s+\S/.test(line))
+    .slice(0, MAX_OUTLINE_HEADINGS);
+  const lines = [`> [!NOTE]`, `> Section "${section.name}" (~${estimateTokens(section.text)} tokens) was trimmed to fit the prompt budget. Read it from ${fullPromptPath} if you need it.`];
+  if (headings.length > 0) {
+    lines.push("", "Outline:", ...headings.map(heading => `- ${heading.replace(/^#+\s+/, "")}`));
+  }
+  return lines.join("\n") + "\n\n";
+}
+
+/**
+ * Replaces lower-priority sections with outlines until the prompt fits the budget.
+ * Sections are trimmed by descending priority and, within a priority, largest first.
+ * @param {PromptSection[]} sections - The prompt sections
+ * @param {number} maxTokens - The prompt budget in tokens
+ * @param {string} fullPromptPath - Path of the untrimmed prompt
+ * @returns {{ sections: PromptSection[], totalTokens: number, trimmed: string[] }}
+ */
+function trimPromptSections(sections, maxTokens, fullPromptPath) {
+  const result = sections.map(section => ({ ...section }));
+  let totalTokens = result.reduce((sum, section) => sum + estimateTokens(section.text), 0);
+  /** @type {string[]} */
+  const trimmed = [];
+
+  const candidates = result
+    .filter(section => section.priority > 0)
+    .sort((a, b) => b.priority - a.priority || estimateTokens(b.text) - estimateTokens(a.text));
+
+  for (const section of candidates) {
+    if (totalTokens <= maxTokens) {
+      break;
+    }
+    const summary = summarizeSection(section, fullPromptPath);
+    const saved = estimateTokens(section.text) - estimateTokens(summary);
+    if (saved <= 0) {
+      continue;
+    }
+    section.text = summary;
+    section.trimmed = true;
+    totalTokens -= saved;
+    trimmed.push(section.name);
+  }
+
+  return { sections: result, totalTokens, trimmed };
+}
+
+/**
+ * Removes the section markers sealed with the nonce from a prompt
+ * @param {string} content - The prompt content
+ * @param {string} nonce - The per-run nonce
+ * @returns {string}
+ */
+function stripSectionMarkers(content, nonce) {
+  return content.replace(sealedMarkerPattern(nonce), "");
+}
+
+/**
+ * Seals the section markers of the prompt with a new nonce. Runs before interpolation.
+ */
+async function seal() {
+  try {
+    const promptPath = process.env.GH_AW_PROMPT;
+    const nonceFile = process.env.GH_AW_PROMPT_SECTION_NONCE_FILE;
+    if (!promptPath || !nonceFile) {
+      core.setFailed(`${ERR_CONFIG}: GH_AW_PROMPT and GH_AW_PROMPT_SECTION_NONCE_FILE environment variables must be set`);
+      return;
+    }
+
+    const nonce = crypto.randomBytes(16).toString("hex");
+    const content = fs.readFileSync(promptPath, "utf8");
+    fs.writeFileSync(promptPath, sealSectionMarkers(content, nonce), "utf8");
+    fs.writeFileSync(nonceFile, nonce, { encoding: "utf8", mode: 0o600 });
+    core.info("Sealed prompt section markers");
+  } catch (error) {
+    core.setFailed(`${ERR_SYSTEM}: Failed to seal prompt sections: ${getErrorMessage(error)}`);
+  }
+}
+
+async function main() {
+  try {
+    const promptPath = process.env.GH_AW_PROMPT;
+    const fullPromptPath = process.env.GH_AW_PROMPT_FULL || "/tmp/gh-aw/aw-prompts/prompt-full.txt";
+    const nonceFile = process.env.GH_AW_PROMPT_SECTION_NONCE_FILE;
+    const maxTokens = parseInt(process.env.GH_AW_PROMPT_MAX_TOKENS || "", 10);
+    if (!promptPath || !nonceFile) {
+      core.setFailed(`${ERR_CONFIG}: GH_AW_PROMPT and GH_AW_PROMPT_SECTION_NONCE_FILE environment variables must be set`);
+      return;
+    }
+    if (!Number.isFinite(maxTokens) || maxTokens <= 0) {
+      core.setFailed(`${ERR_CONFIG}: GH_AW_PROMPT_MAX_TOKENS must be a positive number, got "${process.env.GH_AW_PROMPT_MAX_TOKENS}"`);
+      return;
+    }
+
+    const nonce = fs.readFileSync(nonceFile, "utf8").trim();
+    const content = fs.readFileSync(promptPath, "utf8");
+    const sections = parsePromptSections(content, nonce);
+    fs.writeFileSync(fullPromptPath, stripSectionMarkers(content, nonce), "utf8");
+
+    const totalTokens = sections.reduce((sum, section) => sum + estimateTokens(section.text), 0);
+    core.info(`Prompt size: ~${totalTokens} tokens in ${sections.length} section(s), budget ${maxTokens} tokens`);
+    for (const section of sections) {
+      core.info(`  ${section.name} (priority ${section.priority}): ~${estimateTokens(section.text)} tokens`);
+    }
+
+    if (totalTokens <= maxTokens) {
+      fs.writeFileSync(promptPath, stripSectionMarkers(content, nonce), "utf8");
+      core.info("Prompt is within budget, no sections trimmed");
+      return;
+    }
+
+    const result = trimPromptSections(sections, maxTokens, fullPromptPath);
+    fs.writeFileSync(promptPath, result.sections.map(section => section.text).join(""), "utf8");
+
+    if (result.trimmed.length > 0) {
+      core.info(`Trimmed ${result.trimmed.length} section(s): ${result.trimmed.join(", ")}`);
+      core.info(`Prompt size after trimming: ~${result.totalTokens} tokens`);
+    }
+    if (result.totalTokens > maxTokens) {
+      core.warning(`Prompt is still ~${result.totalTokens} tokens after trimming all lower-priority sections (budget ${maxTokens} tokens). Reduce the workflow body or raise prompt-budget.max-tokens.`);
+    }
+  } catch (error) {
+    core.setFailed(`${ERR_SYSTEM}: Failed to trim prompt: ${getErrorMessage(error)}`);
+  }
+}
+
+module.exports = { main, seal, sealSectionMarkers, parsePromptSections, summarizeSection, trimPromptSections, stripSectionMarkers };
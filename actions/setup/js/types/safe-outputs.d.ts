@@ -326,6 +326,17 @@ interface HideCommentItem extends BaseSafeOutputItem {
   reason?: "SPAM" | "ABUSE" | "OFF_TOPIC" | "OUTDATED" | "RESOLVED";
 }
 
+/**
+ * JSONL item for sending a payload to a webhook endpoint
+ */
+interface SendWebhookItem extends BaseSafeOutputItem {
+  type: "send_webhook";
+  /** Name of the configured endpoint */
+  endpoint: string;
+  /** JSON payload validated against the configured schema */
+  payload: Record<string, unknown>;
+}
+
 /**
  * JSONL item for replying to a pull request review comment
  */
@@ -405,6 +416,7 @@ type SafeOutputItem =
   | NoOpItem
   | LinkSubIssueItem
   | HideCommentItem
+  | SendWebhookItem
   | ReplyToPullRequestReviewCommentItem
   | CreateProjectItem
   | AutofixCodeScanningAlertItem
@@ -446,6 +458,7 @@ export {
   NoOpItem,
   LinkSubIssueItem,
   HideCommentItem,
+  SendWebhookItem,
   ReplyToPullRequestReviewCommentItem,
   AutofixCodeScanningAlertItem,
   ResolvePullRequestReviewThreadItem,
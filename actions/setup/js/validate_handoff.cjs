@@ -0,0 +1,66 @@
+// @ts-check
+/// <reference types="@actions/github-script" />
+
+// validate_handoff.cjs
+// Validates the hand-off file written by an agent stage (stages) before it is uploaded
+// for the next stages. The file must exist, contain a JSON object and match the
+// JSON Schema declared in handoff.schema. Any problem fails the stage job, so later
+// stages never run on a missing or malformed hand-off.
+
+const fs = require("fs");
+const { validateAgainstSchema } = require("./safe_inputs_validation.cjs");
+const { getErrorMessage } = require("./error_helpers.cjs");
+const { ERR_CONFIG, ERR_VALIDATION } = require("./error_codes.cjs");
+
+/**
+ * Validates hand-off content against the stage's schema
+ * @param {string} content - The raw hand-off file content
+ * @param {Object} schema - The JSON Schema of the hand-off
+ * @returns {string[]} Array of validation errors (empty if the hand-off is valid)
+ */
+function validateHandoff(content, schema) {
+  let value;
+  try {
+    value = JSON.parse(content);
+  } catch (error) {
+    return [`hand-off is not valid JSON: ${getErrorMessage(error)}`];
+  }
+  if (value === null || typeof value !== "object" || Array.isArray(value)) {
+    return ["hand-off must be a JSON object"];
+  }
+  return validateAgainstSchema(value, schema);
+}
+
+async function main() {
+  const stageId = process.env.GH_AW_STAGE_ID || "stage";
+  const handoffPath = process.env.GH_AW_HANDOFF_PATH;
+  if (!handoffPath) {
+    core.setFailed(`${ERR_CONFIG}: GH_AW_HANDOFF_PATH environment variable is not set`);
+    return;
+  }
+
+  let schema;
+  try {
+    schema = JSON.parse(process.env.GH_AW_HANDOFF_SCHEMA || '{"type":"object"}');
+  } catch (error) {
+    core.setFailed(`${ERR_CONFIG}: GH_AW_HANDOFF_SCHEMA is not valid JSON: ${getErrorMessage(error)}`);
+    return;
+  }
+
+  if (!fs.existsSync(handoffPath)) {
+    core.setFailed(`${ERR_VALIDATION}: Stage "${stageId}" did not write its hand-off file ${handoffPath}`);
+    return;
+  }
+
+  const content = fs.readFileSync(handoffPath, "utf8");
+  const errors = validateHandoff(content, schema);
+  if (errors.length > 0) {
+    core.setFailed(`${ERR_VALIDATION}: Hand-off of stage "${stageId}" does not match its schema:\n${errors.map(error => `- ${error}`).join("\n")}`);
+    return;
+  }
+
+  core.info(`Hand-off of stage "${stageId}" is valid (${content.length} bytes)`);
+  await core.summary.addHeading(`Hand-off: ${stageId}`, 3).addCodeBlock(content, "json").write();
+}
+
+module.exports = { main, validateHandoff };
@@ -0,0 +1,78 @@
+import { describe, it, expect, vi, beforeEach, afterEach } from "vitest";
+import fs from "fs";
+import os from "os";
+import path from "path";
+
+const summary = { addHeading: vi.fn(), addCodeBlock: vi.fn(), write: vi.fn() };
+summary.addHeading.mockReturnValue(summary);
+summary.addCodeBlock.mockReturnValue(summary);
+const core = { info: vi.fn(), setFailed: vi.fn(), summary };
+global.core = core;
+
+const { main, validateHandoff } = require("./validate_handoff.cjs");
+
+const schema = {
+  type: "object",
+  properties: { steps: { type: "array", items: { type: "string" } } },
+  required: ["steps"],
+};
+
+describe("validate_handoff.cjs", () => {
+  beforeEach(() => {
+    vi.clearAllMocks();
+    summary.addHeading.mockReturnValue(summary);
+    summary.addCodeBlock.mockReturnValue(summary);
+  });
+
+  describe("validateHandoff", () => {
+    it("should accept a hand-off matching the schema", () => {
+      expect(validateHandoff('{"steps":["plan","implement"]}', schema)).toEqual([]);
+    });
+
+    it("should report schema violations", () => {
+      expect(validateHandoff('{"steps":[1]}', schema).length).toBeGreaterThan(0);
+      expect(validateHandoff("{}", schema).length).toBeGreaterThan(0);
+    });
+
+    it("should reject invalid JSON and non-objects", () => {
+      expect(validateHandoff("not json", schema)[0]).toContain("not valid JSON");
+      expect(validateHandoff("[1]", schema)).toEqual(["hand-off must be a JSON object"]);
+    });
+  });
+
+  describe("main", () => {
+    let tmpDir;
+
+    beforeEach(() => {
+      tmpDir = fs.mkdtempSync(path.join(os.tmpdir(), "validate-handoff-"));
+      process.env.GH_AW_STAGE_ID = "planner";
+      process.env.GH_AW_HANDOFF_PATH = path.join(tmpDir, "planner.json");
+      process.env.GH_AW_HANDOFF_SCHEMA = JSON.stringify(schema);
+    });
+
+    afterEach(() => {
+      fs.rmSync(tmpDir, { recursive: true, force: true });
+      delete process.env.GH_AW_STAGE_ID;
+      delete process.env.GH_AW_HANDOFF_PATH;
+      delete process.env.GH_AW_HANDOFF_SCHEMA;
+    });
+
+    it("should pass a valid hand-off", async () => {
+      fs.writeFileSync(process.env.GH_AW_HANDOFF_PATH, '{"steps":["a"]}');
+      await main();
+      expect(core.setFailed).not.toHaveBeenCalled();
+      expect(summary.write).toHaveBeenCalled();
+    });
+
+    it("should fail when the hand-off file is missing", async () => {
+      await main();
+      expect(core.setFailed).toHaveBeenCalledWith(expect.stringContaining('Stage "planner" did not write its hand-off file'));
+    });
+
+    it("should fail when the hand-off does not match the schema", async () => {
+      fs.writeFileSync(process.env.GH_AW_HANDOFF_PATH, '{"plan":"x"}');
+      await main();
+      expect(core.setFailed).toHaveBeenCalledWith(expect.stringContaining("does not match its schema"));
+    });
+  });
+});
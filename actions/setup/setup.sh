@@ -228,6 +228,7 @@ SAFE_OUTPUTS_FILES=(
   "git_helpers.cjs"
   "mcp_enhanced_errors.cjs"
   "comment_limit_helpers.cjs"
+  "safe_output_constraints.cjs"
   "shim.cjs"
 )
 
@@ -0,0 +1,43 @@
+#!/bin/bash
+set -e
+
+# check_mcp_gateway_spec_version.sh - Verify the gateway implements the required specification version
+#
+# Usage: check_mcp_gateway_spec_version.sh MIN_SPEC_VERSION HEALTH_RESPONSE
+#
+# Arguments:
+#   MIN_SPEC_VERSION : Minimum MCP Gateway Specification version (e.g., "1.9.0")
+#   HEALTH_RESPONSE  : JSON body returned by the gateway /health endpoint
+#
+# Per MCP Gateway Specification section 8.1.1, /health reports the specification version
+# the gateway implements in specVersion. Workflows that rely on newer configuration fields
+# (such as toolConstraints) must not run on a gateway that would ignore them.
+#
+# Exit codes:
+#   0 - The gateway implements MIN_SPEC_VERSION or later
+#   1 - The gateway is older, or did not report a specification version
+
+if [ "$#" -ne 2 ]; then
+  echo "Usage: $0 MIN_SPEC_VERSION HEALTH_RESPONSE" >&2
+  exit 1
+fi
+
+MIN_SPEC_VERSION="$1"
+HEALTH_RESPONSE="$2"
+
+SPEC_VERSION=$(echo "$HEALTH_RESPONSE" | jq -r '.specVersion // empty' 2>/dev/null || true)
+if [ -z "$SPEC_VERSION" ]; then
+  echo "ERROR: MCP gateway did not report specVersion in its /health response" >&2
+  echo "This workflow requires MCP Gateway Specification ${MIN_SPEC_VERSION} or later" >&2
+  exit 1
+fi
+
+# sort -V orders versions numerically; the lowest one comes first
+LOWEST=$(printf '%s\n%s\n' "${MIN_SPEC_VERSION#v}" "${SPEC_VERSION#v}" | sort -V | head -n 1)
+if [ "$LOWEST" != "${MIN_SPEC_VERSION#v}" ]; then
+  echo "ERROR: MCP gateway implements specification ${SPEC_VERSION}, but this workflow requires ${MIN_SPEC_VERSION} or later" >&2
+  echo "Pin a newer gateway with sandbox.mcp.version, or remove the settings that need it" >&2
+  exit 1
+fi
+
+echo "MCP gateway implements specification ${SPEC_VERSION} (requires ${MIN_SPEC_VERSION})"
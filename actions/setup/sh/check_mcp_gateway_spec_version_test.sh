@@ -0,0 +1,75 @@
+#!/bin/bash
+# Test script for check_mcp_gateway_spec_version.sh
+set -e
+
+SCRIPT_DIR="$(cd "$(dirname "${BASH_SOURCE[0]}")" && pwd)"
+SCRIPT_PATH="$SCRIPT_DIR/check_mcp_gateway_spec_version.sh"
+
+# Color codes for output
+GREEN='\033[0;32m'
+RED='\033[0;31m'
+NC='\033[0m' # No Color
+
+# Test counters
+TESTS_RUN=0
+TESTS_PASSED=0
+TESTS_FAILED=0
+
+# Print test result
+print_result() {
+  local test_name="$1"
+  local result="$2"
+
+  TESTS_RUN=$((TESTS_RUN + 1))
+
+  if [ "$result" = "PASS" ]; then
+    echo -e "${GREEN}✓ PASS${NC}: $test_name"
+    TESTS_PASSED=$((TESTS_PASSED + 1))
+  else
+    echo -e "${RED}✗ FAIL${NC}: $test_name"
+    TESTS_FAILED=$((TESTS_FAILED + 1))
+  fi
+}
+
+# expect_result NAME EXPECTED_EXIT MIN_SPEC_VERSION HEALTH_RESPONSE
+expect_result() {
+  local name="$1"
+  local expected="$2"
+  local actual=0
+  bash "$SCRIPT_PATH" "$3" "$4" >/dev/null 2>&1 || actual=$?
+  if [ "$actual" = "$expected" ]; then
+    print_result "$name" "PASS"
+  else
+    print_result "$name (exit $actual, expected $expected)" "FAIL"
+  fi
+}
+
+echo "=== Testing check_mcp_gateway_spec_version.sh ==="
+echo "Script: $SCRIPT_PATH"
+
+if bash -n "$SCRIPT_PATH" 2>/dev/null; then
+  print_result "Script syntax is valid" "PASS"
+else
+  print_result "Script has syntax errors" "FAIL"
+fi
+
+expect_result "Rejects an empty response" 1 "1.9.0" ""
+expect_result "Accepts the required version" 0 "1.9.0" '{"status":"healthy","specVersion":"1.9.0"}'
+expect_result "Accepts a newer version" 0 "1.9.0" '{"status":"healthy","specVersion":"1.10.0"}'
+expect_result "Accepts a v-prefixed version" 0 "1.9.0" '{"status":"healthy","specVersion":"v2.0.0"}'
+expect_result "Rejects an older version" 1 "1.9.0" '{"status":"healthy","specVersion":"1.8.0"}'
+expect_result "Rejects a missing specVersion" 1 "1.9.0" '{"status":"healthy"}'
+expect_result "Rejects a non-JSON response" 1 "1.9.0" 'OK'
+
+# Print summary
+echo ""
+echo "=== Test Summary ==="
+echo "Tests run: $TESTS_RUN"
+echo -e "${GREEN}Tests passed: $TESTS_PASSED${NC}"
+if [ $TESTS_FAILED -gt 0 ]; then
+  echo -e "${RED}Tests failed: $TESTS_FAILED${NC}"
+  exit 1
+else
+  echo -e "${GREEN}All tests passed!${NC}"
+  exit 0
+fi
@@ -279,6 +279,16 @@ else
 fi
 echo ""
 
+# Verify the gateway implements the specification version the configuration needs
+# (e.g. toolConstraints need 1.9.0), so that newer settings are never silently ignored
+if [ -n "$MCP_GATEWAY_MIN_SPEC_VERSION" ]; then
+  if ! bash /opt/gh-aw/actions/check_mcp_gateway_spec_version.sh "$MCP_GATEWAY_MIN_SPEC_VERSION" "$HEALTH_RESPONSE"; then
+    kill $GATEWAY_PID 2>/dev/null || true
+    exit 1
+  fi
+  echo ""
+fi
+
 # Wait for gateway output (rewritten configuration)
 echo "Reading gateway output configuration..."
 OUTPUT_WAIT_START=$(date +%s%3N)
@@ -241,7 +241,11 @@ Examples:
   ` + string(constants.CLIExtensionPrefix) + ` compile --watch ci-doctor     # Watch and auto-compile
   ` + string(constants.CLIExtensionPrefix) + ` compile --trial --logical-repo owner/repo  # Compile for trial mode
   ` + string(constants.CLIExtensionPrefix) + ` compile --dependabot        # Generate Dependabot manifests
-  ` + string(constants.CLIExtensionPrefix) + ` compile --dependabot --force  # Force overwrite existing dependabot.yml`,
+  ` + string(constants.CLIExtensionPrefix) + ` compile --dependabot --force  # Force overwrite existing dependabot.yml
+  ` + string(constants.CLIExtensionPrefix) + ` compile ci-doctor --explain-merge  # Show where each imported setting came from
+  GH_HOST=github.example.com ` + string(constants.CLIExtensionPrefix) + ` compile --target ghes:3.16  # Compile for GitHub Enterprise Server
+  ` + string(constants.CLIExtensionPrefix) + ` compile --offline --bundle gh-aw-bundle.tar.gz  # Compile without network access
+  ` + string(constants.CLIExtensionPrefix) + ` compile --provenance-key signing-key.pem  # Sign provenance for each lock file`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		engineOverride, _ := cmd.Flags().GetString("engine")
 		actionMode, _ := cmd.Flags().GetString("action-mode")
@@ -266,14 +270,19 @@ Examples:
 		fix, _ := cmd.Flags().GetBool("fix")
 		stats, _ := cmd.Flags().GetBool("stats")
 		failFast, _ := cmd.Flags().GetBool("fail-fast")
+		explainMerge, _ := cmd.Flags().GetBool("explain-merge")
+		target, _ := cmd.Flags().GetString("target")
+		offline, _ := cmd.Flags().GetBool("offline")
+		bundle, _ := cmd.Flags().GetString("bundle")
+		provenanceKey, _ := cmd.Flags().GetString("provenance-key")
 		noCheckUpdate, _ := cmd.Flags().GetBool("no-check-update")
 		verbose, _ := cmd.Flags().GetBool("verbose")
 		if err := validateEngine(engineOverride); err != nil {
 			return err
 		}
 
-		// Check for updates (non-blocking, runs once per day)
-		cli.CheckForUpdatesAsync(cmd.Context(), noCheckUpdate, verbose)
+		// Check for updates (non-blocking, runs once per day); offline compilation must not reach the network
+		cli.CheckForUpdatesAsync(cmd.Context(), noCheckUpdate || offline, verbose)
 
 		// If --fix is specified, run fix --write first
 		if fix {
@@ -318,6 +327,11 @@ Examples:
 			JSONOutput:             jsonOutput,
 			Stats:                  stats,
 			FailFast:               failFast,
+			ExplainMerge:           explainMerge,
+			Target:                 target,
+			Offline:                offline,
+			Bundle:                 bundle,
+			ProvenanceKey:          provenanceKey,
 		}
 		if _, err := cli.CompileWorkflows(cmd.Context(), config); err != nil {
 			// Return error as-is without additional formatting
@@ -538,7 +552,11 @@ Use "` + string(constants.CLIExtensionPrefix) + ` help all" to show help for all
 	compileCmd.Flags().StringP("engine", "e", "", "Override AI engine (claude, codex, copilot, custom)")
 	compileCmd.Flags().String("action-mode", "", "Action script inlining mode (inline, dev, release). Auto-detected if not specified")
 	compileCmd.Flags().String("action-tag", "", "Override action SHA or tag for actions/setup (overrides action-mode to release). Accepts full SHA or tag name")
-	compileCmd.Flags().Bool("validate", false, "Enable GitHub Actions workflow schema validation, container image validation, and action SHA validation")
+	compileCmd.Flags().String("target", "", "Platform to compile for: github.com (default) or ghes:<version>. GHES targets read the host from GH_HOST or GITHUB_SERVER_URL")
+	compileCmd.Flags().Bool("offline", false, "Compile without network access, reading action pins and remote imports from --bundle")
+	compileCmd.Flags().String("bundle", "", "Offline bundle created by 'gh aw bundle export' (used with --offline)")
+	compileCmd.Flags().String("provenance-key", "", "Ed25519 private key (PEM) used to sign a provenance statement next to each lock file")
+	compileCmd.Flags().Bool("validate", false, "Enable GitHub Actions workflow schema validation, container image validation, action SHA validation, and MCP snapshot checks")
 	compileCmd.Flags().BoolP("watch", "w", false, "Watch for changes to workflow files and recompile automatically")
 	compileCmd.Flags().StringP("dir", "d", "", "Workflow directory (default: .github/workflows)")
 	compileCmd.Flags().String("workflows-dir", "", "Deprecated: use --dir instead")
@@ -559,6 +577,7 @@ Use "` + string(constants.CLIExtensionPrefix) + ` help all" to show help for all
 	compileCmd.Flags().BoolP("json", "j", false, "Output results in JSON format")
 	compileCmd.Flags().Bool("stats", false, "Display statistics table sorted by file size (shows jobs, steps, scripts, and shells)")
 	compileCmd.Flags().Bool("fail-fast", false, "Stop at the first validation error instead of collecting all errors")
+	compileCmd.Flags().Bool("explain-merge", false, "Print which file (workflow or import) contributed each tools, network, permissions and safe-outputs setting")
 	compileCmd.Flags().Bool("no-check-update", false, "Skip checking for gh-aw updates")
 	compileCmd.MarkFlagsMutuallyExclusive("dir", "workflows-dir")
 
@@ -606,14 +625,22 @@ Use "` + string(constants.CLIExtensionPrefix) + ` help all" to show help for all
 	logsCmd := cli.NewLogsCommand()
 	auditCmd := cli.NewAuditCommand()
 	healthCmd := cli.NewHealthCommand()
+	dashboardCmd := cli.NewDashboardCommand()
 	mcpServerCmd := cli.NewMCPServerCommand()
 	prCmd := cli.NewPRCommand()
 	secretsCmd := cli.NewSecretsCommand()
 	fixCmd := cli.NewFixCommand()
+	explainCmd := cli.NewExplainCommand()
+	permissionsCmd := cli.NewPermissionsCommand()
+	networkCmd := cli.NewNetworkCommand()
 	upgradeCmd := cli.NewUpgradeCommand()
 	completionCmd := cli.NewCompletionCommand()
 	hashCmd := cli.NewHashCommand()
 	projectCmd := cli.NewProjectCommand()
+	catalogCmd := cli.NewCatalogCommand()
+	bundleCmd := cli.NewBundleCommand()
+	verifyCmd := cli.NewVerifyCommand()
+	provenanceCmd := cli.NewProvenanceCommand()
 
 	// Assign commands to groups
 	// Setup Commands
@@ -624,6 +651,7 @@ Use "` + string(constants.CLIExtensionPrefix) + ` help all" to show help for all
 	updateCmd.GroupID = "setup"
 	upgradeCmd.GroupID = "setup"
 	secretsCmd.GroupID = "setup"
+	catalogCmd.GroupID = "setup"
 
 	// Development Commands
 	compileCmd.GroupID = "development"
@@ -631,6 +659,12 @@ Use "` + string(constants.CLIExtensionPrefix) + ` help all" to show help for all
 	statusCmd.GroupID = "development"
 	listCmd.GroupID = "development"
 	fixCmd.GroupID = "development"
+	explainCmd.GroupID = "development"
+	permissionsCmd.GroupID = "development"
+	networkCmd.GroupID = "development"
+	bundleCmd.GroupID = "development"
+	verifyCmd.GroupID = "development"
+	provenanceCmd.GroupID = "development"
 
 	// Execution Commands
 	runCmd.GroupID = "execution"
@@ -642,6 +676,7 @@ Use "` + string(constants.CLIExtensionPrefix) + ` help all" to show help for all
 	logsCmd.GroupID = "analysis"
 	auditCmd.GroupID = "analysis"
 	healthCmd.GroupID = "analysis"
+	dashboardCmd.GroupID = "analysis"
 
 	// Utilities
 	mcpServerCmd.GroupID = "utilities"
@@ -669,15 +704,23 @@ Use "` + string(constants.CLIExtensionPrefix) + ` help all" to show help for all
 	rootCmd.AddCommand(logsCmd)
 	rootCmd.AddCommand(auditCmd)
 	rootCmd.AddCommand(healthCmd)
+	rootCmd.AddCommand(dashboardCmd)
 	rootCmd.AddCommand(mcpCmd)
 	rootCmd.AddCommand(mcpServerCmd)
 	rootCmd.AddCommand(prCmd)
 	rootCmd.AddCommand(versionCmd)
 	rootCmd.AddCommand(secretsCmd)
 	rootCmd.AddCommand(fixCmd)
+	rootCmd.AddCommand(explainCmd)
+	rootCmd.AddCommand(permissionsCmd)
+	rootCmd.AddCommand(networkCmd)
+	rootCmd.AddCommand(bundleCmd)
+	rootCmd.AddCommand(verifyCmd)
+	rootCmd.AddCommand(provenanceCmd)
 	rootCmd.AddCommand(completionCmd)
 	rootCmd.AddCommand(hashCmd)
 	rootCmd.AddCommand(projectCmd)
+	rootCmd.AddCommand(catalogCmd)
 }
 
 func main() {
@@ -208,6 +208,8 @@ export default defineConfig({
 				{
 					label: 'Reference',
 					items: [
+						{ label: 'Agent Stages', link: '/reference/agent-stages/' },
+						{ label: 'Matrix Runs', link: '/reference/matrix-runs/' },
 						{ label: 'AI Engines', link: '/reference/engines/' },
 						{ label: 'Assign to Copilot', link: '/reference/assign-to-copilot/' },
 						{ label: 'Authentication', link: '/reference/auth/' },
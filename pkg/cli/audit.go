@@ -60,7 +60,9 @@ Examples:
   ` + string(constants.CLIExtensionPrefix) + ` audit https://github.example.com/owner/repo/actions/runs/1234567890  # Audit from GitHub Enterprise
   ` + string(constants.CLIExtensionPrefix) + ` audit 1234567890 -o ./audit-reports  # Custom output directory
   ` + string(constants.CLIExtensionPrefix) + ` audit 1234567890 -v  # Verbose output
-  ` + string(constants.CLIExtensionPrefix) + ` audit 1234567890 --parse  # Parse agent logs and firewall logs, generating log.md and firewall.md`,
+  ` + string(constants.CLIExtensionPrefix) + ` audit 1234567890 --format html  # Write a self-contained HTML report to report.html
+  ` + string(constants.CLIExtensionPrefix) + ` audit 1234567890 --parse  # Parse agent logs and firewall logs, generating log.md and firewall.md
+  ` + string(constants.CLIExtensionPrefix) + ` audit 1234567890 --export-cassette .github/cassettes/triage.jsonl  # Export MCP tool calls for replay`,
 		Args: cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			runIDOrURL := args[0]
@@ -74,10 +76,21 @@ Examples:
 
 			outputDir, _ := cmd.Flags().GetString("output")
 			verbose, _ := cmd.Flags().GetBool("verbose")
-			jsonOutput, _ := cmd.Flags().GetBool("json")
+			format, err := getReportFormat(cmd)
+			if err != nil {
+				return err
+			}
 			parse, _ := cmd.Flags().GetBool("parse")
+			exportCassette, _ := cmd.Flags().GetString("export-cassette")
 
-			return AuditWorkflowRun(
+			if exportCassette != "" && components.JobID > 0 {
+				return errors.New("--export-cassette requires a run ID or run URL, not a job URL")
+			}
+			if format == reportFormatHTML && components.JobID > 0 {
+				return errors.New("--format html requires a run ID or run URL, not a job URL")
+			}
+
+			if err := AuditWorkflowRun(
 				cmd.Context(),
 				components.Number,
 				components.Owner,
@@ -86,17 +99,28 @@ Examples:
 				outputDir,
 				verbose,
 				parse,
-				jsonOutput,
+				format == reportFormatJSON,
+				format == reportFormatHTML,
 				components.JobID,
 				components.StepNumber,
-			)
+			); err != nil {
+				return err
+			}
+
+			if exportCassette != "" {
+				runOutputDir := filepath.Join(outputDir, fmt.Sprintf("run-%d", components.Number))
+				return ExportRunCassette(runOutputDir, exportCassette, verbose)
+			}
+			return nil
 		},
 	}
 
 	// Add flags to audit command
 	addOutputFlag(cmd, defaultLogsOutputDir)
 	addJSONFlag(cmd)
+	addFormatFlag(cmd)
 	cmd.Flags().Bool("parse", false, "Run JavaScript parsers on agent logs and firewall logs, writing Markdown to log.md and firewall.md")
+	cmd.Flags().String("export-cassette", "", "Write the run's MCP tool calls and responses to a cassette file for replay with sandbox.mcp.replay")
 
 	// Register completions for audit command
 	RegisterDirFlagCompletion(cmd, "output")
@@ -129,7 +153,8 @@ func isPermissionError(err error) bool {
 // AuditWorkflowRun audits a single workflow run and generates a report
 // If jobID is provided (>0), focuses audit on that specific job
 // If stepNumber is provided (>0), extracts output for that specific step
-func AuditWorkflowRun(ctx context.Context, runID int64, owner, repo, hostname string, outputDir string, verbose bool, parse bool, jsonOutput bool, jobID int64, stepNumber int) error {
+// If htmlReport is set, writes a self-contained HTML report to the run directory instead of console output
+func AuditWorkflowRun(ctx context.Context, runID int64, owner, repo, hostname string, outputDir string, verbose bool, parse bool, jsonOutput bool, htmlReport bool, jobID int64, stepNumber int) error {
 	auditLog.Printf("Starting audit for workflow run: runID=%d, owner=%s, repo=%s, jobID=%d, stepNumber=%d", runID, owner, repo, jobID, stepNumber)
 
 	// Check context cancellation at the start
@@ -323,6 +348,13 @@ func AuditWorkflowRun(ctx context.Context, runID int64, owner, repo, hostname st
 		fmt.Fprintln(os.Stderr, console.FormatWarningMessage(fmt.Sprintf("Failed to list artifacts: %v", err)))
 	}
 
+	// Parse gateway metrics, including tool calls rejected by argument constraints
+	gatewayMetrics, gatewayErr := parseGatewayLogs(runOutputDir, verbose)
+	var constraintViolations []GatewayConstraintViolation
+	if gatewayErr == nil {
+		constraintViolations = gatewayMetrics.ConstraintViolations
+	}
+
 	// Create processed run for report generation
 	processedRun := ProcessedRun{
 		Run:                     run,
@@ -333,6 +365,7 @@ func AuditWorkflowRun(ctx context.Context, runID int64, owner, repo, hostname st
 		Noops:                   noops,
 		MCPFailures:             mcpFailures,
 		JobDetails:              jobDetails,
+		ConstraintViolations:    constraintViolations,
 	}
 
 	// Build structured audit data
@@ -343,12 +376,18 @@ func AuditWorkflowRun(ctx context.Context, runID int64, owner, repo, hostname st
 		if err := renderJSON(auditData); err != nil {
 			return fmt.Errorf("failed to render JSON output: %w", err)
 		}
+	} else if htmlReport {
+		graph := buildToolGraph([]ProcessedRun{processedRun}, verbose)
+		report := buildAuditHTMLReport(auditData, graph, time.Now())
+		if err := writeHTMLReport(filepath.Join(runOutputDir, htmlReportFileName), report); err != nil {
+			return err
+		}
 	} else {
 		renderConsole(auditData, runOutputDir)
 	}
 
 	// Display gateway metrics if available
-	if gatewayMetrics, err := parseGatewayLogs(runOutputDir, verbose); err == nil {
+	if gatewayErr == nil {
 		if metricsOutput := renderGatewayMetricsTable(gatewayMetrics, verbose); metricsOutput != "" {
 			fmt.Fprint(os.Stderr, metricsOutput)
 		}
@@ -19,25 +19,26 @@ var auditReportLog = logger.New("cli:audit_report")
 
 // AuditData represents the complete structured audit data for a workflow run
 type AuditData struct {
-	Overview                OverviewData             `json:"overview"`
-	Metrics                 MetricsData              `json:"metrics"`
-	KeyFindings             []Finding                `json:"key_findings,omitempty"`
-	Recommendations         []Recommendation         `json:"recommendations,omitempty"`
-	FailureAnalysis         *FailureAnalysis         `json:"failure_analysis,omitempty"`
-	PerformanceMetrics      *PerformanceMetrics      `json:"performance_metrics,omitempty"`
-	Jobs                    []JobData                `json:"jobs,omitempty"`
-	DownloadedFiles         []FileInfo               `json:"downloaded_files"`
-	MissingTools            []MissingToolReport      `json:"missing_tools,omitempty"`
-	MissingData             []MissingDataReport      `json:"missing_data,omitempty"`
-	Noops                   []NoopReport             `json:"noops,omitempty"`
-	MCPFailures             []MCPFailureReport       `json:"mcp_failures,omitempty"`
-	FirewallAnalysis        *FirewallAnalysis        `json:"firewall_analysis,omitempty"`
-	RedactedDomainsAnalysis *RedactedDomainsAnalysis `json:"redacted_domains_analysis,omitempty"`
-	Errors                  []ErrorInfo              `json:"errors,omitempty"`
-	Warnings                []ErrorInfo              `json:"warnings,omitempty"`
-	ToolUsage               []ToolUsageInfo          `json:"tool_usage,omitempty"`
-	MCPToolUsage            *MCPToolUsageData        `json:"mcp_tool_usage,omitempty"`
-	CreatedItems            []CreatedItemReport      `json:"created_items,omitempty"`
+	Overview                OverviewData                 `json:"overview"`
+	Metrics                 MetricsData                  `json:"metrics"`
+	KeyFindings             []Finding                    `json:"key_findings,omitempty"`
+	Recommendations         []Recommendation             `json:"recommendations,omitempty"`
+	FailureAnalysis         *FailureAnalysis             `json:"failure_analysis,omitempty"`
+	PerformanceMetrics      *PerformanceMetrics          `json:"performance_metrics,omitempty"`
+	Jobs                    []JobData                    `json:"jobs,omitempty"`
+	DownloadedFiles         []FileInfo                   `json:"downloaded_files"`
+	MissingTools            []MissingToolReport          `json:"missing_tools,omitempty"`
+	MissingData             []MissingDataReport          `json:"missing_data,omitempty"`
+	Noops                   []NoopReport                 `json:"noops,omitempty"`
+	MCPFailures             []MCPFailureReport           `json:"mcp_failures,omitempty"`
+	ConstraintViolations    []GatewayConstraintViolation `json:"constraint_violations,omitempty"`
+	FirewallAnalysis        *FirewallAnalysis            `json:"firewall_analysis,omitempty"`
+	RedactedDomainsAnalysis *RedactedDomainsAnalysis     `json:"redacted_domains_analysis,omitempty"`
+	Errors                  []ErrorInfo                  `json:"errors,omitempty"`
+	Warnings                []ErrorInfo                  `json:"warnings,omitempty"`
+	ToolUsage               []ToolUsageInfo              `json:"tool_usage,omitempty"`
+	MCPToolUsage            *MCPToolUsageData            `json:"mcp_tool_usage,omitempty"`
+	CreatedItems            []CreatedItemReport          `json:"created_items,omitempty"`
 }
 
 // Finding represents a key insight discovered during audit
@@ -339,6 +340,7 @@ func buildAuditData(processedRun ProcessedRun, metrics LogMetrics, mcpToolUsage
 		MissingData:             processedRun.MissingData,
 		Noops:                   processedRun.Noops,
 		MCPFailures:             processedRun.MCPFailures,
+		ConstraintViolations:    processedRun.ConstraintViolations,
 		FirewallAnalysis:        processedRun.FirewallAnalysis,
 		RedactedDomainsAnalysis: processedRun.RedactedDomainsAnalysis,
 		Errors:                  errors,
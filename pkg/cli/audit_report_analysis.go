@@ -103,6 +103,17 @@ func generateFindings(processedRun ProcessedRun, metrics MetricsData, errors []E
 		})
 	}
 
+	// Tool calls rejected by argument constraints
+	if len(processedRun.ConstraintViolations) > 0 {
+		findings = append(findings, Finding{
+			Category:    "security",
+			Severity:    "medium",
+			Title:       "Tool Constraint Violations",
+			Description: fmt.Sprintf("The MCP gateway or safe outputs MCP server rejected %d tool call(s) that violated argument constraints, e.g. %s", len(processedRun.ConstraintViolations), formatConstraintViolation(processedRun.ConstraintViolations[0])),
+			Impact:      "The agent attempted tool calls outside the configured constraints",
+		})
+	}
+
 	// Missing tool findings
 	if len(processedRun.MissingTools) > 0 {
 		toolNames := make([]string, 0, min(3, len(processedRun.MissingTools)))
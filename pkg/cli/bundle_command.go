@@ -0,0 +1,318 @@
+// This file provides the bundle command for offline (air-gapped) compilation.
+//
+// `gh aw bundle export` compiles a set of workflows online and snapshots everything the
+// compiler fetched from the network into a tarball:
+//   - action pins (.github/aw/actions-lock.json and the GHES mirror pins, if present)
+//   - remote imports (.github/aw/imports/), with the ref to commit SHA mappings
+//   - the npm/pip/uv packages and container images the workflows use (manifest.json)
+//
+// `gh aw compile --offline --bundle <tar>` then compiles from the tarball without any
+// network access. JSON schemas are embedded in the binary, so the manifest records the
+// compiler version the bundle was exported with.
+
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/github/gh-aw/pkg/console"
+	"github.com/github/gh-aw/pkg/fileutil"
+	"github.com/github/gh-aw/pkg/logger"
+	"github.com/github/gh-aw/pkg/parser"
+	"github.com/github/gh-aw/pkg/workflow"
+	"github.com/spf13/cobra"
+)
+
+var bundleLog = logger.New("cli:bundle_command")
+
+// defaultBundleFileName is the default output file for bundle export
+const defaultBundleFileName = "gh-aw-bundle.tar.gz"
+
+// BundleExportConfig holds configuration for exporting an offline compilation bundle
+type BundleExportConfig struct {
+	WorkflowIDs []string // Workflows to bundle (empty for all workflows)
+	Output      string   // Path of the bundle tarball to write
+	WorkflowDir string   // Custom workflow directory
+	Verbose     bool
+}
+
+// NewBundleCommand creates the bundle command with subcommands
+func NewBundleCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "bundle",
+		Short: "Create bundles for offline workflow compilation",
+		Long: `Create bundles that allow workflows to be compiled without network access.
+
+Compilation normally reaches GitHub to resolve action SHAs and download remote imports,
+and (with --validate) checks npm/PyPI packages and container images. A bundle snapshots
+everything a set of workflows needs so that air-gapped environments can compile them with
+'gh aw compile --offline --bundle <file>'.
+
+Available subcommands:
+  • export - Snapshot the dependencies of workflows into a bundle tarball
+
+Examples:
+  gh aw bundle export                          # Bundle all workflows
+  gh aw bundle export ci-doctor -o bundle.tgz  # Bundle a specific workflow`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cmd.Help()
+		},
+	}
+
+	cmd.AddCommand(newBundleExportSubcommand())
+
+	return cmd
+}
+
+// newBundleExportSubcommand creates the bundle export subcommand
+func newBundleExportSubcommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "export [workflow]...",
+		Short: "Snapshot everything a set of workflows needs to compile offline",
+		Long: `Compiles the given workflows (all workflows by default) and writes a bundle tarball with
+the action pin cache, the remote import cache, and a manifest listing the packages and
+container images the workflows use.
+
+The bundle is verified by compiling the workflows from it without network access before
+it is written. Compile from the bundle with:
+
+  gh aw compile --offline --bundle gh-aw-bundle.tar.gz
+
+The manifest records the gh-aw version the bundle was exported with. Schemas are embedded
+in the binary, so use the same version to compile offline.
+
+Examples:
+  gh aw bundle export                              # Bundle all workflows
+  gh aw bundle export ci-doctor daily-plan         # Bundle specific workflows
+  gh aw bundle export --output /tmp/bundle.tar.gz  # Write the bundle to a custom path
+  gh aw bundle export --dir custom/workflows       # Bundle workflows from a custom directory`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			output, _ := cmd.Flags().GetString("output")
+			dir, _ := cmd.Flags().GetString("dir")
+			verbose, _ := cmd.Flags().GetBool("verbose")
+			return RunBundleExport(BundleExportConfig{
+				WorkflowIDs: args,
+				Output:      output,
+				WorkflowDir: dir,
+				Verbose:     verbose,
+			})
+		},
+	}
+
+	cmd.Flags().StringP("output", "o", defaultBundleFileName, "Path of the bundle tarball to write")
+	cmd.Flags().StringP("dir", "d", "", "Workflow directory (default: .github/workflows)")
+	cmd.ValidArgsFunction = CompleteWorkflowNames
+
+	return cmd
+}
+
+// RunBundleExport compiles the workflows and writes an offline compilation bundle
+func RunBundleExport(config BundleExportConfig) error {
+	bundleLog.Printf("Exporting bundle: workflows=%v, output=%s", config.WorkflowIDs, config.Output)
+
+	workflowFiles, err := resolveBundleWorkflowFiles(config)
+	if err != nil {
+		return err
+	}
+	if len(workflowFiles) == 0 {
+		return errors.New("no workflows found to bundle")
+	}
+
+	compileConfig := CompileConfig{Verbose: config.Verbose, NoEmit: true}
+	compiler := createAndConfigureCompiler(compileConfig)
+
+	manifest := &workflow.BundleManifest{
+		Version:         workflow.BundleFormatVersion,
+		CompilerVersion: GetVersion(),
+		CreatedAt:       time.Now().UTC().Format(time.RFC3339),
+	}
+	for _, file := range workflowFiles {
+		workflowData, err := compiler.ParseWorkflowFile(file)
+		if err != nil {
+			return err
+		}
+		if err := compiler.CompileWorkflowData(workflowData, file); err != nil {
+			return err
+		}
+		manifest.AddWorkflowRequirements(workflowData)
+		manifest.Workflows = append(manifest.Workflows, filepath.Base(file))
+	}
+
+	stagingDir, err := os.MkdirTemp("", "gh-aw-bundle-*")
+	if err != nil {
+		return fmt.Errorf("failed to create staging directory: %w", err)
+	}
+	defer os.RemoveAll(stagingDir)
+
+	if err := stageBundle(compiler, manifest, stagingDir); err != nil {
+		return err
+	}
+
+	// Make sure the bundle is complete before handing it to an air-gapped environment
+	if err := verifyBundle(compileConfig, manifest, stagingDir, workflowFiles); err != nil {
+		return fmt.Errorf("bundle verification failed: %w", err)
+	}
+
+	if err := writeBundleArchive(stagingDir, config.Output); err != nil {
+		return err
+	}
+
+	fmt.Fprintln(os.Stderr, console.FormatSuccessMessage(fmt.Sprintf("Exported bundle for %d workflow(s) to %s (%d action pins, %d remote imports)",
+		len(manifest.Workflows), config.Output, len(compiler.GetSharedActionCache().Entries), len(manifest.Imports))))
+	return nil
+}
+
+// resolveBundleWorkflowFiles returns the workflow files to bundle
+func resolveBundleWorkflowFiles(config BundleExportConfig) ([]string, error) {
+	if len(config.WorkflowIDs) == 0 {
+		return getMarkdownWorkflowFiles(config.WorkflowDir)
+	}
+	var files []string
+	for _, id := range config.WorkflowIDs {
+		file, err := resolveWorkflowFileInDir(id, config.Verbose, config.WorkflowDir)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, file)
+	}
+	return files, nil
+}
+
+// stageBundle writes the action pins, remote imports and manifest to the staging directory
+func stageBundle(compiler *workflow.Compiler, manifest *workflow.BundleManifest, stagingDir string) error {
+	// Action pins resolved while compiling, including the ones already in the repository cache
+	actionCache := workflow.NewActionCache(stagingDir)
+	for _, entry := range compiler.GetSharedActionCache().Entries {
+		actionCache.Set(entry.Repo, entry.Version, entry.SHA)
+	}
+	if err := actionCache.Save(); err != nil {
+		return fmt.Errorf("failed to write action pins to bundle: %w", err)
+	}
+
+	// GHES mirror pins, so bundles can also be used with --target
+	if gitRoot, err := findGitRoot(); err == nil {
+		ghesCache := workflow.NewActionCacheWithFile(gitRoot, workflow.GHESCacheFileName)
+		if err := ghesCache.Load(); err == nil && len(ghesCache.Entries) > 0 {
+			bundleLog.Printf("Including %d GHES mirror pins", len(ghesCache.Entries))
+			stagedGHESCache := workflow.NewActionCacheWithFile(stagingDir, workflow.GHESCacheFileName)
+			for _, entry := range ghesCache.Entries {
+				stagedGHESCache.Set(entry.Repo, entry.Version, entry.SHA)
+			}
+			if err := stagedGHESCache.Save(); err != nil {
+				return fmt.Errorf("failed to write GHES action pins to bundle: %w", err)
+			}
+		}
+	}
+
+	// Remote imports resolved while compiling
+	importCache := compiler.GetSharedImportCache()
+	stagedImports := parser.NewImportCache(stagingDir)
+	for _, ref := range importCache.ResolvedRefs() {
+		cachedPath, ok := importCache.Get(ref.Owner, ref.Repo, ref.Path, ref.SHA)
+		if !ok {
+			return fmt.Errorf("remote import %s/%s/%s@%s is missing from the import cache", ref.Owner, ref.Repo, ref.Path, ref.Ref)
+		}
+		content, err := os.ReadFile(cachedPath)
+		if err != nil {
+			return fmt.Errorf("failed to read cached import %s: %w", cachedPath, err)
+		}
+		if _, err := stagedImports.Set(ref.Owner, ref.Repo, ref.Path, ref.SHA, content); err != nil {
+			return fmt.Errorf("failed to add remote import %s/%s/%s to bundle: %w", ref.Owner, ref.Repo, ref.Path, err)
+		}
+		manifest.Imports = append(manifest.Imports, ref)
+	}
+
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal bundle manifest: %w", err)
+	}
+	return os.WriteFile(filepath.Join(stagingDir, workflow.BundleManifestFileName), append(data, '\n'), 0644)
+}
+
+// verifyBundle compiles the workflows from the staged bundle without network access
+func verifyBundle(compileConfig CompileConfig, manifest *workflow.BundleManifest, bundleDir string, workflowFiles []string) error {
+	bundleLog.Printf("Verifying bundle by compiling %d workflows offline", len(workflowFiles))
+	compiler := createAndConfigureCompiler(compileConfig)
+	compiler.SetOfflineBundle(manifest, bundleDir)
+	for _, file := range workflowFiles {
+		workflowData, err := compiler.ParseWorkflowFile(file)
+		if err != nil {
+			return err
+		}
+		if err := compiler.CompileWorkflowData(workflowData, file); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeBundleArchive writes the staged bundle to a tar.gz file
+func writeBundleArchive(stagingDir, output string) error {
+	file, err := os.Create(output)
+	if err != nil {
+		return fmt.Errorf("failed to create bundle file: %w", err)
+	}
+	if err := fileutil.WriteTarGz(file, stagingDir); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to write bundle: %w", err)
+	}
+	return file.Close()
+}
+
+// loadOfflineBundle extracts a bundle tarball to a temporary directory and reads its manifest.
+// The caller is responsible for removing the returned directory.
+func loadOfflineBundle(bundlePath string) (*workflow.BundleManifest, string, error) {
+	bundleLog.Printf("Loading offline bundle: %s", bundlePath)
+	file, err := os.Open(bundlePath)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to open bundle: %w", err)
+	}
+	defer file.Close()
+
+	bundleDir, err := os.MkdirTemp("", "gh-aw-bundle-*")
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to create bundle directory: %w", err)
+	}
+	if err := fileutil.ExtractTarGz(file, bundleDir); err != nil {
+		os.RemoveAll(bundleDir)
+		return nil, "", fmt.Errorf("failed to extract bundle %s: %w", bundlePath, err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(bundleDir, workflow.BundleManifestFileName))
+	if err != nil {
+		os.RemoveAll(bundleDir)
+		return nil, "", fmt.Errorf("bundle %s has no %s: %w", bundlePath, workflow.BundleManifestFileName, err)
+	}
+	var manifest workflow.BundleManifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		os.RemoveAll(bundleDir)
+		return nil, "", fmt.Errorf("failed to parse bundle manifest: %w", err)
+	}
+	if manifest.Version != workflow.BundleFormatVersion {
+		os.RemoveAll(bundleDir)
+		return nil, "", fmt.Errorf("unsupported bundle format version %d (expected %d). Re-export the bundle with this version of gh-aw", manifest.Version, workflow.BundleFormatVersion)
+	}
+
+	if manifest.CompilerVersion != GetVersion() {
+		fmt.Fprintln(os.Stderr, console.FormatWarningMessage(fmt.Sprintf("Bundle was exported with gh-aw %s but this is %s; compiled output may differ",
+			manifest.CompilerVersion, GetVersion())))
+	}
+
+	return &manifest, bundleDir, nil
+}
+
+// setupOfflineBundle configures the compiler to compile from a bundle tarball.
+// It returns the extracted bundle directory, which the caller must remove.
+func setupOfflineBundle(compiler *workflow.Compiler, bundlePath string) (string, error) {
+	manifest, bundleDir, err := loadOfflineBundle(bundlePath)
+	if err != nil {
+		return "", err
+	}
+	compiler.SetOfflineBundle(manifest, bundleDir)
+	return bundleDir, nil
+}
@@ -0,0 +1,117 @@
+//go:build !integration
+
+package cli
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/github/gh-aw/pkg/parser"
+	"github.com/github/gh-aw/pkg/workflow"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeTestBundle writes a bundle tarball with the given manifest and returns its path
+func writeTestBundle(t *testing.T, manifest workflow.BundleManifest) string {
+	t.Helper()
+	stagingDir := t.TempDir()
+
+	cache := workflow.NewActionCache(stagingDir)
+	cache.Set("actions/checkout", "v5", "1111111111111111111111111111111111111111")
+	require.NoError(t, cache.Save())
+
+	data, err := json.Marshal(manifest)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(stagingDir, workflow.BundleManifestFileName), data, 0644))
+
+	bundlePath := filepath.Join(t.TempDir(), "bundle.tar.gz")
+	require.NoError(t, writeBundleArchive(stagingDir, bundlePath))
+	return bundlePath
+}
+
+func TestLoadOfflineBundle(t *testing.T) {
+	bundlePath := writeTestBundle(t, workflow.BundleManifest{
+		Version:         workflow.BundleFormatVersion,
+		CompilerVersion: GetVersion(),
+		Workflows:       []string{"triage.md"},
+		Imports:         []parser.ImportRef{{Owner: "githubnext", Repo: "agentics", Path: "shared/mcp.md", Ref: "v1", SHA: "abc"}},
+	})
+
+	manifest, bundleDir, err := loadOfflineBundle(bundlePath)
+	require.NoError(t, err)
+	defer os.RemoveAll(bundleDir)
+
+	assert.Equal(t, []string{"triage.md"}, manifest.Workflows)
+	assert.Len(t, manifest.Imports, 1)
+
+	cache := workflow.NewActionCache(bundleDir)
+	require.NoError(t, cache.Load())
+	sha, ok := cache.Get("actions/checkout", "v5")
+	assert.True(t, ok, "action pins should be extracted from the bundle")
+	assert.Equal(t, "1111111111111111111111111111111111111111", sha)
+}
+
+func TestLoadOfflineBundle_Errors(t *testing.T) {
+	t.Run("missing file", func(t *testing.T) {
+		_, _, err := loadOfflineBundle(filepath.Join(t.TempDir(), "missing.tar.gz"))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to open bundle")
+	})
+
+	t.Run("not a tarball", func(t *testing.T) {
+		bundlePath := filepath.Join(t.TempDir(), "bundle.tar.gz")
+		require.NoError(t, os.WriteFile(bundlePath, []byte("not a bundle"), 0644))
+		_, _, err := loadOfflineBundle(bundlePath)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to extract bundle")
+	})
+
+	t.Run("unsupported version", func(t *testing.T) {
+		bundlePath := writeTestBundle(t, workflow.BundleManifest{Version: workflow.BundleFormatVersion + 1})
+		_, _, err := loadOfflineBundle(bundlePath)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "unsupported bundle format version")
+	})
+}
+
+func TestValidateCompileConfig_Offline(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  CompileConfig
+		wantErr string
+	}{
+		{name: "offline with bundle", config: CompileConfig{Offline: true, Bundle: "bundle.tar.gz"}},
+		{name: "offline without bundle", config: CompileConfig{Offline: true}, wantErr: "--offline requires --bundle"},
+		{name: "bundle without offline", config: CompileConfig{Bundle: "bundle.tar.gz"}, wantErr: "--bundle can only be used with --offline"},
+		{name: "offline with zizmor", config: CompileConfig{Offline: true, Bundle: "bundle.tar.gz", Zizmor: true}, wantErr: "--offline cannot be used with --zizmor"},
+		{name: "offline with dependabot", config: CompileConfig{Offline: true, Bundle: "bundle.tar.gz", Dependabot: true}, wantErr: "--offline cannot be used with --dependabot"},
+		{name: "offline with force refresh", config: CompileConfig{Offline: true, Bundle: "bundle.tar.gz", ForceRefreshActionPins: true}, wantErr: "--force-refresh-action-pins"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateCompileConfig(tt.config)
+			if tt.wantErr == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestNewBundleCommand(t *testing.T) {
+	cmd := NewBundleCommand()
+	assert.Equal(t, "bundle", cmd.Use)
+
+	exportCmd, _, err := cmd.Find([]string{"export"})
+	require.NoError(t, err)
+	assert.Equal(t, "export [workflow]...", exportCmd.Use)
+	output := exportCmd.Flags().Lookup("output")
+	require.NotNil(t, output)
+	assert.Equal(t, defaultBundleFileName, output.DefValue)
+}
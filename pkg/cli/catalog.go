@@ -0,0 +1,828 @@
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/github/gh-aw/pkg/console"
+	"github.com/github/gh-aw/pkg/logger"
+	"github.com/github/gh-aw/pkg/parser"
+	"github.com/github/gh-aw/pkg/tty"
+	"github.com/goccy/go-yaml"
+)
+
+var catalogLog = logger.New("cli:catalog")
+
+// catalogConfigPath is the file that lists the repositories indexed by 'gh aw catalog'
+var catalogConfigPath = filepath.Join(".github", "aw", "catalog.json")
+
+// defaultCatalogSourcePath is the directory searched for shared components when a source has no path
+const defaultCatalogSourcePath = ".github/workflows/shared"
+
+// catalogIndexFileName is the file in the user cache directory that stores the indexed
+// components of each catalog source, keyed by the commit they were indexed at
+const catalogIndexFileName = "catalog-index.json"
+
+var (
+	// listCatalogFilesFunc, downloadCatalogFileFunc, resolveCatalogDefaultBranchFunc and
+	// resolveCatalogCommitFunc allow overriding GitHub access in tests
+	listCatalogFilesFunc            = parser.ListWorkflowFiles
+	downloadCatalogFileFunc         = parser.DownloadFileFromGitHub
+	resolveCatalogDefaultBranchFunc = getRepoDefaultBranch
+	resolveCatalogCommitFunc        = parser.ResolveRefToSHA
+
+	// catalogIndexPathFunc returns the path of the persisted catalog index
+	catalogIndexPathFunc = defaultCatalogIndexPath
+
+	// promptCatalogInputFunc allows overriding interactive input prompts in tests
+	promptCatalogInputFunc = console.PromptInputWithValidation
+)
+
+// CatalogConfig is the content of .github/aw/catalog.json
+type CatalogConfig struct {
+	Sources []CatalogSource `json:"sources"`
+}
+
+// CatalogSource is a repository directory that publishes shared workflow components
+type CatalogSource struct {
+	Repo string `json:"repo" console:"header:Repository"`
+	Ref  string `json:"ref,omitempty" console:"header:Ref"`
+	Path string `json:"path,omitempty" console:"header:Path"`
+}
+
+// GetPath returns the directory to index, defaulting to .github/workflows/shared
+func (s CatalogSource) GetPath() string {
+	if s.Path == "" {
+		return defaultCatalogSourcePath
+	}
+	return s.Path
+}
+
+// String formats the source as owner/repo/path[@ref]
+func (s CatalogSource) String() string {
+	if s.Ref == "" {
+		return s.Repo + "/" + s.GetPath()
+	}
+	return fmt.Sprintf("%s/%s@%s", s.Repo, s.GetPath(), s.Ref)
+}
+
+// CatalogComponent describes a shared workflow component published by a catalog source
+type CatalogComponent struct {
+	Spec        string                                   `json:"spec" console:"header:Component"`
+	Description string                                   `json:"description,omitempty" console:"header:Description,omitempty,maxlen:50"`
+	Tools       []string                                 `json:"tools,omitempty" console:"header:Tools,omitempty"`
+	SafeOutputs []string                                 `json:"safe_outputs,omitempty" console:"header:Safe Outputs,omitempty"`
+	InputCount  int                                      `json:"-" console:"header:Inputs"`
+	Repo        string                                   `json:"repo" console:"-"`
+	Path        string                                   `json:"path" console:"-"`
+	Ref         string                                   `json:"ref" console:"-"`
+	Permissions []string                                 `json:"permissions,omitempty" console:"-"`
+	Network     []string                                 `json:"network,omitempty" console:"-"`
+	Inputs      map[string]*parser.ImportInputDefinition `json:"inputs,omitempty" console:"-"`
+}
+
+// Name returns the file name of the component without the .md extension
+func (c *CatalogComponent) Name() string {
+	return strings.TrimSuffix(path.Base(c.Path), ".md")
+}
+
+// CatalogSearchResult is a component that matched a catalog search
+type CatalogSearchResult struct {
+	*CatalogComponent
+	Matches []string `json:"matches" console:"header:Matches"`
+}
+
+// loadCatalogConfig reads the catalog configuration, returning an empty configuration when it does not exist
+func loadCatalogConfig() (*CatalogConfig, error) {
+	data, err := os.ReadFile(catalogConfigPath)
+	if os.IsNotExist(err) {
+		return &CatalogConfig{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", catalogConfigPath, err)
+	}
+
+	var config CatalogConfig
+	if err := json.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", catalogConfigPath, err)
+	}
+	catalogLog.Printf("Loaded %d catalog sources", len(config.Sources))
+	return &config, nil
+}
+
+// saveCatalogConfig writes the catalog configuration
+func saveCatalogConfig(config *CatalogConfig) error {
+	if err := os.MkdirAll(filepath.Dir(catalogConfigPath), 0755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", filepath.Dir(catalogConfigPath), err)
+	}
+	data, err := json.MarshalIndent(config, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal catalog configuration: %w", err)
+	}
+	if err := os.WriteFile(catalogConfigPath, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", catalogConfigPath, err)
+	}
+	return nil
+}
+
+// parseCatalogSource parses an owner/repo[@ref] source specification
+func parseCatalogSource(spec, sourcePath string) (CatalogSource, error) {
+	repoSpec, err := parseRepoSpec(spec)
+	if err != nil {
+		return CatalogSource{}, err
+	}
+	return CatalogSource{
+		Repo: repoSpec.RepoSlug,
+		Ref:  repoSpec.Version,
+		Path: strings.Trim(sourcePath, "/"),
+	}, nil
+}
+
+// catalogSources returns the sources to index: the given specs when set, otherwise the configured sources
+func catalogSources(sourceSpecs []string) ([]CatalogSource, error) {
+	if len(sourceSpecs) > 0 {
+		sources := make([]CatalogSource, 0, len(sourceSpecs))
+		for _, spec := range sourceSpecs {
+			source, err := parseCatalogSource(spec, "")
+			if err != nil {
+				return nil, fmt.Errorf("invalid source '%s': %w", spec, err)
+			}
+			sources = append(sources, source)
+		}
+		return sources, nil
+	}
+
+	config, err := loadCatalogConfig()
+	if err != nil {
+		return nil, err
+	}
+	if len(config.Sources) == 0 {
+		return nil, errors.New("no catalog sources configured; add one with 'gh aw catalog add-source owner/repo' or pass --source owner/repo")
+	}
+	return config.Sources, nil
+}
+
+// AddCatalogSource adds a repository to the catalog configuration
+func AddCatalogSource(spec, sourcePath string, verbose bool) error {
+	source, err := parseCatalogSource(spec, sourcePath)
+	if err != nil {
+		return err
+	}
+
+	config, err := loadCatalogConfig()
+	if err != nil {
+		return err
+	}
+	for i, existing := range config.Sources {
+		if existing.Repo == source.Repo && existing.GetPath() == source.GetPath() {
+			config.Sources[i] = source
+			if err := saveCatalogConfig(config); err != nil {
+				return err
+			}
+			fmt.Fprintln(os.Stderr, console.FormatSuccessMessage("Updated catalog source "+source.String()))
+			return nil
+		}
+	}
+
+	config.Sources = append(config.Sources, source)
+	if err := saveCatalogConfig(config); err != nil {
+		return err
+	}
+	fmt.Fprintln(os.Stderr, console.FormatSuccessMessage("Added catalog source "+source.String()))
+	if verbose {
+		fmt.Fprintln(os.Stderr, console.FormatVerboseMessage("Catalog sources are stored in "+catalogConfigPath))
+	}
+	return nil
+}
+
+// RemoveCatalogSource removes a repository from the catalog configuration
+func RemoveCatalogSource(repo string) error {
+	config, err := loadCatalogConfig()
+	if err != nil {
+		return err
+	}
+
+	remaining := slices.DeleteFunc(slices.Clone(config.Sources), func(source CatalogSource) bool {
+		return source.Repo == repo
+	})
+	if len(remaining) == len(config.Sources) {
+		return fmt.Errorf("catalog source '%s' not found in %s", repo, catalogConfigPath)
+	}
+
+	config.Sources = remaining
+	if err := saveCatalogConfig(config); err != nil {
+		return err
+	}
+	fmt.Fprintln(os.Stderr, console.FormatSuccessMessage("Removed catalog source "+repo))
+	return nil
+}
+
+// ListCatalogSources prints the configured catalog sources
+func ListCatalogSources(jsonOutput bool) error {
+	config, err := loadCatalogConfig()
+	if err != nil {
+		return err
+	}
+
+	if jsonOutput {
+		sources := config.Sources
+		if sources == nil {
+			sources = []CatalogSource{}
+		}
+		jsonBytes, err := json.MarshalIndent(sources, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON: %w", err)
+		}
+		fmt.Println(string(jsonBytes))
+		return nil
+	}
+
+	if len(config.Sources) == 0 {
+		fmt.Fprintln(os.Stderr, console.FormatInfoMessage("No catalog sources configured. Add one with 'gh aw catalog add-source owner/repo'."))
+		return nil
+	}
+	rows := make([]CatalogSource, len(config.Sources))
+	for i, source := range config.Sources {
+		ref := source.Ref
+		if ref == "" {
+			ref = "(default branch)"
+		}
+		rows[i] = CatalogSource{Repo: source.Repo, Ref: ref, Path: source.GetPath()}
+	}
+	fmt.Fprint(os.Stderr, console.RenderStruct(rows))
+	return nil
+}
+
+// catalogIndex is the persisted index of the catalog sources
+type catalogIndex struct {
+	Sources map[string]*catalogIndexEntry `json:"sources"`
+}
+
+// catalogIndexEntry holds the components of a catalog source indexed at a commit
+type catalogIndexEntry struct {
+	Ref        string              `json:"ref"`
+	SHA        string              `json:"sha"`
+	Components []*CatalogComponent `json:"components"`
+}
+
+// defaultCatalogIndexPath returns the catalog index path in the user cache directory
+func defaultCatalogIndexPath() (string, error) {
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(cacheDir, "gh-aw", catalogIndexFileName), nil
+}
+
+// loadCatalogIndex reads the persisted catalog index. A missing or unreadable index is
+// treated as empty, since it only avoids downloads.
+func loadCatalogIndex() *catalogIndex {
+	index := &catalogIndex{Sources: make(map[string]*catalogIndexEntry)}
+	indexPath, err := catalogIndexPathFunc()
+	if err != nil {
+		catalogLog.Printf("No catalog index path: %v", err)
+		return index
+	}
+	data, err := os.ReadFile(indexPath)
+	if err != nil {
+		return index
+	}
+	if err := json.Unmarshal(data, index); err != nil || index.Sources == nil {
+		catalogLog.Printf("Ignoring invalid catalog index %s: %v", indexPath, err)
+		return &catalogIndex{Sources: make(map[string]*catalogIndexEntry)}
+	}
+	for _, entry := range index.Sources {
+		for _, component := range entry.Components {
+			component.InputCount = len(component.Inputs)
+		}
+	}
+	return index
+}
+
+// saveCatalogIndex writes the persisted catalog index
+func saveCatalogIndex(index *catalogIndex) error {
+	indexPath, err := catalogIndexPathFunc()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(indexPath), 0755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", filepath.Dir(indexPath), err)
+	}
+	data, err := json.Marshal(index)
+	if err != nil {
+		return fmt.Errorf("failed to marshal catalog index: %w", err)
+	}
+	if err := os.WriteFile(indexPath, data, 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", indexPath, err)
+	}
+	return nil
+}
+
+// resolveCatalogRef returns the ref to index for a repository: the given ref, or the
+// default branch of the repository when no ref is set
+func resolveCatalogRef(repoSlug, ref string) (string, error) {
+	if ref != "" {
+		return ref, nil
+	}
+	branch, err := resolveCatalogDefaultBranchFunc(repoSlug)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve the default branch of %s: %w", repoSlug, err)
+	}
+	return branch, nil
+}
+
+// indexCatalog lists and parses the shared components published by the given sources.
+// Files with an 'on' trigger are complete workflows rather than components and are skipped.
+// Components are stored in a persisted index keyed by commit, so a source is only downloaded
+// again when its ref moved or refresh is set.
+func indexCatalog(sources []CatalogSource, refresh bool, verbose bool) ([]*CatalogComponent, error) {
+	index := loadCatalogIndex()
+	indexChanged := false
+
+	var components []*CatalogComponent
+	for _, source := range sources {
+		owner, repo, _ := strings.Cut(source.Repo, "/")
+		ref, err := resolveCatalogRef(source.Repo, source.Ref)
+		if err != nil {
+			return nil, fmt.Errorf("failed to index catalog source %s: %w", source.String(), err)
+		}
+		sha, err := resolveCatalogCommitFunc(owner, repo, ref)
+		if err != nil {
+			return nil, fmt.Errorf("failed to index catalog source %s: %w", source.String(), err)
+		}
+
+		key := source.String()
+		if entry, ok := index.Sources[key]; ok && !refresh && entry.Ref == ref && entry.SHA == sha {
+			catalogLog.Printf("Using indexed components of %s at %s", key, sha)
+			components = append(components, entry.Components...)
+			continue
+		}
+
+		if verbose {
+			fmt.Fprintln(os.Stderr, console.FormatVerboseMessage(fmt.Sprintf("Indexing %s/%s@%s", source.Repo, source.GetPath(), ref)))
+		}
+		sourceComponents, err := indexCatalogSource(source, owner, repo, ref, sha, verbose)
+		if err != nil {
+			return nil, err
+		}
+		index.Sources[key] = &catalogIndexEntry{Ref: ref, SHA: sha, Components: sourceComponents}
+		indexChanged = true
+		components = append(components, sourceComponents...)
+	}
+
+	if indexChanged {
+		if err := saveCatalogIndex(index); err != nil && verbose {
+			fmt.Fprintln(os.Stderr, console.FormatWarningMessage("Failed to save the catalog index: "+err.Error()))
+		}
+	}
+
+	sort.Slice(components, func(i, j int) bool {
+		return components[i].Spec < components[j].Spec
+	})
+	catalogLog.Printf("Indexed %d components from %d sources", len(components), len(sources))
+	return components, nil
+}
+
+// indexCatalogSource downloads and parses the components of a single source at a commit
+func indexCatalogSource(source CatalogSource, owner, repo, ref, sha string, verbose bool) ([]*CatalogComponent, error) {
+	files, err := listCatalogFilesFunc(owner, repo, sha, source.GetPath())
+	if err != nil {
+		return nil, fmt.Errorf("failed to index catalog source %s: %w", source.String(), err)
+	}
+
+	var components []*CatalogComponent
+	for _, file := range files {
+		content, err := downloadCatalogFileFunc(owner, repo, file, sha)
+		if err != nil {
+			return nil, fmt.Errorf("failed to download %s/%s@%s: %w", source.Repo, file, ref, err)
+		}
+		component, err := parseCatalogComponent(source.Repo, file, ref, content)
+		if err != nil {
+			if verbose {
+				fmt.Fprintln(os.Stderr, console.FormatWarningMessage(err.Error()))
+			}
+			continue
+		}
+		if component != nil {
+			components = append(components, component)
+		}
+	}
+	return components, nil
+}
+
+// parseCatalogComponent extracts the capabilities of a shared component from its frontmatter.
+// Returns nil when the file is a complete workflow.
+func parseCatalogComponent(repo, filePath, ref string, content []byte) (*CatalogComponent, error) {
+	result, err := parser.ExtractFrontmatterFromContent(string(content))
+	if err != nil {
+		return nil, fmt.Errorf("skipping %s/%s: %w", repo, filePath, err)
+	}
+	frontmatter := result.Frontmatter
+	if _, hasOn := frontmatter["on"]; hasOn {
+		return nil, nil
+	}
+
+	inputs, err := parser.ParseImportInputDefinitions(frontmatter)
+	if err != nil {
+		return nil, fmt.Errorf("skipping %s/%s: %w", repo, filePath, err)
+	}
+
+	description, _ := frontmatter["description"].(string)
+	component := &CatalogComponent{
+		Spec:        fmt.Sprintf("%s/%s@%s", repo, filePath, ref),
+		Description: description,
+		Repo:        repo,
+		Path:        filePath,
+		Ref:         ref,
+		Inputs:      inputs,
+		InputCount:  len(inputs),
+	}
+
+	tools := sortedMapKeys(frontmatter["tools"])
+	for _, server := range sortedMapKeys(frontmatter["mcp-servers"]) {
+		if !slices.Contains(tools, server) {
+			tools = append(tools, server)
+		}
+	}
+	component.Tools = tools
+
+	if safeOutputs, ok := frontmatter["safe-outputs"].(map[string]any); ok {
+		typeKeys, err := parser.GetSafeOutputTypeKeys()
+		if err != nil {
+			return nil, err
+		}
+		for _, key := range sortedMapKeys(safeOutputs) {
+			if slices.Contains(typeKeys, key) {
+				component.SafeOutputs = append(component.SafeOutputs, key)
+			}
+		}
+	}
+
+	switch permissions := frontmatter["permissions"].(type) {
+	case string:
+		component.Permissions = []string{permissions}
+	case map[string]any:
+		for _, scope := range sortedMapKeys(permissions) {
+			component.Permissions = append(component.Permissions, fmt.Sprintf("%s: %v", scope, permissions[scope]))
+		}
+	}
+
+	switch network := frontmatter["network"].(type) {
+	case string:
+		component.Network = []string{network}
+	case map[string]any:
+		if allowed, ok := network["allowed"].([]any); ok {
+			for _, entry := range allowed {
+				component.Network = append(component.Network, fmt.Sprint(entry))
+			}
+		}
+	}
+
+	return component, nil
+}
+
+// sortedMapKeys returns the sorted keys of a frontmatter object, or nil when the value is not an object
+func sortedMapKeys(value any) []string {
+	object, ok := value.(map[string]any)
+	if !ok {
+		return nil
+	}
+	keys := make([]string, 0, len(object))
+	for key := range object {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// searchCatalog returns the components matching every term of the query. Terms are matched
+// case-insensitively against the name, description, tools, safe outputs, network, permissions
+// and inputs of each component.
+func searchCatalog(components []*CatalogComponent, query string) []CatalogSearchResult {
+	terms := strings.Fields(strings.ToLower(query))
+	var results []CatalogSearchResult
+	for _, component := range components {
+		var matches []string
+		matchedAll := true
+		for _, term := range terms {
+			termMatches := matchCatalogTerm(component, term)
+			if len(termMatches) == 0 {
+				matchedAll = false
+				break
+			}
+			for _, match := range termMatches {
+				if !slices.Contains(matches, match) {
+					matches = append(matches, match)
+				}
+			}
+		}
+		if matchedAll {
+			results = append(results, CatalogSearchResult{CatalogComponent: component, Matches: matches})
+		}
+	}
+	catalogLog.Printf("Search %q matched %d of %d components", query, len(results), len(components))
+	return results
+}
+
+// matchCatalogTerm returns the capabilities of a component that contain the term, as kind:value pairs
+func matchCatalogTerm(component *CatalogComponent, term string) []string {
+	capabilities := []struct {
+		kind   string
+		values []string
+	}{
+		{"name", []string{component.Name()}},
+		{"tool", component.Tools},
+		{"safe-output", component.SafeOutputs},
+		{"network", component.Network},
+		{"permission", component.Permissions},
+		{"input", sortedImportInputNames(component.Inputs)},
+	}
+
+	var matches []string
+	for _, capability := range capabilities {
+		for _, value := range capability.values {
+			if strings.Contains(strings.ToLower(value), term) {
+				matches = append(matches, capability.kind+":"+value)
+			}
+		}
+	}
+	if len(matches) == 0 && strings.Contains(strings.ToLower(component.Description), term) {
+		matches = append(matches, "description")
+	}
+	return matches
+}
+
+// resolveCatalogComponent finds a component by full spec (owner/repo/path.md[@ref]), by name or by
+// repository path. Full specs are downloaded directly without indexing the configured sources.
+func resolveCatalogComponent(reference string, sourceSpecs []string, refresh bool, verbose bool) (*CatalogComponent, error) {
+	if strings.Count(reference, "/") >= 2 && strings.HasSuffix(strings.SplitN(reference, "@", 2)[0], ".md") {
+		spec, err := parseWorkflowSpec(reference)
+		if err != nil {
+			return nil, err
+		}
+		ref, err := resolveCatalogRef(spec.RepoSlug, spec.Version)
+		if err != nil {
+			return nil, err
+		}
+		owner, repo, _ := strings.Cut(spec.RepoSlug, "/")
+		content, err := downloadCatalogFileFunc(owner, repo, spec.WorkflowPath, ref)
+		if err != nil {
+			return nil, fmt.Errorf("failed to download %s: %w", reference, err)
+		}
+		component, err := parseCatalogComponent(spec.RepoSlug, spec.WorkflowPath, ref, content)
+		if err != nil {
+			return nil, err
+		}
+		if component == nil {
+			return nil, fmt.Errorf("%s is a workflow with an 'on' trigger, not a shared component; use 'gh aw add' to install it", reference)
+		}
+		return component, nil
+	}
+
+	sources, err := catalogSources(sourceSpecs)
+	if err != nil {
+		return nil, err
+	}
+	components, err := indexCatalog(sources, refresh, verbose)
+	if err != nil {
+		return nil, err
+	}
+
+	name := strings.TrimSuffix(reference, ".md")
+	var candidates []*CatalogComponent
+	for _, component := range components {
+		if component.Name() == name || strings.TrimSuffix(component.Path, ".md") == name {
+			candidates = append(candidates, component)
+		}
+	}
+	switch len(candidates) {
+	case 0:
+		return nil, fmt.Errorf("no catalog component named '%s'; run 'gh aw catalog search %s' to find related components", reference, reference)
+	case 1:
+		return candidates[0], nil
+	}
+	specs := make([]string, len(candidates))
+	for i, candidate := range candidates {
+		specs[i] = candidate.Spec
+	}
+	return nil, fmt.Errorf("component name '%s' is ambiguous; use one of: %s", reference, strings.Join(specs, ", "))
+}
+
+// catalogInputRow is a row of the inputs table shown by 'gh aw catalog show'
+type catalogInputRow struct {
+	Input       string `console:"header:Input"`
+	Type        string `console:"header:Type"`
+	Required    string `console:"header:Required"`
+	Default     string `console:"header:Default"`
+	Constraints string `console:"header:Constraints"`
+	Description string `console:"header:Description,maxlen:50"`
+}
+
+// renderCatalogComponent formats the details of a component for 'gh aw catalog show'
+func renderCatalogComponent(component *CatalogComponent) string {
+	var output strings.Builder
+	output.WriteString(console.FormatInfoMessage(component.Spec) + "\n")
+	if component.Description != "" {
+		output.WriteString(component.Description + "\n")
+	}
+	output.WriteString("\n")
+
+	details := []struct {
+		label  string
+		values []string
+	}{
+		{"Tools", component.Tools},
+		{"Safe outputs", component.SafeOutputs},
+		{"Permissions", component.Permissions},
+		{"Network", component.Network},
+	}
+	for _, detail := range details {
+		value := "none"
+		if len(detail.values) > 0 {
+			value = strings.Join(detail.values, ", ")
+		}
+		fmt.Fprintf(&output, "%-13s %s\n", detail.label+":", value)
+	}
+
+	if len(component.Inputs) == 0 {
+		output.WriteString("\nThis component has no inputs.\n")
+	} else {
+		var rows []catalogInputRow
+		for _, row := range flattenImportInputs("", component.Inputs) {
+			required := "no"
+			if row.definition.Required {
+				required = "yes"
+			}
+			rows = append(rows, catalogInputRow{
+				Input:       row.name,
+				Type:        formatImportInputType(row.definition),
+				Required:    required,
+				Default:     strings.Trim(formatImportInputDefault(row.definition), "`"),
+				Constraints: strings.ReplaceAll(formatImportInputConstraints(row.definition), "`", ""),
+				Description: row.definition.Description,
+			})
+		}
+		output.WriteString("\n" + console.RenderStruct(rows))
+	}
+	return output.String()
+}
+
+// parseCatalogInputFlags parses --input name=value flags. Values are parsed as YAML so numbers,
+// booleans, arrays ([a, b]) and objects ({k: v}) can be passed.
+func parseCatalogInputFlags(flags []string) (map[string]any, error) {
+	values := make(map[string]any)
+	for _, flag := range flags {
+		name, raw, ok := strings.Cut(flag, "=")
+		if !ok || strings.TrimSpace(name) == "" {
+			return nil, fmt.Errorf("invalid --input '%s': expected name=value", flag)
+		}
+		value, err := parseCatalogInputValue(raw)
+		if err != nil {
+			return nil, fmt.Errorf("invalid --input '%s': %w", flag, err)
+		}
+		values[strings.TrimSpace(name)] = value
+	}
+	return values, nil
+}
+
+// parseCatalogInputValue parses a single input value as YAML. Values that do not parse to a
+// number, boolean, array or object (such as "#general") are kept as the raw string.
+func parseCatalogInputValue(raw string) (any, error) {
+	trimmed := strings.TrimSpace(raw)
+	if trimmed == "" {
+		return raw, nil
+	}
+	var value any
+	if err := yaml.Unmarshal([]byte(trimmed), &value); err != nil {
+		if strings.HasPrefix(trimmed, "[") || strings.HasPrefix(trimmed, "{") {
+			return nil, err
+		}
+		return raw, nil
+	}
+	if _, isString := value.(string); isString || value == nil {
+		return raw, nil
+	}
+	return value, nil
+}
+
+// promptCatalogInputs asks for the required inputs without a default that were not passed with --input
+func promptCatalogInputs(component *CatalogComponent, values map[string]any) error {
+	var missing []string
+	for _, name := range sortedImportInputNames(component.Inputs) {
+		definition := component.Inputs[name]
+		if _, ok := values[name]; !ok && definition.Required && definition.Default == nil {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+	if !tty.IsStderrTerminal() {
+		return fmt.Errorf("missing required input(s) for %s: %s; pass them with --input name=value", component.Spec, strings.Join(missing, ", "))
+	}
+
+	for _, name := range missing {
+		definition := component.Inputs[name]
+		description := definition.Description
+		if constraints := formatImportInputConstraints(definition); constraints != "-" {
+			description = strings.TrimSpace(description + " (" + strings.ReplaceAll(constraints, "`", "") + ")")
+		}
+		singleInput := map[string]*parser.ImportInputDefinition{name: definition}
+		raw, err := promptCatalogInputFunc(
+			fmt.Sprintf("%s (%s)", name, formatImportInputType(definition)),
+			description,
+			importInputPlaceholder(definition),
+			func(raw string) error {
+				value, err := parseCatalogInputValue(raw)
+				if err != nil {
+					return err
+				}
+				_, err = parser.ResolveImportInputs(singleInput, map[string]any{name: value})
+				return err
+			})
+		if err != nil {
+			return fmt.Errorf("failed to read input '%s': %w", name, err)
+		}
+		if values[name], err = parseCatalogInputValue(raw); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// AddCatalogComponent adds a catalog component as an import of a local workflow, prompting for
+// required inputs that were not passed on the command line
+func AddCatalogComponent(reference, workflowFile string, inputFlags []string, sourceSpecs []string, refresh bool, verbose bool) error {
+	catalogLog.Printf("Adding catalog component %s to %s", reference, workflowFile)
+
+	workflowPath, err := ResolveWorkflowPath(workflowFile)
+	if err != nil {
+		return err
+	}
+	values, err := parseCatalogInputFlags(inputFlags)
+	if err != nil {
+		return err
+	}
+	component, err := resolveCatalogComponent(reference, sourceSpecs, refresh, verbose)
+	if err != nil {
+		return err
+	}
+
+	if component.Inputs != nil {
+		if err := promptCatalogInputs(component, values); err != nil {
+			return err
+		}
+		if _, err := parser.ResolveImportInputs(component.Inputs, values); err != nil {
+			return fmt.Errorf("invalid inputs for %s: %w", component.Spec, err)
+		}
+	} else if len(values) > 0 {
+		fmt.Fprintln(os.Stderr, console.FormatWarningMessage(component.Spec+" does not declare inputs; passing them unvalidated"))
+	}
+
+	err = parser.UpdateWorkflowFrontmatter(workflowPath, func(frontmatter map[string]any) error {
+		return addCatalogImport(frontmatter, component, values)
+	}, verbose)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintln(os.Stderr, console.FormatSuccessMessage(fmt.Sprintf("Added import %s to %s", component.Spec, console.ToRelativePath(workflowPath))))
+	if len(component.Permissions) > 0 {
+		fmt.Fprintln(os.Stderr, console.FormatInfoMessage("The component requires these permissions, which the workflow must declare: "+strings.Join(component.Permissions, ", ")))
+	}
+	fmt.Fprintln(os.Stderr, console.FormatInfoMessage("Run 'gh aw compile' to update the lock file"))
+	return nil
+}
+
+// addCatalogImport appends a component to the imports of a workflow's frontmatter.
+// Returns an error when the component is already imported at any ref.
+func addCatalogImport(frontmatter map[string]any, component *CatalogComponent, values map[string]any) error {
+	imports, _ := frontmatter["imports"].([]any)
+	componentPath := component.Repo + "/" + component.Path
+	for _, existing := range imports {
+		var existingPath string
+		switch v := existing.(type) {
+		case string:
+			existingPath = v
+		case map[string]any:
+			existingPath, _ = v["path"].(string)
+		}
+		if strings.SplitN(existingPath, "@", 2)[0] == componentPath {
+			return fmt.Errorf("workflow already imports %s", existingPath)
+		}
+	}
+
+	var entry any = component.Spec
+	if len(values) > 0 {
+		entry = map[string]any{"path": component.Spec, "inputs": values}
+	}
+	frontmatter["imports"] = append(imports, entry)
+	return nil
+}
@@ -0,0 +1,299 @@
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/github/gh-aw/pkg/console"
+	"github.com/github/gh-aw/pkg/logger"
+	"github.com/spf13/cobra"
+)
+
+var catalogCommandLog = logger.New("cli:catalog_command")
+
+// NewCatalogCommand creates the main catalog command with subcommands
+func NewCatalogCommand() *cobra.Command {
+	catalogCommandLog.Print("Creating catalog command with subcommands")
+	cmd := &cobra.Command{
+		Use:   "catalog",
+		Short: "Discover shared workflow components and add them as imports",
+		Long: `Discover shared workflow components published in other repositories and add them as imports.
+
+Shared components are markdown files without an 'on' trigger that provide tools, MCP servers,
+safe outputs and instructions to the workflows that import them. The catalog indexes the
+components of the source repositories listed in .github/aw/catalog.json and shows their
+declared inputs, tools, permissions and network needs. Indexed components are cached per
+commit in the user cache directory, so sources are only downloaded again when they change
+(or with --refresh).
+
+Available subcommands:
+  • list          - List the components of all catalog sources
+  • search        - Search components by capability
+  • show          - Show the inputs, tools, permissions and network of a component
+  • add           - Add a component as an import of a workflow
+  • sources       - List the configured catalog sources
+  • add-source    - Add a repository to the catalog sources
+  • remove-source - Remove a repository from the catalog sources
+
+Examples:
+  gh aw catalog add-source githubnext/agentics --path workflows/shared  # Index a repository
+  gh aw catalog search slack                   # Find components that mention Slack
+  gh aw catalog show reporting                 # Show the inputs of the 'reporting' component
+  gh aw catalog add reporting daily-status     # Import it into daily-status.md`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cmd.Help()
+		},
+	}
+
+	cmd.AddCommand(newCatalogListSubcommand())
+	cmd.AddCommand(newCatalogSearchSubcommand())
+	cmd.AddCommand(newCatalogShowSubcommand())
+	cmd.AddCommand(newCatalogAddSubcommand())
+	cmd.AddCommand(newCatalogSourcesSubcommand())
+	cmd.AddCommand(newCatalogAddSourceSubcommand())
+	cmd.AddCommand(newCatalogRemoveSourceSubcommand())
+
+	return cmd
+}
+
+// addCatalogSourceFlag adds the --source flag that overrides the configured catalog sources
+// and the --refresh flag that bypasses the catalog index
+func addCatalogSourceFlag(cmd *cobra.Command) {
+	cmd.Flags().StringArray("source", nil, "Index this repository (owner/repo[@ref]) instead of the configured sources (can be repeated)")
+	cmd.Flags().Bool("refresh", false, "Download all components again instead of using the catalog index")
+}
+
+// newCatalogListSubcommand creates the catalog list subcommand
+func newCatalogListSubcommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List the components of all catalog sources",
+		Long: `List the shared components published by the catalog sources.
+
+Examples:
+  gh aw catalog list                                # List components of the configured sources
+  gh aw catalog list --source githubnext/agentics   # List components of a single repository
+  gh aw catalog list --json                         # Output in JSON format`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			sourceSpecs, _ := cmd.Flags().GetStringArray("source")
+			refresh, _ := cmd.Flags().GetBool("refresh")
+			verbose, _ := cmd.Flags().GetBool("verbose")
+			jsonOutput, _ := cmd.Flags().GetBool("json")
+
+			sources, err := catalogSources(sourceSpecs)
+			if err != nil {
+				return err
+			}
+			components, err := indexCatalog(sources, refresh, verbose)
+			if err != nil {
+				return err
+			}
+			if jsonOutput {
+				return printCatalogJSON(components)
+			}
+			if len(components) == 0 {
+				fmt.Fprintln(os.Stderr, console.FormatInfoMessage("No shared components found in the catalog sources."))
+				return nil
+			}
+			fmt.Fprintln(os.Stderr, console.FormatSuccessMessage(fmt.Sprintf("Found %d component(s)", len(components))))
+			fmt.Fprint(os.Stderr, console.RenderStruct(components))
+			return nil
+		},
+	}
+
+	addCatalogSourceFlag(cmd)
+	addJSONFlag(cmd)
+	return cmd
+}
+
+// newCatalogSearchSubcommand creates the catalog search subcommand
+func newCatalogSearchSubcommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "search <query>...",
+		Short: "Search components by capability",
+		Long: `Search the catalog for components whose name, tools, MCP servers, safe outputs,
+network domains, permissions or inputs contain every search term. Descriptions are
+searched when no capability matches.
+
+Examples:
+  gh aw catalog search slack                  # Components that use Slack
+  gh aw catalog search create-issue           # Components that create issues
+  gh aw catalog search github pull-requests   # Components matching both terms`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			sourceSpecs, _ := cmd.Flags().GetStringArray("source")
+			refresh, _ := cmd.Flags().GetBool("refresh")
+			verbose, _ := cmd.Flags().GetBool("verbose")
+			jsonOutput, _ := cmd.Flags().GetBool("json")
+
+			sources, err := catalogSources(sourceSpecs)
+			if err != nil {
+				return err
+			}
+			components, err := indexCatalog(sources, refresh, verbose)
+			if err != nil {
+				return err
+			}
+			query := strings.Join(args, " ")
+			results := searchCatalog(components, query)
+			if jsonOutput {
+				if results == nil {
+					results = []CatalogSearchResult{}
+				}
+				return printCatalogJSON(results)
+			}
+			if len(results) == 0 {
+				fmt.Fprintln(os.Stderr, console.FormatInfoMessage(fmt.Sprintf("No components match '%s'.", query)))
+				return nil
+			}
+			fmt.Fprint(os.Stderr, console.RenderStruct(results))
+			return nil
+		},
+	}
+
+	addCatalogSourceFlag(cmd)
+	addJSONFlag(cmd)
+	return cmd
+}
+
+// newCatalogShowSubcommand creates the catalog show subcommand
+func newCatalogShowSubcommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "show <component>",
+		Short: "Show the inputs, tools, permissions and network of a component",
+		Long: `Show the declared inputs, tools, safe outputs, permissions and network needs of a component.
+
+The component is a name or path from 'gh aw catalog list', or a full owner/repo/path.md[@ref] spec.
+
+Examples:
+  gh aw catalog show reporting
+  gh aw catalog show githubnext/agentics/workflows/shared/reporting.md@main`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			sourceSpecs, _ := cmd.Flags().GetStringArray("source")
+			refresh, _ := cmd.Flags().GetBool("refresh")
+			verbose, _ := cmd.Flags().GetBool("verbose")
+			jsonOutput, _ := cmd.Flags().GetBool("json")
+
+			component, err := resolveCatalogComponent(args[0], sourceSpecs, refresh, verbose)
+			if err != nil {
+				return err
+			}
+			if jsonOutput {
+				return printCatalogJSON(component)
+			}
+			fmt.Fprint(os.Stderr, renderCatalogComponent(component))
+			return nil
+		},
+	}
+
+	addCatalogSourceFlag(cmd)
+	addJSONFlag(cmd)
+	return cmd
+}
+
+// newCatalogAddSubcommand creates the catalog add subcommand
+func newCatalogAddSubcommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "add <component> <workflow>",
+		Short: "Add a component as an import of a workflow",
+		Long: `Add a catalog component to the imports of a local workflow.
+
+Inputs are validated against the component's declared inputs. Required inputs that are not
+passed with --input are prompted for interactively. Values are parsed as YAML, so numbers,
+booleans, arrays ([a, b]) and objects ({key: value}) can be passed.
+
+Examples:
+  gh aw catalog add reporting daily-status
+  gh aw catalog add reporting daily-status --input title="Daily status" --input labels="[report]"`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			sourceSpecs, _ := cmd.Flags().GetStringArray("source")
+			inputs, _ := cmd.Flags().GetStringArray("input")
+			refresh, _ := cmd.Flags().GetBool("refresh")
+			verbose, _ := cmd.Flags().GetBool("verbose")
+			return AddCatalogComponent(args[0], args[1], inputs, sourceSpecs, refresh, verbose)
+		},
+	}
+
+	addCatalogSourceFlag(cmd)
+	cmd.Flags().StringArray("input", nil, "Input value in name=value format (can be repeated)")
+	cmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 1 {
+			return CompleteWorkflowNames(cmd, args, toComplete)
+		}
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	return cmd
+}
+
+// newCatalogSourcesSubcommand creates the catalog sources subcommand
+func newCatalogSourcesSubcommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "sources",
+		Short: "List the configured catalog sources",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			jsonOutput, _ := cmd.Flags().GetBool("json")
+			return ListCatalogSources(jsonOutput)
+		},
+	}
+
+	addJSONFlag(cmd)
+	return cmd
+}
+
+// newCatalogAddSourceSubcommand creates the catalog add-source subcommand
+func newCatalogAddSourceSubcommand() *cobra.Command {
+	var sourcePath string
+
+	cmd := &cobra.Command{
+		Use:   "add-source <owner/repo[@ref]>",
+		Short: "Add a repository to the catalog sources",
+		Long: `Add a repository directory to the catalog sources in .github/aw/catalog.json.
+
+The directory defaults to ` + defaultCatalogSourcePath + ` and the ref to the default branch
+of the repository.
+
+Examples:
+  gh aw catalog add-source my-org/shared-workflows
+  gh aw catalog add-source githubnext/agentics@main --path workflows/shared`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			verbose, _ := cmd.Flags().GetBool("verbose")
+			return AddCatalogSource(args[0], sourcePath, verbose)
+		},
+	}
+
+	cmd.Flags().StringVar(&sourcePath, "path", "", "Directory containing the shared components (default: "+defaultCatalogSourcePath+")")
+	return cmd
+}
+
+// newCatalogRemoveSourceSubcommand creates the catalog remove-source subcommand
+func newCatalogRemoveSourceSubcommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "remove-source <owner/repo>",
+		Short: "Remove a repository from the catalog sources",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if strings.Contains(args[0], "@") {
+				return errors.New("remove-source takes owner/repo without a ref")
+			}
+			return RemoveCatalogSource(args[0])
+		},
+	}
+}
+
+// printCatalogJSON prints catalog output as indented JSON
+func printCatalogJSON(value any) error {
+	jsonBytes, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal JSON: %w", err)
+	}
+	fmt.Println(string(jsonBytes))
+	return nil
+}
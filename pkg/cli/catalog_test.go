@@ -0,0 +1,314 @@
+//go:build !integration
+
+package cli
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/github/gh-aw/pkg/parser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// catalogTestFiles are the files of a fake catalog source repository, keyed by path
+var catalogTestFiles = map[string]string{
+	".github/workflows/shared/slack.md": `---
+description: Post updates to Slack
+inputs:
+  channel:
+    type: string
+    required: true
+    pattern: "^#"
+  mentions:
+    type: array
+    items:
+      type: string
+safe-outputs:
+  staged: true
+  create-issue:
+network:
+  allowed:
+    - slack.com
+mcp-servers:
+  slack:
+    container: mcp/slack
+---
+Post to ${{ github.aw.inputs.channel }}.
+`,
+	".github/workflows/shared/reporting.md": `---
+description: Shared reporting guidelines
+tools:
+  github:
+    toolsets: [issues]
+permissions:
+  issues: read
+  contents: read
+---
+Report format.
+`,
+	".github/workflows/shared/daily.md": `---
+on: daily
+---
+A full workflow, not a component.
+`,
+}
+
+// catalogGitHubStub is the fake GitHub state used by catalog tests
+type catalogGitHubStub struct {
+	defaultBranch string // default branch of octo/components
+	sha           string // commit every ref resolves to
+	downloads     int    // number of downloaded files
+}
+
+// stubCatalogGitHub replaces GitHub access with the given files and the catalog index with a
+// temporary file for the duration of a test
+func stubCatalogGitHub(t *testing.T, files map[string]string) *catalogGitHubStub {
+	t.Helper()
+	originalList, originalDownload := listCatalogFilesFunc, downloadCatalogFileFunc
+	originalBranch, originalCommit, originalIndexPath := resolveCatalogDefaultBranchFunc, resolveCatalogCommitFunc, catalogIndexPathFunc
+	t.Cleanup(func() {
+		listCatalogFilesFunc, downloadCatalogFileFunc = originalList, originalDownload
+		resolveCatalogDefaultBranchFunc, resolveCatalogCommitFunc, catalogIndexPathFunc = originalBranch, originalCommit, originalIndexPath
+	})
+
+	stub := &catalogGitHubStub{defaultBranch: "main", sha: "1111111111111111111111111111111111111111"}
+	indexPath := filepath.Join(t.TempDir(), catalogIndexFileName)
+	catalogIndexPathFunc = func() (string, error) { return indexPath, nil }
+	resolveCatalogDefaultBranchFunc = func(repo string) (string, error) {
+		if repo != "octo/components" {
+			return "", errors.New("repository not found")
+		}
+		return stub.defaultBranch, nil
+	}
+	resolveCatalogCommitFunc = func(owner, repo, ref string) (string, error) {
+		if owner+"/"+repo != "octo/components" {
+			return "", errors.New("repository not found")
+		}
+		return stub.sha, nil
+	}
+	listCatalogFilesFunc = func(owner, repo, ref, workflowPath string) ([]string, error) {
+		if owner+"/"+repo != "octo/components" {
+			return nil, errors.New("repository not found")
+		}
+		var paths []string
+		for filePath := range files {
+			if filepath.Dir(filePath) == workflowPath {
+				paths = append(paths, filePath)
+			}
+		}
+		return paths, nil
+	}
+	downloadCatalogFileFunc = func(owner, repo, filePath, ref string) ([]byte, error) {
+		content, ok := files[filePath]
+		if !ok {
+			return nil, errors.New("file not found")
+		}
+		stub.downloads++
+		return []byte(content), nil
+	}
+	return stub
+}
+
+func TestIndexCatalog(t *testing.T) {
+	stubCatalogGitHub(t, catalogTestFiles)
+
+	components, err := indexCatalog([]CatalogSource{{Repo: "octo/components"}}, false, false)
+	require.NoError(t, err, "catalog should be indexed")
+	require.Len(t, components, 2, "workflows with an 'on' trigger should be skipped")
+
+	reporting := components[0]
+	assert.Equal(t, "octo/components/.github/workflows/shared/reporting.md@main", reporting.Spec, "spec should be an import path")
+	assert.Equal(t, []string{"github"}, reporting.Tools, "tools should be listed")
+	assert.Equal(t, []string{"contents: read", "issues: read"}, reporting.Permissions, "permissions should be listed")
+	assert.Zero(t, reporting.InputCount, "component without inputs")
+
+	slack := components[1]
+	assert.Equal(t, "slack", slack.Name(), "name should be the file name")
+	assert.Equal(t, []string{"slack"}, slack.Tools, "MCP servers should be listed as tools")
+	assert.Equal(t, []string{"create-issue"}, slack.SafeOutputs, "safe output settings should not be listed as types")
+	assert.Equal(t, []string{"slack.com"}, slack.Network, "network domains should be listed")
+	assert.Equal(t, 2, slack.InputCount, "inputs should be counted")
+
+	_, err = indexCatalog([]CatalogSource{{Repo: "octo/missing"}}, false, false)
+	require.Error(t, err, "unknown repository should fail")
+	assert.Contains(t, err.Error(), "octo/missing/.github/workflows/shared", "error should name the source")
+}
+
+func TestIndexCatalogUsesDefaultBranch(t *testing.T) {
+	stub := stubCatalogGitHub(t, catalogTestFiles)
+	stub.defaultBranch = "trunk"
+
+	components, err := indexCatalog([]CatalogSource{{Repo: "octo/components"}}, false, false)
+	require.NoError(t, err, "catalog should be indexed")
+	require.NotEmpty(t, components)
+	assert.Equal(t, "octo/components/.github/workflows/shared/reporting.md@trunk", components[0].Spec, "spec should use the default branch")
+
+	component, err := resolveCatalogComponent("octo/components/.github/workflows/shared/slack.md", nil, false, false)
+	require.NoError(t, err, "spec without a ref should resolve")
+	assert.Equal(t, "trunk", component.Ref, "spec without a ref should use the default branch")
+}
+
+func TestIndexCatalogPersistsIndex(t *testing.T) {
+	stub := stubCatalogGitHub(t, catalogTestFiles)
+	sources := []CatalogSource{{Repo: "octo/components"}}
+
+	first, err := indexCatalog(sources, false, false)
+	require.NoError(t, err, "catalog should be indexed")
+	downloads := stub.downloads
+	require.Positive(t, downloads, "first index should download the components")
+
+	second, err := indexCatalog(sources, false, false)
+	require.NoError(t, err, "catalog should be read from the index")
+	assert.Equal(t, downloads, stub.downloads, "unchanged source should not be downloaded again")
+	require.Len(t, second, len(first), "index should keep all components")
+	assert.Equal(t, first[1].Spec, second[1].Spec, "index should keep the component specs")
+	assert.Equal(t, first[1].InputCount, second[1].InputCount, "input count should be restored")
+
+	_, err = indexCatalog(sources, true, false)
+	require.NoError(t, err)
+	assert.Equal(t, 2*downloads, stub.downloads, "refresh should download the components again")
+
+	stub.sha = "2222222222222222222222222222222222222222"
+	_, err = indexCatalog(sources, false, false)
+	require.NoError(t, err)
+	assert.Equal(t, 3*downloads, stub.downloads, "moved ref should download the components again")
+}
+
+func TestSearchCatalog(t *testing.T) {
+	stubCatalogGitHub(t, catalogTestFiles)
+	components, err := indexCatalog([]CatalogSource{{Repo: "octo/components"}}, false, false)
+	require.NoError(t, err, "catalog should be indexed")
+
+	tests := []struct {
+		name    string
+		query   string
+		want    []string
+		matches []string
+	}{
+		{name: "by tool", query: "GitHub", want: []string{"reporting"}, matches: []string{"tool:github"}},
+		{name: "by safe output", query: "create-issue", want: []string{"slack"}, matches: []string{"safe-output:create-issue"}},
+		{name: "by network", query: "slack.com", want: []string{"slack"}, matches: []string{"network:slack.com"}},
+		{name: "by permission", query: "issues:", want: []string{"reporting"}, matches: []string{"permission:issues: read"}},
+		{name: "by description", query: "guidelines", want: []string{"reporting"}, matches: []string{"description"}},
+		{name: "all terms must match", query: "slack channel", want: []string{"slack"}, matches: []string{"name:slack", "tool:slack", "network:slack.com", "input:channel"}},
+		{name: "no match", query: "jira"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results := searchCatalog(components, tt.query)
+			var names []string
+			for _, result := range results {
+				names = append(names, result.Name())
+			}
+			assert.Equal(t, tt.want, names, "matching components")
+			if tt.matches != nil {
+				require.Len(t, results, 1, "expected a single result")
+				assert.Equal(t, tt.matches, results[0].Matches, "matched capabilities")
+			}
+		})
+	}
+}
+
+func TestResolveCatalogComponent(t *testing.T) {
+	stubCatalogGitHub(t, catalogTestFiles)
+	sources := []string{"octo/components"}
+
+	component, err := resolveCatalogComponent("slack", sources, false, false)
+	require.NoError(t, err, "component should resolve by name")
+	assert.Equal(t, "octo/components/.github/workflows/shared/slack.md@main", component.Spec)
+
+	component, err = resolveCatalogComponent("octo/components/.github/workflows/shared/reporting.md@v1", nil, false, false)
+	require.NoError(t, err, "component should resolve by spec without sources")
+	assert.Equal(t, "v1", component.Ref, "spec ref should be kept")
+
+	_, err = resolveCatalogComponent("octo/components/.github/workflows/shared/daily.md", nil, false, false)
+	require.Error(t, err, "workflows are not components")
+	assert.Contains(t, err.Error(), "use 'gh aw add'", "error should suggest gh aw add")
+
+	_, err = resolveCatalogComponent("jira", sources, false, false)
+	require.Error(t, err, "unknown component should fail")
+	assert.Contains(t, err.Error(), "no catalog component named 'jira'")
+}
+
+func TestParseCatalogInputFlags(t *testing.T) {
+	values, err := parseCatalogInputFlags([]string{"channel=#general", "count=3", "labels=[a, b]", "config={region: eu}", "empty="})
+	require.NoError(t, err, "flags should parse")
+	assert.Equal(t, "#general", values["channel"], "leading # should not start a YAML comment")
+	assert.EqualValues(t, 3, values["count"], "numbers should be parsed")
+	assert.Equal(t, []any{"a", "b"}, values["labels"], "arrays should be parsed")
+	assert.Equal(t, map[string]any{"region": "eu"}, values["config"], "objects should be parsed")
+	assert.Empty(t, values["empty"], "empty values should be empty strings")
+
+	_, err = parseCatalogInputFlags([]string{"novalue"})
+	require.Error(t, err, "flags without = should be rejected")
+}
+
+func TestAddCatalogComponent(t *testing.T) {
+	stubCatalogGitHub(t, catalogTestFiles)
+	workflowPath := filepath.Join(t.TempDir(), "status.md")
+	require.NoError(t, os.WriteFile(workflowPath, []byte("---\non: daily\n---\n# Status\n"), 0644))
+	sources := []string{"octo/components"}
+
+	err := AddCatalogComponent("slack", workflowPath, nil, sources, false, false)
+	require.Error(t, err, "missing required inputs should fail without a terminal")
+	assert.Contains(t, err.Error(), "missing required input(s)", "error should list missing inputs")
+
+	err = AddCatalogComponent("slack", workflowPath, []string{"channel=general"}, sources, false, false)
+	require.Error(t, err, "invalid inputs should be rejected")
+	assert.Contains(t, err.Error(), "does not match pattern", "error should describe the constraint")
+
+	require.NoError(t, AddCatalogComponent("slack", workflowPath, []string{"channel=#eng", "mentions=[octocat]"}, sources, false, false))
+	require.NoError(t, AddCatalogComponent("reporting", workflowPath, nil, sources, false, false))
+
+	content, err := os.ReadFile(workflowPath)
+	require.NoError(t, err)
+	result, err := parser.ExtractFrontmatterFromContent(string(content))
+	require.NoError(t, err)
+	assert.Equal(t, []any{
+		map[string]any{
+			"path":   "octo/components/.github/workflows/shared/slack.md@main",
+			"inputs": map[string]any{"channel": "#eng", "mentions": []any{"octocat"}},
+		},
+		"octo/components/.github/workflows/shared/reporting.md@main",
+	}, result.Frontmatter["imports"], "imports should be appended")
+	assert.Contains(t, result.Markdown, "# Status", "markdown should be preserved")
+
+	err = AddCatalogComponent("octo/components/.github/workflows/shared/reporting.md@v2", workflowPath, nil, nil, false, false)
+	require.Error(t, err, "duplicate imports should be rejected")
+	assert.Contains(t, err.Error(), "already imports", "error should explain the duplicate")
+}
+
+func TestCatalogSourcesConfig(t *testing.T) {
+	originalPath := catalogConfigPath
+	catalogConfigPath = filepath.Join(t.TempDir(), ".github", "aw", "catalog.json")
+	t.Cleanup(func() { catalogConfigPath = originalPath })
+
+	_, err := catalogSources(nil)
+	require.Error(t, err, "no sources should fail")
+	assert.Contains(t, err.Error(), "gh aw catalog add-source", "error should explain how to add a source")
+
+	require.NoError(t, AddCatalogSource("octo/components@v1", "", false))
+	require.NoError(t, AddCatalogSource("octo/agentics", "/workflows/shared/", false))
+	require.NoError(t, AddCatalogSource("octo/components@v2", "", false), "re-adding a source should update it")
+
+	sources, err := catalogSources(nil)
+	require.NoError(t, err)
+	assert.Equal(t, []CatalogSource{
+		{Repo: "octo/components", Ref: "v2"},
+		{Repo: "octo/agentics", Path: "workflows/shared"},
+	}, sources, "configured sources")
+
+	sources, err = catalogSources([]string{"octo/other@dev"})
+	require.NoError(t, err)
+	assert.Equal(t, []CatalogSource{{Repo: "octo/other", Ref: "dev"}}, sources, "--source should override the configuration")
+
+	require.NoError(t, RemoveCatalogSource("octo/components"))
+	require.Error(t, RemoveCatalogSource("octo/components"), "removing a missing source should fail")
+	config, err := loadCatalogConfig()
+	require.NoError(t, err)
+	assert.Len(t, config.Sources, 1, "one source should remain")
+}
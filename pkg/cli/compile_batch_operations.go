@@ -33,6 +33,7 @@ import (
 
 	"github.com/github/gh-aw/pkg/console"
 	"github.com/github/gh-aw/pkg/logger"
+	"github.com/github/gh-aw/pkg/workflow"
 )
 
 var compileBatchOperationsLog = logger.New("cli:compile_batch_operations")
@@ -144,6 +145,10 @@ func purgeOrphanedLockFiles(workflowsDir string, expectedLockFiles []string, ver
 			} else {
 				fmt.Fprintln(os.Stderr, console.FormatSuccessMessage("Removed orphaned lock file: "+filepath.Base(orphanedFile)))
 			}
+			// Remove the signed provenance of the lock file, if any
+			if err := os.Remove(workflow.ProvenanceFileForLockFile(orphanedFile)); err == nil {
+				compileBatchOperationsLog.Printf("Removed provenance of orphaned lock file: %s", orphanedFile)
+			}
 		}
 		if verbose {
 			fmt.Fprintln(os.Stderr, console.FormatSuccessMessage(fmt.Sprintf("Purged %d orphaned .lock.yml files", len(orphanedFiles))))
@@ -21,6 +21,7 @@
 //   - configureCompilerFlags() - Sets validation, strict mode, trial mode flags
 //   - setupActionMode() - Configures action script inlining mode
 //   - setupRepositoryContext() - Sets repository slug for schedule scattering
+//   - resolveCompileTarget() - Parses the --target platform (github.com or GHES)
 //
 // These functions abstract compiler setup, allowing the main compile
 // orchestrator to focus on coordination while these handle configuration.
@@ -124,6 +125,9 @@ func configureCompilerFlags(compiler *workflow.Compiler, config CompileConfig) {
 		compileCompilerSetupLog.Print("No-emit mode enabled: validating without generating lock files")
 	}
 
+	// Print the provenance of settings merged from imports
+	compiler.SetExplainMerge(config.ExplainMerge)
+
 	// Set strict mode if specified
 	compiler.SetStrictMode(config.Strict)
 
@@ -147,6 +151,12 @@ func configureCompilerFlags(compiler *workflow.Compiler, config CompileConfig) {
 	if config.ForceRefreshActionPins {
 		compileCompilerSetupLog.Print("Force refresh action pins enabled: will clear cache and resolve all actions from GitHub API")
 	}
+
+	// Set compile target (already validated by validateCompileTargetConfig)
+	if target, err := resolveCompileTarget(config.Target); err == nil && target.IsGHES() {
+		compileCompilerSetupLog.Printf("Compiling for %s on %s", target, target.Host)
+		compiler.SetCompileTarget(target)
+	}
 }
 
 // setupActionMode configures the action script inlining mode
@@ -206,3 +216,18 @@ func validateActionModeConfig(actionMode string) error {
 
 	return nil
 }
+
+// resolveCompileTarget parses the --target value, taking the GHES hostname from the
+// environment (GITHUB_SERVER_URL, GITHUB_ENTERPRISE_HOST, GITHUB_HOST or GH_HOST)
+func resolveCompileTarget(target string) (*workflow.CompileTarget, error) {
+	if target == "" {
+		return nil, nil
+	}
+	return workflow.ParseCompileTarget(target, getGitHubHost())
+}
+
+// validateCompileTargetConfig validates the compile target configuration
+func validateCompileTargetConfig(target string) error {
+	_, err := resolveCompileTarget(target)
+	return err
+}
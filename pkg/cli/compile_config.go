@@ -33,6 +33,11 @@ type CompileConfig struct {
 	ActionTag              string   // Override action SHA or tag for actions/setup (overrides action-mode to release)
 	Stats                  bool     // Display statistics table sorted by file size
 	FailFast               bool     // Stop at first error instead of collecting all errors
+	ExplainMerge           bool     // Print which file contributed each setting merged from imports
+	Target                 string   // Platform to compile for: github.com (default) or ghes:<version>
+	Offline                bool     // Compile without network access from an offline bundle
+	Bundle                 string   // Path of the offline bundle tarball (required with Offline)
+	ProvenanceKey          string   // Path of an Ed25519 private key used to sign provenance for each lock file
 }
 
 // WorkflowFailure represents a failed workflow with its error count
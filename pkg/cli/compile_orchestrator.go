@@ -36,6 +36,11 @@ func CompileWorkflows(ctx context.Context, config CompileConfig) ([]*workflow.Wo
 		return nil, err
 	}
 
+	// Validate compile target if specified
+	if err := validateCompileTargetConfig(config.Target); err != nil {
+		return nil, err
+	}
+
 	// Initialize actionlint statistics if actionlint is enabled
 	if config.Actionlint && !config.NoEmit {
 		initActionlintStats()
@@ -60,6 +65,24 @@ func CompileWorkflows(ctx context.Context, config CompileConfig) ([]*workflow.Wo
 	// Create and configure compiler
 	compiler := createAndConfigureCompiler(config)
 
+	// Compile from the offline bundle without network access
+	if config.Offline {
+		bundleDir, err := setupOfflineBundle(compiler, config.Bundle)
+		if err != nil {
+			return nil, err
+		}
+		defer os.RemoveAll(bundleDir)
+	}
+
+	// Sign a provenance statement for each lock file
+	if config.ProvenanceKey != "" {
+		key, err := workflow.LoadProvenanceSigningKey(config.ProvenanceKey)
+		if err != nil {
+			return nil, err
+		}
+		compiler.SetProvenanceKey(key)
+	}
+
 	// Handle watch mode (early return)
 	if config.Watch {
 		// Watch mode: watch for file changes and recompile automatically
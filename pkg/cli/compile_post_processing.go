@@ -92,6 +92,11 @@ func collectWorkflowStatisticsWrapper(markdownFiles []string) []*WorkflowStats {
 		}
 		lockFile := stringutil.MarkdownToLockFile(resolvedFile)
 		if workflowStats, err := collectWorkflowStats(lockFile); err == nil {
+			if prompt, err := collectPromptStats(resolvedFile); err == nil {
+				workflowStats.Prompt = prompt
+			} else {
+				compilePostProcessingLog.Printf("Failed to estimate prompt size for %s: %v", resolvedFile, err)
+			}
 			statsList = append(statsList, workflowStats)
 		}
 	}
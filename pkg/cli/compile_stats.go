@@ -4,18 +4,24 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
 	"sort"
 	"strconv"
+	"strings"
 
 	"github.com/github/gh-aw/pkg/console"
 	"github.com/github/gh-aw/pkg/logger"
 	"github.com/github/gh-aw/pkg/styles"
 	"github.com/github/gh-aw/pkg/tty"
+	"github.com/github/gh-aw/pkg/workflow"
 	"github.com/goccy/go-yaml"
 )
 
 var compileStatsLog = logger.New("cli:compile_stats")
 
+// allowDomainsPattern matches the domain list passed to the firewall in a lock file
+var allowDomainsPattern = regexp.MustCompile(`--allow-domains\s+"?([^"\s]+)`)
+
 // WorkflowStats holds statistics about a compiled workflow
 type WorkflowStats struct {
 	Workflow    string
@@ -26,6 +32,8 @@ type WorkflowStats struct {
 	ScriptSize  int
 	ShellCount  int
 	ShellSize   int
+	Domains     int                          // domains allowed by the firewall, after expanding built-in and custom ecosystems
+	Prompt      *workflow.PromptBudgetReport // estimated prompt size per section (nil when unavailable)
 }
 
 // collectWorkflowStats parses a lock file and collects statistics
@@ -72,6 +80,9 @@ func collectWorkflowStats(lockFilePath string) (*WorkflowStats, error) {
 							if runScript, ok := step["run"].(string); ok {
 								stats.ScriptCount++
 								stats.ScriptSize += len(runScript)
+								if match := allowDomainsPattern.FindStringSubmatch(runScript); match != nil {
+									stats.Domains = max(stats.Domains, len(strings.Split(match[1], ",")))
+								}
 							}
 
 							// Check for "shell" field
@@ -154,19 +165,29 @@ func displayStatsTable(statsList []*WorkflowStats) {
 			strconv.Itoa(stats.Jobs),
 			strconv.Itoa(stats.Steps),
 			strconv.Itoa(stats.ScriptCount),
+			strconv.Itoa(stats.Domains),
+			formatPromptTokens(stats.Prompt),
 		})
 	}
 
 	// Create table config
 	tableConfig := console.TableConfig{
 		Title:   "",
-		Headers: []string{"WORKFLOW", "FILE SIZE", "JOBS", "STEPS", "SCRIPTS"},
+		Headers: []string{"WORKFLOW", "FILE SIZE", "JOBS", "STEPS", "SCRIPTS", "DOMAINS", "PROMPT TOKENS"},
 		Rows:    rows,
 	}
 
 	// Render and print table
 	fmt.Fprint(os.Stderr, console.RenderTable(tableConfig))
 
+	// Render the prompt size breakdown of the displayed workflows
+	for i, stats := range statsList {
+		if i >= maxDisplay {
+			break
+		}
+		displayPromptBreakdown(stats)
+	}
+
 	// Print summary
 	fmt.Fprintln(os.Stderr, console.FormatInfoMessage("Summary:"))
 	if len(statsList) > maxDisplay {
@@ -178,3 +199,54 @@ func displayStatsTable(statsList []*WorkflowStats) {
 	fmt.Fprintf(os.Stderr, "  Total steps:     %d\n", totalSteps)
 	fmt.Fprintf(os.Stderr, "  Total scripts:   %d (%s)\n", totalScripts, console.FormatFileSize(int64(totalScriptSize)))
 }
+
+// collectPromptStats estimates the prompt size of a workflow per section
+func collectPromptStats(markdownPath string) (*workflow.PromptBudgetReport, error) {
+	compileStatsLog.Printf("Estimating prompt size: file=%s", markdownPath)
+	compiler := workflow.NewCompiler()
+	return compiler.EstimatePromptBudget(markdownPath)
+}
+
+// formatPromptTokens formats the estimated prompt size for the stats table
+func formatPromptTokens(report *workflow.PromptBudgetReport) string {
+	if report == nil {
+		return "-"
+	}
+	value := "~" + strconv.Itoa(report.TotalTokens)
+	if report.Exceeded() {
+		if tty.IsStderrTerminal() {
+			return styles.Error.Render("✗ " + value)
+		}
+		return "✗ " + value
+	}
+	return value
+}
+
+// displayPromptBreakdown displays the estimated prompt size of each prompt section of a workflow
+func displayPromptBreakdown(stats *WorkflowStats) {
+	if stats.Prompt == nil || len(stats.Prompt.Sections) == 0 {
+		return
+	}
+
+	rows := make([][]string, 0, len(stats.Prompt.Sections))
+	for _, section := range stats.Prompt.Sections {
+		share := 0
+		if stats.Prompt.TotalTokens > 0 {
+			share = section.Tokens * 100 / stats.Prompt.TotalTokens
+		}
+		rows = append(rows, []string{
+			section.Name,
+			section.Kind,
+			strconv.Itoa(section.Priority),
+			"~" + strconv.Itoa(section.Tokens),
+			strconv.Itoa(share) + "%",
+		})
+	}
+
+	title := fmt.Sprintf("Prompt size: %s (~%d of %d tokens)", stats.Workflow, stats.Prompt.TotalTokens, stats.Prompt.Budget())
+	fmt.Fprint(os.Stderr, console.RenderTable(console.TableConfig{
+		Title:   title,
+		Headers: []string{"SECTION", "KIND", "TRIM PRIORITY", "TOKENS", "SHARE"},
+		Rows:    rows,
+	}))
+}
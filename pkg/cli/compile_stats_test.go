@@ -9,6 +9,8 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+
+	"github.com/github/gh-aw/pkg/workflow"
 )
 
 func TestDisplayStatsTable_Empty(t *testing.T) {
@@ -232,7 +234,7 @@ jobs:
     runs-on: ubuntu-latest
     steps:
       - name: Step 4
-        run: echo "test3"
+        run: sudo awf --allow-domains "api.github.com,github.com,pypi.org" -- copilot
 `
 	lockFilePath := filepath.Join(tempDir, "test.lock.yml")
 	err := os.WriteFile(lockFilePath, []byte(testYAML), 0644)
@@ -263,6 +265,10 @@ jobs:
 		t.Errorf("Expected 3 scripts (run commands), got %d", stats.ScriptCount)
 	}
 
+	if stats.Domains != 3 {
+		t.Errorf("Expected 3 allowed domains, got %d", stats.Domains)
+	}
+
 	if stats.FileSize <= 0 {
 		t.Errorf("Expected positive file size, got %d", stats.FileSize)
 	}
@@ -299,3 +305,41 @@ func TestCollectWorkflowStats_InvalidYAML(t *testing.T) {
 		t.Error("Expected nil stats for invalid YAML")
 	}
 }
+
+func TestDisplayStatsTable_PromptBreakdown(t *testing.T) {
+	statsList := []*WorkflowStats{
+		{
+			Workflow: "review.lock.yml",
+			FileSize: 1000,
+			Prompt: &workflow.PromptBudgetReport{
+				Sections: []workflow.PromptSectionEstimate{
+					{Name: "xpia", Kind: "built-in", Tokens: 300},
+					{Name: "shared/reference.md", Kind: "import", Tokens: 900, Priority: workflow.PromptPriorityImport},
+				},
+				TotalTokens:  1200,
+				MaxTokens:    1000,
+				ContextLimit: workflow.DefaultContextTokenLimit,
+			},
+		},
+	}
+
+	// Capture stderr output
+	oldStderr := os.Stderr
+	r, w, _ := os.Pipe()
+	os.Stderr = w
+
+	displayStatsTable(statsList)
+
+	w.Close()
+	os.Stderr = oldStderr
+
+	var buf bytes.Buffer
+	buf.ReadFrom(r)
+	output := buf.String()
+
+	for _, expected := range []string{"PROMPT TOKENS", "✗ ~1200", "Prompt size: review.lock.yml (~1200 of 1000 tokens)", "shared/reference.md", "75%"} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Expected output to contain %q, got: %s", expected, output)
+		}
+	}
+}
@@ -218,6 +218,28 @@ func validateCompileConfig(config CompileConfig) error {
 		return fmt.Errorf("--dir must be a relative path, got: %s", config.WorkflowDir)
 	}
 
+	// Validate offline compilation flags
+	if config.Offline && config.Bundle == "" {
+		return errors.New("--offline requires --bundle with a bundle created by 'gh aw bundle export'")
+	}
+	if config.Bundle != "" && !config.Offline {
+		return errors.New("--bundle can only be used with --offline")
+	}
+	if config.Offline {
+		switch {
+		case config.Zizmor, config.Poutine, config.Actionlint:
+			return errors.New("--offline cannot be used with --zizmor, --poutine or --actionlint, which run Docker images")
+		case config.Dependabot:
+			return errors.New("--offline cannot be used with --dependabot, which resolves packages from registries")
+		case config.ForceRefreshActionPins:
+			return errors.New("--offline cannot be used with --force-refresh-action-pins")
+		}
+	}
+
+	if config.ProvenanceKey != "" && config.NoEmit {
+		return errors.New("--provenance-key cannot be used with --no-emit")
+	}
+
 	compileValidationLog.Print("Config validation successful")
 	return nil
 }
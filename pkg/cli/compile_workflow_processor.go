@@ -130,7 +130,8 @@ func compileWorkflowFile(
 
 	// Compile the workflow
 	// Disable per-file actionlint run (false instead of actionlint && !noEmit) - we'll batch them
-	if err := CompileWorkflowDataWithValidation(compiler, workflowData, resolvedFile, verbose && !jsonOutput, zizmor && !noEmit, poutine && !noEmit, false, strict, validate && !noEmit); err != nil {
+	// Checking for newer action SHAs queries GitHub, so it is skipped when compiling offline
+	if err := CompileWorkflowDataWithValidation(compiler, workflowData, resolvedFile, verbose && !jsonOutput, zizmor && !noEmit, poutine && !noEmit, false, strict, validate && !noEmit && !compiler.IsOffline()); err != nil {
 		// Don't print error here - it will be displayed in the compilation summary
 		// The error is stored in ValidationResult for JSON output and summary display
 		result.validationResult.Valid = false
@@ -141,6 +142,19 @@ func compileWorkflowFile(
 		return result
 	}
 
+	// Connect to the MCP servers and check them against the workflow's snapshot, if it has one.
+	// Offline compilation cannot reach the servers, so the check is skipped.
+	if validate && !compiler.IsOffline() {
+		if err := verifyWorkflowDataMCPSnapshot(workflowData, resolvedFile, verbose && !jsonOutput); err != nil {
+			result.validationResult.Valid = false
+			result.validationResult.Errors = append(result.validationResult.Errors, CompileValidationError{
+				Type:    "mcp_drift",
+				Message: err.Error(),
+			})
+			return result
+		}
+	}
+
 	result.success = true
 	compileWorkflowProcessorLog.Printf("Successfully processed workflow file: %s", resolvedFile)
 	return result
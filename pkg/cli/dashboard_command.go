@@ -0,0 +1,396 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/github/gh-aw/pkg/constants"
+	"github.com/github/gh-aw/pkg/logger"
+	"github.com/github/gh-aw/pkg/tty"
+	"github.com/github/gh-aw/pkg/workflow"
+	"github.com/spf13/cobra"
+)
+
+var dashboardLog = logger.New("cli:dashboard_command")
+
+// DashboardConfig holds the configuration of the dashboard command
+type DashboardConfig struct {
+	Days         int
+	Threshold    float64
+	PollInterval time.Duration
+	OutputDir    string
+	RepoOverride string
+}
+
+// DashboardWorkflow is a row of the dashboard: an agentic workflow with its health and recent runs
+type DashboardWorkflow struct {
+	ID       string
+	Engine   string
+	State    string
+	Compiled string
+	Health   WorkflowHealth
+	Runs     []WorkflowRun // Newest first
+}
+
+// LatestRun returns the most recent run of the workflow, or nil if it has no runs
+func (w DashboardWorkflow) LatestRun() *WorkflowRun {
+	if len(w.Runs) == 0 {
+		return nil
+	}
+	return &w.Runs[0]
+}
+
+// ActiveRuns returns the number of queued or in-progress runs of the workflow
+func (w DashboardWorkflow) ActiveRuns() int {
+	active := 0
+	for _, run := range w.Runs {
+		if isActiveRun(run) {
+			active++
+		}
+	}
+	return active
+}
+
+// NewDashboardCommand creates the dashboard command
+func NewDashboardCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "dashboard",
+		Short: "Interactive terminal dashboard of workflow health, runs and audits",
+		Long: `Open a full-screen terminal dashboard listing the agentic workflows of the repository with
+their health, latest runs and in-progress runs.
+
+In-progress runs are refreshed periodically. Select a workflow to see its runs, and a run
+to see its audit (key findings, tool usage, firewall analysis and created items) without
+leaving the terminal. Run artifacts are cached in the logs directory (.github/aw/logs).
+
+Keys:
+  ↑/↓, j/k   Move the selection
+  enter      Show the runs of a workflow, or the audit of a run
+  esc        Go back
+  r          Run the selected workflow (asks for confirmation)
+  e          Enable or disable the selected workflow (asks for confirmation)
+  o          Open the selected workflow or run in the browser
+  ctrl+r     Refresh now
+  q          Quit
+
+Examples:
+  ` + string(constants.CLIExtensionPrefix) + ` dashboard                  # Dashboard of the last 7 days
+  ` + string(constants.CLIExtensionPrefix) + ` dashboard --days 30        # Health over the last 30 days
+  ` + string(constants.CLIExtensionPrefix) + ` dashboard --interval 5s    # Refresh in-progress runs every 5 seconds
+  ` + string(constants.CLIExtensionPrefix) + ` dashboard --repo owner/repo`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			days, _ := cmd.Flags().GetInt("days")
+			threshold, _ := cmd.Flags().GetFloat64("threshold")
+			interval, _ := cmd.Flags().GetDuration("interval")
+			outputDir, _ := cmd.Flags().GetString("output")
+			repoOverride, _ := cmd.Flags().GetString("repo")
+
+			return RunDashboard(cmd.Context(), DashboardConfig{
+				Days:         days,
+				Threshold:    threshold,
+				PollInterval: interval,
+				OutputDir:    outputDir,
+				RepoOverride: repoOverride,
+			})
+		},
+	}
+
+	cmd.Flags().Int("days", 7, "Number of days of runs to show (7, 30, or 90)")
+	cmd.Flags().Float64("threshold", 80.0, "Success rate threshold for highlighting unhealthy workflows (percentage)")
+	cmd.Flags().Duration("interval", 15*time.Second, "How often in-progress runs are refreshed")
+	addRepoFlag(cmd)
+	addOutputFlag(cmd, defaultLogsOutputDir)
+
+	return cmd
+}
+
+// RunDashboard runs the interactive dashboard until the user quits
+func RunDashboard(ctx context.Context, config DashboardConfig) error {
+	dashboardLog.Printf("Starting dashboard: days=%d, interval=%v, repo=%s", config.Days, config.PollInterval, config.RepoOverride)
+
+	if config.Days != 7 && config.Days != 30 && config.Days != 90 {
+		return fmt.Errorf("invalid days value: %d. Must be 7, 30, or 90", config.Days)
+	}
+	if config.PollInterval < time.Second {
+		return errors.New("--interval must be at least 1s")
+	}
+	if !tty.IsStdoutTerminal() {
+		return errors.New("the dashboard requires an interactive terminal; use 'gh aw status', 'gh aw health' or 'gh aw logs' instead")
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	// The data and action helpers report progress with spinners and messages on stderr, which
+	// would corrupt the full-screen view, so stderr is discarded while the dashboard runs
+	restoreStderr, err := discardStderr()
+	if err != nil {
+		return err
+	}
+	defer restoreStderr()
+
+	program := tea.NewProgram(newDashboardModel(ctx, config), tea.WithAltScreen(), tea.WithContext(ctx))
+	_, err = program.Run()
+	if errors.Is(err, tea.ErrProgramKilled) && ctx.Err() != nil {
+		return nil
+	}
+	return err
+}
+
+// discardStderr redirects os.Stderr to the null device and returns a function restoring it
+func discardStderr() (func(), error) {
+	devNull, err := os.OpenFile(os.DevNull, os.O_WRONLY, 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", os.DevNull, err)
+	}
+	original := os.Stderr
+	os.Stderr = devNull
+	return func() {
+		os.Stderr = original
+		devNull.Close()
+	}, nil
+}
+
+// loadDashboardWorkflows lists the local agentic workflows with their GitHub state, health and runs
+func loadDashboardWorkflows(config DashboardConfig) ([]DashboardWorkflow, error) {
+	statuses, err := GetWorkflowStatuses("", "", "", config.RepoOverride)
+	if err != nil {
+		return nil, err
+	}
+
+	startDate := time.Now().AddDate(0, 0, -config.Days).Format("2006-01-02")
+	runs, err := fetchWorkflowRuns("", startDate, config.RepoOverride, false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch workflow runs: %w", err)
+	}
+
+	return buildDashboardWorkflows(statuses, runs, config.Threshold), nil
+}
+
+// buildDashboardWorkflows joins workflow statuses with their runs and computes their health.
+// Runs are matched to workflows by the lock file they ran.
+func buildDashboardWorkflows(statuses []WorkflowStatus, runs []WorkflowRun, threshold float64) []DashboardWorkflow {
+	runsByID := make(map[string][]WorkflowRun)
+	for _, run := range runs {
+		id := dashboardWorkflowID(run)
+		runsByID[id] = append(runsByID[id], run)
+	}
+
+	workflows := make([]DashboardWorkflow, 0, len(statuses))
+	for _, status := range statuses {
+		workflowRuns := runsByID[status.Workflow]
+		sort.SliceStable(workflowRuns, func(i, j int) bool { return workflowRuns[i].CreatedAt.After(workflowRuns[j].CreatedAt) })
+		workflows = append(workflows, DashboardWorkflow{
+			ID:       status.Workflow,
+			Engine:   status.EngineID,
+			State:    status.Status,
+			Compiled: status.Compiled,
+			Health:   CalculateWorkflowHealth(status.Workflow, completedRuns(workflowRuns), threshold),
+			Runs:     workflowRuns,
+		})
+	}
+	sort.Slice(workflows, func(i, j int) bool { return workflows[i].ID < workflows[j].ID })
+	dashboardLog.Printf("Built dashboard for %d workflows from %d runs", len(workflows), len(runs))
+	return workflows
+}
+
+// mergeDashboardRuns updates the runs of the dashboard with freshly polled runs, replacing
+// runs that are already known and adding new ones
+func mergeDashboardRuns(workflows []DashboardWorkflow, polled []WorkflowRun, threshold float64) []DashboardWorkflow {
+	byID := make(map[string][]WorkflowRun)
+	for _, run := range polled {
+		id := dashboardWorkflowID(run)
+		byID[id] = append(byID[id], run)
+	}
+
+	merged := make([]DashboardWorkflow, len(workflows))
+	for i, wf := range workflows {
+		runs := append([]WorkflowRun{}, wf.Runs...)
+		for _, run := range byID[wf.ID] {
+			replaced := false
+			for j := range runs {
+				if runs[j].DatabaseID == run.DatabaseID {
+					runs[j] = run
+					replaced = true
+					break
+				}
+			}
+			if !replaced {
+				runs = append(runs, run)
+			}
+		}
+		sort.SliceStable(runs, func(a, b int) bool { return runs[a].CreatedAt.After(runs[b].CreatedAt) })
+		wf.Runs = runs
+		wf.Health = CalculateWorkflowHealth(wf.ID, completedRuns(runs), threshold)
+		merged[i] = wf
+	}
+	return merged
+}
+
+// pollDashboardRuns fetches the most recent runs of the repository to refresh in-progress runs
+func pollDashboardRuns(config DashboardConfig) ([]WorkflowRun, error) {
+	runs, _, err := listWorkflowRunsWithPagination(ListWorkflowRunsOptions{
+		Limit:        50,
+		RepoOverride: config.RepoOverride,
+	})
+	if err != nil {
+		return nil, err
+	}
+	var agentic []WorkflowRun
+	for _, run := range runs {
+		if !strings.HasSuffix(run.WorkflowPath, ".lock.yml") {
+			continue
+		}
+		if run.Duration == 0 && !run.StartedAt.IsZero() && !run.UpdatedAt.IsZero() {
+			run.Duration = run.UpdatedAt.Sub(run.StartedAt)
+		}
+		agentic = append(agentic, run)
+	}
+	return agentic, nil
+}
+
+// loadDashboardAudit downloads (or loads from the cache) the artifacts of a completed run and
+// builds its audit data
+func loadDashboardAudit(run WorkflowRun, outputDir string) (AuditData, error) {
+	if isActiveRun(run) {
+		return AuditData{}, fmt.Errorf("run %d is still %s", run.DatabaseID, run.Status)
+	}
+
+	result := processRun(run, outputDir, false)
+	if result.Error != nil && !errors.Is(result.Error, ErrNoArtifacts) {
+		return AuditData{}, result.Error
+	}
+
+	audited := result.Run
+	audited.LogsPath = result.LogsPath
+	audited.TokenUsage = result.Metrics.TokenUsage
+	audited.EstimatedCost = result.Metrics.EstimatedCost
+	audited.Turns = result.Metrics.Turns
+	if !audited.StartedAt.IsZero() && !audited.UpdatedAt.IsZero() {
+		audited.Duration = audited.UpdatedAt.Sub(audited.StartedAt)
+	}
+
+	processedRun := ProcessedRun{
+		Run:                     audited,
+		AccessAnalysis:          result.AccessAnalysis,
+		FirewallAnalysis:        result.FirewallAnalysis,
+		RedactedDomainsAnalysis: result.RedactedDomainsAnalysis,
+		MissingTools:            result.MissingTools,
+		MissingData:             result.MissingData,
+		Noops:                   result.Noops,
+		MCPFailures:             result.MCPFailures,
+		MCPToolUsage:            result.MCPToolUsage,
+		JobDetails:              result.JobDetails,
+	}
+	return buildAuditData(processedRun, result.Metrics, result.MCPToolUsage), nil
+}
+
+// runDashboardWorkflow triggers a workflow_dispatch run of a workflow
+func runDashboardWorkflow(ctx context.Context, workflowID string, config DashboardConfig) error {
+	return captureDashboardStderr(func() error {
+		return RunWorkflowOnGitHub(ctx, workflowID, RunOptions{RepoOverride: config.RepoOverride})
+	})
+}
+
+// toggleDashboardWorkflow enables a disabled workflow and disables an enabled one
+func toggleDashboardWorkflow(wf DashboardWorkflow, config DashboardConfig) error {
+	return captureDashboardStderr(func() error {
+		if wf.State == "active" {
+			return DisableWorkflowsByNames([]string{wf.ID}, config.RepoOverride)
+		}
+		return EnableWorkflowsByNames([]string{wf.ID}, config.RepoOverride)
+	})
+}
+
+// dashboardStderrMu serializes the actions whose stderr output is captured
+var dashboardStderrMu sync.Mutex
+
+// captureDashboardStderr runs an action that reports progress on stderr with stderr redirected to
+// a temporary file, so that nothing is written over the full-screen view. When the action fails,
+// the last line it printed is added to the error.
+func captureDashboardStderr(action func() error) error {
+	dashboardStderrMu.Lock()
+	defer dashboardStderrMu.Unlock()
+
+	capture, err := os.CreateTemp("", "gh-aw-dashboard-*.log")
+	if err != nil {
+		return fmt.Errorf("failed to capture output: %w", err)
+	}
+	defer os.Remove(capture.Name())
+	defer capture.Close()
+
+	original := os.Stderr
+	os.Stderr = capture
+	actionErr := action()
+	os.Stderr = original
+	if actionErr == nil {
+		return nil
+	}
+
+	output, err := os.ReadFile(capture.Name())
+	if err != nil {
+		dashboardLog.Printf("Failed to read captured output: %v", err)
+		return actionErr
+	}
+	lines := strings.Split(strings.TrimSpace(string(output)), "\n")
+	if last := strings.TrimSpace(lines[len(lines)-1]); last != "" && !strings.Contains(actionErr.Error(), last) {
+		return fmt.Errorf("%w: %s", actionErr, last)
+	}
+	return actionErr
+}
+
+// openDashboardWorkflow opens the Actions page of a workflow in the browser
+func openDashboardWorkflow(workflowID string, config DashboardConfig) error {
+	args := []string{"workflow", "view", workflowID + ".lock.yml", "--web"}
+	if config.RepoOverride != "" {
+		args = append(args, "--repo", config.RepoOverride)
+	}
+	return runGHQuietly(args...)
+}
+
+// openDashboardRun opens a workflow run in the browser
+func openDashboardRun(run WorkflowRun, config DashboardConfig) error {
+	args := []string{"run", "view", strconv.FormatInt(run.DatabaseID, 10), "--web"}
+	if config.RepoOverride != "" {
+		args = append(args, "--repo", config.RepoOverride)
+	}
+	return runGHQuietly(args...)
+}
+
+func runGHQuietly(args ...string) error {
+	output, err := workflow.ExecGH(args...).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("gh %s failed: %w (output: %s)", strings.Join(args, " "), err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// dashboardWorkflowID returns the workflow ID (markdown file name) of the workflow a run belongs to
+func dashboardWorkflowID(run WorkflowRun) string {
+	return strings.TrimSuffix(filepath.Base(run.WorkflowPath), ".lock.yml")
+}
+
+// isActiveRun returns true if a run is queued or in progress
+func isActiveRun(run WorkflowRun) bool {
+	return run.Status != "" && run.Status != "completed"
+}
+
+func completedRuns(runs []WorkflowRun) []WorkflowRun {
+	var completed []WorkflowRun
+	for _, run := range runs {
+		if !isActiveRun(run) {
+			completed = append(completed, run)
+		}
+	}
+	return completed
+}
@@ -0,0 +1,238 @@
+//go:build !integration
+
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func dashboardTestRuns(now time.Time) []WorkflowRun {
+	return []WorkflowRun{
+		{DatabaseID: 1, WorkflowPath: ".github/workflows/triage.lock.yml", Status: "completed", Conclusion: "success", CreatedAt: now.Add(-3 * time.Hour)},
+		{DatabaseID: 2, WorkflowPath: ".github/workflows/triage.lock.yml", Status: "completed", Conclusion: "failure", CreatedAt: now.Add(-2 * time.Hour)},
+		{DatabaseID: 3, WorkflowPath: ".github/workflows/triage.lock.yml", Status: "in_progress", CreatedAt: now.Add(-time.Minute)},
+		{DatabaseID: 4, WorkflowPath: ".github/workflows/docs.lock.yml", Status: "completed", Conclusion: "success", CreatedAt: now.Add(-time.Hour)},
+	}
+}
+
+func TestBuildDashboardWorkflows(t *testing.T) {
+	now := time.Now()
+	statuses := []WorkflowStatus{
+		{Workflow: "triage", EngineID: "copilot", Compiled: "Yes", Status: "active"},
+		{Workflow: "docs", EngineID: "claude", Compiled: "Yes", Status: "disabled"},
+		{Workflow: "unused", EngineID: "codex", Compiled: "No", Status: "active"},
+	}
+
+	workflows := buildDashboardWorkflows(statuses, dashboardTestRuns(now), 80)
+	require.Len(t, workflows, 3)
+	assert.Equal(t, []string{"docs", "triage", "unused"}, []string{workflows[0].ID, workflows[1].ID, workflows[2].ID}, "workflows should be sorted by ID")
+
+	triage := workflows[1]
+	require.Len(t, triage.Runs, 3)
+	assert.Equal(t, int64(3), triage.LatestRun().DatabaseID, "latest run should come first")
+	assert.Equal(t, 1, triage.ActiveRuns())
+	assert.Equal(t, 2, triage.Health.TotalRuns, "in-progress runs should not count toward health")
+	assert.Equal(t, 1, triage.Health.SuccessCount)
+	assert.True(t, triage.Health.BelowThresh)
+
+	assert.Nil(t, workflows[2].LatestRun())
+	assert.Equal(t, 0, workflows[2].Health.TotalRuns)
+}
+
+func TestMergeDashboardRuns(t *testing.T) {
+	now := time.Now()
+	statuses := []WorkflowStatus{{Workflow: "triage", Status: "active"}}
+	workflows := buildDashboardWorkflows(statuses, dashboardTestRuns(now), 80)
+
+	polled := []WorkflowRun{
+		{DatabaseID: 3, WorkflowPath: ".github/workflows/triage.lock.yml", Status: "completed", Conclusion: "success", CreatedAt: now.Add(-time.Minute)},
+		{DatabaseID: 5, WorkflowPath: ".github/workflows/triage.lock.yml", Status: "queued", CreatedAt: now},
+		{DatabaseID: 6, WorkflowPath: ".github/workflows/other.lock.yml", Status: "queued", CreatedAt: now},
+	}
+	merged := mergeDashboardRuns(workflows, polled, 80)
+
+	require.Len(t, merged, 1)
+	require.Len(t, merged[0].Runs, 4, "new runs should be added and known runs replaced")
+	assert.Equal(t, int64(5), merged[0].LatestRun().DatabaseID)
+	assert.Equal(t, 1, merged[0].ActiveRuns())
+	assert.Equal(t, 3, merged[0].Health.TotalRuns, "completed run should now count toward health")
+	assert.Len(t, workflows[0].Runs, 3, "original workflows should not be modified")
+}
+
+func newTestDashboardModel(t *testing.T, workflows []DashboardWorkflow) (dashboardModel, *[]string) {
+	t.Helper()
+	var calls []string
+	m := newDashboardModel(t.Context(), DashboardConfig{Days: 7, Threshold: 80, PollInterval: time.Second})
+	m.backend = dashboardBackend{
+		load: func() ([]DashboardWorkflow, error) { return workflows, nil },
+		poll: func() ([]WorkflowRun, error) { return nil, nil },
+		audit: func(run WorkflowRun) (AuditData, error) {
+			calls = append(calls, "audit")
+			return AuditData{Overview: OverviewData{WorkflowName: "triage", Status: "completed", Conclusion: "success"}}, nil
+		},
+		run: func(workflowID string) error { calls = append(calls, "run "+workflowID); return nil },
+		toggle: func(wf DashboardWorkflow) error {
+			calls = append(calls, "toggle "+wf.ID)
+			return errors.New("permission denied")
+		},
+		openWorkflow: func(workflowID string) error { calls = append(calls, "open "+workflowID); return nil },
+		openRun:      func(run WorkflowRun) error { return nil },
+	}
+	updated, _ := m.Update(dashboardLoadedMsg{workflows: workflows})
+	return updated.(dashboardModel), &calls
+}
+
+func sendDashboardKey(t *testing.T, m dashboardModel, key string) (dashboardModel, tea.Cmd) {
+	t.Helper()
+	var msg tea.KeyMsg
+	switch key {
+	case "enter":
+		msg = tea.KeyMsg{Type: tea.KeyEnter}
+	case "esc":
+		msg = tea.KeyMsg{Type: tea.KeyEsc}
+	default:
+		msg = tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)}
+	}
+	updated, cmd := m.Update(msg)
+	return updated.(dashboardModel), cmd
+}
+
+func TestDashboardModelNavigation(t *testing.T) {
+	now := time.Now()
+	workflows := buildDashboardWorkflows([]WorkflowStatus{
+		{Workflow: "docs", Status: "active"},
+		{Workflow: "triage", Status: "active"},
+	}, dashboardTestRuns(now), 80)
+	m, calls := newTestDashboardModel(t, workflows)
+
+	assert.False(t, m.loading)
+	assert.Contains(t, m.View(), "docs")
+
+	m, _ = sendDashboardKey(t, m, "k")
+	assert.Equal(t, 0, m.workflowCursor, "cursor should not move above the first workflow")
+	m, _ = sendDashboardKey(t, m, "j")
+	m, _ = sendDashboardKey(t, m, "j")
+	assert.Equal(t, 1, m.workflowCursor, "cursor should not move past the last workflow")
+
+	m, _ = sendDashboardKey(t, m, "enter")
+	assert.Equal(t, dashboardRunsView, m.view)
+	assert.Contains(t, m.View(), "Runs of triage")
+
+	// The latest run is in progress and cannot be audited yet
+	m, cmd := sendDashboardKey(t, m, "enter")
+	assert.Nil(t, cmd)
+	assert.Equal(t, dashboardRunsView, m.view)
+	assert.Contains(t, m.status, "still in_progress")
+
+	m, _ = sendDashboardKey(t, m, "j")
+	m, cmd = sendDashboardKey(t, m, "enter")
+	require.NotNil(t, cmd)
+	assert.Equal(t, dashboardAuditView, m.view)
+	assert.Equal(t, int64(2), m.auditRunID)
+
+	updated, _ := m.Update(cmd())
+	m = updated.(dashboardModel)
+	assert.False(t, m.loading)
+	assert.Contains(t, m.auditContent, "triage · success")
+	assert.Equal(t, []string{"audit"}, *calls)
+
+	m, _ = sendDashboardKey(t, m, "esc")
+	assert.Equal(t, dashboardRunsView, m.view)
+	m, _ = sendDashboardKey(t, m, "esc")
+	assert.Equal(t, dashboardWorkflowsView, m.view)
+
+	_, cmd = sendDashboardKey(t, m, "q")
+	require.NotNil(t, cmd)
+	assert.IsType(t, tea.QuitMsg{}, cmd())
+}
+
+func TestDashboardModelActions(t *testing.T) {
+	workflows := buildDashboardWorkflows([]WorkflowStatus{{Workflow: "triage", Status: "active"}}, nil, 80)
+	m, calls := newTestDashboardModel(t, workflows)
+
+	m, cmd := sendDashboardKey(t, m, "r")
+	assert.Nil(t, cmd, "running a workflow should wait for confirmation")
+	assert.Equal(t, "Run triage on GitHub Actions? (y/n)", m.status)
+	assert.Contains(t, m.View(), "y confirm")
+	m, cmd = sendDashboardKey(t, m, "y")
+	require.NotNil(t, cmd)
+	updated, reload := m.Update(cmd())
+	m = updated.(dashboardModel)
+	assert.Equal(t, "Started a run of triage", m.status)
+	assert.True(t, m.loading)
+	assert.NotNil(t, reload, "running a workflow should reload the dashboard")
+
+	m, cmd = sendDashboardKey(t, m, "e")
+	assert.Nil(t, cmd, "disabling a workflow should wait for confirmation")
+	assert.Equal(t, "Disable triage? (y/n)", m.status)
+	m, cmd = sendDashboardKey(t, m, "n")
+	assert.Nil(t, cmd, "declining should not disable the workflow")
+	assert.Equal(t, "Cancelled", m.status)
+	assert.Nil(t, m.pending)
+
+	m, _ = sendDashboardKey(t, m, "e")
+	m, cmd = sendDashboardKey(t, m, "y")
+	require.NotNil(t, cmd)
+	updated, _ = m.Update(cmd())
+	m = updated.(dashboardModel)
+	assert.True(t, m.statusError)
+	assert.Equal(t, "permission denied", m.status)
+
+	m, cmd = sendDashboardKey(t, m, "o")
+	require.NotNil(t, cmd)
+	updated, _ = m.Update(cmd())
+	m = updated.(dashboardModel)
+	assert.False(t, m.statusError)
+
+	assert.Equal(t, []string{"run triage", "toggle triage", "open triage"}, *calls)
+}
+
+func TestCaptureDashboardStderr(t *testing.T) {
+	original := os.Stderr
+	err := captureDashboardStderr(func() error {
+		fmt.Fprintln(os.Stderr, "Lock file is outdated")
+		fmt.Fprintln(os.Stderr, "gh: HTTP 403: Resource not accessible")
+		return errors.New("failed to run workflow")
+	})
+	require.Error(t, err)
+	assert.Equal(t, "failed to run workflow: gh: HTTP 403: Resource not accessible", err.Error(), "the last line of output should explain the error")
+	assert.Same(t, original, os.Stderr, "stderr should be restored")
+
+	err = captureDashboardStderr(func() error {
+		fmt.Fprintln(os.Stderr, "Successfully triggered workflow")
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Same(t, original, os.Stderr, "stderr should be restored")
+}
+
+func TestRenderDashboardAudit(t *testing.T) {
+	audit := AuditData{
+		Overview:    OverviewData{WorkflowName: "triage", Status: "completed", Conclusion: "failure", Event: "issues", Branch: "main"},
+		KeyFindings: []Finding{{Category: "error", Severity: "high", Title: "Multiple errors", Description: "3 errors were logged"}},
+		ToolUsage:   []ToolUsageInfo{{Name: "github.list_issues", CallCount: 2}, {Name: "bash", CallCount: 5}},
+		FirewallAnalysis: &FirewallAnalysis{
+			TotalRequests: 4, AllowedRequests: 3, BlockedRequests: 1,
+			DomainBuckets: DomainBuckets{BlockedDomains: []string{"evil.example.com:443"}},
+		},
+		CreatedItems: []CreatedItemReport{{Type: "add_comment", URL: "https://github.com/octo/repo/issues/1#issuecomment-1"}},
+	}
+
+	output := renderDashboardAudit(audit)
+	assert.Contains(t, output, "triage · failure · issues on main")
+	assert.Contains(t, output, "Multiple errors")
+	assert.Contains(t, output, "3 errors were logged")
+	assert.Less(t, strings.Index(output, "bash"), strings.Index(output, "github.list_issues"), "tools should be sorted by call count")
+	assert.Contains(t, output, "4 requests · 3 allowed · 1 blocked")
+	assert.Contains(t, output, "evil.example.com:443")
+	assert.Contains(t, output, "https://github.com/octo/repo/issues/1#issuecomment-1")
+}
@@ -0,0 +1,672 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/github/gh-aw/pkg/styles"
+	"github.com/github/gh-aw/pkg/timeutil"
+)
+
+// dashboardView is the screen shown by the dashboard
+type dashboardView int
+
+const (
+	dashboardWorkflowsView dashboardView = iota
+	dashboardRunsView
+	dashboardAuditView
+)
+
+// dashboardBackend provides the data and actions of the dashboard; tests replace it with fakes
+type dashboardBackend struct {
+	load         func() ([]DashboardWorkflow, error)
+	poll         func() ([]WorkflowRun, error)
+	audit        func(run WorkflowRun) (AuditData, error)
+	run          func(workflowID string) error
+	toggle       func(wf DashboardWorkflow) error
+	openWorkflow func(workflowID string) error
+	openRun      func(run WorkflowRun) error
+}
+
+type dashboardLoadedMsg struct {
+	workflows []DashboardWorkflow
+	err       error
+}
+
+type dashboardPolledMsg struct {
+	runs []WorkflowRun
+	err  error
+}
+
+type dashboardTickMsg time.Time
+
+type dashboardAuditMsg struct {
+	runID int64
+	audit AuditData
+	err   error
+}
+
+type dashboardActionMsg struct {
+	message string
+	err     error
+	reload  bool
+}
+
+// dashboardPendingAction is an action that changes the repository and waits for confirmation
+type dashboardPendingAction struct {
+	progress string // Status shown while the action runs
+	message  string
+	action   func() error
+}
+
+// dashboardModel is the Bubble Tea model of the dashboard
+type dashboardModel struct {
+	config    DashboardConfig
+	backend   dashboardBackend
+	workflows []DashboardWorkflow
+
+	view           dashboardView
+	workflowCursor int
+	runCursor      int
+	auditRunID     int64
+	auditContent   string
+	viewport       viewport.Model
+
+	loading     bool
+	polling     bool
+	pending     *dashboardPendingAction
+	status      string
+	statusError bool
+	lastRefresh time.Time
+	width       int
+	height      int
+}
+
+func newDashboardModel(ctx context.Context, config DashboardConfig) dashboardModel {
+	return dashboardModel{
+		config:  config,
+		loading: true,
+		backend: dashboardBackend{
+			load:         func() ([]DashboardWorkflow, error) { return loadDashboardWorkflows(config) },
+			poll:         func() ([]WorkflowRun, error) { return pollDashboardRuns(config) },
+			audit:        func(run WorkflowRun) (AuditData, error) { return loadDashboardAudit(run, config.OutputDir) },
+			run:          func(workflowID string) error { return runDashboardWorkflow(ctx, workflowID, config) },
+			toggle:       func(wf DashboardWorkflow) error { return toggleDashboardWorkflow(wf, config) },
+			openWorkflow: func(workflowID string) error { return openDashboardWorkflow(workflowID, config) },
+			openRun:      func(run WorkflowRun) error { return openDashboardRun(run, config) },
+		},
+		viewport: viewport.New(80, 20),
+	}
+}
+
+// Init loads the workflows and starts polling
+func (m dashboardModel) Init() tea.Cmd {
+	return tea.Batch(m.loadCmd(), m.tickCmd())
+}
+
+func (m dashboardModel) loadCmd() tea.Cmd {
+	load := m.backend.load
+	return func() tea.Msg {
+		workflows, err := load()
+		return dashboardLoadedMsg{workflows: workflows, err: err}
+	}
+}
+
+func (m dashboardModel) tickCmd() tea.Cmd {
+	return tea.Tick(m.config.PollInterval, func(t time.Time) tea.Msg { return dashboardTickMsg(t) })
+}
+
+// Update handles messages and updates the model
+func (m dashboardModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width, m.height = msg.Width, msg.Height
+		m.viewport.Width = msg.Width
+		m.viewport.Height = max(msg.Height-4, 1)
+		return m, nil
+
+	case dashboardLoadedMsg:
+		m.loading = false
+		if msg.err != nil {
+			m.setStatus("Failed to load workflows: "+msg.err.Error(), true)
+			return m, nil
+		}
+		m.workflows = msg.workflows
+		m.lastRefresh = time.Now()
+		m.clampCursors()
+		return m, nil
+
+	case dashboardTickMsg:
+		if m.polling || m.loading {
+			return m, m.tickCmd()
+		}
+		m.polling = true
+		poll := m.backend.poll
+		return m, tea.Batch(m.tickCmd(), func() tea.Msg {
+			runs, err := poll()
+			return dashboardPolledMsg{runs: runs, err: err}
+		})
+
+	case dashboardPolledMsg:
+		m.polling = false
+		if msg.err != nil {
+			m.setStatus("Failed to refresh runs: "+msg.err.Error(), true)
+			return m, nil
+		}
+		m.workflows = mergeDashboardRuns(m.workflows, msg.runs, m.config.Threshold)
+		m.lastRefresh = time.Now()
+		m.clampCursors()
+		return m, nil
+
+	case dashboardAuditMsg:
+		if msg.runID != m.auditRunID {
+			return m, nil
+		}
+		m.loading = false
+		if msg.err != nil {
+			m.view = dashboardRunsView
+			m.setStatus(fmt.Sprintf("Failed to audit run %d: %v", msg.runID, msg.err), true)
+			return m, nil
+		}
+		m.auditContent = renderDashboardAudit(msg.audit)
+		m.viewport.SetContent(m.auditContent)
+		m.viewport.GotoTop()
+		return m, nil
+
+	case dashboardActionMsg:
+		if msg.err != nil {
+			m.setStatus(msg.err.Error(), true)
+			return m, nil
+		}
+		m.setStatus(msg.message, false)
+		if msg.reload {
+			m.loading = true
+			return m, m.loadCmd()
+		}
+		return m, nil
+
+	case tea.KeyMsg:
+		return m.handleKey(msg)
+	}
+
+	if m.view == dashboardAuditView {
+		var cmd tea.Cmd
+		m.viewport, cmd = m.viewport.Update(msg)
+		return m, cmd
+	}
+	return m, nil
+}
+
+func (m dashboardModel) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.pending != nil && msg.String() != "ctrl+c" {
+		pending := *m.pending
+		m.pending = nil
+		if msg.String() != "y" && msg.String() != "Y" {
+			m.setStatus("Cancelled", false)
+			return m, nil
+		}
+		m.setStatus(pending.progress, false)
+		return m, m.actionCmd(pending.message, true, pending.action)
+	}
+
+	switch msg.String() {
+	case "ctrl+c", "q":
+		return m, tea.Quit
+	case "ctrl+r":
+		m.loading = true
+		m.setStatus("Refreshing...", false)
+		return m, m.loadCmd()
+	case "esc", "backspace":
+		switch m.view {
+		case dashboardAuditView:
+			m.view = dashboardRunsView
+			m.auditRunID = 0
+			m.loading = false
+		case dashboardRunsView:
+			m.view = dashboardWorkflowsView
+		}
+		return m, nil
+	}
+
+	wf := m.selectedWorkflow()
+	if wf == nil {
+		return m, nil
+	}
+
+	switch m.view {
+	case dashboardWorkflowsView:
+		switch msg.String() {
+		case "up", "k":
+			m.workflowCursor = max(m.workflowCursor-1, 0)
+		case "down", "j":
+			m.workflowCursor = min(m.workflowCursor+1, len(m.workflows)-1)
+		case "enter":
+			m.view = dashboardRunsView
+			m.runCursor = 0
+		case "o":
+			return m, m.actionCmd("Opened "+wf.ID+" in the browser", false, func() error { return m.backend.openWorkflow(wf.ID) })
+		case "r":
+			return m.runSelectedWorkflow(*wf)
+		case "e":
+			return m.toggleSelectedWorkflow(*wf)
+		}
+
+	case dashboardRunsView:
+		run := m.selectedRun()
+		switch msg.String() {
+		case "up", "k":
+			m.runCursor = max(m.runCursor-1, 0)
+		case "down", "j":
+			m.runCursor = min(m.runCursor+1, max(len(wf.Runs)-1, 0))
+		case "enter":
+			if run == nil {
+				return m, nil
+			}
+			if isActiveRun(*run) {
+				m.setStatus(fmt.Sprintf("Run %d is still %s; its audit is available once it completes", run.DatabaseID, run.Status), false)
+				return m, nil
+			}
+			m.view = dashboardAuditView
+			m.auditRunID = run.DatabaseID
+			m.auditContent = ""
+			m.loading = true
+			selected := *run
+			audit := m.backend.audit
+			return m, func() tea.Msg {
+				data, err := audit(selected)
+				return dashboardAuditMsg{runID: selected.DatabaseID, audit: data, err: err}
+			}
+		case "o":
+			if run != nil {
+				selected := *run
+				return m, m.actionCmd(fmt.Sprintf("Opened run %d in the browser", selected.DatabaseID), false, func() error { return m.backend.openRun(selected) })
+			}
+		case "r":
+			return m.runSelectedWorkflow(*wf)
+		case "e":
+			return m.toggleSelectedWorkflow(*wf)
+		}
+
+	case dashboardAuditView:
+		if msg.String() == "o" {
+			if run := m.selectedRun(); run != nil {
+				selected := *run
+				return m, m.actionCmd(fmt.Sprintf("Opened run %d in the browser", selected.DatabaseID), false, func() error { return m.backend.openRun(selected) })
+			}
+			return m, nil
+		}
+		var cmd tea.Cmd
+		m.viewport, cmd = m.viewport.Update(msg)
+		return m, cmd
+	}
+	return m, nil
+}
+
+// runSelectedWorkflow asks for confirmation before running a workflow
+func (m dashboardModel) runSelectedWorkflow(wf DashboardWorkflow) (tea.Model, tea.Cmd) {
+	run := m.backend.run
+	m.pending = &dashboardPendingAction{
+		progress: "Starting " + wf.ID + "...",
+		message:  "Started a run of " + wf.ID,
+		action:   func() error { return run(wf.ID) },
+	}
+	m.setStatus("Run "+wf.ID+" on GitHub Actions? (y/n)", false)
+	return m, nil
+}
+
+// toggleSelectedWorkflow asks for confirmation before enabling or disabling a workflow
+func (m dashboardModel) toggleSelectedWorkflow(wf DashboardWorkflow) (tea.Model, tea.Cmd) {
+	verb, progress, action := "Enable", "Enabling", "Enabled"
+	if wf.State == "active" {
+		verb, progress, action = "Disable", "Disabling", "Disabled"
+	}
+	toggle := m.backend.toggle
+	m.pending = &dashboardPendingAction{
+		progress: progress + " " + wf.ID + "...",
+		message:  action + " " + wf.ID,
+		action:   func() error { return toggle(wf) },
+	}
+	m.setStatus(verb+" "+wf.ID+"? (y/n)", false)
+	return m, nil
+}
+
+func (m dashboardModel) actionCmd(message string, reload bool, action func() error) tea.Cmd {
+	return func() tea.Msg {
+		return dashboardActionMsg{message: message, err: action(), reload: reload}
+	}
+}
+
+func (m *dashboardModel) setStatus(status string, isError bool) {
+	m.status = status
+	m.statusError = isError
+}
+
+func (m *dashboardModel) clampCursors() {
+	m.workflowCursor = min(m.workflowCursor, max(len(m.workflows)-1, 0))
+	if wf := m.selectedWorkflow(); wf != nil {
+		m.runCursor = min(m.runCursor, max(len(wf.Runs)-1, 0))
+	}
+}
+
+func (m dashboardModel) selectedWorkflow() *DashboardWorkflow {
+	if m.workflowCursor < 0 || m.workflowCursor >= len(m.workflows) {
+		return nil
+	}
+	return &m.workflows[m.workflowCursor]
+}
+
+func (m dashboardModel) selectedRun() *WorkflowRun {
+	wf := m.selectedWorkflow()
+	if wf == nil || m.runCursor < 0 || m.runCursor >= len(wf.Runs) {
+		return nil
+	}
+	return &wf.Runs[m.runCursor]
+}
+
+// View renders the current screen
+func (m dashboardModel) View() string {
+	var sb strings.Builder
+	sb.WriteString(m.renderHeader())
+	sb.WriteString("\n\n")
+
+	switch {
+	case m.loading && m.view != dashboardAuditView && len(m.workflows) == 0:
+		sb.WriteString(styles.Progress.Render("Loading workflows and runs..."))
+		sb.WriteString("\n")
+	case m.view == dashboardWorkflowsView:
+		sb.WriteString(renderDashboardWorkflows(m.workflows, m.workflowCursor))
+	case m.view == dashboardRunsView:
+		if wf := m.selectedWorkflow(); wf != nil {
+			sb.WriteString(renderDashboardRuns(*wf, m.runCursor, time.Now()))
+		}
+	case m.view == dashboardAuditView:
+		if m.loading {
+			sb.WriteString(styles.Progress.Render(fmt.Sprintf("Downloading and analyzing run %d...", m.auditRunID)))
+			sb.WriteString("\n")
+		} else {
+			sb.WriteString(m.viewport.View())
+			sb.WriteString("\n")
+		}
+	}
+
+	sb.WriteString("\n")
+	sb.WriteString(m.renderFooter())
+	return sb.String()
+}
+
+func (m dashboardModel) renderHeader() string {
+	repo := m.config.RepoOverride
+	if repo == "" {
+		repo = "current repository"
+	}
+	title := "Agentic workflows"
+	switch m.view {
+	case dashboardRunsView:
+		if wf := m.selectedWorkflow(); wf != nil {
+			title = "Runs of " + wf.ID
+		}
+	case dashboardAuditView:
+		title = fmt.Sprintf("Audit of run %d", m.auditRunID)
+	}
+	details := fmt.Sprintf("%s · last %d days", repo, m.config.Days)
+	if !m.lastRefresh.IsZero() {
+		details += " · refreshed " + m.lastRefresh.Format("15:04:05")
+	}
+	if m.polling {
+		details += " · polling"
+	}
+	return styles.Header.Render(title) + "  " + styles.Verbose.Render(details)
+}
+
+func (m dashboardModel) renderFooter() string {
+	var keys string
+	switch {
+	case m.pending != nil:
+		keys = "y confirm · any other key cancels"
+	case m.view == dashboardWorkflowsView:
+		keys = "↑/↓ move · enter runs · r run · e enable/disable · o open · ctrl+r refresh · q quit"
+	case m.view == dashboardRunsView:
+		keys = "↑/↓ move · enter audit · r run · e enable/disable · o open · esc back · q quit"
+	case m.view == dashboardAuditView:
+		keys = "↑/↓ scroll · o open · esc back · q quit"
+	}
+	footer := styles.Verbose.Render(keys)
+	if m.status != "" {
+		status := styles.Info.Render(m.status)
+		if m.statusError {
+			status = styles.Error.Render(m.status)
+		}
+		footer = status + "\n" + footer
+	}
+	return footer
+}
+
+// renderDashboardWorkflows renders the workflow table with the selected row highlighted
+func renderDashboardWorkflows(workflows []DashboardWorkflow, cursor int) string {
+	if len(workflows) == 0 {
+		return styles.Warning.Render("No agentic workflows found in .github/workflows") + "\n"
+	}
+
+	headers := []string{"Workflow", "Engine", "State", "Success Rate", "Trend", "Active", "Latest Run"}
+	rows := make([][]string, 0, len(workflows))
+	for _, wf := range workflows {
+		latest := "-"
+		if run := wf.LatestRun(); run != nil {
+			latest = fmt.Sprintf("%s %s", runOutcome(*run), formatRunAge(run.CreatedAt, time.Now()))
+		}
+		active := "-"
+		if count := wf.ActiveRuns(); count > 0 {
+			active = fmt.Sprintf("%d", count)
+		}
+		rows = append(rows, []string{wf.ID, wf.Engine, wf.State, wf.Health.DisplayRate, wf.Health.Trend, active, latest})
+	}
+
+	return renderDashboardTable(headers, rows, cursor, func(row int, line string) string {
+		if workflows[row].Health.BelowThresh && workflows[row].Health.TotalRuns > 0 {
+			return styles.Warning.Render(line)
+		}
+		return line
+	})
+}
+
+// renderDashboardRuns renders the runs of a workflow with the selected row highlighted
+func renderDashboardRuns(wf DashboardWorkflow, cursor int, now time.Time) string {
+	summary := fmt.Sprintf("Success rate %s · trend %s · avg duration %s · avg cost %s",
+		wf.Health.DisplayRate, wf.Health.Trend, wf.Health.DisplayDur, wf.Health.DisplayCost)
+	if len(wf.Runs) == 0 {
+		return summary + "\n\n" + styles.Warning.Render("No runs in this period") + "\n"
+	}
+
+	headers := []string{"Run ID", "Status", "Event", "Branch", "Duration", "Created", "Title"}
+	rows := make([][]string, 0, len(wf.Runs))
+	for _, run := range wf.Runs {
+		duration := "-"
+		if run.Duration > 0 {
+			duration = timeutil.FormatDuration(run.Duration)
+		} else if isActiveRun(run) && !run.StartedAt.IsZero() {
+			duration = timeutil.FormatDuration(now.Sub(run.StartedAt))
+		}
+		rows = append(rows, []string{
+			fmt.Sprintf("%d", run.DatabaseID), runOutcome(run), run.Event, run.HeadBranch,
+			duration, formatRunAge(run.CreatedAt, now), run.DisplayTitle,
+		})
+	}
+
+	return summary + "\n\n" + renderDashboardTable(headers, rows, cursor, func(row int, line string) string {
+		switch {
+		case isActiveRun(wf.Runs[row]):
+			return styles.Info.Render(line)
+		case isFailureConclusion(wf.Runs[row].Conclusion):
+			return styles.Error.Render(line)
+		}
+		return line
+	})
+}
+
+// renderDashboardTable renders rows as aligned columns. Long cells are truncated so each row
+// stays on one line; style is applied to each non-selected row after padding.
+func renderDashboardTable(headers []string, rows [][]string, cursor int, style func(row int, line string) string) string {
+	const maxCellWidth = 40
+	widths := make([]int, len(headers))
+	for i, header := range headers {
+		widths[i] = lipgloss.Width(header)
+	}
+	for _, row := range rows {
+		for i, cell := range row {
+			widths[i] = min(max(widths[i], lipgloss.Width(cell)), maxCellWidth)
+		}
+	}
+
+	format := func(cells []string) string {
+		padded := make([]string, len(cells))
+		for i, cell := range cells {
+			if lipgloss.Width(cell) > widths[i] {
+				cell = string([]rune(cell)[:widths[i]-1]) + "…"
+			}
+			padded[i] = cell + strings.Repeat(" ", widths[i]-lipgloss.Width(cell))
+		}
+		return strings.Join(padded, "  ")
+	}
+
+	var sb strings.Builder
+	sb.WriteString(styles.TableHeader.Render(format(headers)))
+	sb.WriteString("\n")
+	selected := lipgloss.NewStyle().Reverse(true).Bold(true)
+	for i, row := range rows {
+		line := format(row)
+		if i == cursor {
+			sb.WriteString(selected.Render(line))
+		} else {
+			sb.WriteString(style(i, line))
+		}
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+// renderDashboardAudit renders the audit of a run as scrollable text
+func renderDashboardAudit(audit AuditData) string {
+	var sb strings.Builder
+	section := func(title string) {
+		sb.WriteString("\n")
+		sb.WriteString(styles.ListHeader.Render(title))
+		sb.WriteString("\n")
+	}
+
+	overview := audit.Overview
+	outcome := overview.Status
+	if overview.Conclusion != "" {
+		outcome = overview.Conclusion
+	}
+	fmt.Fprintf(&sb, "%s · %s · %s on %s\n", overview.WorkflowName, outcome, overview.Event, overview.Branch)
+	if overview.Duration != "" {
+		fmt.Fprintf(&sb, "Duration %s · ", overview.Duration)
+	}
+	fmt.Fprintf(&sb, "Tokens %s · Cost %s · Turns %d · Errors %d · Warnings %d\n",
+		formatTokens(audit.Metrics.TokenUsage), formatCost(audit.Metrics.EstimatedCost), audit.Metrics.Turns, audit.Metrics.ErrorCount, audit.Metrics.WarningCount)
+	sb.WriteString(styles.Verbose.Render(overview.URL))
+	sb.WriteString("\n")
+
+	if len(audit.KeyFindings) > 0 {
+		section("Key Findings")
+		for _, finding := range audit.KeyFindings {
+			fmt.Fprintf(&sb, "  %s %s\n", severityStyle(finding.Severity).Render("["+finding.Severity+"]"), finding.Title)
+			if finding.Description != "" {
+				fmt.Fprintf(&sb, "    %s\n", finding.Description)
+			}
+		}
+	}
+
+	if len(audit.Recommendations) > 0 {
+		section("Recommendations")
+		for _, recommendation := range audit.Recommendations {
+			fmt.Fprintf(&sb, "  [%s] %s\n", recommendation.Priority, recommendation.Action)
+		}
+	}
+
+	if len(audit.ToolUsage) > 0 {
+		section("Tool Usage")
+		tools := append([]ToolUsageInfo{}, audit.ToolUsage...)
+		sort.SliceStable(tools, func(i, j int) bool { return tools[i].CallCount > tools[j].CallCount })
+		for _, tool := range tools {
+			fmt.Fprintf(&sb, "  %-40s %d calls\n", tool.Name, tool.CallCount)
+		}
+	}
+
+	if len(audit.MissingTools) > 0 {
+		section("Missing Tools")
+		for _, missing := range audit.MissingTools {
+			fmt.Fprintf(&sb, "  %s: %s\n", missing.Tool, missing.Reason)
+		}
+	}
+
+	if firewall := audit.FirewallAnalysis; firewall != nil {
+		section("Firewall")
+		fmt.Fprintf(&sb, "  %d requests · %d allowed · %d blocked\n", firewall.TotalRequests, firewall.AllowedRequests, firewall.BlockedRequests)
+		for _, domain := range firewall.BlockedDomains {
+			fmt.Fprintf(&sb, "  %s %s\n", styles.Error.Render("blocked"), domain)
+		}
+		for _, domain := range firewall.AllowedDomains {
+			fmt.Fprintf(&sb, "  %s %s\n", styles.Success.Render("allowed"), domain)
+		}
+	}
+
+	if len(audit.CreatedItems) > 0 {
+		section("Created Items")
+		for _, item := range audit.CreatedItems {
+			fmt.Fprintf(&sb, "  %-24s %s\n", item.Type, item.URL)
+		}
+	}
+
+	if len(audit.Errors) > 0 {
+		section("Errors")
+		for _, errInfo := range audit.Errors {
+			fmt.Fprintf(&sb, "  %s\n", errInfo.Message)
+		}
+	}
+
+	return sb.String()
+}
+
+func severityStyle(severity string) lipgloss.Style {
+	switch severity {
+	case "critical", "high":
+		return styles.Error
+	case "medium":
+		return styles.Warning
+	default:
+		return styles.Info
+	}
+}
+
+// runOutcome returns the conclusion of a completed run or the status of an active run
+func runOutcome(run WorkflowRun) string {
+	if run.Conclusion != "" {
+		return run.Conclusion
+	}
+	return run.Status
+}
+
+// formatRunAge formats the time elapsed since t, e.g. "5m ago"
+func formatRunAge(t time.Time, now time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+	elapsed := now.Sub(t)
+	switch {
+	case elapsed < time.Minute:
+		return "just now"
+	case elapsed < time.Hour:
+		return fmt.Sprintf("%dm ago", int(elapsed.Minutes()))
+	case elapsed < 24*time.Hour:
+		return fmt.Sprintf("%dh ago", int(elapsed.Hours()))
+	default:
+		return fmt.Sprintf("%dd ago", int(elapsed.Hours()/24))
+	}
+}
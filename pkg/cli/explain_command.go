@@ -0,0 +1,78 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/github/gh-aw/pkg/console"
+	"github.com/github/gh-aw/pkg/logger"
+	"github.com/github/gh-aw/pkg/workflow"
+	"github.com/spf13/cobra"
+)
+
+var explainLog = logger.New("cli:explain")
+
+// NewExplainCommand creates the explain command
+func NewExplainCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "explain <workflow>",
+		Short: "Show the effective configuration of a workflow and where each setting came from",
+		Long: `Show the effective configuration of a workflow after imports, defaults and compiler
+adjustments are applied, annotated with the origin of each setting.
+
+The permissions, network, sandbox, tools, MCP servers, safe outputs, runtimes and features
+of the workflow are listed with one of these origins:
+  • frontmatter   - set in the workflow's frontmatter (file and line)
+  • import        - contributed by an imported file (file and line)
+  • default       - filled in by a compiler default
+  • auto-injected - added by the compiler, e.g. the default create-issue safe output
+  • codemod       - rewritten from a deprecated form, e.g. the SRT sandbox
+
+The workflow is compiled in memory; no lock file is written.
+
+Examples:
+  gh aw explain daily-status                        # Explain .github/workflows/daily-status.md
+  gh aw explain .github/workflows/triage.md         # Explain a workflow file
+  gh aw explain daily-status --json                 # Output in JSON format`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			verbose, _ := cmd.Flags().GetBool("verbose")
+			jsonOutput, _ := cmd.Flags().GetBool("json")
+			return RunExplain(args[0], jsonOutput, verbose)
+		},
+	}
+
+	addJSONFlag(cmd)
+
+	return cmd
+}
+
+// RunExplain prints the effective configuration of a workflow with the provenance of each setting
+func RunExplain(workflowName string, jsonOutput bool, verbose bool) error {
+	explainLog.Printf("Explaining workflow: %s", workflowName)
+
+	workflowPath, err := resolveWorkflowFile(workflowName, verbose)
+	if err != nil {
+		return err
+	}
+
+	compiler := workflow.NewCompiler(workflow.WithVerbose(verbose))
+	explanation, err := compiler.ExplainWorkflow(workflowPath)
+	if err != nil {
+		return err
+	}
+
+	if jsonOutput {
+		data, err := json.MarshalIndent(explanation, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal explanation: %w", err)
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	fmt.Fprintln(os.Stderr, console.FormatInfoMessage("Effective configuration of "+explanation.Workflow+":"))
+	fmt.Print(console.RenderStruct(explanation.Settings))
+	return nil
+}
@@ -31,3 +31,9 @@ func addOutputFlag(cmd *cobra.Command, defaultValue string) {
 func addJSONFlag(cmd *cobra.Command) {
 	cmd.Flags().BoolP("json", "j", false, "Output results in JSON format")
 }
+
+// addFormatFlag adds the --format flag to a command.
+// This flag selects console, JSON or self-contained HTML report output; --json is shorthand for --format json.
+func addFormatFlag(cmd *cobra.Command) {
+	cmd.Flags().String("format", reportFormatConsole, "Output format: console, json, or html (writes a self-contained report.html to the output directory)")
+}
@@ -452,3 +452,183 @@ func SetFieldInOnTrigger(content, fieldName, fieldValue string) (string, error)
 	frontmatterEditorLog.Printf("No raw frontmatter lines available")
 	return "", errors.New("no frontmatter lines available to modify")
 }
+
+// ReplaceBlockInFrontmatter replaces a top-level field of the frontmatter, whether it holds an inline
+// value or a nested block, with the given nested block lines. The lines are indented by two spaces
+// under the field. If the field does not exist, it is added at the end of the frontmatter.
+// Other lines, including comments and blank lines, are preserved.
+func ReplaceBlockInFrontmatter(content, fieldName string, blockLines []string) (string, error) {
+	frontmatterEditorLog.Printf("Replacing frontmatter block: %s (%d lines)", fieldName, len(blockLines))
+
+	result, err := parser.ExtractFrontmatterFromContent(content)
+	if err != nil {
+		frontmatterEditorLog.Printf("Failed to parse frontmatter: %v", err)
+		return "", fmt.Errorf("failed to parse frontmatter: %w", err)
+	}
+	if len(result.FrontmatterLines) == 0 {
+		return "", errors.New("no frontmatter lines available to modify")
+	}
+
+	newBlock := make([]string, 0, len(blockLines)+1)
+	newBlock = append(newBlock, fieldName+":")
+	for _, blockLine := range blockLines {
+		newBlock = append(newBlock, "  "+blockLine)
+	}
+
+	frontmatterLines := make([]string, 0, len(result.FrontmatterLines)+len(blockLines))
+	inBlock := false
+	replaced := false
+	for _, line := range result.FrontmatterLines {
+		trimmedLine := strings.TrimSpace(line)
+		isTopLevel := trimmedLine != "" && !strings.HasPrefix(trimmedLine, "#") && len(line) == len(strings.TrimLeft(line, " \t"))
+
+		if inBlock {
+			// Skip the nested lines of the block until the next top-level key
+			if !isTopLevel {
+				continue
+			}
+			inBlock = false
+		}
+
+		if !replaced && isTopLevel && (trimmedLine == fieldName+":" || strings.HasPrefix(trimmedLine, fieldName+": ") || strings.HasPrefix(trimmedLine, fieldName+":\t")) {
+			frontmatterLines = append(frontmatterLines, newBlock...)
+			inBlock = true
+			replaced = true
+			continue
+		}
+
+		frontmatterLines = append(frontmatterLines, line)
+	}
+
+	if !replaced {
+		frontmatterLines = append(frontmatterLines, newBlock...)
+		frontmatterEditorLog.Printf("Added new block %s at end of frontmatter", fieldName)
+	}
+
+	var lines []string
+	lines = append(lines, "---")
+	lines = append(lines, frontmatterLines...)
+	lines = append(lines, "---")
+	if result.Markdown != "" {
+		// Add empty line before markdown content to match original format
+		lines = append(lines, "")
+		lines = append(lines, result.Markdown)
+	}
+
+	return strings.Join(lines, "\n"), nil
+}
+
+// SetListInFrontmatterBlock sets a list field nested under a top-level block of the frontmatter,
+// e.g. network.allowed. The existing list, in block or flow style, is replaced. If the parent holds
+// an inline value, it is replaced by a block with only the list; if it does not exist, it is added
+// at the end of the frontmatter. Other fields of the parent block are preserved.
+func SetListInFrontmatterBlock(content, parentField, fieldName string, values []string) (string, error) {
+	frontmatterEditorLog.Printf("Setting list %s.%s (%d values)", parentField, fieldName, len(values))
+
+	result, err := parser.ExtractFrontmatterFromContent(content)
+	if err != nil {
+		frontmatterEditorLog.Printf("Failed to parse frontmatter: %v", err)
+		return "", fmt.Errorf("failed to parse frontmatter: %w", err)
+	}
+	if len(result.FrontmatterLines) == 0 {
+		return "", errors.New("no frontmatter lines available to modify")
+	}
+
+	renderList := func(indent string) []string {
+		if len(values) == 0 {
+			return []string{indent + fieldName + ": []"}
+		}
+		lines := []string{indent + fieldName + ":"}
+		for _, value := range values {
+			if strings.HasPrefix(value, "*") {
+				value = fmt.Sprintf("%q", value)
+			}
+			lines = append(lines, indent+"  - "+value)
+		}
+		return lines
+	}
+	indentOf := func(line string) int {
+		return len(line) - len(strings.TrimLeft(line, " \t"))
+	}
+	isKey := func(trimmedLine, key string) bool {
+		return trimmedLine == key+":" || strings.HasPrefix(trimmedLine, key+": ") || strings.HasPrefix(trimmedLine, key+":\t")
+	}
+
+	frontmatterLines := make([]string, 0, len(result.FrontmatterLines)+len(values)+2)
+	inParent := false
+	inField := false
+	fieldIndent := 0
+	childIndent := ""
+	fieldSet := false
+	parentFound := false
+	for _, line := range result.FrontmatterLines {
+		trimmedLine := strings.TrimSpace(line)
+		isContent := trimmedLine != "" && !strings.HasPrefix(trimmedLine, "#")
+
+		if inField {
+			// Skip the items of the replaced list, including items at the same indentation as the key
+			if !isContent || indentOf(line) > fieldIndent || (indentOf(line) == fieldIndent && strings.HasPrefix(trimmedLine, "- ")) {
+				continue
+			}
+			inField = false
+		}
+
+		if inParent && isContent {
+			if indentOf(line) == 0 {
+				// Leaving the parent block: add the field if it was not found
+				inParent = false
+				if !fieldSet {
+					frontmatterLines = append(frontmatterLines, renderList(childIndent)...)
+					fieldSet = true
+				}
+			} else if !fieldSet && isKey(trimmedLine, fieldName) {
+				fieldIndent = indentOf(line)
+				frontmatterLines = append(frontmatterLines, renderList(line[:fieldIndent])...)
+				inField = true
+				fieldSet = true
+				continue
+			} else if childIndent == "" {
+				childIndent = line[:indentOf(line)]
+			}
+		}
+
+		if !parentFound && isContent && indentOf(line) == 0 && isKey(trimmedLine, parentField) {
+			parentFound = true
+			if trimmedLine == parentField+":" || strings.HasPrefix(trimmedLine, parentField+": #") {
+				inParent = true
+				childIndent = ""
+				frontmatterLines = append(frontmatterLines, line)
+			} else {
+				// Replace the inline value with a block
+				frontmatterLines = append(frontmatterLines, parentField+":")
+				frontmatterLines = append(frontmatterLines, renderList("  ")...)
+				fieldSet = true
+			}
+			continue
+		}
+
+		frontmatterLines = append(frontmatterLines, line)
+	}
+
+	if !parentFound {
+		frontmatterLines = append(frontmatterLines, parentField+":")
+	}
+	if !fieldSet {
+		if childIndent == "" {
+			childIndent = "  "
+		}
+		frontmatterLines = append(frontmatterLines, renderList(childIndent)...)
+	}
+
+	var lines []string
+	lines = append(lines, "---")
+	lines = append(lines, frontmatterLines...)
+	lines = append(lines, "---")
+	if result.Markdown != "" {
+		// Add empty line before markdown content to match original format
+		lines = append(lines, "")
+		lines = append(lines, result.Markdown)
+	}
+
+	return strings.Join(lines, "\n"), nil
+}
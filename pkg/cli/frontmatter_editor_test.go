@@ -212,3 +212,205 @@ permissions:
 		})
 	}
 }
+
+func TestReplaceBlockInFrontmatter(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		expected string
+	}{
+		{
+			name: "replace nested block",
+			content: `---
+on: issues
+# Least privilege
+permissions:
+  contents: read
+  issues: write
+engine: copilot
+---
+
+# Test`,
+			expected: `---
+on: issues
+# Least privilege
+permissions:
+  contents: read
+  issues: read
+engine: copilot
+---
+
+# Test`,
+		},
+		{
+			name: "replace inline shorthand",
+			content: `---
+on: issues
+permissions: write-all
+---
+
+# Test`,
+			expected: `---
+on: issues
+permissions:
+  contents: read
+  issues: read
+---
+
+# Test`,
+		},
+		{
+			name: "add missing block",
+			content: `---
+on: issues
+---
+
+# Test`,
+			expected: `---
+on: issues
+permissions:
+  contents: read
+  issues: read
+---
+
+# Test`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := ReplaceBlockInFrontmatter(tt.content, "permissions", []string{"contents: read", "issues: read"})
+			if err != nil {
+				t.Fatalf("ReplaceBlockInFrontmatter() error = %v", err)
+			}
+			if result != tt.expected {
+				t.Errorf("ReplaceBlockInFrontmatter() =\n%s\nwant:\n%s", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestSetListInFrontmatterBlock(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		values   []string
+		expected string
+	}{
+		{
+			name: "replace list and keep other fields",
+			content: `---
+on: issues
+network:
+  allowed:
+    - defaults
+    - example.com
+  firewall: true
+engine: copilot
+---
+
+# Test`,
+			values: []string{"defaults", "python"},
+			expected: `---
+on: issues
+network:
+  allowed:
+    - defaults
+    - python
+  firewall: true
+engine: copilot
+---
+
+# Test`,
+		},
+		{
+			name: "replace flow list and quote wildcards",
+			content: `---
+on: issues
+network:
+  allowed: [defaults]
+---
+
+# Test`,
+			values: []string{"*.example.com"},
+			expected: `---
+on: issues
+network:
+  allowed:
+    - "*.example.com"
+---
+
+# Test`,
+		},
+		{
+			name: "replace inline value",
+			content: `---
+on: issues
+network: defaults
+engine: copilot
+---
+
+# Test`,
+			values: []string{"node"},
+			expected: `---
+on: issues
+network:
+  allowed:
+    - node
+engine: copilot
+---
+
+# Test`,
+		},
+		{
+			name: "add field to block",
+			content: `---
+on: issues
+network:
+  firewall: true
+engine: copilot
+---
+
+# Test`,
+			values: []string{},
+			expected: `---
+on: issues
+network:
+  firewall: true
+  allowed: []
+engine: copilot
+---
+
+# Test`,
+		},
+		{
+			name: "add missing block",
+			content: `---
+on: issues
+---
+
+# Test`,
+			values: []string{"github"},
+			expected: `---
+on: issues
+network:
+  allowed:
+    - github
+---
+
+# Test`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := SetListInFrontmatterBlock(tt.content, "network", "allowed", tt.values)
+			if err != nil {
+				t.Fatalf("SetListInFrontmatterBlock() error = %v", err)
+			}
+			if result != tt.expected {
+				t.Errorf("SetListInFrontmatterBlock() =\n%s\nwant:\n%s", result, tt.expected)
+			}
+		})
+	}
+}
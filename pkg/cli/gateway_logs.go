@@ -6,6 +6,7 @@
 //   - Parsing gateway.jsonl JSONL format logs (preferred)
 //   - Parsing rpc-messages.jsonl JSONL format logs (canonical fallback)
 //   - Extracting server and tool usage metrics
+//   - Collecting tool calls rejected by argument constraints
 //   - Aggregating gateway statistics
 //   - Rendering gateway metrics tables
 
@@ -33,6 +34,11 @@ var gatewayLogsLog = logger.New("cli:gateway_logs")
 // maxScannerBufferSize is the maximum scanner buffer for large JSONL payloads (1 MB).
 const maxScannerBufferSize = 1024 * 1024
 
+// rpcConstraintViolationCode is the JSON-RPC error code the gateway (tools.<server>.constraints)
+// and the safe outputs MCP server (safe-outputs.constraints) return when a tool call violates
+// an argument constraint
+const rpcConstraintViolationCode = -32004
+
 // GatewayLogEntry represents a single log entry from gateway.jsonl
 type GatewayLogEntry struct {
 	Timestamp  string  `json:"timestamp"`
@@ -48,6 +54,16 @@ type GatewayLogEntry struct {
 	Status     string  `json:"status,omitempty"`
 	Error      string  `json:"error,omitempty"`
 	Message    string  `json:"message,omitempty"`
+	Argument   string  `json:"argument,omitempty"` // constrained argument (constraint_violation events)
+}
+
+// GatewayConstraintViolation represents a tool call rejected by an argument constraint
+type GatewayConstraintViolation struct {
+	Timestamp  string `json:"timestamp,omitempty"`
+	ServerName string `json:"server_name"`
+	ToolName   string `json:"tool_name"`
+	Argument   string `json:"argument,omitempty"`
+	Message    string `json:"message,omitempty"`
 }
 
 // GatewayServerMetrics represents usage metrics for a single MCP server
@@ -82,6 +98,8 @@ type GatewayMetrics struct {
 	StartTime      time.Time
 	EndTime        time.Time
 	TotalDuration  float64 // in milliseconds
+	// ConstraintViolations lists tool calls rejected by argument constraints
+	ConstraintViolations []GatewayConstraintViolation
 }
 
 // RPCMessageEntry represents a single entry from rpc-messages.jsonl.
@@ -234,6 +252,14 @@ func parseRPCMessages(logPath string, verbose bool) (*GatewayMetrics, error) {
 				metrics.TotalErrors++
 				server := getOrCreateServer(metrics, entry.ServerID)
 				server.ErrorCount++
+
+				if resp.Error.Code == rpcConstraintViolationCode {
+					violation := GatewayConstraintViolation{Timestamp: entry.Timestamp, ServerName: entry.ServerID, Message: resp.Error.Message}
+					if pending, ok := pendingRequests[fmt.Sprintf("%s/%v", entry.ServerID, resp.ID)]; ok {
+						violation.ToolName = pending.ToolName
+					}
+					metrics.ConstraintViolations = append(metrics.ConstraintViolations, violation)
+				}
 			}
 
 			// Calculate duration by matching with pending request
@@ -400,6 +426,21 @@ func processGatewayLogEntry(entry *GatewayLogEntry, metrics *GatewayMetrics, ver
 
 	// Process based on event type
 	switch entry.Event {
+	case "constraint_violation":
+		message := entry.Message
+		if message == "" {
+			message = entry.Error
+		}
+		if entry.ServerName != "" {
+			getOrCreateServer(metrics, entry.ServerName)
+		}
+		metrics.ConstraintViolations = append(metrics.ConstraintViolations, GatewayConstraintViolation{
+			Timestamp:  entry.Timestamp,
+			ServerName: entry.ServerName,
+			ToolName:   entry.ToolName,
+			Argument:   entry.Argument,
+			Message:    message,
+		})
 	case "request", "tool_call", "rpc_call":
 		metrics.TotalRequests++
 
@@ -543,6 +584,16 @@ func renderGatewayMetricsTable(metrics *GatewayMetrics, verbose bool) string {
 		output.WriteString("└────────────────────────────┴──────────┴────────────┴───────────┴────────┘\n")
 	}
 
+	// Tool calls rejected by argument constraints are always shown
+	if len(metrics.ConstraintViolations) > 0 {
+		output.WriteString("\n")
+		output.WriteString(console.FormatWarningMessage(fmt.Sprintf("Constraint Violations: %d tool call(s) rejected", len(metrics.ConstraintViolations))))
+		output.WriteString("\n")
+		for _, violation := range metrics.ConstraintViolations {
+			output.WriteString("  - " + formatConstraintViolation(violation) + "\n")
+		}
+	}
+
 	// Tool metrics table (if verbose)
 	if verbose {
 		output.WriteString("\n")
@@ -585,6 +636,22 @@ func renderGatewayMetricsTable(metrics *GatewayMetrics, verbose bool) string {
 	return output.String()
 }
 
+// formatConstraintViolation formats a rejected tool call as server.tool(argument): message
+func formatConstraintViolation(violation GatewayConstraintViolation) string {
+	var text strings.Builder
+	text.WriteString(violation.ServerName)
+	if violation.ToolName != "" {
+		text.WriteString("." + violation.ToolName)
+	}
+	if violation.Argument != "" {
+		text.WriteString("(" + violation.Argument + ")")
+	}
+	if violation.Message != "" {
+		text.WriteString(": " + violation.Message)
+	}
+	return text.String()
+}
+
 // getSortedServerNames returns server names sorted by request count
 func getSortedServerNames(metrics *GatewayMetrics) []string {
 	var names []string
@@ -937,6 +1004,7 @@ func displayAggregatedGatewayMetrics(processedRuns []ProcessedRun, outputDir str
 		aggregated.TotalToolCalls += runMetrics.TotalToolCalls
 		aggregated.TotalErrors += runMetrics.TotalErrors
 		aggregated.TotalDuration += runMetrics.TotalDuration
+		aggregated.ConstraintViolations = append(aggregated.ConstraintViolations, runMetrics.ConstraintViolations...)
 
 		// Merge server metrics
 		for serverName, serverMetrics := range runMetrics.Servers {
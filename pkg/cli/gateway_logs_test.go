@@ -684,3 +684,42 @@ func TestBuildToolCallsFromRPCMessages(t *testing.T) {
 	assert.Equal(t, "error", getRepo.Status, "status should be error")
 	assert.Equal(t, "rate limit", getRepo.Error, "error message should be set")
 }
+
+func TestGatewayConstraintViolations(t *testing.T) {
+	tmpDir := t.TempDir()
+	logContent := `{"timestamp":"2024-01-12T10:00:00Z","direction":"OUT","type":"REQUEST","server_id":"safeoutputs","payload":{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"create_issue","arguments":{"title":"Hello"}}}}
+{"timestamp":"2024-01-12T10:00:01Z","direction":"IN","type":"RESPONSE","server_id":"safeoutputs","payload":{"jsonrpc":"2.0","id":1,"error":{"code":-32004,"message":"Tool call rejected by constraint: create_issue(title): value \"Hello\" does not match pattern bot: .+"}}}
+`
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "rpc-messages.jsonl"), []byte(logContent), 0644))
+
+	metrics, err := parseGatewayLogs(tmpDir, false)
+	require.NoError(t, err)
+	require.Len(t, metrics.ConstraintViolations, 1, "Should record the rejected call")
+	violation := metrics.ConstraintViolations[0]
+	assert.Equal(t, "safeoutputs", violation.ServerName, "Should record the server")
+	assert.Equal(t, "create_issue", violation.ToolName, "Should resolve the tool from the request")
+	assert.Contains(t, violation.Message, "create_issue(title)", "Should keep the rejection message")
+	assert.Equal(t, 1, metrics.TotalErrors, "Rejected call should count as an error")
+
+	output := renderGatewayMetricsTable(metrics, false)
+	assert.Contains(t, output, "Constraint Violations", "Table should list violations")
+	assert.Contains(t, output, "safeoutputs.create_issue", "Table should name the rejected tool")
+}
+
+func TestGatewayConstraintViolationEvents(t *testing.T) {
+	tmpDir := t.TempDir()
+	logContent := `{"timestamp":"2024-01-12T10:00:00Z","level":"warn","event":"constraint_violation","server_name":"github","tool_name":"create_issue","argument":"repo","message":"value \"secrets\" does not match pattern docs-.*"}
+`
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "gateway.jsonl"), []byte(logContent), 0644))
+
+	metrics, err := parseGatewayLogs(tmpDir, false)
+	require.NoError(t, err)
+	require.Len(t, metrics.ConstraintViolations, 1, "Should record the violation")
+	violation := metrics.ConstraintViolations[0]
+	assert.Equal(t, "github", violation.ServerName, "Should record the server")
+	assert.Equal(t, "create_issue", violation.ToolName, "Should record the tool")
+	assert.Equal(t, "repo", violation.Argument, "Should record the constrained argument")
+
+	output := renderGatewayMetricsTable(metrics, false)
+	assert.Contains(t, output, "github.create_issue(repo)", "Table should name the rejected tool and argument")
+}
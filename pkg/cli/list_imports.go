@@ -0,0 +1,346 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/github/gh-aw/pkg/console"
+	"github.com/github/gh-aw/pkg/logger"
+	"github.com/github/gh-aw/pkg/parser"
+)
+
+var listImportsLog = logger.New("cli:list_imports")
+
+// ImportListItem represents a shared workflow component for list output
+type ImportListItem struct {
+	Component   string                                   `json:"component" console:"header:Component"`
+	Description string                                   `json:"description,omitempty" console:"header:Description,omitempty,maxlen:60"`
+	InputCount  int                                      `json:"input_count" console:"header:Inputs"`
+	UsedBy      []string                                 `json:"used_by,omitempty" console:"-"`
+	UsageCount  int                                      `json:"-" console:"header:Used By"`
+	Inputs      map[string]*parser.ImportInputDefinition `json:"inputs,omitempty" console:"-"`
+}
+
+// RunListImports lists the shared workflow components in the local repository.
+// With inputs set, it prints a Markdown catalog of every component's input contract.
+func RunListImports(pattern string, inputs bool, verbose bool, jsonOutput bool) error {
+	listImportsLog.Printf("Listing imports: pattern=%s, inputs=%v, jsonOutput=%v", pattern, inputs, jsonOutput)
+
+	components, err := collectSharedComponents(getWorkflowsDir(), pattern)
+	if err != nil {
+		return err
+	}
+
+	if jsonOutput {
+		if !inputs {
+			for i := range components {
+				components[i].Inputs = nil
+			}
+		}
+		if components == nil {
+			components = []ImportListItem{}
+		}
+		jsonBytes, err := json.MarshalIndent(components, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON: %w", err)
+		}
+		fmt.Println(string(jsonBytes))
+		return nil
+	}
+
+	if len(components) == 0 {
+		fmt.Fprintln(os.Stderr, console.FormatInfoMessage("No shared workflow components found."))
+		return nil
+	}
+
+	if inputs {
+		fmt.Print(renderImportInputsCatalog(components))
+		return nil
+	}
+
+	fmt.Fprintln(os.Stderr, console.FormatSuccessMessage(fmt.Sprintf("Found %d shared component(s)", len(components))))
+	fmt.Fprint(os.Stderr, console.RenderStruct(components))
+	if verbose {
+		for _, component := range components {
+			if len(component.UsedBy) > 0 {
+				fmt.Fprintln(os.Stderr, console.FormatVerboseMessage(fmt.Sprintf("%s is imported by %s", component.Component, strings.Join(component.UsedBy, ", "))))
+			}
+		}
+	}
+	return nil
+}
+
+// collectSharedComponents finds the markdown files under the workflows directory that have no
+// 'on' trigger, parses their input definitions and counts the workflows that import them
+func collectSharedComponents(workflowsDir, pattern string) ([]ImportListItem, error) {
+	if _, err := os.Stat(workflowsDir); os.IsNotExist(err) {
+		return nil, fmt.Errorf("no %s directory found", workflowsDir)
+	}
+
+	var components []ImportListItem
+	usages := make(map[string][]string)
+
+	err := filepath.WalkDir(workflowsDir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() || !strings.HasSuffix(path, ".md") {
+			return nil
+		}
+
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		result, err := parser.ExtractFrontmatterFromContent(string(content))
+		if err != nil {
+			listImportsLog.Printf("Skipping %s: %v", path, err)
+			return nil
+		}
+
+		relPath, err := filepath.Rel(workflowsDir, path)
+		if err != nil {
+			return err
+		}
+		relPath = filepath.ToSlash(relPath)
+
+		// Workflows with a trigger are not components, but their imports count as usages
+		if _, hasOn := result.Frontmatter["on"]; hasOn {
+			for _, importPath := range localImportPaths(result.Frontmatter) {
+				usages[importPath] = append(usages[importPath], extractWorkflowNameFromPath(path))
+			}
+			return nil
+		}
+
+		if pattern != "" && !strings.Contains(strings.ToLower(relPath), strings.ToLower(pattern)) {
+			return nil
+		}
+
+		definitions, err := parser.ParseImportInputDefinitions(result.Frontmatter)
+		if err != nil {
+			return fmt.Errorf("%s: %w", relPath, err)
+		}
+		description, _ := result.Frontmatter["description"].(string)
+		components = append(components, ImportListItem{
+			Component:   relPath,
+			Description: description,
+			InputCount:  len(definitions),
+			Inputs:      definitions,
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range components {
+		usedBy := usages[components[i].Component]
+		sort.Strings(usedBy)
+		components[i].UsedBy = usedBy
+		components[i].UsageCount = len(usedBy)
+	}
+	sort.Slice(components, func(i, j int) bool {
+		return components[i].Component < components[j].Component
+	})
+
+	listImportsLog.Printf("Found %d shared components", len(components))
+	return components, nil
+}
+
+// localImportPaths returns the local import paths of a workflow, relative to the workflows
+// directory, without section references
+func localImportPaths(frontmatter map[string]any) []string {
+	imports, ok := frontmatter["imports"].([]any)
+	if !ok {
+		return nil
+	}
+
+	var paths []string
+	for _, item := range imports {
+		var importPath string
+		switch v := item.(type) {
+		case string:
+			importPath = v
+		case map[string]any:
+			importPath, _ = v["path"].(string)
+		}
+		importPath, _, _ = strings.Cut(importPath, "#")
+		// Remote workflowspecs (owner/repo/path@ref) are not local components
+		if importPath == "" || strings.Contains(importPath, "@") {
+			continue
+		}
+		paths = append(paths, filepath.ToSlash(filepath.Clean(importPath)))
+	}
+	return paths
+}
+
+// renderImportInputsCatalog renders the input contract of every component as Markdown
+func renderImportInputsCatalog(components []ImportListItem) string {
+	var output strings.Builder
+	output.WriteString("# Shared Component Inputs\n")
+
+	for _, component := range components {
+		fmt.Fprintf(&output, "\n## %s\n\n", component.Component)
+		if component.Description != "" {
+			output.WriteString(component.Description + "\n\n")
+		}
+		if len(component.Inputs) == 0 {
+			output.WriteString("No inputs.\n")
+			continue
+		}
+
+		output.WriteString("| Input | Type | Required | Default | Constraints | Description |\n")
+		output.WriteString("|-------|------|----------|---------|-------------|-------------|\n")
+		for _, row := range flattenImportInputs("", component.Inputs) {
+			required := "no"
+			if row.definition.Required {
+				required = "yes"
+			}
+			description := "-"
+			if row.definition.Description != "" {
+				description = escapeMarkdownTableCell(row.definition.Description)
+			}
+			fmt.Fprintf(&output, "| `%s` | %s | %s | %s | %s | %s |\n",
+				row.name,
+				formatImportInputType(row.definition),
+				required,
+				formatImportInputDefault(row.definition),
+				escapeMarkdownTableCell(formatImportInputConstraints(row.definition)),
+				description)
+		}
+
+		output.WriteString("\n```yaml\nimports:\n")
+		fmt.Fprintf(&output, "  - path: %s\n", component.Component)
+		var required []string
+		for _, name := range sortedImportInputNames(component.Inputs) {
+			if definition := component.Inputs[name]; definition.Required && definition.Default == nil {
+				required = append(required, name)
+			}
+		}
+		if len(required) > 0 {
+			output.WriteString("    inputs:\n")
+			for _, name := range required {
+				fmt.Fprintf(&output, "      %s: %s\n", name, importInputPlaceholder(component.Inputs[name]))
+			}
+		}
+		output.WriteString("```\n")
+	}
+	return output.String()
+}
+
+// importInputRow is a single row of the inputs catalog table
+type importInputRow struct {
+	name       string
+	definition *parser.ImportInputDefinition
+}
+
+// flattenImportInputs lists inputs in sorted order, followed by the properties of object inputs
+func flattenImportInputs(prefix string, definitions map[string]*parser.ImportInputDefinition) []importInputRow {
+	var rows []importInputRow
+	for _, name := range sortedImportInputNames(definitions) {
+		definition := definitions[name]
+		rows = append(rows, importInputRow{name: prefix + name, definition: definition})
+		if len(definition.Properties) > 0 {
+			rows = append(rows, flattenImportInputs(prefix+name+".", definition.Properties)...)
+		}
+	}
+	return rows
+}
+
+// formatImportInputType formats the type of an input, including the item type of arrays
+func formatImportInputType(definition *parser.ImportInputDefinition) string {
+	inputType := definition.Type
+	if inputType == "" {
+		inputType = "string"
+	}
+	if inputType == "array" && definition.Items != nil {
+		return "array of " + formatImportInputType(definition.Items)
+	}
+	return inputType
+}
+
+// formatImportInputDefault formats the default value of an input for the catalog
+func formatImportInputDefault(definition *parser.ImportInputDefinition) string {
+	if definition.Default == nil {
+		return "-"
+	}
+	return "`" + parser.FormatImportInputValue(definition.Default) + "`"
+}
+
+// formatImportInputConstraints describes the value constraints of an input
+func formatImportInputConstraints(definition *parser.ImportInputDefinition) string {
+	var constraints []string
+	if len(definition.Options) > 0 {
+		constraints = append(constraints, "one of "+strings.Join(definition.Options, ", "))
+	}
+	if len(definition.Enum) > 0 {
+		values := make([]string, len(definition.Enum))
+		for i, value := range definition.Enum {
+			values[i] = parser.FormatImportInputValue(value)
+		}
+		constraints = append(constraints, "one of "+strings.Join(values, ", "))
+	}
+	if definition.Pattern != "" {
+		constraints = append(constraints, "matches `"+definition.Pattern+"`")
+	}
+	quantity := ""
+	switch definition.Type {
+	case "", "string":
+		quantity = " length"
+	case "array":
+		quantity = " items"
+	}
+	if definition.Min != nil {
+		constraints = append(constraints, fmt.Sprintf("min%s %v", quantity, *definition.Min))
+	}
+	if definition.Max != nil {
+		constraints = append(constraints, fmt.Sprintf("max%s %v", quantity, *definition.Max))
+	}
+	if definition.Items != nil {
+		if itemConstraints := formatImportInputConstraints(definition.Items); itemConstraints != "" {
+			constraints = append(constraints, "items: "+itemConstraints)
+		}
+	}
+	if len(constraints) == 0 {
+		return "-"
+	}
+	return strings.Join(constraints, "; ")
+}
+
+// importInputPlaceholder returns an example value for a required input in the usage snippet
+func importInputPlaceholder(definition *parser.ImportInputDefinition) string {
+	switch definition.Type {
+	case "choice":
+		return definition.Options[0]
+	case "boolean":
+		return "true"
+	case "number":
+		return "0"
+	case "array":
+		return "[]"
+	case "object":
+		return "{}"
+	}
+	return `""`
+}
+
+// escapeMarkdownTableCell escapes pipes and newlines so a value fits in a table cell
+func escapeMarkdownTableCell(value string) string {
+	value = strings.ReplaceAll(value, "|", `\|`)
+	return strings.ReplaceAll(value, "\n", " ")
+}
+
+// sortedImportInputNames returns input names in sorted order
+func sortedImportInputNames(definitions map[string]*parser.ImportInputDefinition) []string {
+	names := make([]string, 0, len(definitions))
+	for name := range definitions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,102 @@
+//go:build !integration
+
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCollectSharedComponents(t *testing.T) {
+	workflowsDir := filepath.Join(t.TempDir(), ".github", "workflows")
+	require.NoError(t, os.MkdirAll(filepath.Join(workflowsDir, "shared"), 0755))
+
+	files := map[string]string{
+		"shared/fetch.md": `---
+description: Fetch items from a repository
+inputs:
+  repo:
+    type: string
+    required: true
+    pattern: "^[a-z-]+/[a-z-]+$"
+  count:
+    type: number
+    default: 10
+    max: 100
+  config:
+    type: object
+    properties:
+      region:
+        description: Deployment region
+        enum: [eu, us]
+---
+Fetch items.
+`,
+		"shared/tools.md": `---
+tools:
+  github:
+---
+`,
+		"daily.md": `---
+on: daily
+imports:
+  - path: shared/fetch.md
+    inputs:
+      repo: octo/hello
+  - shared/tools.md
+---
+`,
+		"weekly.md": `---
+on: weekly
+imports:
+  - shared/fetch.md
+  - octo/hello/shared/fetch.md@main
+---
+`,
+	}
+	for name, content := range files {
+		require.NoError(t, os.WriteFile(filepath.Join(workflowsDir, name), []byte(content), 0644))
+	}
+
+	components, err := collectSharedComponents(workflowsDir, "")
+	require.NoError(t, err, "components should be collected")
+	require.Len(t, components, 2, "only files without a trigger are components")
+
+	fetch := components[0]
+	assert.Equal(t, "shared/fetch.md", fetch.Component, "components should be sorted by path")
+	assert.Equal(t, "Fetch items from a repository", fetch.Description, "description should be read from frontmatter")
+	assert.Equal(t, 3, fetch.InputCount, "inputs should be counted")
+	assert.Equal(t, []string{"daily", "weekly"}, fetch.UsedBy, "local imports should count as usages")
+	assert.Equal(t, []string{"daily"}, components[1].UsedBy, "plain string imports should count as usages")
+
+	filtered, err := collectSharedComponents(workflowsDir, "TOOLS")
+	require.NoError(t, err, "filtered components should be collected")
+	require.Len(t, filtered, 1, "pattern should filter components")
+	assert.Equal(t, "shared/tools.md", filtered[0].Component, "pattern should match case-insensitively")
+
+	catalog := renderImportInputsCatalog(components)
+	for _, expected := range []string{
+		"## shared/fetch.md",
+		"| `config.region` | string | no | - | one of eu, us | Deployment region |",
+		"| `count` | number | no | `10` | max 100 | - |",
+		"| `repo` | string | yes | - | matches `^[a-z-]+/[a-z-]+$` | - |",
+		"  - path: shared/fetch.md\n    inputs:\n      repo: \"\"\n",
+		"## shared/tools.md\n\nNo inputs.",
+	} {
+		assert.Contains(t, catalog, expected, "catalog should contain %q", expected)
+	}
+}
+
+func TestCollectSharedComponentsInvalidInputs(t *testing.T) {
+	workflowsDir := t.TempDir()
+	content := "---\ninputs:\n  mode:\n    type: choice\n---\n"
+	require.NoError(t, os.WriteFile(filepath.Join(workflowsDir, "broken.md"), []byte(content), 0644))
+
+	_, err := collectSharedComponents(workflowsDir, "")
+	require.Error(t, err, "invalid input definitions should be reported")
+	assert.Contains(t, err.Error(), "broken.md: inputs.mode: choice inputs require options", "error should name the component")
+}
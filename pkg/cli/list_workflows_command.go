@@ -2,6 +2,7 @@ package cli
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"strings"
@@ -44,7 +45,9 @@ Examples:
   ` + string(constants.CLIExtensionPrefix) + ` list ci-                           # List workflows with 'ci-' in name
   ` + string(constants.CLIExtensionPrefix) + ` list --repo github/gh-aw ci-      # List workflows from github/gh-aw with 'ci-' in name
   ` + string(constants.CLIExtensionPrefix) + ` list --json                        # Output in JSON format
-  ` + string(constants.CLIExtensionPrefix) + ` list --label automation            # List workflows with 'automation' label`,
+  ` + string(constants.CLIExtensionPrefix) + ` list --label automation            # List workflows with 'automation' label
+  ` + string(constants.CLIExtensionPrefix) + ` list --imports                     # List shared components and their usage
+  ` + string(constants.CLIExtensionPrefix) + ` list --imports --inputs > INPUTS.md  # Generate a catalog of component inputs`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			var pattern string
 			if len(args) > 0 {
@@ -56,6 +59,17 @@ Examples:
 			verbose, _ := cmd.Flags().GetBool("verbose")
 			jsonFlag, _ := cmd.Flags().GetBool("json")
 			labelFilter, _ := cmd.Flags().GetString("label")
+			imports, _ := cmd.Flags().GetBool("imports")
+			inputs, _ := cmd.Flags().GetBool("inputs")
+			if inputs && !imports {
+				return errors.New("--inputs requires --imports")
+			}
+			if imports {
+				if repo != "" {
+					return errors.New("--imports cannot be combined with --repo")
+				}
+				return RunListImports(pattern, inputs, verbose, jsonFlag)
+			}
 			return RunListWorkflows(repo, path, pattern, verbose, jsonFlag, labelFilter)
 		},
 	}
@@ -64,6 +78,8 @@ Examples:
 	addJSONFlag(cmd)
 	cmd.Flags().String("label", "", "Filter workflows by label")
 	cmd.Flags().String("path", ".github/workflows", "Path to workflows directory in the repository")
+	cmd.Flags().Bool("imports", false, "List shared components (workflow files without an 'on' trigger) instead of workflows")
+	cmd.Flags().Bool("inputs", false, "With --imports, print a Markdown catalog of each component's inputs")
 
 	// Register completions for list command
 	cmd.ValidArgsFunction = CompleteWorkflowNames
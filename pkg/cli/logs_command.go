@@ -44,6 +44,7 @@ Downloaded artifacts include:
 - aw-{branch}.patch: Git patch of changes for each branch (one file per PR/push)
 - workflow-logs/: GitHub Actions workflow run logs (job logs organized in subdirectory)
 - summary.json: Complete metrics and run data for all downloaded runs
+- report.html: Self-contained HTML report (with --format html)
 
 ` + WorkflowIDExplanation + `
 
@@ -84,7 +85,9 @@ Examples:
   ` + string(constants.CLIExtensionPrefix) + ` logs --tool-graph              # Generate Mermaid tool sequence graph
   ` + string(constants.CLIExtensionPrefix) + ` logs --parse                   # Parse logs and generate Markdown reports
   ` + string(constants.CLIExtensionPrefix) + ` logs --json                    # Output metrics in JSON format
+  ` + string(constants.CLIExtensionPrefix) + ` logs --format html             # Write a self-contained HTML report
   ` + string(constants.CLIExtensionPrefix) + ` logs --parse --json            # Generate both Markdown and JSON
+  ` + string(constants.CLIExtensionPrefix) + ` logs --compare-matrix 1234567  # Compare the variants of a matrix run
 
   # Cross-repository
   ` + string(constants.CLIExtensionPrefix) + ` logs weekly-research --repo owner/repo  # Download logs from specific repository`,
@@ -133,11 +136,21 @@ Examples:
 			firewallOnly, _ := cmd.Flags().GetBool("firewall")
 			noFirewall, _ := cmd.Flags().GetBool("no-firewall")
 			parse, _ := cmd.Flags().GetBool("parse")
-			jsonOutput, _ := cmd.Flags().GetBool("json")
+			format, err := getReportFormat(cmd)
+			if err != nil {
+				return err
+			}
+			jsonOutput := format == reportFormatJSON
 			timeout, _ := cmd.Flags().GetInt("timeout")
 			repoOverride, _ := cmd.Flags().GetString("repo")
 			summaryFile, _ := cmd.Flags().GetString("summary-file")
 			safeOutputType, _ := cmd.Flags().GetString("safe-output")
+			compareMatrixRunID, _ := cmd.Flags().GetInt64("compare-matrix")
+
+			if compareMatrixRunID > 0 {
+				logsCommandLog.Printf("Comparing matrix variants of run %d", compareMatrixRunID)
+				return CompareMatrixRun(compareMatrixRunID, repoOverride, outputDir, jsonOutput, verbose)
+			}
 
 			// Resolve relative dates to absolute dates for GitHub CLI
 			now := time.Now()
@@ -172,7 +185,7 @@ Examples:
 
 			logsCommandLog.Printf("Executing logs download: workflow=%s, count=%d, engine=%s", workflowName, count, engine)
 
-			return DownloadWorkflowLogs(cmd.Context(), workflowName, count, startDate, endDate, outputDir, engine, ref, beforeRunID, afterRunID, repoOverride, verbose, toolGraph, noStaged, firewallOnly, noFirewall, parse, jsonOutput, timeout, summaryFile, safeOutputType)
+			return DownloadWorkflowLogs(cmd.Context(), workflowName, count, startDate, endDate, outputDir, engine, ref, beforeRunID, afterRunID, repoOverride, verbose, toolGraph, noStaged, firewallOnly, noFirewall, parse, jsonOutput, timeout, summaryFile, safeOutputType, format == reportFormatHTML)
 		},
 	}
 
@@ -191,8 +204,10 @@ Examples:
 	logsCmd.Flags().Bool("firewall", false, "Filter to only runs with firewall enabled")
 	logsCmd.Flags().Bool("no-firewall", false, "Filter to only runs without firewall enabled")
 	logsCmd.Flags().String("safe-output", "", "Filter to runs containing a specific safe output type (e.g., create-issue, missing-tool, missing-data)")
+	logsCmd.Flags().Int64("compare-matrix", 0, "Compare the engine/model variants of a matrix workflow run side by side (run ID)")
 	logsCmd.Flags().Bool("parse", false, "Run JavaScript parsers on agent logs and firewall logs, writing Markdown to log.md and firewall.md")
 	addJSONFlag(logsCmd)
+	addFormatFlag(logsCmd)
 	logsCmd.Flags().Int("timeout", 0, "Download timeout in seconds (0 = no timeout)")
 	logsCmd.Flags().String("summary-file", "summary.json", "Path to write the summary JSON file relative to output directory (use empty string to disable)")
 	logsCmd.MarkFlagsMutuallyExclusive("firewall", "no-firewall")
@@ -79,6 +79,13 @@ func flattenSingleFileArtifacts(outputDir string, verbose bool) error {
 		sourcePath := filepath.Join(artifactDir, singleEntry.Name())
 		destPath := filepath.Join(outputDir, singleEntry.Name())
 
+		// Keep the file nested when another artifact already provided it at the root
+		// (e.g. the suffixed agent-output artifacts of matrix variants and agent stages)
+		if _, err := os.Stat(destPath); err == nil {
+			logsDownloadLog.Printf("Not flattening %s: %s already exists", entry.Name(), destPath)
+			continue
+		}
+
 		logsDownloadLog.Printf("Flattening: %s → %s", sourcePath, destPath)
 
 		// Move the file to root (parent directory)
@@ -164,6 +164,40 @@ func TestFlattenSingleFileArtifacts(t *testing.T) {
 	}
 }
 
+func TestFlattenSingleFileArtifactsKeepsCollidingFiles(t *testing.T) {
+	// Matrix variant and agent stage jobs upload the same files as the agent job under
+	// suffixed artifact names. Only the agent job's file is moved to the root; without the
+	// collision check the variant's file would silently replace it.
+	tmpDir := testutil.TempDir(t, "test-*")
+	for name, content := range map[string]string{
+		"agent-output":       `{"items":[{"type":"create_issue"}]}`,
+		"agent-output-codex": `{"items":[{"type":"noop"}]}`,
+	} {
+		artifactDir := filepath.Join(tmpDir, name)
+		if err := os.MkdirAll(artifactDir, 0755); err != nil {
+			t.Fatalf("Setup failed: %v", err)
+		}
+		if err := os.WriteFile(filepath.Join(artifactDir, "agent_output.json"), []byte(content), 0644); err != nil {
+			t.Fatalf("Setup failed: %v", err)
+		}
+	}
+
+	if err := flattenSingleFileArtifacts(tmpDir, false); err != nil {
+		t.Fatalf("flattenSingleFileArtifacts failed: %v", err)
+	}
+
+	content, err := os.ReadFile(filepath.Join(tmpDir, "agent_output.json"))
+	if err != nil {
+		t.Fatalf("Expected flattened agent_output.json: %v", err)
+	}
+	if !strings.Contains(string(content), "create_issue") {
+		t.Errorf("Flattened agent_output.json should come from the agent job, got %s", content)
+	}
+	if _, err := os.Stat(filepath.Join(tmpDir, "agent-output-codex", "agent_output.json")); err != nil {
+		t.Errorf("Variant agent_output.json should stay in its artifact directory: %v", err)
+	}
+}
+
 func TestFlattenSingleFileArtifactsInvalidDirectory(t *testing.T) {
 	// Test with non-existent directory
 	err := flattenSingleFileArtifacts("/nonexistent/directory", false)
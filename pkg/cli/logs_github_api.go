@@ -75,12 +75,26 @@ func fetchJobStatuses(runID int64, verbose bool) (int, error) {
 
 // fetchJobDetails gets detailed job information including durations for a workflow run
 func fetchJobDetails(runID int64, verbose bool) ([]JobInfoWithDuration, error) {
-	logsGitHubAPILog.Printf("Fetching job details: runID=%d", runID)
+	return fetchJobDetailsForRepo(runID, "", verbose)
+}
+
+// fetchJobDetailsForRepo gets detailed job information for a workflow run of the given
+// repository ([HOST/]owner/repo), or of the current repository when repo is empty
+func fetchJobDetailsForRepo(runID int64, repo string, verbose bool) ([]JobInfoWithDuration, error) {
+	logsGitHubAPILog.Printf("Fetching job details: runID=%d, repo=%s", runID, repo)
 	if verbose {
 		fmt.Fprintln(os.Stderr, console.FormatVerboseMessage(fmt.Sprintf("Fetching job details for run %d", runID)))
 	}
 
-	output, err := workflow.RunGHCombined("Fetching job details...", "api", fmt.Sprintf("repos/{owner}/{repo}/actions/runs/%d/jobs", runID), "--jq", ".jobs[] | {name: .name, status: .status, conclusion: .conclusion, started_at: .started_at, completed_at: .completed_at}")
+	args := []string{"api"}
+	if repo == "" {
+		repo = "{owner}/{repo}"
+	} else if parts := strings.Split(repo, "/"); len(parts) == 3 {
+		args = append(args, "--hostname", parts[0])
+		repo = parts[1] + "/" + parts[2]
+	}
+	args = append(args, fmt.Sprintf("repos/%s/actions/runs/%d/jobs", repo, runID), "--jq", ".jobs[] | {name: .name, status: .status, conclusion: .conclusion, started_at: .started_at, completed_at: .completed_at}")
+	output, err := workflow.RunGHCombined("Fetching job details...", args...)
 	if err != nil {
 		if verbose {
 			fmt.Fprintln(os.Stderr, console.FormatVerboseMessage(fmt.Sprintf("Failed to fetch job details for run %d: %v", runID, err)))
@@ -0,0 +1,254 @@
+// This file provides command-line interface functionality for gh-aw.
+// This file (logs_matrix.go) implements gh aw logs --compare-matrix, which compares
+// the engine/model variants of a single matrix run side by side.
+
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/github/gh-aw/pkg/console"
+	"github.com/github/gh-aw/pkg/constants"
+	"github.com/github/gh-aw/pkg/fileutil"
+	"github.com/github/gh-aw/pkg/logger"
+	"github.com/github/gh-aw/pkg/timeutil"
+	"github.com/github/gh-aw/pkg/workflow"
+)
+
+var logsMatrixLog = logger.New("cli:logs_matrix")
+
+// MatrixVariantComparison holds the metrics of one matrix variant of a run
+type MatrixVariantComparison struct {
+	Variant    string         `json:"variant"`
+	Job        string         `json:"job"`
+	Engine     string         `json:"engine,omitempty"`
+	Model      string         `json:"model,omitempty"`
+	Applied    bool           `json:"applied"`
+	Conclusion string         `json:"conclusion,omitempty"`
+	Detection  string         `json:"detection,omitempty"`
+	Duration   string         `json:"duration,omitempty"`
+	Tokens     int            `json:"tokens"`
+	Cost       float64        `json:"cost"`
+	Turns      int            `json:"turns"`
+	Outputs    map[string]int `json:"outputs,omitempty"`
+}
+
+// CompareMatrixRun downloads the artifacts of a matrix run and prints a side-by-side
+// comparison of its variants. repoOverride selects the repository (owner/repo) of the
+// run; the current repository is used when it is empty.
+func CompareMatrixRun(runID int64, repoOverride string, outputDir string, jsonOutput bool, verbose bool) error {
+	logsMatrixLog.Printf("Comparing matrix variants of run %d: repo=%s", runID, repoOverride)
+
+	runDir := filepath.Join(outputDir, fmt.Sprintf("run-%d-matrix", runID))
+	if err := os.MkdirAll(runDir, 0755); err != nil {
+		return fmt.Errorf("failed to create run output directory: %w", err)
+	}
+
+	// Download without flattening: every variant keeps its own artifact directories
+	spinner := console.NewSpinner(fmt.Sprintf("Downloading artifacts for run %d...", runID))
+	if !verbose {
+		spinner.Start()
+	}
+	args := []string{"run", "download", strconv.FormatInt(runID, 10), "--dir", runDir}
+	if repoOverride != "" {
+		args = append(args, "--repo", repoOverride)
+	}
+	output, err := workflow.ExecGH(args...).CombinedOutput()
+	if !verbose {
+		spinner.Stop()
+	}
+	if err != nil {
+		return fmt.Errorf("failed to download artifacts for run %d: %w (output: %s)", runID, err, string(output))
+	}
+
+	jobs, err := fetchJobDetailsForRepo(runID, repoOverride, verbose)
+	if err != nil {
+		return err
+	}
+
+	variants, err := buildMatrixComparison(runDir, jobs, verbose)
+	if err != nil {
+		return err
+	}
+
+	if jsonOutput {
+		data, err := json.MarshalIndent(variants, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal matrix comparison: %w", err)
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	fmt.Print(renderMatrixComparison(runID, variants))
+	return nil
+}
+
+// buildMatrixComparison collects the metrics of every matrix variant found in the
+// downloaded artifacts of a run. The agent job uploads agent-artifacts; the other
+// variant jobs upload agent-artifacts-<variant>.
+func buildMatrixComparison(runDir string, jobs []JobInfoWithDuration, verbose bool) ([]MatrixVariantComparison, error) {
+	entries, err := os.ReadDir(runDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read run directory: %w", err)
+	}
+
+	jobsByName := make(map[string]JobInfoWithDuration)
+	for _, job := range jobs {
+		jobsByName[job.Name] = job
+	}
+
+	var variants []MatrixVariantComparison
+	for _, entry := range entries {
+		if !entry.IsDir() || (entry.Name() != "agent-artifacts" && !strings.HasPrefix(entry.Name(), "agent-artifacts-")) {
+			continue
+		}
+		suffix := strings.TrimPrefix(entry.Name(), "agent-artifacts")
+		artifactsDir := filepath.Join(runDir, entry.Name())
+
+		info, err := parseAwInfo(filepath.Join(artifactsDir, "aw_info.json"), verbose)
+		if err != nil || info.MatrixVariant == "" {
+			logsMatrixLog.Printf("Skipping %s: not a matrix variant", entry.Name())
+			continue
+		}
+
+		variant := MatrixVariantComparison{
+			Variant: info.MatrixVariant,
+			Job:     "agent",
+			Engine:  info.EngineID,
+			Model:   info.Model,
+			Outputs: countAgentOutputTypes(filepath.Join(runDir, constants.AgentOutputArtifactName+suffix)),
+		}
+		detectionJob := string(constants.DetectionJobName)
+		if suffix != "" {
+			matrixVariant := &workflow.MatrixVariant{ID: info.MatrixVariant}
+			variant.Job = matrixVariant.JobName()
+			detectionJob = matrixVariant.DetectionJobName()
+		}
+		if job, ok := jobsByName[detectionJob]; ok {
+			variant.Detection = job.Conclusion
+		}
+		// matrix.apply only selects the variant that feeds the safe_outputs job; its outputs
+		// are applied when that job actually succeeded (detection passed, nothing failed)
+		if suffix == "" && !info.Staged {
+			variant.Applied = jobsByName["safe_outputs"].Conclusion == "success"
+		}
+
+		metrics, err := extractLogMetrics(artifactsDir, verbose)
+		if err != nil {
+			return nil, fmt.Errorf("failed to extract metrics for variant %s: %w", variant.Variant, err)
+		}
+		// Session logs with detailed token usage are uploaded separately by some engines
+		if metrics.TokenUsage == 0 {
+			if outputsDir := filepath.Join(runDir, "agent_outputs"+suffix); fileutil.DirExists(outputsDir) {
+				if outputMetrics, err := extractLogMetrics(outputsDir, verbose); err == nil && outputMetrics.TokenUsage > 0 {
+					metrics = outputMetrics
+				}
+			}
+		}
+		variant.Tokens = metrics.TokenUsage
+		variant.Cost = metrics.EstimatedCost
+		variant.Turns = metrics.Turns
+
+		if job, ok := jobsByName[variant.Job]; ok {
+			variant.Conclusion = job.Conclusion
+			if job.Duration > 0 {
+				variant.Duration = timeutil.FormatDuration(job.Duration)
+			}
+		}
+
+		variants = append(variants, variant)
+	}
+
+	if len(variants) == 0 {
+		return nil, fmt.Errorf("no matrix variants found in %s. Is this a run of a workflow with matrix:?", runDir)
+	}
+
+	sort.Slice(variants, func(i, j int) bool { return variants[i].Variant < variants[j].Variant })
+	logsMatrixLog.Printf("Found %d matrix variants", len(variants))
+	return variants, nil
+}
+
+// countAgentOutputTypes counts the safe output items by type in an agent-output artifact
+func countAgentOutputTypes(artifactDir string) map[string]int {
+	content, err := os.ReadFile(filepath.Join(artifactDir, constants.AgentOutputFilename))
+	if err != nil {
+		return nil
+	}
+
+	var agentOutput struct {
+		Items []struct {
+			Type string `json:"type"`
+		} `json:"items"`
+	}
+	if err := json.Unmarshal(content, &agentOutput); err != nil {
+		logsMatrixLog.Printf("Failed to parse %s: %v", artifactDir, err)
+		return nil
+	}
+
+	counts := make(map[string]int)
+	for _, item := range agentOutput.Items {
+		if item.Type != "" {
+			counts[normalizeSafeOutputType(item.Type)]++
+		}
+	}
+	return counts
+}
+
+// renderMatrixComparison renders the variants of a matrix run as a table
+func renderMatrixComparison(runID int64, variants []MatrixVariantComparison) string {
+	config := console.TableConfig{
+		Title:   fmt.Sprintf("Matrix comparison for run %d", runID),
+		Headers: []string{"Variant", "Engine", "Model", "Applied", "Status", "Detection", "Duration", "Tokens", "Cost ($)", "Turns", "Outputs"},
+	}
+	for _, variant := range variants {
+		applied := "no"
+		if variant.Applied {
+			applied = "yes"
+		}
+		config.Rows = append(config.Rows, []string{
+			variant.Variant,
+			variant.Engine,
+			valueOrDash(variant.Model),
+			applied,
+			valueOrDash(variant.Conclusion),
+			valueOrDash(variant.Detection),
+			valueOrDash(variant.Duration),
+			console.FormatNumber(variant.Tokens),
+			formatCost(variant.Cost),
+			strconv.Itoa(variant.Turns),
+			formatOutputCounts(variant.Outputs),
+		})
+	}
+	return console.RenderTable(config)
+}
+
+// formatOutputCounts formats safe output counts as "type×count" in a stable order
+func formatOutputCounts(counts map[string]int) string {
+	if len(counts) == 0 {
+		return "-"
+	}
+	types := make([]string, 0, len(counts))
+	for outputType := range counts {
+		types = append(types, outputType)
+	}
+	sort.Strings(types)
+	parts := make([]string, 0, len(types))
+	for _, outputType := range types {
+		parts = append(parts, fmt.Sprintf("%s×%d", outputType, counts[outputType]))
+	}
+	return strings.Join(parts, ", ")
+}
+
+func valueOrDash(value string) string {
+	if value == "" {
+		return "-"
+	}
+	return value
+}
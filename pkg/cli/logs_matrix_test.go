@@ -0,0 +1,113 @@
+//go:build !integration
+
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/github/gh-aw/pkg/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildMatrixComparison(t *testing.T) {
+	runDir := testutil.TempDir(t, "logs-matrix")
+
+	writeFile := func(relPath, content string) {
+		path := filepath.Join(runDir, relPath)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	}
+	writeFile("agent-artifacts/aw_info.json", `{"engine_id":"claude","model":"sonnet","staged":true,"matrix_variant":"claude"}`)
+	writeFile("agent-artifacts-codex/aw_info.json", `{"engine_id":"codex","model":"gpt-5","staged":true,"matrix_variant":"codex"}`)
+	writeFile("agent-output/agent_output.json", `{"items":[{"type":"create_issue"},{"type":"add-comment"},{"type":"create_issue"}]}`)
+	writeFile("agent-output-codex/agent_output.json", `{"items":[{"type":"noop"}]}`)
+	writeFile("agent-artifacts-other/aw_info.json", `{"engine_id":"copilot"}`)
+
+	jobs := []JobInfoWithDuration{
+		{JobInfo: JobInfo{Name: "agent", Conclusion: "success"}, Duration: 90 * time.Second},
+		{JobInfo: JobInfo{Name: "agent_codex", Conclusion: "failure"}, Duration: 30 * time.Second},
+		{JobInfo: JobInfo{Name: "detection", Conclusion: "success"}},
+		{JobInfo: JobInfo{Name: "detection_codex", Conclusion: "skipped"}},
+		{JobInfo: JobInfo{Name: "safe_outputs", Conclusion: "success"}},
+	}
+
+	variants, err := buildMatrixComparison(runDir, jobs, false)
+	require.NoError(t, err, "Matrix comparison should be built")
+	require.Len(t, variants, 2, "Only artifacts of matrix variants should be compared")
+
+	claude, codex := variants[0], variants[1]
+	assert.Equal(t, "claude", claude.Variant, "Variants should be sorted by id")
+	assert.Equal(t, "agent", claude.Job, "Primary variant should run in the agent job")
+	assert.Equal(t, "sonnet", claude.Model, "Model should come from aw_info.json")
+	assert.False(t, claude.Applied, "Staged variants should not be applied")
+	assert.Equal(t, "success", claude.Conclusion, "Conclusion should come from the job")
+	assert.Equal(t, "1.5m", claude.Duration, "Duration should come from the job")
+	assert.Equal(t, map[string]int{"create_issue": 2, "add_comment": 1}, claude.Outputs, "Outputs should be counted by type")
+
+	assert.Equal(t, "agent_codex", codex.Job, "Secondary variant should run in its own job")
+	assert.Equal(t, "success", claude.Detection, "Detection should come from the detection job")
+	assert.Equal(t, "failure", codex.Conclusion, "Conclusion should come from the variant job")
+	assert.Equal(t, "skipped", codex.Detection, "Detection should come from the variant detection job")
+	assert.Equal(t, map[string]int{"noop": 1}, codex.Outputs, "Variant outputs should come from its own artifact")
+
+	table := renderMatrixComparison(123, variants)
+	assert.Contains(t, table, "add_comment×1, create_issue×2", "Table should list the outputs")
+	assert.Contains(t, table, "gpt-5", "Table should list the models")
+}
+
+func TestBuildMatrixComparisonApplied(t *testing.T) {
+	tests := []struct {
+		name        string
+		safeOutputs string
+		expected    bool
+	}{
+		{name: "safe outputs applied", safeOutputs: "success", expected: true},
+		{name: "detection blocked the safe outputs", safeOutputs: "skipped", expected: false},
+		{name: "safe outputs failed", safeOutputs: "failure", expected: false},
+		{name: "job details unavailable", expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runDir := testutil.TempDir(t, "logs-matrix-applied")
+			for relPath, content := range map[string]string{
+				"agent-artifacts/aw_info.json":       `{"engine_id":"claude","matrix_variant":"claude"}`,
+				"agent-artifacts-codex/aw_info.json": `{"engine_id":"codex","matrix_variant":"codex"}`,
+			} {
+				path := filepath.Join(runDir, relPath)
+				require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+				require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+			}
+
+			var jobs []JobInfoWithDuration
+			if tt.safeOutputs != "" {
+				jobs = append(jobs, JobInfoWithDuration{JobInfo: JobInfo{Name: "safe_outputs", Conclusion: tt.safeOutputs}})
+			}
+
+			variants, err := buildMatrixComparison(runDir, jobs, false)
+			require.NoError(t, err, "Matrix comparison should be built")
+			require.Len(t, variants, 2)
+			assert.Equal(t, tt.expected, variants[0].Applied, "Applied variant should follow the safe_outputs job")
+			assert.False(t, variants[1].Applied, "Secondary variants are never applied")
+		})
+	}
+}
+
+func TestBuildMatrixComparisonWithoutVariants(t *testing.T) {
+	runDir := testutil.TempDir(t, "logs-matrix-empty")
+	require.NoError(t, os.MkdirAll(filepath.Join(runDir, "agent-artifacts"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(runDir, "agent-artifacts", "aw_info.json"), []byte(`{"engine_id":"claude"}`), 0644))
+
+	_, err := buildMatrixComparison(runDir, nil, false)
+	require.Error(t, err, "Runs without matrix variants should be rejected")
+	assert.Contains(t, err.Error(), "no matrix variants found", "Error should explain the problem")
+}
+
+func TestFormatOutputCounts(t *testing.T) {
+	assert.Equal(t, "-", formatOutputCounts(nil), "No outputs should render as a dash")
+	assert.Equal(t, "add_comment×1, create_issue×2", formatOutputCounts(map[string]int{"create_issue": 2, "add_comment": 1}), "Outputs should be sorted by type")
+}
@@ -78,6 +78,7 @@ type ProcessedRun struct {
 	MCPFailures             []MCPFailureReport
 	MCPToolUsage            *MCPToolUsageData
 	JobDetails              []JobInfoWithDuration
+	ConstraintViolations    []GatewayConstraintViolation
 }
 
 // MissingToolReport represents a missing tool reported by an agentic workflow
@@ -238,6 +239,7 @@ type AwInfo struct {
 	CLIVersion      string      `json:"cli_version,omitempty"` // gh-aw CLI version
 	WorkflowName    string      `json:"workflow_name"`
 	Staged          bool        `json:"staged"`
+	MatrixVariant   string      `json:"matrix_variant,omitempty"`   // Matrix variant run by the job (matrix workflows)
 	AwfVersion      string      `json:"awf_version,omitempty"`      // AWF firewall version (new name)
 	FirewallVersion string      `json:"firewall_version,omitempty"` // AWF firewall version (old name, for backward compatibility)
 	Steps           AwInfoSteps `json:"steps,omitzero"`             // Steps metadata
@@ -40,7 +40,7 @@ func getMaxConcurrentDownloads() int {
 }
 
 // DownloadWorkflowLogs downloads and analyzes workflow logs with metrics
-func DownloadWorkflowLogs(ctx context.Context, workflowName string, count int, startDate, endDate, outputDir, engine, ref string, beforeRunID, afterRunID int64, repoOverride string, verbose bool, toolGraph bool, noStaged bool, firewallOnly bool, noFirewall bool, parse bool, jsonOutput bool, timeout int, summaryFile string, safeOutputType string) error {
+func DownloadWorkflowLogs(ctx context.Context, workflowName string, count int, startDate, endDate, outputDir, engine, ref string, beforeRunID, afterRunID int64, repoOverride string, verbose bool, toolGraph bool, noStaged bool, firewallOnly bool, noFirewall bool, parse bool, jsonOutput bool, timeout int, summaryFile string, safeOutputType string, htmlReport bool) error {
 	logsOrchestratorLog.Printf("Starting workflow log download: workflow=%s, count=%d, startDate=%s, endDate=%s, outputDir=%s, summaryFile=%s, safeOutputType=%s", workflowName, count, startDate, endDate, outputDir, summaryFile, safeOutputType)
 
 	// Ensure .github/aw/logs/.gitignore exists on every invocation
@@ -488,6 +488,12 @@ func DownloadWorkflowLogs(ctx context.Context, workflowName string, count int, s
 		if err := renderLogsJSON(logsData); err != nil {
 			return fmt.Errorf("failed to render JSON output: %w", err)
 		}
+	} else if htmlReport {
+		graph := buildToolGraph(processedRuns, verbose)
+		report := buildLogsHTMLReport(logsData, graph, time.Now())
+		if err := writeHTMLReport(filepath.Join(outputDir, htmlReportFileName), report); err != nil {
+			return err
+		}
 	} else {
 		renderLogsConsole(logsData)
 
@@ -563,197 +569,7 @@ func downloadRunArtifactsConcurrent(ctx context.Context, runs []WorkflowRun, out
 				}, nil
 			default:
 			}
-			if verbose {
-				fmt.Fprintln(os.Stderr, console.FormatInfoMessage(fmt.Sprintf("Processing run %d (%s)...", run.DatabaseID, run.Status)))
-			}
-
-			// Download artifacts and logs for this run
-			runOutputDir := filepath.Join(outputDir, fmt.Sprintf("run-%d", run.DatabaseID))
-
-			// Try to load cached summary first
-			if summary, ok := loadRunSummary(runOutputDir, verbose); ok {
-				// Valid cached summary exists, use it directly
-				result := DownloadResult{
-					Run:                     summary.Run,
-					Metrics:                 summary.Metrics,
-					AccessAnalysis:          summary.AccessAnalysis,
-					FirewallAnalysis:        summary.FirewallAnalysis,
-					RedactedDomainsAnalysis: summary.RedactedDomainsAnalysis,
-					MissingTools:            summary.MissingTools,
-					MissingData:             summary.MissingData,
-					Noops:                   summary.Noops,
-					MCPFailures:             summary.MCPFailures,
-					MCPToolUsage:            summary.MCPToolUsage,
-					JobDetails:              summary.JobDetails,
-					LogsPath:                runOutputDir,
-					Cached:                  true, // Mark as cached
-				}
-				// Update progress counter
-				completed := atomic.AddInt64(&completedCount, 1)
-				if progressBar != nil {
-					fmt.Fprintf(os.Stderr, "Processing runs: %s\r", progressBar.Update(completed))
-				}
-				return result, nil
-			}
-
-			// No cached summary or version mismatch - download and process
-			err := downloadRunArtifacts(run.DatabaseID, runOutputDir, verbose)
-
-			result := DownloadResult{
-				Run:      run,
-				LogsPath: runOutputDir,
-			}
-
-			if err != nil {
-				// Check if this is a "no artifacts" case
-				if errors.Is(err, ErrNoArtifacts) {
-					// For runs with important conclusions (timed_out, failure, cancelled),
-					// still process them even without artifacts to show the failure in reports
-					if isFailureConclusion(run.Conclusion) {
-						// Don't skip - we want these to appear in the report
-						// Just use empty metrics
-						result.Metrics = LogMetrics{}
-
-						// Try to fetch job details to get error count
-						if failedJobCount, jobErr := fetchJobStatuses(run.DatabaseID, verbose); jobErr == nil {
-							run.ErrorCount = failedJobCount
-						}
-					} else {
-						// For other runs (success, neutral, etc.) without artifacts, skip them
-						result.Skipped = true
-						result.Error = err
-					}
-				} else {
-					result.Error = err
-				}
-			} else {
-				// Extract metrics from logs
-				metrics, metricsErr := extractLogMetrics(runOutputDir, verbose)
-				if metricsErr != nil {
-					if verbose {
-						fmt.Fprintln(os.Stderr, console.FormatWarningMessage(fmt.Sprintf("Failed to extract metrics for run %d: %v", run.DatabaseID, metricsErr)))
-					}
-					// Don't fail the whole download for metrics errors
-					metrics = LogMetrics{}
-				}
-				result.Metrics = metrics
-
-				// Analyze access logs if available
-				accessAnalysis, accessErr := analyzeAccessLogs(runOutputDir, verbose)
-				if accessErr != nil {
-					if verbose {
-						fmt.Fprintln(os.Stderr, console.FormatWarningMessage(fmt.Sprintf("Failed to analyze access logs for run %d: %v", run.DatabaseID, accessErr)))
-					}
-				}
-				result.AccessAnalysis = accessAnalysis
-
-				// Analyze firewall logs if available
-				firewallAnalysis, firewallErr := analyzeFirewallLogs(runOutputDir, verbose)
-				if firewallErr != nil {
-					if verbose {
-						fmt.Fprintln(os.Stderr, console.FormatWarningMessage(fmt.Sprintf("Failed to analyze firewall logs for run %d: %v", run.DatabaseID, firewallErr)))
-					}
-				}
-				result.FirewallAnalysis = firewallAnalysis
-
-				// Analyze redacted domains if available
-				redactedDomainsAnalysis, redactedErr := analyzeRedactedDomains(runOutputDir, verbose)
-				if redactedErr != nil {
-					if verbose {
-						fmt.Fprintln(os.Stderr, console.FormatWarningMessage(fmt.Sprintf("Failed to analyze redacted domains for run %d: %v", run.DatabaseID, redactedErr)))
-					}
-				}
-				result.RedactedDomainsAnalysis = redactedDomainsAnalysis
-
-				// Extract missing tools if available
-				missingTools, missingErr := extractMissingToolsFromRun(runOutputDir, run, verbose)
-				if missingErr != nil {
-					if verbose {
-						fmt.Fprintln(os.Stderr, console.FormatWarningMessage(fmt.Sprintf("Failed to extract missing tools for run %d: %v", run.DatabaseID, missingErr)))
-					}
-				}
-				result.MissingTools = missingTools
-
-				// Extract missing data if available
-				missingData, missingDataErr := extractMissingDataFromRun(runOutputDir, run, verbose)
-				if missingDataErr != nil {
-					if verbose {
-						fmt.Fprintln(os.Stderr, console.FormatWarningMessage(fmt.Sprintf("Failed to extract missing data for run %d: %v", run.DatabaseID, missingDataErr)))
-					}
-				}
-				result.MissingData = missingData
-
-				// Extract noops if available
-				noops, noopErr := extractNoopsFromRun(runOutputDir, run, verbose)
-				if noopErr != nil {
-					if verbose {
-						fmt.Fprintln(os.Stderr, console.FormatWarningMessage(fmt.Sprintf("Failed to extract noops for run %d: %v", run.DatabaseID, noopErr)))
-					}
-				}
-				result.Noops = noops
-
-				// Extract MCP failures if available
-				mcpFailures, mcpErr := extractMCPFailuresFromRun(runOutputDir, run, verbose)
-				if mcpErr != nil {
-					if verbose {
-						fmt.Fprintln(os.Stderr, console.FormatWarningMessage(fmt.Sprintf("Failed to extract MCP failures for run %d: %v", run.DatabaseID, mcpErr)))
-					}
-				}
-				result.MCPFailures = mcpFailures
-
-				// Extract MCP tool usage data from gateway logs if available
-				mcpToolUsage, mcpToolErr := extractMCPToolUsageData(runOutputDir, verbose)
-				if mcpToolErr != nil {
-					if verbose {
-						fmt.Fprintln(os.Stderr, console.FormatWarningMessage(fmt.Sprintf("Failed to extract MCP tool usage for run %d: %v", run.DatabaseID, mcpToolErr)))
-					}
-				}
-				result.MCPToolUsage = mcpToolUsage
-
-				// Count safe output items created in GitHub (from manifest artifact)
-				result.Run.SafeItemsCount = len(extractCreatedItemsFromManifest(runOutputDir))
-
-				// Fetch job details for the summary
-				jobDetails, jobErr := fetchJobDetails(run.DatabaseID, verbose)
-				if jobErr != nil {
-					if verbose {
-						fmt.Fprintln(os.Stderr, console.FormatWarningMessage(fmt.Sprintf("Failed to fetch job details for run %d: %v", run.DatabaseID, jobErr)))
-					}
-				}
-
-				// List all artifacts
-				artifacts, listErr := listArtifacts(runOutputDir)
-				if listErr != nil {
-					if verbose {
-						fmt.Fprintln(os.Stderr, console.FormatWarningMessage(fmt.Sprintf("Failed to list artifacts for run %d: %v", run.DatabaseID, listErr)))
-					}
-				}
-
-				// Create and save run summary
-				summary := &RunSummary{
-					CLIVersion:              GetVersion(),
-					RunID:                   run.DatabaseID,
-					ProcessedAt:             time.Now(),
-					Run:                     run,
-					Metrics:                 metrics,
-					AccessAnalysis:          accessAnalysis,
-					FirewallAnalysis:        firewallAnalysis,
-					RedactedDomainsAnalysis: redactedDomainsAnalysis,
-					MissingTools:            missingTools,
-					MissingData:             missingData,
-					Noops:                   noops,
-					MCPFailures:             mcpFailures,
-					MCPToolUsage:            mcpToolUsage,
-					ArtifactsList:           artifacts,
-					JobDetails:              jobDetails,
-				}
-
-				if saveErr := saveRunSummary(runOutputDir, summary, verbose); saveErr != nil {
-					if verbose {
-						fmt.Fprintln(os.Stderr, console.FormatWarningMessage(fmt.Sprintf("Failed to save run summary for run %d: %v", run.DatabaseID, saveErr)))
-					}
-				}
-			}
+			result := processRun(run, outputDir, verbose)
 
 			// Update progress counter for completed downloads
 			completed := atomic.AddInt64(&completedCount, 1)
@@ -796,6 +612,199 @@ func downloadRunArtifactsConcurrent(ctx context.Context, runs []WorkflowRun, out
 	return results
 }
 
+// processRun downloads the artifacts of a single run, or loads its cached summary, and
+// analyzes its logs
+func processRun(run WorkflowRun, outputDir string, verbose bool) DownloadResult {
+	if verbose {
+		fmt.Fprintln(os.Stderr, console.FormatInfoMessage(fmt.Sprintf("Processing run %d (%s)...", run.DatabaseID, run.Status)))
+	}
+
+	// Download artifacts and logs for this run
+	runOutputDir := filepath.Join(outputDir, fmt.Sprintf("run-%d", run.DatabaseID))
+
+	// Try to load cached summary first
+	if summary, ok := loadRunSummary(runOutputDir, verbose); ok {
+		// Valid cached summary exists, use it directly
+		result := DownloadResult{
+			Run:                     summary.Run,
+			Metrics:                 summary.Metrics,
+			AccessAnalysis:          summary.AccessAnalysis,
+			FirewallAnalysis:        summary.FirewallAnalysis,
+			RedactedDomainsAnalysis: summary.RedactedDomainsAnalysis,
+			MissingTools:            summary.MissingTools,
+			MissingData:             summary.MissingData,
+			Noops:                   summary.Noops,
+			MCPFailures:             summary.MCPFailures,
+			MCPToolUsage:            summary.MCPToolUsage,
+			JobDetails:              summary.JobDetails,
+			LogsPath:                runOutputDir,
+			Cached:                  true, // Mark as cached
+		}
+		return result
+	}
+
+	// No cached summary or version mismatch - download and process
+	err := downloadRunArtifacts(run.DatabaseID, runOutputDir, verbose)
+
+	result := DownloadResult{
+		Run:      run,
+		LogsPath: runOutputDir,
+	}
+
+	if err != nil {
+		// Check if this is a "no artifacts" case
+		if errors.Is(err, ErrNoArtifacts) {
+			// For runs with important conclusions (timed_out, failure, cancelled),
+			// still process them even without artifacts to show the failure in reports
+			if isFailureConclusion(run.Conclusion) {
+				// Don't skip - we want these to appear in the report
+				// Just use empty metrics
+				result.Metrics = LogMetrics{}
+
+				// Try to fetch job details to get error count
+				if failedJobCount, jobErr := fetchJobStatuses(run.DatabaseID, verbose); jobErr == nil {
+					run.ErrorCount = failedJobCount
+				}
+			} else {
+				// For other runs (success, neutral, etc.) without artifacts, skip them
+				result.Skipped = true
+				result.Error = err
+			}
+		} else {
+			result.Error = err
+		}
+	} else {
+		// Extract metrics from logs
+		metrics, metricsErr := extractLogMetrics(runOutputDir, verbose)
+		if metricsErr != nil {
+			if verbose {
+				fmt.Fprintln(os.Stderr, console.FormatWarningMessage(fmt.Sprintf("Failed to extract metrics for run %d: %v", run.DatabaseID, metricsErr)))
+			}
+			// Don't fail the whole download for metrics errors
+			metrics = LogMetrics{}
+		}
+		result.Metrics = metrics
+
+		// Analyze access logs if available
+		accessAnalysis, accessErr := analyzeAccessLogs(runOutputDir, verbose)
+		if accessErr != nil {
+			if verbose {
+				fmt.Fprintln(os.Stderr, console.FormatWarningMessage(fmt.Sprintf("Failed to analyze access logs for run %d: %v", run.DatabaseID, accessErr)))
+			}
+		}
+		result.AccessAnalysis = accessAnalysis
+
+		// Analyze firewall logs if available
+		firewallAnalysis, firewallErr := analyzeFirewallLogs(runOutputDir, verbose)
+		if firewallErr != nil {
+			if verbose {
+				fmt.Fprintln(os.Stderr, console.FormatWarningMessage(fmt.Sprintf("Failed to analyze firewall logs for run %d: %v", run.DatabaseID, firewallErr)))
+			}
+		}
+		result.FirewallAnalysis = firewallAnalysis
+
+		// Analyze redacted domains if available
+		redactedDomainsAnalysis, redactedErr := analyzeRedactedDomains(runOutputDir, verbose)
+		if redactedErr != nil {
+			if verbose {
+				fmt.Fprintln(os.Stderr, console.FormatWarningMessage(fmt.Sprintf("Failed to analyze redacted domains for run %d: %v", run.DatabaseID, redactedErr)))
+			}
+		}
+		result.RedactedDomainsAnalysis = redactedDomainsAnalysis
+
+		// Extract missing tools if available
+		missingTools, missingErr := extractMissingToolsFromRun(runOutputDir, run, verbose)
+		if missingErr != nil {
+			if verbose {
+				fmt.Fprintln(os.Stderr, console.FormatWarningMessage(fmt.Sprintf("Failed to extract missing tools for run %d: %v", run.DatabaseID, missingErr)))
+			}
+		}
+		result.MissingTools = missingTools
+
+		// Extract missing data if available
+		missingData, missingDataErr := extractMissingDataFromRun(runOutputDir, run, verbose)
+		if missingDataErr != nil {
+			if verbose {
+				fmt.Fprintln(os.Stderr, console.FormatWarningMessage(fmt.Sprintf("Failed to extract missing data for run %d: %v", run.DatabaseID, missingDataErr)))
+			}
+		}
+		result.MissingData = missingData
+
+		// Extract noops if available
+		noops, noopErr := extractNoopsFromRun(runOutputDir, run, verbose)
+		if noopErr != nil {
+			if verbose {
+				fmt.Fprintln(os.Stderr, console.FormatWarningMessage(fmt.Sprintf("Failed to extract noops for run %d: %v", run.DatabaseID, noopErr)))
+			}
+		}
+		result.Noops = noops
+
+		// Extract MCP failures if available
+		mcpFailures, mcpErr := extractMCPFailuresFromRun(runOutputDir, run, verbose)
+		if mcpErr != nil {
+			if verbose {
+				fmt.Fprintln(os.Stderr, console.FormatWarningMessage(fmt.Sprintf("Failed to extract MCP failures for run %d: %v", run.DatabaseID, mcpErr)))
+			}
+		}
+		result.MCPFailures = mcpFailures
+
+		// Extract MCP tool usage data from gateway logs if available
+		mcpToolUsage, mcpToolErr := extractMCPToolUsageData(runOutputDir, verbose)
+		if mcpToolErr != nil {
+			if verbose {
+				fmt.Fprintln(os.Stderr, console.FormatWarningMessage(fmt.Sprintf("Failed to extract MCP tool usage for run %d: %v", run.DatabaseID, mcpToolErr)))
+			}
+		}
+		result.MCPToolUsage = mcpToolUsage
+
+		// Count safe output items created in GitHub (from manifest artifact)
+		result.Run.SafeItemsCount = len(extractCreatedItemsFromManifest(runOutputDir))
+
+		// Fetch job details for the summary
+		jobDetails, jobErr := fetchJobDetails(run.DatabaseID, verbose)
+		if jobErr != nil {
+			if verbose {
+				fmt.Fprintln(os.Stderr, console.FormatWarningMessage(fmt.Sprintf("Failed to fetch job details for run %d: %v", run.DatabaseID, jobErr)))
+			}
+		}
+
+		// List all artifacts
+		artifacts, listErr := listArtifacts(runOutputDir)
+		if listErr != nil {
+			if verbose {
+				fmt.Fprintln(os.Stderr, console.FormatWarningMessage(fmt.Sprintf("Failed to list artifacts for run %d: %v", run.DatabaseID, listErr)))
+			}
+		}
+
+		// Create and save run summary
+		summary := &RunSummary{
+			CLIVersion:              GetVersion(),
+			RunID:                   run.DatabaseID,
+			ProcessedAt:             time.Now(),
+			Run:                     run,
+			Metrics:                 metrics,
+			AccessAnalysis:          accessAnalysis,
+			FirewallAnalysis:        firewallAnalysis,
+			RedactedDomainsAnalysis: redactedDomainsAnalysis,
+			MissingTools:            missingTools,
+			MissingData:             missingData,
+			Noops:                   noops,
+			MCPFailures:             mcpFailures,
+			MCPToolUsage:            mcpToolUsage,
+			ArtifactsList:           artifacts,
+			JobDetails:              jobDetails,
+		}
+
+		if saveErr := saveRunSummary(runOutputDir, summary, verbose); saveErr != nil {
+			if verbose {
+				fmt.Fprintln(os.Stderr, console.FormatWarningMessage(fmt.Sprintf("Failed to save run summary for run %d: %v", run.DatabaseID, saveErr)))
+			}
+		}
+	}
+
+	return result
+}
+
 // normalizeSafeOutputType converts dashes to underscores for matching
 // This allows users to use either "missing-tool" or "missing_tool" interchangeably
 func normalizeSafeOutputType(safeOutputType string) string {
@@ -24,11 +24,17 @@ Available subcommands:
   • list-tools - List available tools for a specific MCP server
   • inspect    - Inspect MCP servers and list available tools, resources, and roots
   • add        - Add an MCP tool to an agentic workflow
+  • test-safe-inputs - Run safe-inputs tools locally against fixture inputs
+  • snapshot   - Record the tools and input schemas of a workflow's MCP servers
+  • verify     - Check a workflow's MCP servers against their snapshot
 
 Examples:
   gh aw mcp list                              # List all workflows with MCP servers
   gh aw mcp inspect weekly-research           # Inspect MCP servers in workflow
   gh aw mcp add my-workflow tavily            # Add Tavily MCP server to workflow
+  gh aw mcp test-safe-inputs my-workflow      # Run safe-inputs fixtures for workflow
+  gh aw mcp snapshot weekly-research          # Record MCP tool contracts of workflow
+  gh aw mcp verify weekly-research            # Fail if MCP tools drifted from the snapshot
   gh aw mcp inspect weekly-research --server github --tool create_issue  # Inspect specific tool`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			return cmd.Help()
@@ -40,6 +46,9 @@ Examples:
 	cmd.AddCommand(NewMCPListSubcommand())
 	cmd.AddCommand(NewMCPListToolsSubcommand())
 	cmd.AddCommand(NewMCPInspectSubcommand())
+	cmd.AddCommand(NewMCPTestSafeInputsSubcommand())
+	cmd.AddCommand(NewMCPSnapshotSubcommand())
+	cmd.AddCommand(NewMCPVerifySubcommand())
 
 	return cmd
 }
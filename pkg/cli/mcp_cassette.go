@@ -0,0 +1,266 @@
+package cli
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/github/gh-aw/pkg/console"
+	"github.com/github/gh-aw/pkg/constants"
+	"github.com/github/gh-aw/pkg/logger"
+)
+
+var mcpCassetteLog = logger.New("cli:mcp_cassette")
+
+// mcpCassetteFileName is the name of the cassette the MCP gateway records when sandbox.mcp.record is enabled
+var mcpCassetteFileName = filepath.Base(constants.DefaultMCPGatewayCassettePath)
+
+// CassetteEntry is a single recorded MCP tool call and its response.
+// Cassettes are JSONL files with one entry per line, in call order.
+type CassetteEntry struct {
+	Timestamp string          `json:"timestamp,omitempty"`
+	Server    string          `json:"server"`
+	Tool      string          `json:"tool"`
+	Arguments json.RawMessage `json:"arguments"`
+	Result    json.RawMessage `json:"result,omitempty"`
+	Error     *rpcError       `json:"error,omitempty"`
+}
+
+// rpcToolCallArguments represents the name and arguments of a tools/call request.
+type rpcToolCallArguments struct {
+	Name      string          `json:"name"`
+	Arguments json.RawMessage `json:"arguments"`
+}
+
+// rpcToolCallResponse represents the result or error of a tools/call response.
+type rpcToolCallResponse struct {
+	ID     any             `json:"id"`
+	Result json.RawMessage `json:"result,omitempty"`
+	Error  *rpcError       `json:"error,omitempty"`
+}
+
+// normalizeCassetteArguments rewrites tool arguments as compact JSON with sorted object keys,
+// so that equivalent calls produce identical cassette lines
+func normalizeCassetteArguments(raw json.RawMessage) (json.RawMessage, error) {
+	trimmed := bytes.TrimSpace(raw)
+	if len(trimmed) == 0 || string(trimmed) == "null" {
+		return json.RawMessage("{}"), nil
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(trimmed))
+	decoder.UseNumber()
+	var value any
+	if err := decoder.Decode(&value); err != nil {
+		return nil, fmt.Errorf("invalid tool arguments: %w", err)
+	}
+	// encoding/json sorts map keys when marshaling
+	normalized, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	return normalized, nil
+}
+
+// isCassetteServer reports whether calls to a server belong in a cassette.
+// Safe outputs are excluded: a replayed run must still produce its own outputs.
+func isCassetteServer(server string) bool {
+	return server != "" && server != string(constants.SafeOutputsMCPServerID)
+}
+
+// findCassettePath returns the path to a cassette recorded by the MCP gateway, or "" if not found
+func findCassettePath(logDir string) string {
+	for _, path := range []string{
+		filepath.Join(logDir, "mcp-logs", mcpCassetteFileName),
+		filepath.Join(logDir, mcpCassetteFileName),
+	} {
+		if _, err := os.Stat(path); err == nil {
+			return path
+		}
+	}
+	return ""
+}
+
+// readCassette reads a cassette file, normalizing arguments and dropping safe output calls
+func readCassette(path string) ([]CassetteEntry, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open cassette: %w", err)
+	}
+	defer file.Close()
+
+	var entries []CassetteEntry
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 10*1024*1024)
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+
+		var entry CassetteEntry
+		if err := json.Unmarshal(line, &entry); err != nil {
+			return nil, fmt.Errorf("invalid cassette entry on line %d: %w", lineNum, err)
+		}
+		if !isCassetteServer(entry.Server) || entry.Tool == "" {
+			continue
+		}
+		if entry.Arguments, err = normalizeCassetteArguments(entry.Arguments); err != nil {
+			return nil, fmt.Errorf("invalid cassette entry on line %d: %w", lineNum, err)
+		}
+		entries = append(entries, entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error reading cassette: %w", err)
+	}
+
+	mcpCassetteLog.Printf("Read %d cassette entries from %s", len(entries), path)
+	return entries, nil
+}
+
+// buildCassetteFromRPCMessages pairs the tools/call requests and responses of a
+// rpc-messages.jsonl file into cassette entries, in request order
+func buildCassetteFromRPCMessages(path string) ([]CassetteEntry, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open rpc-messages.jsonl: %w", err)
+	}
+	defer file.Close()
+
+	var calls []*CassetteEntry
+	answered := make(map[*CassetteEntry]bool)
+	pending := make(map[string]*CassetteEntry)
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 10*1024*1024)
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+
+		var entry RPCMessageEntry
+		if err := json.Unmarshal(line, &entry); err != nil {
+			mcpCassetteLog.Printf("Skipping invalid rpc-messages.jsonl line: %v", err)
+			continue
+		}
+		if !isCassetteServer(entry.ServerID) {
+			continue
+		}
+
+		switch {
+		case entry.Direction == "OUT" && entry.Type == "REQUEST":
+			var req rpcRequestPayload
+			if err := json.Unmarshal(entry.Payload, &req); err != nil || req.Method != "tools/call" || req.ID == nil {
+				continue
+			}
+			var params rpcToolCallArguments
+			if err := json.Unmarshal(req.Params, &params); err != nil || params.Name == "" {
+				continue
+			}
+			arguments, err := normalizeCassetteArguments(params.Arguments)
+			if err != nil {
+				continue
+			}
+			call := &CassetteEntry{Timestamp: entry.Timestamp, Server: entry.ServerID, Tool: params.Name, Arguments: arguments}
+			calls = append(calls, call)
+			pending[fmt.Sprintf("%s/%v", entry.ServerID, req.ID)] = call
+
+		case entry.Direction == "IN" && entry.Type == "RESPONSE":
+			var resp rpcToolCallResponse
+			if err := json.Unmarshal(entry.Payload, &resp); err != nil {
+				continue
+			}
+			key := fmt.Sprintf("%s/%v", entry.ServerID, resp.ID)
+			call, ok := pending[key]
+			if !ok {
+				continue
+			}
+			delete(pending, key)
+			call.Result = resp.Result
+			call.Error = resp.Error
+			answered[call] = true
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error reading rpc-messages.jsonl: %w", err)
+	}
+
+	// Calls that never received a response cannot be replayed
+	var entries []CassetteEntry
+	for _, call := range calls {
+		if answered[call] {
+			entries = append(entries, *call)
+		}
+	}
+
+	mcpCassetteLog.Printf("Built %d cassette entries from %s (%d unanswered calls)", len(entries), path, len(calls)-len(entries))
+	return entries, nil
+}
+
+// extractRunCassette loads the tool calls of a downloaded run, preferring the cassette
+// recorded by the gateway over the rpc-messages.jsonl written by the engine
+func extractRunCassette(runOutputDir string) ([]CassetteEntry, string, error) {
+	if path := findCassettePath(runOutputDir); path != "" {
+		entries, err := readCassette(path)
+		return entries, path, err
+	}
+	if path := findRPCMessagesPath(runOutputDir); path != "" {
+		entries, err := buildCassetteFromRPCMessages(path)
+		return entries, path, err
+	}
+	return nil, "", errors.New("no MCP tool call recording found in the run artifacts; enable 'sandbox.mcp.record: true' in the workflow and run it again")
+}
+
+// writeCassette writes cassette entries as JSONL, creating parent directories as needed
+func writeCassette(path string, entries []CassetteEntry) error {
+	if dir := filepath.Dir(path); dir != "." {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return fmt.Errorf("failed to create cassette directory: %w", err)
+		}
+	}
+
+	var content bytes.Buffer
+	for _, entry := range entries {
+		line, err := json.Marshal(entry)
+		if err != nil {
+			return fmt.Errorf("failed to encode cassette entry: %w", err)
+		}
+		content.Write(line)
+		content.WriteByte('\n')
+	}
+	if err := os.WriteFile(path, content.Bytes(), 0644); err != nil {
+		return fmt.Errorf("failed to write cassette: %w", err)
+	}
+	return nil
+}
+
+// ExportRunCassette extracts the MCP tool calls of an audited run into a cassette that
+// can be replayed with sandbox.mcp.replay
+func ExportRunCassette(runOutputDir, cassettePath string, verbose bool) error {
+	mcpCassetteLog.Printf("Exporting cassette from %s to %s", runOutputDir, cassettePath)
+
+	entries, source, err := extractRunCassette(runOutputDir)
+	if err != nil {
+		return err
+	}
+	if verbose {
+		fmt.Fprintln(os.Stderr, console.FormatVerboseMessage("Reading tool calls from "+source))
+	}
+	if len(entries) == 0 {
+		return fmt.Errorf("no MCP tool calls found in %s", source)
+	}
+
+	if err := writeCassette(cassettePath, entries); err != nil {
+		return err
+	}
+
+	fmt.Fprintln(os.Stderr, console.FormatSuccessMessage(fmt.Sprintf("Exported %d tool call(s) to %s", len(entries), cassettePath)))
+	fmt.Fprintln(os.Stderr, console.FormatInfoMessage(fmt.Sprintf("Replay them by adding 'sandbox.mcp.replay: %s' to the workflow", filepath.ToSlash(cassettePath))))
+	return nil
+}
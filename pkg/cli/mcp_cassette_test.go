@@ -0,0 +1,97 @@
+//go:build !integration
+
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeCassetteArguments(t *testing.T) {
+	tests := []struct {
+		name string
+		raw  string
+		want string
+	}{
+		{name: "empty", raw: "", want: "{}"},
+		{name: "null", raw: "null", want: "{}"},
+		{name: "sorts keys and removes whitespace", raw: `{ "repo": "demo",  "owner": "octo" }`, want: `{"owner":"octo","repo":"demo"}`},
+		{name: "preserves large numbers", raw: `{"id": 12345678901234567890}`, want: `{"id":12345678901234567890}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			normalized, err := normalizeCassetteArguments([]byte(tt.raw))
+			require.NoError(t, err)
+			assert.JSONEq(t, tt.want, string(normalized))
+			assert.Equal(t, tt.want, string(normalized), "Normalized arguments should be compact")
+		})
+	}
+
+	_, err := normalizeCassetteArguments([]byte("{invalid"))
+	assert.Error(t, err, "Invalid JSON should be rejected")
+}
+
+func TestBuildCassetteFromRPCMessages(t *testing.T) {
+	tmpDir := t.TempDir()
+	logContent := `{"timestamp":"2024-01-12T10:00:00Z","direction":"OUT","type":"REQUEST","server_id":"github","payload":{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"issue_read","arguments":{"repo":"demo","owner":"octo"}}}}
+{"timestamp":"2024-01-12T10:00:00Z","direction":"OUT","type":"REQUEST","server_id":"github","payload":{"jsonrpc":"2.0","id":2,"method":"tools/list"}}
+{"timestamp":"2024-01-12T10:00:01Z","direction":"OUT","type":"REQUEST","server_id":"github","payload":{"jsonrpc":"2.0","id":3,"method":"tools/call","params":{"name":"list_issues","arguments":{}}}}
+{"timestamp":"2024-01-12T10:00:02Z","direction":"IN","type":"RESPONSE","server_id":"github","payload":{"jsonrpc":"2.0","id":1,"result":{"content":[{"type":"text","text":"issue"}]}}}
+{"timestamp":"2024-01-12T10:00:03Z","direction":"OUT","type":"REQUEST","server_id":"safeoutputs","payload":{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"create_issue","arguments":{"title":"x"}}}}
+{"timestamp":"2024-01-12T10:00:04Z","direction":"IN","type":"RESPONSE","server_id":"safeoutputs","payload":{"jsonrpc":"2.0","id":1,"result":{}}}
+{"timestamp":"2024-01-12T10:00:05Z","direction":"OUT","type":"REQUEST","server_id":"fetch","payload":{"jsonrpc":"2.0","id":7,"method":"tools/call","params":{"name":"fetch","arguments":{"url":"https://example.com"}}}}
+{"timestamp":"2024-01-12T10:00:06Z","direction":"IN","type":"RESPONSE","server_id":"fetch","payload":{"jsonrpc":"2.0","id":7,"error":{"code":-32001,"message":"Server unavailable"}}}
+`
+	path := filepath.Join(tmpDir, "rpc-messages.jsonl")
+	require.NoError(t, os.WriteFile(path, []byte(logContent), 0644))
+
+	entries, err := buildCassetteFromRPCMessages(path)
+	require.NoError(t, err)
+	require.Len(t, entries, 2, "Only answered tool calls outside safe outputs should be recorded")
+
+	assert.Equal(t, "github", entries[0].Server)
+	assert.Equal(t, "issue_read", entries[0].Tool)
+	assert.JSONEq(t, `{"owner":"octo","repo":"demo"}`, string(entries[0].Arguments))
+	assert.JSONEq(t, `{"content":[{"type":"text","text":"issue"}]}`, string(entries[0].Result))
+
+	assert.Equal(t, "fetch", entries[1].Tool)
+	require.NotNil(t, entries[1].Error, "Error responses should be recorded")
+	assert.Equal(t, -32001, entries[1].Error.Code)
+}
+
+func TestExportRunCassette(t *testing.T) {
+	t.Run("prefers the recorded cassette", func(t *testing.T) {
+		runDir := t.TempDir()
+		require.NoError(t, os.MkdirAll(filepath.Join(runDir, "mcp-logs"), 0755))
+		recorded := `{"server":"github","tool":"issue_read","arguments":{"repo":"demo", "owner":"octo"},"result":{}}
+{"server":"safeoutputs","tool":"noop","arguments":{},"result":{}}
+`
+		require.NoError(t, os.WriteFile(filepath.Join(runDir, "mcp-logs", "cassette.jsonl"), []byte(recorded), 0644))
+		require.NoError(t, os.WriteFile(filepath.Join(runDir, "mcp-logs", "rpc-messages.jsonl"), []byte(""), 0644))
+
+		cassettePath := filepath.Join(t.TempDir(), "cassettes", "triage.jsonl")
+		require.NoError(t, ExportRunCassette(runDir, cassettePath, false))
+
+		content, err := os.ReadFile(cassettePath)
+		require.NoError(t, err)
+		lines := strings.Split(strings.TrimSpace(string(content)), "\n")
+		require.Len(t, lines, 1, "Safe output calls should be dropped")
+		assert.Contains(t, lines[0], `"arguments":{"owner":"octo","repo":"demo"}`, "Arguments should be normalized")
+
+		entries, err := readCassette(cassettePath)
+		require.NoError(t, err)
+		assert.Len(t, entries, 1, "Exported cassette should read back")
+	})
+
+	t.Run("no recording", func(t *testing.T) {
+		err := ExportRunCassette(t.TempDir(), filepath.Join(t.TempDir(), "c.jsonl"), false)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "sandbox.mcp.record")
+	})
+}
@@ -0,0 +1,398 @@
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/github/gh-aw/pkg/console"
+	"github.com/github/gh-aw/pkg/logger"
+	"github.com/github/gh-aw/pkg/parser"
+	"github.com/github/gh-aw/pkg/workflow"
+	"github.com/spf13/cobra"
+)
+
+var mcpSnapshotLog = logger.New("cli:mcp_snapshot")
+
+// mcpSnapshotFileSuffix is appended to the workflow name (without .md) to form the snapshot path
+const mcpSnapshotFileSuffix = ".mcp-snapshot.json"
+
+// MCPSnapshot records the tools that the MCP servers of a workflow exposed when it was snapshotted
+type MCPSnapshot struct {
+	Servers map[string]*MCPServerSnapshot `json:"servers"`
+}
+
+// MCPServerSnapshot records the tools of a single MCP server
+type MCPServerSnapshot struct {
+	Type  string            `json:"type"`
+	Tools []MCPToolSnapshot `json:"tools"`
+}
+
+// MCPToolSnapshot records the name and input schema of a tool
+type MCPToolSnapshot struct {
+	Name        string `json:"name"`
+	InputSchema any    `json:"input_schema,omitempty"`
+}
+
+// MCPDrift describes a difference between a snapshot and the live tools of a server
+type MCPDrift struct {
+	Server string `json:"server"`
+	Tool   string `json:"tool,omitempty"`
+	Kind   string `json:"kind"` // "removed", "changed", "added" or "unsnapshotted"
+}
+
+// String formats the drift for display
+func (d MCPDrift) String() string {
+	switch d.Kind {
+	case "removed":
+		return fmt.Sprintf("%s: tool '%s' was removed", d.Server, d.Tool)
+	case "changed":
+		return fmt.Sprintf("%s: input schema of tool '%s' changed", d.Server, d.Tool)
+	case "added":
+		return fmt.Sprintf("%s: new tool '%s' is not in the snapshot", d.Server, d.Tool)
+	default:
+		return fmt.Sprintf("%s: server is not in the snapshot", d.Server)
+	}
+}
+
+// mcpSnapshotPath returns the snapshot file path of a workflow
+func mcpSnapshotPath(workflowPath string) string {
+	return strings.TrimSuffix(workflowPath, ".md") + mcpSnapshotFileSuffix
+}
+
+// readMCPSnapshot reads a snapshot file. It returns nil without error when the file does not exist.
+func readMCPSnapshot(path string) (*MCPSnapshot, error) {
+	content, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read MCP snapshot: %w", err)
+	}
+	var snapshot MCPSnapshot
+	if err := json.Unmarshal(content, &snapshot); err != nil {
+		return nil, fmt.Errorf("failed to parse MCP snapshot %s: %w", path, err)
+	}
+	if snapshot.Servers == nil {
+		snapshot.Servers = make(map[string]*MCPServerSnapshot)
+	}
+	return &snapshot, nil
+}
+
+// writeMCPSnapshot writes a snapshot file with stable formatting
+func writeMCPSnapshot(path string, snapshot *MCPSnapshot) error {
+	content, err := json.MarshalIndent(snapshot, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal MCP snapshot: %w", err)
+	}
+	if err := os.WriteFile(path, append(content, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write MCP snapshot: %w", err)
+	}
+	return nil
+}
+
+// buildMCPServerSnapshot records the tools of a connected server, sorted by name
+func buildMCPServerSnapshot(info *parser.MCPServerInfo) (*MCPServerSnapshot, error) {
+	server := &MCPServerSnapshot{Type: info.Config.Type, Tools: []MCPToolSnapshot{}}
+	for _, tool := range info.Tools {
+		schema, err := normalizeMCPSchema(tool.InputSchema)
+		if err != nil {
+			return nil, fmt.Errorf("tool '%s': %w", tool.Name, err)
+		}
+		server.Tools = append(server.Tools, MCPToolSnapshot{Name: tool.Name, InputSchema: schema})
+	}
+	sort.Slice(server.Tools, func(i, j int) bool { return server.Tools[i].Name < server.Tools[j].Name })
+	return server, nil
+}
+
+// normalizeMCPSchema converts a schema to plain JSON values so that schemas read from a
+// snapshot file and schemas returned by a server compare equal
+func normalizeMCPSchema(schema any) (any, error) {
+	if schema == nil {
+		return nil, nil
+	}
+	content, err := json.Marshal(schema)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal input schema: %w", err)
+	}
+	var normalized any
+	if err := json.Unmarshal(content, &normalized); err != nil {
+		return nil, fmt.Errorf("failed to parse input schema: %w", err)
+	}
+	return normalized, nil
+}
+
+// diffMCPServerSnapshot compares the snapshot of a server with its live tools. Removed and
+// changed tools are only reported when they are in the server's allowed list (all tools when
+// the list is empty or contains "*"), since the agent cannot call the others. New tools are
+// always reported, so that an upstream upgrade is reviewed before the allowed list changes.
+func diffMCPServerSnapshot(name string, snapshot *MCPServerSnapshot, live *MCPServerSnapshot, allowed []string) []MCPDrift {
+	if snapshot == nil {
+		return []MCPDrift{{Server: name, Kind: "unsnapshotted"}}
+	}
+
+	liveTools := make(map[string]MCPToolSnapshot, len(live.Tools))
+	for _, tool := range live.Tools {
+		liveTools[tool.Name] = tool
+	}
+	snapshotTools := make(map[string]bool, len(snapshot.Tools))
+
+	var drifts []MCPDrift
+	for _, tool := range snapshot.Tools {
+		snapshotTools[tool.Name] = true
+		if !isMCPToolAllowed(allowed, tool.Name) {
+			continue
+		}
+		liveTool, ok := liveTools[tool.Name]
+		if !ok {
+			drifts = append(drifts, MCPDrift{Server: name, Tool: tool.Name, Kind: "removed"})
+			continue
+		}
+		if !reflect.DeepEqual(tool.InputSchema, liveTool.InputSchema) {
+			drifts = append(drifts, MCPDrift{Server: name, Tool: tool.Name, Kind: "changed"})
+		}
+	}
+	for _, tool := range live.Tools {
+		if !snapshotTools[tool.Name] {
+			drifts = append(drifts, MCPDrift{Server: name, Tool: tool.Name, Kind: "added"})
+		}
+	}
+	return drifts
+}
+
+// isMCPToolAllowed reports whether a server's allowed list lets the agent call a tool
+func isMCPToolAllowed(allowed []string, toolName string) bool {
+	return len(allowed) == 0 || slices.Contains(allowed, "*") || slices.Contains(allowed, toolName)
+}
+
+// loadSnapshotMCPConfigs parses a workflow with its imports and returns the MCP servers to
+// snapshot. Safe outputs and safe inputs are defined by the workflow itself and are skipped.
+func loadSnapshotMCPConfigs(workflowPath string, serverFilter string, verbose bool) ([]parser.MCPServerConfig, error) {
+	compiler := workflow.NewCompiler(workflow.WithVerbose(verbose))
+	workflowData, err := compiler.ParseWorkflowFile(workflowPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse workflow file: %w", err)
+	}
+	return snapshotMCPConfigsFromWorkflowData(workflowData, serverFilter)
+}
+
+// snapshotMCPConfigsFromWorkflowData returns the MCP servers of parsed workflow data to snapshot
+func snapshotMCPConfigsFromWorkflowData(workflowData *workflow.WorkflowData, serverFilter string) ([]parser.MCPServerConfig, error) {
+	mcpConfigs, err := parser.ExtractMCPConfigurations(buildFrontmatterFromWorkflowData(workflowData), serverFilter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to extract MCP configurations: %w", err)
+	}
+	return filterOutSafeOutputs(mcpConfigs), nil
+}
+
+// snapshotLiveMCPServer connects to a server and records its current tools
+func snapshotLiveMCPServer(config parser.MCPServerConfig, verbose bool) (*MCPServerSnapshot, error) {
+	if err := validateServerSecrets(config, verbose, false); err != nil {
+		return nil, fmt.Errorf("secret validation failed: %w", err)
+	}
+	info, err := connectToMCPServer(config, verbose)
+	if err != nil {
+		return nil, fmt.Errorf("connection failed: %w", err)
+	}
+	return buildMCPServerSnapshot(info)
+}
+
+// resolveAbsoluteWorkflowPath resolves a workflow id or file to an absolute path
+func resolveAbsoluteWorkflowPath(workflowFile string) (string, error) {
+	workflowPath, err := ResolveWorkflowPath(workflowFile)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Abs(workflowPath)
+}
+
+// SnapshotWorkflowMCP connects to the MCP servers of a workflow and writes their tools and
+// input schemas to the workflow's snapshot file. With a server filter, only that server
+// is updated and the other servers of an existing snapshot are kept.
+func SnapshotWorkflowMCP(workflowFile string, serverFilter string, verbose bool) error {
+	mcpSnapshotLog.Printf("Snapshotting MCP servers: workflow=%s, server=%s", workflowFile, serverFilter)
+
+	workflowPath, err := resolveAbsoluteWorkflowPath(workflowFile)
+	if err != nil {
+		return err
+	}
+	mcpConfigs, err := loadSnapshotMCPConfigs(workflowPath, serverFilter, verbose)
+	if err != nil {
+		return err
+	}
+	if len(mcpConfigs) == 0 {
+		if serverFilter != "" {
+			return fmt.Errorf("no MCP server named '%s' found in workflow", serverFilter)
+		}
+		return errors.New("no MCP servers found in workflow")
+	}
+
+	snapshotPath := mcpSnapshotPath(workflowPath)
+	snapshot := &MCPSnapshot{Servers: make(map[string]*MCPServerSnapshot)}
+	if serverFilter != "" {
+		existing, err := readMCPSnapshot(snapshotPath)
+		if err != nil {
+			return err
+		}
+		if existing != nil {
+			snapshot = existing
+		}
+	}
+
+	for _, config := range mcpConfigs {
+		server, err := snapshotLiveMCPServer(config, verbose)
+		if err != nil {
+			return fmt.Errorf("failed to snapshot MCP server '%s': %w", config.Name, err)
+		}
+		snapshot.Servers[config.Name] = server
+		fmt.Fprintln(os.Stderr, console.FormatSuccessMessage(fmt.Sprintf("Recorded %d tool(s) of MCP server '%s'", len(server.Tools), config.Name)))
+	}
+
+	if err := writeMCPSnapshot(snapshotPath, snapshot); err != nil {
+		return err
+	}
+	fmt.Fprintln(os.Stderr, console.FormatInfoMessage("Wrote MCP snapshot to "+console.ToRelativePath(snapshotPath)))
+	return nil
+}
+
+// verifyMCPSnapshot compares the live tools of the given servers with a snapshot
+func verifyMCPSnapshot(snapshot *MCPSnapshot, mcpConfigs []parser.MCPServerConfig, verbose bool) ([]MCPDrift, error) {
+	var drifts []MCPDrift
+	for _, config := range mcpConfigs {
+		live, err := snapshotLiveMCPServer(config, verbose)
+		if err != nil {
+			return nil, fmt.Errorf("failed to verify MCP server '%s': %w", config.Name, err)
+		}
+		drifts = append(drifts, diffMCPServerSnapshot(config.Name, snapshot.Servers[config.Name], live, config.Allowed)...)
+	}
+	return drifts, nil
+}
+
+// VerifyWorkflowMCP fails when the live tools of a workflow's MCP servers drifted from its snapshot
+func VerifyWorkflowMCP(workflowFile string, serverFilter string, verbose bool) error {
+	mcpSnapshotLog.Printf("Verifying MCP servers: workflow=%s, server=%s", workflowFile, serverFilter)
+
+	workflowPath, err := resolveAbsoluteWorkflowPath(workflowFile)
+	if err != nil {
+		return err
+	}
+	snapshotPath := mcpSnapshotPath(workflowPath)
+	snapshot, err := readMCPSnapshot(snapshotPath)
+	if err != nil {
+		return err
+	}
+	if snapshot == nil {
+		return fmt.Errorf("no MCP snapshot found at %s. Run 'gh aw mcp snapshot %s' first", console.ToRelativePath(snapshotPath), workflowFile)
+	}
+
+	mcpConfigs, err := loadSnapshotMCPConfigs(workflowPath, serverFilter, verbose)
+	if err != nil {
+		return err
+	}
+	drifts, err := verifyMCPSnapshot(snapshot, mcpConfigs, verbose)
+	if err != nil {
+		return err
+	}
+	if len(drifts) > 0 {
+		return newMCPDriftError(workflowFile, drifts)
+	}
+
+	fmt.Fprintln(os.Stderr, console.FormatSuccessMessage(fmt.Sprintf("%d MCP server(s) match the snapshot", len(mcpConfigs))))
+	return nil
+}
+
+// verifyWorkflowDataMCPSnapshot verifies the MCP servers of a compiled workflow against its
+// snapshot, if the workflow has one. Used by compile --validate.
+func verifyWorkflowDataMCPSnapshot(workflowData *workflow.WorkflowData, workflowPath string, verbose bool) error {
+	snapshot, err := readMCPSnapshot(mcpSnapshotPath(workflowPath))
+	if err != nil || snapshot == nil {
+		return err
+	}
+	mcpConfigs, err := snapshotMCPConfigsFromWorkflowData(workflowData, "")
+	if err != nil {
+		return err
+	}
+	drifts, err := verifyMCPSnapshot(snapshot, mcpConfigs, verbose)
+	if err != nil {
+		return err
+	}
+	if len(drifts) > 0 {
+		return newMCPDriftError(filepath.Base(workflowPath), drifts)
+	}
+	return nil
+}
+
+// newMCPDriftError formats drifts as a single error with a hint to refresh the snapshot
+func newMCPDriftError(workflowFile string, drifts []MCPDrift) error {
+	lines := make([]string, 0, len(drifts))
+	for _, drift := range drifts {
+		lines = append(lines, "  - "+drift.String())
+	}
+	return fmt.Errorf("MCP servers drifted from the snapshot:\n%s\nReview the changes and run 'gh aw mcp snapshot %s' to accept them", strings.Join(lines, "\n"), strings.TrimSuffix(workflowFile, ".md"))
+}
+
+// NewMCPSnapshotSubcommand creates the mcp snapshot subcommand
+func NewMCPSnapshotSubcommand() *cobra.Command {
+	var serverFilter string
+
+	cmd := &cobra.Command{
+		Use:   "snapshot <workflow>",
+		Short: "Record the tools and input schemas of a workflow's MCP servers",
+		Long: `Record the tools and input schemas of a workflow's MCP servers in a snapshot file.
+
+The snapshot is written next to the workflow as <workflow>.mcp-snapshot.json and is meant to be
+checked in. 'gh aw mcp verify' and 'gh aw compile --validate' fail when the live tools of a
+server drift from the snapshot, so an upstream MCP server upgrade cannot silently change what
+the agent can do.
+
+Examples:
+  gh aw mcp snapshot weekly-research                 # Snapshot all MCP servers
+  gh aw mcp snapshot weekly-research --server tavily # Refresh only the tavily server`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			verbose, _ := cmd.Flags().GetBool("verbose")
+			return SnapshotWorkflowMCP(args[0], serverFilter, verbose)
+		},
+	}
+
+	cmd.Flags().StringVar(&serverFilter, "server", "", "Snapshot only the specified MCP server")
+	cmd.ValidArgsFunction = CompleteWorkflowNames
+
+	return cmd
+}
+
+// NewMCPVerifySubcommand creates the mcp verify subcommand
+func NewMCPVerifySubcommand() *cobra.Command {
+	var serverFilter string
+
+	cmd := &cobra.Command{
+		Use:   "verify <workflow>",
+		Short: "Check a workflow's MCP servers against their snapshot",
+		Long: `Connect to a workflow's MCP servers and compare their live tools with the snapshot
+recorded by 'gh aw mcp snapshot'.
+
+The command fails when a tool was removed, its input schema changed, a new tool appeared,
+or a server has no snapshot yet. Removed and changed tools are only reported when they are
+in the server's allowed list; new tools are always reported.
+
+Examples:
+  gh aw mcp verify weekly-research                 # Verify all MCP servers
+  gh aw mcp verify weekly-research --server tavily # Verify only the tavily server`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			verbose, _ := cmd.Flags().GetBool("verbose")
+			return VerifyWorkflowMCP(args[0], serverFilter, verbose)
+		},
+	}
+
+	cmd.Flags().StringVar(&serverFilter, "server", "", "Verify only the specified MCP server")
+	cmd.ValidArgsFunction = CompleteWorkflowNames
+
+	return cmd
+}
@@ -0,0 +1,113 @@
+//go:build !integration
+
+package cli
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/github/gh-aw/pkg/parser"
+	"github.com/github/gh-aw/pkg/testutil"
+	"github.com/github/gh-aw/pkg/workflow"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMCPSnapshotPath(t *testing.T) {
+	assert.Equal(t, ".github/workflows/research.mcp-snapshot.json", mcpSnapshotPath(".github/workflows/research.md"), "Snapshot should live next to the workflow")
+}
+
+func TestBuildMCPServerSnapshot(t *testing.T) {
+	info := &parser.MCPServerInfo{
+		Config: parser.MCPServerConfig{Name: "tavily"},
+		Tools: []*mcp.Tool{
+			{Name: "search", InputSchema: map[string]any{"type": "object", "properties": map[string]any{"query": map[string]any{"type": "string"}}}},
+			{Name: "extract", InputSchema: map[string]any{"type": "object"}},
+		},
+	}
+	info.Config.Type = "stdio"
+
+	server, err := buildMCPServerSnapshot(info)
+	require.NoError(t, err, "Snapshot should be built")
+	assert.Equal(t, "stdio", server.Type, "Server type should be recorded")
+	require.Len(t, server.Tools, 2, "All tools should be recorded")
+	assert.Equal(t, "extract", server.Tools[0].Name, "Tools should be sorted by name")
+	assert.Equal(t, "search", server.Tools[1].Name, "Tools should be sorted by name")
+}
+
+func TestMCPSnapshotRoundTrip(t *testing.T) {
+	path := filepath.Join(testutil.TempDir(t, "mcp-snapshot"), "research.mcp-snapshot.json")
+
+	missing, err := readMCPSnapshot(path)
+	require.NoError(t, err, "Missing snapshot should not be an error")
+	assert.Nil(t, missing, "Missing snapshot should yield nil")
+
+	schema, err := normalizeMCPSchema(map[string]any{"type": "object", "required": []string{"query"}})
+	require.NoError(t, err, "Schema should normalize")
+	snapshot := &MCPSnapshot{Servers: map[string]*MCPServerSnapshot{
+		"tavily": {Type: "http", Tools: []MCPToolSnapshot{{Name: "search", InputSchema: schema}}},
+	}}
+	require.NoError(t, writeMCPSnapshot(path, snapshot), "Snapshot should be written")
+
+	read, err := readMCPSnapshot(path)
+	require.NoError(t, err, "Snapshot should be read")
+	assert.Empty(t, diffMCPServerSnapshot("tavily", read.Servers["tavily"], snapshot.Servers["tavily"], nil), "Snapshot read back should not drift")
+}
+
+func TestDiffMCPServerSnapshot(t *testing.T) {
+	schema := func(properties ...string) any {
+		props := map[string]any{}
+		for _, property := range properties {
+			props[property] = map[string]any{"type": "string"}
+		}
+		normalized, err := normalizeMCPSchema(map[string]any{"type": "object", "properties": props})
+		require.NoError(t, err, "Schema should normalize")
+		return normalized
+	}
+
+	snapshot := &MCPServerSnapshot{Tools: []MCPToolSnapshot{
+		{Name: "search", InputSchema: schema("query")},
+		{Name: "extract", InputSchema: schema("url")},
+		{Name: "crawl", InputSchema: schema("url")},
+	}}
+	live := &MCPServerSnapshot{Tools: []MCPToolSnapshot{
+		{Name: "search", InputSchema: schema("query")},
+		{Name: "extract", InputSchema: schema("url", "format")},
+		{Name: "delete", InputSchema: schema("id")},
+	}}
+
+	drifts := diffMCPServerSnapshot("tavily", snapshot, live, nil)
+	assert.Equal(t, []MCPDrift{
+		{Server: "tavily", Tool: "extract", Kind: "changed"},
+		{Server: "tavily", Tool: "crawl", Kind: "removed"},
+		{Server: "tavily", Tool: "delete", Kind: "added"},
+	}, drifts, "Changed, removed and added tools should be reported")
+
+	assert.Equal(t, drifts, diffMCPServerSnapshot("tavily", snapshot, live, []string{"*"}), "A wildcard allowed list should compare every tool")
+
+	assert.Equal(t, []MCPDrift{
+		{Server: "tavily", Tool: "extract", Kind: "changed"},
+		{Server: "tavily", Tool: "delete", Kind: "added"},
+	}, diffMCPServerSnapshot("tavily", snapshot, live, []string{"search", "extract"}), "Removed tools outside the allowed list should not drift, but new tools should always be reported")
+
+	assert.Empty(t, diffMCPServerSnapshot("tavily", live, live, nil), "Identical tools should not drift")
+	assert.Equal(t, []MCPDrift{{Server: "tavily", Kind: "unsnapshotted"}}, diffMCPServerSnapshot("tavily", nil, live, nil), "Servers without snapshot should be reported")
+}
+
+func TestNewMCPDriftError(t *testing.T) {
+	err := newMCPDriftError("research.md", []MCPDrift{
+		{Server: "tavily", Tool: "delete", Kind: "added"},
+		{Server: "github", Kind: "unsnapshotted"},
+	})
+	require.Error(t, err, "Drift should be an error")
+	assert.Contains(t, err.Error(), "tavily: new tool 'delete' is not in the snapshot", "Error should list added tools")
+	assert.Contains(t, err.Error(), "github: server is not in the snapshot", "Error should list unsnapshotted servers")
+	assert.Contains(t, err.Error(), "gh aw mcp snapshot research", "Error should explain how to accept the changes")
+}
+
+func TestVerifyWorkflowDataMCPSnapshotWithoutSnapshot(t *testing.T) {
+	workflowPath := filepath.Join(testutil.TempDir(t, "mcp-verify"), "research.md")
+	err := verifyWorkflowDataMCPSnapshot(&workflow.WorkflowData{}, workflowPath, false)
+	assert.NoError(t, err, "Workflows without snapshot should not be verified")
+}
@@ -0,0 +1,405 @@
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/github/gh-aw/pkg/console"
+	"github.com/github/gh-aw/pkg/logger"
+	"github.com/github/gh-aw/pkg/workflow"
+	"github.com/spf13/cobra"
+)
+
+var mcpTestSafeInputsLog = logger.New("cli:mcp_test_safe_inputs")
+
+// safeInputsFixturesSuffix is the suffix of the fixture file stored next to a workflow.
+// JSON is used instead of YAML so GitHub Actions never mistakes the file for a workflow.
+const safeInputsFixturesSuffix = ".safe-inputs-fixtures.json"
+
+// SafeInputsFixtures is the fixture file format for `mcp test-safe-inputs`.
+// Tools maps a safe-input tool name to the list of cases to run against it.
+type SafeInputsFixtures struct {
+	Tools map[string][]SafeInputsFixtureCase `json:"tools"`
+}
+
+// SafeInputsFixtureCase is a single fixture invocation of a safe-input tool
+type SafeInputsFixtureCase struct {
+	Name   string                  `json:"name"`
+	Inputs map[string]any          `json:"inputs,omitempty"`
+	Env    map[string]string       `json:"env,omitempty"`
+	Expect SafeInputsFixtureExpect `json:"expect"`
+}
+
+// SafeInputsFixtureExpect describes the expected result of a fixture case
+type SafeInputsFixtureExpect struct {
+	// Error expects the invocation to fail (invalid inputs, non-zero exit or timeout)
+	Error bool `json:"error,omitempty"`
+	// Output is matched against the tool result; objects match when every expected key matches
+	Output any `json:"output,omitempty"`
+	// Contains lists substrings that must appear in the JSON-encoded tool result
+	Contains []string `json:"contains,omitempty"`
+}
+
+// SafeInputsFixtureResult is the outcome of running a single fixture case
+type SafeInputsFixtureResult struct {
+	Tool     string        `json:"tool"`
+	Case     string        `json:"case"`
+	Passed   bool          `json:"passed"`
+	Failures []string      `json:"failures,omitempty"`
+	Output   any           `json:"output,omitempty"`
+	Error    string        `json:"error,omitempty"`
+	Duration time.Duration `json:"duration_ns"`
+}
+
+// NewMCPTestSafeInputsSubcommand creates the mcp test-safe-inputs subcommand
+func NewMCPTestSafeInputsSubcommand() *cobra.Command {
+	var fixturesPath string
+	var toolFilter string
+
+	cmd := &cobra.Command{
+		Use:   "test-safe-inputs <workflow>",
+		Short: "Run safe-inputs tools locally against fixture inputs",
+		Long: `Run each safe-inputs tool of a workflow locally against fixture inputs and check its outputs.
+
+Fixtures are read from <workflow>` + safeInputsFixturesSuffix + ` next to the workflow file
+unless --fixtures is given. The fixture file maps tool names to a list of cases:
+
+  {
+    "tools": {
+      "greet-user": [
+        { "name": "greets by name", "inputs": { "name": "Ada" }, "expect": { "output": { "message": "Hello, Ada!" } } },
+        { "name": "requires a name", "inputs": {}, "expect": { "error": true } }
+      ]
+    }
+  }
+
+For each case the command:
+- Validates the fixture inputs against the tool's input schema
+- Runs the tool handler locally (node, bash, python3 or go)
+- Validates the result against the tool's declared output schema
+- Checks the expected error, output and contains assertions
+
+Environment variables referenced by the tool's env: field are taken from the
+local environment or from the case's "env" object; secrets are never resolved.
+
+Examples:
+  gh aw mcp test-safe-inputs my-workflow
+  gh aw mcp test-safe-inputs my-workflow --tool greet-user
+  gh aw mcp test-safe-inputs my-workflow --fixtures testdata/fixtures.json --json`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			verbose, _ := cmd.Flags().GetBool("verbose")
+			jsonOutput, _ := cmd.Flags().GetBool("json")
+			return RunSafeInputsFixtures(args[0], fixturesPath, toolFilter, jsonOutput, verbose)
+		},
+	}
+
+	cmd.Flags().StringVar(&fixturesPath, "fixtures", "", "Path to the fixture file (default: <workflow>"+safeInputsFixturesSuffix+" next to the workflow)")
+	cmd.Flags().StringVar(&toolFilter, "tool", "", "Only run fixtures for the specified tool")
+	addJSONFlag(cmd)
+
+	cmd.ValidArgsFunction = CompleteWorkflowNames
+
+	return cmd
+}
+
+// RunSafeInputsFixtures runs the safe-inputs fixtures of a workflow and reports the results.
+// It returns an error when any fixture case fails.
+func RunSafeInputsFixtures(workflowFile, fixturesPath, toolFilter string, jsonOutput, verbose bool) error {
+	mcpTestSafeInputsLog.Printf("Testing safe-inputs: workflow=%s, fixtures=%s, tool=%s", workflowFile, fixturesPath, toolFilter)
+
+	workflowPath, err := ResolveWorkflowPath(workflowFile)
+	if err != nil {
+		return err
+	}
+
+	// Use the workflow compiler so imported safe-inputs are merged
+	compiler := workflow.NewCompiler(
+		workflow.WithVerbose(verbose),
+	)
+	workflowData, err := compiler.ParseWorkflowFile(workflowPath)
+	if err != nil {
+		return fmt.Errorf("failed to parse workflow file: %w", err)
+	}
+
+	safeInputsConfig := workflowData.SafeInputs
+	if !workflow.HasSafeInputs(safeInputsConfig) {
+		return errors.New("no safe-inputs configuration found in workflow")
+	}
+
+	if fixturesPath == "" {
+		fixturesPath = defaultSafeInputsFixturesPath(workflowPath)
+	}
+	fixtures, err := loadSafeInputsFixtures(fixturesPath)
+	if err != nil {
+		return err
+	}
+
+	if verbose {
+		fmt.Fprintln(os.Stderr, console.FormatInfoMessage("Using fixtures from "+fixturesPath))
+	}
+
+	results, err := runSafeInputsFixtures(safeInputsConfig, fixtures, toolFilter, verbose)
+	if err != nil {
+		return err
+	}
+
+	if jsonOutput {
+		jsonBytes, err := json.MarshalIndent(results, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal results: %w", err)
+		}
+		fmt.Println(string(jsonBytes))
+	} else {
+		renderSafeInputsFixtureResults(results)
+	}
+
+	failed := 0
+	for _, result := range results {
+		if !result.Passed {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d safe-inputs fixture(s) failed", failed, len(results))
+	}
+	return nil
+}
+
+// defaultSafeInputsFixturesPath returns the fixture file path stored next to the workflow
+func defaultSafeInputsFixturesPath(workflowPath string) string {
+	return strings.TrimSuffix(workflowPath, ".md") + safeInputsFixturesSuffix
+}
+
+// loadSafeInputsFixtures reads and parses a fixture file
+func loadSafeInputsFixtures(path string) (*SafeInputsFixtures, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("fixture file not found: %s", path)
+		}
+		return nil, fmt.Errorf("failed to read fixture file: %w", err)
+	}
+
+	var fixtures SafeInputsFixtures
+	if err := json.Unmarshal(data, &fixtures); err != nil {
+		return nil, fmt.Errorf("failed to parse fixture file %s: %w", path, err)
+	}
+	if len(fixtures.Tools) == 0 {
+		return nil, fmt.Errorf("fixture file %s does not define any tool fixtures", path)
+	}
+	return &fixtures, nil
+}
+
+// runSafeInputsFixtures runs every fixture case against the configured tools
+func runSafeInputsFixtures(safeInputsConfig *workflow.SafeInputsConfig, fixtures *SafeInputsFixtures, toolFilter string, verbose bool) ([]SafeInputsFixtureResult, error) {
+	toolNames := make([]string, 0, len(fixtures.Tools))
+	for toolName := range fixtures.Tools {
+		if toolFilter != "" && toolName != toolFilter {
+			continue
+		}
+		if _, exists := safeInputsConfig.Tools[toolName]; !exists {
+			return nil, fmt.Errorf("fixtures reference unknown safe-inputs tool '%s'", toolName)
+		}
+		toolNames = append(toolNames, toolName)
+	}
+	if len(toolNames) == 0 {
+		if toolFilter != "" {
+			return nil, fmt.Errorf("no fixtures found for tool '%s'", toolFilter)
+		}
+		return nil, errors.New("no fixtures to run")
+	}
+	sort.Strings(toolNames)
+
+	tmpDir, err := os.MkdirTemp("", "gh-aw-safe-inputs-test-*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temporary directory: %w", err)
+	}
+	defer func() {
+		if err := os.RemoveAll(tmpDir); err != nil {
+			mcpTestSafeInputsLog.Printf("Failed to clean up temporary directory %s: %v", tmpDir, err)
+		}
+	}()
+
+	var results []SafeInputsFixtureResult
+	for _, toolName := range toolNames {
+		toolConfig := safeInputsConfig.Tools[toolName]
+		runner, err := newSafeInputToolRunner(tmpDir, toolConfig)
+		if err != nil {
+			return nil, err
+		}
+
+		for i, fixtureCase := range fixtures.Tools[toolName] {
+			if fixtureCase.Name == "" {
+				fixtureCase.Name = fmt.Sprintf("case %d", i+1)
+			}
+			if verbose {
+				fmt.Fprintln(os.Stderr, console.FormatProgressMessage(fmt.Sprintf("Running %s: %s", toolName, fixtureCase.Name)))
+			}
+			results = append(results, runSafeInputsFixtureCase(runner, toolConfig, fixtureCase))
+		}
+	}
+
+	return results, nil
+}
+
+// runSafeInputsFixtureCase runs a single fixture case and evaluates its expectations
+func runSafeInputsFixtureCase(runner *safeInputToolRunner, toolConfig *workflow.SafeInputToolConfig, fixtureCase SafeInputsFixtureCase) SafeInputsFixtureResult {
+	result := SafeInputsFixtureResult{
+		Tool: toolConfig.Name,
+		Case: fixtureCase.Name,
+	}
+
+	inputs := fixtureCase.Inputs
+	if inputs == nil {
+		inputs = map[string]any{}
+	}
+
+	start := time.Now()
+	output, runErr := func() (any, error) {
+		inputSchema := workflow.GenerateSafeInputToolInputSchemaForInspector(toolConfig)
+		if err := workflow.ValidateSafeInputValue(inputSchema, inputs); err != nil {
+			return nil, fmt.Errorf("invalid inputs: %w", err)
+		}
+		output, err := runner.run(inputs, fixtureCase.Env)
+		if err != nil {
+			return nil, err
+		}
+		if toolConfig.Output != nil {
+			if err := workflow.ValidateSafeInputValue(toolConfig.Output, output); err != nil {
+				return output, fmt.Errorf("output does not match output schema: %w", err)
+			}
+		}
+		return output, nil
+	}()
+	result.Duration = time.Since(start)
+	result.Output = output
+	if runErr != nil {
+		result.Error = runErr.Error()
+	}
+
+	result.Failures = checkSafeInputsFixtureExpectations(fixtureCase.Expect, output, runErr)
+	result.Passed = len(result.Failures) == 0
+	return result
+}
+
+// checkSafeInputsFixtureExpectations compares a tool result with the expectations of a fixture case
+func checkSafeInputsFixtureExpectations(expect SafeInputsFixtureExpect, output any, runErr error) []string {
+	var failures []string
+
+	if expect.Error {
+		if runErr == nil {
+			failures = append(failures, "expected an error but the tool succeeded")
+		}
+		return failures
+	}
+	if runErr != nil {
+		return append(failures, runErr.Error())
+	}
+
+	if expect.Output != nil {
+		if mismatch := matchSafeInputsOutput(expect.Output, output, ""); mismatch != "" {
+			failures = append(failures, mismatch)
+		}
+	}
+
+	if len(expect.Contains) > 0 {
+		encoded, _ := json.Marshal(output)
+		for _, substring := range expect.Contains {
+			if !strings.Contains(string(encoded), substring) {
+				failures = append(failures, fmt.Sprintf("output does not contain %q", substring))
+			}
+		}
+	}
+
+	return failures
+}
+
+// matchSafeInputsOutput checks that actual matches expected. Objects match when every
+// expected key matches (extra keys in actual are ignored); all other values must be equal.
+// Returns a description of the first mismatch, or an empty string.
+func matchSafeInputsOutput(expected, actual any, path string) string {
+	location := path
+	if location == "" {
+		location = "/"
+	}
+
+	if expectedMap, ok := expected.(map[string]any); ok {
+		actualMap, ok := actual.(map[string]any)
+		if !ok {
+			return fmt.Sprintf("output at '%s': expected an object, got %s", location, formatFixtureValue(actual))
+		}
+		keys := make([]string, 0, len(expectedMap))
+		for key := range expectedMap {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			actualValue, exists := actualMap[key]
+			if !exists {
+				return fmt.Sprintf("output at '%s': missing key '%s'", location, key)
+			}
+			if mismatch := matchSafeInputsOutput(expectedMap[key], actualValue, path+"/"+key); mismatch != "" {
+				return mismatch
+			}
+		}
+		return ""
+	}
+
+	if !reflect.DeepEqual(expected, actual) {
+		return fmt.Sprintf("output at '%s': expected %s, got %s", location, formatFixtureValue(expected), formatFixtureValue(actual))
+	}
+	return ""
+}
+
+// formatFixtureValue renders a value as compact JSON for failure messages
+func formatFixtureValue(value any) string {
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprintf("%v", value)
+	}
+	return string(encoded)
+}
+
+// renderSafeInputsFixtureResults prints the fixture results as a table followed by failure details
+func renderSafeInputsFixtureResults(results []SafeInputsFixtureResult) {
+	rows := make([][]string, 0, len(results))
+	passed := 0
+	for _, result := range results {
+		status := "✗ fail"
+		if result.Passed {
+			status = "✓ pass"
+			passed++
+		}
+		rows = append(rows, []string{result.Tool, result.Case, status, result.Duration.Round(time.Millisecond).String()})
+	}
+
+	fmt.Fprint(os.Stderr, console.RenderTable(console.TableConfig{
+		Title:   "Safe-Inputs Fixtures",
+		Headers: []string{"Tool", "Case", "Result", "Duration"},
+		Rows:    rows,
+	}))
+
+	for _, result := range results {
+		if result.Passed {
+			continue
+		}
+		fmt.Fprintln(os.Stderr, console.FormatErrorMessage(fmt.Sprintf("%s: %s", result.Tool, result.Case)))
+		for _, failure := range result.Failures {
+			fmt.Fprintln(os.Stderr, console.FormatListItem(failure))
+		}
+	}
+
+	summary := fmt.Sprintf("%d of %d fixture(s) passed", passed, len(results))
+	if passed == len(results) {
+		fmt.Fprintln(os.Stderr, console.FormatSuccessMessage(summary))
+	} else {
+		fmt.Fprintln(os.Stderr, console.FormatWarningMessage(summary))
+	}
+}
@@ -0,0 +1,205 @@
+package cli
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/github/gh-aw/pkg/workflow"
+)
+
+// safeInputsJavaScriptRunner loads a generated JavaScript tool module, passes it the
+// JSON inputs from stdin and prints the JSON-encoded result to stdout
+const safeInputsJavaScriptRunner = `const { execute } = require(process.argv[1]);
+let data = "";
+process.stdin.on("data", chunk => (data += chunk));
+process.stdin.on("end", async () => {
+  const result = await execute(data.trim() ? JSON.parse(data) : {});
+  process.stdout.write(JSON.stringify(result === undefined ? null : result));
+});
+`
+
+// safeInputToolRunner executes a single safe-input tool handler locally, mirroring
+// the conventions of the safe-inputs MCP server handlers:
+//   - JavaScript, Python and Go tools read JSON inputs from stdin and write JSON to stdout
+//   - Shell tools receive INPUT_* environment variables and write key=value lines to GITHUB_OUTPUT
+type safeInputToolRunner struct {
+	toolConfig  *workflow.SafeInputToolConfig
+	handlerPath string
+	command     []string
+}
+
+// newSafeInputToolRunner writes the tool handler into dir and prepares the command that runs it
+func newSafeInputToolRunner(dir string, toolConfig *workflow.SafeInputToolConfig) (*safeInputToolRunner, error) {
+	runner := &safeInputToolRunner{toolConfig: toolConfig}
+
+	var content, extension string
+	switch {
+	case toolConfig.Script != "":
+		content = workflow.GenerateSafeInputJavaScriptToolScriptForInspector(toolConfig)
+		extension = ".cjs"
+	case toolConfig.Run != "":
+		content = workflow.GenerateSafeInputShellToolScriptForInspector(toolConfig)
+		extension = ".sh"
+	case toolConfig.Py != "":
+		content = workflow.GenerateSafeInputPythonToolScriptForInspector(toolConfig)
+		extension = ".py"
+	case toolConfig.Go != "":
+		content = workflow.GenerateSafeInputGoToolScriptForInspector(toolConfig)
+		extension = ".go"
+	default:
+		return nil, fmt.Errorf("safe-inputs tool '%s' has no implementation", toolConfig.Name)
+	}
+
+	runner.handlerPath = filepath.Join(dir, toolConfig.Name+extension)
+	if err := os.WriteFile(runner.handlerPath, []byte(content), 0755); err != nil {
+		return nil, fmt.Errorf("failed to write tool %s: %w", toolConfig.Name, err)
+	}
+
+	var interpreter string
+	switch extension {
+	case ".cjs":
+		interpreter = "node"
+		runner.command = []string{"node", "-e", safeInputsJavaScriptRunner, runner.handlerPath}
+	case ".sh":
+		interpreter = "bash"
+		runner.command = []string{"bash", runner.handlerPath}
+	case ".py":
+		interpreter = "python3"
+		runner.command = []string{"python3", runner.handlerPath}
+	case ".go":
+		interpreter = "go"
+		runner.command = []string{"go", "run", runner.handlerPath}
+	}
+
+	if _, err := exec.LookPath(interpreter); err != nil {
+		return nil, fmt.Errorf("%s not found. It is required to run safe-inputs tool '%s': %w", interpreter, toolConfig.Name, err)
+	}
+
+	return runner, nil
+}
+
+// run executes the tool with the given inputs and returns the decoded result
+func (r *safeInputToolRunner) run(inputs map[string]any, env map[string]string) (any, error) {
+	timeout := time.Duration(r.toolConfig.Timeout) * time.Second
+	if timeout <= 0 {
+		timeout = 60 * time.Second
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	inputJSON, err := json.Marshal(inputs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode inputs: %w", err)
+	}
+
+	cmd := exec.CommandContext(ctx, r.command[0], r.command[1:]...)
+	cmd.Env = os.Environ()
+	for name, value := range env {
+		cmd.Env = append(cmd.Env, name+"="+value)
+	}
+
+	var outputFile string
+	if r.toolConfig.Run != "" {
+		for name, value := range inputs {
+			cmd.Env = append(cmd.Env, safeInputEnvName(name)+"="+safeInputEnvValue(value))
+		}
+		outputFile = r.handlerPath + ".output"
+		if err := os.WriteFile(outputFile, nil, 0644); err != nil {
+			return nil, fmt.Errorf("failed to create output file: %w", err)
+		}
+		defer os.Remove(outputFile)
+		cmd.Env = append(cmd.Env, "GITHUB_OUTPUT="+outputFile)
+	} else {
+		cmd.Stdin = bytes.NewReader(inputJSON)
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return nil, fmt.Errorf("tool timed out after %s", timeout)
+		}
+		message := strings.TrimSpace(stderr.String())
+		if message == "" {
+			message = err.Error()
+		}
+		return nil, fmt.Errorf("tool failed: %s", message)
+	}
+
+	if r.toolConfig.Run != "" {
+		outputs, err := readSafeInputShellOutputs(outputFile)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]any{
+			"stdout":  stdout.String(),
+			"stderr":  stderr.String(),
+			"outputs": outputs,
+		}, nil
+	}
+
+	trimmed := strings.TrimSpace(stdout.String())
+	if trimmed != "" {
+		var result any
+		if err := json.Unmarshal([]byte(trimmed), &result); err == nil {
+			return result, nil
+		}
+	}
+	return map[string]any{
+		"stdout": stdout.String(),
+		"stderr": stderr.String(),
+	}, nil
+}
+
+// safeInputEnvName converts an input name to the INPUT_* environment variable used by shell tools
+func safeInputEnvName(name string) string {
+	return "INPUT_" + strings.ReplaceAll(strings.ToUpper(name), "-", "_")
+}
+
+// safeInputEnvValue encodes an input value for a shell tool; objects and arrays are JSON-encoded
+func safeInputEnvValue(value any) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case map[string]any, []any:
+		encoded, _ := json.Marshal(v)
+		return string(encoded)
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
+
+// readSafeInputShellOutputs parses the key=value lines a shell tool wrote to GITHUB_OUTPUT
+func readSafeInputShellOutputs(path string) (map[string]any, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read tool outputs: %w", err)
+	}
+	defer file.Close()
+
+	outputs := make(map[string]any)
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		key, value, found := strings.Cut(line, "=")
+		if !found || key == "" {
+			continue
+		}
+		outputs[key] = value
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read tool outputs: %w", err)
+	}
+	return outputs, nil
+}
@@ -0,0 +1,204 @@
+//go:build !integration
+
+package cli
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/github/gh-aw/pkg/workflow"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMatchSafeInputsOutput(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected any
+		actual   any
+		mismatch string
+	}{
+		{
+			name:     "partial object match ignores extra keys",
+			expected: map[string]any{"count": float64(2)},
+			actual:   map[string]any{"count": float64(2), "extra": "ignored"},
+		},
+		{
+			name:     "nested mismatch reports location",
+			expected: map[string]any{"result": map[string]any{"state": "open"}},
+			actual:   map[string]any{"result": map[string]any{"state": "closed"}},
+			mismatch: `output at '/result/state': expected "open", got "closed"`,
+		},
+		{
+			name:     "missing key",
+			expected: map[string]any{"count": float64(2)},
+			actual:   map[string]any{},
+			mismatch: "output at '/': missing key 'count'",
+		},
+		{
+			name:     "arrays must match exactly",
+			expected: []any{"a", "b"},
+			actual:   []any{"a"},
+			mismatch: `output at '/': expected ["a","b"], got ["a"]`,
+		},
+		{
+			name:     "object expected but scalar returned",
+			expected: map[string]any{"a": "b"},
+			actual:   "text",
+			mismatch: `output at '/': expected an object, got "text"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.mismatch, matchSafeInputsOutput(tt.expected, tt.actual, ""), "Mismatch description should match")
+		})
+	}
+}
+
+func TestCheckSafeInputsFixtureExpectations(t *testing.T) {
+	output := map[string]any{"message": "Hello, Ada!"}
+
+	assert.Empty(t, checkSafeInputsFixtureExpectations(SafeInputsFixtureExpect{Contains: []string{"Ada"}}, output, nil), "Contains assertion should pass")
+	assert.Equal(t, []string{`output does not contain "Bob"`}, checkSafeInputsFixtureExpectations(SafeInputsFixtureExpect{Contains: []string{"Bob"}}, output, nil), "Contains assertion should fail")
+	assert.Empty(t, checkSafeInputsFixtureExpectations(SafeInputsFixtureExpect{Error: true}, nil, errors.New("boom")), "Expected error should pass")
+	assert.Equal(t, []string{"expected an error but the tool succeeded"}, checkSafeInputsFixtureExpectations(SafeInputsFixtureExpect{Error: true}, output, nil), "Missing error should fail")
+	assert.Equal(t, []string{"boom"}, checkSafeInputsFixtureExpectations(SafeInputsFixtureExpect{}, nil, errors.New("boom")), "Unexpected error should fail")
+}
+
+func TestLoadSafeInputsFixtures(t *testing.T) {
+	dir := t.TempDir()
+
+	_, err := loadSafeInputsFixtures(filepath.Join(dir, "missing.json"))
+	require.Error(t, err, "Missing fixture file should fail")
+	assert.Contains(t, err.Error(), "fixture file not found", "Error should mention the missing file")
+
+	emptyPath := filepath.Join(dir, "empty.json")
+	require.NoError(t, os.WriteFile(emptyPath, []byte(`{"tools":{}}`), 0644))
+	_, err = loadSafeInputsFixtures(emptyPath)
+	require.Error(t, err, "Fixture file without tools should fail")
+
+	validPath := filepath.Join(dir, "valid.json")
+	require.NoError(t, os.WriteFile(validPath, []byte(`{"tools":{"echo":[{"name":"basic","inputs":{"message":"hi"}}]}}`), 0644))
+	fixtures, err := loadSafeInputsFixtures(validPath)
+	require.NoError(t, err, "Valid fixture file should load")
+	assert.Equal(t, "hi", fixtures.Tools["echo"][0].Inputs["message"], "Inputs should be parsed")
+}
+
+func TestDefaultSafeInputsFixturesPath(t *testing.T) {
+	assert.Equal(t, filepath.Join(".github", "workflows", "triage.safe-inputs-fixtures.json"), defaultSafeInputsFixturesPath(filepath.Join(".github", "workflows", "triage.md")))
+}
+
+func TestRunSafeInputsFixtures_Shell(t *testing.T) {
+	if _, err := exec.LookPath("bash"); err != nil {
+		t.Skip("bash not available")
+	}
+
+	config := &workflow.SafeInputsConfig{
+		Tools: map[string]*workflow.SafeInputToolConfig{
+			"greet": {
+				Name:        "greet",
+				Description: "Greet a user",
+				Run:         `echo "greeting=Hello, $INPUT_NAME" >> "$GITHUB_OUTPUT"` + "\n" + `echo "tags=$INPUT_TAGS" >> "$GITHUB_OUTPUT"`,
+				Timeout:     10,
+				Inputs: map[string]*workflow.SafeInputParam{
+					"name": {Type: "string", Required: true},
+					"tags": {Type: "array", Items: &workflow.SafeInputParam{Type: "string"}},
+				},
+				Output: map[string]any{
+					"type": "object",
+					"properties": map[string]any{
+						"outputs": map[string]any{"type": "object", "required": []any{"greeting"}},
+					},
+				},
+			},
+		},
+	}
+	fixtures := &SafeInputsFixtures{
+		Tools: map[string][]SafeInputsFixtureCase{
+			"greet": {
+				{
+					Name:   "greets by name",
+					Inputs: map[string]any{"name": "Ada", "tags": []any{"a", "b"}},
+					Expect: SafeInputsFixtureExpect{Output: map[string]any{"outputs": map[string]any{"greeting": "Hello, Ada", "tags": `["a","b"]`}}},
+				},
+				{
+					Name:   "requires a name",
+					Inputs: map[string]any{},
+					Expect: SafeInputsFixtureExpect{Error: true},
+				},
+				{
+					Name:   "wrong expectation",
+					Inputs: map[string]any{"name": "Ada"},
+					Expect: SafeInputsFixtureExpect{Output: map[string]any{"outputs": map[string]any{"greeting": "Hi"}}},
+				},
+			},
+		},
+	}
+
+	results, err := runSafeInputsFixtures(config, fixtures, "", false)
+	require.NoError(t, err, "Fixtures should run")
+	require.Len(t, results, 3, "Each case should produce a result")
+
+	assert.True(t, results[0].Passed, "Matching case should pass: %v", results[0].Failures)
+	assert.True(t, results[1].Passed, "Invalid inputs should satisfy the error expectation: %v", results[1].Failures)
+	assert.False(t, results[2].Passed, "Mismatching case should fail")
+}
+
+func TestRunSafeInputsFixtures_OutputSchemaViolation(t *testing.T) {
+	if _, err := exec.LookPath("python3"); err != nil {
+		t.Skip("python3 not available")
+	}
+
+	config := &workflow.SafeInputsConfig{
+		Tools: map[string]*workflow.SafeInputToolConfig{
+			"count": {
+				Name:        "count",
+				Description: "Count items",
+				Py:          "print(json.dumps({'count': len(inputs.get('items', []))}))",
+				Timeout:     10,
+				Inputs: map[string]*workflow.SafeInputParam{
+					"items": {Type: "array"},
+				},
+				Output: map[string]any{
+					"type":       "object",
+					"properties": map[string]any{"count": map[string]any{"type": "integer", "maximum": 2}},
+				},
+			},
+		},
+	}
+	fixtures := &SafeInputsFixtures{
+		Tools: map[string][]SafeInputsFixtureCase{
+			"count": {
+				{Name: "within schema", Inputs: map[string]any{"items": []any{1, 2}}, Expect: SafeInputsFixtureExpect{Output: map[string]any{"count": float64(2)}}},
+				{Name: "violates schema", Inputs: map[string]any{"items": []any{1, 2, 3}}},
+			},
+		},
+	}
+
+	results, err := runSafeInputsFixtures(config, fixtures, "count", false)
+	require.NoError(t, err, "Fixtures should run")
+	require.Len(t, results, 2, "Each case should produce a result")
+
+	assert.True(t, results[0].Passed, "Output within schema should pass: %v", results[0].Failures)
+	assert.False(t, results[1].Passed, "Output violating schema should fail")
+	assert.Contains(t, results[1].Error, "output does not match output schema", "Failure should mention the output schema")
+}
+
+func TestRunSafeInputsFixtures_UnknownTool(t *testing.T) {
+	config := &workflow.SafeInputsConfig{
+		Tools: map[string]*workflow.SafeInputToolConfig{
+			"known": {Name: "known", Run: "echo"},
+		},
+	}
+	fixtures := &SafeInputsFixtures{
+		Tools: map[string][]SafeInputsFixtureCase{"unknown": {{Name: "case"}}},
+	}
+
+	_, err := runSafeInputsFixtures(config, fixtures, "", false)
+	require.Error(t, err, "Unknown tools should be rejected")
+	assert.Contains(t, err.Error(), "unknown safe-inputs tool 'unknown'", "Error should name the unknown tool")
+}
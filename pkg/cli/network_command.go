@@ -0,0 +1,225 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/github/gh-aw/pkg/console"
+	"github.com/github/gh-aw/pkg/logger"
+	"github.com/github/gh-aw/pkg/workflow"
+	"github.com/spf13/cobra"
+)
+
+var networkCommandLog = logger.New("cli:network_command")
+
+// NewNetworkCommand creates the main network command with subcommands
+func NewNetworkCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "network",
+		Short: "Inspect and tighten the network allow-lists of workflows",
+		Long: `Inspect and tighten the network allow-lists of agentic workflows.
+
+Available subcommands:
+  • suggest - Learn a minimal network.allowed list from the firewall logs of recent runs
+
+Examples:
+  gh aw network suggest daily-status                    # Compare declared and observed domains
+  gh aw network suggest daily-status --runs 20 --write  # Rewrite network.allowed from the last 20 runs`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cmd.Help()
+		},
+	}
+
+	cmd.AddCommand(newNetworkSuggestSubcommand())
+
+	return cmd
+}
+
+// newNetworkSuggestSubcommand creates the network suggest subcommand
+func newNetworkSuggestSubcommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "suggest <workflow>",
+		Short: "Learn a minimal network allow-list from the firewall logs of recent runs",
+		Long: `Aggregate the allowed and blocked domains from the firewall logs of recent runs of a
+workflow and propose a minimal network.allowed list.
+
+Domains are expressed as ecosystem identifiers (e.g. python, node) where possible and as
+literal domains otherwise. Domains that are allowed automatically (engine defaults, HTTP MCP
+servers, Playwright and runtimes) are not listed. Declared entries that no run requested are
+proposed for removal. Domains that were only ever blocked are reported, and are proposed for
+addition with --include-blocked.
+
+Run artifacts are downloaded to the logs cache directory (.github/aw/logs), so runs already
+downloaded by 'gh aw logs' are not downloaded again.
+
+With --write, network.allowed in the workflow's frontmatter is replaced with the suggested list.
+Entries contributed by imports are kept in the imports and not written to the workflow.
+
+Examples:
+  gh aw network suggest daily-status              # Learn from the last 10 runs
+  gh aw network suggest daily-status --runs 30    # Learn from the last 30 runs
+  gh aw network suggest daily-status --write      # Apply the suggestion to the frontmatter
+  gh aw network suggest daily-status --include-blocked --write  # Also allow blocked domains
+  gh aw network suggest daily-status --json       # Output in JSON format`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			runs, _ := cmd.Flags().GetInt("runs")
+			write, _ := cmd.Flags().GetBool("write")
+			includeBlocked, _ := cmd.Flags().GetBool("include-blocked")
+			repoOverride, _ := cmd.Flags().GetString("repo")
+			outputDir, _ := cmd.Flags().GetString("output")
+			verbose, _ := cmd.Flags().GetBool("verbose")
+			jsonOutput, _ := cmd.Flags().GetBool("json")
+			return RunNetworkSuggest(cmd.Context(), args[0], runs, outputDir, repoOverride, write, includeBlocked, jsonOutput, verbose)
+		},
+	}
+
+	cmd.Flags().Int("runs", 10, "Number of recent runs to learn from")
+	cmd.Flags().Bool("write", false, "Rewrite network.allowed in the workflow's frontmatter")
+	cmd.Flags().Bool("include-blocked", false, "Add domains that were only ever blocked to the suggested allow-list")
+	addRepoFlag(cmd)
+	addOutputFlag(cmd, defaultLogsOutputDir)
+	addJSONFlag(cmd)
+	cmd.ValidArgsFunction = CompleteWorkflowNames
+
+	return cmd
+}
+
+// RunNetworkSuggest learns a minimal network allow-list for a workflow from the firewall logs
+// of its recent runs and optionally writes it to the frontmatter
+func RunNetworkSuggest(ctx context.Context, workflowName string, runCount int, outputDir, repoOverride string, write bool, includeBlocked bool, jsonOutput bool, verbose bool) error {
+	networkCommandLog.Printf("Suggesting network allow-list for workflow: %s (runs=%d, write=%v, include_blocked=%v)", workflowName, runCount, write, includeBlocked)
+	if runCount < 1 {
+		return errors.New("--runs must be at least 1")
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	workflowPath, err := resolveWorkflowFile(workflowName, verbose)
+	if err != nil {
+		return err
+	}
+	compiler := workflow.NewCompiler(workflow.WithVerbose(verbose))
+	workflowData, err := compiler.ParseWorkflowFile(workflowPath)
+	if err != nil {
+		return err
+	}
+
+	displayName, err := workflow.FindWorkflowName(workflow.GetWorkflowIDFromPath(workflowPath))
+	if err != nil {
+		return err
+	}
+	runs, _, err := listWorkflowRunsWithPagination(ListWorkflowRunsOptions{
+		WorkflowName: displayName,
+		Limit:        runCount,
+		RepoOverride: repoOverride,
+		Verbose:      verbose,
+	})
+	if err != nil {
+		return err
+	}
+	if len(runs) == 0 {
+		return fmt.Errorf("no runs found for workflow '%s'", displayName)
+	}
+
+	results := downloadRunArtifactsConcurrent(ctx, runs, outputDir, verbose, runCount)
+	var analyses []*FirewallAnalysis
+	for _, result := range results {
+		if result.Error != nil || result.Skipped || result.FirewallAnalysis == nil {
+			continue
+		}
+		analyses = append(analyses, result.FirewallAnalysis)
+	}
+	if len(analyses) == 0 {
+		return fmt.Errorf("none of the %d runs of '%s' have firewall logs", len(runs), displayName)
+	}
+
+	suggestion := workflow.SuggestNetworkAllowed(workflowData, workflowPath, aggregateObservedDomains(analyses), len(analyses), includeBlocked)
+
+	if jsonOutput {
+		data, err := json.MarshalIndent(suggestion, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal network suggestion: %w", err)
+		}
+		fmt.Println(string(data))
+	} else {
+		fmt.Fprintln(os.Stderr, console.FormatInfoMessage(fmt.Sprintf("Network allow-list of %s learned from %d runs:", suggestion.Workflow, suggestion.Runs)))
+		fmt.Print(console.RenderStruct(suggestion.Changes))
+		if !suggestion.HasChanges() {
+			fmt.Fprintln(os.Stderr, console.FormatSuccessMessage("The declared allow-list matches the observed domains"))
+		}
+	}
+
+	if !write || !suggestion.HasChanges() {
+		return nil
+	}
+
+	content, err := os.ReadFile(workflowPath)
+	if err != nil {
+		return fmt.Errorf("failed to read workflow file: %w", err)
+	}
+	updated, err := SetListInFrontmatterBlock(string(content), "network", "allowed", suggestion.FrontmatterAllowed())
+	if err != nil {
+		return fmt.Errorf("failed to update network allow-list: %w", err)
+	}
+	if err := os.WriteFile(workflowPath, []byte(updated), 0644); err != nil {
+		return fmt.Errorf("failed to write workflow file: %w", err)
+	}
+
+	if !jsonOutput {
+		fmt.Fprintln(os.Stderr, console.FormatSuccessMessage("Updated network.allowed in "+workflowPath))
+		fmt.Fprintln(os.Stderr, console.FormatInfoMessage("Run 'gh aw compile' to regenerate the lock file"))
+	}
+	return nil
+}
+
+// aggregateObservedDomains merges the per-domain request counts of several firewall analyses.
+// Ports are stripped from domains and entries without a domain are skipped.
+func aggregateObservedDomains(analyses []*FirewallAnalysis) []workflow.ObservedDomain {
+	byDomain := make(map[string]*workflow.ObservedDomain)
+	add := func(domain string, allowed, blocked int) {
+		if host, _, found := strings.Cut(domain, ":"); found {
+			domain = host
+		}
+		if domain == "" || domain == "-" {
+			return
+		}
+		observed, exists := byDomain[domain]
+		if !exists {
+			observed = &workflow.ObservedDomain{Domain: domain}
+			byDomain[domain] = observed
+		}
+		observed.Allowed += allowed
+		observed.Blocked += blocked
+	}
+
+	for _, analysis := range analyses {
+		if len(analysis.RequestsByDomain) > 0 {
+			for domain, stats := range analysis.RequestsByDomain {
+				add(domain, stats.Allowed, stats.Blocked)
+			}
+			continue
+		}
+		// Cached summaries may only contain the domain buckets
+		for _, domain := range analysis.AllowedDomains {
+			add(domain, 1, 0)
+		}
+		for _, domain := range analysis.BlockedDomains {
+			add(domain, 0, 1)
+		}
+	}
+
+	result := make([]workflow.ObservedDomain, 0, len(byDomain))
+	for _, observed := range byDomain {
+		result = append(result, *observed)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Domain < result[j].Domain })
+	networkCommandLog.Printf("Aggregated %d domains from %d firewall analyses", len(result), len(analyses))
+	return result
+}
@@ -0,0 +1,40 @@
+//go:build !integration
+
+package cli
+
+import (
+	"testing"
+
+	"github.com/github/gh-aw/pkg/workflow"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAggregateObservedDomains(t *testing.T) {
+	analyses := []*FirewallAnalysis{
+		{
+			RequestsByDomain: map[string]DomainRequestStats{
+				"api.github.com:443": {Allowed: 3},
+				"pypi.org:443":       {Blocked: 1},
+				"-":                  {Blocked: 2},
+			},
+		},
+		{
+			RequestsByDomain: map[string]DomainRequestStats{
+				"pypi.org:443": {Allowed: 1, Blocked: 2},
+			},
+		},
+		{
+			DomainBuckets: DomainBuckets{
+				AllowedDomains: []string{"api.github.com:443"},
+				BlockedDomains: []string{"example.com:443"},
+			},
+		},
+	}
+
+	expected := []workflow.ObservedDomain{
+		{Domain: "api.github.com", Allowed: 4},
+		{Domain: "example.com", Blocked: 1},
+		{Domain: "pypi.org", Allowed: 1, Blocked: 3},
+	}
+	assert.Equal(t, expected, aggregateObservedDomains(analyses), "domains should be merged without ports")
+}
@@ -0,0 +1,138 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/github/gh-aw/pkg/console"
+	"github.com/github/gh-aw/pkg/logger"
+	"github.com/github/gh-aw/pkg/workflow"
+	"github.com/spf13/cobra"
+)
+
+var permissionsCommandLog = logger.New("cli:permissions_command")
+
+// NewPermissionsCommand creates the main permissions command with subcommands
+func NewPermissionsCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "permissions",
+		Short: "Inspect and tighten the permissions requested by workflows",
+		Long: `Inspect and tighten the GitHub Actions permissions requested by agentic workflows.
+
+Available subcommands:
+  • suggest - Compute the least-privilege permissions of a workflow
+
+Examples:
+  gh aw permissions suggest daily-status          # Compare declared and least-privilege permissions
+  gh aw permissions suggest daily-status --write  # Rewrite the permissions in the frontmatter`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cmd.Help()
+		},
+	}
+
+	cmd.AddCommand(newPermissionsSuggestSubcommand())
+
+	return cmd
+}
+
+// newPermissionsSuggestSubcommand creates the permissions suggest subcommand
+func newPermissionsSuggestSubcommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "suggest <workflow>",
+		Short: "Compute the least-privilege permissions of a workflow",
+		Long: `Compute the minimal permissions the agent job of a workflow needs and compare them with
+the permissions it declares.
+
+The least-privilege set is inferred from:
+  • the enabled GitHub toolsets, narrowed to the toolsets of the allowed tools
+  • the read-only setting of the GitHub tool
+  • commands and actions used in custom steps (git push, gh issue, gh pr, cloud logins)
+  • the agentic-workflows tool
+
+Steps that call gh api, actions/github-script or use GITHUB_TOKEN, and safe-input tools that
+receive GITHUB_TOKEN, cannot be inferred: they are reported as notes and the declared
+permissions are kept.
+
+Safe outputs and repo-memory pushes run in separate jobs that are granted their own write
+permissions, so the agent job does not need them.
+
+With --write, the permissions block of the workflow's frontmatter is replaced with the
+suggested permissions. Permissions contributed by imports are not rewritten.
+
+Examples:
+  gh aw permissions suggest daily-status           # Show the suggested changes
+  gh aw permissions suggest daily-status --write   # Apply them to the frontmatter
+  gh aw permissions suggest daily-status --json    # Output in JSON format`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			write, _ := cmd.Flags().GetBool("write")
+			verbose, _ := cmd.Flags().GetBool("verbose")
+			jsonOutput, _ := cmd.Flags().GetBool("json")
+			return RunPermissionsSuggest(args[0], write, jsonOutput, verbose)
+		},
+	}
+
+	cmd.Flags().Bool("write", false, "Rewrite the permissions in the workflow's frontmatter")
+	addJSONFlag(cmd)
+	cmd.ValidArgsFunction = CompleteWorkflowNames
+
+	return cmd
+}
+
+// RunPermissionsSuggest prints the least-privilege permissions of a workflow and optionally
+// writes them to its frontmatter
+func RunPermissionsSuggest(workflowName string, write bool, jsonOutput bool, verbose bool) error {
+	permissionsCommandLog.Printf("Suggesting permissions for workflow: %s (write=%v)", workflowName, write)
+
+	workflowPath, err := resolveWorkflowFile(workflowName, verbose)
+	if err != nil {
+		return err
+	}
+
+	compiler := workflow.NewCompiler(workflow.WithVerbose(verbose))
+	suggestion, err := compiler.SuggestWorkflowPermissions(workflowPath)
+	if err != nil {
+		return err
+	}
+
+	if jsonOutput {
+		data, err := json.MarshalIndent(suggestion, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal permission suggestion: %w", err)
+		}
+		fmt.Println(string(data))
+	} else {
+		if suggestion.HasChanges() {
+			fmt.Fprintln(os.Stderr, console.FormatInfoMessage("Suggested permission changes for "+suggestion.Workflow+":"))
+			fmt.Print(console.RenderStruct(suggestion.Changes))
+		} else {
+			fmt.Fprintln(os.Stderr, console.FormatSuccessMessage(suggestion.Workflow+" already declares least-privilege permissions"))
+		}
+		for _, note := range suggestion.Notes {
+			fmt.Fprintln(os.Stderr, console.FormatWarningMessage(note))
+		}
+	}
+
+	if !write || !suggestion.HasChanges() {
+		return nil
+	}
+
+	content, err := os.ReadFile(workflowPath)
+	if err != nil {
+		return fmt.Errorf("failed to read workflow file: %w", err)
+	}
+	updated, err := ReplaceBlockInFrontmatter(string(content), "permissions", suggestion.RenderSuggestedYAML())
+	if err != nil {
+		return fmt.Errorf("failed to update permissions: %w", err)
+	}
+	if err := os.WriteFile(workflowPath, []byte(updated), 0644); err != nil {
+		return fmt.Errorf("failed to write workflow file: %w", err)
+	}
+
+	if !jsonOutput {
+		fmt.Fprintln(os.Stderr, console.FormatSuccessMessage("Updated permissions in "+workflowPath))
+		fmt.Fprintln(os.Stderr, console.FormatInfoMessage("Run 'gh aw compile' to regenerate the lock file"))
+	}
+	return nil
+}
@@ -0,0 +1,279 @@
+// This file provides the provenance command, which verifies signed provenance of lock files.
+//
+// `gh aw compile --provenance-key <key>` writes a DSSE-signed in-toto statement next to each
+// lock file (<workflow>.provenance.json). `gh aw provenance verify` checks in CI that:
+//
+//   - the statement is signed by the trusted key
+//   - the statement was signed for this lock file, compiled from its own markdown
+//   - the lock file is byte-identical to the one that was signed
+//   - the source markdown and local imports in the checkout are the ones it was compiled from
+//
+// so that only lock files generated by the compiler from reviewed markdown are allowed to run.
+
+package cli
+
+import (
+	"crypto/ed25519"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/github/gh-aw/pkg/console"
+	"github.com/github/gh-aw/pkg/logger"
+	"github.com/github/gh-aw/pkg/stringutil"
+	"github.com/github/gh-aw/pkg/workflow"
+	"github.com/spf13/cobra"
+)
+
+var provenanceLog = logger.New("cli:provenance_command")
+
+// LockProvenanceResult is the result of verifying the provenance of a lock file
+type LockProvenanceResult struct {
+	LockFile        string `json:"lock_file"`
+	Provenance      string `json:"provenance"`
+	Verified        bool   `json:"verified"`
+	KeyID           string `json:"key_id"`
+	Source          string `json:"source,omitempty"`
+	CompilerVersion string `json:"compiler_version,omitempty"`
+	Imports         int    `json:"imports"`
+	Actions         int    `json:"actions"`
+	Reason          string `json:"reason,omitempty"`
+}
+
+// NewProvenanceCommand creates the provenance command
+func NewProvenanceCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "provenance",
+		Short: "Verify signed provenance of compiled lock files",
+		Long: `Verify the signed provenance statements written by 'gh aw compile --provenance-key'.
+
+Each statement is an in-toto statement with a SLSA provenance predicate, signed with an
+Ed25519 key in a DSSE envelope. It records the digest of the lock file, the digests of the
+source markdown and all imports, the pinned actions and the gh-aw version.
+
+Available subcommands:
+  • verify - Verify lock files against their signed provenance
+
+Examples:
+  gh aw provenance verify .github/workflows/*.lock.yml --key provenance.pub`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cmd.Help()
+		},
+	}
+
+	cmd.AddCommand(newProvenanceVerifySubcommand())
+
+	return cmd
+}
+
+// newProvenanceVerifySubcommand creates the provenance verify subcommand
+func newProvenanceVerifySubcommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "verify <lock-file>...",
+		Short: "Verify lock files against their signed provenance",
+		Long: `Check that each lock file has a provenance statement signed by the given key, that the
+lock file has not been modified since it was signed, and that the source markdown and local
+imports in the repository match the digests recorded when it was compiled.
+
+Run this in CI before workflows are allowed to run. The command exits with a non-zero
+status when any lock file fails verification.
+
+Generate a signing key and its public key with:
+
+  openssl genpkey -algorithm ed25519 -out provenance.key
+  openssl pkey -in provenance.key -pubout -out provenance.pub
+
+Examples:
+  gh aw provenance verify .github/workflows/ci-doctor.lock.yml --key provenance.pub
+  gh aw provenance verify .github/workflows/*.lock.yml --key provenance.pub --json`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			keyPath, _ := cmd.Flags().GetString("key")
+			jsonOutput, _ := cmd.Flags().GetBool("json")
+			verbose, _ := cmd.Flags().GetBool("verbose")
+			return RunProvenanceVerify(args, keyPath, jsonOutput, verbose)
+		},
+	}
+
+	cmd.Flags().String("key", "", "Ed25519 public key (PEM) trusted to sign provenance")
+	_ = cmd.MarkFlagRequired("key")
+	addJSONFlag(cmd)
+
+	return cmd
+}
+
+// RunProvenanceVerify verifies the signed provenance of the given lock files and reports the results
+func RunProvenanceVerify(lockFiles []string, keyPath string, jsonOutput bool, verbose bool) error {
+	provenanceLog.Printf("Verifying provenance of %d lock files with key %s", len(lockFiles), keyPath)
+
+	publicKey, err := workflow.LoadProvenanceVerificationKey(keyPath)
+	if err != nil {
+		return err
+	}
+
+	var results []LockProvenanceResult
+	failed := 0
+	for _, lockFile := range lockFiles {
+		result := verifyLockProvenance(lockFile, publicKey, verbose)
+		if !result.Verified {
+			failed++
+		}
+		results = append(results, *result)
+	}
+
+	if jsonOutput {
+		data, err := json.MarshalIndent(results, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal provenance results: %w", err)
+		}
+		fmt.Println(string(data))
+	} else {
+		for _, result := range results {
+			if result.Verified {
+				fmt.Fprintln(os.Stderr, console.FormatSuccessMessage(fmt.Sprintf("%s was compiled from %s by gh-aw %s (%d imports, %d actions)",
+					result.LockFile, result.Source, result.CompilerVersion, result.Imports, result.Actions)))
+			} else {
+				fmt.Fprintln(os.Stderr, console.FormatErrorMessage(fmt.Sprintf("%s failed provenance verification: %s", result.LockFile, result.Reason)))
+			}
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d lock file(s) failed provenance verification", failed, len(results))
+	}
+	return nil
+}
+
+// verifyLockProvenance checks the signed provenance of a lock file against the lock file and its sources
+func verifyLockProvenance(lockFile string, publicKey ed25519.PublicKey, verbose bool) *LockProvenanceResult {
+	result := &LockProvenanceResult{
+		LockFile:   lockFile,
+		Provenance: workflow.ProvenanceFileForLockFile(lockFile),
+		KeyID:      workflow.ProvenanceKeyID(publicKey),
+	}
+
+	statement, err := readSignedProvenance(result.Provenance, publicKey)
+	if err != nil {
+		result.Reason = err.Error()
+		return result
+	}
+	result.CompilerVersion = statement.Predicate.RunDetails.Builder.Version["gh-aw"]
+
+	lockContent, err := os.ReadFile(lockFile)
+	if err != nil {
+		result.Reason = fmt.Sprintf("failed to read lock file: %v", err)
+		return result
+	}
+
+	gitRoot, err := findGitRootForPath(lockFile)
+	if err != nil {
+		result.Reason = err.Error()
+		return result
+	}
+	lockName, err := repositoryRelativeLockPath(gitRoot, lockFile)
+	if err != nil {
+		result.Reason = err.Error()
+		return result
+	}
+
+	// The statement must describe this lock file, so that a valid statement of another
+	// workflow cannot be copied next to it
+	if len(statement.Subject) == 0 || statement.Subject[0].Name != lockName {
+		result.Reason = fmt.Sprintf("the provenance was not signed for %s", lockName)
+		return result
+	}
+	lockDigest := workflow.SHA256Digest(lockContent)
+	if statement.Subject[0].Digest["sha256"] != lockDigest {
+		result.Reason = "the lock file was modified after its provenance was signed"
+		return result
+	}
+
+	sourceName := stringutil.LockFileToMarkdown(lockName)
+	for _, dependency := range statement.Predicate.BuildDefinition.ResolvedDependencies {
+		kind := dependency.Annotations["kind"]
+		switch kind {
+		case workflow.ProvenanceKindSource:
+			if dependency.Name != sourceName {
+				result.Reason = fmt.Sprintf("the provenance records %s as the source, not %s", dependency.Name, sourceName)
+				return result
+			}
+			result.Source = dependency.Name
+		case workflow.ProvenanceKindImport:
+			result.Imports++
+		case workflow.ProvenanceKindAction:
+			result.Actions++
+			continue
+		}
+
+		// Remote imports are pinned to a commit; local files must match the checkout
+		if _, remote := dependency.Digest["gitCommit"]; remote {
+			console.LogVerbose(verbose, fmt.Sprintf("Remote import %s pinned to %s", dependency.Name, dependency.Digest["gitCommit"]))
+			continue
+		}
+		if !filepath.IsLocal(filepath.FromSlash(dependency.Name)) {
+			result.Reason = fmt.Sprintf("%s %s recorded in the provenance is outside the repository", kind, dependency.Name)
+			return result
+		}
+		content, err := os.ReadFile(filepath.Join(gitRoot, filepath.FromSlash(dependency.Name)))
+		if err != nil {
+			result.Reason = fmt.Sprintf("%s %s recorded in the provenance was not found", kind, dependency.Name)
+			return result
+		}
+		if workflow.SHA256Digest(content) != dependency.Digest["sha256"] {
+			result.Reason = fmt.Sprintf("%s %s changed since the lock file was compiled. Recompile and sign it again", kind, dependency.Name)
+			return result
+		}
+	}
+
+	if result.Source == "" {
+		result.Reason = "the provenance does not record a source workflow"
+		return result
+	}
+
+	result.Verified = true
+	return result
+}
+
+// repositoryRelativeLockPath returns the path of a lock file relative to the repository root, in the
+// form recorded in provenance statements
+func repositoryRelativeLockPath(gitRoot, lockFile string) (string, error) {
+	absPath, err := filepath.Abs(lockFile)
+	if err != nil {
+		return "", fmt.Errorf("failed to get absolute path of %s: %w", lockFile, err)
+	}
+	// git reports the repository root with symlinks resolved
+	if resolved, err := filepath.EvalSymlinks(absPath); err == nil {
+		absPath = resolved
+	}
+	if resolved, err := filepath.EvalSymlinks(gitRoot); err == nil {
+		gitRoot = resolved
+	}
+	relPath, err := filepath.Rel(gitRoot, absPath)
+	if err != nil || !filepath.IsLocal(relPath) {
+		return "", fmt.Errorf("lock file %s is outside the repository", lockFile)
+	}
+	return filepath.ToSlash(relPath), nil
+}
+
+// readSignedProvenance reads a provenance file and returns its statement if it is signed by publicKey
+func readSignedProvenance(path string, publicKey ed25519.PublicKey) (*workflow.ProvenanceStatement, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("no provenance found at %s. Compile with 'gh aw compile --provenance-key <key>'", path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read provenance: %w", err)
+	}
+
+	var envelope workflow.ProvenanceEnvelope
+	if err := json.Unmarshal(data, &envelope); err != nil {
+		return nil, fmt.Errorf("failed to parse provenance %s: %w", path, err)
+	}
+	statement, err := workflow.VerifyProvenance(&envelope, publicKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid provenance signature: %w", err)
+	}
+	return statement, nil
+}
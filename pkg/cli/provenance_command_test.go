@@ -0,0 +1,156 @@
+//go:build !integration
+
+package cli
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/github/gh-aw/pkg/workflow"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// compileSignedTestWorkflow compiles a workflow with a local import in a new git repository
+// and signs its provenance, returning the workflows directory and the signing key
+func compileSignedTestWorkflow(t *testing.T) (string, ed25519.PrivateKey) {
+	t.Helper()
+	_, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	repoDir := t.TempDir()
+	require.NoError(t, exec.Command("git", "init", "-q", repoDir).Run())
+	workflowsDir := filepath.Join(repoDir, ".github", "workflows")
+	require.NoError(t, os.MkdirAll(filepath.Join(workflowsDir, "shared"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(workflowsDir, "shared", "tools.md"), []byte("---\ntools:\n  bash: [\"ls\"]\n---\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(workflowsDir, "triage.md"), []byte(`---
+on:
+  issues:
+    types: [opened]
+permissions:
+  contents: read
+  issues: read
+engine: copilot
+imports:
+  - shared/tools.md
+---
+Triage the issue.
+`), 0644))
+
+	compiler := workflow.NewCompiler(workflow.WithGitRoot(repoDir))
+	compiler.SetQuiet(true)
+	compiler.SetProvenanceKey(privateKey)
+	require.NoError(t, compiler.CompileWorkflow(filepath.Join(workflowsDir, "triage.md")))
+	return workflowsDir, privateKey
+}
+
+func TestVerifyLockProvenance(t *testing.T) {
+	workflowsDir, privateKey := compileSignedTestWorkflow(t)
+	lockFile := filepath.Join(workflowsDir, "triage.lock.yml")
+	publicKey := privateKey.Public().(ed25519.PublicKey)
+
+	result := verifyLockProvenance(lockFile, publicKey, false)
+	assert.True(t, result.Verified, "provenance should verify: %s", result.Reason)
+	assert.Equal(t, ".github/workflows/triage.md", result.Source)
+	assert.Equal(t, 1, result.Imports)
+	assert.Positive(t, result.Actions)
+	assert.Equal(t, workflow.ProvenanceKeyID(publicKey), result.KeyID)
+
+	t.Run("untrusted key", func(t *testing.T) {
+		otherKey, _, err := ed25519.GenerateKey(rand.Reader)
+		require.NoError(t, err)
+		result := verifyLockProvenance(lockFile, otherKey, false)
+		assert.False(t, result.Verified)
+		assert.Contains(t, result.Reason, "invalid provenance signature")
+	})
+
+	t.Run("modified import", func(t *testing.T) {
+		importPath := filepath.Join(workflowsDir, "shared", "tools.md")
+		original, err := os.ReadFile(importPath)
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(importPath, append(original, "# edited\n"...), 0644))
+		defer os.WriteFile(importPath, original, 0644)
+
+		result := verifyLockProvenance(lockFile, publicKey, false)
+		assert.False(t, result.Verified)
+		assert.Equal(t, "import .github/workflows/shared/tools.md changed since the lock file was compiled. Recompile and sign it again", result.Reason)
+	})
+
+	t.Run("modified lock file", func(t *testing.T) {
+		original, err := os.ReadFile(lockFile)
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(lockFile, append(original, "# edited\n"...), 0644))
+		defer os.WriteFile(lockFile, original, 0644)
+
+		result := verifyLockProvenance(lockFile, publicKey, false)
+		assert.False(t, result.Verified)
+		assert.Equal(t, "the lock file was modified after its provenance was signed", result.Reason)
+	})
+
+	t.Run("provenance of another lock file", func(t *testing.T) {
+		// A valid lock file and statement of one workflow copied over another workflow's
+		otherLock := filepath.Join(workflowsDir, "deploy.lock.yml")
+		for from, to := range map[string]string{
+			lockFile: otherLock,
+			workflow.ProvenanceFileForLockFile(lockFile): workflow.ProvenanceFileForLockFile(otherLock),
+		} {
+			content, err := os.ReadFile(from)
+			require.NoError(t, err)
+			require.NoError(t, os.WriteFile(to, content, 0644))
+			defer os.Remove(to)
+		}
+
+		result := verifyLockProvenance(otherLock, publicKey, false)
+		assert.False(t, result.Verified)
+		assert.Equal(t, "the provenance was not signed for .github/workflows/deploy.lock.yml", result.Reason)
+	})
+
+	t.Run("source of another workflow", func(t *testing.T) {
+		provenancePath := workflow.ProvenanceFileForLockFile(lockFile)
+		original, err := os.ReadFile(provenancePath)
+		require.NoError(t, err)
+		defer os.WriteFile(provenancePath, original, 0644)
+
+		// Sign a statement for this lock file that records another markdown file as its source
+		statement, err := readSignedProvenance(provenancePath, publicKey)
+		require.NoError(t, err)
+		source, err := os.ReadFile(filepath.Join(workflowsDir, "triage.md"))
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(filepath.Join(workflowsDir, "deploy.md"), source, 0644))
+		defer os.Remove(filepath.Join(workflowsDir, "deploy.md"))
+		for i, dependency := range statement.Predicate.BuildDefinition.ResolvedDependencies {
+			if dependency.Annotations["kind"] == workflow.ProvenanceKindSource {
+				statement.Predicate.BuildDefinition.ResolvedDependencies[i].Name = ".github/workflows/deploy.md"
+			}
+		}
+		envelope, err := workflow.SignProvenance(statement, privateKey)
+		require.NoError(t, err)
+		data, err := json.Marshal(envelope)
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(provenancePath, data, 0644))
+
+		result := verifyLockProvenance(lockFile, publicKey, false)
+		assert.False(t, result.Verified)
+		assert.Equal(t, "the provenance records .github/workflows/deploy.md as the source, not .github/workflows/triage.md", result.Reason)
+	})
+
+	t.Run("missing provenance", func(t *testing.T) {
+		require.NoError(t, os.Remove(workflow.ProvenanceFileForLockFile(lockFile)))
+		result := verifyLockProvenance(lockFile, publicKey, false)
+		assert.False(t, result.Verified)
+		assert.Contains(t, result.Reason, "no provenance found")
+	})
+}
+
+func TestValidateCompileConfig_ProvenanceKey(t *testing.T) {
+	require.NoError(t, validateCompileConfig(CompileConfig{ProvenanceKey: "provenance.key"}))
+
+	err := validateCompileConfig(CompileConfig{ProvenanceKey: "provenance.key", NoEmit: true})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "--provenance-key cannot be used with --no-emit")
+}
@@ -0,0 +1,484 @@
+package cli
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/github/gh-aw/pkg/console"
+	"github.com/github/gh-aw/pkg/logger"
+	"github.com/spf13/cobra"
+)
+
+var reportHTMLLog = logger.New("cli:report_html")
+
+// Report formats accepted by the --format flag
+const (
+	reportFormatConsole = "console"
+	reportFormatJSON    = "json"
+	reportFormatHTML    = "html"
+)
+
+// htmlReportFileName is the name of the HTML report written to the output directory
+const htmlReportFileName = "report.html"
+
+// maxHTMLTimelineCalls limits the number of MCP tool calls listed in the timeline
+const maxHTMLTimelineCalls = 500
+
+// getReportFormat returns the report format selected with --format, treating --json as
+// shorthand for --format json
+func getReportFormat(cmd *cobra.Command) (string, error) {
+	format, _ := cmd.Flags().GetString("format")
+	jsonOutput, _ := cmd.Flags().GetBool("json")
+	format = strings.ToLower(strings.TrimSpace(format))
+
+	switch format {
+	case reportFormatConsole, reportFormatJSON, reportFormatHTML:
+	default:
+		return "", fmt.Errorf("invalid format '%s'. Must be one of: console, json, html", format)
+	}
+	if jsonOutput {
+		if format == reportFormatHTML {
+			return "", fmt.Errorf("--json cannot be combined with --format %s", format)
+		}
+		return reportFormatJSON, nil
+	}
+	return format, nil
+}
+
+// htmlReport is the data rendered by the HTML report template
+type htmlReport struct {
+	Title       string
+	Subtitle    string
+	URL         string
+	GeneratedAt string
+	Version     string
+
+	Overview        []htmlField
+	Metrics         []htmlField
+	Findings        []Finding
+	Recommendations []Recommendation
+	Jobs            []JobData
+	Runs            []RunData
+	ToolUsage       []htmlToolUsage
+	Timeline        []MCPToolCall
+	TimelineTotal   int
+	Firewall        *htmlFirewall
+	ToolGraph       *htmlToolGraph
+	CreatedItems    []CreatedItemReport
+	MissingTools    []htmlMissingTool
+	Errors          []htmlMessage
+	Warnings        []htmlMessage
+}
+
+type htmlField struct {
+	Label string
+	Value string
+}
+
+type htmlToolUsage struct {
+	Name        string
+	Calls       int
+	Runs        int
+	MaxOutput   string
+	MaxDuration string
+}
+
+type htmlFirewall struct {
+	TotalRequests   int
+	AllowedRequests int
+	BlockedRequests int
+	AllowedDomains  []string
+	BlockedDomains  []string
+}
+
+type htmlToolGraph struct {
+	Mermaid     string
+	Transitions []ToolTransition
+}
+
+type htmlMissingTool struct {
+	Tool   string
+	Reason string
+	Count  int
+}
+
+type htmlMessage struct {
+	Type    string
+	Message string
+	Count   int
+	Link    string
+}
+
+// buildAuditHTMLReport converts audit data into the HTML report data
+func buildAuditHTMLReport(data AuditData, graph *ToolGraph, now time.Time) htmlReport {
+	overview := data.Overview
+	outcome := overview.Status
+	if overview.Conclusion != "" {
+		outcome = overview.Conclusion
+	}
+
+	report := htmlReport{
+		Title:           fmt.Sprintf("Audit of %s run %d", overview.WorkflowName, overview.RunID),
+		Subtitle:        fmt.Sprintf("%s · %s on %s", outcome, overview.Event, overview.Branch),
+		URL:             overview.URL,
+		GeneratedAt:     now.UTC().Format(time.RFC3339),
+		Version:         GetVersion(),
+		Findings:        data.KeyFindings,
+		Recommendations: data.Recommendations,
+		Jobs:            data.Jobs,
+		CreatedItems:    data.CreatedItems,
+		ToolGraph:       newHTMLToolGraph(graph),
+	}
+
+	report.Overview = nonEmptyFields(
+		htmlField{"Run ID", fmt.Sprintf("%d", overview.RunID)},
+		htmlField{"Workflow", overview.WorkflowName},
+		htmlField{"Status", outcome},
+		htmlField{"Event", overview.Event},
+		htmlField{"Branch", overview.Branch},
+		htmlField{"Created", formatHTMLTime(overview.CreatedAt)},
+		htmlField{"Duration", overview.Duration},
+	)
+	report.Metrics = nonEmptyFields(
+		htmlField{"Tokens", formatTokens(data.Metrics.TokenUsage)},
+		htmlField{"Estimated cost", formatCost(data.Metrics.EstimatedCost)},
+		htmlField{"Turns", fmt.Sprintf("%d", data.Metrics.Turns)},
+		htmlField{"Errors", fmt.Sprintf("%d", data.Metrics.ErrorCount)},
+		htmlField{"Warnings", fmt.Sprintf("%d", data.Metrics.WarningCount)},
+	)
+
+	for _, tool := range data.ToolUsage {
+		report.ToolUsage = append(report.ToolUsage, htmlToolUsage{
+			Name:        tool.Name,
+			Calls:       tool.CallCount,
+			MaxOutput:   console.FormatFileSize(int64(tool.MaxOutputSize)),
+			MaxDuration: tool.MaxDuration,
+		})
+	}
+	if data.MCPToolUsage != nil {
+		report.Timeline, report.TimelineTotal = buildHTMLTimeline(data.MCPToolUsage.ToolCalls)
+	}
+	if fw := data.FirewallAnalysis; fw != nil {
+		report.Firewall = &htmlFirewall{
+			TotalRequests:   fw.TotalRequests,
+			AllowedRequests: fw.AllowedRequests,
+			BlockedRequests: fw.BlockedRequests,
+			AllowedDomains:  fw.AllowedDomains,
+			BlockedDomains:  fw.BlockedDomains,
+		}
+	}
+	for _, missing := range data.MissingTools {
+		report.MissingTools = append(report.MissingTools, htmlMissingTool{Tool: missing.Tool, Reason: missing.Reason, Count: 1})
+	}
+	for _, errInfo := range data.Errors {
+		report.Errors = append(report.Errors, htmlMessage{Type: errInfo.Type, Message: errInfo.Message, Count: 1})
+	}
+	for _, warning := range data.Warnings {
+		report.Warnings = append(report.Warnings, htmlMessage{Type: warning.Type, Message: warning.Message, Count: 1})
+	}
+	return report
+}
+
+// buildLogsHTMLReport converts logs data into the HTML report data
+func buildLogsHTMLReport(data LogsData, graph *ToolGraph, now time.Time) htmlReport {
+	summary := data.Summary
+	report := htmlReport{
+		Title:       "Workflow logs report",
+		Subtitle:    fmt.Sprintf("%d runs", summary.TotalRuns),
+		GeneratedAt: now.UTC().Format(time.RFC3339),
+		Version:     GetVersion(),
+		Runs:        data.Runs,
+		ToolGraph:   newHTMLToolGraph(graph),
+	}
+
+	report.Metrics = nonEmptyFields(
+		htmlField{"Runs", fmt.Sprintf("%d", summary.TotalRuns)},
+		htmlField{"Total duration", summary.TotalDuration},
+		htmlField{"Tokens", formatTokens(summary.TotalTokens)},
+		htmlField{"Estimated cost", formatCost(summary.TotalCost)},
+		htmlField{"Turns", fmt.Sprintf("%d", summary.TotalTurns)},
+		htmlField{"Errors", fmt.Sprintf("%d", summary.TotalErrors)},
+		htmlField{"Warnings", fmt.Sprintf("%d", summary.TotalWarnings)},
+		htmlField{"Missing tools", fmt.Sprintf("%d", summary.TotalMissingTools)},
+		htmlField{"Safe output items", fmt.Sprintf("%d", summary.TotalSafeItems)},
+	)
+
+	for _, tool := range data.ToolUsage {
+		report.ToolUsage = append(report.ToolUsage, htmlToolUsage{
+			Name:        tool.Name,
+			Calls:       tool.TotalCalls,
+			Runs:        tool.Runs,
+			MaxOutput:   console.FormatFileSize(int64(tool.MaxOutputSize)),
+			MaxDuration: tool.MaxDuration,
+		})
+	}
+	if data.MCPToolUsage != nil {
+		report.Timeline, report.TimelineTotal = buildHTMLTimeline(data.MCPToolUsage.ToolCalls)
+	}
+	if fw := data.FirewallLog; fw != nil {
+		report.Firewall = &htmlFirewall{
+			TotalRequests:   fw.TotalRequests,
+			AllowedRequests: fw.AllowedRequests,
+			BlockedRequests: fw.BlockedRequests,
+			AllowedDomains:  fw.AllowedDomains,
+			BlockedDomains:  fw.BlockedDomains,
+		}
+	}
+	for _, missing := range data.MissingTools {
+		report.MissingTools = append(report.MissingTools, htmlMissingTool{Tool: missing.Tool, Reason: missing.FirstReason, Count: missing.Count})
+	}
+	for _, entry := range data.ErrorsAndWarnings {
+		message := htmlMessage{Type: entry.Type, Message: entry.Message, Count: entry.Count, Link: entry.RunURL}
+		if strings.EqualFold(entry.Type, "error") {
+			report.Errors = append(report.Errors, message)
+		} else {
+			report.Warnings = append(report.Warnings, message)
+		}
+	}
+	return report
+}
+
+// buildHTMLTimeline returns the MCP tool calls in chronological order, truncated to
+// maxHTMLTimelineCalls, together with the total number of calls
+func buildHTMLTimeline(calls []MCPToolCall) ([]MCPToolCall, int) {
+	timeline := append([]MCPToolCall{}, calls...)
+	sort.SliceStable(timeline, func(i, j int) bool { return timeline[i].Timestamp < timeline[j].Timestamp })
+	if len(timeline) > maxHTMLTimelineCalls {
+		timeline = timeline[:maxHTMLTimelineCalls]
+	}
+	return timeline, len(calls)
+}
+
+// newHTMLToolGraph returns the transitions of a tool graph and its Mermaid definition as source
+// text (the report loads no scripts, so it is not rendered), or nil if the graph has no tools
+func newHTMLToolGraph(graph *ToolGraph) *htmlToolGraph {
+	if graph == nil || len(graph.Tools) == 0 {
+		return nil
+	}
+	mermaid := strings.TrimPrefix(graph.GenerateMermaidGraph(), "```mermaid\n")
+	mermaid = strings.TrimSuffix(mermaid, "```\n")
+	return &htmlToolGraph{Mermaid: mermaid, Transitions: graph.SortedTransitions()}
+}
+
+func nonEmptyFields(fields ...htmlField) []htmlField {
+	var result []htmlField
+	for _, field := range fields {
+		if field.Value != "" && field.Value != "0" && field.Value != "-" {
+			result = append(result, field)
+		}
+	}
+	return result
+}
+
+func formatHTMLTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format("2006-01-02 15:04:05 UTC")
+}
+
+// renderHTMLReport renders a report as a single self-contained HTML document
+func renderHTMLReport(report htmlReport) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := htmlReportTemplate.Execute(&buf, report); err != nil {
+		return nil, fmt.Errorf("failed to render HTML report: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// writeHTMLReport renders a report and writes it to path
+func writeHTMLReport(path string, report htmlReport) error {
+	content, err := renderHTMLReport(report)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create report directory: %w", err)
+	}
+	if err := os.WriteFile(path, content, 0644); err != nil {
+		return fmt.Errorf("failed to write HTML report: %w", err)
+	}
+	reportHTMLLog.Printf("Wrote HTML report: path=%s, size=%d", path, len(content))
+
+	absPath, _ := filepath.Abs(path)
+	fmt.Fprintln(os.Stderr, console.FormatSuccessMessage("HTML report written to "+absPath))
+	return nil
+}
+
+var htmlReportTemplate = template.Must(template.New("report").Funcs(template.FuncMap{
+	"severityClass": func(severity string) string {
+		switch severity {
+		case "critical", "high":
+			return "bad"
+		case "medium":
+			return "warn"
+		default:
+			return "info"
+		}
+	},
+	"outcomeClass": func(outcome string) string {
+		switch {
+		case outcome == "success":
+			return "good"
+		case isFailureConclusion(outcome):
+			return "bad"
+		default:
+			return "info"
+		}
+	},
+	"outcome": func(run RunData) string {
+		if run.Conclusion != "" {
+			return run.Conclusion
+		}
+		return run.Status
+	},
+	"cost":   formatCost,
+	"tokens": formatTokens,
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{.Title}}</title>
+<style>
+:root { --fg: #1f2328; --muted: #59636e; --bg: #ffffff; --panel: #f6f8fa; --border: #d1d9e0; --good: #1a7f37; --bad: #d1242f; --warn: #9a6700; --info: #0969da; }
+@media (prefers-color-scheme: dark) { :root { --fg: #f0f6fc; --muted: #9198a1; --bg: #0d1117; --panel: #151b23; --border: #3d444d; --good: #3fb950; --bad: #f85149; --warn: #d29922; --info: #4493f8; } }
+body { margin: 0 auto; max-width: 1200px; padding: 24px; font: 14px/1.5 -apple-system, BlinkMacSystemFont, "Segoe UI", Helvetica, Arial, sans-serif; color: var(--fg); background: var(--bg); }
+h1 { margin: 0; font-size: 24px; }
+h2 { margin-top: 32px; padding-bottom: 4px; border-bottom: 1px solid var(--border); font-size: 18px; }
+a { color: var(--info); }
+.muted { color: var(--muted); }
+.cards { display: flex; flex-wrap: wrap; gap: 12px; margin-top: 16px; }
+.card { min-width: 120px; padding: 8px 12px; border: 1px solid var(--border); border-radius: 6px; background: var(--panel); }
+.card .label { color: var(--muted); font-size: 12px; }
+.card .value { font-size: 16px; font-weight: 600; }
+table { width: 100%; border-collapse: collapse; margin-top: 8px; }
+th, td { padding: 4px 8px; border-bottom: 1px solid var(--border); text-align: left; vertical-align: top; }
+th { background: var(--panel); }
+td.num { text-align: right; font-variant-numeric: tabular-nums; }
+code, pre { font-family: ui-monospace, SFMono-Regular, Menlo, Consolas, monospace; font-size: 12px; }
+pre { overflow-x: auto; padding: 12px; border-radius: 6px; background: var(--panel); }
+.badge { display: inline-block; padding: 0 6px; border-radius: 10px; font-size: 12px; font-weight: 600; color: var(--bg); }
+.badge.good { background: var(--good); } .badge.bad { background: var(--bad); } .badge.warn { background: var(--warn); } .badge.info { background: var(--info); }
+.domains { columns: 2; padding-left: 20px; }
+footer { margin-top: 40px; color: var(--muted); font-size: 12px; }
+</style>
+</head>
+<body>
+<header>
+<h1>{{.Title}}</h1>
+<div class="muted">{{.Subtitle}}{{if .URL}} · <a href="{{.URL}}">{{.URL}}</a>{{end}}</div>
+</header>
+{{if .Overview}}
+<h2>Overview</h2>
+<div class="cards">{{range .Overview}}<div class="card"><div class="label">{{.Label}}</div><div class="value">{{.Value}}</div></div>{{end}}</div>
+{{end}}
+{{if .Metrics}}
+<h2>Metrics</h2>
+<div class="cards">{{range .Metrics}}<div class="card"><div class="label">{{.Label}}</div><div class="value">{{.Value}}</div></div>{{end}}</div>
+{{end}}
+{{if .Findings}}
+<h2>Key Findings</h2>
+<table>
+<tr><th>Severity</th><th>Category</th><th>Finding</th></tr>
+{{range .Findings}}<tr><td><span class="badge {{severityClass .Severity}}">{{.Severity}}</span></td><td>{{.Category}}</td><td><strong>{{.Title}}</strong><br>{{.Description}}{{if .Impact}}<br><span class="muted">Impact: {{.Impact}}</span>{{end}}</td></tr>
+{{end}}</table>
+{{end}}
+{{if .Recommendations}}
+<h2>Recommendations</h2>
+<table>
+<tr><th>Priority</th><th>Action</th></tr>
+{{range .Recommendations}}<tr><td><span class="badge {{severityClass .Priority}}">{{.Priority}}</span></td><td><strong>{{.Action}}</strong><br>{{.Reason}}{{if .Example}}<pre>{{.Example}}</pre>{{end}}</td></tr>
+{{end}}</table>
+{{end}}
+{{if .Jobs}}
+<h2>Jobs</h2>
+<table>
+<tr><th>Name</th><th>Status</th><th>Duration</th></tr>
+{{range .Jobs}}<tr><td>{{.Name}}</td><td><span class="badge {{outcomeClass (or .Conclusion .Status)}}">{{or .Conclusion .Status}}</span></td><td>{{.Duration}}</td></tr>
+{{end}}</table>
+{{end}}
+{{if .Runs}}
+<h2>Runs</h2>
+<table>
+<tr><th>Run</th><th>Workflow</th><th>Status</th><th>Duration</th><th>Tokens</th><th>Cost</th><th>Errors</th><th>Warnings</th><th>Created</th></tr>
+{{range .Runs}}<tr><td><a href="{{.URL}}">{{.DatabaseID}}</a></td><td>{{.WorkflowName}}</td><td><span class="badge {{outcomeClass (outcome .)}}">{{outcome .}}</span></td><td>{{.Duration}}</td><td class="num">{{tokens .TokenUsage}}</td><td class="num">{{cost .EstimatedCost}}</td><td class="num">{{.ErrorCount}}</td><td class="num">{{.WarningCount}}</td><td>{{.CreatedAt.UTC.Format "2006-01-02 15:04"}}</td></tr>
+{{end}}</table>
+{{end}}
+{{if .ToolUsage}}
+<h2>Tool Usage</h2>
+<table>
+<tr><th>Tool</th><th>Calls</th>{{if .Runs}}<th>Runs</th>{{end}}<th>Max output</th><th>Max duration</th></tr>
+{{$logs := .Runs}}{{range .ToolUsage}}<tr><td><code>{{.Name}}</code></td><td class="num">{{.Calls}}</td>{{if $logs}}<td class="num">{{.Runs}}</td>{{end}}<td class="num">{{.MaxOutput}}</td><td>{{.MaxDuration}}</td></tr>
+{{end}}</table>
+{{end}}
+{{if .Timeline}}
+<h2>MCP Tool Call Timeline</h2>
+{{if gt .TimelineTotal (len .Timeline)}}<p class="muted">Showing the first {{len .Timeline}} of {{.TimelineTotal}} calls.</p>{{end}}
+<table>
+<tr><th>Time</th><th>Server</th><th>Tool</th><th>Status</th><th>Duration</th><th>Input</th><th>Output</th></tr>
+{{range .Timeline}}<tr><td><code>{{.Timestamp}}</code></td><td>{{.ServerName}}</td><td><code>{{.ToolName}}</code></td><td><span class="badge {{if eq .Status "error"}}bad{{else}}good{{end}}">{{.Status}}</span>{{if .Error}}<br><span class="muted">{{.Error}}</span>{{end}}</td><td>{{.Duration}}</td><td class="num">{{.InputSize}}</td><td class="num">{{.OutputSize}}</td></tr>
+{{end}}</table>
+{{end}}
+{{with .ToolGraph}}
+<h2>Tool Transitions</h2>
+<table>
+<tr><th>From</th><th>To</th><th>Count</th></tr>
+{{range .Transitions}}<tr><td><code>{{.From}}</code></td><td><code>{{.To}}</code></td><td class="num">{{.Count}}</td></tr>
+{{end}}</table>
+<details>
+<summary>Mermaid source</summary>
+<p class="muted">The transitions above as Mermaid text, to paste into a Mermaid editor or a Markdown file.</p>
+<pre>{{.Mermaid}}</pre>
+</details>
+{{end}}
+{{with .Firewall}}
+<h2>Firewall</h2>
+<div class="cards">
+<div class="card"><div class="label">Requests</div><div class="value">{{.TotalRequests}}</div></div>
+<div class="card"><div class="label">Allowed</div><div class="value">{{.AllowedRequests}}</div></div>
+<div class="card"><div class="label">Blocked</div><div class="value">{{.BlockedRequests}}</div></div>
+</div>
+{{if .BlockedDomains}}<h3>Blocked domains</h3>
+<ul class="domains">{{range .BlockedDomains}}<li><code>{{.}}</code></li>{{end}}</ul>{{end}}
+{{if .AllowedDomains}}<h3>Allowed domains</h3>
+<ul class="domains">{{range .AllowedDomains}}<li><code>{{.}}</code></li>{{end}}</ul>{{end}}
+{{end}}
+{{if .CreatedItems}}
+<h2>Created Items</h2>
+<table>
+<tr><th>Type</th><th>Item</th><th>Repository</th></tr>
+{{range .CreatedItems}}<tr><td>{{.Type}}</td><td>{{if .URL}}<a href="{{.URL}}">{{if .Number}}#{{.Number}}{{else}}{{.URL}}{{end}}</a>{{end}}</td><td>{{.Repo}}</td></tr>
+{{end}}</table>
+{{end}}
+{{if .MissingTools}}
+<h2>Missing Tools</h2>
+<table>
+<tr><th>Tool</th><th>Reason</th><th>Occurrences</th></tr>
+{{range .MissingTools}}<tr><td><code>{{.Tool}}</code></td><td>{{.Reason}}</td><td class="num">{{.Count}}</td></tr>
+{{end}}</table>
+{{end}}
+{{if .Errors}}
+<h2>Errors</h2>
+<table>
+<tr><th>Message</th><th>Occurrences</th></tr>
+{{range .Errors}}<tr><td>{{if .Link}}<a href="{{.Link}}">{{.Message}}</a>{{else}}{{.Message}}{{end}}</td><td class="num">{{.Count}}</td></tr>
+{{end}}</table>
+{{end}}
+{{if .Warnings}}
+<h2>Warnings</h2>
+<table>
+<tr><th>Message</th><th>Occurrences</th></tr>
+{{range .Warnings}}<tr><td>{{if .Link}}<a href="{{.Link}}">{{.Message}}</a>{{else}}{{.Message}}{{end}}</td><td class="num">{{.Count}}</td></tr>
+{{end}}</table>
+{{end}}
+<footer>Generated by gh-aw {{.Version}} at {{.GeneratedAt}}</footer>
+</body>
+</html>
+`))
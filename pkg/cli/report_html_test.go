@@ -0,0 +1,155 @@
+//go:build !integration
+
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetReportFormat(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		want    string
+		wantErr string
+	}{
+		{name: "default", want: reportFormatConsole},
+		{name: "html", args: []string{"--format", "html"}, want: reportFormatHTML},
+		{name: "case insensitive", args: []string{"--format", "JSON"}, want: reportFormatJSON},
+		{name: "json flag", args: []string{"--json"}, want: reportFormatJSON},
+		{name: "json flag with json format", args: []string{"--json", "--format", "json"}, want: reportFormatJSON},
+		{name: "json flag with html format", args: []string{"--json", "--format", "html"}, wantErr: "--json cannot be combined with --format html"},
+		{name: "unknown format", args: []string{"--format", "pdf"}, wantErr: "invalid format 'pdf'"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := &cobra.Command{Use: "test"}
+			addJSONFlag(cmd)
+			addFormatFlag(cmd)
+			require.NoError(t, cmd.ParseFlags(tt.args))
+
+			format, err := getReportFormat(cmd)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, format)
+		})
+	}
+}
+
+func TestRenderAuditHTMLReport(t *testing.T) {
+	data := AuditData{
+		Overview: OverviewData{RunID: 42, WorkflowName: "triage", Status: "completed", Conclusion: "failure", Event: "issues", Branch: "main", URL: "https://github.com/octo/repo/actions/runs/42"},
+		Metrics:  MetricsData{TokenUsage: 12000, ErrorCount: 2},
+		KeyFindings: []Finding{
+			{Category: "error", Severity: "high", Title: "Multiple errors", Description: "<script>alert(1)</script>"},
+		},
+		Recommendations: []Recommendation{{Priority: "high", Action: "Review error logs", Reason: "Errors were logged"}},
+		Jobs:            []JobData{{Name: "agent", Status: "completed", Conclusion: "failure", Duration: "2m"}},
+		ToolUsage:       []ToolUsageInfo{{Name: "github.list_issues", CallCount: 3, MaxOutputSize: 2048}},
+		MCPToolUsage: &MCPToolUsageData{ToolCalls: []MCPToolCall{
+			{Timestamp: "2026-01-01T10:00:05Z", ServerName: "github", ToolName: "get_issue", Status: "success"},
+			{Timestamp: "2026-01-01T10:00:01Z", ServerName: "github", ToolName: "list_issues", Status: "error", Error: "rate limited"},
+		}},
+		FirewallAnalysis: &FirewallAnalysis{
+			TotalRequests: 5, AllowedRequests: 4, BlockedRequests: 1,
+			DomainBuckets: DomainBuckets{AllowedDomains: []string{"api.github.com:443"}, BlockedDomains: []string{"evil.example.com:443"}},
+		},
+		CreatedItems: []CreatedItemReport{{Type: "add_comment", URL: "https://github.com/octo/repo/issues/7#issuecomment-1", Number: 7, Repo: "octo/repo"}},
+	}
+	graph := NewToolGraph()
+	graph.AddSequence([]string{"list_issues", "get_issue", "add_comment"})
+
+	report := buildAuditHTMLReport(data, graph, time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC))
+	require.Len(t, report.Timeline, 2)
+	assert.Equal(t, "list_issues", report.Timeline[0].ToolName, "timeline should be chronological")
+
+	content, err := renderHTMLReport(report)
+	require.NoError(t, err)
+	output := string(content)
+
+	assert.True(t, strings.HasPrefix(output, "<!DOCTYPE html>"))
+	assert.NotContains(t, output, "<script", "report should not load or embed scripts")
+	assert.NotContains(t, output, `src="http`, "report should not reference external resources")
+	assert.NotContains(t, output, `class="mermaid"`, "Mermaid source should not rely on a Mermaid runtime")
+	assert.Contains(t, output, "&lt;script&gt;alert(1)&lt;/script&gt;", "content should be escaped")
+	for _, expected := range []string{
+		"Audit of triage run 42",
+		`<a href="https://github.com/octo/repo/actions/runs/42">`,
+		"Multiple errors",
+		"Review error logs",
+		"<td>agent</td>",
+		"github.list_issues",
+		"2.0 KB",
+		"rate limited",
+		"evil.example.com:443",
+		"api.github.com:443",
+		"<td><code>list_issues</code></td><td><code>get_issue</code></td>",
+		"stateDiagram-v2",
+		"#7</a>",
+		"2026-01-02T03:04:05Z",
+	} {
+		assert.Contains(t, output, expected)
+	}
+}
+
+func TestRenderLogsHTMLReport(t *testing.T) {
+	data := LogsData{
+		Summary: LogsSummary{TotalRuns: 2, TotalTokens: 5000, TotalErrors: 1},
+		Runs: []RunData{
+			{DatabaseID: 1, WorkflowName: "triage", Status: "completed", Conclusion: "success", URL: "https://github.com/octo/repo/actions/runs/1"},
+			{DatabaseID: 2, WorkflowName: "triage", Status: "in_progress"},
+		},
+		ToolUsage:    []ToolUsageSummary{{Name: "bash", TotalCalls: 10, Runs: 2}},
+		MissingTools: []MissingToolSummary{{Tool: "terraform", Count: 3, FirstReason: "Needed to plan changes"}},
+		FirewallLog:  &FirewallLogSummary{TotalRequests: 3, BlockedRequests: 3, BlockedDomains: []string{"pypi.org:443"}},
+	}
+
+	content, err := renderHTMLReport(buildLogsHTMLReport(data, nil, time.Now()))
+	require.NoError(t, err)
+	output := string(content)
+
+	assert.Contains(t, output, "Workflow logs report")
+	assert.Contains(t, output, `<a href="https://github.com/octo/repo/actions/runs/1">1</a>`)
+	assert.Contains(t, output, `<span class="badge good">success</span>`)
+	assert.Contains(t, output, `<span class="badge info">in_progress</span>`)
+	assert.Contains(t, output, "<th>Runs</th>")
+	assert.Contains(t, output, "Needed to plan changes")
+	assert.Contains(t, output, "pypi.org:443")
+	assert.NotContains(t, output, "Tool Transitions", "graph section should be omitted without tool calls")
+	assert.NotContains(t, output, "Key Findings")
+}
+
+func TestBuildHTMLTimelineTruncates(t *testing.T) {
+	calls := make([]MCPToolCall, maxHTMLTimelineCalls+10)
+	for i := range calls {
+		calls[i] = MCPToolCall{Timestamp: fmt.Sprintf("2026-01-01T10:%02d:%02dZ", (len(calls)-i)/60, (len(calls)-i)%60)}
+	}
+
+	timeline, total := buildHTMLTimeline(calls)
+	assert.Len(t, timeline, maxHTMLTimelineCalls)
+	assert.Equal(t, len(calls), total)
+	assert.Equal(t, calls[len(calls)-1].Timestamp, timeline[0].Timestamp)
+}
+
+func TestWriteHTMLReport(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "run-1", htmlReportFileName)
+	require.NoError(t, writeHTMLReport(path, htmlReport{Title: "Report"}))
+
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "<title>Report</title>")
+}
@@ -0,0 +1,596 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/github/gh-aw/pkg/console"
+	"github.com/github/gh-aw/pkg/logger"
+	"github.com/github/gh-aw/pkg/workflow"
+	"github.com/goccy/go-yaml"
+	"github.com/spf13/cobra"
+)
+
+var secretsAuditLog = logger.New("cli:secrets_audit_command")
+
+var (
+	// auditExpressionPattern matches a single ${{ ... }} expression
+	auditExpressionPattern = regexp.MustCompile(`\$\{\{[^}]+\}\}`)
+	// auditSecretNamePattern matches a secrets.NAME reference inside an expression
+	auditSecretNamePattern = regexp.MustCompile(`secrets\.([A-Za-z_][A-Za-z0-9_]*)`)
+	// auditLiteralFallbackPattern matches a `|| 'literal'` fallback that makes the secrets in an expression optional
+	auditLiteralFallbackPattern = regexp.MustCompile(`\|\|\s*'`)
+)
+
+// fetchSecretInventoryFunc allows overriding GitHub access in tests
+var fetchSecretInventoryFunc = fetchSecretInventory
+
+// maxUsageWorkflowsShown limits the workflows listed per secret in console tables
+const maxUsageWorkflowsShown = 3
+
+// workflowLevelJob is the job name used for references outside of any job
+const workflowLevelJob = "(workflow)"
+
+// secretInventory lists the secret names configured at each scope visible to a repository
+type secretInventory struct {
+	Repository   []string
+	Organization []string
+	Environments map[string][]string
+}
+
+// lockSecretReference is one expression in a lock file that references one or more secrets
+type lockSecretReference struct {
+	Workflow    string
+	Job         string
+	Environment string
+	// Names lists the secrets in the expression; any of them satisfies the reference
+	Names []string
+	// Optional is true when the expression has a literal fallback value
+	Optional bool
+}
+
+// SecretUsage lists the jobs of a workflow that reference a secret
+type SecretUsage struct {
+	Workflow string   `json:"workflow"`
+	Jobs     []string `json:"jobs"`
+}
+
+// SecretAuditEntry describes one secret in the audit report
+type SecretAuditEntry struct {
+	Name         string        `json:"name"`
+	Scopes       []string      `json:"scopes,omitempty"`
+	Alternatives []string      `json:"alternatives,omitempty"`
+	Reason       string        `json:"reason,omitempty"`
+	Usage        []SecretUsage `json:"usage,omitempty"`
+}
+
+// SecretsAuditReport is the result of comparing workflow secret references with configured secrets
+type SecretsAuditReport struct {
+	Repository       string             `json:"repository"`
+	WorkflowsScanned int                `json:"workflows_scanned"`
+	Referenced       []SecretAuditEntry `json:"referenced"`
+	Missing          []SecretAuditEntry `json:"missing"`
+	Unused           []SecretAuditEntry `json:"unused"`
+	OverShared       []SecretAuditEntry `json:"over_shared"`
+}
+
+// newSecretsAuditSubcommand creates the secrets audit subcommand
+func newSecretsAuditSubcommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "audit",
+		Short: "Audit secret references in compiled workflows against configured secrets",
+		Long: `Scans all compiled workflows (.github/workflows/*.lock.yml) for secrets.* references
+and compares them with the secrets configured for the repository, its organization
+and its deployment environments.
+
+Lock files contain every secret a workflow uses, including engine secrets, MCP server
+header secrets and safe-output tokens, so the audit reflects what actually runs.
+
+The report lists:
+- Missing secrets: referenced by a job but not available to it. References with a
+  fallback (for example secrets.GH_AW_GITHUB_TOKEN || secrets.GITHUB_TOKEN) are only
+  missing when no alternative is configured.
+- Unused secrets: repository or environment secrets that no workflow references.
+- Over-shared secrets: organization secrets visible to the repository that no workflow
+  references, and secrets defined at more than one scope.
+
+Each secret is listed with the workflows and jobs that reference it.
+
+Examples:
+  gh aw secrets audit                    # Audit the current repository
+  gh aw secrets audit --repo owner/repo  # Audit a specific repository
+  gh aw secrets audit --json             # Output the report as JSON`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			repo, _ := cmd.Flags().GetString("repo")
+			jsonOutput, _ := cmd.Flags().GetBool("json")
+			return runSecretsAudit(getWorkflowsDir(), repo, jsonOutput)
+		},
+	}
+
+	addRepoFlag(cmd)
+	addJSONFlag(cmd)
+
+	return cmd
+}
+
+func runSecretsAudit(workflowsDir, repo string, jsonOutput bool) error {
+	secretsAuditLog.Printf("Running secrets audit: dir=%s, repo=%s", workflowsDir, repo)
+
+	repoSlug := repo
+	if repoSlug == "" {
+		var err error
+		repoSlug, err = GetCurrentRepoSlug()
+		if err != nil {
+			return fmt.Errorf("failed to detect current repository: %w", err)
+		}
+	}
+
+	refs, workflows, err := scanLockFileSecrets(workflowsDir)
+	if err != nil {
+		return err
+	}
+	if len(workflows) == 0 {
+		return fmt.Errorf("no compiled workflows (*.lock.yml) found in %s", workflowsDir)
+	}
+
+	externalRefs, err := scanOtherWorkflowSecrets(workflowsDir)
+	if err != nil {
+		return err
+	}
+
+	inventory, err := fetchSecretInventoryFunc(repoSlug)
+	if err != nil {
+		return fmt.Errorf("failed to list secrets for %s: %w", repoSlug, err)
+	}
+
+	report := buildSecretsAuditReport(refs, inventory, externalRefs)
+	report.Repository = repoSlug
+	report.WorkflowsScanned = len(workflows)
+
+	if jsonOutput {
+		jsonBytes, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON: %w", err)
+		}
+		fmt.Println(string(jsonBytes))
+		return nil
+	}
+
+	renderSecretsAuditReport(report)
+	return nil
+}
+
+// scanLockFileSecrets collects the secret references of every lock file in the directory.
+// It returns the references and the names of the workflows that were scanned.
+func scanLockFileSecrets(workflowsDir string) ([]lockSecretReference, []string, error) {
+	files, err := filepath.Glob(filepath.Join(workflowsDir, "*.lock.yml"))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to list lock files: %w", err)
+	}
+	sort.Strings(files)
+
+	var refs []lockSecretReference
+	var workflows []string
+	for _, file := range files {
+		content, err := os.ReadFile(file)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to read %s: %w", file, err)
+		}
+		workflowName := strings.TrimSuffix(filepath.Base(file), ".lock.yml")
+		fileRefs, err := extractLockFileSecretReferences(workflowName, content)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to parse %s: %w", file, err)
+		}
+		secretsAuditLog.Printf("Found %d secret references in %s", len(fileRefs), file)
+		refs = append(refs, fileRefs...)
+		workflows = append(workflows, workflowName)
+	}
+
+	return refs, workflows, nil
+}
+
+// extractLockFileSecretReferences parses a compiled workflow and attributes each secret
+// expression to the job that contains it
+func extractLockFileSecretReferences(workflowName string, content []byte) ([]lockSecretReference, error) {
+	var parsed map[string]any
+	if err := yaml.Unmarshal(content, &parsed); err != nil {
+		return nil, err
+	}
+
+	var refs []lockSecretReference
+	for key, value := range parsed {
+		if key == "jobs" {
+			continue
+		}
+		refs = append(refs, secretReferencesInValue(workflowName, workflowLevelJob, "", value)...)
+	}
+
+	jobs, _ := parsed["jobs"].(map[string]any)
+	for _, jobName := range slices.Sorted(maps.Keys(jobs)) {
+		job, _ := jobs[jobName].(map[string]any)
+		refs = append(refs, secretReferencesInValue(workflowName, jobName, jobEnvironmentName(job), job)...)
+	}
+
+	return refs, nil
+}
+
+// jobEnvironmentName returns the deployment environment of a job, which may be a name or a map with a name
+func jobEnvironmentName(job map[string]any) string {
+	switch env := job["environment"].(type) {
+	case string:
+		return env
+	case map[string]any:
+		if name, ok := env["name"].(string); ok {
+			return name
+		}
+	}
+	return ""
+}
+
+// secretReferencesInValue walks a parsed YAML value and returns the secret expressions in its strings
+func secretReferencesInValue(workflowName, jobName, environment string, value any) []lockSecretReference {
+	var refs []lockSecretReference
+	switch v := value.(type) {
+	case string:
+		for _, expr := range auditExpressionPattern.FindAllString(v, -1) {
+			var names []string
+			for _, match := range auditSecretNamePattern.FindAllStringSubmatch(expr, -1) {
+				name := strings.ToUpper(match[1])
+				if !slices.Contains(names, name) {
+					names = append(names, name)
+				}
+			}
+			if len(names) == 0 {
+				continue
+			}
+			refs = append(refs, lockSecretReference{
+				Workflow:    workflowName,
+				Job:         jobName,
+				Environment: environment,
+				Names:       names,
+				Optional:    auditLiteralFallbackPattern.MatchString(expr),
+			})
+		}
+	case map[string]any:
+		for _, key := range slices.Sorted(maps.Keys(v)) {
+			refs = append(refs, secretReferencesInValue(workflowName, jobName, environment, v[key])...)
+		}
+	case []any:
+		for _, item := range v {
+			refs = append(refs, secretReferencesInValue(workflowName, jobName, environment, item)...)
+		}
+	}
+	return refs
+}
+
+// scanOtherWorkflowSecrets returns the secrets referenced by non-agentic workflows in the
+// directory so that secrets they use are not reported as unused
+func scanOtherWorkflowSecrets(workflowsDir string) (map[string]bool, error) {
+	referenced := make(map[string]bool)
+	for _, pattern := range []string{"*.yml", "*.yaml"} {
+		files, err := filepath.Glob(filepath.Join(workflowsDir, pattern))
+		if err != nil {
+			return nil, fmt.Errorf("failed to list workflow files: %w", err)
+		}
+		for _, file := range files {
+			if strings.HasSuffix(file, ".lock.yml") {
+				continue
+			}
+			content, err := os.ReadFile(file)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read %s: %w", file, err)
+			}
+			for _, expr := range auditExpressionPattern.FindAllString(string(content), -1) {
+				for _, match := range auditSecretNamePattern.FindAllStringSubmatch(expr, -1) {
+					referenced[strings.ToUpper(match[1])] = true
+				}
+			}
+		}
+	}
+	secretsAuditLog.Printf("Found %d secrets referenced by non-agentic workflows", len(referenced))
+	return referenced, nil
+}
+
+// buildSecretsAuditReport compares secret references with the configured secrets
+func buildSecretsAuditReport(refs []lockSecretReference, inventory *secretInventory, externalRefs map[string]bool) *SecretsAuditReport {
+	scopes := secretScopes(inventory)
+
+	// availableTo reports whether a secret can be read by a job in the given environment
+	availableTo := func(name, environment string) bool {
+		if name == "GITHUB_TOKEN" {
+			return true
+		}
+		for _, scope := range scopes[name] {
+			if scope == "repository" || scope == "organization" || (environment != "" && scope == "environment:"+environment) {
+				return true
+			}
+		}
+		return false
+	}
+
+	usage := make(map[string]map[string]map[string]bool)
+	missingUsage := make(map[string]map[string]map[string]bool)
+	alternatives := make(map[string][]string)
+	addUsage := func(target map[string]map[string]map[string]bool, name, workflowName, jobName string) {
+		if target[name] == nil {
+			target[name] = make(map[string]map[string]bool)
+		}
+		if target[name][workflowName] == nil {
+			target[name][workflowName] = make(map[string]bool)
+		}
+		target[name][workflowName][jobName] = true
+	}
+
+	// Secrets that appear in a fallback chain are optional within that workflow, so standalone
+	// references to them (such as the redaction step) defer to the chain
+	chained := make(map[string]map[string]bool)
+	for _, ref := range refs {
+		if len(ref.Names) < 2 && !ref.Optional {
+			continue
+		}
+		if chained[ref.Workflow] == nil {
+			chained[ref.Workflow] = make(map[string]bool)
+		}
+		for _, name := range ref.Names {
+			chained[ref.Workflow][name] = true
+		}
+	}
+
+	for _, ref := range refs {
+		satisfied := ref.Optional || (len(ref.Names) == 1 && chained[ref.Workflow][ref.Names[0]])
+		for _, name := range ref.Names {
+			addUsage(usage, name, ref.Workflow, ref.Job)
+			if availableTo(name, ref.Environment) {
+				satisfied = true
+			}
+		}
+		if satisfied {
+			continue
+		}
+		for _, name := range ref.Names {
+			addUsage(missingUsage, name, ref.Workflow, ref.Job)
+			for _, other := range ref.Names {
+				if other != name && !slices.Contains(alternatives[name], other) {
+					alternatives[name] = append(alternatives[name], other)
+				}
+			}
+		}
+	}
+
+	report := &SecretsAuditReport{
+		Referenced: []SecretAuditEntry{},
+		Missing:    []SecretAuditEntry{},
+		Unused:     []SecretAuditEntry{},
+		OverShared: []SecretAuditEntry{},
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(usage)) {
+		if name == "GITHUB_TOKEN" {
+			continue
+		}
+		report.Referenced = append(report.Referenced, SecretAuditEntry{
+			Name:   name,
+			Scopes: scopes[name],
+			Usage:  secretUsageList(usage[name]),
+		})
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(missingUsage)) {
+		alts := alternatives[name]
+		sort.Strings(alts)
+		report.Missing = append(report.Missing, SecretAuditEntry{
+			Name:         name,
+			Scopes:       scopes[name],
+			Alternatives: alts,
+			Usage:        secretUsageList(missingUsage[name]),
+		})
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(scopes)) {
+		nameScopes := scopes[name]
+		used := usage[name] != nil || externalRefs[name]
+
+		if len(nameScopes) > 1 {
+			report.OverShared = append(report.OverShared, SecretAuditEntry{
+				Name:   name,
+				Scopes: nameScopes,
+				Reason: "defined at more than one scope",
+				Usage:  secretUsageList(usage[name]),
+			})
+			continue
+		}
+		if used {
+			continue
+		}
+		if nameScopes[0] == "organization" {
+			report.OverShared = append(report.OverShared, SecretAuditEntry{
+				Name:   name,
+				Scopes: nameScopes,
+				Reason: "organization secret visible to this repository but not referenced",
+			})
+			continue
+		}
+		report.Unused = append(report.Unused, SecretAuditEntry{Name: name, Scopes: nameScopes})
+	}
+
+	secretsAuditLog.Printf("Secrets audit: %d referenced, %d missing, %d unused, %d over-shared",
+		len(report.Referenced), len(report.Missing), len(report.Unused), len(report.OverShared))
+	return report
+}
+
+// secretScopes maps each configured secret name to the scopes that define it
+func secretScopes(inventory *secretInventory) map[string][]string {
+	scopes := make(map[string][]string)
+	add := func(name, scope string) {
+		name = strings.ToUpper(name)
+		if !slices.Contains(scopes[name], scope) {
+			scopes[name] = append(scopes[name], scope)
+		}
+	}
+	for _, name := range inventory.Repository {
+		add(name, "repository")
+	}
+	for _, name := range inventory.Organization {
+		add(name, "organization")
+	}
+	for _, env := range slices.Sorted(maps.Keys(inventory.Environments)) {
+		for _, name := range inventory.Environments[env] {
+			add(name, "environment:"+env)
+		}
+	}
+	return scopes
+}
+
+// secretUsageList converts a workflow to jobs set map into a sorted usage list
+func secretUsageList(workflows map[string]map[string]bool) []SecretUsage {
+	usage := make([]SecretUsage, 0, len(workflows))
+	for _, workflowName := range slices.Sorted(maps.Keys(workflows)) {
+		usage = append(usage, SecretUsage{Workflow: workflowName, Jobs: slices.Sorted(maps.Keys(workflows[workflowName]))})
+	}
+	return usage
+}
+
+// fetchSecretInventory lists the repository, organization and environment secrets visible to a repository.
+// Only the repository secrets are required; organization and environment secrets are skipped with a
+// warning when the token cannot read them.
+func fetchSecretInventory(repoSlug string) (*secretInventory, error) {
+	inventory := &secretInventory{Environments: make(map[string][]string)}
+
+	repoSecrets, err := listSecretNames("Listing repository secrets...", fmt.Sprintf("repos/%s/actions/secrets", repoSlug), ".secrets[].name")
+	if err != nil {
+		return nil, err
+	}
+	inventory.Repository = repoSecrets
+
+	orgSecrets, err := listSecretNames("Listing organization secrets...", fmt.Sprintf("repos/%s/actions/organization-secrets", repoSlug), ".secrets[].name")
+	if err != nil {
+		fmt.Fprintln(os.Stderr, console.FormatWarningMessage(fmt.Sprintf("Could not list organization secrets: %v", err)))
+	}
+	inventory.Organization = orgSecrets
+
+	environments, err := listSecretNames("Listing environments...", fmt.Sprintf("repos/%s/environments", repoSlug), ".environments[].name")
+	if err != nil {
+		fmt.Fprintln(os.Stderr, console.FormatWarningMessage(fmt.Sprintf("Could not list environments: %v", err)))
+	}
+	for _, env := range environments {
+		envSecrets, err := listSecretNames("Listing secrets for environment "+env+"...", fmt.Sprintf("repos/%s/environments/%s/secrets", repoSlug, url.PathEscape(env)), ".secrets[].name")
+		if err != nil {
+			fmt.Fprintln(os.Stderr, console.FormatWarningMessage(fmt.Sprintf("Could not list secrets for environment %s: %v", env, err)))
+			continue
+		}
+		inventory.Environments[env] = envSecrets
+	}
+
+	secretsAuditLog.Printf("Secret inventory: %d repository, %d organization, %d environments",
+		len(inventory.Repository), len(inventory.Organization), len(inventory.Environments))
+	return inventory, nil
+}
+
+// listSecretNames calls a paginated GitHub API endpoint and returns the names selected by the jq filter
+func listSecretNames(spinnerMessage, path, jq string) ([]string, error) {
+	output, err := workflow.RunGH(spinnerMessage, "api", path, "--paginate", "--jq", jq)
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for line := range strings.SplitSeq(string(output), "\n") {
+		if name := strings.TrimSpace(line); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names, nil
+}
+
+// renderSecretsAuditReport prints the audit report as console tables
+func renderSecretsAuditReport(report *SecretsAuditReport) {
+	fmt.Fprintln(os.Stderr, console.FormatInfoMessage(fmt.Sprintf("Secrets audit for %s (%d workflows scanned)", report.Repository, report.WorkflowsScanned)))
+	fmt.Fprintln(os.Stderr, "")
+
+	if len(report.Referenced) > 0 {
+		fmt.Fprint(os.Stderr, console.RenderTable(console.TableConfig{
+			Title:   "Referenced Secrets",
+			Headers: []string{"Secret", "Defined In", "Used By"},
+			Rows:    secretAuditRows(report.Referenced, false),
+		}))
+		fmt.Fprintln(os.Stderr, "")
+	}
+
+	if len(report.Missing) > 0 {
+		fmt.Fprint(os.Stderr, console.RenderTable(console.TableConfig{
+			Title:   "Missing Secrets",
+			Headers: []string{"Secret", "Alternatives", "Used By"},
+			Rows:    secretAuditRows(report.Missing, true),
+		}))
+		fmt.Fprintln(os.Stderr, "")
+	}
+
+	if len(report.Unused) > 0 {
+		fmt.Fprint(os.Stderr, console.RenderTable(console.TableConfig{
+			Title:   "Unused Secrets",
+			Headers: []string{"Secret", "Defined In", "Used By"},
+			Rows:    secretAuditRows(report.Unused, false),
+		}))
+		fmt.Fprintln(os.Stderr, "")
+	}
+
+	if len(report.OverShared) > 0 {
+		rows := make([][]string, 0, len(report.OverShared))
+		for _, entry := range report.OverShared {
+			rows = append(rows, []string{entry.Name, strings.Join(entry.Scopes, ", "), entry.Reason})
+		}
+		fmt.Fprint(os.Stderr, console.RenderTable(console.TableConfig{
+			Title:   "Over-Shared Secrets",
+			Headers: []string{"Secret", "Defined In", "Reason"},
+			Rows:    rows,
+		}))
+		fmt.Fprintln(os.Stderr, "")
+	}
+
+	if len(report.Missing) == 0 && len(report.Unused) == 0 && len(report.OverShared) == 0 {
+		fmt.Fprintln(os.Stderr, console.FormatSuccessMessage("All referenced secrets are configured and no configured secrets are unused"))
+		return
+	}
+	fmt.Fprintln(os.Stderr, console.FormatWarningMessage(fmt.Sprintf("%d missing, %d unused, %d over-shared secrets",
+		len(report.Missing), len(report.Unused), len(report.OverShared))))
+}
+
+// secretAuditRows formats audit entries as table rows, showing alternatives or scopes in the second column
+func secretAuditRows(entries []SecretAuditEntry, showAlternatives bool) [][]string {
+	rows := make([][]string, 0, len(entries))
+	for _, entry := range entries {
+		second := strings.Join(entry.Scopes, ", ")
+		if showAlternatives {
+			second = strings.Join(entry.Alternatives, ", ")
+		}
+		if second == "" {
+			second = "-"
+		}
+		rows = append(rows, []string{entry.Name, second, formatSecretUsage(entry.Usage)})
+	}
+	return rows
+}
+
+// formatSecretUsage formats usage as "workflow (job, job); workflow (job)", listing at most
+// maxUsageWorkflowsShown workflows. The JSON output always contains the full list.
+func formatSecretUsage(usage []SecretUsage) string {
+	if len(usage) == 0 {
+		return "-"
+	}
+	parts := make([]string, 0, maxUsageWorkflowsShown+1)
+	for _, u := range usage[:min(len(usage), maxUsageWorkflowsShown)] {
+		parts = append(parts, fmt.Sprintf("%s (%s)", u.Workflow, strings.Join(u.Jobs, ", ")))
+	}
+	if len(usage) > maxUsageWorkflowsShown {
+		parts = append(parts, fmt.Sprintf("+%d more", len(usage)-maxUsageWorkflowsShown))
+	}
+	return strings.Join(parts, "; ")
+}
@@ -0,0 +1,157 @@
+//go:build !integration
+
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const auditTestLockFile = `name: "Triage"
+on:
+  issues:
+    types: [opened]
+jobs:
+  agent:
+    runs-on: ubuntu-latest
+    steps:
+      - name: Execute Copilot CLI
+        env:
+          COPILOT_GITHUB_TOKEN: ${{ secrets.COPILOT_GITHUB_TOKEN }}
+          GITHUB_MCP_SERVER_TOKEN: ${{ secrets.GH_AW_GITHUB_MCP_SERVER_TOKEN || secrets.GH_AW_GITHUB_TOKEN || secrets.GITHUB_TOKEN }}
+          DD_API_KEY: ${{ secrets.DD_API_KEY }}
+          DD_SITE: ${{ secrets.DD_SITE || 'datadoghq.com' }}
+        run: copilot --prompt "triage"
+      - name: Redact secrets in logs
+        env:
+          SECRET_GH_AW_GITHUB_MCP_SERVER_TOKEN: ${{ secrets.GH_AW_GITHUB_MCP_SERVER_TOKEN }}
+        run: redact
+  deploy:
+    runs-on: ubuntu-latest
+    environment:
+      name: production
+    steps:
+      - run: ./deploy.sh
+        env:
+          DEPLOY_KEY: ${{ secrets.DEPLOY_KEY }}
+  safe_outputs:
+    runs-on: ubuntu-latest
+    steps:
+      - uses: actions/github-script@v8
+        with:
+          github-token: ${{ secrets.GH_AW_AGENT_TOKEN || secrets.GH_AW_CUSTOM_TOKEN }}
+          script: |
+            const token = "${{ secrets.DEPLOY_KEY }}";
+`
+
+func TestExtractLockFileSecretReferences(t *testing.T) {
+	refs, err := extractLockFileSecretReferences("triage", []byte(auditTestLockFile))
+	require.NoError(t, err)
+
+	byJob := make(map[string][]lockSecretReference)
+	for _, ref := range refs {
+		assert.Equal(t, "triage", ref.Workflow)
+		byJob[ref.Job] = append(byJob[ref.Job], ref)
+	}
+
+	require.Len(t, byJob["agent"], 5)
+	assert.Contains(t, byJob["agent"], lockSecretReference{Workflow: "triage", Job: "agent", Names: []string{"GH_AW_GITHUB_MCP_SERVER_TOKEN", "GH_AW_GITHUB_TOKEN", "GITHUB_TOKEN"}})
+	assert.Contains(t, byJob["agent"], lockSecretReference{Workflow: "triage", Job: "agent", Names: []string{"DD_SITE"}, Optional: true})
+
+	require.Len(t, byJob["deploy"], 1)
+	assert.Equal(t, "production", byJob["deploy"][0].Environment, "environment should be read from the job")
+
+	require.Len(t, byJob["safe_outputs"], 2, "secrets inside run scripts should be found")
+}
+
+func TestBuildSecretsAuditReport(t *testing.T) {
+	refs, err := extractLockFileSecretReferences("triage", []byte(auditTestLockFile))
+	require.NoError(t, err)
+
+	inventory := &secretInventory{
+		Repository:   []string{"COPILOT_GITHUB_TOKEN", "GH_AW_GITHUB_TOKEN", "OLD_TOKEN", "CI_TOKEN"},
+		Organization: []string{"COPILOT_GITHUB_TOKEN", "ORG_NPM_TOKEN"},
+		Environments: map[string][]string{"production": {"DEPLOY_KEY"}},
+	}
+	report := buildSecretsAuditReport(refs, inventory, map[string]bool{"CI_TOKEN": true})
+
+	names := func(entries []SecretAuditEntry) []string {
+		result := make([]string, 0, len(entries))
+		for _, entry := range entries {
+			result = append(result, entry.Name)
+		}
+		return result
+	}
+
+	// DEPLOY_KEY is available to the deploy job through its environment but not to safe_outputs
+	assert.Equal(t, []string{"DD_API_KEY", "DEPLOY_KEY", "GH_AW_AGENT_TOKEN", "GH_AW_CUSTOM_TOKEN"}, names(report.Missing))
+	for _, entry := range report.Missing {
+		switch entry.Name {
+		case "DEPLOY_KEY":
+			assert.Equal(t, []SecretUsage{{Workflow: "triage", Jobs: []string{"safe_outputs"}}}, entry.Usage)
+		case "GH_AW_AGENT_TOKEN":
+			assert.Equal(t, []string{"GH_AW_CUSTOM_TOKEN"}, entry.Alternatives)
+		}
+	}
+
+	assert.NotContains(t, names(report.Missing), "GH_AW_GITHUB_MCP_SERVER_TOKEN", "standalone references should defer to the fallback chain")
+	assert.Equal(t, []string{"OLD_TOKEN"}, names(report.Unused), "secrets used by non-agentic workflows should not be unused")
+	assert.Equal(t, []string{"COPILOT_GITHUB_TOKEN", "ORG_NPM_TOKEN"}, names(report.OverShared))
+	assert.Equal(t, "defined at more than one scope", report.OverShared[0].Reason)
+	assert.Equal(t, []string{"repository", "organization"}, report.OverShared[0].Scopes)
+
+	assert.NotContains(t, names(report.Referenced), "GITHUB_TOKEN")
+	assert.Contains(t, names(report.Referenced), "GH_AW_GITHUB_MCP_SERVER_TOKEN", "optional alternatives are still listed as referenced")
+	for _, entry := range report.Referenced {
+		if entry.Name == "DEPLOY_KEY" {
+			assert.Equal(t, []SecretUsage{{Workflow: "triage", Jobs: []string{"deploy", "safe_outputs"}}}, entry.Usage)
+			assert.Equal(t, []string{"environment:production"}, entry.Scopes)
+		}
+	}
+}
+
+func TestRunSecretsAuditJSON(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "triage.lock.yml"), []byte(auditTestLockFile), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "ci.yml"), []byte("jobs:\n  test:\n    steps:\n      - run: echo ${{ secrets.CI_TOKEN }}\n"), 0644))
+
+	original := fetchSecretInventoryFunc
+	t.Cleanup(func() { fetchSecretInventoryFunc = original })
+	fetchSecretInventoryFunc = func(repoSlug string) (*secretInventory, error) {
+		assert.Equal(t, "owner/repo", repoSlug)
+		return &secretInventory{Repository: []string{"CI_TOKEN", "COPILOT_GITHUB_TOKEN"}}, nil
+	}
+
+	// Capture stdout
+	oldStdout := os.Stdout
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+
+	err := runSecretsAudit(dir, "owner/repo", true)
+	w.Close()
+
+	var buf bytes.Buffer
+	io.Copy(&buf, r)
+	os.Stdout = oldStdout
+	require.NoError(t, err)
+
+	var report SecretsAuditReport
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &report))
+	assert.Equal(t, "owner/repo", report.Repository)
+	assert.Equal(t, 1, report.WorkflowsScanned)
+	assert.Empty(t, report.Unused)
+	assert.NotEmpty(t, report.Missing)
+}
+
+func TestRunSecretsAuditNoLockFiles(t *testing.T) {
+	err := runSecretsAudit(t.TempDir(), "owner/repo", true)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "no compiled workflows")
+}
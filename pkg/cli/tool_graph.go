@@ -118,7 +118,28 @@ func (g *ToolGraph) GenerateMermaidGraph() string {
 		}
 	}
 
-	// Add transitions with counts as labels
+	// Add transitions with counts as labels, most frequent first
+	transitions := g.SortedTransitions()
+	for _, transition := range transitions {
+		fromState, fromExists := toolToStateMap[transition.From]
+		toState, toExists := toolToStateMap[transition.To]
+
+		if fromExists && toExists {
+			label := ""
+			if transition.Count > 1 {
+				label = fmt.Sprintf(" : %dx", transition.Count)
+			}
+			fmt.Fprintf(&sb, "    %s --> %s%s\n", fromState, toState, label)
+		}
+	}
+
+	sb.WriteString("```\n")
+	return sb.String()
+}
+
+// SortedTransitions returns the transitions of the graph sorted by count (descending),
+// then by source and target tool name
+func (g *ToolGraph) SortedTransitions() []ToolTransition {
 	var transitions []ToolTransition
 	for key, count := range g.Transitions {
 		parts := strings.Split(key, "->")
@@ -131,7 +152,6 @@ func (g *ToolGraph) GenerateMermaidGraph() string {
 		}
 	}
 
-	// Sort transitions by count (descending) for better visualization
 	sort.Slice(transitions, func(i, j int) bool {
 		if transitions[i].Count != transitions[j].Count {
 			return transitions[i].Count > transitions[j].Count
@@ -141,22 +161,7 @@ func (g *ToolGraph) GenerateMermaidGraph() string {
 		}
 		return transitions[i].To < transitions[j].To
 	})
-
-	for _, transition := range transitions {
-		fromState, fromExists := toolToStateMap[transition.From]
-		toState, toExists := toolToStateMap[transition.To]
-
-		if fromExists && toExists {
-			label := ""
-			if transition.Count > 1 {
-				label = fmt.Sprintf(" : %dx", transition.Count)
-			}
-			fmt.Fprintf(&sb, "    %s --> %s%s\n", fromState, toState, label)
-		}
-	}
-
-	sb.WriteString("```\n")
-	return sb.String()
+	return transitions
 }
 
 // GetSummary returns a summary of the tool graph
@@ -210,6 +215,15 @@ func generateToolGraph(processedRuns []ProcessedRun, verbose bool) {
 	}
 
 	toolGraphLog.Printf("Generating tool graph from %d processed runs", len(processedRuns))
+	graph := buildToolGraph(processedRuns, verbose)
+
+	// Generate and display Mermaid graph only
+	mermaidGraph := graph.GenerateMermaidGraph()
+	fmt.Println(mermaidGraph)
+}
+
+// buildToolGraph builds a tool sequence graph from the agent logs of processed runs
+func buildToolGraph(processedRuns []ProcessedRun, verbose bool) *ToolGraph {
 	graph := NewToolGraph()
 	for _, run := range processedRuns {
 		sequences := extractToolSequencesFromRun(run, verbose)
@@ -225,10 +239,7 @@ func generateToolGraph(processedRuns []ProcessedRun, verbose bool) {
 			graph.AddSequence(sequence)
 		}
 	}
-
-	// Generate and display Mermaid graph only
-	mermaidGraph := graph.GenerateMermaidGraph()
-	fmt.Println(mermaidGraph)
+	return graph
 }
 
 // extractToolSequencesFromRun extracts tool call sequences from a single run
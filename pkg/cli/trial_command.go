@@ -9,6 +9,7 @@ import (
 	"os/exec"
 	"path/filepath"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -33,6 +34,9 @@ type WorkflowTrialResult struct {
 	AgenticRunInfo      map[string]any `json:"agentic_run_info,omitempty"`
 	AdditionalArtifacts map[string]any `json:"additional_artifacts,omitempty"`
 	Timestamp           time.Time      `json:"timestamp"`
+	// Scenario and Assertions are set when running with an eval spec
+	Scenario   string                `json:"scenario,omitempty"`
+	Assertions []EvalAssertionResult `json:"assertions,omitempty"`
 }
 
 // CombinedTrialResult represents the combined results of multiple workflow trials
@@ -64,6 +68,7 @@ type TrialOptions struct {
 	AppendText             string
 	Verbose                bool
 	DisableSecurityScanner bool
+	EvalSpecPath           string
 }
 
 // NewTrialCommand creates the trial command
@@ -99,6 +104,9 @@ Repeat and cleanup examples:
   ` + string(constants.CLIExtensionPrefix) + ` trial githubnext/agentics/my-workflow --quiet --host-repo my-trial # Custom host repo
   ` + string(constants.CLIExtensionPrefix) + ` trial githubnext/agentics/my-workflow --dry-run                 # Show what would be done without changes
 
+Eval examples:
+  ` + string(constants.CLIExtensionPrefix) + ` trial ./issue-triage.md --eval evals/issue-triage.yml --repeat 4   # Score 5 runs of each scenario
+
 Auto-merge examples:
   ` + string(constants.CLIExtensionPrefix) + ` trial githubnext/agentics/my-workflow --auto-merge-prs          # Auto-merge any PRs created during trial
 
@@ -112,6 +120,11 @@ Repository modes:
 - --repo REPO: Runs directly in the specified repository (no simulation, workflows installed and executed in REPO)
 - --clone-repo REPO: Clones the specified repository's contents into the trial repository before execution (useful for testing against actual repository state)
 
+Eval mode:
+- --eval SPEC runs each scenario of the eval spec (trigger context, issue or pull request fixture,
+  workflow_dispatch inputs) and checks its safe outputs against the scenario's assertions. With --repeat,
+  pass rates are computed across all runs and written to trials/ as a JUnit XML report and a markdown scorecard.
+
 All workflows must support workflow_dispatch trigger to be used in trial mode.
 The host repository will be created as private and kept by default unless --delete-host-repo-after is specified.
 Trial results are saved both locally (in trials/ directory) and in the host repository for future reference.`,
@@ -139,6 +152,7 @@ Trial results are saved both locally (in trials/ directory) and in the host repo
 			appendText, _ := cmd.Flags().GetString("append")
 			verbose, _ := cmd.Root().PersistentFlags().GetBool("verbose")
 			disableSecurityScanner, _ := cmd.Flags().GetBool("disable-security-scanner")
+			evalSpecPath, _ := cmd.Flags().GetString("eval")
 
 			if err := validateEngine(engineOverride); err != nil {
 				return err
@@ -166,6 +180,7 @@ Trial results are saved both locally (in trials/ directory) and in the host repo
 				AppendText:             appendText,
 				Verbose:                verbose,
 				DisableSecurityScanner: disableSecurityScanner,
+				EvalSpecPath:           evalSpecPath,
 			}
 
 			if err := RunWorkflowTrials(cmd.Context(), workflowSpecs, opts); err != nil {
@@ -192,6 +207,7 @@ Trial results are saved both locally (in trials/ directory) and in the host repo
 	addEngineFlag(cmd)
 	cmd.Flags().String("append", "", "Append extra content to the end of agentic workflow on installation")
 	cmd.Flags().Bool("disable-security-scanner", false, "Disable security scanning of workflow markdown content")
+	cmd.Flags().String("eval", "", "Eval spec file with scenarios and safe output assertions; writes a JUnit XML report and markdown scorecard to trials/")
 	cmd.MarkFlagsMutuallyExclusive("host-repo", "repo")
 	cmd.MarkFlagsMutuallyExclusive("logical-repo", "clone-repo")
 
@@ -215,6 +231,26 @@ func RunWorkflowTrials(ctx context.Context, workflowSpecs []string, opts TrialOp
 		parsedSpecs = append(parsedSpecs, parsedSpec)
 	}
 
+	// Load the eval spec, which scores the safe outputs of a single workflow
+	var evalSpec *EvalSpec
+	var evalReport *EvalReport
+	evalIteration := 0
+	if opts.EvalSpecPath != "" {
+		if len(parsedSpecs) != 1 {
+			return errors.New("--eval can only be used with a single workflow")
+		}
+		spec, err := LoadEvalSpec(opts.EvalSpecPath)
+		if err != nil {
+			return err
+		}
+		if spec.Workflow != "" && spec.Workflow != parsedSpecs[0].WorkflowName {
+			return fmt.Errorf("eval spec %s is for workflow '%s', not '%s'", opts.EvalSpecPath, spec.Workflow, parsedSpecs[0].WorkflowName)
+		}
+		evalSpec = spec
+		evalReport = &EvalReport{Workflow: parsedSpecs[0].WorkflowName, Spec: opts.EvalSpecPath, Timestamp: time.Now()}
+		trialLog.Printf("Loaded eval spec with %d scenarios", len(spec.Scenarios))
+	}
+
 	if opts.DryRun {
 		fmt.Fprintln(os.Stderr, console.FormatInfoMessage("[DRY RUN] Showing what would be done without making changes"))
 	}
@@ -446,6 +482,8 @@ func RunWorkflowTrials(ctx context.Context, workflowSpecs []string, opts TrialOp
 
 		// Step 5: Run trials for each workflow
 		var workflowResults []WorkflowTrialResult
+		var resultNames []string
+		evalIteration++
 
 		for _, parsedSpec := range parsedSpecs {
 			fmt.Fprintln(os.Stderr, console.FormatInfoMessage(fmt.Sprintf("=== Running trial for workflow: %s ===", parsedSpec.WorkflowName)))
@@ -463,73 +501,129 @@ func RunWorkflowTrials(ctx context.Context, workflowSpecs []string, opts TrialOp
 				fmt.Fprintln(os.Stderr, "")
 			}
 
-			// Run the workflow and wait for completion (with trigger context if provided)
-			runID, err := triggerWorkflowRun(hostRepoSlug, parsedSpec.WorkflowName, opts.TriggerContext, opts.Verbose)
-			if err != nil {
-				return fmt.Errorf("failed to trigger workflow run for '%s': %w", parsedSpec.WorkflowName, err)
+			// Without an eval spec the workflow runs once with the trigger context of the command line
+			scenarios := []EvalScenario{{TriggerContext: opts.TriggerContext}}
+			if evalSpec != nil {
+				scenarios = evalSpec.Scenarios
 			}
 
-			// Generate workflow run URL
-			githubHost := getGitHubHost()
-			workflowRunURL := fmt.Sprintf("%s/%s/actions/runs/%s", githubHost, hostRepoSlug, runID)
-			fmt.Fprintln(os.Stderr, console.FormatInfoMessage(fmt.Sprintf("Workflow run started with ID: %s (%s)", runID, workflowRunURL)))
+			for _, scenario := range scenarios {
+				triggerContext := scenario.TriggerContext
+				if triggerContext == "" {
+					triggerContext = opts.TriggerContext
+				}
+				if scenario.Name != "" {
+					fmt.Fprintln(os.Stderr, console.FormatInfoMessage(fmt.Sprintf("--- Scenario: %s ---", scenario.Name)))
+				}
+				if scenario.Fixture != nil {
+					fixtureContext, err := createEvalFixture(hostRepoSlug, scenario, opts.Verbose)
+					if err != nil {
+						return fmt.Errorf("failed to create fixture for scenario '%s': %w", scenario.Name, err)
+					}
+					triggerContext = fixtureContext
+				}
+				started := time.Now()
 
-			// Wait for workflow completion
-			if err := WaitForWorkflowCompletion(hostRepoSlug, runID, opts.TimeoutMinutes, opts.Verbose); err != nil {
-				return fmt.Errorf("workflow '%s' execution failed or timed out: %w", parsedSpec.WorkflowName, err)
-			}
+				// Run the workflow and wait for completion (with trigger context if provided)
+				runID, err := triggerWorkflowRun(hostRepoSlug, parsedSpec.WorkflowName, triggerContext, scenario.Inputs, opts.Verbose)
+				if err != nil {
+					return fmt.Errorf("failed to trigger workflow run for '%s': %w", parsedSpec.WorkflowName, err)
+				}
 
-			// Auto-merge PRs if requested
-			if opts.AutoMergePRs {
-				if err := AutoMergePullRequestsLegacy(hostRepoSlug, opts.Verbose); err != nil {
-					fmt.Fprintln(os.Stderr, console.FormatWarningMessage(fmt.Sprintf("Failed to auto-merge pull requests: %v", err)))
+				// Generate workflow run URL
+				githubHost := getGitHubHost()
+				workflowRunURL := fmt.Sprintf("%s/%s/actions/runs/%s", githubHost, hostRepoSlug, runID)
+				fmt.Fprintln(os.Stderr, console.FormatInfoMessage(fmt.Sprintf("Workflow run started with ID: %s (%s)", runID, workflowRunURL)))
+
+				// Wait for workflow completion
+				if err := WaitForWorkflowCompletion(hostRepoSlug, runID, opts.TimeoutMinutes, opts.Verbose); err != nil {
+					// A failed run is a failed eval scenario rather than an aborted trial
+					if evalSpec != nil {
+						fmt.Fprintln(os.Stderr, console.FormatErrorMessage(fmt.Sprintf("Scenario '%s' failed: %v", scenario.Name, err)))
+						evalReport.AddRun(scenario.Name, EvalScenarioRun{
+							Repeat:     evalIteration,
+							RunID:      runID,
+							Duration:   time.Since(started),
+							Assertions: []EvalAssertionResult{{Assertion: "workflow run succeeds", Message: err.Error()}},
+						})
+						continue
+					}
+					return fmt.Errorf("workflow '%s' execution failed or timed out: %w", parsedSpec.WorkflowName, err)
 				}
-			}
 
-			// Download and process all artifacts
-			artifacts, err := downloadAllArtifacts(hostRepoSlug, runID, opts.Verbose)
-			if err != nil {
-				return fmt.Errorf("failed to download artifacts for '%s': %w", parsedSpec.WorkflowName, err)
-			}
+				// Auto-merge PRs if requested
+				if opts.AutoMergePRs {
+					if err := AutoMergePullRequestsLegacy(hostRepoSlug, opts.Verbose); err != nil {
+						fmt.Fprintln(os.Stderr, console.FormatWarningMessage(fmt.Sprintf("Failed to auto-merge pull requests: %v", err)))
+					}
+				}
 
-			// Save individual workflow results
-			result := WorkflowTrialResult{
-				WorkflowName: parsedSpec.WorkflowName,
-				RunID:        runID,
-				SafeOutputs:  artifacts.SafeOutputs,
-				//AgentStdioLogs:      artifacts.AgentStdioLogs,
-				AgenticRunInfo:      artifacts.AgenticRunInfo,
-				AdditionalArtifacts: artifacts.AdditionalArtifacts,
-				Timestamp:           time.Now(),
-			}
-			workflowResults = append(workflowResults, result)
+				// Download and process all artifacts
+				artifacts, err := downloadAllArtifacts(hostRepoSlug, runID, opts.Verbose)
+				if err != nil {
+					return fmt.Errorf("failed to download artifacts for '%s': %w", parsedSpec.WorkflowName, err)
+				}
 
-			// Save individual trial file
-			sanitizedTargetRepo := repoutil.SanitizeForFilename(targetRepoForFilename)
-			individualFilename := fmt.Sprintf("trials/%s-%s.%s.json", parsedSpec.WorkflowName, sanitizedTargetRepo, dateTimeID)
-			if err := saveTrialResult(individualFilename, result, opts.Verbose); err != nil {
-				fmt.Fprintln(os.Stderr, console.FormatWarningMessage(fmt.Sprintf("Failed to save individual trial result: %v", err)))
-			}
+				// Save individual workflow results
+				result := WorkflowTrialResult{
+					WorkflowName: parsedSpec.WorkflowName,
+					RunID:        runID,
+					SafeOutputs:  artifacts.SafeOutputs,
+					//AgentStdioLogs:      artifacts.AgentStdioLogs,
+					AgenticRunInfo:      artifacts.AgenticRunInfo,
+					AdditionalArtifacts: artifacts.AdditionalArtifacts,
+					Timestamp:           time.Now(),
+					Scenario:            scenario.Name,
+				}
+				if evalSpec != nil {
+					evalRun := EvaluateScenario(scenario, result, evalIteration, time.Since(started))
+					result.Assertions = evalRun.Assertions
+					evalReport.AddRun(scenario.Name, evalRun)
+					if evalRun.Passed {
+						fmt.Fprintln(os.Stderr, console.FormatSuccessMessage(fmt.Sprintf("Scenario '%s' passed", scenario.Name)))
+					} else {
+						fmt.Fprintln(os.Stderr, console.FormatErrorMessage(fmt.Sprintf("Scenario '%s' failed", scenario.Name)))
+						for _, assertion := range evalRun.Assertions {
+							if !assertion.Passed {
+								fmt.Fprintln(os.Stderr, console.FormatListItem(assertion.Message))
+							}
+						}
+					}
+				}
+				workflowResults = append(workflowResults, result)
 
-			// Display safe outputs to stdout
-			if len(artifacts.SafeOutputs) > 0 {
-				outputBytes, _ := json.MarshalIndent(artifacts.SafeOutputs, "", "  ")
-				fmt.Fprintln(os.Stderr, console.FormatSuccessMessage(fmt.Sprintf("=== Safe Outputs from %s ===", parsedSpec.WorkflowName)))
-				fmt.Println(string(outputBytes))
-				fmt.Fprintln(os.Stderr, console.FormatSuccessMessage("=== End of Safe Outputs ==="))
-			} else {
-				fmt.Fprintln(os.Stderr, console.FormatInfoMessage(fmt.Sprintf("=== No Safe Outputs Generated by %s ===", parsedSpec.WorkflowName)))
-			}
+				// Save individual trial file
+				resultName := parsedSpec.WorkflowName
+				if scenario.Name != "" {
+					resultName += "-" + sanitizeBranchName(scenario.Name)
+				}
+				resultNames = append(resultNames, resultName)
+				sanitizedTargetRepo := repoutil.SanitizeForFilename(targetRepoForFilename)
+				individualFilename := fmt.Sprintf("trials/%s-%s.%s.json", resultName, sanitizedTargetRepo, dateTimeID)
+				if err := saveTrialResult(individualFilename, result, opts.Verbose); err != nil {
+					fmt.Fprintln(os.Stderr, console.FormatWarningMessage(fmt.Sprintf("Failed to save individual trial result: %v", err)))
+				}
 
-			// Display additional artifact information if available
-			// if len(artifacts.AgentStdioLogs) > 0 {
-			// 	fmt.Fprintln(os.Stderr, console.FormatInfoMessage(fmt.Sprintf("=== Agent Stdio Logs Available from %s (%d files) ===", parsedSpec.WorkflowName, len(artifacts.AgentStdioLogs))))
-			// }
-			if len(artifacts.AgenticRunInfo) > 0 {
-				fmt.Fprintln(os.Stderr, console.FormatInfoMessage(fmt.Sprintf("=== Agentic Run Information Available from %s ===", parsedSpec.WorkflowName)))
-			}
-			if len(artifacts.AdditionalArtifacts) > 0 {
-				fmt.Fprintln(os.Stderr, console.FormatInfoMessage(fmt.Sprintf("=== Additional Artifacts Available from %s (%d files) ===", parsedSpec.WorkflowName, len(artifacts.AdditionalArtifacts))))
+				// Display safe outputs to stdout
+				if len(artifacts.SafeOutputs) > 0 {
+					outputBytes, _ := json.MarshalIndent(artifacts.SafeOutputs, "", "  ")
+					fmt.Fprintln(os.Stderr, console.FormatSuccessMessage(fmt.Sprintf("=== Safe Outputs from %s ===", parsedSpec.WorkflowName)))
+					fmt.Println(string(outputBytes))
+					fmt.Fprintln(os.Stderr, console.FormatSuccessMessage("=== End of Safe Outputs ==="))
+				} else {
+					fmt.Fprintln(os.Stderr, console.FormatInfoMessage(fmt.Sprintf("=== No Safe Outputs Generated by %s ===", parsedSpec.WorkflowName)))
+				}
+
+				// Display additional artifact information if available
+				// if len(artifacts.AgentStdioLogs) > 0 {
+				// 	fmt.Fprintln(os.Stderr, console.FormatInfoMessage(fmt.Sprintf("=== Agent Stdio Logs Available from %s (%d files) ===", parsedSpec.WorkflowName, len(artifacts.AgentStdioLogs))))
+				// }
+				if len(artifacts.AgenticRunInfo) > 0 {
+					fmt.Fprintln(os.Stderr, console.FormatInfoMessage(fmt.Sprintf("=== Agentic Run Information Available from %s ===", parsedSpec.WorkflowName)))
+				}
+				if len(artifacts.AdditionalArtifacts) > 0 {
+					fmt.Fprintln(os.Stderr, console.FormatInfoMessage(fmt.Sprintf("=== Additional Artifacts Available from %s (%d files) ===", parsedSpec.WorkflowName, len(artifacts.AdditionalArtifacts))))
+				}
 			}
 
 			fmt.Fprintln(os.Stderr, console.FormatSuccessMessage("Trial completed for workflow: "+parsedSpec.WorkflowName))
@@ -560,7 +654,7 @@ func RunWorkflowTrials(ctx context.Context, workflowSpecs []string, opts TrialOp
 		for i, spec := range parsedSpecs {
 			workflowNames[i] = spec.WorkflowName
 		}
-		if err := copyTrialResultsToHostRepo(tempDir, dateTimeID, workflowNames, targetRepoForFilename, opts.Verbose); err != nil {
+		if err := copyTrialResultsToHostRepo(tempDir, dateTimeID, workflowNames, resultNames, targetRepoForFilename, opts.Verbose); err != nil {
 			fmt.Fprintln(os.Stderr, console.FormatWarningMessage(fmt.Sprintf("Failed to copy trial results to repository: %v", err)))
 		}
 
@@ -569,7 +663,7 @@ func RunWorkflowTrials(ctx context.Context, workflowSpecs []string, opts TrialOp
 	}
 
 	// Execute trials with optional repeat functionality
-	return ExecuteWithRepeat(RepeatOptions{
+	err := ExecuteWithRepeat(RepeatOptions{
 		RepeatCount:   opts.RepeatCount,
 		RepeatMessage: "Repeating trial run",
 		ExecuteFunc:   runAllTrials,
@@ -584,6 +678,17 @@ func RunWorkflowTrials(ctx context.Context, workflowSpecs []string, opts TrialOp
 		UseStderr: true,
 	})
 
+	// Score the runs recorded so far, even if a later run aborted the trial
+	if evalReport != nil && len(evalReport.Scenarios) > 0 {
+		junitPath, scorecardPath, reportErr := writeEvalReports(evalReport, "trials", opts.Verbose)
+		if reportErr != nil {
+			return errors.Join(err, reportErr)
+		}
+		fmt.Fprintln(os.Stderr, console.FormatInfoMessage("Eval scorecard for "+evalReport.Workflow+":"))
+		fmt.Fprint(os.Stderr, console.RenderStruct(evalReport.ScorecardRows()))
+		fmt.Fprintln(os.Stderr, console.FormatInfoMessage(fmt.Sprintf("Eval reports saved to: %s, %s", junitPath, scorecardPath)))
+	}
+	return err
 }
 
 // getCurrentGitHubUsername gets the current GitHub username from gh CLI
@@ -818,7 +923,7 @@ func showTrialConfirmation(parsedSpecs []*WorkflowSpec, logicalRepoSlug, cloneRe
 	return nil
 }
 
-func triggerWorkflowRun(repoSlug, workflowName string, triggerContext string, verbose bool) (string, error) {
+func triggerWorkflowRun(repoSlug, workflowName string, triggerContext string, inputs map[string]string, verbose bool) (string, error) {
 	if verbose {
 		fmt.Fprintln(os.Stderr, console.FormatInfoMessage("Triggering workflow run for: "+workflowName))
 	}
@@ -842,6 +947,16 @@ func triggerWorkflowRun(repoSlug, workflowName string, triggerContext string, ve
 		}
 	}
 
+	// Add explicit workflow_dispatch inputs in a stable order
+	inputNames := make([]string, 0, len(inputs))
+	for name := range inputs {
+		inputNames = append(inputNames, name)
+	}
+	sort.Strings(inputNames)
+	for _, name := range inputNames {
+		args = append(args, "--field", name+"="+inputs[name])
+	}
+
 	output, err := workflow.RunGHCombined("Triggering workflow...", args...)
 
 	if err != nil {
@@ -911,7 +1026,7 @@ func saveTrialResult(filename string, result any, verbose bool) error {
 }
 
 // copyTrialResultsToHostRepo copies trial result files to the host repository and commits them
-func copyTrialResultsToHostRepo(tempDir, dateTimeID string, workflowNames []string, targetRepoSlug string, verbose bool) error {
+func copyTrialResultsToHostRepo(tempDir, dateTimeID string, workflowNames, resultNames []string, targetRepoSlug string, verbose bool) error {
 	if verbose {
 		fmt.Fprintln(os.Stderr, console.FormatInfoMessage("Copying trial results to host repository"))
 	}
@@ -924,9 +1039,9 @@ func copyTrialResultsToHostRepo(tempDir, dateTimeID string, workflowNames []stri
 
 	// Copy individual workflow result files
 	sanitizedTargetRepo := repoutil.SanitizeForFilename(targetRepoSlug)
-	for _, workflowName := range workflowNames {
-		sourceFile := fmt.Sprintf("trials/%s-%s.%s.json", workflowName, sanitizedTargetRepo, dateTimeID)
-		destFile := filepath.Join(trialsDir, fmt.Sprintf("%s-%s.%s.json", workflowName, sanitizedTargetRepo, dateTimeID))
+	for _, resultName := range resultNames {
+		sourceFile := fmt.Sprintf("trials/%s-%s.%s.json", resultName, sanitizedTargetRepo, dateTimeID)
+		destFile := filepath.Join(trialsDir, fmt.Sprintf("%s-%s.%s.json", resultName, sanitizedTargetRepo, dateTimeID))
 
 		if err := fileutil.CopyFile(sourceFile, destFile); err != nil {
 			if verbose {
@@ -0,0 +1,586 @@
+package cli
+
+import (
+	"encoding/base64"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/github/gh-aw/pkg/console"
+	"github.com/github/gh-aw/pkg/logger"
+	"github.com/github/gh-aw/pkg/workflow"
+	"github.com/goccy/go-yaml"
+)
+
+var trialEvalLog = logger.New("cli:trial_eval")
+
+// EvalSpec describes the scenarios a workflow is evaluated against with `trial --eval`
+type EvalSpec struct {
+	Workflow  string         `yaml:"workflow,omitempty"`
+	Scenarios []EvalScenario `yaml:"scenarios"`
+}
+
+// EvalScenario is a single way of triggering a workflow together with the assertions its safe outputs must satisfy
+type EvalScenario struct {
+	Name           string            `yaml:"name"`
+	TriggerContext string            `yaml:"trigger-context,omitempty"`
+	Inputs         map[string]string `yaml:"inputs,omitempty"`
+	Fixture        *EvalFixture      `yaml:"fixture,omitempty"`
+	Assertions     []EvalAssertion   `yaml:"assertions"`
+}
+
+// EvalFixture is an issue or pull request created in the host repository before the scenario runs.
+// Its number is used as the trigger context of the run.
+type EvalFixture struct {
+	Issue       *EvalIssueFixture       `yaml:"issue,omitempty"`
+	PullRequest *EvalPullRequestFixture `yaml:"pull-request,omitempty"`
+}
+
+// EvalIssueFixture is an issue created in the host repository
+type EvalIssueFixture struct {
+	Title  string   `yaml:"title"`
+	Body   string   `yaml:"body,omitempty"`
+	Labels []string `yaml:"labels,omitempty"`
+}
+
+// EvalPullRequestFixture is a pull request created in the host repository from a branch
+// containing the given files
+type EvalPullRequestFixture struct {
+	Title string            `yaml:"title"`
+	Body  string            `yaml:"body,omitempty"`
+	Base  string            `yaml:"base,omitempty"`
+	Files map[string]string `yaml:"files"`
+}
+
+// EvalAssertion checks the number of safe output items of a type that match optional filters.
+// Without count, min or max the assertion requires at least one matching item.
+type EvalAssertion struct {
+	Type         string   `yaml:"type"`
+	Count        *int     `yaml:"count,omitempty"`
+	Min          *int     `yaml:"min,omitempty"`
+	Max          *int     `yaml:"max,omitempty"`
+	Labels       []string `yaml:"labels,omitempty"`
+	TitleMatches string   `yaml:"title-matches,omitempty"`
+	BodyMatches  string   `yaml:"body-matches,omitempty"`
+}
+
+// EvalAssertionResult is the outcome of one assertion for one run
+type EvalAssertionResult struct {
+	Assertion string `json:"assertion"`
+	Passed    bool   `json:"passed"`
+	Message   string `json:"message,omitempty"`
+}
+
+// EvalScenarioRun is the outcome of one run of a scenario
+type EvalScenarioRun struct {
+	Repeat     int                   `json:"repeat"`
+	RunID      string                `json:"run_id"`
+	Passed     bool                  `json:"passed"`
+	Duration   time.Duration         `json:"duration"`
+	Assertions []EvalAssertionResult `json:"assertions"`
+}
+
+// EvalScenarioReport collects the runs of a scenario across repeats
+type EvalScenarioReport struct {
+	Name string            `json:"name"`
+	Runs []EvalScenarioRun `json:"runs"`
+}
+
+// EvalReport collects the outcome of all scenarios of an eval spec
+type EvalReport struct {
+	Workflow  string               `json:"workflow"`
+	Spec      string               `json:"spec"`
+	Timestamp time.Time            `json:"timestamp"`
+	Scenarios []EvalScenarioReport `json:"scenarios"`
+}
+
+// EvalScorecardRow is a row of the console summary of an eval report
+type EvalScorecardRow struct {
+	Scenario string `console:"header:Scenario"`
+	Runs     int    `console:"header:Runs"`
+	Passed   int    `console:"header:Passed"`
+	PassRate string `console:"header:Pass Rate"`
+}
+
+// LoadEvalSpec reads and validates an eval spec file
+func LoadEvalSpec(path string) (*EvalSpec, error) {
+	trialEvalLog.Printf("Loading eval spec: %s", path)
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read eval spec: %w", err)
+	}
+	var spec EvalSpec
+	if err := yaml.UnmarshalWithOptions(content, &spec, yaml.Strict()); err != nil {
+		return nil, fmt.Errorf("failed to parse eval spec %s: %w", path, err)
+	}
+	if err := spec.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid eval spec %s: %w", path, err)
+	}
+	return &spec, nil
+}
+
+// Validate checks that the spec has uniquely named scenarios with well-formed fixtures and assertions
+func (s *EvalSpec) Validate() error {
+	if len(s.Scenarios) == 0 {
+		return errors.New("at least one scenario is required")
+	}
+	seen := make(map[string]bool)
+	for i, scenario := range s.Scenarios {
+		if strings.TrimSpace(scenario.Name) == "" {
+			return fmt.Errorf("scenario %d has no name", i+1)
+		}
+		if seen[scenario.Name] {
+			return fmt.Errorf("scenario '%s' is defined more than once", scenario.Name)
+		}
+		seen[scenario.Name] = true
+
+		if fixture := scenario.Fixture; fixture != nil {
+			if (fixture.Issue == nil) == (fixture.PullRequest == nil) {
+				return fmt.Errorf("scenario '%s': fixture must define exactly one of issue or pull-request", scenario.Name)
+			}
+			if scenario.TriggerContext != "" {
+				return fmt.Errorf("scenario '%s': fixture and trigger-context cannot be used together", scenario.Name)
+			}
+			if fixture.Issue != nil && fixture.Issue.Title == "" {
+				return fmt.Errorf("scenario '%s': issue fixture requires a title", scenario.Name)
+			}
+			if pr := fixture.PullRequest; pr != nil && (pr.Title == "" || len(pr.Files) == 0) {
+				return fmt.Errorf("scenario '%s': pull-request fixture requires a title and at least one file", scenario.Name)
+			}
+		}
+
+		if len(scenario.Assertions) == 0 {
+			return fmt.Errorf("scenario '%s' has no assertions", scenario.Name)
+		}
+		for _, assertion := range scenario.Assertions {
+			if err := assertion.validate(); err != nil {
+				return fmt.Errorf("scenario '%s': %w", scenario.Name, err)
+			}
+		}
+	}
+	return nil
+}
+
+func (a EvalAssertion) validate() error {
+	if a.Type == "" {
+		return errors.New("assertion requires a type")
+	}
+	if a.Count != nil && (a.Min != nil || a.Max != nil) {
+		return fmt.Errorf("assertion on %s: count cannot be combined with min or max", a.Type)
+	}
+	for _, bound := range []*int{a.Count, a.Min, a.Max} {
+		if bound != nil && *bound < 0 {
+			return fmt.Errorf("assertion on %s: counts cannot be negative", a.Type)
+		}
+	}
+	if a.Min != nil && a.Max != nil && *a.Min > *a.Max {
+		return fmt.Errorf("assertion on %s: min is greater than max", a.Type)
+	}
+	for _, pattern := range []string{a.TitleMatches, a.BodyMatches} {
+		if _, err := regexp.Compile(pattern); err != nil {
+			return fmt.Errorf("assertion on %s: invalid pattern '%s': %w", a.Type, pattern, err)
+		}
+	}
+	return nil
+}
+
+// bounds returns the inclusive range of matching items the assertion accepts (max -1 means unbounded)
+func (a EvalAssertion) bounds() (int, int) {
+	switch {
+	case a.Count != nil:
+		return *a.Count, *a.Count
+	case a.Min != nil && a.Max != nil:
+		return *a.Min, *a.Max
+	case a.Min != nil:
+		return *a.Min, -1
+	case a.Max != nil:
+		return 0, *a.Max
+	default:
+		return 1, -1
+	}
+}
+
+// String describes the assertion, e.g. "exactly 1 create_issue with labels [bug]"
+func (a EvalAssertion) String() string {
+	minCount, maxCount := a.bounds()
+	var quantity string
+	switch {
+	case minCount == maxCount && minCount == 0:
+		quantity = "no"
+	case minCount == maxCount:
+		quantity = fmt.Sprintf("exactly %d", minCount)
+	case maxCount == -1:
+		quantity = fmt.Sprintf("at least %d", minCount)
+	case minCount == 0:
+		quantity = fmt.Sprintf("at most %d", maxCount)
+	default:
+		quantity = fmt.Sprintf("between %d and %d", minCount, maxCount)
+	}
+
+	var filters []string
+	if len(a.Labels) > 0 {
+		filters = append(filters, fmt.Sprintf("labels [%s]", strings.Join(a.Labels, ", ")))
+	}
+	if a.TitleMatches != "" {
+		filters = append(filters, fmt.Sprintf("title matching /%s/", a.TitleMatches))
+	}
+	if a.BodyMatches != "" {
+		filters = append(filters, fmt.Sprintf("body matching /%s/", a.BodyMatches))
+	}
+
+	description := quantity + " " + normalizeSafeOutputType(a.Type)
+	if len(filters) > 0 {
+		description += " with " + strings.Join(filters, " and ")
+	}
+	return description
+}
+
+// Evaluate checks the assertion against the safe output items of a run
+func (a EvalAssertion) Evaluate(items []map[string]any) EvalAssertionResult {
+	outputType := normalizeSafeOutputType(a.Type)
+	titlePattern := regexp.MustCompile(a.TitleMatches)
+	bodyPattern := regexp.MustCompile(a.BodyMatches)
+
+	matched := 0
+	for _, item := range items {
+		if itemType, _ := item["type"].(string); normalizeSafeOutputType(itemType) != outputType {
+			continue
+		}
+		if !hasAllLabels(item["labels"], a.Labels) {
+			continue
+		}
+		if title, _ := item["title"].(string); a.TitleMatches != "" && !titlePattern.MatchString(title) {
+			continue
+		}
+		if body, _ := item["body"].(string); a.BodyMatches != "" && !bodyPattern.MatchString(body) {
+			continue
+		}
+		matched++
+	}
+
+	result := EvalAssertionResult{Assertion: a.String()}
+	minCount, maxCount := a.bounds()
+	result.Passed = matched >= minCount && (maxCount == -1 || matched <= maxCount)
+	if !result.Passed {
+		result.Message = fmt.Sprintf("expected %s, got %d", result.Assertion, matched)
+	}
+	return result
+}
+
+// EvaluateScenario checks every assertion of a scenario against the safe outputs of a trial run
+func EvaluateScenario(scenario EvalScenario, result WorkflowTrialResult, repeat int, duration time.Duration) EvalScenarioRun {
+	items := safeOutputItems(result.SafeOutputs)
+	run := EvalScenarioRun{Repeat: repeat, RunID: result.RunID, Passed: true, Duration: duration}
+	for _, assertion := range scenario.Assertions {
+		assertionResult := assertion.Evaluate(items)
+		run.Passed = run.Passed && assertionResult.Passed
+		run.Assertions = append(run.Assertions, assertionResult)
+	}
+	trialEvalLog.Printf("Evaluated scenario %s (repeat %d): passed=%v", scenario.Name, repeat, run.Passed)
+	return run
+}
+
+// AddRun records the outcome of a scenario run in the report
+func (r *EvalReport) AddRun(scenario string, run EvalScenarioRun) {
+	for i := range r.Scenarios {
+		if r.Scenarios[i].Name == scenario {
+			r.Scenarios[i].Runs = append(r.Scenarios[i].Runs, run)
+			return
+		}
+	}
+	r.Scenarios = append(r.Scenarios, EvalScenarioReport{Name: scenario, Runs: []EvalScenarioRun{run}})
+}
+
+// Passed returns the number of runs of the scenario in which all assertions held
+func (s EvalScenarioReport) Passed() int {
+	passed := 0
+	for _, run := range s.Runs {
+		if run.Passed {
+			passed++
+		}
+	}
+	return passed
+}
+
+// PassRate returns the fraction of runs of the scenario in which all assertions held
+func (s EvalScenarioReport) PassRate() float64 {
+	if len(s.Runs) == 0 {
+		return 0
+	}
+	return float64(s.Passed()) / float64(len(s.Runs))
+}
+
+// ScorecardRows summarizes the pass rate of each scenario for console output
+func (r *EvalReport) ScorecardRows() []EvalScorecardRow {
+	rows := make([]EvalScorecardRow, 0, len(r.Scenarios))
+	for _, scenario := range r.Scenarios {
+		rows = append(rows, EvalScorecardRow{
+			Scenario: scenario.Name,
+			Runs:     len(scenario.Runs),
+			Passed:   scenario.Passed(),
+			PassRate: formatPassRate(scenario.PassRate()),
+		})
+	}
+	return rows
+}
+
+// RenderMarkdown renders the report as a markdown scorecard with per-scenario and per-assertion pass rates
+func (r *EvalReport) RenderMarkdown() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "# Eval scorecard: %s\n\n", r.Workflow)
+	fmt.Fprintf(&sb, "- Spec: `%s`\n", r.Spec)
+	fmt.Fprintf(&sb, "- Date: %s\n\n", r.Timestamp.UTC().Format(time.RFC3339))
+
+	sb.WriteString("| Scenario | Runs | Passed | Pass rate |\n")
+	sb.WriteString("|----------|------|--------|-----------|\n")
+	for _, scenario := range r.Scenarios {
+		fmt.Fprintf(&sb, "| %s | %d | %d | %s |\n", scenario.Name, len(scenario.Runs), scenario.Passed(), formatPassRate(scenario.PassRate()))
+	}
+
+	for _, scenario := range r.Scenarios {
+		fmt.Fprintf(&sb, "\n## %s\n\n", scenario.Name)
+		sb.WriteString("| Assertion | Pass rate |\n")
+		sb.WriteString("|-----------|-----------|\n")
+		// Runs that failed before producing outputs only record a "workflow run succeeds" assertion,
+		// so pass rates are aggregated by assertion rather than by position
+		var assertions []string
+		passed := make(map[string]int)
+		for _, run := range scenario.Runs {
+			for _, assertion := range run.Assertions {
+				if _, seen := passed[assertion.Assertion]; !seen {
+					assertions = append(assertions, assertion.Assertion)
+					passed[assertion.Assertion] = 0
+				}
+				if assertion.Passed {
+					passed[assertion.Assertion]++
+				}
+			}
+		}
+		for _, assertion := range assertions {
+			fmt.Fprintf(&sb, "| %s | %s |\n", strings.ReplaceAll(assertion, "|", "\\|"), formatPassRate(float64(passed[assertion])/float64(len(scenario.Runs))))
+		}
+
+		var failures []string
+		for _, run := range scenario.Runs {
+			for _, assertion := range run.Assertions {
+				if !assertion.Passed {
+					failures = append(failures, fmt.Sprintf("- Run %d (%s): %s", run.Repeat, run.RunID, assertion.Message))
+				}
+			}
+		}
+		if len(failures) > 0 {
+			sb.WriteString("\nFailures:\n\n")
+			sb.WriteString(strings.Join(failures, "\n"))
+			sb.WriteString("\n")
+		}
+	}
+	return sb.String()
+}
+
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Timestamp string          `xml:"timestamp,attr"`
+	Cases     []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Content string `xml:",chardata"`
+}
+
+// RenderJUnit renders the report as JUnit XML with one test suite per scenario and one test case per run
+func (r *EvalReport) RenderJUnit() ([]byte, error) {
+	suites := junitTestSuites{Name: r.Workflow}
+	for _, scenario := range r.Scenarios {
+		suite := junitTestSuite{
+			Name:      scenario.Name,
+			Tests:     len(scenario.Runs),
+			Failures:  len(scenario.Runs) - scenario.Passed(),
+			Timestamp: r.Timestamp.UTC().Format(time.RFC3339),
+		}
+		for _, run := range scenario.Runs {
+			testCase := junitTestCase{
+				Name:      fmt.Sprintf("%s #%d", scenario.Name, run.Repeat),
+				ClassName: r.Workflow,
+				Time:      fmt.Sprintf("%.3f", run.Duration.Seconds()),
+			}
+			if !run.Passed {
+				var messages []string
+				for _, assertion := range run.Assertions {
+					if !assertion.Passed {
+						messages = append(messages, assertion.Message)
+					}
+				}
+				testCase.Failure = &junitFailure{
+					Message: fmt.Sprintf("%d of %d assertions failed (run %s)", len(messages), len(run.Assertions), run.RunID),
+					Content: strings.Join(messages, "\n"),
+				}
+			}
+			suite.Cases = append(suite.Cases, testCase)
+		}
+		suites.Tests += suite.Tests
+		suites.Failures += suite.Failures
+		suites.Suites = append(suites.Suites, suite)
+	}
+
+	data, err := xml.MarshalIndent(suites, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal JUnit report: %w", err)
+	}
+	return append([]byte(xml.Header), append(data, '\n')...), nil
+}
+
+// writeEvalReports writes the JUnit XML and markdown scorecard of a report to the trials directory
+func writeEvalReports(report *EvalReport, dir string, verbose bool) (string, string, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", "", fmt.Errorf("failed to create trials directory: %w", err)
+	}
+	stem := filepath.Join(dir, fmt.Sprintf("%s.eval.%s", report.Workflow, report.Timestamp.Format("20060102-150405")))
+
+	junit, err := report.RenderJUnit()
+	if err != nil {
+		return "", "", err
+	}
+	junitPath := stem + ".junit.xml"
+	if err := os.WriteFile(junitPath, junit, 0644); err != nil {
+		return "", "", fmt.Errorf("failed to write JUnit report: %w", err)
+	}
+
+	scorecardPath := stem + ".md"
+	if err := os.WriteFile(scorecardPath, []byte(report.RenderMarkdown()), 0644); err != nil {
+		return "", "", fmt.Errorf("failed to write eval scorecard: %w", err)
+	}
+
+	if verbose {
+		fmt.Fprintln(os.Stderr, console.FormatVerboseMessage(fmt.Sprintf("Wrote eval reports %s and %s", junitPath, scorecardPath)))
+	}
+	return junitPath, scorecardPath, nil
+}
+
+// createEvalFixture creates the issue or pull request fixture of a scenario in the host repository
+// and returns a trigger context referencing it
+func createEvalFixture(hostRepoSlug string, scenario EvalScenario, verbose bool) (string, error) {
+	fixture := scenario.Fixture
+	if fixture.Issue != nil {
+		args := []string{"issue", "create", "--repo", hostRepoSlug, "--title", fixture.Issue.Title, "--body", fixture.Issue.Body}
+		for _, label := range fixture.Issue.Labels {
+			// Labels must exist before they can be applied to an issue
+			if output, err := workflow.RunGHCombined("Creating label...", "label", "create", label, "--repo", hostRepoSlug, "--force"); err != nil {
+				return "", fmt.Errorf("failed to create label '%s': %w (output: %s)", label, err, string(output))
+			}
+			args = append(args, "--label", label)
+		}
+		output, err := workflow.RunGHCombined("Creating issue fixture...", args...)
+		if err != nil {
+			return "", fmt.Errorf("failed to create issue fixture: %w (output: %s)", err, string(output))
+		}
+		return fixtureReference(string(output), verbose)
+	}
+
+	pr := fixture.PullRequest
+	base := pr.Base
+	if base == "" {
+		output, err := workflow.RunGH("Resolving default branch...", "repo", "view", hostRepoSlug, "--json", "defaultBranchRef", "--jq", ".defaultBranchRef.name")
+		if err != nil {
+			return "", fmt.Errorf("failed to resolve default branch: %w", err)
+		}
+		base = strings.TrimSpace(string(output))
+	}
+	baseSHA, err := workflow.RunGH("Resolving base commit...", "api", fmt.Sprintf("repos/%s/git/ref/heads/%s", hostRepoSlug, base), "--jq", ".object.sha")
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve base branch '%s': %w", base, err)
+	}
+
+	branch := fmt.Sprintf("eval/%s-%d", sanitizeBranchName(scenario.Name), time.Now().Unix())
+	if output, err := workflow.RunGHCombined("Creating fixture branch...", "api", "-X", "POST", fmt.Sprintf("repos/%s/git/refs", hostRepoSlug),
+		"-f", "ref=refs/heads/"+branch, "-f", "sha="+strings.TrimSpace(string(baseSHA))); err != nil {
+		return "", fmt.Errorf("failed to create fixture branch: %w (output: %s)", err, string(output))
+	}
+
+	paths := make([]string, 0, len(pr.Files))
+	for path := range pr.Files {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		content := base64.StdEncoding.EncodeToString([]byte(pr.Files[path]))
+		if output, err := workflow.RunGHCombined("Committing fixture file...", "api", "-X", "PUT", fmt.Sprintf("repos/%s/contents/%s", hostRepoSlug, path),
+			"-f", "message=Add eval fixture "+path, "-f", "content="+content, "-f", "branch="+branch); err != nil {
+			return "", fmt.Errorf("failed to commit fixture file '%s': %w (output: %s)", path, err, string(output))
+		}
+	}
+
+	output, err := workflow.RunGHCombined("Creating pull request fixture...", "pr", "create", "--repo", hostRepoSlug,
+		"--base", base, "--head", branch, "--title", pr.Title, "--body", pr.Body)
+	if err != nil {
+		return "", fmt.Errorf("failed to create pull request fixture: %w (output: %s)", err, string(output))
+	}
+	return fixtureReference(string(output), verbose)
+}
+
+var fixtureURLPattern = regexp.MustCompile(`/(?:issues|pull)/(\d+)`)
+
+// fixtureReference extracts the number from the URL printed by gh when creating an issue or pull request
+func fixtureReference(output string, verbose bool) (string, error) {
+	matches := fixtureURLPattern.FindStringSubmatch(output)
+	if len(matches) < 2 {
+		return "", fmt.Errorf("could not find the fixture number in: %s", strings.TrimSpace(output))
+	}
+	if verbose {
+		fmt.Fprintln(os.Stderr, console.FormatVerboseMessage("Created fixture #"+matches[1]))
+	}
+	return "#" + matches[1], nil
+}
+
+// safeOutputItems returns the items of an agent output artifact
+func safeOutputItems(safeOutputs map[string]any) []map[string]any {
+	rawItems, _ := safeOutputs["items"].([]any)
+	items := make([]map[string]any, 0, len(rawItems))
+	for _, raw := range rawItems {
+		if item, ok := raw.(map[string]any); ok {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+func hasAllLabels(rawLabels any, required []string) bool {
+	labels, _ := rawLabels.([]any)
+	for _, label := range required {
+		if !slices.ContainsFunc(labels, func(value any) bool {
+			s, _ := value.(string)
+			return strings.EqualFold(s, label)
+		}) {
+			return false
+		}
+	}
+	return true
+}
+
+func formatPassRate(rate float64) string {
+	return fmt.Sprintf("%.0f%%", rate*100)
+}
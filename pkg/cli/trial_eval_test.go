@@ -0,0 +1,232 @@
+//go:build !integration
+
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func intPtr(v int) *int { return &v }
+
+func TestLoadEvalSpec(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{
+			name: "valid spec",
+			content: `workflow: issue-triage
+scenarios:
+  - name: bug report
+    fixture:
+      issue:
+        title: App crashes on start
+        labels: [needs-triage]
+    assertions:
+      - type: add-labels
+        labels: [bug]
+      - type: create-pull-request
+        count: 0
+`,
+		},
+		{
+			name:    "no scenarios",
+			content: "workflow: issue-triage\n",
+			wantErr: "at least one scenario is required",
+		},
+		{
+			name: "unknown field",
+			content: `scenarios:
+  - name: a
+    asserts: []
+`,
+			wantErr: "failed to parse eval spec",
+		},
+		{
+			name: "duplicate scenario",
+			content: `scenarios:
+  - name: a
+    assertions: [{type: create-issue}]
+  - name: a
+    assertions: [{type: create-issue}]
+`,
+			wantErr: "scenario 'a' is defined more than once",
+		},
+		{
+			name: "fixture with trigger context",
+			content: `scenarios:
+  - name: a
+    trigger-context: "#1"
+    fixture:
+      issue: {title: t}
+    assertions: [{type: create-issue}]
+`,
+			wantErr: "fixture and trigger-context cannot be used together",
+		},
+		{
+			name: "count with min",
+			content: `scenarios:
+  - name: a
+    assertions: [{type: create-issue, count: 1, min: 1}]
+`,
+			wantErr: "count cannot be combined with min or max",
+		},
+		{
+			name: "invalid regex",
+			content: `scenarios:
+  - name: a
+    assertions: [{type: add-comment, body-matches: "("}]
+`,
+			wantErr: "invalid pattern",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "eval.yml")
+			require.NoError(t, os.WriteFile(path, []byte(tt.content), 0644))
+
+			spec, err := LoadEvalSpec(path)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "issue-triage", spec.Workflow)
+			require.Len(t, spec.Scenarios, 1)
+			assert.Equal(t, []string{"needs-triage"}, spec.Scenarios[0].Fixture.Issue.Labels)
+		})
+	}
+}
+
+func TestEvalAssertionEvaluate(t *testing.T) {
+	items := []map[string]any{
+		{"type": "create_issue", "title": "Crash on start", "body": "Steps to reproduce", "labels": []any{"bug", "p1"}},
+		{"type": "create_issue", "title": "Docs typo", "body": "Fix spelling", "labels": []any{"docs"}},
+		{"type": "add_comment", "body": "Thanks for the report! Triaged as bug."},
+	}
+
+	tests := []struct {
+		name        string
+		assertion   EvalAssertion
+		wantPassed  bool
+		description string
+	}{
+		{
+			name:        "exactly one issue with label",
+			assertion:   EvalAssertion{Type: "create-issue", Count: intPtr(1), Labels: []string{"bug"}},
+			wantPassed:  true,
+			description: "exactly 1 create_issue with labels [bug]",
+		},
+		{
+			name:        "exactly one issue fails with two issues",
+			assertion:   EvalAssertion{Type: "create_issue", Count: intPtr(1)},
+			wantPassed:  false,
+			description: "exactly 1 create_issue",
+		},
+		{
+			name:        "comment matches regex",
+			assertion:   EvalAssertion{Type: "add-comment", BodyMatches: `(?i)^thanks`},
+			wantPassed:  true,
+			description: "at least 1 add_comment with body matching /(?i)^thanks/",
+		},
+		{
+			name:        "no pull request created",
+			assertion:   EvalAssertion{Type: "create-pull-request", Count: intPtr(0)},
+			wantPassed:  true,
+			description: "no create_pull_request",
+		},
+		{
+			name:        "title filter with max zero",
+			assertion:   EvalAssertion{Type: "create-issue", TitleMatches: "Docs", Max: intPtr(0)},
+			wantPassed:  false,
+			description: "no create_issue with title matching /Docs/",
+		},
+		{
+			name:        "min and max range",
+			assertion:   EvalAssertion{Type: "create-issue", Min: intPtr(1), Max: intPtr(2)},
+			wantPassed:  true,
+			description: "between 1 and 2 create_issue",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := tt.assertion.Evaluate(items)
+			assert.Equal(t, tt.wantPassed, result.Passed)
+			assert.Equal(t, tt.description, result.Assertion)
+			if tt.wantPassed {
+				assert.Empty(t, result.Message)
+			} else {
+				assert.Contains(t, result.Message, "expected "+tt.description)
+			}
+		})
+	}
+}
+
+func TestEvalReportRendering(t *testing.T) {
+	scenario := EvalScenario{
+		Name: "bug report",
+		Assertions: []EvalAssertion{
+			{Type: "create-issue", Count: intPtr(1), Labels: []string{"bug"}},
+			{Type: "create-pull-request", Count: intPtr(0)},
+		},
+	}
+	passing := WorkflowTrialResult{RunID: "101", SafeOutputs: map[string]any{
+		"items": []any{map[string]any{"type": "create_issue", "labels": []any{"bug"}}},
+	}}
+	failing := WorkflowTrialResult{RunID: "102", SafeOutputs: map[string]any{
+		"items": []any{map[string]any{"type": "create_pull_request"}},
+	}}
+
+	report := &EvalReport{Workflow: "issue-triage", Spec: "evals/issue-triage.yml", Timestamp: time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)}
+	report.AddRun(scenario.Name, EvaluateScenario(scenario, passing, 1, 2*time.Second))
+	report.AddRun(scenario.Name, EvaluateScenario(scenario, failing, 2, time.Second))
+
+	require.Len(t, report.Scenarios, 1)
+	assert.Equal(t, 1, report.Scenarios[0].Passed())
+	assert.InDelta(t, 0.5, report.Scenarios[0].PassRate(), 0.001)
+	assert.Equal(t, []EvalScorecardRow{{Scenario: "bug report", Runs: 2, Passed: 1, PassRate: "50%"}}, report.ScorecardRows())
+
+	junit, err := report.RenderJUnit()
+	require.NoError(t, err)
+	xmlOutput := string(junit)
+	assert.True(t, strings.HasPrefix(xmlOutput, "<?xml"))
+	assert.Contains(t, xmlOutput, `<testsuites name="issue-triage" tests="2" failures="1">`)
+	assert.Contains(t, xmlOutput, `<testcase name="bug report #1" classname="issue-triage" time="2.000"></testcase>`)
+	assert.Contains(t, xmlOutput, `<failure message="2 of 2 assertions failed (run 102)">`)
+
+	markdown := report.RenderMarkdown()
+	assert.Contains(t, markdown, "| bug report | 2 | 1 | 50% |")
+	assert.Contains(t, markdown, "| exactly 1 create_issue with labels [bug] | 50% |")
+	assert.Contains(t, markdown, "| no create_pull_request | 50% |")
+	assert.Contains(t, markdown, "- Run 2 (102): expected no create_pull_request, got 1")
+
+	dir := t.TempDir()
+	junitPath, scorecardPath, err := writeEvalReports(report, dir, false)
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, "issue-triage.eval.20260102-030405.junit.xml"), junitPath)
+	assert.FileExists(t, scorecardPath)
+}
+
+func TestFixtureReference(t *testing.T) {
+	ref, err := fixtureReference("https://github.com/octo/trial/pull/42\n", false)
+	require.NoError(t, err)
+	assert.Equal(t, "#42", ref)
+
+	ref, err = fixtureReference("https://github.com/octo/trial/issues/7", false)
+	require.NoError(t, err)
+	assert.Equal(t, "#7", ref)
+
+	_, err = fixtureReference("no url", false)
+	assert.Error(t, err)
+}
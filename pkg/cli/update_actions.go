@@ -49,14 +49,9 @@ func UpdateActions(allowMajor, verbose bool) error {
 	}
 
 	// Load the current actions lock file
-	data, err := os.ReadFile(actionsLockPath)
+	actionsLock, err := readActionsLock(actionsLockPath)
 	if err != nil {
-		return fmt.Errorf("failed to read actions lock file: %w", err)
-	}
-
-	var actionsLock actionsLockFile
-	if err := json.Unmarshal(data, &actionsLock); err != nil {
-		return fmt.Errorf("failed to parse actions lock file: %w", err)
+		return err
 	}
 
 	updateLog.Printf("Loaded %d action entries from actions-lock.json", len(actionsLock.Entries))
@@ -133,17 +128,8 @@ func UpdateActions(allowMajor, verbose bool) error {
 
 	// Save the updated actions lock file if there were any updates
 	if len(updatedActions) > 0 {
-		// Marshal with sorted keys and pretty printing
-		updatedData, err := marshalActionsLockSorted(&actionsLock)
-		if err != nil {
-			return fmt.Errorf("failed to marshal updated actions lock: %w", err)
-		}
-
-		// Add trailing newline for prettier compliance
-		updatedData = append(updatedData, '\n')
-
-		if err := os.WriteFile(actionsLockPath, updatedData, 0644); err != nil {
-			return fmt.Errorf("failed to write updated actions lock file: %w", err)
+		if err := writeActionsLock(actionsLockPath, actionsLock); err != nil {
+			return err
 		}
 
 		updateLog.Printf("Successfully wrote updated actions-lock.json with %d updates", len(updatedActions))
@@ -184,73 +170,9 @@ func getLatestActionRelease(repo, currentVersion string, allowMajor, verbose boo
 		return "", "", errors.New("no releases found")
 	}
 
-	// Parse current version
-	currentVer := parseVersion(currentVersion)
-
-	// Find all valid semantic version releases and sort by semver
-	type releaseWithVersion struct {
-		tag     string
-		version *semanticVersion
-	}
-	var validReleases []releaseWithVersion
-	for _, release := range releases {
-		releaseVer := parseVersion(release)
-		if releaseVer != nil {
-			validReleases = append(validReleases, releaseWithVersion{
-				tag:     release,
-				version: releaseVer,
-			})
-		}
-	}
-
-	if len(validReleases) == 0 {
-		return "", "", errors.New("no valid semantic version releases found")
-	}
-
-	// Sort releases by semver in descending order (highest first)
-	sort.Slice(validReleases, func(i, j int) bool {
-		return validReleases[i].version.isNewer(validReleases[j].version)
-	})
-
-	// If current version is not valid, return the highest semver release
-	if currentVer == nil {
-		latestRelease := validReleases[0].tag
-		sha, err := getActionSHAForTag(baseRepo, latestRelease)
-		if err != nil {
-			return "", "", fmt.Errorf("failed to get SHA for %s: %w", latestRelease, err)
-		}
-		return latestRelease, sha, nil
-	}
-
-	// Find the highest compatible release (respecting major version if !allowMajor)
-	var latestCompatible string
-	var latestCompatibleVersion *semanticVersion
-
-	for _, rel := range validReleases {
-		// Check if compatible based on major version
-		if !allowMajor && rel.version.major != currentVer.major {
-			continue
-		}
-
-		// Since releases are sorted by semver descending, first match is highest
-		if latestCompatibleVersion == nil || rel.version.isNewer(latestCompatibleVersion) {
-			latestCompatible = rel.tag
-			latestCompatibleVersion = rel.version
-		} else if !rel.version.isNewer(latestCompatibleVersion) &&
-			rel.version.major == latestCompatibleVersion.major &&
-			rel.version.minor == latestCompatibleVersion.minor &&
-			rel.version.patch == latestCompatibleVersion.patch {
-			// If versions are equal, prefer the less precise one (e.g., "v8" over "v8.0.0")
-			// This follows GitHub Actions convention of using major version tags
-			if !rel.version.isPreciseVersion() && latestCompatibleVersion.isPreciseVersion() {
-				latestCompatible = rel.tag
-				latestCompatibleVersion = rel.version
-			}
-		}
-	}
-
-	if latestCompatible == "" {
-		return "", "", errors.New("no compatible release found")
+	latestCompatible, err := selectLatestCompatibleRelease(releases, currentVersion, allowMajor)
+	if err != nil {
+		return "", "", err
 	}
 
 	// Get the SHA for the latest compatible release
@@ -306,6 +228,23 @@ func getLatestActionReleaseViaGit(repo, currentVersion string, allowMajor, verbo
 		return "", "", errors.New("no releases found")
 	}
 
+	latestCompatible, err := selectLatestCompatibleRelease(releases, currentVersion, allowMajor)
+	if err != nil {
+		return "", "", err
+	}
+
+	sha := tagToSHA[latestCompatible]
+	if verbose {
+		fmt.Fprintln(os.Stderr, console.FormatVerboseMessage(fmt.Sprintf("Latest compatible release: %s (via git)", latestCompatible)))
+	}
+
+	return latestCompatible, sha, nil
+}
+
+// selectLatestCompatibleRelease returns the highest semantic version tag, respecting the
+// major version of currentVersion unless allowMajor is set. When currentVersion is not a
+// semantic version, the highest release is returned.
+func selectLatestCompatibleRelease(tags []string, currentVersion string, allowMajor bool) (string, error) {
 	// Parse current version
 	currentVer := parseVersion(currentVersion)
 
@@ -315,7 +254,7 @@ func getLatestActionReleaseViaGit(repo, currentVersion string, allowMajor, verbo
 		version *semanticVersion
 	}
 	var validReleases []releaseWithVersion
-	for _, release := range releases {
+	for _, release := range tags {
 		releaseVer := parseVersion(release)
 		if releaseVer != nil {
 			validReleases = append(validReleases, releaseWithVersion{
@@ -326,7 +265,7 @@ func getLatestActionReleaseViaGit(repo, currentVersion string, allowMajor, verbo
 	}
 
 	if len(validReleases) == 0 {
-		return "", "", errors.New("no valid semantic version releases found")
+		return "", errors.New("no valid semantic version releases found")
 	}
 
 	// Sort releases by semver in descending order (highest first)
@@ -336,12 +275,8 @@ func getLatestActionReleaseViaGit(repo, currentVersion string, allowMajor, verbo
 
 	// If current version is not valid, return the highest semver release
 	if currentVer == nil {
-		latestRelease := validReleases[0].tag
-		sha := tagToSHA[latestRelease]
-		if verbose {
-			fmt.Fprintln(os.Stderr, console.FormatVerboseMessage(fmt.Sprintf("Current version is not valid, using highest semver release: %s (via git)", latestRelease)))
-		}
-		return latestRelease, sha, nil
+		updateLog.Printf("Current version %q is not valid, using highest semver release: %s", currentVersion, validReleases[0].tag)
+		return validReleases[0].tag, nil
 	}
 
 	// Find the highest compatible release (respecting major version if !allowMajor)
@@ -372,15 +307,10 @@ func getLatestActionReleaseViaGit(repo, currentVersion string, allowMajor, verbo
 	}
 
 	if latestCompatible == "" {
-		return "", "", errors.New("no compatible release found")
+		return "", errors.New("no compatible release found")
 	}
 
-	sha := tagToSHA[latestCompatible]
-	if verbose {
-		fmt.Fprintln(os.Stderr, console.FormatVerboseMessage(fmt.Sprintf("Latest compatible release: %s (via git)", latestCompatible)))
-	}
-
-	return latestCompatible, sha, nil
+	return latestCompatible, nil
 }
 
 // getActionSHAForTag gets the commit SHA for a given tag in an action repository
@@ -406,6 +336,37 @@ func getActionSHAForTag(repo, tag string) (string, error) {
 	return sha, nil
 }
 
+// readActionsLock loads an actions-lock.json file
+func readActionsLock(actionsLockPath string) (*actionsLockFile, error) {
+	data, err := os.ReadFile(actionsLockPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read actions lock file: %w", err)
+	}
+
+	var actionsLock actionsLockFile
+	if err := json.Unmarshal(data, &actionsLock); err != nil {
+		return nil, fmt.Errorf("failed to parse actions lock file: %w", err)
+	}
+	return &actionsLock, nil
+}
+
+// writeActionsLock writes an actions-lock.json file with sorted keys
+func writeActionsLock(actionsLockPath string, actionsLock *actionsLockFile) error {
+	// Marshal with sorted keys and pretty printing
+	data, err := marshalActionsLockSorted(actionsLock)
+	if err != nil {
+		return fmt.Errorf("failed to marshal updated actions lock: %w", err)
+	}
+
+	// Add trailing newline for prettier compliance
+	data = append(data, '\n')
+
+	if err := os.WriteFile(actionsLockPath, data, 0644); err != nil {
+		return fmt.Errorf("failed to write updated actions lock file: %w", err)
+	}
+	return nil
+}
+
 // marshalActionsLockSorted marshals the actions lock with entries sorted by key
 func marshalActionsLockSorted(actionsLock *actionsLockFile) ([]byte, error) {
 	// Extract and sort the keys
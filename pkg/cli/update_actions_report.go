@@ -0,0 +1,514 @@
+// This file provides the risk report of `gh aw update actions --report`.
+//
+// For each action pinned in .github/aw/actions-lock.json, the report lists the newer
+// versions available, checks whether the pinned tag was force-moved (the tag now resolves
+// to a different SHA than the pin), collects the release notes between the pinned and the
+// target version, and compares the action.yml of both versions for new inputs, inputs that
+// receive the workflow token, and runtime changes. The result is rendered as a single
+// markdown document suitable for the body of the pull request that applies the bump.
+
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/github/gh-aw/pkg/console"
+	"github.com/github/gh-aw/pkg/workflow"
+	"github.com/goccy/go-yaml"
+)
+
+// maxReleaseNoteLength is the maximum length of the notes of a single release in the report
+const maxReleaseNoteLength = 2000
+
+// Risk levels of an action update
+const (
+	actionRiskLow    = "low"
+	actionRiskMedium = "medium"
+	actionRiskHigh   = "high"
+)
+
+var (
+	// fetchActionReleasesFunc, fetchActionMetadataFunc and resolveActionTagSHAFunc allow
+	// overriding GitHub access in tests
+	fetchActionReleasesFunc = fetchActionReleases
+	fetchActionMetadataFunc = fetchActionMetadata
+	resolveActionTagSHAFunc = getActionSHAForTag
+
+	// permissionMentionPattern matches release notes that mention permissions
+	permissionMentionPattern = regexp.MustCompile(`(?i)\bpermissions?\b`)
+)
+
+// actionRelease is a GitHub release of an action repository
+type actionRelease struct {
+	TagName    string `json:"tag_name"`
+	Body       string `json:"body"`
+	HTMLURL    string `json:"html_url"`
+	Draft      bool   `json:"draft"`
+	Prerelease bool   `json:"prerelease"`
+}
+
+// actionMetadata is the part of an action.yml compared between versions
+type actionMetadata struct {
+	Inputs map[string]actionMetadataInput `yaml:"inputs"`
+	Runs   struct {
+		Using string `yaml:"using"`
+	} `yaml:"runs"`
+}
+
+// actionMetadataInput is an input declared in action.yml
+type actionMetadataInput struct {
+	Required any    `yaml:"required"` // bool, or a string such as "true" in some actions
+	Default  string `yaml:"default"`
+}
+
+// isRequired reports whether the input is required and has no default
+func (i actionMetadataInput) isRequired() bool {
+	return fmt.Sprint(i.Required) == "true" && i.Default == ""
+}
+
+// ActionReleaseNote holds the release notes of one version between the pinned and target version
+type ActionReleaseNote struct {
+	Version string `json:"version"`
+	URL     string `json:"url,omitempty"`
+	Notes   string `json:"notes,omitempty"`
+}
+
+// ActionUpdateReport describes the update of a pinned action and its risk
+type ActionUpdateReport struct {
+	Repo               string              `json:"repo"`
+	CurrentVersion     string              `json:"current_version"`
+	CurrentSHA         string              `json:"current_sha"`
+	TargetVersion      string              `json:"target_version,omitempty"`
+	TargetSHA          string              `json:"target_sha,omitempty"`
+	NewerVersions      []string            `json:"newer_versions,omitempty"`
+	ForceMoved         bool                `json:"force_moved"`
+	MovedToSHA         string              `json:"moved_to_sha,omitempty"`
+	ReleaseNotes       []ActionReleaseNote `json:"release_notes,omitempty"`
+	AddedInputs        []string            `json:"added_inputs,omitempty"`
+	RemovedInputs      []string            `json:"removed_inputs,omitempty"`
+	NewRequiredInputs  []string            `json:"new_required_inputs,omitempty"`
+	TokenInputs        []string            `json:"token_inputs,omitempty"`
+	RuntimeChange      string              `json:"runtime_change,omitempty"`
+	PermissionMentions []string            `json:"permission_mentions,omitempty"`
+	Risk               string              `json:"risk"`
+	RiskReasons        []string            `json:"risk_reasons,omitempty"`
+	Error              string              `json:"error,omitempty"`
+}
+
+// Updated reports whether the pin changes
+func (r *ActionUpdateReport) Updated() bool {
+	return r.Error == "" && r.TargetSHA != "" && (r.TargetVersion != r.CurrentVersion || r.TargetSHA != r.CurrentSHA)
+}
+
+// fetchActionReleases lists the published releases of an action repository
+func fetchActionReleases(baseRepo string) ([]actionRelease, error) {
+	output, err := workflow.RunGH("Fetching releases...", "api", fmt.Sprintf("/repos/%s/releases?per_page=100", baseRepo))
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch releases: %w", err)
+	}
+	var releases []actionRelease
+	if err := json.Unmarshal(output, &releases); err != nil {
+		return nil, fmt.Errorf("failed to parse releases: %w", err)
+	}
+	return releases, nil
+}
+
+// fetchActionMetadata downloads and parses the action.yml of an action at a commit
+func fetchActionMetadata(repo, sha string) (*actionMetadata, error) {
+	baseRepo := extractBaseRepo(repo)
+	dir := strings.TrimPrefix(strings.TrimPrefix(repo, baseRepo), "/")
+
+	var lastErr error
+	for _, name := range []string{"action.yml", "action.yaml"} {
+		content, err := downloadWorkflowContent(baseRepo, filepath.ToSlash(filepath.Join(dir, name)), sha, false)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		var metadata actionMetadata
+		if err := yaml.Unmarshal(content, &metadata); err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", name, err)
+		}
+		return &metadata, nil
+	}
+	return nil, lastErr
+}
+
+// buildActionUpdateReport checks a pinned action for updates and assesses the risk of the bump
+func buildActionUpdateReport(entry actionsLockEntry, allowMajor bool) *ActionUpdateReport {
+	report := &ActionUpdateReport{
+		Repo:           entry.Repo,
+		CurrentVersion: entry.Version,
+		CurrentSHA:     entry.SHA,
+	}
+	baseRepo := extractBaseRepo(entry.Repo)
+	updateLog.Printf("Building update report for %s@%s", entry.Repo, entry.Version)
+
+	releases, err := fetchActionReleasesFunc(baseRepo)
+	if err != nil {
+		report.Error = err.Error()
+		return report
+	}
+	var tags []string
+	for _, release := range releases {
+		if !release.Draft && !release.Prerelease {
+			tags = append(tags, release.TagName)
+		}
+	}
+	if len(tags) == 0 {
+		report.Error = "no releases found"
+		return report
+	}
+
+	report.NewerVersions = newerReleaseTags(tags, entry.Version)
+	report.TargetVersion, err = selectLatestCompatibleRelease(tags, entry.Version, allowMajor)
+	if err != nil {
+		report.Error = err.Error()
+		return report
+	}
+	report.TargetSHA, err = resolveActionTagSHAFunc(baseRepo, report.TargetVersion)
+	if err != nil {
+		report.Error = fmt.Sprintf("failed to get SHA for %s: %v", report.TargetVersion, err)
+		return report
+	}
+
+	// A pinned tag that now resolves to a different commit was force-moved
+	if report.TargetVersion == entry.Version {
+		report.MovedToSHA = report.TargetSHA
+	} else if sha, err := resolveActionTagSHAFunc(baseRepo, entry.Version); err == nil {
+		report.MovedToSHA = sha
+	}
+	if report.MovedToSHA == entry.SHA {
+		report.MovedToSHA = ""
+	}
+	report.ForceMoved = report.MovedToSHA != ""
+
+	if report.Updated() {
+		report.ReleaseNotes = releaseNotesBetween(releases, entry.Version, report.TargetVersion)
+		for _, note := range report.ReleaseNotes {
+			if permissionMentionPattern.MatchString(note.Notes) {
+				report.PermissionMentions = append(report.PermissionMentions, note.Version)
+			}
+		}
+		compareActionMetadata(report)
+	}
+
+	assessActionUpdateRisk(report)
+	return report
+}
+
+// newerReleaseTags returns the semantic version tags newer than currentVersion, highest first
+func newerReleaseTags(tags []string, currentVersion string) []string {
+	current := parseVersion(currentVersion)
+	var newer []string
+	for _, tag := range tags {
+		if version := parseVersion(tag); version != nil && (current == nil || version.isNewer(current)) {
+			newer = append(newer, tag)
+		}
+	}
+	sort.Slice(newer, func(i, j int) bool {
+		return parseVersion(newer[i]).isNewer(parseVersion(newer[j]))
+	})
+	return newer
+}
+
+// releaseNotesBetween returns the notes of the releases after fromVersion up to and including
+// toVersion, oldest first
+func releaseNotesBetween(releases []actionRelease, fromVersion, toVersion string) []ActionReleaseNote {
+	from, to := parseVersion(fromVersion), parseVersion(toVersion)
+	if to == nil {
+		return nil
+	}
+
+	var selected []actionRelease
+	for _, release := range releases {
+		version := parseVersion(release.TagName)
+		if version == nil || release.Draft || release.Prerelease || version.isNewer(to) {
+			continue
+		}
+		if from != nil && !version.isNewer(from) {
+			continue
+		}
+		selected = append(selected, release)
+	}
+	sort.Slice(selected, func(i, j int) bool {
+		return parseVersion(selected[j].TagName).isNewer(parseVersion(selected[i].TagName))
+	})
+
+	notes := make([]ActionReleaseNote, 0, len(selected))
+	for _, release := range selected {
+		body := strings.TrimSpace(strings.ReplaceAll(release.Body, "\r\n", "\n"))
+		if len(body) > maxReleaseNoteLength {
+			body = strings.TrimSpace(body[:maxReleaseNoteLength]) + "\n\n… (truncated)"
+		}
+		notes = append(notes, ActionReleaseNote{Version: release.TagName, URL: release.HTMLURL, Notes: body})
+	}
+	return notes
+}
+
+// compareActionMetadata records the input and runtime changes between the pinned and target action.yml
+func compareActionMetadata(report *ActionUpdateReport) {
+	current, err := fetchActionMetadataFunc(report.Repo, report.CurrentSHA)
+	if err != nil {
+		updateLog.Printf("Failed to fetch action.yml of %s@%s: %v", report.Repo, report.CurrentSHA, err)
+		report.RiskReasons = append(report.RiskReasons, "action.yml could not be compared: "+err.Error())
+		return
+	}
+	target, err := fetchActionMetadataFunc(report.Repo, report.TargetSHA)
+	if err != nil {
+		updateLog.Printf("Failed to fetch action.yml of %s@%s: %v", report.Repo, report.TargetSHA, err)
+		report.RiskReasons = append(report.RiskReasons, "action.yml could not be compared: "+err.Error())
+		return
+	}
+
+	for name, input := range target.Inputs {
+		previous, existed := current.Inputs[name]
+		if !existed {
+			report.AddedInputs = append(report.AddedInputs, name)
+			if strings.Contains(input.Default, "github.token") || strings.Contains(strings.ToLower(name), "token") {
+				report.TokenInputs = append(report.TokenInputs, name)
+			}
+		}
+		if input.isRequired() && (!existed || !previous.isRequired()) {
+			report.NewRequiredInputs = append(report.NewRequiredInputs, name)
+		}
+	}
+	for name := range current.Inputs {
+		if _, exists := target.Inputs[name]; !exists {
+			report.RemovedInputs = append(report.RemovedInputs, name)
+		}
+	}
+	sort.Strings(report.AddedInputs)
+	sort.Strings(report.RemovedInputs)
+	sort.Strings(report.NewRequiredInputs)
+	sort.Strings(report.TokenInputs)
+
+	if current.Runs.Using != target.Runs.Using {
+		report.RuntimeChange = fmt.Sprintf("%s → %s", current.Runs.Using, target.Runs.Using)
+	}
+}
+
+// assessActionUpdateRisk sets the risk level of an update from its changes
+func assessActionUpdateRisk(report *ActionUpdateReport) {
+	if report.Error != "" || !report.Updated() {
+		report.Risk = actionRiskLow
+		return
+	}
+
+	var high, medium []string
+	if report.ForceMoved {
+		high = append(high, fmt.Sprintf("tag %s was force-moved from %s to %s", report.CurrentVersion, shortSHA(report.CurrentSHA), shortSHA(report.MovedToSHA)))
+	}
+	current, target := parseVersion(report.CurrentVersion), parseVersion(report.TargetVersion)
+	switch {
+	case current == nil || target == nil:
+		medium = append(medium, fmt.Sprintf("non-semantic version change (%s → %s)", report.CurrentVersion, report.TargetVersion))
+	case target.major != current.major:
+		high = append(high, fmt.Sprintf("major version bump (%s → %s)", report.CurrentVersion, report.TargetVersion))
+	case target.minor != current.minor:
+		medium = append(medium, fmt.Sprintf("minor version bump (%s → %s)", report.CurrentVersion, report.TargetVersion))
+	}
+	if len(report.TokenInputs) > 0 {
+		high = append(high, "new inputs receive a token: "+strings.Join(report.TokenInputs, ", "))
+	}
+	if len(report.PermissionMentions) > 0 {
+		high = append(high, "release notes mention permissions: "+strings.Join(report.PermissionMentions, ", "))
+	}
+	if len(report.NewRequiredInputs) > 0 {
+		medium = append(medium, "new required inputs: "+strings.Join(report.NewRequiredInputs, ", "))
+	}
+	if len(report.RemovedInputs) > 0 {
+		medium = append(medium, "removed inputs: "+strings.Join(report.RemovedInputs, ", "))
+	}
+	if report.RuntimeChange != "" {
+		medium = append(medium, "runtime change: "+report.RuntimeChange)
+	}
+	// Reasons recorded while comparing metadata (such as an unavailable action.yml) are kept as medium risk
+	medium = append(medium, report.RiskReasons...)
+
+	switch {
+	case len(high) > 0:
+		report.Risk = actionRiskHigh
+	case len(medium) > 0:
+		report.Risk = actionRiskMedium
+	default:
+		report.Risk = actionRiskLow
+	}
+	report.RiskReasons = append(high, medium...)
+}
+
+// renderActionUpdateReport renders the reports as a markdown pull request body
+func renderActionUpdateReport(reports []*ActionUpdateReport, allowMajor bool) string {
+	var updated, upToDate, failed []*ActionUpdateReport
+	for _, report := range reports {
+		switch {
+		case report.Error != "":
+			failed = append(failed, report)
+		case report.Updated():
+			updated = append(updated, report)
+		default:
+			upToDate = append(upToDate, report)
+		}
+	}
+	riskOrder := map[string]int{actionRiskHigh: 0, actionRiskMedium: 1, actionRiskLow: 2}
+	sort.SliceStable(updated, func(i, j int) bool {
+		if riskOrder[updated[i].Risk] != riskOrder[updated[j].Risk] {
+			return riskOrder[updated[i].Risk] < riskOrder[updated[j].Risk]
+		}
+		return updated[i].Repo < updated[j].Repo
+	})
+
+	var b strings.Builder
+	b.WriteString("## Update pinned GitHub Actions\n\n")
+	if len(updated) == 0 {
+		b.WriteString("All pinned actions are up to date.\n")
+	} else {
+		counts := make(map[string]int)
+		for _, report := range updated {
+			counts[report.Risk]++
+		}
+		fmt.Fprintf(&b, "Updates %d action pin(s) in `.github/aw/actions-lock.json`: %d high, %d medium and %d low risk.\n\n",
+			len(updated), counts[actionRiskHigh], counts[actionRiskMedium], counts[actionRiskLow])
+
+		b.WriteString("| Action | From | To | Risk |\n")
+		b.WriteString("|--------|------|----|------|\n")
+		for _, report := range updated {
+			fmt.Fprintf(&b, "| `%s` | %s (`%s`) | %s (`%s`) | %s |\n", report.Repo,
+				report.CurrentVersion, shortSHA(report.CurrentSHA), report.TargetVersion, shortSHA(report.TargetSHA), report.Risk)
+		}
+
+		for _, report := range updated {
+			fmt.Fprintf(&b, "\n### `%s` %s → %s\n\n", report.Repo, report.CurrentVersion, report.TargetVersion)
+			fmt.Fprintf(&b, "**Risk: %s**\n", report.Risk)
+			if len(report.RiskReasons) > 0 {
+				b.WriteString("\n")
+				for _, reason := range report.RiskReasons {
+					fmt.Fprintf(&b, "- %s\n", reason)
+				}
+			}
+			if len(report.AddedInputs) > 0 {
+				fmt.Fprintf(&b, "\nNew inputs: %s\n", formatCodeList(report.AddedInputs))
+			}
+			if len(report.NewerVersions) > 0 {
+				fmt.Fprintf(&b, "\nNewer versions available: %s\n", strings.Join(report.NewerVersions, ", "))
+			}
+			writeReleaseNotes(&b, report.ReleaseNotes)
+		}
+	}
+
+	var majorAvailable []string
+	for _, report := range upToDate {
+		if len(report.NewerVersions) > 0 {
+			majorAvailable = append(majorAvailable, fmt.Sprintf("- `%s` %s: %s", report.Repo, report.CurrentVersion, strings.Join(report.NewerVersions, ", ")))
+		}
+	}
+	if len(majorAvailable) > 0 && !allowMajor {
+		b.WriteString("\n### Newer major versions not applied\n\n")
+		b.WriteString("Run `gh aw update actions --major` to include them.\n\n")
+		b.WriteString(strings.Join(majorAvailable, "\n") + "\n")
+	}
+
+	if len(failed) > 0 {
+		b.WriteString("\n### Not checked\n\n")
+		for _, report := range failed {
+			fmt.Fprintf(&b, "- `%s` %s: %s\n", report.Repo, report.CurrentVersion, report.Error)
+		}
+	}
+	return b.String()
+}
+
+// writeReleaseNotes writes release notes in a collapsed section
+func writeReleaseNotes(b *strings.Builder, notes []ActionReleaseNote) {
+	if len(notes) == 0 {
+		return
+	}
+	fmt.Fprintf(b, "\n<details>\n<summary>Release notes (%d)</summary>\n", len(notes))
+	for _, note := range notes {
+		if note.URL != "" {
+			fmt.Fprintf(b, "\n#### [%s](%s)\n", note.Version, note.URL)
+		} else {
+			fmt.Fprintf(b, "\n#### %s\n", note.Version)
+		}
+		if note.Notes != "" {
+			b.WriteString("\n" + note.Notes + "\n")
+		}
+	}
+	b.WriteString("\n</details>\n")
+}
+
+// formatCodeList formats names as a comma-separated list of inline code
+func formatCodeList(names []string) string {
+	quoted := make([]string, len(names))
+	for i, name := range names {
+		quoted[i] = "`" + name + "`"
+	}
+	return strings.Join(quoted, ", ")
+}
+
+// shortSHA returns the first 7 characters of a commit SHA
+func shortSHA(sha string) string {
+	if len(sha) > 7 {
+		return sha[:7]
+	}
+	return sha
+}
+
+// UpdateActionsWithReport updates the pins in .github/aw/actions-lock.json like UpdateActions
+// and returns a markdown report of the risk of each update
+func UpdateActionsWithReport(allowMajor, verbose bool) (string, error) {
+	actionsLockPath := filepath.Join(".github", "aw", "actions-lock.json")
+	if _, err := os.Stat(actionsLockPath); os.IsNotExist(err) {
+		return "", fmt.Errorf("actions lock file not found: %s", actionsLockPath)
+	}
+	actionsLock, err := readActionsLock(actionsLockPath)
+	if err != nil {
+		return "", err
+	}
+
+	keys := make([]string, 0, len(actionsLock.Entries))
+	for key := range actionsLock.Entries {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var reports []*ActionUpdateReport
+	updatedCount := 0
+	for _, key := range keys {
+		entry := actionsLock.Entries[key]
+		report := buildActionUpdateReport(entry, allowMajor)
+		reports = append(reports, report)
+
+		if report.Error != "" {
+			fmt.Fprintln(os.Stderr, console.FormatWarningMessage(fmt.Sprintf("Failed to check %s: %s", entry.Repo, report.Error)))
+			continue
+		}
+		if !report.Updated() {
+			console.LogVerbose(verbose, fmt.Sprintf("%s@%s is up to date", entry.Repo, entry.Version))
+			continue
+		}
+
+		fmt.Fprintln(os.Stderr, console.FormatSuccessMessage(fmt.Sprintf("Updated %s from %s to %s (%s risk)", entry.Repo, entry.Version, report.TargetVersion, report.Risk)))
+		delete(actionsLock.Entries, key)
+		actionsLock.Entries[entry.Repo+"@"+report.TargetVersion] = actionsLockEntry{
+			Repo:    entry.Repo,
+			Version: report.TargetVersion,
+			SHA:     report.TargetSHA,
+		}
+		updatedCount++
+	}
+
+	if updatedCount > 0 {
+		if err := writeActionsLock(actionsLockPath, actionsLock); err != nil {
+			return "", err
+		}
+		updateLog.Printf("Wrote actions-lock.json with %d updates", updatedCount)
+	}
+
+	return renderActionUpdateReport(reports, allowMajor), nil
+}
@@ -0,0 +1,181 @@
+//go:build !integration
+
+package cli
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testPinnedSHA = "1111111111111111111111111111111111111111"
+	testMovedSHA  = "2222222222222222222222222222222222222222"
+	testNewSHA    = "3333333333333333333333333333333333333333"
+)
+
+// stubActionUpdateSources replaces GitHub access with fixed releases, tag SHAs and action.yml files
+func stubActionUpdateSources(t *testing.T, releases []actionRelease, tagSHAs map[string]string, metadata map[string]*actionMetadata) {
+	t.Helper()
+	origReleases, origMetadata, origResolve := fetchActionReleasesFunc, fetchActionMetadataFunc, resolveActionTagSHAFunc
+	t.Cleanup(func() {
+		fetchActionReleasesFunc, fetchActionMetadataFunc, resolveActionTagSHAFunc = origReleases, origMetadata, origResolve
+	})
+
+	fetchActionReleasesFunc = func(baseRepo string) ([]actionRelease, error) {
+		return releases, nil
+	}
+	resolveActionTagSHAFunc = func(repo, tag string) (string, error) {
+		if sha, ok := tagSHAs[tag]; ok {
+			return sha, nil
+		}
+		return "", errors.New("tag not found")
+	}
+	fetchActionMetadataFunc = func(repo, sha string) (*actionMetadata, error) {
+		if m, ok := metadata[sha]; ok {
+			return m, nil
+		}
+		return nil, errors.New("action.yml not found")
+	}
+}
+
+func TestBuildActionUpdateReport(t *testing.T) {
+	releases := []actionRelease{
+		{TagName: "v5.0.0", Body: "Breaking changes"},
+		{TagName: "v4.2.0", Body: "Adds a `github-token` input. Requires `contents: write` permissions.", HTMLURL: "https://github.com/owner/action/releases/tag/v4.2.0"},
+		{TagName: "v4.1.1", Body: "Bug fixes"},
+		{TagName: "v4.1.0", Body: "Current release"},
+		{TagName: "v4.3.0-beta", Body: "Beta", Prerelease: true},
+	}
+	current := &actionMetadata{Inputs: map[string]actionMetadataInput{"path": {}, "legacy": {}}}
+	current.Runs.Using = "node20"
+	target := &actionMetadata{Inputs: map[string]actionMetadataInput{
+		"path":         {},
+		"github-token": {Default: "${{ github.token }}"},
+		"mode":         {Required: true},
+	}}
+	target.Runs.Using = "node24"
+
+	t.Run("minor bump", func(t *testing.T) {
+		stubActionUpdateSources(t, releases,
+			map[string]string{"v4.1.0": testPinnedSHA, "v4.2.0": testNewSHA},
+			map[string]*actionMetadata{testPinnedSHA: current, testNewSHA: target})
+
+		report := buildActionUpdateReport(actionsLockEntry{Repo: "owner/action", Version: "v4.1.0", SHA: testPinnedSHA}, false)
+		require.Empty(t, report.Error)
+		assert.True(t, report.Updated())
+		assert.Equal(t, "v4.2.0", report.TargetVersion)
+		assert.Equal(t, testNewSHA, report.TargetSHA)
+		assert.Equal(t, []string{"v5.0.0", "v4.2.0", "v4.1.1"}, report.NewerVersions, "prereleases should not be offered")
+		assert.False(t, report.ForceMoved)
+
+		require.Len(t, report.ReleaseNotes, 2)
+		assert.Equal(t, "v4.1.1", report.ReleaseNotes[0].Version, "release notes should be oldest first")
+		assert.Equal(t, "v4.2.0", report.ReleaseNotes[1].Version)
+
+		assert.Equal(t, []string{"github-token", "mode"}, report.AddedInputs)
+		assert.Equal(t, []string{"legacy"}, report.RemovedInputs)
+		assert.Equal(t, []string{"mode"}, report.NewRequiredInputs)
+		assert.Equal(t, []string{"github-token"}, report.TokenInputs)
+		assert.Equal(t, "node20 → node24", report.RuntimeChange)
+		assert.Equal(t, []string{"v4.2.0"}, report.PermissionMentions)
+
+		assert.Equal(t, actionRiskHigh, report.Risk)
+		assert.Equal(t, []string{
+			"new inputs receive a token: github-token",
+			"release notes mention permissions: v4.2.0",
+			"minor version bump (v4.1.0 → v4.2.0)",
+			"new required inputs: mode",
+			"removed inputs: legacy",
+			"runtime change: node20 → node24",
+		}, report.RiskReasons)
+	})
+
+	t.Run("force-moved tag", func(t *testing.T) {
+		stubActionUpdateSources(t, []actionRelease{{TagName: "v4"}},
+			map[string]string{"v4": testMovedSHA},
+			map[string]*actionMetadata{testPinnedSHA: current, testMovedSHA: current})
+
+		report := buildActionUpdateReport(actionsLockEntry{Repo: "owner/action", Version: "v4", SHA: testPinnedSHA}, false)
+		require.Empty(t, report.Error)
+		assert.True(t, report.Updated(), "a moved tag should be re-pinned")
+		assert.True(t, report.ForceMoved)
+		assert.Equal(t, testMovedSHA, report.MovedToSHA)
+		assert.Equal(t, actionRiskHigh, report.Risk)
+		assert.Equal(t, []string{"tag v4 was force-moved from 1111111 to 2222222"}, report.RiskReasons)
+	})
+
+	t.Run("newer major only", func(t *testing.T) {
+		stubActionUpdateSources(t, releases,
+			map[string]string{"v4.2.0": testPinnedSHA},
+			nil)
+
+		report := buildActionUpdateReport(actionsLockEntry{Repo: "owner/action", Version: "v4.2.0", SHA: testPinnedSHA}, false)
+		require.Empty(t, report.Error)
+		assert.False(t, report.Updated())
+		assert.Equal(t, []string{"v5.0.0"}, report.NewerVersions)
+		assert.Equal(t, actionRiskLow, report.Risk)
+		assert.Empty(t, report.ReleaseNotes)
+	})
+
+	t.Run("patch bump without metadata", func(t *testing.T) {
+		stubActionUpdateSources(t, releases,
+			map[string]string{"v4.1.0": testPinnedSHA, "v4.2.0": testNewSHA},
+			nil)
+
+		report := buildActionUpdateReport(actionsLockEntry{Repo: "owner/action", Version: "v4.1.0", SHA: testPinnedSHA}, false)
+		assert.Equal(t, actionRiskHigh, report.Risk, "permission mentions in the notes keep the risk high")
+		assert.Contains(t, report.RiskReasons, "action.yml could not be compared: action.yml not found")
+	})
+}
+
+func TestRenderActionUpdateReport(t *testing.T) {
+	reports := []*ActionUpdateReport{
+		{Repo: "actions/cache", CurrentVersion: "v4.1.0", CurrentSHA: testPinnedSHA, TargetVersion: "v4.1.1", TargetSHA: testNewSHA, Risk: actionRiskLow,
+			ReleaseNotes: []ActionReleaseNote{{Version: "v4.1.1", URL: "https://github.com/actions/cache/releases/tag/v4.1.1", Notes: "Bug fixes"}}},
+		{Repo: "actions/checkout", CurrentVersion: "v4", CurrentSHA: testPinnedSHA, TargetVersion: "v4", TargetSHA: testMovedSHA, ForceMoved: true, Risk: actionRiskHigh,
+			RiskReasons: []string{"tag v4 was force-moved from 1111111 to 2222222"}},
+		{Repo: "actions/setup-node", CurrentVersion: "v4.2.0", CurrentSHA: testPinnedSHA, TargetVersion: "v4.2.0", TargetSHA: testPinnedSHA, NewerVersions: []string{"v5.0.0"}, Risk: actionRiskLow},
+		{Repo: "owner/private", CurrentVersion: "v1", CurrentSHA: testPinnedSHA, Error: "failed to fetch releases: HTTP 404"},
+	}
+
+	body := renderActionUpdateReport(reports, false)
+	assert.Contains(t, body, "Updates 2 action pin(s) in `.github/aw/actions-lock.json`: 1 high, 0 medium and 1 low risk.")
+	assert.Contains(t, body, "| `actions/checkout` | v4 (`1111111`) | v4 (`2222222`) | high |\n| `actions/cache` |", "high risk updates should be listed first")
+	assert.Contains(t, body, "### `actions/checkout` v4 → v4\n\n**Risk: high**\n\n- tag v4 was force-moved from 1111111 to 2222222\n")
+	assert.Contains(t, body, "<summary>Release notes (1)</summary>\n\n#### [v4.1.1](https://github.com/actions/cache/releases/tag/v4.1.1)\n\nBug fixes\n")
+	assert.Contains(t, body, "### Newer major versions not applied")
+	assert.Contains(t, body, "- `actions/setup-node` v4.2.0: v5.0.0")
+	assert.Contains(t, body, "### Not checked\n\n- `owner/private` v1: failed to fetch releases: HTTP 404")
+
+	assert.Contains(t, renderActionUpdateReport(nil, false), "All pinned actions are up to date.")
+}
+
+func TestUpdateActionsWithReport(t *testing.T) {
+	dir := t.TempDir()
+	t.Chdir(dir)
+	require.NoError(t, os.MkdirAll(filepath.Join(".github", "aw"), 0755))
+	actionsLockPath := filepath.Join(".github", "aw", "actions-lock.json")
+	require.NoError(t, writeActionsLock(actionsLockPath, &actionsLockFile{Entries: map[string]actionsLockEntry{
+		"owner/action@v4.1.0": {Repo: "owner/action", Version: "v4.1.0", SHA: testPinnedSHA},
+	}}))
+
+	metadata := &actionMetadata{}
+	stubActionUpdateSources(t, []actionRelease{{TagName: "v4.1.1", Body: "Bug fixes"}, {TagName: "v4.1.0"}},
+		map[string]string{"v4.1.0": testPinnedSHA, "v4.1.1": testNewSHA},
+		map[string]*actionMetadata{testPinnedSHA: metadata, testNewSHA: metadata})
+
+	body, err := UpdateActionsWithReport(false, false)
+	require.NoError(t, err)
+	assert.Contains(t, body, "| `owner/action` | v4.1.0 (`1111111`) | v4.1.1 (`3333333`) | low |")
+
+	actionsLock, err := readActionsLock(actionsLockPath)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]actionsLockEntry{
+		"owner/action@v4.1.1": {Repo: "owner/action", Version: "v4.1.1", SHA: testNewSHA},
+	}, actionsLock.Entries)
+}
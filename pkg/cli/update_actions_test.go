@@ -203,39 +203,9 @@ func TestMajorVersionPreference(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			currentVer := parseVersion(tt.currentVersion)
-			if currentVer == nil {
-				t.Fatalf("Failed to parse current version: %s", tt.currentVersion)
-			}
-
-			var latestCompatible string
-			var latestCompatibleVersion *semanticVersion
-
-			for _, release := range tt.releases {
-				releaseVer := parseVersion(release)
-				if releaseVer == nil {
-					continue
-				}
-
-				// Check if compatible based on major version
-				if !tt.allowMajor && releaseVer.major != currentVer.major {
-					continue
-				}
-
-				// Check if this is newer than what we have
-				if latestCompatibleVersion == nil || releaseVer.isNewer(latestCompatibleVersion) {
-					latestCompatible = release
-					latestCompatibleVersion = releaseVer
-				} else if !releaseVer.isNewer(latestCompatibleVersion) &&
-					releaseVer.major == latestCompatibleVersion.major &&
-					releaseVer.minor == latestCompatibleVersion.minor &&
-					releaseVer.patch == latestCompatibleVersion.patch {
-					// If versions are equal, prefer the less precise one (e.g., "v8" over "v8.0.0")
-					if !releaseVer.isPreciseVersion() && latestCompatibleVersion.isPreciseVersion() {
-						latestCompatible = release
-						latestCompatibleVersion = releaseVer
-					}
-				}
+			latestCompatible, err := selectLatestCompatibleRelease(tt.releases, tt.currentVersion, tt.allowMajor)
+			if err != nil {
+				t.Fatalf("selectLatestCompatibleRelease() error = %v", err)
 			}
 
 			if latestCompatible != tt.expectedVersion {
@@ -2,7 +2,9 @@ package cli
 
 import (
 	"fmt"
+	"os"
 
+	"github.com/github/gh-aw/pkg/console"
 	"github.com/github/gh-aw/pkg/constants"
 	"github.com/github/gh-aw/pkg/logger"
 	"github.com/spf13/cobra"
@@ -30,7 +32,8 @@ For workflow updates, it fetches the latest version based on the current ref:
 - If the ref is a branch, it fetches the latest commit from that branch
 - If the ref is a commit SHA, it fetches the latest commit from the default branch
 
-For extension updates, action updates, agent files, and codemods, use 'gh aw upgrade'.
+To update pinned GitHub Actions with a risk report, use 'gh aw update actions'.
+For extension updates, agent files, and codemods, use 'gh aw upgrade'.
 
 ` + WorkflowIDExplanation + `
 
@@ -73,9 +76,84 @@ Examples:
 	RegisterEngineFlagCompletion(cmd)
 	RegisterDirFlagCompletion(cmd, "dir")
 
+	cmd.AddCommand(newUpdateActionsSubcommand())
+
 	return cmd
 }
 
+// newUpdateActionsSubcommand creates the update actions subcommand
+func newUpdateActionsSubcommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "actions",
+		Short: "Update pinned GitHub Actions and report the risk of each bump",
+		Long: `Update the GitHub Actions pinned in .github/aw/actions-lock.json to their latest
+compatible release and recompile all workflows.
+
+With --report, each pinned action is also checked for:
+- Newer versions available, including major versions not applied without --major
+- Tags that were force-moved (the pinned tag now points to a different commit)
+- Release notes between the pinned and the new version
+- New, removed and required inputs, inputs that receive a token, and runtime changes in action.yml
+- Release notes that mention permissions
+
+The report is written to stdout as markdown with a risk level (high, medium or low)
+per action, ready to use as the body of the pull request that applies the update.
+
+Examples:
+  ` + string(constants.CLIExtensionPrefix) + ` update actions                         # Update action pins
+  ` + string(constants.CLIExtensionPrefix) + ` update actions --major                 # Allow major version updates
+  ` + string(constants.CLIExtensionPrefix) + ` update actions --report > pr-body.md   # Update and write a risk summary`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			majorFlag, _ := cmd.Flags().GetBool("major")
+			reportFlag, _ := cmd.Flags().GetBool("report")
+			workflowDir, _ := cmd.Flags().GetString("dir")
+			verbose, _ := cmd.Flags().GetBool("verbose")
+			return RunUpdateActions(majorFlag, reportFlag, workflowDir, verbose)
+		},
+	}
+
+	cmd.Flags().Bool("major", false, "Allow major version updates")
+	cmd.Flags().Bool("report", false, "Write a markdown summary of the release notes and risk of each update to stdout")
+	cmd.Flags().StringP("dir", "d", "", "Workflow directory (default: .github/workflows)")
+	RegisterDirFlagCompletion(cmd, "dir")
+
+	return cmd
+}
+
+// RunUpdateActions updates the pinned GitHub Actions, optionally printing a risk report, and
+// recompiles all workflows so that lock files use the new pins
+func RunUpdateActions(allowMajor, report bool, workflowDir string, verbose bool) error {
+	updateLog.Printf("Starting action updates: allowMajor=%v, report=%v", allowMajor, report)
+
+	if report {
+		body, err := UpdateActionsWithReport(allowMajor, verbose)
+		if err != nil {
+			return fmt.Errorf("action update failed: %w", err)
+		}
+		fmt.Print(body)
+	} else if err := UpdateActions(allowMajor, verbose); err != nil {
+		return fmt.Errorf("action update failed: %w", err)
+	}
+
+	workflowsDir := workflowDir
+	if workflowsDir == "" {
+		workflowsDir = ".github/workflows"
+	}
+	compiler := createAndConfigureCompiler(CompileConfig{
+		Verbose:     verbose,
+		WorkflowDir: workflowDir,
+	})
+	stats, err := compileAllWorkflowFiles(compiler, workflowsDir, verbose)
+	if err != nil {
+		return fmt.Errorf("failed to compile workflows: %w", err)
+	}
+	if stats != nil && stats.Errors > 0 {
+		fmt.Fprintln(os.Stderr, console.FormatWarningMessage(fmt.Sprintf("%d workflow(s) failed to compile", stats.Errors)))
+	}
+	return nil
+}
+
 // RunUpdateWorkflows updates workflows from their source repositories.
 // Each workflow is compiled immediately after update.
 func RunUpdateWorkflows(workflowNames []string, allowMajor, force, verbose bool, engineOverride string, workflowsDir string, noStopAfter bool, stopAfter string, noMerge bool) error {
@@ -0,0 +1,426 @@
+// This file provides the verify command, which checks that a lock file can be reproduced.
+//
+// A lock file records the frontmatter hash, the compiler version (release builds) and the
+// commit SHAs of remote imports in its gh-aw-metadata header. `gh aw verify` recompiles the
+// source workflow in a clean copy of the repository, with remote imports downloaded at the
+// recorded commits instead of the current commit of their refs, and byte-compares the result
+// with the lock file. When they differ it reports the first divergent line and the YAML
+// section that contains it.
+
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/github/gh-aw/pkg/console"
+	"github.com/github/gh-aw/pkg/logger"
+	"github.com/github/gh-aw/pkg/parser"
+	"github.com/github/gh-aw/pkg/stringutil"
+	"github.com/github/gh-aw/pkg/workflow"
+	"github.com/spf13/cobra"
+)
+
+var verifyLog = logger.New("cli:verify_command")
+
+var (
+	// lockCompileTargetPattern matches the compile target comment in a lock file header
+	lockCompileTargetPattern = regexp.MustCompile(`(?m)^# Compile target: (ghes:\S+) \((\S+)\)$`)
+	// lockKeyPattern matches a YAML mapping key, capturing the key and its inline value
+	lockKeyPattern = regexp.MustCompile(`^("[^"]+"|'[^']+'|[A-Za-z0-9_.\-/]+):(?:\s+(.*))?$`)
+)
+
+// LockDivergence describes the first difference between a lock file and its recompiled output
+type LockDivergence struct {
+	Line     int    `json:"line"`
+	Section  string `json:"section"`
+	Expected string `json:"expected"`
+	Actual   string `json:"actual"`
+}
+
+// LockVerifyResult is the result of verifying that a lock file is reproducible
+type LockVerifyResult struct {
+	LockFile                string            `json:"lock_file"`
+	Source                  string            `json:"source"`
+	Reproducible            bool              `json:"reproducible"`
+	RecordedCompilerVersion string            `json:"recorded_compiler_version,omitempty"`
+	CompilerVersion         string            `json:"compiler_version"`
+	Imports                 map[string]string `json:"imports,omitempty"`
+	Reason                  string            `json:"reason,omitempty"`
+	Divergence              *LockDivergence   `json:"divergence,omitempty"`
+}
+
+// NewVerifyCommand creates the verify command
+func NewVerifyCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "verify <lock-file>...",
+		Short: "Verify that lock files are reproducible from their source workflows",
+		Long: `Recompile the source workflow of each lock file in a clean copy of the repository and
+byte-compare the result with the lock file.
+
+The recompilation uses the compiler version and remote import commit SHAs recorded in the
+lock file's gh-aw-metadata header, so remote imports are downloaded at the exact commits the
+lock file was compiled from even if their refs have moved since. Lock files compiled by a
+different gh-aw version cannot be verified with this binary; install the recorded version
+first.
+
+When the output differs, the first divergent line is reported along with the YAML section
+(for example jobs.agent.steps[Checkout repository]) that contains it.
+
+Examples:
+  gh aw verify .github/workflows/ci-doctor.lock.yml   # Verify a lock file
+  gh aw verify .github/workflows/*.lock.yml           # Verify all lock files
+  gh aw verify ci-doctor.lock.yml --json              # Output the result as JSON`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			jsonOutput, _ := cmd.Flags().GetBool("json")
+			verbose, _ := cmd.Flags().GetBool("verbose")
+			return RunVerify(args, jsonOutput, verbose)
+		},
+	}
+
+	addJSONFlag(cmd)
+
+	return cmd
+}
+
+// RunVerify verifies that the given lock files are reproducible and reports the results
+func RunVerify(lockFiles []string, jsonOutput bool, verbose bool) error {
+	verifyLog.Printf("Verifying %d lock files", len(lockFiles))
+
+	var results []LockVerifyResult
+	failed := 0
+	for _, lockFile := range lockFiles {
+		result, err := verifyLockFile(lockFile, verbose)
+		if err != nil {
+			return err
+		}
+		if !result.Reproducible {
+			failed++
+		}
+		results = append(results, *result)
+	}
+
+	if jsonOutput {
+		data, err := json.MarshalIndent(results, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal verify results: %w", err)
+		}
+		fmt.Println(string(data))
+	} else {
+		for _, result := range results {
+			printLockVerifyResult(result)
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d lock file(s) could not be reproduced", failed, len(results))
+	}
+	return nil
+}
+
+// printLockVerifyResult prints the verification result of a lock file to stderr
+func printLockVerifyResult(result LockVerifyResult) {
+	if result.Reproducible {
+		fmt.Fprintln(os.Stderr, console.FormatSuccessMessage(fmt.Sprintf("%s is reproducible from %s (gh-aw %s)",
+			result.LockFile, result.Source, result.CompilerVersion)))
+		return
+	}
+
+	fmt.Fprintln(os.Stderr, console.FormatErrorMessage(fmt.Sprintf("%s is not reproducible: %s", result.LockFile, result.Reason)))
+	if d := result.Divergence; d != nil {
+		fmt.Fprintf(os.Stderr, "  First divergence at line %d in %s\n", d.Line, d.Section)
+		fmt.Fprintf(os.Stderr, "    lock file:  %s\n", d.Actual)
+		fmt.Fprintf(os.Stderr, "    recompiled: %s\n", d.Expected)
+	}
+}
+
+// verifyLockFile recompiles the source of a lock file in a clean environment and compares the output
+func verifyLockFile(lockFile string, verbose bool) (*LockVerifyResult, error) {
+	content, err := os.ReadFile(lockFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read lock file: %w", err)
+	}
+	metadata, isLegacy, err := workflow.ExtractMetadataFromLockFile(string(content))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read metadata from %s: %w", lockFile, err)
+	}
+	if metadata == nil || isLegacy {
+		return nil, fmt.Errorf("%s has no gh-aw-metadata header. Recompile it with 'gh aw compile' before verifying", lockFile)
+	}
+
+	result := &LockVerifyResult{
+		LockFile:                lockFile,
+		Source:                  stringutil.LockFileToMarkdown(lockFile),
+		RecordedCompilerVersion: metadata.CompilerVersion,
+		CompilerVersion:         GetVersion(),
+		Imports:                 metadata.Imports,
+	}
+
+	// Schemas, templates and scripts are embedded in the binary, so only the recorded version can reproduce the output
+	if metadata.CompilerVersion != "" && metadata.CompilerVersion != GetVersion() {
+		result.Reason = fmt.Sprintf("compiled with gh-aw %s but this is %s. Install the recorded version to verify: gh extension install github/gh-aw@%s",
+			metadata.CompilerVersion, GetVersion(), metadata.CompilerVersion)
+		return result, nil
+	}
+	if metadata.CompilerVersion == "" {
+		console.LogVerbose(verbose, "Lock file does not record a compiler version (compiled by a development build)")
+	}
+
+	if _, err := os.Stat(result.Source); err != nil {
+		return nil, fmt.Errorf("source workflow %s not found for %s", result.Source, lockFile)
+	}
+
+	recompiled, err := recompileInCleanEnvironment(result.Source, string(content), metadata, verbose)
+	if err != nil {
+		result.Reason = "recompilation failed: " + err.Error()
+		return result, nil
+	}
+
+	if recompiled == string(content) {
+		result.Reproducible = true
+		return result, nil
+	}
+
+	result.Divergence = findLockDivergence(recompiled, string(content))
+	result.Reason = "recompiled output differs from the lock file"
+	if recompiledMetadata, _, err := workflow.ExtractMetadataFromLockFile(recompiled); err == nil && recompiledMetadata != nil &&
+		recompiledMetadata.FrontmatterHash != metadata.FrontmatterHash {
+		result.Reason = "the source frontmatter or its imports changed since the lock file was compiled"
+	}
+	return result, nil
+}
+
+// recompileInCleanEnvironment compiles a workflow in a temporary copy of its repository and
+// returns the generated lock file. Remote imports are downloaded at their recorded commits
+// rather than read from the repository's import cache.
+func recompileInCleanEnvironment(markdownPath string, lockContent string, metadata *workflow.LockMetadata, verbose bool) (string, error) {
+	absMarkdownPath, err := filepath.Abs(markdownPath)
+	if err != nil {
+		return "", err
+	}
+	gitRoot, err := findGitRootForPath(absMarkdownPath)
+	if err != nil {
+		return "", err
+	}
+	relMarkdownPath, err := filepath.Rel(gitRoot, absMarkdownPath)
+	if err != nil {
+		return "", err
+	}
+
+	cleanDir, err := os.MkdirTemp("", "gh-aw-verify-*")
+	if err != nil {
+		return "", fmt.Errorf("failed to create clean environment: %w", err)
+	}
+	defer os.RemoveAll(cleanDir)
+
+	if err := copyRepositoryFiles(gitRoot, cleanDir); err != nil {
+		return "", err
+	}
+
+	compiler := workflow.NewCompiler(
+		workflow.WithVerbose(verbose),
+		workflow.WithGitRoot(cleanDir),
+	)
+	configureCompilerFlags(compiler, CompileConfig{})
+	setupActionMode(compiler, "", "")
+	compiler.SetQuiet(true)
+	if repoSlug := getRepositorySlugFromRemoteForPath(absMarkdownPath); repoSlug != "" {
+		compiler.SetRepositorySlug(repoSlug)
+	}
+
+	importCache := parser.NewImportCache(cleanDir)
+	importCache.PinRefs(metadata.Imports)
+	compiler.SetImportCache(importCache)
+
+	if match := lockCompileTargetPattern.FindStringSubmatch(lockContent); match != nil {
+		target, err := workflow.ParseCompileTarget(match[1], match[2])
+		if err != nil {
+			return "", err
+		}
+		compiler.SetCompileTarget(target)
+	}
+
+	cleanMarkdownPath := filepath.Join(cleanDir, relMarkdownPath)
+	if err := compiler.CompileWorkflow(cleanMarkdownPath); err != nil {
+		return "", err
+	}
+
+	recompiled, err := os.ReadFile(stringutil.MarkdownToLockFile(cleanMarkdownPath))
+	if err != nil {
+		return "", fmt.Errorf("failed to read recompiled lock file: %w", err)
+	}
+	return string(recompiled), nil
+}
+
+// copyRepositoryFiles copies the tracked and untracked (but not ignored) files of a repository.
+// Cached remote imports are left out so they are downloaded again at their recorded commits.
+func copyRepositoryFiles(gitRoot, destDir string) error {
+	cmd := exec.Command("git", "-C", gitRoot, "ls-files", "-z", "--cached", "--others", "--exclude-standard")
+	output, err := cmd.Output()
+	if err != nil {
+		return fmt.Errorf("failed to list repository files: %w", err)
+	}
+
+	importCacheDir := filepath.ToSlash(parser.ImportCacheDir) + "/"
+	for file := range strings.SplitSeq(strings.TrimRight(string(output), "\x00"), "\x00") {
+		if file == "" || strings.HasPrefix(file, importCacheDir) {
+			continue
+		}
+		if err := copyRepositoryFile(filepath.Join(gitRoot, file), filepath.Join(destDir, file)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// copyRepositoryFile copies a regular file, skipping files deleted from the working tree
+func copyRepositoryFile(src, dest string) error {
+	info, err := os.Lstat(src)
+	if errors.Is(err, os.ErrNotExist) || (err == nil && !info.Mode().IsRegular()) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+		return err
+	}
+	out, err := os.OpenFile(dest, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, info.Mode().Perm())
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return fmt.Errorf("failed to copy %s: %w", src, err)
+	}
+	return out.Close()
+}
+
+// lockSectionFrame is a mapping key or list item on the path to a line of a lock file
+type lockSectionFrame struct {
+	indent int
+	label  string
+	item   bool
+}
+
+// findLockDivergence returns the first line where the recompiled output and the lock file
+// differ, with the path of the YAML section containing it
+func findLockDivergence(expected, actual string) *LockDivergence {
+	expectedLines := strings.Split(expected, "\n")
+	actualLines := strings.Split(actual, "\n")
+
+	for i := 0; i < max(len(expectedLines), len(actualLines)); i++ {
+		var expectedLine, actualLine string
+		if i < len(expectedLines) {
+			expectedLine = expectedLines[i]
+		}
+		if i < len(actualLines) {
+			actualLine = actualLines[i]
+		}
+		if expectedLine == actualLine {
+			continue
+		}
+
+		// Use the recompiled output for context unless it ended before the lock file
+		context := expectedLines
+		if i >= len(expectedLines) {
+			context = actualLines
+		}
+		return &LockDivergence{
+			Line:     i + 1,
+			Section:  lockSectionAt(context, i),
+			Expected: expectedLine,
+			Actual:   actualLine,
+		}
+	}
+	return nil
+}
+
+// lockSectionAt returns the path of the YAML section containing the given line, such as
+// jobs.agent.steps[Checkout repository].with, or "header" for the leading comment block
+func lockSectionAt(lines []string, index int) string {
+	var stack []lockSectionFrame
+	blockIndent := -1                  // indentation of the key that started the current block scalar
+	itemCounts := make(map[string]int) // number of list items seen per parent section
+
+	for i := 0; i <= index && i < len(lines); i++ {
+		line := lines[i]
+		trimmed := strings.TrimSpace(line)
+		indent := len(line) - len(strings.TrimLeft(line, " "))
+
+		if blockIndent >= 0 {
+			if trimmed == "" || indent > blockIndent {
+				continue
+			}
+			blockIndent = -1
+		}
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+
+		keyIndent := indent
+		if rest, ok := strings.CutPrefix(trimmed, "- "); ok {
+			// Sequences may be written at the same indentation as their parent key
+			for len(stack) > 0 && (stack[len(stack)-1].indent > indent || stack[len(stack)-1].indent == indent && stack[len(stack)-1].item) {
+				stack = stack[:len(stack)-1]
+			}
+			parent := formatLockSection(stack)
+			stack = append(stack, lockSectionFrame{indent: indent, label: fmt.Sprintf("[%d]", itemCounts[parent]), item: true})
+			itemCounts[parent]++
+			trimmed = strings.TrimSpace(rest)
+			keyIndent = indent + 2
+		}
+
+		match := lockKeyPattern.FindStringSubmatch(trimmed)
+		if match == nil {
+			continue
+		}
+		key, value := strings.Trim(match[1], `"'`), strings.TrimSpace(match[2])
+
+		for len(stack) > 0 && stack[len(stack)-1].indent >= keyIndent {
+			stack = stack[:len(stack)-1]
+		}
+		if top := len(stack) - 1; key == "name" && top >= 0 && stack[top].item && stack[top].indent == keyIndent-2 {
+			// Label list items (steps) by their name
+			stack[top].label = "[" + strings.Trim(value, `"'`) + "]"
+			continue
+		}
+		stack = append(stack, lockSectionFrame{indent: keyIndent, label: key})
+
+		if strings.HasPrefix(value, "|") || strings.HasPrefix(value, ">") {
+			blockIndent = keyIndent
+		}
+	}
+
+	if len(stack) == 0 {
+		return "header"
+	}
+	return formatLockSection(stack)
+}
+
+// formatLockSection joins the frames of a section path, such as jobs.agent.steps[0]
+func formatLockSection(stack []lockSectionFrame) string {
+	var section strings.Builder
+	for i, frame := range stack {
+		if i > 0 && !frame.item {
+			section.WriteString(".")
+		}
+		section.WriteString(frame.label)
+	}
+	return section.String()
+}
@@ -0,0 +1,141 @@
+//go:build !integration
+
+package cli
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/github/gh-aw/pkg/workflow"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindLockDivergence(t *testing.T) {
+	lock := `# gh-aw-metadata: {"schema_version":"v1"}
+
+name: "Triage"
+"on":
+  issues:
+    types: [opened]
+jobs:
+  agent:
+    runs-on: ubuntu-latest
+    steps:
+      - name: Checkout repository
+        uses: actions/checkout@v5
+        with:
+          persist-credentials: false
+      - uses: actions/setup-node@v4
+      - name: Run agent
+        run: |
+          echo "name: not a key"
+          copilot --prompt "$PROMPT"
+        env:
+          PROMPT: triage
+`
+
+	tests := []struct {
+		name    string
+		old     string
+		new     string
+		line    int
+		section string
+	}{
+		{name: "header", old: `"schema_version":"v1"`, new: `"schema_version":"v2"`, line: 1, section: "header"},
+		{name: "trigger", old: "types: [opened]", new: "types: [opened, edited]", line: 6, section: "on.issues.types"},
+		{name: "step input", old: "persist-credentials: false", new: "persist-credentials: true", line: 14, section: "jobs.agent.steps[Checkout repository].with.persist-credentials"},
+		{name: "unnamed step", old: "setup-node@v4", new: "setup-node@v5", line: 15, section: "jobs.agent.steps[1].uses"},
+		{name: "block scalar", old: `--prompt "$PROMPT"`, new: `--prompt "$TASK"`, line: 19, section: "jobs.agent.steps[Run agent].run"},
+		{name: "after block scalar", old: "PROMPT: triage", new: "PROMPT: review", line: 21, section: "jobs.agent.steps[Run agent].env.PROMPT"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recompiled := strings.Replace(lock, tt.old, tt.new, 1)
+			divergence := findLockDivergence(recompiled, lock)
+			require.NotNil(t, divergence)
+			assert.Equal(t, tt.line, divergence.Line)
+			assert.Equal(t, tt.section, divergence.Section)
+			assert.Contains(t, divergence.Expected, tt.new)
+			assert.Contains(t, divergence.Actual, tt.old)
+		})
+	}
+
+	assert.Nil(t, findLockDivergence(lock, lock))
+
+	truncated := findLockDivergence(lock, lock+"  extra:\n")
+	require.NotNil(t, truncated, "extra lines in the lock file should diverge")
+	assert.Equal(t, 22, truncated.Line)
+	assert.Empty(t, truncated.Expected)
+}
+
+func TestVerifyLockFile_CompilerVersionMismatch(t *testing.T) {
+	lockFile := filepath.Join(t.TempDir(), "triage.lock.yml")
+	require.NoError(t, os.WriteFile(lockFile, []byte(`# gh-aw-metadata: {"schema_version":"v1","frontmatter_hash":"abc","compiler_version":"v0.0.1-test"}
+name: test
+`), 0644))
+
+	result, err := verifyLockFile(lockFile, false)
+	require.NoError(t, err)
+	assert.False(t, result.Reproducible)
+	assert.Equal(t, "v0.0.1-test", result.RecordedCompilerVersion)
+	assert.Contains(t, result.Reason, "compiled with gh-aw v0.0.1-test")
+	assert.Contains(t, result.Reason, "gh extension install github/gh-aw@v0.0.1-test")
+}
+
+func TestVerifyLockFile_MissingMetadata(t *testing.T) {
+	lockFile := filepath.Join(t.TempDir(), "triage.lock.yml")
+	require.NoError(t, os.WriteFile(lockFile, []byte("name: test\n"), 0644))
+
+	_, err := verifyLockFile(lockFile, false)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "has no gh-aw-metadata header")
+}
+
+func TestVerifyLockFile_Reproducible(t *testing.T) {
+	repoDir := t.TempDir()
+	require.NoError(t, exec.Command("git", "init", "-q", repoDir).Run())
+	workflowsDir := filepath.Join(repoDir, ".github", "workflows")
+	require.NoError(t, os.MkdirAll(filepath.Join(workflowsDir, "shared"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(workflowsDir, "shared", "tools.md"), []byte("---\ntools:\n  bash: [\"ls\"]\n---\n"), 0644))
+
+	markdownPath := filepath.Join(workflowsDir, "triage.md")
+	require.NoError(t, os.WriteFile(markdownPath, []byte(`---
+on:
+  issues:
+    types: [opened]
+permissions:
+  contents: read
+  issues: read
+engine: copilot
+imports:
+  - shared/tools.md
+---
+Triage the issue.
+`), 0644))
+
+	compiler := workflow.NewCompiler(workflow.WithGitRoot(repoDir), workflow.WithActionMode(workflow.DetectActionMode(GetVersion())))
+	compiler.SetQuiet(true)
+	require.NoError(t, compiler.CompileWorkflow(markdownPath))
+	lockFile := filepath.Join(workflowsDir, "triage.lock.yml")
+
+	result, err := verifyLockFile(lockFile, false)
+	require.NoError(t, err)
+	assert.True(t, result.Reproducible, "lock file should be reproducible: %s", result.Reason)
+
+	// An edited lock file is reported with the divergent section
+	content, err := os.ReadFile(lockFile)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(lockFile, []byte(strings.Replace(string(content), "types:\n    - opened", "types:\n    - edited", 1)), 0644))
+
+	result, err = verifyLockFile(lockFile, false)
+	require.NoError(t, err)
+	assert.False(t, result.Reproducible)
+	require.NotNil(t, result.Divergence)
+	assert.Equal(t, "on.issues.types[0]", result.Divergence.Section)
+	assert.Equal(t, "recompiled output differs from the lock file", result.Reason)
+}
@@ -415,6 +415,16 @@ const AWFDefaultLogLevel = "info"
 // DefaultMCPGatewayVersion is the default version of the MCP Gateway (gh-aw-mcpg) Docker image
 const DefaultMCPGatewayVersion Version = "v0.1.5"
 
+// MCPGatewayCassetteSpecVersion is the MCP Gateway Specification version that added the record
+// and replay gateway settings (section 5.6). Gateways report the specification version they
+// implement in the specVersion field of their /health response.
+const MCPGatewayCassetteSpecVersion Version = "1.10.0"
+
+// MCPGatewayToolConstraintsSpecVersion is the MCP Gateway Specification version that added
+// tool argument constraints (toolConstraints, section 5.5). Gateways report the specification
+// version they implement in the specVersion field of their /health response.
+const MCPGatewayToolConstraintsSpecVersion Version = "1.9.0"
+
 // DefaultMCPGatewayContainer is the default container image for the MCP Gateway
 const DefaultMCPGatewayContainer = "ghcr.io/github/gh-aw-mcpg"
 
@@ -422,6 +432,14 @@ const DefaultMCPGatewayContainer = "ghcr.io/github/gh-aw-mcpg"
 // This directory is shared between the agent container and MCP gateway for large payload exchange
 const DefaultMCPGatewayPayloadDir = "/tmp/gh-aw/mcp-payloads"
 
+// DefaultMCPGatewayCassettePath is where the MCP gateway records tool calls when recording is enabled
+// It lives in the mcp-logs directory so the cassette is uploaded with the agent artifacts
+const DefaultMCPGatewayCassettePath = "/tmp/gh-aw/mcp-logs/cassette.jsonl"
+
+// DefaultMCPGatewayReplayPath is where the replay cassette is copied before the MCP gateway starts
+// It lives outside the writable workspace; /opt is mounted read-only into the gateway
+const DefaultMCPGatewayReplayPath = "/opt/gh-aw/mcp-cassettes/replay.jsonl"
+
 // DefaultFirewallRegistry is the container image registry for AWF (gh-aw-firewall) Docker images
 const DefaultFirewallRegistry = "ghcr.io/github/gh-aw-firewall"
 
@@ -3,6 +3,10 @@
 package fileutil
 
 import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"os"
 	"path/filepath"
 	"runtime"
 	"strings"
@@ -176,3 +180,46 @@ func TestValidateAbsolutePath_SecurityScenarios(t *testing.T) {
 		})
 	}
 }
+
+func TestWriteAndExtractTarGz(t *testing.T) {
+	srcDir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(srcDir, "nested", "dir"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(srcDir, "manifest.json"), []byte(`{"version":1}`), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(srcDir, "nested", "dir", "file.md"), []byte("content"), 0600))
+
+	var first, second bytes.Buffer
+	require.NoError(t, WriteTarGz(&first, srcDir))
+	require.NoError(t, WriteTarGz(&second, srcDir))
+	assert.Equal(t, first.Bytes(), second.Bytes(), "archives of the same files should be identical")
+
+	destDir := t.TempDir()
+	require.NoError(t, ExtractTarGz(bytes.NewReader(first.Bytes()), destDir))
+
+	content, err := os.ReadFile(filepath.Join(destDir, "nested", "dir", "file.md"))
+	require.NoError(t, err)
+	assert.Equal(t, "content", string(content))
+	content, err = os.ReadFile(filepath.Join(destDir, "manifest.json"))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"version":1}`, string(content))
+}
+
+func TestExtractTarGz_RejectsPathTraversal(t *testing.T) {
+	tests := []string{"../escape.txt", "nested/../../escape.txt", "/etc/escape.txt"}
+
+	for _, name := range tests {
+		t.Run(name, func(t *testing.T) {
+			var buf bytes.Buffer
+			gw := gzip.NewWriter(&buf)
+			tw := tar.NewWriter(gw)
+			require.NoError(t, tw.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: 1, Typeflag: tar.TypeReg}))
+			_, err := tw.Write([]byte("x"))
+			require.NoError(t, err)
+			require.NoError(t, tw.Close())
+			require.NoError(t, gw.Close())
+
+			err = ExtractTarGz(&buf, t.TempDir())
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), "invalid path in archive")
+		})
+	}
+}
@@ -3,8 +3,14 @@ package fileutil
 import (
 	"archive/tar"
 	"bytes"
+	"compress/gzip"
 	"fmt"
 	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
 )
 
 // ExtractFileFromTar extracts a single file from a tar archive.
@@ -26,3 +32,98 @@ func ExtractFileFromTar(data []byte, path string) ([]byte, error) {
 	}
 	return nil, fmt.Errorf("file %q not found in archive", path)
 }
+
+// WriteTarGz writes the regular files under srcDir to a gzip-compressed tar archive.
+// Entries are sorted and have fixed timestamps and modes so the same files always
+// produce the same archive.
+func WriteTarGz(w io.Writer, srcDir string) error {
+	var files []string
+	err := filepath.WalkDir(srcDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.Type().IsRegular() {
+			files = append(files, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to list files in %s: %w", srcDir, err)
+	}
+	sort.Strings(files)
+
+	gw := gzip.NewWriter(w)
+	tw := tar.NewWriter(gw)
+	for _, path := range files {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", path, err)
+		}
+		relPath, err := filepath.Rel(srcDir, path)
+		if err != nil {
+			return err
+		}
+		header := &tar.Header{
+			Name:     filepath.ToSlash(relPath),
+			Mode:     0644,
+			Size:     int64(len(content)),
+			ModTime:  time.Unix(0, 0),
+			Typeflag: tar.TypeReg,
+			Format:   tar.FormatPAX,
+		}
+		if err := tw.WriteHeader(header); err != nil {
+			return fmt.Errorf("failed to write tar header for %s: %w", relPath, err)
+		}
+		if _, err := tw.Write(content); err != nil {
+			return fmt.Errorf("failed to write %s to archive: %w", relPath, err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return fmt.Errorf("failed to close tar archive: %w", err)
+	}
+	return gw.Close()
+}
+
+// ExtractTarGz extracts the regular files of a gzip-compressed tar archive into destDir.
+// Entries with absolute paths or paths escaping destDir are rejected.
+func ExtractTarGz(r io.Reader, destDir string) error {
+	gr, err := gzip.NewReader(r)
+	if err != nil {
+		return fmt.Errorf("failed to read gzip archive: %w", err)
+	}
+	defer gr.Close()
+
+	tr := tar.NewReader(gr)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read tar archive: %w", err)
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		name := filepath.FromSlash(header.Name)
+		if filepath.IsAbs(name) || !filepath.IsLocal(name) {
+			return fmt.Errorf("invalid path in archive: %s", header.Name)
+		}
+		target := filepath.Join(destDir, name)
+		if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+			return err
+		}
+		file, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+		if err != nil {
+			return err
+		}
+		if _, err := io.Copy(file, tr); err != nil {
+			file.Close()
+			return fmt.Errorf("failed to extract %s: %w", header.Name, err)
+		}
+		if err := file.Close(); err != nil {
+			return err
+		}
+	}
+}
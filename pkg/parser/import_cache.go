@@ -5,8 +5,10 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 
+	"github.com/github/gh-aw/pkg/gitutil"
 	"github.com/github/gh-aw/pkg/logger"
 )
 
@@ -54,7 +56,31 @@ func validatePathComponents(owner, repo, path, sha string) error {
 
 // ImportCache manages cached imported workflow files
 type ImportCache struct {
-	baseDir string // Base directory for cache (typically repo root)
+	baseDir      string               // Base directory for cache (typically repo root)
+	offline      bool                 // If true, remote imports are only served from the cache
+	offlineRefs  map[string]string    // Ref to commit SHA mappings used in offline mode (key: "owner/repo@ref")
+	resolvedRefs []ImportRef          // Remote imports resolved by this cache instance
+	cachedRefs   map[string]ImportRef // Resolved remote imports by cached file path
+	pinnedRefs   map[string]string    // Commit SHAs used instead of resolving refs (key: "owner/repo/path@ref")
+}
+
+// ImportRef records a remote import resolved to a commit SHA
+type ImportRef struct {
+	Owner string `json:"owner"`
+	Repo  string `json:"repo"`
+	Path  string `json:"path"`
+	Ref   string `json:"ref"`
+	SHA   string `json:"sha"`
+}
+
+// Key returns the "owner/repo@ref" key used to look up the ref in offline mode
+func (r ImportRef) Key() string {
+	return r.Owner + "/" + r.Repo + "@" + r.Ref
+}
+
+// Spec returns the "owner/repo/path@ref" workflowspec of the import
+func (r ImportRef) Spec() string {
+	return r.Owner + "/" + r.Repo + "/" + r.Path + "@" + r.Ref
 }
 
 // NewImportCache creates a new import cache instance
@@ -136,6 +162,63 @@ func (c *ImportCache) Set(owner, repo, path, sha string, content []byte) (string
 	return fullCachePath, nil
 }
 
+// SetOffline serves remote imports only from the cache, resolving refs to commit SHAs
+// with the given mappings (key: "owner/repo@ref") instead of querying GitHub
+func (c *ImportCache) SetOffline(refs map[string]string) {
+	importCacheLog.Printf("Enabling offline mode with %d ref mappings", len(refs))
+	c.offline = true
+	c.offlineRefs = refs
+}
+
+// IsOffline returns true when remote imports are only served from the cache
+func (c *ImportCache) IsOffline() bool {
+	return c.offline
+}
+
+// lookupOfflineRef returns the commit SHA recorded for a ref in offline mode
+func (c *ImportCache) lookupOfflineRef(owner, repo, ref string) (string, bool) {
+	if gitutil.IsHexString(ref) && len(ref) == 40 {
+		return ref, true
+	}
+	sha, ok := c.offlineRefs[ImportRef{Owner: owner, Repo: repo, Ref: ref}.Key()]
+	return sha, ok
+}
+
+// PinRefs resolves remote imports to the given commit SHAs (key: "owner/repo/path@ref")
+// instead of querying GitHub for the commit their refs currently point to
+func (c *ImportCache) PinRefs(refs map[string]string) {
+	importCacheLog.Printf("Pinning %d remote imports to recorded commit SHAs", len(refs))
+	c.pinnedRefs = refs
+}
+
+// lookupPinnedRef returns the commit SHA a remote import is pinned to
+func (c *ImportCache) lookupPinnedRef(ref ImportRef) (string, bool) {
+	sha, ok := c.pinnedRefs[ref.Spec()]
+	return sha, ok
+}
+
+// recordResolvedRef records a remote import resolved to a commit SHA and cached at cachedPath
+func (c *ImportCache) recordResolvedRef(ref ImportRef, cachedPath string) {
+	if !slices.Contains(c.resolvedRefs, ref) {
+		c.resolvedRefs = append(c.resolvedRefs, ref)
+	}
+	if c.cachedRefs == nil {
+		c.cachedRefs = make(map[string]ImportRef)
+	}
+	c.cachedRefs[cachedPath] = ref
+}
+
+// RefForCachedPath returns the remote import that was resolved to the given cached file
+func (c *ImportCache) RefForCachedPath(cachedPath string) (ImportRef, bool) {
+	ref, ok := c.cachedRefs[cachedPath]
+	return ref, ok
+}
+
+// ResolvedRefs returns the remote imports resolved by this cache instance
+func (c *ImportCache) ResolvedRefs() []ImportRef {
+	return c.resolvedRefs
+}
+
 // GetCacheDir returns the base cache directory path
 func (c *ImportCache) GetCacheDir() string {
 	return filepath.Join(c.baseDir, ImportCacheDir)
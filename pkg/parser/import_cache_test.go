@@ -287,3 +287,68 @@ func TestImportCacheSet_Validation(t *testing.T) {
 		})
 	}
 }
+
+func TestImportCacheOffline(t *testing.T) {
+	tempDir := t.TempDir()
+	sha := "0123456789abcdef0123456789abcdef01234567"
+
+	cache := NewImportCache(tempDir)
+	cachedPath, err := cache.Set("testowner", "testrepo", "shared/tools.md", sha, []byte("# Tools"))
+	require.NoError(t, err)
+	cache.SetOffline(map[string]string{"testowner/testrepo@v1": sha})
+	assert.True(t, cache.IsOffline())
+
+	t.Run("recorded ref", func(t *testing.T) {
+		path, err := downloadIncludeFromWorkflowSpec("testowner/testrepo/shared/tools.md@v1", cache)
+		require.NoError(t, err)
+		assert.Equal(t, cachedPath, path)
+
+		ref, ok := cache.RefForCachedPath(path)
+		require.True(t, ok, "offline imports should be recorded")
+		assert.Equal(t, ImportRef{Owner: "testowner", Repo: "testrepo", Path: "shared/tools.md", Ref: "v1", SHA: sha}, ref)
+	})
+
+	t.Run("full SHA ref", func(t *testing.T) {
+		path, err := downloadIncludeFromWorkflowSpec("testowner/testrepo/shared/tools.md@"+sha, cache)
+		require.NoError(t, err)
+		assert.Equal(t, cachedPath, path)
+	})
+
+	t.Run("unrecorded ref", func(t *testing.T) {
+		_, err := downloadIncludeFromWorkflowSpec("testowner/testrepo/shared/tools.md@main", cache)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "is not in the offline bundle: ref 'main' of testowner/testrepo was not recorded")
+	})
+
+	t.Run("uncached file", func(t *testing.T) {
+		_, err := downloadIncludeFromWorkflowSpec("testowner/testrepo/shared/other.md@v1", cache)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "testowner/testrepo/shared/other.md@"+sha+" was not cached")
+	})
+}
+
+func TestImportCachePinRefs(t *testing.T) {
+	tempDir := t.TempDir()
+	sha := "0123456789abcdef0123456789abcdef01234567"
+
+	cache := NewImportCache(tempDir)
+	cachedPath, err := cache.Set("testowner", "testrepo", "shared/tools.md", sha, []byte("# Tools"))
+	require.NoError(t, err)
+	cache.PinRefs(map[string]string{"testowner/testrepo/shared/tools.md@main": sha})
+
+	// The pinned SHA is used instead of resolving the branch, so the cached file is found without network access
+	path, err := downloadIncludeFromWorkflowSpec("testowner/testrepo/shared/tools.md@main", cache)
+	require.NoError(t, err)
+	assert.Equal(t, cachedPath, path)
+
+	ref, ok := cache.RefForCachedPath(path)
+	require.True(t, ok)
+	assert.Equal(t, sha, ref.SHA)
+	assert.Equal(t, []ImportRef{ref}, cache.ResolvedRefs())
+}
+
+func TestImportRefKey(t *testing.T) {
+	ref := ImportRef{Owner: "githubnext", Repo: "agentics", Path: "shared/mcp.md", Ref: "v1", SHA: "abc"}
+	assert.Equal(t, "githubnext/agentics@v1", ref.Key())
+	assert.Equal(t, "githubnext/agentics/shared/mcp.md@v1", ref.Spec())
+}
@@ -176,3 +176,34 @@ func findImportItemLocation(yamlContent string, importPath string) (line int, co
 	// Fallback to imports field location
 	return findImportsFieldLocation(yamlContent)
 }
+
+// findImportInputLocation finds the line and column of an input passed to an import item,
+// falling back to the import item itself when the input is not found
+func findImportInputLocation(yamlContent string, importPath string, inputName string) (line int, column int) {
+	importLine, importColumn := findImportItemLocation(yamlContent, importPath)
+	if inputName == "" {
+		return importLine, importColumn
+	}
+
+	lines := strings.Split(yamlContent, "\n")
+	itemIndent := -1
+	for i := importLine; i < len(lines); i++ {
+		text := lines[i]
+		trimmed := strings.TrimSpace(text)
+		if trimmed == "" {
+			continue
+		}
+		indent := len(text) - len(strings.TrimLeft(text, " \t"))
+		if itemIndent < 0 {
+			itemIndent = indent
+		}
+		// Stop at the next import item or the end of the imports section
+		if indent < itemIndent || strings.HasPrefix(trimmed, "- ") || trimmed == "---" {
+			break
+		}
+		if strings.HasPrefix(trimmed, inputName+":") {
+			return i + 1, indent + 1
+		}
+	}
+	return importLine, importColumn
+}
@@ -0,0 +1,534 @@
+package parser
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/github/gh-aw/pkg/logger"
+)
+
+var importInputsLog = logger.New("parser:import_inputs")
+
+// importInputTypes lists the supported import input types
+var importInputTypes = []string{"string", "choice", "boolean", "number", "array", "object"}
+
+// importInputReferenceRegex matches ${{ github.aw.inputs.<name> }} references in input defaults,
+// including ${{ github.aw.inputs.<name>.<property> }} for object inputs
+var importInputReferenceRegex = regexp.MustCompile(`\$\{\{\s*github\.aw\.inputs\.([a-zA-Z0-9_-]+)((?:\.[a-zA-Z0-9_-]+)*)\s*\}\}`)
+
+// ImportInputDefinition defines an input parameter for a shared workflow import.
+// It extends the workflow_dispatch input schema (string, choice, boolean, number) with
+// arrays, objects and value constraints.
+// The parser package uses map[string]any for actual parsing to avoid circular dependencies.
+type ImportInputDefinition struct {
+	Description string   `yaml:"description,omitempty" json:"description,omitempty"`
+	Required    bool     `yaml:"required,omitempty" json:"required,omitempty"`
+	Default     any      `yaml:"default,omitempty" json:"default,omitempty"` // Dynamic type from YAML; string defaults may reference other inputs
+	Type        string   `yaml:"type,omitempty" json:"type,omitempty"`       // "string", "choice", "boolean", "number", "array", "object"
+	Options     []string `yaml:"options,omitempty" json:"options,omitempty"` // Options for choice type
+	Enum        []any    `yaml:"enum,omitempty" json:"enum,omitempty"`       // Allowed values for string and number types
+	Pattern     string   `yaml:"pattern,omitempty" json:"pattern,omitempty"` // Regular expression a string value must match
+	Min         *float64 `yaml:"min,omitempty" json:"min,omitempty"`         // Minimum number value, string length or array length
+	Max         *float64 `yaml:"max,omitempty" json:"max,omitempty"`         // Maximum number value, string length or array length
+
+	Items      *ImportInputDefinition            `yaml:"items,omitempty" json:"items,omitempty"`           // Item definition for array type
+	Properties map[string]*ImportInputDefinition `yaml:"properties,omitempty" json:"properties,omitempty"` // Property definitions for object type
+}
+
+// ImportInputError is a validation error for the inputs passed to an import
+type ImportInputError struct {
+	Input   string // Top-level input name the error refers to ("" when it applies to the import)
+	Message string
+}
+
+// Error returns the error message
+func (e *ImportInputError) Error() string {
+	return e.Message
+}
+
+// inputType returns the effective type of an input definition (string by default)
+func (d *ImportInputDefinition) inputType() string {
+	if d.Type == "" {
+		return "string"
+	}
+	return d.Type
+}
+
+// ParseImportInputDefinitions parses and validates the inputs section of a shared workflow's frontmatter.
+// Returns nil when the shared workflow declares no inputs.
+func ParseImportInputDefinitions(frontmatter map[string]any) (map[string]*ImportInputDefinition, error) {
+	inputsValue, ok := frontmatter["inputs"]
+	if !ok || inputsValue == nil {
+		return nil, nil
+	}
+	inputsMap, ok := inputsValue.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("'inputs' must be an object, got %T", inputsValue)
+	}
+
+	// Round-trip through JSON to decode the nested definitions
+	content, err := json.Marshal(inputsMap)
+	if err != nil {
+		return nil, fmt.Errorf("invalid 'inputs' section: %w", err)
+	}
+	var definitions map[string]*ImportInputDefinition
+	if err := json.Unmarshal(content, &definitions); err != nil {
+		return nil, fmt.Errorf("invalid 'inputs' section: %w", err)
+	}
+
+	for _, name := range sortedInputNames(definitions) {
+		if definitions[name] == nil {
+			definitions[name] = &ImportInputDefinition{}
+		}
+		if err := validateImportInputDefinition("inputs."+name, definitions[name]); err != nil {
+			return nil, err
+		}
+	}
+
+	importInputsLog.Printf("Parsed %d import input definitions", len(definitions))
+	return definitions, nil
+}
+
+// validateImportInputDefinition checks that a definition is consistent, including its default value
+func validateImportInputDefinition(path string, def *ImportInputDefinition) error {
+	inputType := def.inputType()
+	if !slices.Contains(importInputTypes, inputType) {
+		return fmt.Errorf("%s: unknown type '%s' (valid types: %s)", path, def.Type, strings.Join(importInputTypes, ", "))
+	}
+	if inputType == "choice" && len(def.Options) == 0 {
+		return fmt.Errorf("%s: choice inputs require options", path)
+	}
+	if def.Pattern != "" {
+		if inputType != "string" {
+			return fmt.Errorf("%s: pattern is only supported for string inputs", path)
+		}
+		if _, err := regexp.Compile(def.Pattern); err != nil {
+			return fmt.Errorf("%s: invalid pattern: %w", path, err)
+		}
+	}
+	if len(def.Enum) > 0 && inputType != "string" && inputType != "number" {
+		return fmt.Errorf("%s: enum is only supported for string and number inputs", path)
+	}
+	if (def.Min != nil || def.Max != nil) && inputType != "string" && inputType != "number" && inputType != "array" {
+		return fmt.Errorf("%s: min and max are only supported for string, number and array inputs", path)
+	}
+	if def.Min != nil && def.Max != nil && *def.Min > *def.Max {
+		return fmt.Errorf("%s: min (%v) is greater than max (%v)", path, *def.Min, *def.Max)
+	}
+	if def.Items != nil {
+		if inputType != "array" {
+			return fmt.Errorf("%s: items is only supported for array inputs", path)
+		}
+		if err := validateImportInputDefinition(path+".items", def.Items); err != nil {
+			return err
+		}
+	}
+	if len(def.Properties) > 0 {
+		if inputType != "object" {
+			return fmt.Errorf("%s: properties is only supported for object inputs", path)
+		}
+		for _, name := range sortedInputNames(def.Properties) {
+			if def.Properties[name] == nil {
+				def.Properties[name] = &ImportInputDefinition{}
+			}
+			if err := validateImportInputDefinition(path+".properties."+name, def.Properties[name]); err != nil {
+				return err
+			}
+		}
+	}
+
+	// Defaults that reference other inputs are checked once they are resolved
+	if def.Default != nil && !referencesImportInputs(def.Default) {
+		if _, err := validateImportInputValue("default", def, def.Default); err != nil {
+			return fmt.Errorf("%s: invalid default: %w", path, err)
+		}
+	}
+	return nil
+}
+
+// ResolveImportInputs validates the input values passed to an import against the shared
+// workflow's definitions and fills in defaults. Defaults may reference other inputs with
+// ${{ github.aw.inputs.<name> }}.
+func ResolveImportInputs(definitions map[string]*ImportInputDefinition, values map[string]any) (map[string]any, error) {
+	for _, name := range sortedInputNames(values) {
+		if _, ok := definitions[name]; !ok {
+			return nil, &ImportInputError{
+				Input:   name,
+				Message: fmt.Sprintf("unknown input '%s' (declared inputs: %s)", name, strings.Join(sortedInputNames(definitions), ", ")),
+			}
+		}
+	}
+
+	resolved := make(map[string]any)
+	for _, name := range sortedInputNames(definitions) {
+		value, ok := values[name]
+		if !ok {
+			continue
+		}
+		converted, err := validateImportInputValue(name, definitions[name], value)
+		if err != nil {
+			return nil, &ImportInputError{Input: name, Message: "invalid input " + err.Error()}
+		}
+		resolved[name] = converted
+	}
+
+	resolver := &importInputDefaultResolver{definitions: definitions, resolved: resolved, resolving: make(map[string]bool)}
+	for _, name := range sortedInputNames(definitions) {
+		if _, err := resolver.resolve(name); err != nil {
+			return nil, err
+		}
+	}
+
+	importInputsLog.Printf("Resolved %d import inputs (%d passed explicitly)", len(resolved), len(values))
+	return resolved, nil
+}
+
+// importInputDefaultResolver resolves input defaults in dependency order
+type importInputDefaultResolver struct {
+	definitions map[string]*ImportInputDefinition
+	resolved    map[string]any
+	resolving   map[string]bool
+}
+
+// resolve returns the value of an input, resolving its default first if needed.
+// Returns nil for optional inputs without a value or default.
+func (r *importInputDefaultResolver) resolve(name string) (any, error) {
+	if value, ok := r.resolved[name]; ok {
+		return value, nil
+	}
+	def := r.definitions[name]
+	if def.Default == nil {
+		if def.Required {
+			return nil, &ImportInputError{Input: name, Message: fmt.Sprintf("missing required input '%s'", name)}
+		}
+		return nil, nil
+	}
+	if r.resolving[name] {
+		return nil, &ImportInputError{Input: name, Message: fmt.Sprintf("default of input '%s' references itself through other input defaults", name)}
+	}
+	r.resolving[name] = true
+	defer delete(r.resolving, name)
+
+	value := def.Default
+	if text, ok := value.(string); ok && referencesImportInputs(text) {
+		var err error
+		if value, err = r.interpolate(name, text); err != nil {
+			return nil, err
+		}
+		converted, err := validateImportInputValue(name, def, value)
+		if err != nil {
+			return nil, &ImportInputError{Input: name, Message: "invalid default for input " + err.Error()}
+		}
+		value = converted
+	}
+
+	r.resolved[name] = value
+	return value, nil
+}
+
+// interpolate substitutes input references in a default. A default that consists of a single
+// reference takes the referenced value with its type; otherwise values are formatted as text.
+func (r *importInputDefaultResolver) interpolate(name, text string) (any, error) {
+	references := importInputReferenceRegex.FindAllStringSubmatch(text, -1)
+	for _, reference := range references {
+		if _, ok := r.definitions[reference[1]]; !ok {
+			return nil, &ImportInputError{Input: name, Message: fmt.Sprintf("default of input '%s' references undeclared input '%s'", name, reference[1])}
+		}
+	}
+
+	if len(references) == 1 && strings.TrimSpace(text) == references[0][0] {
+		return r.resolveReference(name, references[0][1], references[0][2])
+	}
+
+	var resolveErr error
+	result := importInputReferenceRegex.ReplaceAllStringFunc(text, func(match string) string {
+		reference := importInputReferenceRegex.FindStringSubmatch(match)
+		value, err := r.resolveReference(name, reference[1], reference[2])
+		if err != nil {
+			if resolveErr == nil {
+				resolveErr = err
+			}
+			return match
+		}
+		return FormatImportInputValue(value)
+	})
+	if resolveErr != nil {
+		return nil, resolveErr
+	}
+	return result, nil
+}
+
+// resolveReference resolves an input, or a property path within an object input,
+// referenced from the default of another input
+func (r *importInputDefaultResolver) resolveReference(name, referenced, propertyPath string) (any, error) {
+	if _, err := r.resolve(referenced); err != nil {
+		return nil, err
+	}
+	value, ok := LookupImportInput(r.resolved, referenced+propertyPath)
+	if !ok || value == nil {
+		return nil, &ImportInputError{Input: name, Message: fmt.Sprintf("default of input '%s' references input '%s', which has no value", name, referenced+propertyPath)}
+	}
+	return value, nil
+}
+
+// validateImportInputValue checks a value against its definition and returns the value to use.
+// Numbers passed to string inputs are converted to text because YAML parses unquoted numbers.
+func validateImportInputValue(path string, def *ImportInputDefinition, value any) (any, error) {
+	switch def.inputType() {
+	case "string":
+		text, ok := value.(string)
+		if !ok {
+			number, isNumber := importInputNumber(value)
+			if !isNumber {
+				return nil, fmt.Errorf("'%s': expected a string, got %s", path, importInputValueKind(value))
+			}
+			text = strconv.FormatFloat(number, 'f', -1, 64)
+		}
+		if def.Pattern != "" && !regexp.MustCompile(def.Pattern).MatchString(text) {
+			return nil, fmt.Errorf("'%s': value %q does not match pattern %s", path, text, def.Pattern)
+		}
+		if err := checkImportInputRange(path, def, float64(utf8.RuneCountInString(text)), "length"); err != nil {
+			return nil, err
+		}
+		if err := checkImportInputEnum(path, def, text); err != nil {
+			return nil, err
+		}
+		return text, nil
+
+	case "choice":
+		text, ok := value.(string)
+		if !ok {
+			return nil, fmt.Errorf("'%s': expected one of %s, got %s", path, strings.Join(def.Options, ", "), importInputValueKind(value))
+		}
+		if !slices.Contains(def.Options, text) {
+			return nil, fmt.Errorf("'%s': value %q is not one of %s", path, text, strings.Join(def.Options, ", "))
+		}
+		return text, nil
+
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			return nil, fmt.Errorf("'%s': expected a boolean, got %s", path, importInputValueKind(value))
+		}
+		return value, nil
+
+	case "number":
+		number, ok := importInputNumber(value)
+		if !ok {
+			return nil, fmt.Errorf("'%s': expected a number, got %s", path, importInputValueKind(value))
+		}
+		if err := checkImportInputRange(path, def, number, "value"); err != nil {
+			return nil, err
+		}
+		if err := checkImportInputEnum(path, def, value); err != nil {
+			return nil, err
+		}
+		return value, nil
+
+	case "array":
+		items, ok := value.([]any)
+		if !ok {
+			return nil, fmt.Errorf("'%s': expected an array, got %s", path, importInputValueKind(value))
+		}
+		if err := checkImportInputRange(path, def, float64(len(items)), "item count"); err != nil {
+			return nil, err
+		}
+		if def.Items == nil {
+			return items, nil
+		}
+		converted := make([]any, len(items))
+		for i, item := range items {
+			itemValue, err := validateImportInputValue(fmt.Sprintf("%s[%d]", path, i), def.Items, item)
+			if err != nil {
+				return nil, err
+			}
+			converted[i] = itemValue
+		}
+		return converted, nil
+
+	case "object":
+		object, ok := value.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("'%s': expected an object, got %s", path, importInputValueKind(value))
+		}
+		if len(def.Properties) == 0 {
+			return object, nil
+		}
+		converted := make(map[string]any, len(object))
+		for _, key := range sortedInputNames(object) {
+			if _, ok := def.Properties[key]; !ok {
+				return nil, fmt.Errorf("'%s': unknown property '%s' (declared properties: %s)", path, key, strings.Join(sortedInputNames(def.Properties), ", "))
+			}
+		}
+		for _, key := range sortedInputNames(def.Properties) {
+			property := def.Properties[key]
+			propertyValue, ok := object[key]
+			if !ok {
+				if property.Default != nil {
+					converted[key] = property.Default
+				} else if property.Required {
+					return nil, fmt.Errorf("'%s': missing required property '%s'", path, key)
+				}
+				continue
+			}
+			convertedValue, err := validateImportInputValue(path+"."+key, property, propertyValue)
+			if err != nil {
+				return nil, err
+			}
+			converted[key] = convertedValue
+		}
+		return converted, nil
+	}
+	return value, nil
+}
+
+// checkImportInputRange checks a measured quantity against the min and max of a definition
+func checkImportInputRange(path string, def *ImportInputDefinition, measured float64, quantity string) error {
+	if def.Min != nil && measured < *def.Min {
+		return fmt.Errorf("'%s': %s %v is less than the minimum %v", path, quantity, measured, *def.Min)
+	}
+	if def.Max != nil && measured > *def.Max {
+		return fmt.Errorf("'%s': %s %v is greater than the maximum %v", path, quantity, measured, *def.Max)
+	}
+	return nil
+}
+
+// checkImportInputEnum checks that a value is one of the allowed enum values
+func checkImportInputEnum(path string, def *ImportInputDefinition, value any) error {
+	if len(def.Enum) == 0 {
+		return nil
+	}
+	formatted := FormatImportInputValue(value)
+	allowed := make([]string, 0, len(def.Enum))
+	for _, candidate := range def.Enum {
+		candidateText := FormatImportInputValue(candidate)
+		if candidateText == formatted {
+			return nil
+		}
+		allowed = append(allowed, candidateText)
+	}
+	return fmt.Errorf("'%s': value %s is not one of %s", path, formatted, strings.Join(allowed, ", "))
+}
+
+// importInputNumber converts a YAML number to float64
+func importInputNumber(value any) (float64, bool) {
+	switch v := value.(type) {
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case uint64:
+		return float64(v), true
+	case float64:
+		return v, true
+	}
+	return 0, false
+}
+
+// importInputValueKind describes the type of a value for error messages
+func importInputValueKind(value any) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case string:
+		return "a string"
+	case bool:
+		return "a boolean"
+	case []any:
+		return "an array"
+	case map[string]any:
+		return "an object"
+	}
+	if _, ok := importInputNumber(value); ok {
+		return "a number"
+	}
+	return fmt.Sprintf("%T", value)
+}
+
+// referencesImportInputs reports whether a default value references other inputs
+func referencesImportInputs(value any) bool {
+	text, ok := value.(string)
+	return ok && importInputReferenceRegex.MatchString(text)
+}
+
+// FormatImportInputValue formats an input value for substitution into markdown:
+// scalars as text, arrays and objects as compact JSON
+func FormatImportInputValue(value any) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case []any, map[string]any:
+		content, err := json.Marshal(v)
+		if err == nil {
+			return string(content)
+		}
+	}
+	return fmt.Sprintf("%v", value)
+}
+
+// LookupImportInput resolves a dotted input path such as "config.branch" in resolved import inputs
+func LookupImportInput(inputs map[string]any, path string) (any, bool) {
+	parts := strings.Split(path, ".")
+	value, exists := inputs[parts[0]]
+	for _, part := range parts[1:] {
+		if !exists {
+			break
+		}
+		object, ok := value.(map[string]any)
+		if !ok {
+			return nil, false
+		}
+		value, exists = object[part]
+	}
+	return value, exists
+}
+
+// sortedInputNames returns the keys of an inputs map in sorted order
+func sortedInputNames[V any](m map[string]V) []string {
+	names := make([]string, 0, len(m))
+	for name := range m {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// resolveImportItemInputs validates the inputs of a queued import against the shared workflow's
+// declared inputs. Errors point at the importing workflow when its source is available.
+// Returns nil when the shared workflow declares no inputs.
+func resolveImportItemInputs(item importQueueItem, frontmatter map[string]any, workflowFilePath, yamlContent string) (map[string]any, error) {
+	definitions, err := ParseImportInputDefinitions(frontmatter)
+	if err != nil {
+		return nil, fmt.Errorf("invalid inputs in imported file '%s': %w", item.importPath, err)
+	}
+	if definitions == nil {
+		return nil, nil
+	}
+
+	resolved, err := ResolveImportInputs(definitions, item.inputs)
+	if err == nil {
+		return resolved, nil
+	}
+
+	cause := fmt.Errorf("import '%s': %w", item.importPath, err)
+	if workflowFilePath == "" || yamlContent == "" {
+		return nil, cause
+	}
+	var inputName string
+	if inputErr, ok := err.(*ImportInputError); ok {
+		inputName = inputErr.Input
+	}
+	line, column := findImportInputLocation(yamlContent, item.importPath, inputName)
+	return nil, FormatImportError(&ImportError{
+		ImportPath: item.importPath,
+		FilePath:   workflowFilePath,
+		Line:       line,
+		Column:     column,
+		Cause:      cause,
+	}, yamlContent)
+}
@@ -0,0 +1,289 @@
+//go:build !integration
+
+package parser
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseImportInputDefinitions(t *testing.T) {
+	tests := []struct {
+		name    string
+		inputs  any
+		wantErr string
+	}{
+		{
+			name:   "no inputs",
+			inputs: nil,
+		},
+		{
+			name: "all types",
+			inputs: map[string]any{
+				"title":  map[string]any{"type": "string", "pattern": "^[A-Z]", "min": 3, "max": 40},
+				"mode":   map[string]any{"type": "choice", "options": []any{"fast", "slow"}},
+				"dry":    map[string]any{"type": "boolean", "default": false},
+				"count":  map[string]any{"type": "number", "min": 1, "max": 10, "default": 5},
+				"labels": map[string]any{"type": "array", "items": map[string]any{"type": "string"}, "max": 3},
+				"config": map[string]any{"type": "object", "properties": map[string]any{"region": map[string]any{"required": true}}},
+			},
+		},
+		{
+			name:    "unknown type",
+			inputs:  map[string]any{"x": map[string]any{"type": "date"}},
+			wantErr: "inputs.x: unknown type 'date'",
+		},
+		{
+			name:    "choice without options",
+			inputs:  map[string]any{"x": map[string]any{"type": "choice"}},
+			wantErr: "inputs.x: choice inputs require options",
+		},
+		{
+			name:    "pattern on number",
+			inputs:  map[string]any{"x": map[string]any{"type": "number", "pattern": "^1"}},
+			wantErr: "pattern is only supported for string inputs",
+		},
+		{
+			name:    "invalid pattern",
+			inputs:  map[string]any{"x": map[string]any{"pattern": "("}},
+			wantErr: "inputs.x: invalid pattern",
+		},
+		{
+			name:    "min greater than max",
+			inputs:  map[string]any{"x": map[string]any{"type": "number", "min": 5, "max": 1}},
+			wantErr: "min (5) is greater than max (1)",
+		},
+		{
+			name:    "items on object",
+			inputs:  map[string]any{"x": map[string]any{"type": "object", "items": map[string]any{}}},
+			wantErr: "items is only supported for array inputs",
+		},
+		{
+			name:    "nested property error",
+			inputs:  map[string]any{"x": map[string]any{"type": "object", "properties": map[string]any{"y": map[string]any{"type": "nope"}}}},
+			wantErr: "inputs.x.properties.y: unknown type 'nope'",
+		},
+		{
+			name:    "invalid default",
+			inputs:  map[string]any{"x": map[string]any{"type": "number", "max": 3, "default": 7}},
+			wantErr: "inputs.x: invalid default",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			frontmatter := map[string]any{}
+			if tt.inputs != nil {
+				frontmatter["inputs"] = tt.inputs
+			}
+			definitions, err := ParseImportInputDefinitions(frontmatter)
+			if tt.wantErr != "" {
+				require.Error(t, err, "definitions should be rejected")
+				assert.Contains(t, err.Error(), tt.wantErr, "error should describe the problem")
+				return
+			}
+			require.NoError(t, err, "definitions should parse")
+			if tt.inputs == nil {
+				assert.Nil(t, definitions, "no inputs should return nil")
+			}
+		})
+	}
+}
+
+func TestResolveImportInputs(t *testing.T) {
+	definitions, err := ParseImportInputDefinitions(map[string]any{
+		"inputs": map[string]any{
+			"repo":    map[string]any{"type": "string", "required": true, "pattern": "^[a-z-]+/[a-z-]+$"},
+			"branch":  map[string]any{"type": "string", "default": "main", "enum": []any{"main", "dev"}},
+			"count":   map[string]any{"type": "number", "min": 1, "max": 100, "default": 10},
+			"mode":    map[string]any{"type": "choice", "options": []any{"fast", "slow"}, "default": "fast"},
+			"labels":  map[string]any{"type": "array", "items": map[string]any{"type": "string", "max": 10}, "max": 2},
+			"config":  map[string]any{"type": "object", "properties": map[string]any{"region": map[string]any{"required": true}, "zone": map[string]any{"default": "a"}}},
+			"version": map[string]any{"type": "string"},
+			"ref":     map[string]any{"type": "string", "default": "${{ github.aw.inputs.repo }}@${{ github.aw.inputs.branch }}"},
+			"limit":   map[string]any{"type": "number", "default": "${{ github.aw.inputs.count }}"},
+		},
+	})
+	require.NoError(t, err, "definitions should parse")
+
+	tests := []struct {
+		name    string
+		values  map[string]any
+		want    map[string]any
+		wantErr string
+		input   string
+	}{
+		{
+			name:   "defaults and references",
+			values: map[string]any{"repo": "octo/hello"},
+			want: map[string]any{
+				"repo":   "octo/hello",
+				"branch": "main",
+				"count":  float64(10), // defaults are decoded from JSON
+				"mode":   "fast",
+				"ref":    "octo/hello@main",
+				"limit":  float64(10),
+			},
+		},
+		{
+			name:   "explicit values are converted",
+			values: map[string]any{"repo": "octo/hello", "version": 2, "count": 20, "labels": []any{"bug"}, "config": map[string]any{"region": "eu"}},
+			want: map[string]any{
+				"repo":    "octo/hello",
+				"branch":  "main",
+				"count":   20,
+				"mode":    "fast",
+				"labels":  []any{"bug"},
+				"config":  map[string]any{"region": "eu", "zone": "a"},
+				"version": "2",
+				"ref":     "octo/hello@main",
+				"limit":   20,
+			},
+		},
+		{
+			name:    "unknown input",
+			values:  map[string]any{"repo": "octo/hello", "colour": "red"},
+			wantErr: "unknown input 'colour'",
+			input:   "colour",
+		},
+		{
+			name:    "missing required input",
+			values:  map[string]any{},
+			wantErr: "missing required input 'repo'",
+			input:   "repo",
+		},
+		{
+			name:    "pattern mismatch",
+			values:  map[string]any{"repo": "not a repo"},
+			wantErr: "does not match pattern",
+			input:   "repo",
+		},
+		{
+			name:    "enum mismatch",
+			values:  map[string]any{"repo": "octo/hello", "branch": "feature"},
+			wantErr: "value feature is not one of main, dev",
+			input:   "branch",
+		},
+		{
+			name:    "number out of range",
+			values:  map[string]any{"repo": "octo/hello", "count": 500},
+			wantErr: "value 500 is greater than the maximum 100",
+			input:   "count",
+		},
+		{
+			name:    "wrong type",
+			values:  map[string]any{"repo": "octo/hello", "count": "many"},
+			wantErr: "'count': expected a number, got a string",
+			input:   "count",
+		},
+		{
+			name:    "too many items",
+			values:  map[string]any{"repo": "octo/hello", "labels": []any{"a", "b", "c"}},
+			wantErr: "item count 3 is greater than the maximum 2",
+			input:   "labels",
+		},
+		{
+			name:    "invalid item",
+			values:  map[string]any{"repo": "octo/hello", "labels": []any{true}},
+			wantErr: "'labels[0]': expected a string",
+			input:   "labels",
+		},
+		{
+			name:    "missing required property",
+			values:  map[string]any{"repo": "octo/hello", "config": map[string]any{}},
+			wantErr: "missing required property 'region'",
+			input:   "config",
+		},
+		{
+			name:    "unknown property",
+			values:  map[string]any{"repo": "octo/hello", "config": map[string]any{"region": "eu", "size": 3}},
+			wantErr: "unknown property 'size'",
+			input:   "config",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resolved, err := ResolveImportInputs(definitions, tt.values)
+			if tt.wantErr != "" {
+				require.Error(t, err, "inputs should be rejected")
+				assert.Contains(t, err.Error(), tt.wantErr, "error should describe the problem")
+				var inputErr *ImportInputError
+				require.ErrorAs(t, err, &inputErr, "error should be an ImportInputError")
+				assert.Equal(t, tt.input, inputErr.Input, "error should name the offending input")
+				return
+			}
+			require.NoError(t, err, "inputs should resolve")
+			assert.Equal(t, tt.want, resolved, "resolved inputs should match")
+		})
+	}
+}
+
+func TestResolveImportInputsDefaultCycle(t *testing.T) {
+	definitions, err := ParseImportInputDefinitions(map[string]any{
+		"inputs": map[string]any{
+			"a": map[string]any{"default": "${{ github.aw.inputs.b }}"},
+			"b": map[string]any{"default": "x-${{ github.aw.inputs.a }}"},
+		},
+	})
+	require.NoError(t, err, "definitions should parse")
+
+	_, err = ResolveImportInputs(definitions, nil)
+	require.Error(t, err, "cyclic defaults should be rejected")
+	assert.Contains(t, err.Error(), "references itself", "error should describe the cycle")
+
+	resolved, err := ResolveImportInputs(definitions, map[string]any{"a": "value"})
+	require.NoError(t, err, "passing a value breaks the cycle")
+	assert.Equal(t, "x-value", resolved["b"], "default should interpolate the passed value")
+}
+
+func TestResolveImportInputsPropertyReference(t *testing.T) {
+	definitions, err := ParseImportInputDefinitions(map[string]any{
+		"inputs": map[string]any{
+			"config": map[string]any{"type": "object"},
+			"region": map[string]any{"default": "${{ github.aw.inputs.config.region }}"},
+		},
+	})
+	require.NoError(t, err, "definitions should parse")
+
+	resolved, err := ResolveImportInputs(definitions, map[string]any{"config": map[string]any{"region": "eu"}})
+	require.NoError(t, err, "property reference should resolve")
+	assert.Equal(t, "eu", resolved["region"], "default should take the property value")
+
+	_, err = ResolveImportInputs(definitions, nil)
+	require.Error(t, err, "reference to a missing value should be rejected")
+	assert.Contains(t, err.Error(), "references input 'config.region', which has no value", "error should name the reference")
+}
+
+func TestFormatImportInputValue(t *testing.T) {
+	assert.Equal(t, "text", FormatImportInputValue("text"), "strings are unchanged")
+	assert.Equal(t, "42", FormatImportInputValue(42), "numbers are formatted as text")
+	assert.Equal(t, "true", FormatImportInputValue(true), "booleans are formatted as text")
+	assert.JSONEq(t, `["a","b"]`, FormatImportInputValue([]any{"a", "b"}), "arrays are formatted as JSON")
+	assert.JSONEq(t, `{"k":1}`, FormatImportInputValue(map[string]any{"k": 1}), "objects are formatted as JSON")
+}
+
+func TestFindImportInputLocation(t *testing.T) {
+	content := `---
+on: issues
+imports:
+  - shared/other.md
+  - path: shared/fetch.md
+    inputs:
+      repo: octo/hello
+      count: 500
+  - path: shared/next.md
+    inputs:
+      count: 1
+---
+`
+	line, column := findImportInputLocation(content, "shared/fetch.md", "count")
+	assert.Equal(t, 8, line, "should point at the input line")
+	assert.Equal(t, 7, column, "should point at the input key")
+
+	importLine, _ := findImportItemLocation(content, "shared/fetch.md")
+	line, _ = findImportInputLocation(content, "shared/fetch.md", "missing")
+	assert.Equal(t, importLine, line, "unknown inputs should fall back to the import item")
+}
@@ -18,33 +18,36 @@ var importLog = logger.New("parser:import_processor")
 
 // ImportsResult holds the result of processing imports from frontmatter
 type ImportsResult struct {
-	MergedTools         string           // Merged tools configuration from all imports
-	MergedMCPServers    string           // Merged mcp-servers configuration from all imports
-	MergedEngines       []string         // Merged engine configurations from all imports
-	MergedSafeOutputs   []string         // Merged safe-outputs configurations from all imports
-	MergedSafeInputs    []string         // Merged safe-inputs configurations from all imports
-	MergedMarkdown      string           // Only contains imports WITH inputs (for compile-time substitution)
-	ImportPaths         []string         // List of import file paths for runtime-import macro generation (replaces MergedMarkdown)
-	MergedSteps         string           // Merged steps configuration from all imports (excluding copilot-setup-steps)
-	CopilotSetupSteps   string           // Steps from copilot-setup-steps.yml (inserted at start)
-	MergedRuntimes      string           // Merged runtimes configuration from all imports
-	MergedServices      string           // Merged services configuration from all imports
-	MergedNetwork       string           // Merged network configuration from all imports
-	MergedPermissions   string           // Merged permissions configuration from all imports
-	MergedSecretMasking string           // Merged secret-masking steps from all imports
-	MergedBots          []string         // Merged bots list from all imports (union of bot names)
-	MergedPlugins       []string         // Merged plugins list from all imports (union of plugin repos)
-	MergedSkipRoles     []string         // Merged skip-roles list from all imports (union of role names)
-	MergedSkipBots      []string         // Merged skip-bots list from all imports (union of usernames)
-	MergedPostSteps     string           // Merged post-steps configuration from all imports (appended in order)
-	MergedLabels        []string         // Merged labels from all imports (union of label names)
-	MergedCaches        []string         // Merged cache configurations from all imports (appended in order)
-	MergedJobs          string           // Merged jobs from imported YAML workflows (JSON format)
-	MergedFeatures      []map[string]any // Merged features configuration from all imports (parsed YAML structures)
-	ImportedFiles       []string         // List of imported file paths (for manifest)
-	AgentFile           string           // Path to custom agent file (if imported)
-	AgentImportSpec     string           // Original import specification for agent file (e.g., "owner/repo/path@ref")
-	RepositoryImports   []string         // List of repository imports (format: "owner/repo@ref") for .github folder merging
+	MergedTools         string               // Merged tools configuration from all imports
+	MergedMCPServers    string               // Merged mcp-servers configuration from all imports
+	MergedEngines       []string             // Merged engine configurations from all imports
+	MergedSafeOutputs   []string             // Merged safe-outputs configurations from all imports
+	MergedSafeInputs    []string             // Merged safe-inputs configurations from all imports
+	MergedMarkdown      string               // Only contains imports WITH inputs (for compile-time substitution)
+	ImportPaths         []string             // List of import file paths for runtime-import macro generation (replaces MergedMarkdown)
+	MergedSteps         string               // Merged steps configuration from all imports (excluding copilot-setup-steps)
+	CopilotSetupSteps   string               // Steps from copilot-setup-steps.yml (inserted at start)
+	MergedRuntimes      string               // Merged runtimes configuration from all imports
+	MergedServices      string               // Merged services configuration from all imports
+	MergedNetwork       string               // Merged network configuration from all imports
+	MergedPermissions   string               // Merged permissions configuration from all imports
+	MergedSecretMasking string               // Merged secret-masking steps from all imports
+	MergedBots          []string             // Merged bots list from all imports (union of bot names)
+	MergedPlugins       []string             // Merged plugins list from all imports (union of plugin repos)
+	MergedSkipRoles     []string             // Merged skip-roles list from all imports (union of role names)
+	MergedSkipBots      []string             // Merged skip-bots list from all imports (union of usernames)
+	MergedPostSteps     string               // Merged post-steps configuration from all imports (appended in order)
+	MergedLabels        []string             // Merged labels from all imports (union of label names)
+	MergedCaches        []string             // Merged cache configurations from all imports (appended in order)
+	MergedJobs          string               // Merged jobs from imported YAML workflows (JSON format)
+	MergedFeatures      []map[string]any     // Merged features configuration from all imports (parsed YAML structures)
+	ImportedFiles       []string             // List of imported file paths (for manifest)
+	ImportedFilePaths   map[string]string    // Resolved path on disk of each entry in ImportedFiles (remote imports are in the import cache)
+	Contributions       []ImportContribution // Per-import mergeable configuration (for merge directives and provenance)
+	AgentFile           string               // Path to custom agent file (if imported)
+	AgentImportSpec     string               // Original import specification for agent file (e.g., "owner/repo/path@ref")
+	RepositoryImports   []string             // List of repository imports (format: "owner/repo@ref") for .github folder merging
+	RemoteImports       []ImportRef          // Remote imports with the commit SHAs they were resolved to
 	// ImportInputs uses map[string]any because input values can be different types (string, number, boolean).
 	// This is parsed from YAML frontmatter where the structure is dynamic and not known at compile time.
 	// This is an appropriate use of 'any' for dynamic YAML/JSON data.
@@ -52,16 +55,19 @@ type ImportsResult struct {
 	ImportInputs map[string]any // Aggregated input values from all imports (key = input name, value = input value)
 }
 
-// ImportInputDefinition defines an input parameter for a shared workflow import.
-// Uses the same schema as workflow_dispatch inputs.
-// NOTE: This type matches workflow.InputDefinition which is the canonical type for input parameters.
-// The parser package uses map[string]any for actual parsing to avoid circular dependencies.
-type ImportInputDefinition struct {
-	Description string   `yaml:"description,omitempty" json:"description,omitempty"`
-	Required    bool     `yaml:"required,omitempty" json:"required,omitempty"`
-	Default     any      `yaml:"default,omitempty" json:"default,omitempty"` // Can be string, number, or boolean (dynamic type from YAML)
-	Type        string   `yaml:"type,omitempty" json:"type,omitempty"`       // "string", "choice", "boolean", "number"
-	Options     []string `yaml:"options,omitempty" json:"options,omitempty"` // Options for choice type
+// ImportContribution holds the mergeable configuration contributed by a single imported file.
+// Each field is the JSON encoding of the corresponding frontmatter section (empty when not set),
+// in the same form that is written to the Merged* fields of ImportsResult.
+type ImportContribution struct {
+	File        string // Import path relative to the repository root (e.g., ".github/workflows/shared/tools.md")
+	Path        string // Resolved path of the imported file on disk
+	Tools       string // Tools from the file and its @include directives (newline-separated JSON)
+	MCPServers  string // mcp-servers configuration
+	Network     string // network configuration
+	Permissions string // permissions configuration
+	SafeOutputs string // safe-outputs configuration
+	Runtimes    string // runtimes configuration
+	Features    string // features configuration
 }
 
 // ImportSpec represents a single import specification (either a string path or an object with path and inputs)
@@ -70,7 +76,7 @@ type ImportSpec struct {
 	// Inputs uses map[string]any because input values can be different types (string, number, boolean).
 	// This is parsed from YAML frontmatter and validated against the imported workflow's input definitions.
 	// This is an appropriate use of 'any' for dynamic YAML data. See scratchpad/go-type-patterns.md.
-	Inputs map[string]any // Optional input values to pass to the imported workflow (values are string, number, or boolean)
+	Inputs map[string]any // Optional input values to pass to the imported workflow (validated against the imported workflow's inputs section)
 }
 
 // ProcessImportsFromFrontmatter processes imports field from frontmatter
@@ -237,7 +243,8 @@ func processImportsFromFrontmatterWithManifestAndSource(frontmatter map[string]a
 	// Initialize BFS queue and visited set for cycle detection
 	var queue []importQueueItem
 	visited := make(map[string]bool)
-	processedOrder := []string{} // Track processing order for manifest
+	processedOrder := []string{}                 // Track processing order for manifest
+	importedFilePaths := make(map[string]string) // Track resolved path of each processed import
 
 	// Initialize result accumulators
 	var toolsBuilder strings.Builder
@@ -255,23 +262,25 @@ func processImportsFromFrontmatterWithManifestAndSource(frontmatter map[string]a
 	var engines []string
 	var safeOutputs []string
 	var safeInputs []string
-	var bots []string                     // Track unique bot names
-	botsSet := make(map[string]bool)      // Set for deduplicating bots
-	var plugins []string                  // Track unique plugin repos
-	pluginsSet := make(map[string]bool)   // Set for deduplicating plugins
-	var labels []string                   // Track unique labels
-	labelsSet := make(map[string]bool)    // Set for deduplicating labels
-	var skipRoles []string                // Track unique skip-roles
-	skipRolesSet := make(map[string]bool) // Set for deduplicating skip-roles
-	var skipBots []string                 // Track unique skip-bots
-	skipBotsSet := make(map[string]bool)  // Set for deduplicating skip-bots
-	var caches []string                   // Track cache configurations (appended in order)
-	var jobsBuilder strings.Builder       // Track jobs from imported YAML workflows
-	var features []map[string]any         // Track features configurations from imports (parsed structures)
-	var agentFile string                  // Track custom agent file
-	var agentImportSpec string            // Track agent import specification for remote imports
-	var repositoryImports []string        // Track repository-only imports for .github folder merging
-	importInputs := make(map[string]any)  // Aggregated input values from all imports
+	var bots []string                      // Track unique bot names
+	botsSet := make(map[string]bool)       // Set for deduplicating bots
+	var plugins []string                   // Track unique plugin repos
+	pluginsSet := make(map[string]bool)    // Set for deduplicating plugins
+	var labels []string                    // Track unique labels
+	labelsSet := make(map[string]bool)     // Set for deduplicating labels
+	var skipRoles []string                 // Track unique skip-roles
+	skipRolesSet := make(map[string]bool)  // Set for deduplicating skip-roles
+	var skipBots []string                  // Track unique skip-bots
+	skipBotsSet := make(map[string]bool)   // Set for deduplicating skip-bots
+	var caches []string                    // Track cache configurations (appended in order)
+	var jobsBuilder strings.Builder        // Track jobs from imported YAML workflows
+	var features []map[string]any          // Track features configurations from imports (parsed structures)
+	var agentFile string                   // Track custom agent file
+	var agentImportSpec string             // Track agent import specification for remote imports
+	var repositoryImports []string         // Track repository-only imports for .github folder merging
+	var remoteImports []ImportRef          // Track remote imports and their resolved commit SHAs
+	var contributions []ImportContribution // Track mergeable configuration per imported file
+	importInputs := make(map[string]any)   // Aggregated input values from all imports
 
 	// Seed the queue with initial imports
 	for _, importSpec := range importSpecs {
@@ -370,6 +379,14 @@ func processImportsFromFrontmatterWithManifestAndSource(frontmatter map[string]a
 
 		// Add to processing order
 		processedOrder = append(processedOrder, item.importPath)
+		importedFilePaths[item.importPath] = item.fullPath
+
+		// Record the commit SHA of remote imports for the lock file metadata
+		if cache != nil {
+			if ref, ok := cache.RefForCachedPath(item.fullPath); ok {
+				remoteImports = append(remoteImports, ref)
+			}
+		}
 
 		// Check if this is a custom agent file (any markdown file under .github/agents)
 		isAgentFile := strings.Contains(item.fullPath, "/.github/agents/") && strings.HasSuffix(strings.ToLower(item.fullPath), ".md")
@@ -486,6 +503,16 @@ func processImportsFromFrontmatterWithManifestAndSource(frontmatter map[string]a
 			// If frontmatter extraction fails, continue with other processing
 			log.Printf("Failed to extract frontmatter from %s: %v", item.fullPath, err)
 		} else if result.Frontmatter != nil {
+			// Validate the inputs passed to this import against the inputs it declares and apply defaults
+			resolvedInputs, err := resolveImportItemInputs(item, result.Frontmatter, workflowFilePath, yamlContent)
+			if err != nil {
+				return nil, err
+			}
+			if resolvedInputs != nil {
+				item.inputs = resolvedInputs
+				maps.Copy(importInputs, resolvedInputs)
+			}
+
 			// Check for nested imports field
 			if nestedImportsField, hasImports := result.Frontmatter["imports"]; hasImports {
 				var nestedImports []string
@@ -593,6 +620,7 @@ func processImportsFromFrontmatterWithManifestAndSource(frontmatter map[string]a
 			return nil, fmt.Errorf("failed to process imported file '%s': %w", item.fullPath, err)
 		}
 		toolsBuilder.WriteString(toolsContent + "\n")
+		contribution := ImportContribution{Tools: toolsContent}
 
 		// Track import path for runtime-import macro generation (only if no inputs)
 		// Imports with inputs must be inlined for compile-time substitution
@@ -604,6 +632,8 @@ func processImportsFromFrontmatterWithManifestAndSource(frontmatter map[string]a
 			// For files not under .github/, use the original import path
 			importRelPath = item.importPath
 		}
+		contribution.File = importRelPath
+		contribution.Path = item.fullPath
 
 		if len(item.inputs) == 0 {
 			// No inputs - use runtime-import macro
@@ -641,12 +671,14 @@ func processImportsFromFrontmatterWithManifestAndSource(frontmatter map[string]a
 		mcpServersContent, err := extractFrontmatterField(string(content), "mcp-servers", "{}")
 		if err == nil && mcpServersContent != "" && mcpServersContent != "{}" {
 			mcpServersBuilder.WriteString(mcpServersContent + "\n")
+			contribution.MCPServers = mcpServersContent
 		}
 
 		// Extract safe-outputs from imported file
 		safeOutputsContent, err := extractFrontmatterField(string(content), "safe-outputs", "{}")
 		if err == nil && safeOutputsContent != "" && safeOutputsContent != "{}" {
 			safeOutputs = append(safeOutputs, safeOutputsContent)
+			contribution.SafeOutputs = safeOutputsContent
 		}
 
 		// Extract safe-inputs from imported file
@@ -665,6 +697,7 @@ func processImportsFromFrontmatterWithManifestAndSource(frontmatter map[string]a
 		runtimesContent, err := extractFrontmatterField(string(content), "runtimes", "{}")
 		if err == nil && runtimesContent != "" && runtimesContent != "{}" {
 			runtimesBuilder.WriteString(runtimesContent + "\n")
+			contribution.Runtimes = runtimesContent
 		}
 
 		// Extract services from imported file
@@ -677,12 +710,14 @@ func processImportsFromFrontmatterWithManifestAndSource(frontmatter map[string]a
 		networkContent, err := extractFrontmatterField(string(content), "network", "{}")
 		if err == nil && networkContent != "" && networkContent != "{}" {
 			networkBuilder.WriteString(networkContent + "\n")
+			contribution.Network = networkContent
 		}
 
 		// Extract permissions from imported file
 		permissionsContent, err := ExtractPermissionsFromContent(string(content))
 		if err == nil && permissionsContent != "" && permissionsContent != "{}" {
 			permissionsBuilder.WriteString(permissionsContent + "\n")
+			contribution.Permissions = permissionsContent
 		}
 
 		// Extract secret-masking from imported file
@@ -801,7 +836,10 @@ func processImportsFromFrontmatterWithManifestAndSource(frontmatter map[string]a
 				features = append(features, featuresMap)
 				log.Printf("Extracted features from import: %d entries", len(featuresMap))
 			}
+			contribution.Features = featuresContent
 		}
+
+		contributions = append(contributions, contribution)
 	}
 
 	log.Printf("Completed BFS traversal. Processed %d imports in total", len(processedOrder))
@@ -839,9 +877,12 @@ func processImportsFromFrontmatterWithManifestAndSource(frontmatter map[string]a
 		MergedJobs:          jobsBuilder.String(),
 		MergedFeatures:      features,
 		ImportedFiles:       topologicalOrder,
+		ImportedFilePaths:   importedFilePaths,
+		Contributions:       contributions,
 		AgentFile:           agentFile,
 		AgentImportSpec:     agentImportSpec,
 		RepositoryImports:   repositoryImports,
+		RemoteImports:       remoteImports,
 		ImportInputs:        importInputs,
 	}, nil
 }
@@ -330,6 +330,12 @@ tools:
 				}
 			}
 
+			// Every imported file should have its resolved path recorded
+			for _, importedFile := range result.ImportedFiles {
+				assert.Equal(t, filepath.Join(tempDir, importedFile), result.ImportedFilePaths[importedFile],
+					"Resolved path of %s should be recorded", importedFile)
+			}
+
 			t.Logf("Expected order: %v", tt.expectedOrder)
 			t.Logf("Actual order:   %v", result.ImportedFiles)
 		})
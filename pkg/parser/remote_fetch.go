@@ -232,11 +232,35 @@ func downloadIncludeFromWorkflowSpec(spec string, cache *ImportCache) (string, e
 	filePath := strings.Join(slashParts[2:], "/")
 	remoteLog.Printf("Parsed workflowspec: owner=%s, repo=%s, file=%s, ref=%s", owner, repo, filePath, ref)
 
+	// In offline mode, imports must come from the cache using the recorded ref mappings
+	if cache != nil && cache.IsOffline() {
+		sha, ok := cache.lookupOfflineRef(owner, repo, ref)
+		if !ok {
+			return "", fmt.Errorf("remote import %s is not in the offline bundle: ref '%s' of %s/%s was not recorded", spec, ref, owner, repo)
+		}
+		cachedPath, found := cache.Get(owner, repo, filePath, sha)
+		if !found {
+			return "", fmt.Errorf("remote import %s is not in the offline bundle: %s/%s/%s@%s was not cached", spec, owner, repo, filePath, sha)
+		}
+		remoteLog.Printf("Using offline import: %s/%s/%s@%s (SHA: %s)", owner, repo, filePath, ref, sha)
+		cache.recordResolvedRef(ImportRef{Owner: owner, Repo: repo, Path: filePath, Ref: ref, SHA: sha}, cachedPath)
+		return cachedPath, nil
+	}
+
 	// Resolve ref to SHA for cache lookup
 	var sha string
+	downloadRef := ref
 	if cache != nil {
 		// Only resolve SHA if we're using the cache
-		resolvedSHA, err := resolveRefToSHA(owner, repo, ref)
+		var resolvedSHA string
+		var err error
+		if pinnedSHA, ok := cache.lookupPinnedRef(ImportRef{Owner: owner, Repo: repo, Path: filePath, Ref: ref}); ok {
+			// Use the recorded commit even if the ref has moved since
+			remoteLog.Printf("Using pinned SHA for %s: %s", spec, pinnedSHA)
+			resolvedSHA, downloadRef = pinnedSHA, pinnedSHA
+		} else {
+			resolvedSHA, err = resolveRefToSHA(owner, repo, ref)
+		}
 		if err != nil {
 			// If the error is an authentication error, propagate it immediately
 			lowerErr := strings.ToLower(err.Error())
@@ -250,14 +274,15 @@ func downloadIncludeFromWorkflowSpec(spec string, cache *ImportCache) (string, e
 			// Check cache using SHA
 			if cachedPath, found := cache.Get(owner, repo, filePath, sha); found {
 				remoteLog.Printf("Using cached import: %s/%s/%s@%s (SHA: %s)", owner, repo, filePath, ref, sha)
+				cache.recordResolvedRef(ImportRef{Owner: owner, Repo: repo, Path: filePath, Ref: ref, SHA: sha}, cachedPath)
 				return cachedPath, nil
 			}
 		}
 	}
 
 	// Download the file content from GitHub
-	remoteLog.Printf("Fetching file from GitHub: %s/%s/%s@%s", owner, repo, filePath, ref)
-	content, err := downloadFileFromGitHub(owner, repo, filePath, ref)
+	remoteLog.Printf("Fetching file from GitHub: %s/%s/%s@%s", owner, repo, filePath, downloadRef)
+	content, err := downloadFileFromGitHub(owner, repo, filePath, downloadRef)
 	if err != nil {
 		return "", fmt.Errorf("failed to download include from %s: %w", spec, err)
 	}
@@ -271,6 +296,7 @@ func downloadIncludeFromWorkflowSpec(spec string, cache *ImportCache) (string, e
 			// Don't fail the compilation, fall back to temp file
 		} else {
 			remoteLog.Printf("Successfully cached download at: %s", cachedPath)
+			cache.recordResolvedRef(ImportRef{Owner: owner, Repo: repo, Path: filePath, Ref: ref, SHA: sha}, cachedPath)
 			return cachedPath, nil
 		}
 	}
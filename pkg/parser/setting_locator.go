@@ -0,0 +1,80 @@
+package parser
+
+import (
+	"strings"
+
+	"github.com/github/gh-aw/pkg/logger"
+	"github.com/goccy/go-yaml/ast"
+	yamlparser "github.com/goccy/go-yaml/parser"
+)
+
+var settingLocatorLog = logger.New("parser:setting_locator")
+
+// LocateFrontmatterSetting finds the line of a frontmatter setting in the content of a markdown file.
+// keys is the path of the setting (e.g., ["tools", "github", "toolsets"]). When the setting is a list
+// and value is not empty, the line of the matching list entry is returned.
+//
+// If the full path is not present (e.g., the setting is written in a shorthand form), the location of
+// the deepest key that matched is returned. Found is false when not even the first key is present.
+func LocateFrontmatterSetting(content string, keys []string, value string) JSONPathLocation {
+	result, err := ExtractFrontmatterFromContent(content)
+	if err != nil || len(result.FrontmatterLines) == 0 {
+		return JSONPathLocation{}
+	}
+
+	file, err := yamlparser.ParseBytes([]byte(strings.Join(result.FrontmatterLines, "\n")), 0)
+	if err != nil || len(file.Docs) == 0 {
+		settingLocatorLog.Printf("Failed to parse frontmatter: %v", err)
+		return JSONPathLocation{}
+	}
+
+	location := JSONPathLocation{}
+	node := file.Docs[0].Body
+	for _, key := range keys {
+		entry := findMappingEntry(node, key)
+		if entry == nil {
+			return location
+		}
+		location = nodeLocation(entry.Key, result.FrontmatterStart)
+		node = entry.Value
+	}
+
+	if sequence, ok := node.(*ast.SequenceNode); ok && value != "" {
+		for _, item := range sequence.Values {
+			if token := item.GetToken(); token != nil && token.Value == value {
+				return nodeLocation(item, result.FrontmatterStart)
+			}
+		}
+	}
+	return location
+}
+
+// findMappingEntry returns the entry of a mapping node with the given key, or nil
+func findMappingEntry(node ast.Node, key string) *ast.MappingValueNode {
+	var entries []*ast.MappingValueNode
+	switch typed := node.(type) {
+	case *ast.MappingNode:
+		entries = typed.Values
+	case *ast.MappingValueNode:
+		entries = []*ast.MappingValueNode{typed}
+	}
+	for _, entry := range entries {
+		if token := entry.Key.GetToken(); token != nil && token.Value == key {
+			return entry
+		}
+	}
+	return nil
+}
+
+// nodeLocation converts the position of a frontmatter node to a location in the markdown file
+func nodeLocation(node ast.Node, frontmatterStart int) JSONPathLocation {
+	token := node.GetToken()
+	if token == nil || token.Position == nil {
+		return JSONPathLocation{}
+	}
+	return JSONPathLocation{
+		Line:   token.Position.Line + frontmatterStart - 1,
+		Column: token.Position.Column,
+		Found:  true,
+	}
+}
@@ -0,0 +1,53 @@
+//go:build !integration
+
+package parser
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLocateFrontmatterSetting(t *testing.T) {
+	content := `---
+on: issues
+tools:
+  github:
+    toolsets: [issues, pull_requests]
+  bash:
+    - "echo"
+    - "ls"
+"network": defaults
+---
+# Workflow
+`
+
+	tests := []struct {
+		name     string
+		keys     []string
+		value    string
+		wantLine int
+		found    bool
+	}{
+		{name: "top-level key", keys: []string{"on"}, wantLine: 2, found: true},
+		{name: "nested key", keys: []string{"tools", "github", "toolsets"}, wantLine: 5, found: true},
+		{name: "flow list entry", keys: []string{"tools", "github", "toolsets"}, value: "pull_requests", wantLine: 5, found: true},
+		{name: "block list entry", keys: []string{"tools", "bash"}, value: "ls", wantLine: 8, found: true},
+		{name: "unknown list entry", keys: []string{"tools", "bash"}, value: "rm", wantLine: 6, found: true},
+		{name: "quoted key", keys: []string{"network"}, wantLine: 9, found: true},
+		{name: "shorthand falls back to parent", keys: []string{"network", "allowed"}, wantLine: 9, found: true},
+		{name: "missing setting", keys: []string{"permissions"}, found: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			location := LocateFrontmatterSetting(content, tt.keys, tt.value)
+			assert.Equal(t, tt.found, location.Found, "found")
+			if tt.found {
+				assert.Equal(t, tt.wantLine, location.Line, "line")
+			}
+		})
+	}
+
+	assert.False(t, LocateFrontmatterSetting("# No frontmatter\n", []string{"on"}, "").Found, "content without frontmatter")
+}
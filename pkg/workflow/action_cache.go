@@ -16,6 +16,8 @@ var actionCacheLog = logger.New("workflow:action_cache")
 const (
 	// CacheFileName is the name of the cache file in .github/aw/.
 	CacheFileName = "actions-lock.json"
+	// GHESCacheFileName is the name of the cache file for actions mirrored to GitHub Enterprise Server
+	GHESCacheFileName = "actions-lock.ghes.json"
 )
 
 // ActionCacheEntry represents a cached action pin resolution.
@@ -34,7 +36,12 @@ type ActionCache struct {
 
 // NewActionCache creates a new action cache instance
 func NewActionCache(repoRoot string) *ActionCache {
-	cachePath := filepath.Join(repoRoot, ".github", "aw", CacheFileName)
+	return NewActionCacheWithFile(repoRoot, CacheFileName)
+}
+
+// NewActionCacheWithFile creates a new action cache instance backed by the given file in .github/aw/
+func NewActionCacheWithFile(repoRoot, fileName string) *ActionCache {
+	cachePath := filepath.Join(repoRoot, ".github", "aw", fileName)
 	actionCacheLog.Printf("Creating action cache with path: %s", cachePath)
 	return &ActionCache{
 		Entries: make(map[string]ActionCacheEntry),
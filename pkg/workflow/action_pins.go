@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"slices"
 	"sort"
 	"strings"
 	"sync"
@@ -178,6 +179,28 @@ func GetActionPinWithData(actionRepo, version string, data *WorkflowData) (strin
 			return result, nil
 		}
 		actionPinsLog.Printf("Dynamic resolution failed for %s@%s: %v", actionRepo, version, err)
+
+		// Offline bundles must contain every action pin that is not embedded in the binary;
+		// record the miss so compilation fails clearly
+		if data.ActionResolver.IsOffline() {
+			for _, pin := range getActionPins() {
+				if pin.Repo == actionRepo && pin.Version == version {
+					actionPinsLog.Printf("Using embedded pin for %s@%s in offline mode", actionRepo, version)
+					return formatActionReference(actionRepo, pin.SHA, pin.Version), nil
+				}
+			}
+			if !slices.Contains(data.MissingBundleActions, formatActionCacheKey(actionRepo, version)) {
+				data.MissingBundleActions = append(data.MissingBundleActions, formatActionCacheKey(actionRepo, version))
+			}
+			return "", nil
+		}
+
+		// Hardcoded pins come from github.com; on GHES the action must be in the mirror.
+		// Leave it unpinned so the mirror check reports it once the lock file is generated.
+		if data.CompileTarget.IsGHES() {
+			actionPinsLog.Printf("Action %s@%s not found in GHES mirror, leaving unpinned", actionRepo, version)
+			return "", nil
+		}
 	} else {
 		if isAlreadySHA {
 			actionPinsLog.Printf("Version is already a SHA, skipping dynamic resolution")
@@ -15,6 +15,8 @@ var resolverLog = logger.New("workflow:action_resolver")
 type ActionResolver struct {
 	cache             *ActionCache
 	failedResolutions map[string]bool // tracks failed resolution attempts in current run (key: "repo@version")
+	host              string          // GitHub host to query (empty for the gh default host)
+	offline           bool            // If true, only cached resolutions are used (offline bundle compilation)
 }
 
 // NewActionResolver creates a new action resolver
@@ -25,6 +27,19 @@ func NewActionResolver(cache *ActionCache) *ActionResolver {
 	}
 }
 
+// NewActionResolverForHost creates a new action resolver that queries the given GitHub host,
+// such as a GitHub Enterprise Server instance with mirrored actions
+func NewActionResolverForHost(cache *ActionCache, host string) *ActionResolver {
+	resolver := NewActionResolver(cache)
+	resolver.host = host
+	return resolver
+}
+
+// IsOffline returns true when the resolver only uses cached resolutions
+func (r *ActionResolver) IsOffline() bool {
+	return r.offline
+}
+
 // ResolveSHA resolves the SHA for a given action@version using GitHub CLI
 // Returns the SHA and an error if resolution fails
 func (r *ActionResolver) ResolveSHA(repo, version string) (string, error) {
@@ -45,6 +60,12 @@ func (r *ActionResolver) ResolveSHA(repo, version string) (string, error) {
 		return sha, nil
 	}
 
+	if r.offline {
+		resolverLog.Printf("Cache miss for %s@%s in offline mode", repo, version)
+		r.failedResolutions[cacheKey] = true
+		return "", fmt.Errorf("%s@%s is not pinned in the offline bundle", repo, version)
+	}
+
 	resolverLog.Printf("Cache miss for %s@%s, querying GitHub API", repo, version)
 	resolverLog.Printf("This may take a moment as we query GitHub API at /repos/%s/git/ref/tags/%s", extractBaseRepo(repo), version)
 
@@ -81,7 +102,11 @@ func (r *ActionResolver) resolveFromGitHub(repo, version string) (string, error)
 	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
 	defer cancel()
 
-	cmd := ExecGHContext(ctx, "api", apiPath, "--jq", ".object.sha")
+	args := []string{"api", apiPath, "--jq", ".object.sha"}
+	if r.host != "" {
+		args = append(args, "--hostname", r.host)
+	}
+	cmd := ExecGHContext(ctx, args...)
 	output, err := cmd.Output()
 	if err != nil {
 		// Try without "refs/tags/" prefix in case version is already a ref
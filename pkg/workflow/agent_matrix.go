@@ -0,0 +1,201 @@
+package workflow
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/github/gh-aw/pkg/logger"
+)
+
+var agentMatrixLog = logger.New("workflow:agent_matrix")
+
+// MatrixVariant is one engine/model variant of a matrix run
+type MatrixVariant struct {
+	ID            string
+	EngineSetting string
+	EngineConfig  *EngineConfig
+}
+
+// JobName returns the job id of a variant that runs next to the agent job
+func (v *MatrixVariant) JobName() string {
+	return "agent_" + strings.ReplaceAll(v.ID, "-", "_")
+}
+
+// DetectionJobName returns the job id of the variant's threat detection job
+func (v *MatrixVariant) DetectionJobName() string {
+	return "detection_" + strings.ReplaceAll(v.ID, "-", "_")
+}
+
+// MatrixConfig holds the matrix frontmatter configuration. Every variant runs the same
+// prompt in parallel. The applied variant (or the first one) runs in the agent job; the
+// other variants run in their own jobs and their safe outputs are only previewed.
+type MatrixConfig struct {
+	Variants []*MatrixVariant
+	Apply    string // id of the variant whose safe outputs are applied ("" = stage all)
+}
+
+// PrimaryVariant returns the variant that runs in the agent job: the applied variant,
+// or the first variant when safe outputs are only staged
+func (m *MatrixConfig) PrimaryVariant() *MatrixVariant {
+	if m == nil || len(m.Variants) == 0 {
+		return nil
+	}
+	for _, variant := range m.Variants {
+		if variant.ID == m.Apply {
+			return variant
+		}
+	}
+	return m.Variants[0]
+}
+
+// SecondaryVariants returns the variants that run in their own jobs
+func (m *MatrixConfig) SecondaryVariants() []*MatrixVariant {
+	primary := m.PrimaryVariant()
+	var variants []*MatrixVariant
+	for _, variant := range m.Variants {
+		if variant != primary {
+			variants = append(variants, variant)
+		}
+	}
+	return variants
+}
+
+// extractMatrixConfig extracts the matrix section from frontmatter
+func (c *Compiler) extractMatrixConfig(frontmatter map[string]any) *MatrixConfig {
+	matrixValue, exists := frontmatter["matrix"]
+	if !exists || matrixValue == nil {
+		return nil
+	}
+
+	matrixMap, ok := matrixValue.(map[string]any)
+	if !ok {
+		return nil
+	}
+
+	config := &MatrixConfig{}
+	if apply, ok := matrixMap["apply"].(string); ok {
+		config.Apply = apply
+	}
+	if variants, ok := matrixMap["variants"].([]any); ok {
+		for _, item := range variants {
+			variantMap, ok := item.(map[string]any)
+			if !ok {
+				continue
+			}
+			variant := &MatrixVariant{}
+			if id, ok := variantMap["id"].(string); ok {
+				variant.ID = id
+			}
+			if _, hasEngine := variantMap["engine"]; hasEngine {
+				variant.EngineSetting, variant.EngineConfig = c.ExtractEngineConfig(map[string]any{"engine": variantMap["engine"]})
+			}
+			config.Variants = append(config.Variants, variant)
+		}
+	}
+
+	agentMatrixLog.Printf("Extracted matrix with %d variants, apply=%q", len(config.Variants), config.Apply)
+	return config
+}
+
+// validateMatrixConfig validates variant ids and engines and the applied variant
+func (c *Compiler) validateMatrixConfig(data *WorkflowData) error {
+	matrix := data.Matrix
+	if matrix == nil {
+		return nil
+	}
+	if len(matrix.Variants) < 2 {
+		return fmt.Errorf("matrix.variants must list at least 2 variants, got %d", len(matrix.Variants))
+	}
+	if len(data.AgentStages) > 0 {
+		return fmt.Errorf("matrix cannot be combined with stages")
+	}
+
+	seen := make(map[string]bool)
+	for i, variant := range matrix.Variants {
+		if !agentStageIDPattern.MatchString(variant.ID) {
+			return fmt.Errorf("matrix.variants[%d]: invalid id %q. Variant ids must start with a lowercase letter and contain only lowercase letters, digits, '-' and '_'", i, variant.ID)
+		}
+		if seen[variant.ID] {
+			return fmt.Errorf("matrix.variants: duplicate variant id %q", variant.ID)
+		}
+		seen[variant.ID] = true
+
+		if variant.EngineSetting == "" {
+			return fmt.Errorf("matrix.variants.%s: engine is required", variant.ID)
+		}
+		if _, err := c.getAgenticEngine(variant.EngineSetting); err != nil {
+			return fmt.Errorf("matrix.variants.%s: %w", variant.ID, err)
+		}
+	}
+
+	if matrix.Apply != "" && !seen[matrix.Apply] {
+		return fmt.Errorf("matrix.apply: unknown variant %q", matrix.Apply)
+	}
+	return nil
+}
+
+// variantWorkflowData returns the workflow data used to build the job of a matrix variant.
+// Variants share the prompt, tools and safe outputs configuration of the workflow and only
+// change the engine. Memory belongs to the agent job only.
+func variantWorkflowData(data *WorkflowData, variant *MatrixVariant) *WorkflowData {
+	variantData := *data
+	variantData.AI = variant.EngineSetting
+	variantData.EngineConfig = variant.EngineConfig
+	variantData.CacheMemoryConfig = nil
+	variantData.RepoMemoryConfig = nil
+	variantData.MatrixVariant = variant
+	return &variantData
+}
+
+// buildMatrixVariantJobs builds an agent job and, when threat detection is enabled, a
+// detection job for every variant other than the primary one. Their safe outputs are
+// uploaded and scanned but never applied.
+func (c *Compiler) buildMatrixVariantJobs(data *WorkflowData, activationJobCreated bool) error {
+	if data.Matrix == nil {
+		return nil
+	}
+
+	for _, variant := range data.Matrix.SecondaryVariants() {
+		agentMatrixLog.Printf("Building job for matrix variant %s", variant.ID)
+		variantData := variantWorkflowData(data, variant)
+
+		// Each variant job is validated for step ordering on its own
+		mainTracker := c.stepOrderTracker
+		c.stepOrderTracker = NewStepOrderTracker()
+		job, err := c.buildMainJob(variantData, activationJobCreated)
+		c.stepOrderTracker = mainTracker
+		if err != nil {
+			return fmt.Errorf("failed to build job for matrix variant %s: %w", variant.ID, err)
+		}
+		job.Name = variant.JobName()
+		job.Concurrency = ""
+		if err := c.jobManager.AddJob(job); err != nil {
+			return fmt.Errorf("failed to add matrix variant job %s: %w", variant.ID, err)
+		}
+
+		if variantData.SafeOutputs == nil || variantData.SafeOutputs.ThreatDetection == nil {
+			continue
+		}
+		detectionJob, err := c.buildThreatDetectionJob(variantData, variant.JobName())
+		if err != nil {
+			return fmt.Errorf("failed to build detection job for matrix variant %s: %w", variant.ID, err)
+		}
+		detectionJob.Name = variant.DetectionJobName()
+		detectionJob.Concurrency = ""
+		if err := c.jobManager.AddJob(detectionJob); err != nil {
+			return fmt.Errorf("failed to add detection job for matrix variant %s: %w", variant.ID, err)
+		}
+	}
+	return nil
+}
+
+// matrixVariantID returns the id of the matrix variant a job runs, or "" without a matrix
+func matrixVariantID(data *WorkflowData) string {
+	if data.MatrixVariant != nil {
+		return data.MatrixVariant.ID
+	}
+	if primary := data.Matrix.PrimaryVariant(); primary != nil {
+		return primary.ID
+	}
+	return ""
+}
@@ -0,0 +1,208 @@
+//go:build !integration
+
+package workflow
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/github/gh-aw/pkg/stringutil"
+	"github.com/github/gh-aw/pkg/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExtractMatrixConfig(t *testing.T) {
+	compiler := NewCompiler()
+
+	matrix := compiler.extractMatrixConfig(map[string]any{
+		"matrix": map[string]any{
+			"apply": "codex",
+			"variants": []any{
+				map[string]any{"id": "claude", "engine": "claude"},
+				map[string]any{"id": "codex", "engine": map[string]any{"id": "codex", "model": "gpt-5"}},
+			},
+		},
+	})
+	require.NotNil(t, matrix, "Matrix should be extracted")
+	require.Len(t, matrix.Variants, 2, "Both variants should be extracted")
+
+	assert.Equal(t, "claude", matrix.Variants[0].EngineSetting, "Engine string should be extracted")
+	assert.Equal(t, "codex", matrix.Variants[1].EngineSetting, "Engine object should be extracted")
+	require.NotNil(t, matrix.Variants[1].EngineConfig, "Engine config should be extracted")
+	assert.Equal(t, "gpt-5", matrix.Variants[1].EngineConfig.Model, "Model should be extracted")
+
+	assert.Equal(t, "codex", matrix.PrimaryVariant().ID, "Applied variant should run in the agent job")
+	require.Len(t, matrix.SecondaryVariants(), 1, "Other variants should run in their own jobs")
+	assert.Equal(t, "agent_claude", matrix.SecondaryVariants()[0].JobName(), "Job name should be derived from the id")
+	assert.Equal(t, "detection_claude", matrix.SecondaryVariants()[0].DetectionJobName(), "Detection job name should be derived from the id")
+
+	matrix.Apply = ""
+	assert.Equal(t, "claude", matrix.PrimaryVariant().ID, "First variant should run in the agent job without apply")
+
+	assert.Nil(t, compiler.extractMatrixConfig(map[string]any{}), "Missing section should yield no matrix")
+}
+
+func TestValidateMatrixConfig(t *testing.T) {
+	claude := &MatrixVariant{ID: "claude", EngineSetting: "claude"}
+	codex := &MatrixVariant{ID: "codex", EngineSetting: "codex"}
+
+	tests := []struct {
+		name    string
+		data    *WorkflowData
+		wantErr string
+	}{
+		{
+			name: "valid matrix",
+			data: &WorkflowData{Matrix: &MatrixConfig{Variants: []*MatrixVariant{claude, codex}, Apply: "codex"}},
+		},
+		{
+			name:    "single variant",
+			data:    &WorkflowData{Matrix: &MatrixConfig{Variants: []*MatrixVariant{claude}}},
+			wantErr: "at least 2 variants",
+		},
+		{
+			name:    "combined with stages",
+			data:    &WorkflowData{Matrix: &MatrixConfig{Variants: []*MatrixVariant{claude, codex}}, AgentStages: []*AgentStage{{ID: "planner"}}},
+			wantErr: "cannot be combined with stages",
+		},
+		{
+			name:    "invalid id",
+			data:    &WorkflowData{Matrix: &MatrixConfig{Variants: []*MatrixVariant{claude, {ID: "Codex", EngineSetting: "codex"}}}},
+			wantErr: "invalid id",
+		},
+		{
+			name:    "duplicate id",
+			data:    &WorkflowData{Matrix: &MatrixConfig{Variants: []*MatrixVariant{claude, {ID: "claude", EngineSetting: "codex"}}}},
+			wantErr: "duplicate variant id",
+		},
+		{
+			name:    "missing engine",
+			data:    &WorkflowData{Matrix: &MatrixConfig{Variants: []*MatrixVariant{claude, {ID: "codex"}}}},
+			wantErr: "matrix.variants.codex: engine is required",
+		},
+		{
+			name:    "unknown engine",
+			data:    &WorkflowData{Matrix: &MatrixConfig{Variants: []*MatrixVariant{claude, {ID: "other", EngineSetting: "unknown-engine"}}}},
+			wantErr: "matrix.variants.other:",
+		},
+		{
+			name:    "unknown applied variant",
+			data:    &WorkflowData{Matrix: &MatrixConfig{Variants: []*MatrixVariant{claude, codex}, Apply: "copilot"}},
+			wantErr: "unknown variant \"copilot\"",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := NewCompiler().validateMatrixConfig(tt.data)
+			if tt.wantErr == "" {
+				assert.NoError(t, err, "Matrix should be valid")
+				return
+			}
+			require.Error(t, err, "Matrix should be invalid")
+			assert.Contains(t, err.Error(), tt.wantErr, "Error should describe the problem")
+		})
+	}
+}
+
+func TestAgentMatrixCompile(t *testing.T) {
+	tests := []struct {
+		name       string
+		apply      string
+		wantStaged bool
+		wantAgent  string
+	}{
+		{name: "staged without apply", wantStaged: true, wantAgent: "claude"},
+		{name: "apply winner", apply: "\n  apply: codex", wantStaged: false, wantAgent: "codex"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir := testutil.TempDir(t, "agent-matrix-compile")
+
+			workflow := `---
+on: workflow_dispatch
+permissions:
+  contents: read
+matrix:` + tt.apply + `
+  variants:
+    - id: claude
+      engine: claude
+    - id: codex
+      engine:
+        id: codex
+        model: gpt-5
+safe-outputs:
+  create-issue:
+---
+
+# Triage
+
+Summarize the open issues.
+`
+			workflowPath := filepath.Join(tmpDir, "matrix.md")
+			require.NoError(t, os.WriteFile(workflowPath, []byte(workflow), 0644))
+
+			compiler := NewCompiler()
+			require.NoError(t, compiler.CompileWorkflow(workflowPath), "Workflow with matrix should compile")
+
+			lockContent, err := os.ReadFile(stringutil.MarkdownToLockFile(workflowPath))
+			require.NoError(t, err, "Lock file should be written")
+			lock := string(lockContent)
+
+			other := "codex"
+			if tt.wantAgent == "codex" {
+				other = "claude"
+			}
+
+			agent := agentStagesJobSection(lock, "agent")
+			variant := agentStagesJobSection(lock, "agent_"+other)
+			detection := agentStagesJobSection(lock, "detection_"+other)
+			require.NotEmpty(t, variant, "Variant job should be generated")
+			require.NotEmpty(t, detection, "Variant detection job should be generated")
+
+			assert.Contains(t, agent, `matrix_variant: "`+tt.wantAgent+`"`, "Agent job should run the primary variant")
+			assert.Contains(t, agent, "name: agent-artifacts\n", "Agent job should keep the default artifact name")
+			assert.Contains(t, variant, `matrix_variant: "`+other+`"`, "Variant job should record its variant")
+			assert.Contains(t, variant, "name: agent-artifacts-"+other, "Variant artifacts should use their own name")
+			assert.Contains(t, variant, "name: agent-output-"+other, "Variant agent output should use its own name")
+			assert.NotContains(t, variant, "concurrency:", "Variant jobs should not share the agent concurrency group")
+
+			assert.Contains(t, detection, "needs: agent_"+other, "Detection should scan the variant job")
+			assert.Contains(t, detection, "name: agent-output-"+other, "Detection should download the variant output")
+
+			assert.NotContains(t, lock, "safe_outputs_"+other, "Variant safe outputs should never be applied")
+			if tt.wantStaged {
+				assert.Contains(t, lock, `GH_AW_SAFE_OUTPUTS_STAGED: "true"`, "Safe outputs should be staged without apply")
+			} else {
+				assert.NotContains(t, lock, `GH_AW_SAFE_OUTPUTS_STAGED: "true"`, "Applied variant safe outputs should not be staged")
+			}
+		})
+	}
+}
+
+func TestAgentMatrixRejectsEngine(t *testing.T) {
+	tmpDir := testutil.TempDir(t, "agent-matrix-engine")
+
+	workflow := `---
+on: workflow_dispatch
+engine: copilot
+matrix:
+  variants:
+    - id: claude
+      engine: claude
+    - id: codex
+      engine: codex
+---
+
+# Triage
+`
+	workflowPath := filepath.Join(tmpDir, "matrix.md")
+	require.NoError(t, os.WriteFile(workflowPath, []byte(workflow), 0644))
+
+	err := NewCompiler().CompileWorkflow(workflowPath)
+	require.Error(t, err, "Matrix with a top-level engine should not compile")
+	assert.Contains(t, err.Error(), "engine and matrix cannot be used together", "Error should explain the conflict")
+}
@@ -0,0 +1,547 @@
+package workflow
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/github/gh-aw/pkg/logger"
+)
+
+var agentStagesLog = logger.New("workflow:agent_stages")
+
+const (
+	// handoffDir is where agent stages write and later jobs download hand-off files
+	handoffDir = "/tmp/gh-aw/handoff"
+
+	// handoffArtifactPrefix is the artifact name prefix of stage hand-off files
+	handoffArtifactPrefix = "handoff-"
+
+	// handoffDetectionDir is where the threat detection job downloads hand-off files
+	handoffDetectionDir = "/tmp/gh-aw/threat-detection/handoff"
+)
+
+// agentStageIDPattern restricts stage ids to names that are valid in job ids and artifact names
+var agentStageIDPattern = regexp.MustCompile(`^[a-z][a-z0-9_-]*$`)
+
+// AgentStage is an agent stage that runs before the main agent job (stages).
+// Each stage runs in its own job with its own engine, tools, network and permissions,
+// and passes its result to later stages as a JSON hand-off file.
+// The workflow body and top-level settings form the final stage.
+type AgentStage struct {
+	ID            string
+	EngineSetting string              // engine id ("" = workflow engine)
+	EngineConfig  *EngineConfig       // engine configuration (nil = workflow engine)
+	Tools         map[string]any      // tools available to the stage (github by default)
+	Network       *NetworkPermissions // network permissions (nil = workflow network)
+	Permissions   string              // permissions YAML ("" = contents: read)
+	Prompt        string              // stage instructions (markdown)
+	HandoffSchema map[string]any      // JSON Schema of the hand-off file
+}
+
+// JobName returns the job id of the stage
+func (s *AgentStage) JobName() string {
+	return "stage_" + strings.ReplaceAll(s.ID, "-", "_")
+}
+
+// HandoffPath returns the path of the stage's hand-off file
+func (s *AgentStage) HandoffPath() string {
+	return fmt.Sprintf("%s/%s.json", handoffDir, s.ID)
+}
+
+// extractAgentStages extracts the stages section from frontmatter
+func (c *Compiler) extractAgentStages(frontmatter map[string]any) []*AgentStage {
+	stagesValue, exists := frontmatter["stages"]
+	if !exists || stagesValue == nil {
+		return nil
+	}
+
+	stagesList, ok := stagesValue.([]any)
+	if !ok {
+		return nil
+	}
+
+	var stages []*AgentStage
+	for _, item := range stagesList {
+		stageMap, ok := item.(map[string]any)
+		if !ok {
+			continue
+		}
+
+		stage := &AgentStage{}
+		if id, ok := stageMap["id"].(string); ok {
+			stage.ID = id
+		}
+		if prompt, ok := stageMap["prompt"].(string); ok {
+			stage.Prompt = prompt
+		}
+		if _, hasEngine := stageMap["engine"]; hasEngine {
+			stage.EngineSetting, stage.EngineConfig = c.ExtractEngineConfig(map[string]any{"engine": stageMap["engine"]})
+		}
+		if tools, ok := stageMap["tools"].(map[string]any); ok {
+			stage.Tools = tools
+		}
+		if _, hasNetwork := stageMap["network"]; hasNetwork {
+			stage.Network = c.extractNetworkPermissions(map[string]any{"network": stageMap["network"]})
+		}
+		if _, hasPermissions := stageMap["permissions"]; hasPermissions {
+			stage.Permissions = c.extractPermissions(map[string]any{"permissions": stageMap["permissions"]})
+		}
+		if handoff, ok := stageMap["handoff"].(map[string]any); ok {
+			if schema, ok := handoff["schema"].(map[string]any); ok {
+				stage.HandoffSchema = schema
+			}
+		}
+		if stage.HandoffSchema == nil {
+			stage.HandoffSchema = map[string]any{"type": "object"}
+		}
+
+		stages = append(stages, stage)
+	}
+
+	agentStagesLog.Printf("Extracted %d agent stages", len(stages))
+	return stages
+}
+
+// validateAgentStages validates stage ids, engines, prompts and hand-off schemas.
+// In strict mode, the permissions, network and tools of each stage must also pass the
+// strict mode checks of the workflow, since the stage job runs with them.
+func (c *Compiler) validateAgentStages(data *WorkflowData) error {
+	if len(data.AgentStages) == 0 {
+		return nil
+	}
+
+	initialStrictMode := c.strictMode
+	c.strictMode = c.isStrictModeEnabled(data.RawFrontmatter)
+	defer func() { c.strictMode = initialStrictMode }()
+
+	seen := make(map[string]bool)
+	for i, stage := range data.AgentStages {
+		if stage.ID == "" {
+			return fmt.Errorf("stages[%d]: id is required", i)
+		}
+		if !agentStageIDPattern.MatchString(stage.ID) {
+			return fmt.Errorf("stages[%d]: invalid id %q. Stage ids must start with a lowercase letter and contain only lowercase letters, digits, '-' and '_'", i, stage.ID)
+		}
+		if seen[stage.ID] {
+			return fmt.Errorf("stages: duplicate stage id %q", stage.ID)
+		}
+		seen[stage.ID] = true
+
+		if strings.TrimSpace(stage.Prompt) == "" {
+			return fmt.Errorf("stages.%s: prompt is required", stage.ID)
+		}
+		// Stage prompts are written verbatim in the stage job, without the activation job's
+		// expression sanitization, so GitHub Actions expressions are not allowed
+		if strings.Contains(stage.Prompt, "${{") {
+			return fmt.Errorf("stages.%s: prompt must not contain GitHub Actions expressions. Read event details with the github tool or move them to the workflow body", stage.ID)
+		}
+
+		if stage.EngineSetting != "" {
+			if _, err := c.getAgenticEngine(stage.EngineSetting); err != nil {
+				return fmt.Errorf("stages.%s: %w", stage.ID, err)
+			}
+		}
+
+		if _, err := compileSafeInputSchema(stage.HandoffSchema); err != nil {
+			return fmt.Errorf("stages.%s.handoff.schema is not a valid JSON Schema: %w", stage.ID, err)
+		}
+		if schemaType, _ := stage.HandoffSchema["type"].(string); schemaType != "object" {
+			return fmt.Errorf("stages.%s.handoff.schema must have type: object", stage.ID)
+		}
+
+		if c.strictMode {
+			if err := c.validateStrictAgentStage(data, stage); err != nil {
+				return fmt.Errorf("stages.%s: %w", stage.ID, err)
+			}
+		}
+	}
+	return nil
+}
+
+// validateStrictAgentStage refuses stage permissions, network and tools that strict mode
+// refuses at the top level
+func (c *Compiler) validateStrictAgentStage(data *WorkflowData, stage *AgentStage) error {
+	if err := validateStrictWritePermissions(NewPermissionsParser(stage.Permissions)); err != nil {
+		return err
+	}
+	if err := c.validateStrictTools(map[string]any{"tools": stage.Tools}); err != nil {
+		return err
+	}
+
+	// A stage that uses the workflow's network and engine was checked with the workflow
+	if stage.Network == nil && stage.EngineSetting == "" {
+		return nil
+	}
+	network := data.NetworkPermissions
+	if stage.Network != nil {
+		network = stage.Network
+		if err := c.validateStrictNetwork(network); err != nil {
+			return err
+		}
+	}
+	engineID := data.AI
+	if stage.EngineSetting != "" {
+		engineID = stage.EngineSetting
+	}
+	if engineID == "" {
+		return nil
+	}
+	return c.validateStrictFirewall(engineID, network, data.SandboxConfig)
+}
+
+// stageWorkflowData returns the workflow data used to build the job of an agent stage.
+// It starts from the workflow data and replaces the engine, tools, network and permissions
+// with the stage's own. Safe outputs, memory and custom steps belong to the final stage only.
+// AgentStages is set to the earlier stages, whose hand-offs the stage can read.
+func (c *Compiler) stageWorkflowData(data *WorkflowData, index int) *WorkflowData {
+	stage := data.AgentStages[index]
+	stageData := *data
+
+	// The maps and configuration structs of the workflow are copied so that building
+	// the stage job cannot modify the workflow data used for the agent job
+	stageData.Features = maps.Clone(data.Features)
+	stageData.Runtimes = maps.Clone(data.Runtimes)
+	stageData.Jobs = maps.Clone(data.Jobs)
+	stageData.ImportInputs = maps.Clone(data.ImportInputs)
+	stageData.EngineConfig = cloneEngineConfig(data.EngineConfig)
+	stageData.NetworkPermissions = cloneNetworkPermissions(data.NetworkPermissions)
+	stageData.SandboxConfig = cloneSandboxConfig(data.SandboxConfig)
+
+	if stage.EngineSetting != "" {
+		stageData.AI = stage.EngineSetting
+		stageData.EngineConfig = cloneEngineConfig(stage.EngineConfig)
+	}
+	if stage.Network != nil {
+		stageData.NetworkPermissions = cloneNetworkPermissions(stage.Network)
+	}
+	stageData.Permissions = stage.Permissions
+	if stageData.Permissions == "" {
+		// Workflow-level indentation, like the defaults applied to the workflow permissions
+		stageData.Permissions = "permissions:\n  contents: read"
+	}
+
+	stageData.SafeOutputs = nil
+	stageData.SafeInputs = nil
+	stageData.CacheMemoryConfig = nil
+	stageData.RepoMemoryConfig = nil
+	stageData.CustomSteps = ""
+	stageData.PostSteps = ""
+	stageData.PromptBudget = nil
+
+	tools := make(map[string]any, len(stage.Tools))
+	for name, config := range stage.Tools {
+		tools[name] = config
+	}
+	stageData.Tools = c.applyDefaultTools(tools, nil, stageData.SandboxConfig, stageData.NetworkPermissions)
+	stageData.ParsedTools = NewTools(stageData.Tools)
+
+	stageData.CurrentStage = stage
+	stageData.AgentStages = data.AgentStages[:index]
+	return &stageData
+}
+
+// cloneEngineConfig returns a copy of an engine configuration that shares no maps or slices
+func cloneEngineConfig(config *EngineConfig) *EngineConfig {
+	if config == nil {
+		return nil
+	}
+	clone := *config
+	clone.Env = maps.Clone(config.Env)
+	clone.Args = slices.Clone(config.Args)
+	clone.Firewall = cloneFirewallConfig(config.Firewall)
+	return &clone
+}
+
+// cloneNetworkPermissions returns a copy of network permissions that shares no maps or slices
+func cloneNetworkPermissions(network *NetworkPermissions) *NetworkPermissions {
+	if network == nil {
+		return nil
+	}
+	clone := *network
+	clone.Allowed = slices.Clone(network.Allowed)
+	clone.Blocked = slices.Clone(network.Blocked)
+	if network.Ecosystems != nil {
+		clone.Ecosystems = make(map[string][]string, len(network.Ecosystems))
+		for name, domains := range network.Ecosystems {
+			clone.Ecosystems[name] = slices.Clone(domains)
+		}
+	}
+	clone.Firewall = cloneFirewallConfig(network.Firewall)
+	return &clone
+}
+
+// cloneFirewallConfig returns a copy of a firewall configuration that shares no slices
+func cloneFirewallConfig(firewall *FirewallConfig) *FirewallConfig {
+	if firewall == nil {
+		return nil
+	}
+	clone := *firewall
+	clone.Args = slices.Clone(firewall.Args)
+	clone.AllowURLs = slices.Clone(firewall.AllowURLs)
+	return &clone
+}
+
+// cloneSandboxConfig returns a copy of a sandbox configuration whose agent and MCP gateway
+// settings can be modified without affecting the original
+func cloneSandboxConfig(sandbox *SandboxConfig) *SandboxConfig {
+	if sandbox == nil {
+		return nil
+	}
+	clone := *sandbox
+	if sandbox.Agent != nil {
+		agent := *sandbox.Agent
+		agent.Args = slices.Clone(sandbox.Agent.Args)
+		agent.Env = maps.Clone(sandbox.Agent.Env)
+		agent.Mounts = slices.Clone(sandbox.Agent.Mounts)
+		clone.Agent = &agent
+	}
+	if sandbox.MCP != nil {
+		mcp := *sandbox.MCP
+		mcp.Args = slices.Clone(sandbox.MCP.Args)
+		mcp.EntrypointArgs = slices.Clone(sandbox.MCP.EntrypointArgs)
+		mcp.Env = maps.Clone(sandbox.MCP.Env)
+		mcp.Mounts = slices.Clone(sandbox.MCP.Mounts)
+		clone.MCP = &mcp
+	}
+	return &clone
+}
+
+// buildAgentStageJobs builds one job per agent stage. Stages run in order: each stage
+// job depends on the activation job and on the previous stage.
+func (c *Compiler) buildAgentStageJobs(data *WorkflowData, activationJobCreated bool) error {
+	for i, stage := range data.AgentStages {
+		agentStagesLog.Printf("Building job for agent stage %s", stage.ID)
+		stageData := c.stageWorkflowData(data, i)
+
+		// Each stage job is validated for step ordering on its own
+		mainTracker := c.stepOrderTracker
+		c.stepOrderTracker = NewStepOrderTracker()
+		job, err := c.buildMainJob(stageData, activationJobCreated)
+		c.stepOrderTracker = mainTracker
+		if err != nil {
+			return fmt.Errorf("failed to build job for stage %s: %w", stage.ID, err)
+		}
+
+		job.Name = stage.JobName()
+		job.DisplayName = "Stage: " + stage.ID
+		job.Concurrency = ""
+		if i > 0 {
+			job.Needs = append(job.Needs, data.AgentStages[i-1].JobName())
+		}
+		if err := c.jobManager.AddJob(job); err != nil {
+			return fmt.Errorf("failed to add stage job %s: %w", stage.ID, err)
+		}
+	}
+	return nil
+}
+
+// lastAgentStageJobName returns the job name of the last agent stage, or "" without stages
+func lastAgentStageJobName(data *WorkflowData) string {
+	if len(data.AgentStages) == 0 {
+		return ""
+	}
+	return data.AgentStages[len(data.AgentStages)-1].JobName()
+}
+
+// agentStageJobNames returns the job names of the agent stages
+func agentStageJobNames(data *WorkflowData) []string {
+	names := make([]string, 0, len(data.AgentStages))
+	for _, stage := range data.AgentStages {
+		names = append(names, stage.JobName())
+	}
+	return names
+}
+
+// agentStageFailedCondition returns a condition that is true when an agent stage failed.
+// The agent job is skipped when a stage fails, so the conclusion job checks the stage
+// results to report the failure. Returns nil without stages.
+func agentStageFailedCondition(data *WorkflowData) ConditionNode {
+	if len(data.AgentStages) == 0 {
+		return nil
+	}
+	var terms []ConditionNode
+	for _, name := range agentStageJobNames(data) {
+		terms = append(terms, BuildEquals(
+			BuildPropertyAccess(fmt.Sprintf("needs.%s.result", name)),
+			BuildStringLiteral("failure"),
+		))
+	}
+	return BuildDisjunction(false, terms...)
+}
+
+// agentConclusionExpression returns the expression of the agent conclusion reported by the
+// conclusion job: "failure" when an agent stage failed, otherwise the agent job result
+func agentConclusionExpression(data *WorkflowData, mainJobName string) string {
+	stageFailed := agentStageFailedCondition(data)
+	if stageFailed == nil {
+		return fmt.Sprintf("${{ needs.%s.result }}", mainJobName)
+	}
+	return fmt.Sprintf("${{ (%s) && 'failure' || needs.%s.result }}", stageFailed.Render(), mainJobName)
+}
+
+// secretVerificationExpression returns the expression of the engine secret verification
+// result reported by the conclusion job. Each stage validates the secrets of its own engine,
+// so the result is "failed" when the agent job or any stage failed to verify its secret.
+// Returns "" when no job validates an engine secret.
+func (c *Compiler) secretVerificationExpression(data *WorkflowData, mainJobName string) (string, error) {
+	engine, err := c.getAgenticEngine(data.AI)
+	if err != nil {
+		return "", fmt.Errorf("failed to get agentic engine: %w", err)
+	}
+	mainValidates := EngineHasValidateSecretStep(engine, data)
+
+	var stageTerms []ConditionNode
+	for i, stage := range data.AgentStages {
+		stageData := c.stageWorkflowData(data, i)
+		stageEngine, err := c.getAgenticEngine(stageData.AI)
+		if err != nil {
+			return "", fmt.Errorf("stages.%s: %w", stage.ID, err)
+		}
+		if EngineHasValidateSecretStep(stageEngine, stageData) {
+			stageTerms = append(stageTerms, BuildEquals(
+				BuildPropertyAccess(fmt.Sprintf("needs.%s.outputs.secret_verification_result", stage.JobName())),
+				BuildStringLiteral("failed"),
+			))
+		}
+	}
+
+	mainResult := fmt.Sprintf("needs.%s.outputs.secret_verification_result", mainJobName)
+	switch {
+	case len(stageTerms) == 0 && !mainValidates:
+		return "", nil
+	case len(stageTerms) == 0:
+		return "${{ " + mainResult + " }}", nil
+	case !mainValidates:
+		mainResult = "''"
+	}
+	return fmt.Sprintf("${{ (%s) && 'failed' || %s }}", BuildDisjunction(false, stageTerms...).Render(), mainResult), nil
+}
+
+// buildHandoffPromptText describes the hand-off files of earlier stages
+func buildHandoffPromptText(stages []*AgentStage) string {
+	var sb strings.Builder
+	sb.WriteString("<handoff>\n")
+	sb.WriteString("Earlier agent stages of this workflow wrote their results to JSON hand-off files. Read them before you start:\n")
+	for _, stage := range stages {
+		fmt.Fprintf(&sb, "- %s: %s\n", stage.ID, stage.HandoffPath())
+	}
+	sb.WriteString("</handoff>\n")
+	return sb.String()
+}
+
+// buildStageInstructionsText describes the hand-off file a stage must write
+func buildStageInstructionsText(stage *AgentStage) (string, error) {
+	schemaJSON, err := json.MarshalIndent(stage.HandoffSchema, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to encode hand-off schema of stage %s: %w", stage.ID, err)
+	}
+
+	var sb strings.Builder
+	sb.WriteString("<stage>\n")
+	fmt.Fprintf(&sb, "You are the %q stage of a multi-stage agentic workflow. Your result is passed to the next stage, not applied directly.\n", stage.ID)
+	fmt.Fprintf(&sb, "When you are done, write your result as a single JSON object to %s. It must match this JSON Schema:\n\n", stage.HandoffPath())
+	sb.WriteString("```json\n")
+	sb.Write(schemaJSON)
+	sb.WriteString("\n```\n")
+	sb.WriteString("</stage>\n")
+	return sb.String(), nil
+}
+
+// generateStagePromptStep writes the prompt of an agent stage: the built-in security and
+// temporary folder instructions, the hand-off instructions and the stage prompt
+func (c *Compiler) generateStagePromptStep(yaml *strings.Builder, data *WorkflowData) error {
+	stage := data.CurrentStage
+	instructions, err := buildStageInstructionsText(stage)
+	if err != nil {
+		return err
+	}
+
+	delimiter := GenerateHeredocDelimiter("PROMPT")
+	writeHeredoc := func(content string) {
+		yaml.WriteString("          cat << '" + delimiter + "' >> \"$GH_AW_PROMPT\"\n")
+		for line := range strings.SplitSeq(strings.TrimRight(content, "\n"), "\n") {
+			yaml.WriteString("          " + line + "\n")
+		}
+		yaml.WriteString("          " + delimiter + "\n")
+	}
+
+	yaml.WriteString("      - name: Create stage prompt\n")
+	yaml.WriteString("        env:\n")
+	yaml.WriteString("          GH_AW_PROMPT: /tmp/gh-aw/aw-prompts/prompt.txt\n")
+	yaml.WriteString("        run: |\n")
+	yaml.WriteString("          mkdir -p /tmp/gh-aw/aw-prompts " + handoffDir + "\n")
+	yaml.WriteString("          : > \"$GH_AW_PROMPT\"\n")
+	yaml.WriteString("          echo '<system>' >> \"$GH_AW_PROMPT\"\n")
+	for _, section := range c.collectPromptSections(data) {
+		if section.IsFile && section.ShellCondition == "" {
+			fmt.Fprintf(yaml, "          cat \"%s/%s\" >> \"$GH_AW_PROMPT\"\n", promptsDir, section.Content)
+		}
+	}
+	writeHeredoc(instructions)
+	if len(data.AgentStages) > 0 {
+		writeHeredoc(buildHandoffPromptText(data.AgentStages))
+	}
+	yaml.WriteString("          echo '</system>' >> \"$GH_AW_PROMPT\"\n")
+	writeHeredoc(removeXMLComments(stage.Prompt))
+	return nil
+}
+
+// generateHandoffDownloadStep downloads the hand-off files of earlier stages
+func generateHandoffDownloadStep(yaml *strings.Builder, data *WorkflowData) {
+	if len(data.AgentStages) == 0 {
+		return
+	}
+	yaml.WriteString("      - name: Download hand-off artifacts\n")
+	fmt.Fprintf(yaml, "        uses: %s\n", GetActionPin("actions/download-artifact"))
+	yaml.WriteString("        with:\n")
+	yaml.WriteString("          pattern: " + handoffArtifactPrefix + "*\n")
+	yaml.WriteString("          path: " + handoffDir + "\n")
+	yaml.WriteString("          merge-multiple: true\n")
+}
+
+// buildHandoffDetectionDownloadStep downloads the hand-off files of all agent stages into
+// the threat detection directory, where the detection prompt lists them for analysis
+func buildHandoffDetectionDownloadStep() []string {
+	return []string{
+		"      - name: Download hand-off artifacts\n",
+		fmt.Sprintf("        uses: %s\n", GetActionPin("actions/download-artifact")),
+		"        with:\n",
+		"          pattern: " + handoffArtifactPrefix + "*\n",
+		"          path: " + handoffDetectionDir + "\n",
+		"          merge-multiple: true\n",
+	}
+}
+
+// generateHandoffSteps validates the hand-off file written by an agent stage against
+// its schema and uploads it for the next stages. A missing or invalid hand-off fails the job.
+func generateHandoffSteps(yaml *strings.Builder, stage *AgentStage) error {
+	schemaJSON, err := json.Marshal(stage.HandoffSchema)
+	if err != nil {
+		return fmt.Errorf("failed to encode hand-off schema of stage %s: %w", stage.ID, err)
+	}
+
+	yaml.WriteString("      - name: Validate hand-off\n")
+	fmt.Fprintf(yaml, "        uses: %s\n", GetActionPin("actions/github-script"))
+	yaml.WriteString("        env:\n")
+	fmt.Fprintf(yaml, "          GH_AW_STAGE_ID: %s\n", stage.ID)
+	fmt.Fprintf(yaml, "          GH_AW_HANDOFF_PATH: %s\n", stage.HandoffPath())
+	fmt.Fprintf(yaml, "          GH_AW_HANDOFF_SCHEMA: %q\n", string(schemaJSON))
+	yaml.WriteString("        with:\n")
+	yaml.WriteString("          script: |\n")
+	yaml.WriteString("            const { setupGlobals } = require('" + SetupActionDestination + "/setup_globals.cjs');\n")
+	yaml.WriteString("            setupGlobals(core, github, context, exec, io);\n")
+	yaml.WriteString("            const { main } = require('" + SetupActionDestination + "/validate_handoff.cjs');\n")
+	yaml.WriteString("            await main();\n")
+
+	yaml.WriteString("      - name: Upload hand-off\n")
+	fmt.Fprintf(yaml, "        uses: %s\n", GetActionPin("actions/upload-artifact"))
+	yaml.WriteString("        with:\n")
+	yaml.WriteString("          name: " + handoffArtifactPrefix + stage.ID + "\n")
+	yaml.WriteString("          path: " + stage.HandoffPath() + "\n")
+	yaml.WriteString("          if-no-files-found: error\n")
+	return nil
+}
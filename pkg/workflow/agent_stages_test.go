@@ -0,0 +1,304 @@
+//go:build !integration
+
+package workflow
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/github/gh-aw/pkg/stringutil"
+	"github.com/github/gh-aw/pkg/testutil"
+	"github.com/goccy/go-yaml"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExtractAgentStages(t *testing.T) {
+	compiler := NewCompiler()
+
+	stages := compiler.extractAgentStages(map[string]any{
+		"stages": []any{
+			map[string]any{
+				"id":          "planner",
+				"engine":      "claude",
+				"prompt":      "Plan the change.",
+				"permissions": map[string]any{"issues": "read"},
+				"handoff": map[string]any{
+					"schema": map[string]any{"type": "object", "required": []any{"steps"}},
+				},
+			},
+			map[string]any{"id": "implementer", "prompt": "Implement the plan."},
+		},
+	})
+	require.Len(t, stages, 2, "Both stages should be extracted")
+
+	assert.Equal(t, "planner", stages[0].ID, "Stage id should be extracted")
+	assert.Equal(t, "claude", stages[0].EngineSetting, "Stage engine should be extracted")
+	assert.Contains(t, stages[0].Permissions, "issues: read", "Stage permissions should be extracted")
+	assert.Equal(t, []any{"steps"}, stages[0].HandoffSchema["required"], "Hand-off schema should be extracted")
+	assert.Equal(t, "stage_planner", stages[0].JobName(), "Job name should be derived from the id")
+	assert.Equal(t, "/tmp/gh-aw/handoff/planner.json", stages[0].HandoffPath(), "Hand-off path should be derived from the id")
+
+	assert.Empty(t, stages[1].EngineSetting, "Stage without engine should use the workflow engine")
+	assert.Equal(t, map[string]any{"type": "object"}, stages[1].HandoffSchema, "Hand-off schema should default to any object")
+
+	assert.Nil(t, compiler.extractAgentStages(map[string]any{}), "Missing section should yield no stages")
+}
+
+func TestValidateAgentStages(t *testing.T) {
+	objectSchema := map[string]any{"type": "object"}
+
+	tests := []struct {
+		name    string
+		stages  []*AgentStage
+		wantErr string
+	}{
+		{
+			name:   "valid stages",
+			stages: []*AgentStage{{ID: "planner", Prompt: "Plan", HandoffSchema: objectSchema}, {ID: "code-review", EngineSetting: "codex", Prompt: "Review", HandoffSchema: objectSchema}},
+		},
+		{
+			name:    "invalid id",
+			stages:  []*AgentStage{{ID: "Planner", Prompt: "Plan", HandoffSchema: objectSchema}},
+			wantErr: `invalid id "Planner"`,
+		},
+		{
+			name:    "duplicate id",
+			stages:  []*AgentStage{{ID: "planner", Prompt: "Plan", HandoffSchema: objectSchema}, {ID: "planner", Prompt: "Plan again", HandoffSchema: objectSchema}},
+			wantErr: `duplicate stage id "planner"`,
+		},
+		{
+			name:    "missing prompt",
+			stages:  []*AgentStage{{ID: "planner", HandoffSchema: objectSchema}},
+			wantErr: "stages.planner: prompt is required",
+		},
+		{
+			name:    "expression in prompt",
+			stages:  []*AgentStage{{ID: "planner", Prompt: "Plan ${{ github.event.issue.title }}", HandoffSchema: objectSchema}},
+			wantErr: "must not contain GitHub Actions expressions",
+		},
+		{
+			name:    "unknown engine",
+			stages:  []*AgentStage{{ID: "planner", EngineSetting: "unknown-engine", Prompt: "Plan", HandoffSchema: objectSchema}},
+			wantErr: "stages.planner:",
+		},
+		{
+			name:    "non-object schema",
+			stages:  []*AgentStage{{ID: "planner", Prompt: "Plan", HandoffSchema: map[string]any{"type": "string"}}},
+			wantErr: "must have type: object",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := NewCompiler().validateAgentStages(&WorkflowData{AgentStages: tt.stages})
+			if tt.wantErr == "" {
+				assert.NoError(t, err, "Stages should be valid")
+				return
+			}
+			require.Error(t, err, "Stages should be invalid")
+			assert.Contains(t, err.Error(), tt.wantErr, "Error should describe the problem")
+		})
+	}
+}
+
+func TestAgentStagesCompile(t *testing.T) {
+	tmpDir := testutil.TempDir(t, "agent-stages-compile")
+
+	workflow := `---
+on:
+  issues:
+    types: [opened]
+permissions:
+  contents: read
+  issues: read
+engine: copilot
+stages:
+  - id: planner
+    engine: claude
+    prompt: |
+      Read the issue and write an implementation plan.
+    handoff:
+      schema:
+        type: object
+        properties:
+          steps:
+            type: array
+            items:
+              type: string
+        required: [steps]
+  - id: implementer
+    prompt: |
+      Implement the plan.
+safe-outputs:
+  create-pull-request:
+---
+
+# Review
+
+Review the implementation and open a pull request.
+`
+	workflowPath := filepath.Join(tmpDir, "pipeline.md")
+	require.NoError(t, os.WriteFile(workflowPath, []byte(workflow), 0644))
+
+	compiler := NewCompiler()
+	require.NoError(t, compiler.CompileWorkflow(workflowPath), "Workflow with stages should compile")
+
+	lockContent, err := os.ReadFile(stringutil.MarkdownToLockFile(workflowPath))
+	require.NoError(t, err, "Lock file should be written")
+	lock := string(lockContent)
+
+	var parsed struct {
+		Jobs map[string]struct {
+			Name  string `yaml:"name"`
+			Needs any    `yaml:"needs"`
+		} `yaml:"jobs"`
+	}
+	require.NoError(t, yaml.Unmarshal(lockContent, &parsed), "Lock file should be valid YAML")
+	assert.Equal(t, "Stage: planner", parsed.Jobs["stage_planner"].Name, "Stage job should have a display name")
+	assert.Equal(t, "Stage: implementer", parsed.Jobs["stage_implementer"].Name, "Stage job should have a display name")
+
+	planner := agentStagesJobSection(lock, "stage_planner")
+	implementer := agentStagesJobSection(lock, "stage_implementer")
+	agent := agentStagesJobSection(lock, "agent")
+	require.NotEmpty(t, planner, "Planner stage job should be generated")
+	require.NotEmpty(t, implementer, "Implementer stage job should be generated")
+
+	assert.Contains(t, planner, "- name: Create stage prompt", "Stage should write its own prompt")
+	assert.NotContains(t, planner, "Download prompt artifact", "Stage should not use the activation prompt")
+	assert.NotContains(t, planner, "Download hand-off artifacts", "First stage has no hand-offs to download")
+	assert.Contains(t, planner, "Read the issue and write an implementation plan.", "Stage prompt should be written")
+	assert.Contains(t, planner, "GH_AW_HANDOFF_PATH: /tmp/gh-aw/handoff/planner.json", "Hand-off should be validated")
+	assert.Contains(t, planner, "name: handoff-planner", "Hand-off should be uploaded")
+	assert.Contains(t, planner, "name: agent-artifacts-planner", "Stage artifacts should use their own name")
+	assert.Contains(t, planner, "ANTHROPIC_API_KEY", "Stage should use its own engine")
+	assert.Contains(t, planner, "    permissions:\n      contents: read\n", "Stage should default to contents: read")
+	assert.NotContains(t, planner, "GH_AW_SAFE_OUTPUTS: ", "Stages should not have safe outputs")
+	assert.Less(t, strings.Index(planner, "- name: Redact secrets"), strings.Index(planner, "- name: Upload hand-off"), "Hand-off should be uploaded after secret redaction")
+
+	assert.Contains(t, implementer, "- stage_planner", "Implementer should run after the planner")
+	assert.Contains(t, implementer, "Download hand-off artifacts", "Implementer should download earlier hand-offs")
+	assert.Contains(t, implementer, "- planner: /tmp/gh-aw/handoff/planner.json", "Implementer prompt should list the planner hand-off")
+
+	assert.Contains(t, agent, "- stage_implementer", "Agent job should run after the last stage")
+	assert.Contains(t, agent, "Download hand-off artifacts", "Agent job should download the hand-offs")
+	assert.Contains(t, agent, "GH_AW_SAFE_OUTPUTS", "Only the final stage should have safe outputs")
+	assert.Contains(t, lock, "- implementer: /tmp/gh-aw/handoff/implementer.json", "Final prompt should list the hand-offs")
+
+	assert.Contains(t, planner, "- name: Validate ANTHROPIC_API_KEY secret", "Stage should validate the secret of its engine")
+	detection := agentStagesJobSection(lock, "detection")
+	assert.Contains(t, detection, "path: /tmp/gh-aw/threat-detection/handoff", "Threat detection should analyze the hand-offs")
+
+	conclusion := agentStagesJobSection(lock, "conclusion")
+	assert.Contains(t, conclusion, "      - stage_planner\n", "Conclusion should wait for the stages")
+	assert.Contains(t, conclusion, "needs.stage_planner.result == 'failure'", "A failed stage should be reported")
+	assert.Contains(t, conclusion, "GH_AW_AGENT_CONCLUSION: ${{ (needs.stage_planner.result == 'failure' || needs.stage_implementer.result == 'failure') && 'failure' || needs.agent.result }}", "A failed stage should be reported as an agent failure")
+	assert.Contains(t, conclusion, "GH_AW_SECRET_VERIFICATION_RESULT: ${{ (needs.stage_planner.outputs.secret_verification_result == 'failed' || needs.stage_implementer.outputs.secret_verification_result == 'failed') && 'failed' || needs.agent.outputs.secret_verification_result }}", "Secret verification should include the stage engines")
+}
+
+func TestStageWorkflowDataIsolation(t *testing.T) {
+	compiler := NewCompiler()
+	data := &WorkflowData{
+		AI:                 "copilot",
+		EngineConfig:       &EngineConfig{ID: "copilot", Env: map[string]string{"A": "1"}, Args: []string{"--flag"}},
+		NetworkPermissions: &NetworkPermissions{Allowed: []string{"defaults"}, Ecosystems: map[string][]string{"corp": {"corp.example.com"}}},
+		SandboxConfig:      &SandboxConfig{MCP: &MCPGatewayRuntimeConfig{Env: map[string]string{"B": "2"}}},
+		Features:           map[string]any{"feature": true},
+		AgentStages:        []*AgentStage{{ID: "planner", Prompt: "Plan."}},
+	}
+
+	stageData := compiler.stageWorkflowData(data, 0)
+	stageData.EngineConfig.Env["A"] = "changed"
+	stageData.EngineConfig.Args[0] = "--changed"
+	stageData.NetworkPermissions.Allowed[0] = "changed"
+	stageData.NetworkPermissions.Ecosystems["corp"][0] = "changed"
+	stageData.SandboxConfig.MCP.Env["B"] = "changed"
+	stageData.Features["feature"] = false
+
+	assert.Equal(t, "1", data.EngineConfig.Env["A"], "Engine env should not be shared")
+	assert.Equal(t, "--flag", data.EngineConfig.Args[0], "Engine args should not be shared")
+	assert.Equal(t, "defaults", data.NetworkPermissions.Allowed[0], "Network permissions should not be shared")
+	assert.Equal(t, "corp.example.com", data.NetworkPermissions.Ecosystems["corp"][0], "Network ecosystems should not be shared")
+	assert.Equal(t, "2", data.SandboxConfig.MCP.Env["B"], "Sandbox configuration should not be shared")
+	assert.Equal(t, true, data.Features["feature"], "Features should not be shared")
+}
+
+// agentStagesJobSection extracts the section of a job from the compiled YAML
+func agentStagesJobSection(yaml, jobName string) string {
+	var jobLines []string
+	inJob := false
+	for line := range strings.SplitSeq(yaml, "\n") {
+		if line == "  "+jobName+":" {
+			inJob = true
+		} else if inJob && strings.HasPrefix(line, "  ") && !strings.HasPrefix(line, "    ") {
+			break
+		}
+		if inJob {
+			jobLines = append(jobLines, line)
+		}
+	}
+	return strings.Join(jobLines, "\n")
+}
+
+func TestAgentStagesStrictMode(t *testing.T) {
+	tests := []struct {
+		name    string
+		strict  string
+		stage   string
+		wantErr string
+	}{
+		{
+			name:    "write permission",
+			strict:  "strict: true",
+			stage:   "    permissions:\n      contents: read\n      issues: write\n",
+			wantErr: "stages.planner: strict mode: write permission 'issues: write' is not allowed",
+		},
+		{
+			name:    "write-all permissions",
+			strict:  "strict: true",
+			stage:   "    permissions: write-all\n",
+			wantErr: "stages.planner: strict mode: write permission 'contents: write' is not allowed",
+		},
+		{
+			name:    "wildcard network",
+			strict:  "strict: true",
+			stage:   "    network:\n      allowed:\n        - \"*\"\n",
+			wantErr: "stages.planner: strict mode: wildcard '*' is not allowed in network.allowed domains",
+		},
+		{
+			name:    "strict by default",
+			stage:   "    permissions:\n      pull-requests: write\n",
+			wantErr: "stages.planner: strict mode: write permission 'pull-requests: write' is not allowed",
+		},
+		{
+			name:   "read permissions and explicit domains",
+			strict: "strict: true",
+			stage:  "    permissions:\n      contents: read\n      issues: read\n    network:\n      allowed:\n        - defaults\n        - python\n",
+		},
+		{
+			name:   "non-strict workflow",
+			strict: "strict: false",
+			stage:  "    permissions:\n      issues: write\n    network:\n      allowed:\n        - \"*\"\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir := testutil.TempDir(t, "agent-stages-strict")
+			workflow := "---\non: workflow_dispatch\npermissions:\n  contents: read\nengine: copilot\n" + tt.strict + "\nstages:\n  - id: planner\n    prompt: Plan the change.\n" + tt.stage + "---\n\n# Implement\n\nImplement the plan.\n"
+			workflowPath := filepath.Join(tmpDir, "pipeline.md")
+			require.NoError(t, os.WriteFile(workflowPath, []byte(workflow), 0644))
+
+			err := NewCompiler().CompileWorkflow(workflowPath)
+			if tt.wantErr == "" {
+				assert.NoError(t, err, "Stage should pass strict mode")
+				return
+			}
+			require.Error(t, err, "Stage should fail strict mode")
+			assert.Contains(t, err.Error(), tt.wantErr, "Error should name the stage and the strict mode check")
+			assert.NoFileExists(t, stringutil.MarkdownToLockFile(workflowPath), "No lock file should be written")
+		})
+	}
+}
@@ -59,6 +59,9 @@ type GitHubActionStep []string
 //   ├── GetDefaultDetectionModel()
 //   └── GetRequiredSecretNames()
 //
+//   ContextWindowProvider (prompt budgeting - optional)
+//   └── GetContextTokenLimit()
+//
 //   CodingAgentEngine (composite - backward compatibility)
 //   └── Composes all above interfaces
 //
@@ -192,6 +195,14 @@ type ModelEnvVarProvider interface {
 	GetModelEnvVarName() string
 }
 
+// ContextWindowProvider reports the size of the model context window used by an engine.
+// The compiler uses it as the default prompt budget (see prompt-budget in the frontmatter).
+// The default implementation in BaseEngine returns DefaultContextTokenLimit.
+type ContextWindowProvider interface {
+	// GetContextTokenLimit returns the approximate context window of the engine's default model in tokens
+	GetContextTokenLimit() int
+}
+
 // CodingAgentEngine is a composite interface that combines all focused interfaces
 // This maintains backward compatibility with existing code while allowing more flexibility
 // Implementations can choose to implement only the interfaces they need by embedding BaseEngine
@@ -203,6 +214,7 @@ type CodingAgentEngine interface {
 	LogParser
 	SecurityProvider
 	ModelEnvVarProvider
+	ContextWindowProvider
 }
 
 // BaseEngine provides common functionality for agentic engines
@@ -218,6 +230,7 @@ type BaseEngine struct {
 	supportsFirewall       bool
 	supportsPlugins        bool
 	supportsLLMGateway     bool
+	contextTokenLimit      int // 0 means DefaultContextTokenLimit
 }
 
 func (e *BaseEngine) GetID() string {
@@ -284,6 +297,15 @@ func (e *BaseEngine) GetModelEnvVarName() string {
 	return ""
 }
 
+// GetContextTokenLimit returns the context window configured for the engine,
+// or DefaultContextTokenLimit when the engine does not declare one
+func (e *BaseEngine) GetContextTokenLimit() int {
+	if e.contextTokenLimit > 0 {
+		return e.contextTokenLimit
+	}
+	return DefaultContextTokenLimit
+}
+
 // GetLogFileForParsing returns the default log file path for parsing
 // Engines can override this to use engine-specific log files
 func (e *BaseEngine) GetLogFileForParsing() string {
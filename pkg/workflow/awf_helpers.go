@@ -155,7 +155,9 @@ func BuildAWFArgs(config AWFCommandConfig) []string {
 	// Use double-quoted form (via shellDoubleQuoteArg) so wildcards like *.domain.com are
 	// treated as plain arguments rather than shell globs, fixing ShellCheck SC1003, while
 	// still escaping $, `, \, and " to prevent unintended shell expansion.
-	awfArgs = append(awfArgs, "--allow-domains", shellDoubleQuoteArg(config.AllowedDomains))
+	// GHES targets replace github.com domains with the GHES host
+	allowedDomains := rewriteDomainsForTarget(config.AllowedDomains, config.WorkflowData.CompileTarget)
+	awfArgs = append(awfArgs, "--allow-domains", shellDoubleQuoteArg(allowedDomains))
 
 	// Add blocked domains if specified
 	blockedDomains := formatBlockedDomains(config.WorkflowData.NetworkPermissions)
@@ -26,11 +26,12 @@ func NewClaudeEngine() *ClaudeEngine {
 			description:            "Uses Claude Code with full MCP tool support and allow-listing",
 			experimental:           false,
 			supportsToolsAllowlist: true,
-			supportsMaxTurns:       true,  // Claude supports max-turns feature
-			supportsWebFetch:       true,  // Claude has built-in WebFetch support
-			supportsWebSearch:      true,  // Claude has built-in WebSearch support
-			supportsFirewall:       true,  // Claude supports network firewalling via AWF
-			supportsLLMGateway:     false, // Claude does not support LLM gateway
+			supportsMaxTurns:       true,   // Claude supports max-turns feature
+			supportsWebFetch:       true,   // Claude has built-in WebFetch support
+			supportsWebSearch:      true,   // Claude has built-in WebSearch support
+			supportsFirewall:       true,   // Claude supports network firewalling via AWF
+			supportsLLMGateway:     false,  // Claude does not support LLM gateway
+			contextTokenLimit:      200000, // Claude models have a 200k token context window
 		},
 	}
 }
@@ -0,0 +1,169 @@
+// This file provides the compile target for workflows that run on GitHub Enterprise Server.
+//
+// By default lock files assume github.com: network allow-lists contain github.com
+// domains, the GitHub MCP server may use the hosted remote endpoint and actions are
+// pinned to SHAs resolved from github.com. When compiling with a GHES target
+// (--target ghes:<version>), the compiler:
+//   - rewrites GitHub domains in firewall and sanitization allow-lists to the GHES host
+//   - runs the GitHub MCP server locally, pointed at the GHES host
+//   - pins actions from the GHES mirror (.github/aw/actions-lock.ghes.json)
+//   - rejects features and actions that are not available on the GHES version
+
+package workflow
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/github/gh-aw/pkg/console"
+	"github.com/github/gh-aw/pkg/logger"
+)
+
+var compileTargetLog = logger.New("workflow:compile_target")
+
+const (
+	// CompileTargetGitHubCom is the default compile target
+	CompileTargetGitHubCom = "github.com"
+	// CompileTargetGHESPrefix prefixes GHES compile targets (e.g. "ghes:3.16")
+	CompileTargetGHESPrefix = "ghes:"
+)
+
+// ghesVersionPattern matches GHES release versions such as 3.16 or 3.16.2
+var ghesVersionPattern = regexp.MustCompile(`^\d+\.\d+(\.\d+)?$`)
+
+// CompileTarget describes the GitHub platform that compiled workflows run on.
+// A nil target means github.com.
+type CompileTarget struct {
+	Version string // GHES release version (e.g. "3.16")
+	Host    string // GHES hostname (e.g. "github.example.com")
+}
+
+// ParseCompileTarget parses a --target value. It returns nil for github.com.
+// GHES targets have the form ghes:<version> and require the GHES hostname.
+func ParseCompileTarget(value, host string) (*CompileTarget, error) {
+	if value == "" || value == CompileTargetGitHubCom {
+		return nil, nil
+	}
+
+	version, ok := strings.CutPrefix(value, CompileTargetGHESPrefix)
+	if !ok {
+		return nil, fmt.Errorf("invalid compile target '%s'. Must be '%s' or '%s<version>' (e.g. ghes:3.16)", value, CompileTargetGitHubCom, CompileTargetGHESPrefix)
+	}
+	if !ghesVersionPattern.MatchString(version) {
+		return nil, fmt.Errorf("invalid GHES version '%s' in compile target. Use a release version such as 3.16", version)
+	}
+
+	host = strings.TrimSuffix(strings.TrimPrefix(strings.TrimPrefix(host, "https://"), "http://"), "/")
+	if host == "" || host == CompileTargetGitHubCom {
+		return nil, fmt.Errorf("compile target '%s' requires the GHES hostname. Set GH_HOST (or GITHUB_SERVER_URL) to your GitHub Enterprise Server host", value)
+	}
+
+	compileTargetLog.Printf("Parsed compile target: ghes version=%s host=%s", version, host)
+	return &CompileTarget{Version: version, Host: host}, nil
+}
+
+// IsGHES returns true when compiling for GitHub Enterprise Server
+func (t *CompileTarget) IsGHES() bool {
+	return t != nil && t.Host != ""
+}
+
+// String returns the target in --target form
+func (t *CompileTarget) String() string {
+	if !t.IsGHES() {
+		return CompileTargetGitHubCom
+	}
+	return CompileTargetGHESPrefix + t.Version
+}
+
+// SupportsVersion reports whether the target GHES version is at least minVersion
+func (t *CompileTarget) SupportsVersion(minVersion string) bool {
+	return compareGHESVersions(t.Version, minVersion) >= 0
+}
+
+// compareGHESVersions compares two dotted versions, returning -1, 0 or 1
+func compareGHESVersions(a, b string) int {
+	aParts := strings.Split(a, ".")
+	bParts := strings.Split(b, ".")
+	for i := range max(len(aParts), len(bParts)) {
+		var aNum, bNum int
+		if i < len(aParts) {
+			aNum, _ = strconv.Atoi(aParts[i])
+		}
+		if i < len(bParts) {
+			bNum, _ = strconv.Atoi(bParts[i])
+		}
+		if aNum != bNum {
+			if aNum < bNum {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
+
+// isGitHubComDomain reports whether a domain is served by github.com and has a GHES equivalent.
+// Copilot API hosts and ghcr.io are external services and are not rewritten.
+func isGitHubComDomain(domain string) bool {
+	switch domain {
+	case "github.com", "api.github.com", "github.githubassets.com", "github-cloud.s3.amazonaws.com":
+		return true
+	}
+	return strings.HasSuffix(domain, ".github.com") || strings.HasSuffix(domain, "githubusercontent.com")
+}
+
+// rewriteDomainsForTarget rewrites github.com domains in a comma-separated allow-list to the GHES host.
+// The API and web UI map to the host itself; raw content, codeload, LFS and packages map to its
+// subdomains, which GHES uses when subdomain isolation is enabled.
+func rewriteDomainsForTarget(domains string, target *CompileTarget) string {
+	if !target.IsGHES() || domains == "" {
+		return domains
+	}
+
+	rewritten := make(map[string]bool)
+	for domain := range strings.SplitSeq(domains, ",") {
+		switch {
+		case domain == "github.com" || domain == "api.github.com":
+			rewritten[target.Host] = true
+		case isGitHubComDomain(domain):
+			rewritten[target.Host] = true
+			rewritten["*."+target.Host] = true
+		default:
+			rewritten[domain] = true
+		}
+	}
+
+	result := strings.Join(slices.Sorted(maps.Keys(rewritten)), ",")
+	compileTargetLog.Printf("Rewrote allowed domains for %s: %d -> %d entries", target, strings.Count(domains, ",")+1, len(rewritten))
+	return result
+}
+
+// applyCompileTargetToGitHubTool adjusts the GitHub tool configuration for the compile target.
+// GHES has no hosted GitHub MCP endpoint, so the GitHub MCP server runs locally. The renderers
+// point it at the GHES host through the GITHUB_HOST environment variable.
+func (c *Compiler) applyCompileTargetToGitHubTool(githubConfig map[string]any) {
+	if !c.target.IsGHES() {
+		return
+	}
+
+	if getGitHubType(githubConfig) == "remote" {
+		compileTargetLog.Print("Switching GitHub MCP server from remote to local mode for GHES target")
+		fmt.Fprintln(os.Stderr, console.FormatWarningMessage("The remote GitHub MCP server is not available on GitHub Enterprise Server; using local mode"))
+		c.IncrementWarningCount()
+	}
+	delete(githubConfig, "mode")
+}
+
+// compileTargetGitHubHost returns the URL of the GHES host that the local GitHub MCP server
+// connects to, or an empty string for github.com
+func compileTargetGitHubHost(workflowData *WorkflowData) string {
+	if workflowData == nil || !workflowData.CompileTarget.IsGHES() {
+		return ""
+	}
+	return "https://" + workflowData.CompileTarget.Host
+}
@@ -0,0 +1,144 @@
+//go:build !integration
+
+package workflow
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCompileTarget(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		host    string
+		want    *CompileTarget
+		wantErr string
+	}{
+		{name: "empty", value: "", host: "https://github.example.com"},
+		{name: "github.com", value: "github.com", host: "https://github.example.com"},
+		{name: "ghes", value: "ghes:3.16", host: "https://github.example.com/", want: &CompileTarget{Version: "3.16", Host: "github.example.com"}},
+		{name: "ghes patch version", value: "ghes:3.16.2", host: "github.example.com", want: &CompileTarget{Version: "3.16.2", Host: "github.example.com"}},
+		{name: "unknown platform", value: "gitlab", host: "github.example.com", wantErr: "invalid compile target 'gitlab'"},
+		{name: "invalid version", value: "ghes:latest", host: "github.example.com", wantErr: "invalid GHES version 'latest'"},
+		{name: "github.com host", value: "ghes:3.16", host: "https://github.com", wantErr: "requires the GHES hostname"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			target, err := ParseCompileTarget(tt.value, tt.host)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, target)
+		})
+	}
+}
+
+func TestCompileTargetSupportsVersion(t *testing.T) {
+	target := &CompileTarget{Version: "3.8", Host: "github.example.com"}
+	assert.True(t, target.SupportsVersion("3.6"))
+	assert.True(t, target.SupportsVersion("3.8"))
+	assert.False(t, target.SupportsVersion("3.10"), "versions should compare numerically")
+	assert.Equal(t, "ghes:3.8", target.String())
+
+	var githubCom *CompileTarget
+	assert.False(t, githubCom.IsGHES())
+	assert.Equal(t, "github.com", githubCom.String())
+}
+
+func TestRewriteDomainsForTarget(t *testing.T) {
+	domains := "api.github.com,api.githubcopilot.com,codeload.github.com,github.com,raw.githubusercontent.com,registry.npmjs.org"
+
+	assert.Equal(t, domains, rewriteDomainsForTarget(domains, nil), "github.com targets should not be rewritten")
+
+	target := &CompileTarget{Version: "3.16", Host: "github.example.com"}
+	assert.Equal(t, "*.github.example.com,api.githubcopilot.com,github.example.com,registry.npmjs.org", rewriteDomainsForTarget(domains, target))
+}
+
+func TestApplyCompileTargetToGitHubTool(t *testing.T) {
+	compiler := NewCompiler()
+	compiler.SetCompileTarget(&CompileTarget{Version: "3.16", Host: "github.example.com"})
+
+	githubConfig := map[string]any{"mode": "remote", "args": []any{"--network", "host"}}
+	compiler.applyCompileTargetToGitHubTool(githubConfig)
+	compiler.applyCompileTargetToGitHubTool(githubConfig)
+
+	assert.NotContains(t, githubConfig, "mode")
+	assert.Equal(t, []any{"--network", "host"}, githubConfig["args"], "custom args should not be changed")
+	assert.Equal(t, 1, compiler.GetWarningCount(), "switching from remote mode should warn")
+}
+
+func TestCompileWorkflowForGHESTarget(t *testing.T) {
+	tmpDir := t.TempDir()
+	workflowsDir := filepath.Join(tmpDir, ".github", "workflows")
+	require.NoError(t, os.MkdirAll(workflowsDir, 0755))
+	require.NoError(t, os.MkdirAll(filepath.Join(tmpDir, ".github", "aw"), 0755))
+
+	// Mirror pins come from the GHES cache file, so seed it with the repository's github.com pins
+	pins, err := os.ReadFile(filepath.Join("..", "..", ".github", "aw", CacheFileName))
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, ".github", "aw", GHESCacheFileName), pins, 0644))
+
+	workflowPath := filepath.Join(workflowsDir, "triage.md")
+	require.NoError(t, os.WriteFile(workflowPath, []byte(`---
+on:
+  issues:
+    types: [opened]
+permissions:
+  contents: read
+  issues: read
+engine: copilot
+tools:
+  github:
+    mode: remote
+    toolsets: [issues]
+    args: ["--network", "host"]
+---
+Triage the issue.
+`), 0644))
+
+	compiler := NewCompiler(WithGitRoot(tmpDir), WithActionMode(ActionModeDev))
+	compiler.SetCompileTarget(&CompileTarget{Version: "3.16", Host: "github.example.com"})
+	require.NoError(t, compiler.CompileWorkflow(workflowPath))
+
+	lockContent, err := os.ReadFile(filepath.Join(workflowsDir, "triage.lock.yml"))
+	require.NoError(t, err)
+	lock := string(lockContent)
+
+	assert.Contains(t, lock, "# Compile target: ghes:3.16 (github.example.com)")
+
+	// The gateway configuration must be valid JSON once the shell has expanded the heredoc
+	_, gatewayConfig, found := strings.Cut(lock, "cat << GH_AW_MCP_CONFIG_EOF | bash /opt/gh-aw/actions/start_mcp_gateway.sh\n")
+	require.True(t, found, "lock file should start the MCP gateway")
+	gatewayConfig, _, found = strings.Cut(gatewayConfig, "GH_AW_MCP_CONFIG_EOF")
+	require.True(t, found, "gateway configuration should be terminated")
+	var gateway struct {
+		MCPServers map[string]struct {
+			Args []string          `json:"args"`
+			Env  map[string]string `json:"env"`
+		} `json:"mcpServers"`
+	}
+	expanded := strings.NewReplacer(`\$`, "$", "$MCP_GATEWAY_PORT", "80").Replace(gatewayConfig)
+	require.NoError(t, json.Unmarshal([]byte(expanded), &gateway), "gateway configuration should be valid JSON")
+	require.Contains(t, gateway.MCPServers, "github")
+	assert.Equal(t, "https://github.example.com", gateway.MCPServers["github"].Env["GITHUB_HOST"], "GitHub MCP server should point at the GHES host")
+	assert.Equal(t, []string{"--network", "host"}, gateway.MCPServers["github"].Args, "custom args should be kept")
+	assert.NotContains(t, lock, "api.githubcopilot.com/mcp", "the remote GitHub MCP server should not be used")
+	assert.Contains(t, lock, `--allow-domains "*.github.example.com,`)
+	for line := range strings.SplitSeq(lock, "\n") {
+		if strings.Contains(line, "--allow-domains") || strings.Contains(line, "GH_AW_ALLOWED_DOMAINS") {
+			assert.NotContains(t, line, ",github.com,")
+			assert.NotContains(t, line, "api.github.com")
+		}
+	}
+}
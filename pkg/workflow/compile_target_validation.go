@@ -0,0 +1,187 @@
+// This file validates compiled workflows against a GitHub Enterprise Server compile target.
+//
+// After the lock file is generated for a GHES target, the compiler:
+//   - re-pins every action reference from the GHES mirror, failing when an action
+//     has not been mirrored to the instance
+//   - rejects events, permissions, contexts and safe outputs that the target
+//     GHES version does not provide
+
+package workflow
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/goccy/go-yaml"
+)
+
+// ghesEventMinVersions lists workflow events that require a minimum GHES version
+var ghesEventMinVersions = map[string]string{
+	"discussion":         "3.6",
+	"discussion_comment": "3.6",
+}
+
+// ghesPermissionMinVersions lists token permissions that require a minimum GHES version
+var ghesPermissionMinVersions = map[string]string{
+	"id-token": "3.5",
+}
+
+// ghesUnsupportedPermissions lists token permissions for github.com-only services
+var ghesUnsupportedPermissions = []string{"models"}
+
+// ghesVarsContextMinVersion is the GHES version that introduced the vars context
+const ghesVarsContextMinVersion = "3.8"
+
+// lockFileUsesPattern matches action references in a generated lock file,
+// capturing the prefix, the action, the ref and the optional version comment
+var lockFileUsesPattern = regexp.MustCompile(`^(\s*(?:-\s+)?uses:\s+)([^\s@]+)@([^\s#]+)(?:\s+#\s+(\S+))?\s*$`)
+
+// applyCompileTarget pins actions from the GHES mirror and validates feature availability
+// for the compile target. It returns the lock file content unchanged for github.com.
+func (c *Compiler) applyCompileTarget(data *WorkflowData, yamlContent string) (string, error) {
+	if !data.CompileTarget.IsGHES() {
+		return yamlContent, nil
+	}
+	compileTargetLog.Printf("Applying compile target %s to lock file", data.CompileTarget)
+
+	pinned, err := pinActionsFromGHESMirror(data, yamlContent)
+	if err != nil {
+		return "", err
+	}
+
+	if err := c.validateCompileTargetFeatures(data, pinned); err != nil {
+		return "", err
+	}
+
+	return pinned, nil
+}
+
+// pinActionsFromGHESMirror rewrites action references in the lock file to the SHAs of the
+// actions mirrored to the GHES instance. Actions pinned from github.com keep their tag in the
+// version comment, which is used to look them up in the mirror.
+func pinActionsFromGHESMirror(data *WorkflowData, yamlContent string) (string, error) {
+	if data.ActionResolver == nil {
+		compileTargetLog.Print("No action resolver available, skipping GHES mirror pinning")
+		return yamlContent, nil
+	}
+
+	var missing []string
+	lines := strings.Split(yamlContent, "\n")
+	for i, line := range lines {
+		match := lockFileUsesPattern.FindStringSubmatch(line)
+		if match == nil {
+			continue
+		}
+		prefix, repo, ref, version := match[1], match[2], match[3], match[4]
+		if strings.HasPrefix(repo, "./") || strings.HasPrefix(repo, "docker://") {
+			continue
+		}
+		if version == "" {
+			if isValidFullSHA(ref) {
+				// SHAs are preserved when actions are mirrored, so an untagged SHA is kept as-is
+				compileTargetLog.Printf("Keeping untagged SHA reference %s@%s", repo, ref)
+				continue
+			}
+			version = ref
+		}
+
+		sha, err := data.ActionResolver.ResolveSHA(repo, version)
+		if err != nil {
+			compileTargetLog.Printf("Action %s@%s is not available in the GHES mirror: %v", repo, version, err)
+			if key := formatActionCacheKey(repo, version); !slices.Contains(missing, key) {
+				missing = append(missing, key)
+			}
+			continue
+		}
+		lines[i] = prefix + formatActionReference(repo, sha, version)
+	}
+
+	if len(missing) > 0 {
+		slices.Sort(missing)
+		return "", fmt.Errorf("actions are not available on %s: %s. Mirror them to the instance with actions-sync, or add their SHAs to .github/aw/%s",
+			data.CompileTarget.Host, strings.Join(missing, ", "), GHESCacheFileName)
+	}
+
+	return strings.Join(lines, "\n"), nil
+}
+
+// validateCompileTargetFeatures checks that the lock file only uses events, permissions,
+// contexts and safe outputs available on the target GHES version
+func (c *Compiler) validateCompileTargetFeatures(data *WorkflowData, yamlContent string) error {
+	target := data.CompileTarget
+	collector := NewErrorCollector(c.failFast)
+
+	var workflow map[string]any
+	if err := yaml.Unmarshal([]byte(yamlContent), &workflow); err != nil {
+		return fmt.Errorf("failed to parse generated workflow for compile target validation: %w", err)
+	}
+
+	if on, ok := workflow["on"].(map[string]any); ok {
+		for _, event := range sortedMapKeys(on) {
+			if minVersion, ok := ghesEventMinVersions[event]; ok && !target.SupportsVersion(minVersion) {
+				if err := collector.Add(fmt.Errorf("the '%s' event requires GHES %s or later (target is %s)", event, minVersion, target)); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	for _, permission := range lockFilePermissionScopes(workflow) {
+		if slices.Contains(ghesUnsupportedPermissions, permission) {
+			if err := collector.Add(fmt.Errorf("the '%s' permission is not available on GitHub Enterprise Server", permission)); err != nil {
+				return err
+			}
+		} else if minVersion, ok := ghesPermissionMinVersions[permission]; ok && !target.SupportsVersion(minVersion) {
+			if err := collector.Add(fmt.Errorf("the '%s' permission requires GHES %s or later (target is %s)", permission, minVersion, target)); err != nil {
+				return err
+			}
+		}
+	}
+
+	if strings.Contains(yamlContent, "${{ vars.") && !target.SupportsVersion(ghesVarsContextMinVersion) {
+		if err := collector.Add(fmt.Errorf("the vars context requires GHES %s or later (target is %s)", ghesVarsContextMinVersion, target)); err != nil {
+			return err
+		}
+	}
+
+	// The Copilot coding agent is only available on github.com
+	if data.SafeOutputs != nil {
+		if data.SafeOutputs.AssignToAgent != nil {
+			if err := collector.Add(errors.New("safe-outputs.assign-to-agent uses the Copilot coding agent, which is not available on GitHub Enterprise Server")); err != nil {
+				return err
+			}
+		}
+		if data.SafeOutputs.CreateAgentSessions != nil {
+			if err := collector.Add(errors.New("safe-outputs.create-agent-session uses the Copilot coding agent, which is not available on GitHub Enterprise Server")); err != nil {
+				return err
+			}
+		}
+	}
+
+	return collector.FormattedError("compile target")
+}
+
+// lockFilePermissionScopes returns the sorted permission scopes granted at the workflow or job level
+func lockFilePermissionScopes(workflow map[string]any) []string {
+	scopes := make(map[string]bool)
+	addScopes := func(permissions any) {
+		if permissionMap, ok := permissions.(map[string]any); ok {
+			for scope := range permissionMap {
+				scopes[scope] = true
+			}
+		}
+	}
+
+	addScopes(workflow["permissions"])
+	if jobs, ok := workflow["jobs"].(map[string]any); ok {
+		for _, job := range jobs {
+			if jobMap, ok := job.(map[string]any); ok {
+				addScopes(jobMap["permissions"])
+			}
+		}
+	}
+	return sortedMapKeys(scopes)
+}
@@ -0,0 +1,87 @@
+//go:build !integration
+
+package workflow
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newGHESTestData(t *testing.T, version string) *WorkflowData {
+	cache := NewActionCacheWithFile(t.TempDir(), GHESCacheFileName)
+	cache.Set("actions/checkout", "v5", "1111111111111111111111111111111111111111")
+	cache.Set("github/codeql-action/upload-sarif", "v3", "2222222222222222222222222222222222222222")
+	resolver := NewActionResolverForHost(cache, "github.example.com")
+	// Avoid network lookups for actions missing from the mirror
+	resolver.failedResolutions[formatActionCacheKey("actions/cache", "v4")] = true
+
+	return &WorkflowData{
+		CompileTarget:  &CompileTarget{Version: version, Host: "github.example.com"},
+		ActionCache:    cache,
+		ActionResolver: resolver,
+	}
+}
+
+func TestPinActionsFromGHESMirror(t *testing.T) {
+	data := newGHESTestData(t, "3.16")
+
+	lock := `jobs:
+  agent:
+    steps:
+      - name: Checkout
+        uses: actions/checkout@93cb6efe18208431cddfb8368fd83d5badbf9bfd # v5
+      - uses: github/codeql-action/upload-sarif@v3
+      - uses: ./actions/setup
+      - uses: docker://alpine:3
+      - uses: owner/custom@0123456789abcdef0123456789abcdef01234567
+`
+	pinned, err := pinActionsFromGHESMirror(data, lock)
+	require.NoError(t, err)
+	assert.Contains(t, pinned, "        uses: actions/checkout@1111111111111111111111111111111111111111 # v5\n")
+	assert.Contains(t, pinned, "      - uses: github/codeql-action/upload-sarif@2222222222222222222222222222222222222222 # v3\n")
+	assert.Contains(t, pinned, "      - uses: ./actions/setup\n")
+	assert.Contains(t, pinned, "      - uses: docker://alpine:3\n")
+	assert.Contains(t, pinned, "owner/custom@0123456789abcdef0123456789abcdef01234567\n", "untagged SHAs should be kept")
+
+	_, err = pinActionsFromGHESMirror(data, lock+"      - uses: actions/cache@0057852bfaa89a56745cba8c7296529d2fc39830 # v4\n")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "actions are not available on github.example.com: actions/cache@v4")
+	assert.Contains(t, err.Error(), GHESCacheFileName)
+}
+
+func TestValidateCompileTargetFeatures(t *testing.T) {
+	lock := `on:
+  discussion:
+    types: [created]
+permissions: {}
+jobs:
+  agent:
+    permissions:
+      models: read
+      id-token: write
+    steps:
+      - run: echo "${{ vars.MODEL }}"
+`
+
+	t.Run("old GHES version", func(t *testing.T) {
+		data := newGHESTestData(t, "3.4")
+		data.SafeOutputs = &SafeOutputsConfig{AssignToAgent: &AssignToAgentConfig{}}
+
+		err := NewCompiler().validateCompileTargetFeatures(data, lock)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "Found 5 compile target errors")
+		assert.Contains(t, err.Error(), "the 'discussion' event requires GHES 3.6 or later (target is ghes:3.4)")
+		assert.Contains(t, err.Error(), "the 'models' permission is not available on GitHub Enterprise Server")
+		assert.Contains(t, err.Error(), "the 'id-token' permission requires GHES 3.5 or later")
+		assert.Contains(t, err.Error(), "the vars context requires GHES 3.8 or later")
+		assert.Contains(t, err.Error(), "safe-outputs.assign-to-agent uses the Copilot coding agent")
+	})
+
+	t.Run("current GHES version", func(t *testing.T) {
+		err := NewCompiler().validateCompileTargetFeatures(newGHESTestData(t, "3.16"), lock)
+		require.Error(t, err)
+		assert.Equal(t, "the 'models' permission is not available on GitHub Enterprise Server", err.Error())
+	})
+}
@@ -155,6 +155,12 @@ func (c *Compiler) validateWorkflowData(workflowData *WorkflowData, markdownPath
 		return formatCompilerError(markdownPath, "error", err.Error(), err)
 	}
 
+	// Validate send-webhook endpoints against safe-outputs allowed-domains
+	log.Printf("Validating safe-outputs send-webhook")
+	if err := c.validateSendWebhook(workflowData.SafeOutputs); err != nil {
+		return formatCompilerError(markdownPath, "error", err.Error(), err)
+	}
+
 	// Validate network allowed domains configuration
 	log.Printf("Validating network allowed domains")
 	if err := c.validateNetworkAllowedDomains(workflowData.NetworkPermissions); err != nil {
@@ -167,6 +173,39 @@ func (c *Compiler) validateWorkflowData(workflowData *WorkflowData, markdownPath
 		return formatCompilerError(markdownPath, "error", err.Error(), err)
 	}
 
+	// Validate safe-inputs tool schemas
+	log.Printf("Validating safe-inputs tool schemas")
+	if err := validateSafeInputs(workflowData.SafeInputs); err != nil {
+		return formatCompilerError(markdownPath, "error", err.Error(), err)
+	}
+
+	// Validate agent stages
+	log.Printf("Validating agent stages")
+	if err := c.validateAgentStages(workflowData); err != nil {
+		return formatCompilerError(markdownPath, "error", err.Error(), err)
+	}
+
+	// Validate matrix variants
+	log.Printf("Validating matrix variants")
+	if err := c.validateMatrixConfig(workflowData); err != nil {
+		return formatCompilerError(markdownPath, "error", err.Error(), err)
+	}
+
+	// Validate safe output and MCP server argument constraints
+	log.Printf("Validating tool constraints")
+	if err := validateToolConstraints(workflowData, markdownPath); err != nil {
+		return formatCompilerError(markdownPath, "error", err.Error(), err)
+	}
+	if err := validateMCPToolConstraints(workflowData); err != nil {
+		return formatCompilerError(markdownPath, "error", err.Error(), err)
+	}
+
+	// Validate prompt template variables, loops and partials
+	log.Printf("Validating prompt templates")
+	if err := validatePromptTemplates(workflowData, markdownPath); err != nil {
+		return formatCompilerError(markdownPath, "error", err.Error(), err)
+	}
+
 	// Validate labels configuration
 	log.Printf("Validating labels")
 	if err := validateLabels(workflowData); err != nil {
@@ -223,6 +262,10 @@ func (c *Compiler) validateWorkflowData(workflowData *WorkflowData, markdownPath
 		c.IncrementWarningCount()
 	}
 
+	// Warn when the estimated prompt size exceeds the prompt budget
+	log.Printf("Checking prompt budget")
+	c.checkPromptBudget(workflowData, markdownPath)
+
 	// Validate workflow_run triggers have branch restrictions
 	log.Printf("Validating workflow_run triggers for branch restrictions")
 	if err := c.validateWorkflowRunBranches(workflowData, markdownPath); err != nil {
@@ -285,6 +328,10 @@ Ensure proper audience validation and trust policies are configured.`
 		}
 	}
 
+	// Warn about write permissions the agent job does not need
+	log.Printf("Checking for excess write permissions")
+	c.checkExcessWritePermissions(workflowData, markdownPath)
+
 	// Validate GitHub tools against enabled toolsets
 	log.Printf("Validating GitHub tools against enabled toolsets")
 	if workflowData.ParsedTools != nil && workflowData.ParsedTools.GitHub != nil {
@@ -345,6 +392,17 @@ func (c *Compiler) generateAndValidateYAML(workflowData *WorkflowData, markdownP
 		return "", formatCompilerError(markdownPath, "error", fmt.Sprintf("failed to generate YAML: %v", err), err)
 	}
 
+	// Fail on actions that could not be pinned from the offline bundle
+	if err := c.validateOfflineActions(workflowData); err != nil {
+		return "", formatCompilerError(markdownPath, "error", fmt.Sprintf("offline bundle validation failed: %v", err), err)
+	}
+
+	// Pin actions from the GHES mirror and check feature availability for GHES targets
+	yamlContent, err = c.applyCompileTarget(workflowData, yamlContent)
+	if err != nil {
+		return "", formatCompilerError(markdownPath, "error", fmt.Sprintf("compile target validation failed: %v", err), err)
+	}
+
 	// Always validate expression sizes - this is a hard limit from GitHub Actions (21KB)
 	// that cannot be bypassed, so we validate it unconditionally
 	log.Print("Validating expression sizes")
@@ -389,6 +447,10 @@ func (c *Compiler) generateAndValidateYAML(workflowData *WorkflowData, markdownP
 		// Validate container images used in MCP configurations
 		log.Print("Validating container images")
 		if err := c.validateContainerImages(workflowData); err != nil {
+			// Images missing from an offline bundle are errors, since they cannot be checked later
+			if c.IsOffline() {
+				return "", formatCompilerError(markdownPath, "error", fmt.Sprintf("container image validation failed: %v", err), err)
+			}
 			// Treat container image validation failures as warnings, not errors
 			// This is because validation may fail due to auth issues locally (e.g., private registries)
 			fmt.Fprintln(os.Stderr, formatCompilerMessage(markdownPath, "warning", fmt.Sprintf("container image validation failed: %v", err)))
@@ -538,7 +600,15 @@ func (c *Compiler) CompileWorkflowData(workflowData *WorkflowData, markdownPath
 	}
 
 	// Write output
-	return c.writeWorkflowOutput(lockFile, yamlContent, markdownPath)
+	if err := c.writeWorkflowOutput(lockFile, yamlContent, markdownPath); err != nil {
+		return err
+	}
+
+	// Sign provenance for the lock file if a signing key is configured
+	if c.provenanceKey != nil && !c.noEmit {
+		return c.writeProvenance(workflowData, markdownPath, lockFile, yamlContent)
+	}
+	return nil
 }
 
 // ParseWorkflowFile parses a markdown workflow file and extracts all necessary data
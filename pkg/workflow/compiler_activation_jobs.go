@@ -691,7 +691,14 @@ func (c *Compiler) buildActivationJob(data *WorkflowData, preActivationJobCreate
 	steps = append(steps, fmt.Sprintf("        uses: %s\n", GetActionPin("actions/upload-artifact")))
 	steps = append(steps, "        with:\n")
 	steps = append(steps, "          name: prompt\n")
-	steps = append(steps, "          path: /tmp/gh-aw/aw-prompts/prompt.txt\n")
+	if isPromptTrimEnabled(data) {
+		// Keep the untrimmed prompt next to the trimmed one so the agent can read trimmed sections
+		steps = append(steps, "          path: |\n")
+		steps = append(steps, "            /tmp/gh-aw/aw-prompts/prompt.txt\n")
+		steps = append(steps, "            "+promptFullPath+"\n")
+	} else {
+		steps = append(steps, "          path: /tmp/gh-aw/aw-prompts/prompt.txt\n")
+	}
 	steps = append(steps, "          retention-days: 1\n")
 
 	// Set permissions - activation job always needs contents:read for GitHub API access
@@ -796,6 +803,11 @@ func (c *Compiler) buildMainJob(data *WorkflowData, activationJobCreated bool) (
 		depends = []string{string(constants.ActivationJobName)} // Depend on the activation job only if it exists
 	}
 
+	// The final agent job runs after the last agent stage
+	if data.CurrentStage == nil && len(data.AgentStages) > 0 {
+		depends = append(depends, lastAgentStageJobName(data))
+	}
+
 	// Add custom jobs as dependencies only if they don't depend on pre_activation or agent
 	// Custom jobs that depend on pre_activation are now dependencies of activation,
 	// so the agent job gets them transitively through activation
@@ -204,11 +204,21 @@ func (c *Compiler) buildJobs(data *WorkflowData, markdownPath string) error {
 		return err
 	}
 
+	// Build agent stage jobs that run before the main job (stages)
+	if err := c.buildAgentStageJobs(data, activationJobCreated); err != nil {
+		return err
+	}
+
 	// Build main workflow job
 	if err := c.buildMainJobWrapper(data, activationJobCreated); err != nil {
 		return err
 	}
 
+	// Build agent and detection jobs of the other matrix variants (matrix)
+	if err := c.buildMatrixVariantJobs(data, activationJobCreated); err != nil {
+		return err
+	}
+
 	// Build safe outputs jobs if configured
 	if err := c.buildSafeOutputsJobs(data, string(constants.AgentJobName), markdownPath); err != nil {
 		return fmt.Errorf("failed to build safe outputs jobs: %w", err)
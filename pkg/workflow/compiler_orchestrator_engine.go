@@ -21,6 +21,7 @@ type engineSetupResult struct {
 	networkPermissions *NetworkPermissions
 	sandboxConfig      *SandboxConfig
 	importsResult      *parser.ImportsResult
+	mergeProvenance    *MergeProvenance
 }
 
 // setupEngineAndImports configures the AI engine, processes imports, and validates network/sandbox settings.
@@ -29,13 +30,22 @@ type engineSetupResult struct {
 // - Import processing and merging
 // - Network permissions setup
 // - Sandbox configuration
+// - Merge directives of the main workflow
 // - Strict mode validations
-func (c *Compiler) setupEngineAndImports(result *parser.FrontmatterResult, cleanPath string, content []byte, markdownDir string) (*engineSetupResult, error) {
+func (c *Compiler) setupEngineAndImports(result *parser.FrontmatterResult, cleanPath string, content []byte, markdownDir string, mergeDirectives *MergeDirectives) (*engineSetupResult, error) {
 	orchestratorEngineLog.Printf("Setting up engine and processing imports")
 
 	// Extract AI engine setting from frontmatter
 	engineSetting, engineConfig := c.ExtractEngineConfig(result.Frontmatter)
 
+	// With a matrix, the agent job runs the applied (or first) variant
+	if primary := c.extractMatrixConfig(result.Frontmatter).PrimaryVariant(); primary != nil {
+		if _, hasEngine := result.Frontmatter["engine"]; hasEngine {
+			return nil, errors.New("engine and matrix cannot be used together: each matrix variant sets its own engine")
+		}
+		engineSetting, engineConfig = primary.EngineSetting, primary.EngineConfig
+	}
+
 	// Extract network permissions from frontmatter
 	networkPermissions := c.extractNetworkPermissions(result.Frontmatter)
 
@@ -55,18 +65,7 @@ func (c *Compiler) setupEngineAndImports(result *parser.FrontmatterResult, clean
 
 	// Check strict mode in frontmatter
 	// Priority: CLI flag > frontmatter > schema default (true)
-	if !c.strictMode {
-		// CLI flag not set, check frontmatter
-		if strictValue, exists := result.Frontmatter["strict"]; exists {
-			// Frontmatter explicitly sets strict mode
-			if strictBool, ok := strictValue.(bool); ok {
-				c.strictMode = strictBool
-			}
-		} else {
-			// Neither CLI nor frontmatter set - use schema default (true)
-			c.strictMode = true
-		}
-	}
+	c.strictMode = c.isStrictModeEnabled(result.Frontmatter)
 
 	// Perform strict mode validations
 	orchestratorEngineLog.Printf("Performing strict mode validation (strict=%v)", c.strictMode)
@@ -114,6 +113,17 @@ func (c *Compiler) setupEngineAndImports(result *parser.FrontmatterResult, clean
 		return nil, err // Error is already formatted with source location
 	}
 
+	// Apply the main workflow's merge directives to the imported configuration
+	provenance, err := c.applyMergeDirectives(result.Frontmatter, importsResult, mergeDirectives, cleanPath)
+	if err != nil {
+		orchestratorEngineLog.Printf("Applying merge directives failed: %v", err)
+		return nil, err
+	}
+	if c.explainMerge {
+		fmt.Fprintln(os.Stderr, console.FormatInfoMessage("Merge provenance for "+provenance.Workflow+":"))
+		fmt.Fprint(os.Stderr, console.RenderStruct(provenance.Entries))
+	}
+
 	// Security scan imported markdown files' content (skip non-markdown imports like .yml)
 	for _, importedFile := range importsResult.ImportedFiles {
 		// Strip section references (e.g., "shared/foo.md#Section")
@@ -241,18 +251,7 @@ func (c *Compiler) setupEngineAndImports(result *parser.FrontmatterResult, clean
 	// Re-evaluate strict mode for firewall and network validation
 	// (it was restored after validateStrictMode but we need it again)
 	initialStrictModeForFirewall := c.strictMode
-	if !c.strictMode {
-		// CLI flag not set, check frontmatter
-		if strictValue, exists := result.Frontmatter["strict"]; exists {
-			// Frontmatter explicitly sets strict mode
-			if strictBool, ok := strictValue.(bool); ok {
-				c.strictMode = strictBool
-			}
-		} else {
-			// Neither CLI nor frontmatter set - use schema default (true)
-			c.strictMode = true
-		}
-	}
+	c.strictMode = c.isStrictModeEnabled(result.Frontmatter)
 
 	// Validate firewall is enabled in strict mode for copilot with network restrictions
 	orchestratorEngineLog.Printf("Validating strict firewall (strict=%v)", c.strictMode)
@@ -298,5 +297,6 @@ func (c *Compiler) setupEngineAndImports(result *parser.FrontmatterResult, clean
 		networkPermissions: networkPermissions,
 		sandboxConfig:      sandboxConfig,
 		importsResult:      importsResult,
+		mergeProvenance:    provenance,
 	}, nil
 }
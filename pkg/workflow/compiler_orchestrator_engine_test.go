@@ -41,7 +41,7 @@ Test content
 	require.NoError(t, err)
 
 	// Call setupEngineAndImports
-	result, err := compiler.setupEngineAndImports(frontmatterResult, testFile, content, tmpDir)
+	result, err := compiler.setupEngineAndImports(frontmatterResult, testFile, content, tmpDir, nil)
 	require.NoError(t, err, "Valid setup should succeed")
 	require.NotNil(t, result)
 
@@ -73,7 +73,7 @@ on: push
 	frontmatterResult, err := parser.ExtractFrontmatterFromContent(string(content))
 	require.NoError(t, err)
 
-	result, err := compiler.setupEngineAndImports(frontmatterResult, testFile, content, tmpDir)
+	result, err := compiler.setupEngineAndImports(frontmatterResult, testFile, content, tmpDir, nil)
 	require.NoError(t, err)
 	require.NotNil(t, result)
 
@@ -103,7 +103,7 @@ engine: copilot
 	frontmatterResult, err := parser.ExtractFrontmatterFromContent(string(content))
 	require.NoError(t, err)
 
-	result, err := compiler.setupEngineAndImports(frontmatterResult, testFile, content, tmpDir)
+	result, err := compiler.setupEngineAndImports(frontmatterResult, testFile, content, tmpDir, nil)
 	require.NoError(t, err)
 	require.NotNil(t, result)
 
@@ -132,7 +132,7 @@ engine: invalid-engine-name
 	frontmatterResult, err := parser.ExtractFrontmatterFromContent(string(content))
 	require.NoError(t, err)
 
-	result, err := compiler.setupEngineAndImports(frontmatterResult, testFile, content, tmpDir)
+	result, err := compiler.setupEngineAndImports(frontmatterResult, testFile, content, tmpDir, nil)
 	require.Error(t, err, "Invalid engine should cause error")
 	assert.Nil(t, result)
 	assert.Contains(t, err.Error(), "invalid-engine-name")
@@ -202,7 +202,7 @@ strict: false
 			frontmatterResult, err := parser.ExtractFrontmatterFromContent(string(content))
 			require.NoError(t, err)
 
-			result, err := compiler.setupEngineAndImports(frontmatterResult, testFile, content, tmpDir)
+			result, err := compiler.setupEngineAndImports(frontmatterResult, testFile, content, tmpDir, nil)
 
 			if tt.shouldSucceed {
 				require.NoError(t, err, "Should succeed for test: %s", tt.name)
@@ -261,7 +261,7 @@ network:
 	frontmatterResult, err := parser.ExtractFrontmatterFromContent(string(content))
 	require.NoError(t, err)
 
-	result, err := compiler.setupEngineAndImports(frontmatterResult, testFile, content, tmpDir)
+	result, err := compiler.setupEngineAndImports(frontmatterResult, testFile, content, tmpDir, nil)
 	require.NoError(t, err)
 	require.NotNil(t, result)
 
@@ -291,7 +291,7 @@ engine: copilot
 	frontmatterResult, err := parser.ExtractFrontmatterFromContent(string(content))
 	require.NoError(t, err)
 
-	result, err := compiler.setupEngineAndImports(frontmatterResult, testFile, content, tmpDir)
+	result, err := compiler.setupEngineAndImports(frontmatterResult, testFile, content, tmpDir, nil)
 	require.NoError(t, err)
 	require.NotNil(t, result)
 
@@ -325,7 +325,7 @@ sandbox:
 	frontmatterResult, err := parser.ExtractFrontmatterFromContent(string(content))
 	require.NoError(t, err)
 
-	result, err := compiler.setupEngineAndImports(frontmatterResult, testFile, content, tmpDir)
+	result, err := compiler.setupEngineAndImports(frontmatterResult, testFile, content, tmpDir, nil)
 	require.NoError(t, err)
 	require.NotNil(t, result)
 
@@ -367,7 +367,7 @@ imports:
 	frontmatterResult, err := parser.ExtractFrontmatterFromContent(string(content))
 	require.NoError(t, err)
 
-	result, err := compiler.setupEngineAndImports(frontmatterResult, testFile, content, tmpDir)
+	result, err := compiler.setupEngineAndImports(frontmatterResult, testFile, content, tmpDir, nil)
 
 	// Should error due to conflicting engines
 	require.Error(t, err, "Conflicting engines should cause error")
@@ -399,7 +399,7 @@ network:
 	frontmatterResult, err := parser.ExtractFrontmatterFromContent(string(content))
 	require.NoError(t, err)
 
-	result, err := compiler.setupEngineAndImports(frontmatterResult, testFile, content, tmpDir)
+	result, err := compiler.setupEngineAndImports(frontmatterResult, testFile, content, tmpDir, nil)
 	require.NoError(t, err)
 	require.NotNil(t, result)
 
@@ -431,7 +431,7 @@ imports:
 	frontmatterResult, err := parser.ExtractFrontmatterFromContent(string(content))
 	require.NoError(t, err)
 
-	result, err := compiler.setupEngineAndImports(frontmatterResult, testFile, content, tmpDir)
+	result, err := compiler.setupEngineAndImports(frontmatterResult, testFile, content, tmpDir, nil)
 
 	// Should error due to missing import
 	require.Error(t, err, "Missing import should cause error")
@@ -461,7 +461,7 @@ permissions:
 	frontmatterResult, err := parser.ExtractFrontmatterFromContent(string(content))
 	require.NoError(t, err)
 
-	result, err := compiler.setupEngineAndImports(frontmatterResult, testFile, content, tmpDir)
+	result, err := compiler.setupEngineAndImports(frontmatterResult, testFile, content, tmpDir, nil)
 	require.NoError(t, err)
 	require.NotNil(t, result)
 }
@@ -487,7 +487,7 @@ engine: codex
 	frontmatterResult, err := parser.ExtractFrontmatterFromContent(string(content))
 	require.NoError(t, err)
 
-	result, err := compiler.setupEngineAndImports(frontmatterResult, testFile, content, tmpDir)
+	result, err := compiler.setupEngineAndImports(frontmatterResult, testFile, content, tmpDir, nil)
 	require.NoError(t, err)
 	require.NotNil(t, result)
 
@@ -20,6 +20,7 @@ type frontmatterParseResult struct {
 	frontmatterForValidation map[string]any
 	markdownDir              string
 	isSharedWorkflow         bool
+	mergeDirectives          *MergeDirectives
 }
 
 // parseFrontmatterSection reads the workflow file and parses its frontmatter.
@@ -67,6 +68,16 @@ func (c *Compiler) parseFrontmatterSection(markdownPath string) (*frontmatterPar
 		return nil, err
 	}
 
+	// Extract merge directives (e.g. "!remove") of main workflows before the settings are validated
+	var mergeDirectives *MergeDirectives
+	if _, isMainWorkflow := result.Frontmatter["on"]; isMainWorkflow {
+		mergeDirectives, err = extractMergeDirectives(result.Frontmatter)
+		if err != nil {
+			orchestratorFrontmatterLog.Printf("Merge directive extraction failed: %v", err)
+			return nil, formatCompilerError(cleanPath, "error", "invalid merge directive: "+err.Error(), err)
+		}
+	}
+
 	// Create a copy of frontmatter without internal markers for schema validation
 	// Keep the original frontmatter with markers for YAML generation
 	frontmatterForValidation := c.copyFrontmatterWithoutInternalMarkers(result.Frontmatter)
@@ -132,6 +143,7 @@ func (c *Compiler) parseFrontmatterSection(markdownPath string) (*frontmatterPar
 		frontmatterForValidation: frontmatterForValidation,
 		markdownDir:              filepath.Dir(cleanPath),
 		isSharedWorkflow:         false,
+		mergeDirectives:          mergeDirectives,
 	}, nil
 }
 
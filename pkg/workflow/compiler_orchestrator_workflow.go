@@ -36,7 +36,7 @@ func (c *Compiler) ParseWorkflowFile(markdownPath string) (*WorkflowData, error)
 	markdownDir := parseResult.markdownDir
 
 	// Setup engine and process imports
-	engineSetup, err := c.setupEngineAndImports(result, cleanPath, content, markdownDir)
+	engineSetup, err := c.setupEngineAndImports(result, cleanPath, content, markdownDir, parseResult.mergeDirectives)
 	if err != nil {
 		return nil, err
 	}
@@ -153,6 +153,8 @@ func (c *Compiler) buildInitialWorkflowData(
 		Source:                c.extractSource(result.Frontmatter),
 		TrackerID:             toolsResult.trackerID,
 		ImportedFiles:         importsResult.ImportedFiles,
+		ImportedFilePaths:     importsResult.ImportedFilePaths,
+		RemoteImports:         importsResult.RemoteImports,
 		ImportedMarkdown:      toolsResult.importedMarkdown, // Only imports WITH inputs
 		ImportPaths:           toolsResult.importPaths,      // Import paths for runtime-import macros (imports without inputs)
 		MainWorkflowMarkdown:  toolsResult.mainWorkflowMarkdown,
@@ -176,12 +178,14 @@ func (c *Compiler) buildInitialWorkflowData(
 		TrialMode:             c.trialMode,
 		TrialLogicalRepo:      c.trialLogicalRepoSlug,
 		StrictMode:            c.strictMode,
+		CompileTarget:         c.target,
 		SecretMasking:         toolsResult.secretMasking,
 		ParsedFrontmatter:     toolsResult.parsedFrontmatter,
 		RawFrontmatter:        result.Frontmatter,
 		HasExplicitGitHubTool: toolsResult.hasExplicitGitHubTool,
 		ActionMode:            c.actionMode,
 		InlinedImports:        inlinedImports,
+		MergeProvenance:       engineSetup.mergeProvenance,
 	}
 }
 
@@ -480,6 +484,13 @@ func (c *Compiler) extractAdditionalConfigurations(
 		workflowData.SafeInputs = c.mergeSafeInputs(workflowData.SafeInputs, importsResult.MergedSafeInputs)
 	}
 
+	// Extract typed prompt template variables
+	workflowData.PromptVariables = c.extractPromptVariables(frontmatter)
+	workflowData.PromptBudget = c.extractPromptBudget(frontmatter)
+	workflowData.AgentStages = c.extractAgentStages(frontmatter)
+	workflowData.Matrix = c.extractMatrixConfig(frontmatter)
+	workflowData.MCPToolConstraints = extractMCPToolConstraints(workflowData.Tools)
+
 	// Extract safe-jobs from safe-outputs.jobs location
 	topSafeJobs := extractSafeJobsFromFrontmatter(frontmatter)
 
@@ -535,6 +546,11 @@ func (c *Compiler) extractAdditionalConfigurations(
 	// This ensures every workflow with safe-outputs has at least one meaningful action handler.
 	applyDefaultCreateIssue(workflowData)
 
+	// Without matrix.apply, the safe outputs of every matrix variant are only previewed
+	if workflowData.Matrix != nil && workflowData.Matrix.Apply == "" && workflowData.SafeOutputs != nil {
+		workflowData.SafeOutputs.Staged = true
+	}
+
 	return nil
 }
 
@@ -322,6 +322,7 @@ func (c *Compiler) applyDefaultTools(tools map[string]any, safeOutputs *SafeOutp
 			}
 			githubConfig["allowed"] = existingAllowed
 		}
+		c.applyCompileTargetToGitHubTool(githubConfig)
 		tools["github"] = githubConfig
 	}
 
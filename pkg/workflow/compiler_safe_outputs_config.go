@@ -542,6 +542,12 @@ var handlerRegistry = map[string]handlerBuilder{
 
 		return builder.Build()
 	},
+	"send_webhook": func(cfg *SafeOutputsConfig) map[string]any {
+		if cfg.SendWebhook == nil {
+			return nil
+		}
+		return buildSendWebhookHandlerConfig(cfg.SendWebhook, cfg.AllowedDomains)
+	},
 	"missing_tool": func(cfg *SafeOutputsConfig) map[string]any {
 		if cfg.MissingTool == nil {
 			return nil
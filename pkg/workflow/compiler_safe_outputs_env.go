@@ -97,6 +97,12 @@ func (c *Compiler) addAllSafeOutputConfigEnvVars(steps *[]string, data *Workflow
 		// Note: base_branch and max_patch_size are now in handler config JSON
 	}
 
+	// Send Webhook - endpoint URLs and signing secrets are only exposed to this step
+	if data.SafeOutputs.SendWebhook != nil {
+		compilerSafeOutputsEnvLog.Print("Processing send-webhook env vars")
+		addSendWebhookEnvVars(steps, data.SafeOutputs.SendWebhook)
+	}
+
 	if stagedFlagAdded {
 		_ = stagedFlagAdded // Mark as used for linter
 	}
@@ -81,14 +81,20 @@ func (c *Compiler) ParseWorkflowString(content string, virtualPath string) (*Wor
 		return nil, err
 	}
 
-	frontmatterForValidation := c.copyFrontmatterWithoutInternalMarkers(result.Frontmatter)
-
 	// Check if shared workflow (no 'on' field)
-	_, hasOnField := frontmatterForValidation["on"]
+	_, hasOnField := result.Frontmatter["on"]
 	if !hasOnField {
 		return nil, &SharedWorkflowError{Path: cleanPath}
 	}
 
+	// Extract merge directives before the settings are validated
+	mergeDirectives, err := extractMergeDirectives(result.Frontmatter)
+	if err != nil {
+		return nil, formatCompilerError(cleanPath, "error", "invalid merge directive: "+err.Error(), err)
+	}
+
+	frontmatterForValidation := c.copyFrontmatterWithoutInternalMarkers(result.Frontmatter)
+
 	// Validate frontmatter against schema
 	if err := parser.ValidateMainWorkflowFrontmatterWithSchemaAndLocation(frontmatterForValidation, cleanPath); err != nil {
 		return nil, err
@@ -102,10 +108,11 @@ func (c *Compiler) ParseWorkflowString(content string, virtualPath string) (*Wor
 		frontmatterForValidation: frontmatterForValidation,
 		markdownDir:              filepath.Dir(cleanPath),
 		isSharedWorkflow:         false,
+		mergeDirectives:          mergeDirectives,
 	}
 
 	// Setup engine and process imports
-	engineSetup, err := c.setupEngineAndImports(parseResult.frontmatterResult, parseResult.cleanPath, parseResult.content, parseResult.markdownDir)
+	engineSetup, err := c.setupEngineAndImports(parseResult.frontmatterResult, parseResult.cleanPath, parseResult.content, parseResult.markdownDir, parseResult.mergeDirectives)
 	if err != nil {
 		return nil, err
 	}
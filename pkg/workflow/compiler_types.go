@@ -1,6 +1,7 @@
 package workflow
 
 import (
+	"crypto/ed25519"
 	"os"
 
 	"github.com/github/gh-aw/pkg/logger"
@@ -119,6 +120,7 @@ type Compiler struct {
 	refreshStopTime         bool                // If true, regenerate stop-after times instead of preserving existing ones
 	forceRefreshActionPins  bool                // If true, clear action cache and resolve all actions from GitHub API
 	failFast                bool                // If true, stop at first validation error instead of collecting all errors
+	explainMerge            bool                // If true, print the provenance of settings merged from imports
 	actionCacheCleared      bool                // Tracks if action cache has already been cleared (for forceRefreshActionPins)
 	markdownPath            string              // Path to the markdown file being compiled (for context in dynamic tool generation)
 	actionMode              ActionMode          // Mode for generating JavaScript steps (inline vs custom actions)
@@ -141,6 +143,10 @@ type Compiler struct {
 	contentOverride         string              // If set, use this content instead of reading from disk (for Wasm/in-memory compilation)
 	skipHeader              bool                // If true, skip ASCII art header in generated YAML (for Wasm/editor mode)
 	inlinePrompt            bool                // If true, inline markdown content in YAML instead of using runtime-import macros (for Wasm builds)
+	target                  *CompileTarget      // Platform the workflows are compiled for (nil for github.com)
+	offlineBundle           *BundleManifest     // If set, compile without network access from an offline bundle
+	bundleDir               string              // Directory of the extracted offline bundle
+	provenanceKey           ed25519.PrivateKey  // If set, sign a provenance statement for each lock file
 }
 
 // NewCompiler creates a new workflow compiler with functional options.
@@ -203,6 +209,11 @@ func (c *Compiler) SetNoEmit(noEmit bool) {
 	c.noEmit = noEmit
 }
 
+// SetExplainMerge configures whether to print which file contributed each setting merged from imports
+func (c *Compiler) SetExplainMerge(explainMerge bool) {
+	c.explainMerge = explainMerge
+}
+
 // SetFileTracker sets the file tracker for tracking created files
 func (c *Compiler) SetFileTracker(tracker FileTracker) {
 	c.fileTracker = tracker
@@ -248,6 +259,16 @@ func (c *Compiler) SetActionTag(tag string) {
 	c.actionTag = tag
 }
 
+// SetCompileTarget sets the platform the workflows are compiled for (nil for github.com)
+func (c *Compiler) SetCompileTarget(target *CompileTarget) {
+	c.target = target
+}
+
+// GetCompileTarget returns the compile target (nil for github.com)
+func (c *Compiler) GetCompileTarget() *CompileTarget {
+	return c.target
+}
+
 // GetActionTag returns the action tag override (empty if not set)
 func (c *Compiler) GetActionTag() string {
 	return c.actionTag
@@ -306,6 +327,10 @@ func (c *Compiler) getSharedActionResolver() (*ActionCache, *ActionResolver) {
 		// Initialize cache and resolver on first use
 		// Use git root if provided, otherwise fall back to current working directory
 		baseDir := c.gitRoot
+		if c.IsOffline() {
+			// Offline compilation reads action pins only from the bundle
+			baseDir = c.bundleDir
+		}
 		if baseDir == "" {
 			cwd, err := os.Getwd()
 			if err != nil {
@@ -313,7 +338,12 @@ func (c *Compiler) getSharedActionResolver() (*ActionCache, *ActionResolver) {
 			}
 			baseDir = cwd
 		}
-		c.actionCache = NewActionCache(baseDir)
+		if c.target.IsGHES() {
+			// Actions on GHES come from the instance's mirror, so their pins are kept apart from github.com pins
+			c.actionCache = NewActionCacheWithFile(baseDir, GHESCacheFileName)
+		} else {
+			c.actionCache = NewActionCache(baseDir)
+		}
 
 		// Load existing cache unless force refresh is enabled
 		if !c.forceRefreshActionPins {
@@ -324,7 +354,12 @@ func (c *Compiler) getSharedActionResolver() (*ActionCache, *ActionResolver) {
 			c.actionCacheCleared = true
 		}
 
-		c.actionResolver = NewActionResolver(c.actionCache)
+		if c.target.IsGHES() {
+			c.actionResolver = NewActionResolverForHost(c.actionCache, c.target.Host)
+		} else {
+			c.actionResolver = NewActionResolver(c.actionCache)
+		}
+		c.actionResolver.offline = c.IsOffline()
 		logTypes.Print("Initialized shared action cache and resolver for compiler")
 	} else if c.forceRefreshActionPins && !c.actionCacheCleared {
 		// If cache already exists but force refresh is set and we haven't cleared it yet, clear it once
@@ -356,6 +391,12 @@ func (c *Compiler) getSharedImportCache() *parser.ImportCache {
 	return c.importCache
 }
 
+// SetImportCache sets the cache used to resolve remote imports instead of the shared
+// cache rooted at the current working directory
+func (c *Compiler) SetImportCache(cache *parser.ImportCache) {
+	c.importCache = cache
+}
+
 // GetSharedActionCache returns the shared action cache used by this compiler instance.
 // The cache is lazily initialized on first access and shared across all workflows.
 // This allows action SHA validation and other operations to reuse cached resolutions.
@@ -387,20 +428,22 @@ type SkipIfNoMatchConfig struct {
 // WorkflowData holds all the data needed to generate a GitHub Actions workflow
 type WorkflowData struct {
 	Name                  string
-	WorkflowID            string         // workflow identifier derived from markdown filename (basename without extension)
-	TrialMode             bool           // whether the workflow is running in trial mode
-	TrialLogicalRepo      string         // target repository slug for trial mode (owner/repo)
-	FrontmatterName       string         // name field from frontmatter (for code scanning alert driver default)
-	FrontmatterYAML       string         // raw frontmatter YAML content (rendered as comment in lock file for reference)
-	Description           string         // optional description rendered as comment in lock file
-	Source                string         // optional source field (owner/repo@ref/path) rendered as comment in lock file
-	TrackerID             string         // optional tracker identifier for created assets (min 8 chars, alphanumeric + hyphens/underscores)
-	ImportedFiles         []string       // list of files imported via imports field (rendered as comment in lock file)
-	ImportedMarkdown      string         // Only imports WITH inputs (for compile-time substitution)
-	ImportPaths           []string       // Import file paths for runtime-import macro generation (imports without inputs)
-	MainWorkflowMarkdown  string         // main workflow markdown without imports (for runtime-import)
-	IncludedFiles         []string       // list of files included via @include directives (rendered as comment in lock file)
-	ImportInputs          map[string]any // input values from imports with inputs (for github.aw.inputs.* substitution)
+	WorkflowID            string             // workflow identifier derived from markdown filename (basename without extension)
+	TrialMode             bool               // whether the workflow is running in trial mode
+	TrialLogicalRepo      string             // target repository slug for trial mode (owner/repo)
+	FrontmatterName       string             // name field from frontmatter (for code scanning alert driver default)
+	FrontmatterYAML       string             // raw frontmatter YAML content (rendered as comment in lock file for reference)
+	Description           string             // optional description rendered as comment in lock file
+	Source                string             // optional source field (owner/repo@ref/path) rendered as comment in lock file
+	TrackerID             string             // optional tracker identifier for created assets (min 8 chars, alphanumeric + hyphens/underscores)
+	ImportedFiles         []string           // list of files imported via imports field (rendered as comment in lock file)
+	ImportedFilePaths     map[string]string  // resolved path of each imported file (for provenance digests)
+	RemoteImports         []parser.ImportRef // remote imports with their resolved commit SHAs (recorded in lock metadata)
+	ImportedMarkdown      string             // Only imports WITH inputs (for compile-time substitution)
+	ImportPaths           []string           // Import file paths for runtime-import macro generation (imports without inputs)
+	MainWorkflowMarkdown  string             // main workflow markdown without imports (for runtime-import)
+	IncludedFiles         []string           // list of files included via @include directives (rendered as comment in lock file)
+	ImportInputs          map[string]any     // input values from imports with inputs (for github.aw.inputs.* substitution)
 	On                    string
 	Permissions           string
 	Network               string // top-level network permissions configuration
@@ -442,6 +485,13 @@ type WorkflowData struct {
 	SandboxConfig         *SandboxConfig       // parsed sandbox configuration (AWF or SRT)
 	SafeOutputs           *SafeOutputsConfig   // output configuration for automatic output routes
 	SafeInputs            *SafeInputsConfig    // safe-inputs configuration for custom MCP tools
+	PromptVariables       []*PromptVariable    // typed prompt template variables (sorted by name)
+	PromptBudget          *PromptBudgetConfig  // prompt size budget and runtime trimming (prompt-budget)
+	AgentStages           []*AgentStage        // agent stages that run before the agent job (stages)
+	CurrentStage          *AgentStage          // set when building the job of an agent stage
+	Matrix                *MatrixConfig        // engine/model variants that run in parallel (matrix)
+	MCPToolConstraints    MCPToolConstraints   // per-tool argument constraints enforced by the MCP gateway
+	MatrixVariant         *MatrixVariant       // set when building the job of a secondary matrix variant
 	Roles                 []string             // permission levels required to trigger workflow
 	Bots                  []string             // allow list of bot identifiers that can trigger workflow
 	RateLimit             *RateLimitConfig     // rate limiting configuration for workflow triggers
@@ -455,6 +505,8 @@ type WorkflowData struct {
 	ActionCache           *ActionCache         // cache for action pin resolutions
 	ActionResolver        *ActionResolver      // resolver for action pins
 	StrictMode            bool                 // strict mode for action pinning
+	CompileTarget         *CompileTarget       // platform the workflow is compiled for (nil for github.com)
+	MissingBundleActions  []string             // actions not pinned in the offline bundle (key: "repo@version")
 	SecretMasking         *SecretMaskingConfig // secret masking configuration
 	ParsedFrontmatter     *FrontmatterConfig   // cached parsed frontmatter configuration (for performance optimization)
 	RawFrontmatter        map[string]any       // raw parsed frontmatter map (for passing to hash functions without re-parsing)
@@ -462,6 +514,7 @@ type WorkflowData struct {
 	ActionMode            ActionMode           // action mode for workflow compilation (dev, release, script)
 	HasExplicitGitHubTool bool                 // true if tools.github was explicitly configured in frontmatter
 	InlinedImports        bool                 // if true, inline all imports at compile time (from inlined-imports frontmatter field)
+	MergeProvenance       *MergeProvenance     // file that contributed each setting merged from imports (for gh aw explain)
 }
 
 // BaseSafeOutputConfig holds common configuration fields for all safe output types
@@ -507,6 +560,7 @@ type SafeOutputsConfig struct {
 	LinkSubIssue                    *LinkSubIssueConfig                    `yaml:"link-sub-issue,omitempty"`               // Link issues as sub-issues
 	HideComment                     *HideCommentConfig                     `yaml:"hide-comment,omitempty"`                 // Hide comments
 	DispatchWorkflow                *DispatchWorkflowConfig                `yaml:"dispatch-workflow,omitempty"`            // Dispatch workflow_dispatch events to other workflows
+	SendWebhook                     *SendWebhookConfig                     `yaml:"send-webhook,omitempty"`                 // Send JSON payloads to allow-listed webhook endpoints
 	MissingTool                     *MissingToolConfig                     `yaml:"missing-tool,omitempty"`                 // Optional for reporting missing functionality
 	MissingData                     *MissingDataConfig                     `yaml:"missing-data,omitempty"`                 // Optional for reporting missing data required to achieve goals
 	NoOp                            *NoOpConfig                            `yaml:"noop,omitempty"`                         // No-op output for logging only (always available as fallback)
@@ -525,6 +579,7 @@ type SafeOutputsConfig struct {
 	Footer                          *bool                                  `yaml:"footer,omitempty"`                    // Global footer control - when false, omits visible footer from all safe outputs (XML markers still included)
 	GroupReports                    bool                                   `yaml:"group-reports,omitempty"`             // If true, create parent "Failed runs" issue for agent failures (default: false)
 	MaxBotMentions                  *string                                `yaml:"max-bot-mentions,omitempty"`          // Maximum bot trigger references (e.g. 'fixes #123') allowed before filtering. Default: 10. Supports integer or GitHub Actions expression.
+	Constraints                     ToolConstraints                        `yaml:"constraints,omitempty"`               // Per-tool argument constraints enforced by the safe outputs MCP server
 	AutoInjectedCreateIssue         bool                                   `yaml:"-"`                                   // Internal: true when create-issues was automatically injected by the compiler (not user-configured)
 }
 
@@ -119,6 +119,13 @@ func (c *Compiler) generateWorkflowHeader(yaml *strings.Builder, data *WorkflowD
 	if frontmatterHash != "" {
 		yaml.WriteString("#\n")
 		metadata := GenerateLockMetadata(frontmatterHash, data.StopTime)
+		// Record the commit SHAs of remote imports so the lock file can be reproduced
+		for _, ref := range data.RemoteImports {
+			if metadata.Imports == nil {
+				metadata.Imports = make(map[string]string)
+			}
+			metadata.Imports[ref.Spec()] = ref.SHA
+		}
 		metadataJSON, err := metadata.ToJSON()
 		if err != nil {
 			// Fallback to legacy format if JSON serialization fails
@@ -128,6 +135,12 @@ func (c *Compiler) generateWorkflowHeader(yaml *strings.Builder, data *WorkflowD
 		}
 	}
 
+	// Add compile target comment for GHES targets
+	if data.CompileTarget.IsGHES() {
+		yaml.WriteString("#\n")
+		fmt.Fprintf(yaml, "# Compile target: %s (%s)\n", data.CompileTarget, data.CompileTarget.Host)
+	}
+
 	// Add stop-time comment if configured
 	if data.StopTime != "" {
 		yaml.WriteString("#\n")
@@ -270,6 +283,9 @@ func (c *Compiler) generatePrompt(yaml *strings.Builder, data *WorkflowData, pre
 	var userPromptChunks []string
 	var expressionMappings []*ExpressionMapping
 
+	// When runtime trimming is enabled, each user prompt part is preceded by a section marker
+	trimPrompt := isPromptTrimEnabled(data)
+
 	// Step 1a: Process and inline imported markdown with inputs (if any)
 	// Imports with inputs MUST be inlined because substitution happens at compile time
 	if data.ImportedMarkdown != "" {
@@ -282,6 +298,9 @@ func (c *Compiler) generatePrompt(yaml *strings.Builder, data *WorkflowData, pre
 			cleaned = SubstituteImportInputs(cleaned, data.ImportInputs)
 		}
 		chunks, exprMaps := processMarkdownBody(cleaned)
+		if trimPrompt {
+			userPromptChunks = append(userPromptChunks, promptSectionMarker("imports with inputs", PromptPriorityImport))
+		}
 		userPromptChunks = append(userPromptChunks, chunks...)
 		expressionMappings = exprMaps
 		compilerYamlLog.Printf("Inlined imported markdown with inputs in %d chunks", len(chunks))
@@ -297,6 +316,9 @@ func (c *Compiler) generatePrompt(yaml *strings.Builder, data *WorkflowData, pre
 			workspaceRoot := resolveWorkspaceRoot(c.markdownPath)
 			for _, importPath := range data.ImportPaths {
 				importPath = filepath.ToSlash(importPath)
+				if trimPrompt {
+					userPromptChunks = append(userPromptChunks, promptSectionMarker(importPath, PromptPriorityImport))
+				}
 				rawContent, err := os.ReadFile(filepath.Join(workspaceRoot, importPath))
 				if err != nil {
 					// Fall back to runtime-import macro if file cannot be read
@@ -318,6 +340,9 @@ func (c *Compiler) generatePrompt(yaml *strings.Builder, data *WorkflowData, pre
 			compilerYamlLog.Printf("Generating runtime-import macros for %d imports without inputs", len(data.ImportPaths))
 			for _, importPath := range data.ImportPaths {
 				importPath = filepath.ToSlash(importPath)
+				if trimPrompt {
+					userPromptChunks = append(userPromptChunks, promptSectionMarker(importPath, PromptPriorityImport))
+				}
 				userPromptChunks = append(userPromptChunks, fmt.Sprintf("{{#runtime-import %s}}", importPath))
 				compilerYamlLog.Printf("Added runtime-import macro for: %s", importPath)
 			}
@@ -351,6 +376,9 @@ func (c *Compiler) generatePrompt(yaml *strings.Builder, data *WorkflowData, pre
 	expressionMappings = filterExpressionsForActivation(expressionMappings, data.Jobs, beforeActivationJobs)
 
 	// Step 2: Add main workflow markdown content to the prompt
+	if trimPrompt {
+		userPromptChunks = append(userPromptChunks, promptSectionMarker(filepath.Base(c.markdownPath), PromptPriorityRequired))
+	}
 	if c.inlinePrompt || data.InlinedImports {
 		// Inline mode (Wasm/browser): embed the markdown content directly in the YAML
 		// since runtime-import macros cannot resolve without filesystem access
@@ -445,6 +473,9 @@ func (c *Compiler) generatePrompt(yaml *strings.Builder, data *WorkflowData, pre
 		}
 	}
 
+	// Seal the prompt section markers before any expression is interpolated into the prompt
+	c.generatePromptSealStep(yaml, data)
+
 	// Add combined interpolation and template rendering step
 	// This step processes runtime-import macros, so it must run BEFORE placeholder substitution
 	c.generateInterpolationAndTemplateStep(yaml, expressionMappings, data)
@@ -457,6 +488,9 @@ func (c *Compiler) generatePrompt(yaml *strings.Builder, data *WorkflowData, pre
 		generatePlaceholderSubstitutionStep(yaml, allExpressionMappings, "      ")
 	}
 
+	// Trim lower-priority sections when the complete prompt exceeds the prompt budget
+	c.generatePromptTrimStep(yaml, data)
+
 	// Validate that all placeholders have been substituted
 	yaml.WriteString("      - name: Validate prompt placeholders\n")
 	yaml.WriteString("        env:\n")
@@ -588,6 +622,11 @@ func (c *Compiler) generateCreateAwInfo(yaml *strings.Builder, data *WorkflowDat
 	}
 	fmt.Fprintf(yaml, "              staged: %s,\n", stagedValue)
 
+	// Matrix variant run by this job, used by logs --compare-matrix
+	if variantID := matrixVariantID(data); variantID != "" {
+		fmt.Fprintf(yaml, "              matrix_variant: \"%s\",\n", variantID)
+	}
+
 	// Network configuration
 	var allowedDomains []string
 	firewallEnabled := false
@@ -669,7 +708,7 @@ func (c *Compiler) generateOutputCollectionStep(yaml *strings.Builder, data *Wor
 	yaml.WriteString("        if: always()\n")
 	fmt.Fprintf(yaml, "        uses: %s\n", GetActionPin("actions/upload-artifact"))
 	yaml.WriteString("        with:\n")
-	fmt.Fprintf(yaml, "          name: %s\n", constants.SafeOutputArtifactName)
+	fmt.Fprintf(yaml, "          name: %s\n", jobArtifactName(data, constants.SafeOutputArtifactName))
 	yaml.WriteString("          path: ${{ env.GH_AW_SAFE_OUTPUTS }}\n")
 	yaml.WriteString("          if-no-files-found: warn\n")
 
@@ -731,7 +770,7 @@ func (c *Compiler) generateOutputCollectionStep(yaml *strings.Builder, data *Wor
 	yaml.WriteString("        if: always() && env.GH_AW_AGENT_OUTPUT\n")
 	fmt.Fprintf(yaml, "        uses: %s\n", GetActionPin("actions/upload-artifact"))
 	yaml.WriteString("        with:\n")
-	fmt.Fprintf(yaml, "          name: %s\n", constants.AgentOutputArtifactName)
+	fmt.Fprintf(yaml, "          name: %s\n", jobArtifactName(data, constants.AgentOutputArtifactName))
 	yaml.WriteString("          path: ${{ env.GH_AW_AGENT_OUTPUT }}\n")
 	yaml.WriteString("          if-no-files-found: warn\n")
 
@@ -21,10 +21,24 @@ func (c *Compiler) generateUploadAccessLogs(yaml *strings.Builder, tools map[str
 	// No proxy tools anymore - network filtering is handled at workflow level
 }
 
+// jobArtifactName returns the name of an artifact uploaded by an agent job. Agent stage and
+// matrix variant jobs run next to the agent job, so their artifacts get the stage or
+// variant id as suffix to keep artifact names unique within the run.
+func jobArtifactName(data *WorkflowData, name string) string {
+	if data.CurrentStage != nil {
+		return name + "-" + data.CurrentStage.ID
+	}
+	if data.MatrixVariant != nil {
+		return name + "-" + data.MatrixVariant.ID
+	}
+	return name
+}
+
 // generateUnifiedArtifactUpload generates a single step that uploads all agent job artifacts
 // This consolidates multiple individual upload steps into one, improving workflow readability
 // and reliability. The step always runs (even on cancellation) and ignores missing files.
-func (c *Compiler) generateUnifiedArtifactUpload(yaml *strings.Builder, paths []string) {
+// Agent stage jobs use their own artifact name so the artifacts of each job are kept apart.
+func (c *Compiler) generateUnifiedArtifactUpload(yaml *strings.Builder, name string, paths []string) {
 	if len(paths) == 0 {
 		compilerYamlArtifactsLog.Print("No paths to upload, skipping unified artifact upload")
 		return
@@ -37,7 +51,7 @@ func (c *Compiler) generateUnifiedArtifactUpload(yaml *strings.Builder, paths []
 	yaml.WriteString("        continue-on-error: true\n")
 	fmt.Fprintf(yaml, "        uses: %s\n", GetActionPin("actions/upload-artifact"))
 	yaml.WriteString("        with:\n")
-	yaml.WriteString("          name: agent-artifacts\n")
+	yaml.WriteString("          name: " + name + "\n")
 
 	// Write paths as multi-line YAML string
 	yaml.WriteString("          path: |\n")
@@ -235,13 +235,24 @@ func (c *Compiler) generateMainJobSteps(yaml *strings.Builder, data *WorkflowDat
 	// This reads from aw_info.json for consistent data
 	c.generateWorkflowOverviewStep(yaml, data, engine)
 
-	// Download prompt artifact from activation job
-	compilerYamlLog.Print("Adding prompt artifact download step")
-	yaml.WriteString("      - name: Download prompt artifact\n")
-	fmt.Fprintf(yaml, "        uses: %s\n", GetActionPin("actions/download-artifact"))
-	yaml.WriteString("        with:\n")
-	yaml.WriteString("          name: prompt\n")
-	yaml.WriteString("          path: /tmp/gh-aw/aw-prompts\n")
+	if data.CurrentStage != nil {
+		// Agent stages write their own prompt
+		compilerYamlLog.Printf("Adding prompt step for agent stage %s", data.CurrentStage.ID)
+		if err := c.generateStagePromptStep(yaml, data); err != nil {
+			return err
+		}
+	} else {
+		// Download prompt artifact from activation job
+		compilerYamlLog.Print("Adding prompt artifact download step")
+		yaml.WriteString("      - name: Download prompt artifact\n")
+		fmt.Fprintf(yaml, "        uses: %s\n", GetActionPin("actions/download-artifact"))
+		yaml.WriteString("        with:\n")
+		yaml.WriteString("          name: prompt\n")
+		yaml.WriteString("          path: /tmp/gh-aw/aw-prompts\n")
+	}
+
+	// Download the hand-off files of earlier agent stages
+	generateHandoffDownloadStep(yaml, data)
 
 	// Collect artifact paths for unified upload at the end
 	var artifactPaths []string
@@ -317,6 +328,13 @@ func (c *Compiler) generateMainJobSteps(yaml *strings.Builder, data *WorkflowDat
 	// This ensures all artifacts are scanned for secrets before being uploaded
 	c.generateSecretRedactionStep(yaml, yaml.String(), data)
 
+	// Validate and upload the hand-off file of an agent stage
+	if data.CurrentStage != nil {
+		if err := generateHandoffSteps(yaml, data.CurrentStage); err != nil {
+			return err
+		}
+	}
+
 	// Add output collection step only if safe-outputs feature is used (GH_AW_SAFE_OUTPUTS functionality)
 	if data.SafeOutputs != nil {
 		c.generateOutputCollectionStep(yaml, data)
@@ -324,7 +342,7 @@ func (c *Compiler) generateMainJobSteps(yaml *strings.Builder, data *WorkflowDat
 
 	// Add engine-declared output files collection (if any)
 	if len(engine.GetDeclaredOutputFiles()) > 0 {
-		c.generateEngineOutputCollection(yaml, engine)
+		c.generateEngineOutputCollection(yaml, data, engine)
 	}
 
 	// Extract and upload squid access logs (if any proxy tools were used)
@@ -427,7 +445,7 @@ func (c *Compiler) generateMainJobSteps(yaml *strings.Builder, data *WorkflowDat
 	c.generatePostSteps(yaml, data)
 
 	// Generate single unified artifact upload with all collected paths
-	c.generateUnifiedArtifactUpload(yaml, artifactPaths)
+	c.generateUnifiedArtifactUpload(yaml, jobArtifactName(data, "agent-artifacts"), artifactPaths)
 
 	// Add GitHub MCP app token invalidation step if configured (runs always, even on failure)
 	c.generateGitHubMCPAppTokenInvalidationStep(yaml, data)
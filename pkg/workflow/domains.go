@@ -142,6 +142,20 @@ func getEcosystemDomains(category string) []string {
 	return result
 }
 
+// resolveEcosystemDomains returns the domains of an ecosystem identifier, looking up the custom
+// ecosystems defined in network.ecosystems before the built-in ones
+func resolveEcosystemDomains(network *NetworkPermissions, identifier string) []string {
+	if network != nil {
+		if domains, exists := network.Ecosystems[identifier]; exists {
+			result := make([]string, len(domains))
+			copy(result, domains)
+			sort.Strings(result)
+			return result
+		}
+	}
+	return getEcosystemDomains(identifier)
+}
+
 // runtimeToEcosystem maps runtime IDs to their corresponding ecosystem categories in ecosystem_domains.json
 // Some runtimes share ecosystems (e.g., bun and deno use node ecosystem domains)
 var runtimeToEcosystem = map[string]string{
@@ -277,7 +291,7 @@ func GetAllowedDomains(network *NetworkPermissions) []string {
 	domainMap := make(map[string]bool)
 	for _, domain := range network.Allowed {
 		// Try to get domains for this ecosystem category
-		ecosystemDomains := getEcosystemDomains(domain)
+		ecosystemDomains := resolveEcosystemDomains(network, domain)
 		if len(ecosystemDomains) > 0 {
 			// This was an ecosystem identifier, expand it
 			domainsLog.Printf("Expanded ecosystem '%s' to %d domains", domain, len(ecosystemDomains))
@@ -635,7 +649,7 @@ func GetBlockedDomains(network *NetworkPermissions) []string {
 	domainMap := make(map[string]bool)
 	for _, domain := range network.Blocked {
 		// Try to get domains for this ecosystem category
-		ecosystemDomains := getEcosystemDomains(domain)
+		ecosystemDomains := resolveEcosystemDomains(network, domain)
 		if len(ecosystemDomains) > 0 {
 			// This was an ecosystem identifier, expand it
 			domainsLog.Printf("Expanded ecosystem '%s' to %d domains", domain, len(ecosystemDomains))
@@ -686,18 +700,21 @@ func (c *Compiler) computeAllowedDomainsForSanitization(data *WorkflowData) stri
 
 	// Compute domains based on engine type, including tools and runtimes to match
 	// what's provided to the actual firewall at runtime
+	var domains string
 	switch engineID {
 	case "copilot":
-		return GetCopilotAllowedDomainsWithToolsAndRuntimes(data.NetworkPermissions, data.Tools, data.Runtimes)
+		domains = GetCopilotAllowedDomainsWithToolsAndRuntimes(data.NetworkPermissions, data.Tools, data.Runtimes)
 	case "codex":
-		return GetCodexAllowedDomainsWithToolsAndRuntimes(data.NetworkPermissions, data.Tools, data.Runtimes)
+		domains = GetCodexAllowedDomainsWithToolsAndRuntimes(data.NetworkPermissions, data.Tools, data.Runtimes)
 	case "claude":
-		return GetClaudeAllowedDomainsWithToolsAndRuntimes(data.NetworkPermissions, data.Tools, data.Runtimes)
+		domains = GetClaudeAllowedDomainsWithToolsAndRuntimes(data.NetworkPermissions, data.Tools, data.Runtimes)
 	case "gemini":
-		return GetGeminiAllowedDomainsWithToolsAndRuntimes(data.NetworkPermissions, data.Tools, data.Runtimes)
+		domains = GetGeminiAllowedDomainsWithToolsAndRuntimes(data.NetworkPermissions, data.Tools, data.Runtimes)
 	default:
 		// For other engines, use network permissions only
-		domains := GetAllowedDomains(data.NetworkPermissions)
-		return strings.Join(domains, ",")
+		domains = strings.Join(GetAllowedDomains(data.NetworkPermissions), ",")
 	}
+
+	// The firewall rewrites GitHub domains for GHES targets, so sanitization must match
+	return rewriteDomainsForTarget(domains, data.CompileTarget)
 }
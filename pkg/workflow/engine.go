@@ -58,10 +58,11 @@ type EngineConfig struct {
 // Ecosystem identifiers in the Allowed list are expanded to their corresponding domain lists.
 // See GetAllowedDomains() for the list of supported ecosystem identifiers.
 type NetworkPermissions struct {
-	Allowed           []string        `yaml:"allowed,omitempty"`  // List of allowed domains or ecosystem identifiers (e.g., "defaults", "github", "python")
-	Blocked           []string        `yaml:"blocked,omitempty"`  // List of blocked domains (takes precedence over allowed)
-	Firewall          *FirewallConfig `yaml:"firewall,omitempty"` // AWF firewall configuration (see firewall.go)
-	ExplicitlyDefined bool            `yaml:"-"`                  // Internal flag: true if network field was explicitly set in frontmatter
+	Allowed           []string            `yaml:"allowed,omitempty"`    // List of allowed domains or ecosystem identifiers (e.g., "defaults", "github", "python")
+	Blocked           []string            `yaml:"blocked,omitempty"`    // List of blocked domains (takes precedence over allowed)
+	Ecosystems        map[string][]string `yaml:"ecosystems,omitempty"` // Custom ecosystem identifiers and their domains, usable in allowed and blocked
+	Firewall          *FirewallConfig     `yaml:"firewall,omitempty"`   // AWF firewall configuration (see firewall.go)
+	ExplicitlyDefined bool                `yaml:"-"`                    // Internal flag: true if network field was explicitly set in frontmatter
 }
 
 // EngineNetworkConfig combines engine configuration with top-level network permissions
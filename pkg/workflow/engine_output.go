@@ -45,7 +45,7 @@ func generateCleanupStep(outputFiles []string) (string, bool) {
 }
 
 // generateEngineOutputCollection generates a step that collects engine-declared output files as artifacts
-func (c *Compiler) generateEngineOutputCollection(yaml *strings.Builder, engine CodingAgentEngine) {
+func (c *Compiler) generateEngineOutputCollection(yaml *strings.Builder, data *WorkflowData, engine CodingAgentEngine) {
 	outputFiles := engine.GetDeclaredOutputFiles()
 	if len(outputFiles) == 0 {
 		engineOutputLog.Print("No engine output files to collect")
@@ -69,7 +69,7 @@ func (c *Compiler) generateEngineOutputCollection(yaml *strings.Builder, engine
 	yaml.WriteString("      - name: Upload engine output files\n")
 	fmt.Fprintf(yaml, "        uses: %s\n", GetActionPin("actions/upload-artifact"))
 	yaml.WriteString("        with:\n")
-	yaml.WriteString("          name: agent_outputs\n")
+	yaml.WriteString("          name: " + jobArtifactName(data, "agent_outputs") + "\n")
 
 	// Create the path list for all declared output files
 	yaml.WriteString("          path: |\n")
@@ -0,0 +1,232 @@
+package workflow
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/github/gh-aw/pkg/logger"
+	"github.com/github/gh-aw/pkg/parser"
+	"github.com/goccy/go-yaml"
+)
+
+var explainLog = logger.New("workflow:explain")
+
+// Origins of the settings of a workflow's effective configuration
+const (
+	ExplainOriginFrontmatter  = "frontmatter"   // Set in the workflow's own frontmatter
+	ExplainOriginImport       = "import"        // Contributed by an imported file
+	ExplainOriginDefault      = "default"       // Filled in by a compiler default
+	ExplainOriginAutoInjected = "auto-injected" // Added by the compiler because the configuration needs it
+	ExplainOriginCodemod      = "codemod"       // Rewritten from a deprecated form
+)
+
+// WorkflowExplanation is the effective configuration of a workflow annotated with provenance
+type WorkflowExplanation struct {
+	Workflow string             `json:"workflow"`
+	Settings []EffectiveSetting `json:"settings"`
+}
+
+// EffectiveSetting is a single setting of the effective configuration and where it came from.
+// Lists are reported as one setting per entry.
+type EffectiveSetting struct {
+	Section string `json:"section" console:"-"`
+	Setting string `json:"setting" console:"header:Setting"`
+	Value   string `json:"value,omitempty" console:"header:Value,maxlen:50"`
+	Origin  string `json:"origin" console:"header:Origin"`
+	Source  string `json:"-" console:"header:Source"`
+	File    string `json:"file,omitempty" console:"-"`   // File that introduced the setting (frontmatter and import origins)
+	Line    int    `json:"line,omitempty" console:"-"`   // Line of the setting in File (0 when it cannot be located)
+	Detail  string `json:"detail,omitempty" console:"-"` // Compiler step that produced the setting (default, auto-injected and codemod origins)
+}
+
+// ExplainWorkflow compiles a workflow without writing the lock file and returns its effective
+// permissions, network, sandbox, tools, MCP servers, safe outputs, runtimes and features, with the
+// file and line that introduced each value or the compiler step that produced it.
+func (c *Compiler) ExplainWorkflow(markdownPath string) (*WorkflowExplanation, error) {
+	explainLog.Printf("Explaining workflow: %s", markdownPath)
+	workflowData, err := c.ParseWorkflowFile(markdownPath)
+	if err != nil {
+		return nil, err
+	}
+	return explainWorkflowData(workflowData, markdownPath), nil
+}
+
+// settingExplainer attributes effective settings to the files and compiler steps that produced them
+type settingExplainer struct {
+	provenance *MergeProvenance
+	contents   map[string]string // File contents by display path, for locating settings
+	settings   []EffectiveSetting
+}
+
+// explainWorkflowData builds the explanation of a parsed workflow
+func explainWorkflowData(workflowData *WorkflowData, markdownPath string) *WorkflowExplanation {
+	provenance := workflowData.MergeProvenance
+	if provenance == nil {
+		workflow := provenanceDisplayPath(markdownPath)
+		provenance = &MergeProvenance{Workflow: workflow, paths: map[string]string{workflow: markdownPath}}
+	}
+	e := &settingExplainer{provenance: provenance, contents: make(map[string]string)}
+	// The sandbox is not merged from imports, so it is only attributed to the workflow itself
+	if sandbox, exists := workflowData.RawFrontmatter["sandbox"]; exists {
+		provenance.own("sandbox", sandbox, provenance.Workflow)
+	}
+
+	var permissions map[string]any
+	if err := yaml.Unmarshal([]byte(workflowData.Permissions), &permissions); err != nil {
+		explainLog.Printf("Failed to parse permissions: %v", err)
+	}
+	e.explain("permissions", "permissions", permissions["permissions"], func(string, string) (string, string) {
+		return ExplainOriginDefault, "applyDefaults: contents: read when no permissions are set"
+	})
+
+	e.explain("network", "network", normalizeSetting(workflowData.NetworkPermissions), func(setting, _ string) (string, string) {
+		if strings.HasPrefix(setting, "network.firewall") {
+			return ExplainOriginDefault, "enableFirewallByDefaultForEngine: firewall enabled when network access is restricted"
+		}
+		return ExplainOriginDefault, "setupEngineAndImports: 'defaults' ecosystem when no network is set"
+	})
+
+	rawSandbox := formatProvenanceValue(workflowData.RawFrontmatter["sandbox"])
+	e.explain("sandbox", "sandbox", normalizeSetting(workflowData.SandboxConfig), func(_, value string) (string, string) {
+		if value == string(SandboxTypeAWF) && (strings.Contains(rawSandbox, "srt") || strings.Contains(rawSandbox, "sandbox-runtime")) {
+			return ExplainOriginCodemod, "migrateSRTToAWF: SRT sandbox migrated to AWF"
+		}
+		return ExplainOriginDefault, "applySandboxDefaults: AWF agent sandbox when none is set"
+	})
+
+	tools, _ := normalizeSetting(workflowData.Tools).(map[string]any)
+	for _, section := range []string{"tools", "mcp-servers"} {
+		for name, value := range tools {
+			if e.isMCPServer(name) != (section == "mcp-servers") {
+				continue
+			}
+			e.explain(section, section+"."+name, value, func(string, string) (string, string) {
+				return ExplainOriginDefault, "applyDefaultTools: GitHub tools and tools required by the sandbox or safe outputs"
+			})
+		}
+	}
+
+	e.explain("safe-outputs", "safe-outputs", normalizeSetting(workflowData.SafeOutputs), func(setting, _ string) (string, string) {
+		if workflowData.SafeOutputs.AutoInjectedCreateIssue && strings.HasPrefix(setting, "safe-outputs.create-issue") {
+			return ExplainOriginAutoInjected, "applyDefaultCreateIssue: no other safe output is configured"
+		}
+		return ExplainOriginDefault, "extractSafeOutputsConfig: safe output defaults"
+	})
+
+	compilerDefault := func(string, string) (string, string) { return ExplainOriginDefault, "compiler default" }
+	e.explain("runtimes", "runtimes", normalizeSetting(workflowData.Runtimes), compilerDefault)
+	e.explain("features", "features", normalizeSetting(workflowData.Features), compilerDefault)
+
+	explainLog.Printf("Explained %d settings", len(e.settings))
+	return &WorkflowExplanation{Workflow: provenance.Workflow, Settings: e.settings}
+}
+
+// explain records the leaf settings of value under path. fallback returns the origin and detail of
+// settings that were not introduced by the workflow or its imports.
+func (e *settingExplainer) explain(section, path string, value any, fallback func(setting, value string) (string, string)) {
+	if settings, isMap := value.(map[string]any); value == nil || (isMap && len(settings) == 0 && path == section && section != "permissions") {
+		return
+	}
+	walkSettings(path, value, func(setting, formatted string) {
+		effective := EffectiveSetting{Section: section, Setting: setting, Value: formatted}
+		owner, ownedSetting, exact := e.attribute(setting, formatted)
+		if owner != nil && !exact {
+			// A value rewritten from a deprecated form is reported as a codemod
+			if origin, _ := fallback(setting, formatted); origin == ExplainOriginCodemod {
+				owner = nil
+			}
+		}
+		if owner != nil {
+			effective.File = owner.file
+			effective.Origin = ExplainOriginImport
+			if effective.File == e.provenance.Workflow {
+				effective.Origin = ExplainOriginFrontmatter
+			}
+			effective.Line = e.locate(effective.File, ownedSetting, owner.value)
+			effective.Source = effective.File
+			if effective.Line > 0 {
+				effective.Source = fmt.Sprintf("%s:%d", effective.File, effective.Line)
+			}
+		} else {
+			effective.Origin, effective.Detail = fallback(setting, formatted)
+			effective.Source = effective.Detail
+			if name, _, ok := strings.Cut(effective.Detail, ":"); ok {
+				effective.Source = name
+			}
+		}
+		e.settings = append(e.settings, effective)
+	})
+}
+
+// attribute returns the file that set an effective setting while the workflow and its imports were
+// merged, and the setting it was set under. Settings are matched by path, because the compiler may
+// rewrite a value after merging (e.g., "bash: true" becomes "*" and "expires: 2d" becomes 48), but
+// list entries are matched by value, because the compiler adds default entries to lists. A setting
+// enabled without configuration (e.g., "github:") or in a shorthand form (e.g., "firewall: true")
+// introduces the settings below it. An empty object has no settings below it, so the settings the
+// compiler adds to an empty or missing section (e.g., the default tools when there is no "tools:")
+// are not attributed to the file. exact reports whether the file set the value itself.
+func (e *settingExplainer) attribute(setting, value string) (owner *settingOwner, ownedSetting string, exact bool) {
+	if owner := e.provenance.latestOwner(setting, func(owner settingOwner) bool { return owner.value == value }); owner != nil {
+		return owner, setting, true
+	}
+	for path := setting; path != ""; path = parentSetting(path) {
+		owner := e.provenance.latestOwner(path, func(owner settingOwner) bool { return !owner.listItem })
+		if owner == nil || (path != setting && owner.value == "{}") {
+			continue
+		}
+		return owner, path, false
+	}
+	return nil, "", false
+}
+
+// isMCPServer reports whether a tool was configured under mcp-servers by the workflow or an import
+func (e *settingExplainer) isMCPServer(name string) bool {
+	prefix := "mcp-servers." + name
+	for _, entry := range e.provenance.Entries {
+		if entry.Setting == prefix || strings.HasPrefix(entry.Setting, prefix+".") {
+			return true
+		}
+	}
+	return false
+}
+
+// locate returns the line of a setting in the frontmatter of a source file, or 0 if it cannot be found
+func (e *settingExplainer) locate(file, setting, value string) int {
+	content, cached := e.contents[file]
+	if !cached {
+		path := e.provenance.paths[file]
+		if path == "" {
+			path = filepath.FromSlash(file)
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			explainLog.Printf("Failed to read %s: %v", path, err)
+		}
+		content = string(data)
+		e.contents[file] = content
+	}
+	location := parser.LocateFrontmatterSetting(content, strings.Split(setting, "."), value)
+	if !location.Found {
+		return 0
+	}
+	return location.Line
+}
+
+// normalizeSetting converts a configuration value to the generic form of parsed frontmatter, so that
+// it can be compared with the settings of the workflow and its imports
+func normalizeSetting(value any) any {
+	data, err := yaml.Marshal(value)
+	if err != nil {
+		explainLog.Printf("Failed to marshal setting: %v", err)
+		return nil
+	}
+	var normalized any
+	if err := yaml.Unmarshal(data, &normalized); err != nil {
+		explainLog.Printf("Failed to unmarshal setting: %v", err)
+		return nil
+	}
+	return normalized
+}
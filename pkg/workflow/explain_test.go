@@ -0,0 +1,238 @@
+//go:build !integration
+
+package workflow
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExplainWorkflow(t *testing.T) {
+	workflowsDir := filepath.Join(t.TempDir(), ".github", "workflows")
+	require.NoError(t, os.MkdirAll(filepath.Join(workflowsDir, "shared"), 0755))
+
+	shared := `---
+mcp-servers:
+  slack:
+    container: mcp/slack
+    allowed: [post_message]
+runtimes:
+  node:
+    version: "20"
+features:
+  fast-mode: true
+network:
+  allowed:
+    - slack.com
+---
+Shared instructions.
+`
+	main := `---
+on: issues
+engine: copilot
+permissions:
+  contents: read
+imports:
+  - shared/slack.md
+tools:
+  github:
+    toolsets: [issues]
+runtimes:
+  node:
+    version: "22"
+features:
+  fast-mode: false
+safe-outputs:
+  noop:
+---
+# Triage
+`
+	require.NoError(t, os.WriteFile(filepath.Join(workflowsDir, "shared", "slack.md"), []byte(shared), 0644))
+	mainFile := filepath.Join(workflowsDir, "triage.md")
+	require.NoError(t, os.WriteFile(mainFile, []byte(main), 0644))
+
+	explanation, err := NewCompiler().ExplainWorkflow(mainFile)
+	require.NoError(t, err, "workflow should be explained")
+	assert.Equal(t, ".github/workflows/triage.md", explanation.Workflow, "workflow should be shown relative to the repository")
+
+	settings := make(map[string]EffectiveSetting)
+	for _, setting := range explanation.Settings {
+		settings[setting.Setting+"="+setting.Value] = setting
+	}
+
+	const mainPath, sharedPath = ".github/workflows/triage.md", ".github/workflows/shared/slack.md"
+	tests := []struct {
+		key     string
+		section string
+		origin  string
+		file    string
+		line    int
+		detail  string
+	}{
+		{key: "permissions.contents=read", section: "permissions", origin: ExplainOriginFrontmatter, file: mainPath, line: 5},
+		{key: "tools.github.toolsets=issues", section: "tools", origin: ExplainOriginFrontmatter, file: mainPath, line: 10},
+		{key: "mcp-servers.slack.allowed=post_message", section: "mcp-servers", origin: ExplainOriginImport, file: sharedPath, line: 5},
+		{key: "mcp-servers.slack.container=mcp/slack", section: "mcp-servers", origin: ExplainOriginImport, file: sharedPath, line: 4},
+		{key: "network.allowed=slack.com", section: "network", origin: ExplainOriginImport, file: sharedPath, line: 13},
+		{key: "network.firewall.enabled=true", section: "network", origin: ExplainOriginDefault, detail: "enableFirewallByDefaultForEngine"},
+		{key: "sandbox.agent.type=awf", section: "sandbox", origin: ExplainOriginDefault, detail: "applySandboxDefaults"},
+		{key: "runtimes.node.version=20", section: "runtimes", origin: ExplainOriginImport, file: sharedPath, line: 8},
+		{key: "features.fast-mode=false", section: "features", origin: ExplainOriginFrontmatter, file: mainPath, line: 15},
+		{key: "safe-outputs.create-issue.max=1", section: "safe-outputs", origin: ExplainOriginAutoInjected, detail: "applyDefaultCreateIssue"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			setting, found := settings[tt.key]
+			require.True(t, found, "setting should be explained")
+			assert.Equal(t, tt.section, setting.Section, "section")
+			assert.Equal(t, tt.origin, setting.Origin, "origin")
+			assert.Equal(t, tt.file, setting.File, "file")
+			assert.Equal(t, tt.line, setting.Line, "line")
+			if tt.detail != "" {
+				assert.Contains(t, setting.Detail, tt.detail, "detail should name the compiler step")
+			}
+		})
+	}
+
+	assert.NotContains(t, settings, "runtimes.node.version=22", "overridden runtimes should not be listed")
+	assert.NotContains(t, settings, "features.fast-mode=true", "overridden features should not be listed")
+}
+
+func TestExplainWorkflowDataCodemod(t *testing.T) {
+	workflowData := &WorkflowData{
+		RawFrontmatter: map[string]any{"sandbox": map[string]any{"type": "sandbox-runtime"}},
+		SandboxConfig:  applySandboxDefaults(&SandboxConfig{Type: "sandbox-runtime"}, nil),
+	}
+
+	explanation := explainWorkflowData(workflowData, "/repo/.github/workflows/legacy.md")
+	require.Len(t, explanation.Settings, 1, "only the sandbox should be explained")
+	setting := explanation.Settings[0]
+	assert.Equal(t, "sandbox.type", setting.Setting, "setting")
+	assert.Equal(t, "awf", setting.Value, "migrated value")
+	assert.Equal(t, ExplainOriginCodemod, setting.Origin, "migrated settings should be reported as codemods")
+	assert.Contains(t, setting.Detail, "migrateSRTToAWF", "detail should name the codemod")
+}
+
+func TestExplainWorkflowRewrittenValues(t *testing.T) {
+	workflowsDir := filepath.Join(t.TempDir(), ".github", "workflows")
+	require.NoError(t, os.MkdirAll(filepath.Join(workflowsDir, "shared"), 0755))
+
+	shared := `---
+tools:
+  bash:
+    - "ffmpeg *"
+---
+Shared instructions.
+`
+	main := `---
+on: issues
+engine: copilot
+imports:
+  - shared/ffmpeg.md
+tools:
+  bash: true
+  edit:
+safe-outputs:
+  create-issue:
+    expires: 2d
+---
+# Video
+`
+	require.NoError(t, os.WriteFile(filepath.Join(workflowsDir, "shared", "ffmpeg.md"), []byte(shared), 0644))
+	mainFile := filepath.Join(workflowsDir, "video.md")
+	require.NoError(t, os.WriteFile(mainFile, []byte(main), 0644))
+
+	explanation, err := NewCompiler().ExplainWorkflow(mainFile)
+	require.NoError(t, err, "workflow should be explained")
+
+	settings := make(map[string]EffectiveSetting)
+	for _, setting := range explanation.Settings {
+		settings[setting.Setting+"="+setting.Value] = setting
+	}
+
+	const mainPath, sharedPath = ".github/workflows/video.md", ".github/workflows/shared/ffmpeg.md"
+	tests := []struct {
+		key    string
+		origin string
+		file   string
+		line   int
+	}{
+		{key: "safe-outputs.create-issue.expires=48", origin: ExplainOriginFrontmatter, file: mainPath, line: 11},
+		{key: "tools.bash=ffmpeg *", origin: ExplainOriginImport, file: sharedPath, line: 4},
+		{key: "tools.bash=echo", origin: ExplainOriginDefault},
+	}
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			setting, found := settings[tt.key]
+			require.True(t, found, "setting should be explained")
+			assert.Equal(t, tt.origin, setting.Origin, "origin")
+			assert.Equal(t, tt.file, setting.File, "file")
+			assert.Equal(t, tt.line, setting.Line, "line")
+		})
+	}
+}
+
+func TestExplainWorkflowShorthandTool(t *testing.T) {
+	workflowsDir := filepath.Join(t.TempDir(), ".github", "workflows")
+	require.NoError(t, os.MkdirAll(workflowsDir, 0755))
+
+	main := `---
+on: issues
+engine: copilot
+tools:
+  bash: true
+---
+# Shell
+`
+	mainFile := filepath.Join(workflowsDir, "shell.md")
+	require.NoError(t, os.WriteFile(mainFile, []byte(main), 0644))
+
+	explanation, err := NewCompiler().ExplainWorkflow(mainFile)
+	require.NoError(t, err, "workflow should be explained")
+
+	for _, setting := range explanation.Settings {
+		if setting.Setting == "tools.bash" && setting.Value == "*" {
+			assert.Equal(t, ExplainOriginFrontmatter, setting.Origin, "shorthand tools should be attributed to the frontmatter")
+			assert.Equal(t, 5, setting.Line, "line")
+			return
+		}
+	}
+	t.Fatal("tools.bash=* should be explained")
+}
+
+func TestExplainWorkflowDefaultTools(t *testing.T) {
+	workflowsDir := filepath.Join(t.TempDir(), ".github", "workflows")
+	require.NoError(t, os.MkdirAll(workflowsDir, 0755))
+
+	main := `---
+on: issues
+engine: claude
+permissions:
+  contents: read
+---
+# No tools
+`
+	mainFile := filepath.Join(workflowsDir, "plain.md")
+	require.NoError(t, os.WriteFile(mainFile, []byte(main), 0644))
+
+	explanation, err := NewCompiler().ExplainWorkflow(mainFile)
+	require.NoError(t, err, "workflow should be explained")
+
+	explained := make(map[string]bool)
+	for _, setting := range explanation.Settings {
+		if setting.Section != "tools" {
+			continue
+		}
+		explained[setting.Setting+"="+setting.Value] = true
+		assert.Equal(t, ExplainOriginDefault, setting.Origin, "%s=%s should be a default without a tools section", setting.Setting, setting.Value)
+		assert.Empty(t, setting.File, "%s=%s should not be attributed to a file", setting.Setting, setting.Value)
+		assert.Contains(t, setting.Detail, "applyDefaultTools", "detail should name the compiler step")
+	}
+	for _, key := range []string{"tools.bash=*", "tools.bash=echo", "tools.edit=true", "tools.github={}"} {
+		assert.True(t, explained[key], "%s should be explained", key)
+	}
+}
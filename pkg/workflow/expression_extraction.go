@@ -9,6 +9,7 @@ import (
 	"strings"
 
 	"github.com/github/gh-aw/pkg/logger"
+	"github.com/github/gh-aw/pkg/parser"
 )
 
 var expressionExtractionLog = logger.New("workflow:expression_extraction")
@@ -250,8 +251,9 @@ func (e *ExpressionExtractor) GetMappings() []*ExpressionMapping {
 	return result
 }
 
-// awInputsExprRegex matches ${{ github.aw.inputs.<key> }} expressions
-var awInputsExprRegex = regexp.MustCompile(`\$\{\{\s*github\.aw\.inputs\.([a-zA-Z0-9_-]+)\s*\}\}`)
+// awInputsExprRegex matches ${{ github.aw.inputs.<key> }} expressions, including
+// ${{ github.aw.inputs.<key>.<property> }} for object inputs
+var awInputsExprRegex = regexp.MustCompile(`\$\{\{\s*github\.aw\.inputs\.([a-zA-Z0-9_-]+(?:\.[a-zA-Z0-9_-]+)*)\s*\}\}`)
 
 // SubstituteImportInputs replaces ${{ github.aw.inputs.<key> }} expressions
 // with the corresponding values from the importInputs map.
@@ -271,9 +273,9 @@ func SubstituteImportInputs(content string, importInputs map[string]any) string
 		}
 
 		key := matches[1]
-		if value, exists := importInputs[key]; exists {
-			// Convert value to string
-			strValue := fmt.Sprintf("%v", value)
+		if value, exists := parser.LookupImportInput(importInputs, key); exists {
+			// Convert value to string (arrays and objects are rendered as JSON)
+			strValue := parser.FormatImportInputValue(value)
 			expressionExtractionLog.Printf("Substituting github.aw.inputs.%s with value: %s", key, strValue)
 			return strValue
 		}
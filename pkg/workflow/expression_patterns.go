@@ -93,13 +93,13 @@ var (
 	// Example: inputs.branch_name
 	WorkflowCallInputsPattern = regexp.MustCompile(`^inputs\.[a-zA-Z0-9_-]+$`)
 
-	// AWInputsPattern matches github.aw.inputs.* patterns
-	// Example: github.aw.inputs.custom_param
-	AWInputsPattern = regexp.MustCompile(`^github\.aw\.inputs\.[a-zA-Z0-9_-]+$`)
+	// AWInputsPattern matches github.aw.inputs.* patterns, including object properties
+	// Example: github.aw.inputs.custom_param, github.aw.inputs.config.branch
+	AWInputsPattern = regexp.MustCompile(`^github\.aw\.inputs\.[a-zA-Z0-9_-]+(\.[a-zA-Z0-9_-]+)*$`)
 
 	// AWInputsExpressionPattern matches full ${{ github.aw.inputs.* }} expressions
 	// Used for extraction rather than validation
-	AWInputsExpressionPattern = regexp.MustCompile(`\$\{\{\s*github\.aw\.inputs\.([a-zA-Z0-9_-]+)\s*\}\}`)
+	AWInputsExpressionPattern = regexp.MustCompile(`\$\{\{\s*github\.aw\.inputs\.([a-zA-Z0-9_-]+(?:\.[a-zA-Z0-9_-]+)*)\s*\}\}`)
 
 	// EnvPattern matches env.* patterns
 	// Example: env.NODE_VERSION
@@ -54,6 +54,25 @@ func (c *Compiler) extractNetworkPermissions(frontmatter map[string]any) *Networ
 				}
 			}
 
+			// Extract custom ecosystems if present
+			if ecosystems, hasEcosystems := networkObj["ecosystems"]; hasEcosystems {
+				if ecosystemsMap, ok := ecosystems.(map[string]any); ok {
+					permissions.Ecosystems = make(map[string][]string, len(ecosystemsMap))
+					for name, domains := range ecosystemsMap {
+						domainsSlice, ok := domains.([]any)
+						if !ok {
+							continue
+						}
+						for _, domain := range domainsSlice {
+							if domainStr, ok := domain.(string); ok {
+								permissions.Ecosystems[name] = append(permissions.Ecosystems[name], domainStr)
+							}
+						}
+					}
+					frontmatterExtractionSecurityLog.Printf("Extracted %d custom ecosystems", len(permissions.Ecosystems))
+				}
+			}
+
 			// Extract firewall configuration if present
 			if firewall, hasFirewall := networkObj["firewall"]; hasFirewall {
 				frontmatterExtractionSecurityLog.Print("Extracting firewall configuration")
@@ -438,6 +457,20 @@ func (c *Compiler) extractMCPGatewayConfig(mcpVal any) *MCPGatewayRuntimeConfig
 		}
 	}
 
+	// Extract record (record tool calls to a cassette)
+	if recordVal, hasRecord := mcpObj["record"]; hasRecord {
+		if recordBool, ok := recordVal.(bool); ok {
+			mcpConfig.Record = recordBool
+		}
+	}
+
+	// Extract replay (serve tool calls from a recorded cassette)
+	if replayVal, hasReplay := mcpObj["replay"]; hasReplay {
+		if replayStr, ok := replayVal.(string); ok {
+			mcpConfig.Replay = replayStr
+		}
+	}
+
 	return mcpConfig
 }
 
@@ -27,7 +27,8 @@ func NewGeminiEngine() *GeminiEngine {
 			supportsWebSearch:      false,
 			supportsFirewall:       true, // Gemini supports network firewalling via AWF
 			supportsPlugins:        false,
-			supportsLLMGateway:     true, // Gemini supports LLM gateway on port 10003
+			supportsLLMGateway:     true,    // Gemini supports LLM gateway on port 10003
+			contextTokenLimit:      1000000, // Gemini models have a 1M token context window
 		},
 	}
 }
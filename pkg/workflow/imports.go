@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"maps"
+	"slices"
 	"sort"
 	"strings"
 	"sync"
@@ -117,6 +118,17 @@ func (c *Compiler) MergeNetworkPermissions(topNetwork *NetworkPermissions, impor
 		importsLog.Printf("Starting with %d top-level allowed domains", len(topNetwork.Allowed))
 	}
 
+	// Custom ecosystems of the workflow take precedence over those of its imports
+	ecosystems := make(map[string][]string)
+	topEcosystems := make(map[string]bool)
+	if topNetwork != nil {
+		for name, domains := range topNetwork.Ecosystems {
+			ecosystems[name] = domains
+			topEcosystems[name] = true
+		}
+	}
+	onlyEcosystems := true // Imports that only define ecosystems keep the default network
+
 	// Track domains to avoid duplicates
 	domainSet := make(map[string]bool)
 	for _, domain := range result.Allowed {
@@ -139,6 +151,22 @@ func (c *Compiler) MergeNetworkPermissions(topNetwork *NetworkPermissions, impor
 			continue // Skip invalid lines
 		}
 
+		// Merge custom ecosystems from imported network
+		for name, domains := range importedNetwork.Ecosystems {
+			existing, exists := ecosystems[name]
+			switch {
+			case !exists:
+				ecosystems[name] = domains
+			case topEcosystems[name]:
+				importsLog.Printf("Custom ecosystem %s of the workflow overrides an imported definition", name)
+			case !slices.Equal(existing, domains):
+				return nil, fmt.Errorf("network ecosystem '%s' is defined differently by multiple imports; define it in one shared file or override it in the workflow", name)
+			}
+		}
+		if len(importedNetwork.Allowed) > 0 || len(importedNetwork.Blocked) > 0 || importedNetwork.Firewall != nil {
+			onlyEcosystems = false
+		}
+
 		// Merge allowed domains from imported network
 		for _, domain := range importedNetwork.Allowed {
 			if !domainSet[domain] {
@@ -148,6 +176,15 @@ func (c *Compiler) MergeNetworkPermissions(topNetwork *NetworkPermissions, impor
 		}
 	}
 
+	// Without a top-level network, ecosystem definitions alone do not restrict the default network
+	if topNetwork == nil && onlyEcosystems {
+		importsLog.Print("Imports only define custom ecosystems, keeping the default network")
+		return topNetwork, nil
+	}
+	if len(ecosystems) > 0 {
+		result.Ecosystems = ecosystems
+	}
+
 	// Sort the final domain list for consistent output
 	sort.Strings(result.Allowed)
 
@@ -495,6 +532,8 @@ func hasSafeOutputType(config *SafeOutputsConfig, key string) bool {
 		return config.HideComment != nil
 	case "dispatch-workflow":
 		return config.DispatchWorkflow != nil
+	case "send-webhook":
+		return config.SendWebhook != nil
 	case "missing-data":
 		return config.MissingData != nil
 	case "missing-tool":
@@ -621,6 +660,9 @@ func mergeSafeOutputConfig(result *SafeOutputsConfig, config map[string]any, c *
 	if result.DispatchWorkflow == nil && importedConfig.DispatchWorkflow != nil {
 		result.DispatchWorkflow = importedConfig.DispatchWorkflow
 	}
+	if result.SendWebhook == nil && importedConfig.SendWebhook != nil {
+		result.SendWebhook = importedConfig.SendWebhook
+	}
 	if result.MissingTool == nil && importedConfig.MissingTool != nil {
 		result.MissingTool = importedConfig.MissingTool
 	}
@@ -186,3 +186,127 @@ func TestImportInputsExpressionValidation(t *testing.T) {
 		t.Errorf("Expression validation should allow github.aw.inputs.* expressions: %v", err)
 	}
 }
+
+// TestImportWithTypedInputs tests that array, object and default inputs are substituted
+func TestImportWithTypedInputs(t *testing.T) {
+	tempDir := testutil.TempDir(t, "test-import-typed-inputs-*")
+
+	sharedDir := filepath.Join(tempDir, "shared")
+	if err := os.MkdirAll(sharedDir, 0755); err != nil {
+		t.Fatalf("Failed to create shared directory: %v", err)
+	}
+	sharedContent := `---
+inputs:
+  labels:
+    type: array
+    items:
+      type: string
+  config:
+    type: object
+    properties:
+      region:
+        required: true
+  summary:
+    type: string
+    default: "labels in ${{ github.aw.inputs.config.region }}"
+---
+
+Apply labels ${{ github.aw.inputs.labels }} in region ${{ github.aw.inputs.config.region }}.
+Config: ${{ github.aw.inputs.config }}
+Summary: ${{ github.aw.inputs.summary }}
+`
+	if err := os.WriteFile(filepath.Join(sharedDir, "labels.md"), []byte(sharedContent), 0644); err != nil {
+		t.Fatalf("Failed to write shared file: %v", err)
+	}
+
+	workflowPath := filepath.Join(tempDir, "test-workflow.md")
+	workflowContent := `---
+on: issues
+permissions:
+  contents: read
+engine: copilot
+imports:
+  - path: shared/labels.md
+    inputs:
+      labels: [bug, triage]
+      config:
+        region: eu
+---
+
+# Test Workflow
+`
+	if err := os.WriteFile(workflowPath, []byte(workflowContent), 0644); err != nil {
+		t.Fatalf("Failed to write workflow file: %v", err)
+	}
+
+	compiler := workflow.NewCompiler()
+	if err := compiler.CompileWorkflow(workflowPath); err != nil {
+		t.Fatalf("CompileWorkflow failed: %v", err)
+	}
+
+	lockFileContent, err := os.ReadFile(stringutil.MarkdownToLockFile(workflowPath))
+	if err != nil {
+		t.Fatalf("Failed to read lock file: %v", err)
+	}
+	lockContent := string(lockFileContent)
+
+	for _, expected := range []string{`Apply labels ["bug","triage"] in region eu.`, `Config: {"region":"eu"}`, "Summary: labels in eu"} {
+		if !strings.Contains(lockContent, expected) {
+			t.Errorf("Lock file should contain %q", expected)
+		}
+	}
+	if strings.Contains(lockContent, "github.aw.inputs") {
+		t.Error("Lock file should not contain unsubstituted github.aw.inputs expressions")
+	}
+}
+
+// TestImportInputsValidationError tests that invalid inputs are reported at the call site
+func TestImportInputsValidationError(t *testing.T) {
+	tempDir := testutil.TempDir(t, "test-import-inputs-error-*")
+
+	sharedDir := filepath.Join(tempDir, "shared")
+	if err := os.MkdirAll(sharedDir, 0755); err != nil {
+		t.Fatalf("Failed to create shared directory: %v", err)
+	}
+	sharedContent := `---
+inputs:
+  count:
+    type: number
+    max: 100
+---
+
+Fetch ${{ github.aw.inputs.count }} items.
+`
+	if err := os.WriteFile(filepath.Join(sharedDir, "fetch.md"), []byte(sharedContent), 0644); err != nil {
+		t.Fatalf("Failed to write shared file: %v", err)
+	}
+
+	workflowPath := filepath.Join(tempDir, "test-workflow.md")
+	workflowContent := `---
+on: issues
+permissions:
+  contents: read
+engine: copilot
+imports:
+  - path: shared/fetch.md
+    inputs:
+      count: 500
+---
+
+# Test Workflow
+`
+	if err := os.WriteFile(workflowPath, []byte(workflowContent), 0644); err != nil {
+		t.Fatalf("Failed to write workflow file: %v", err)
+	}
+
+	compiler := workflow.NewCompiler()
+	err := compiler.CompileWorkflow(workflowPath)
+	if err == nil {
+		t.Fatal("CompileWorkflow should fail for an out-of-range input")
+	}
+	for _, expected := range []string{"test-workflow.md:9:7", "value 500 is greater than the maximum 100"} {
+		if !strings.Contains(err.Error(), expected) {
+			t.Errorf("Error should contain %q, got: %v", expected, err)
+		}
+	}
+}
@@ -227,9 +227,10 @@ func (jm *JobManager) renderJob(job *Job) string {
 
 	fmt.Fprintf(&yaml, "  %s:\n", job.Name)
 
-	// Add display name if present
+	// Add display name if present. Display names are quoted because they may contain
+	// characters such as ": " that are not allowed in a plain YAML scalar
 	if job.DisplayName != "" {
-		fmt.Fprintf(&yaml, "    name: %s\n", job.DisplayName)
+		fmt.Fprintf(&yaml, "    name: %s\n", QuoteYAMLString(job.DisplayName))
 	}
 
 	// Add needs clause if there are dependencies
@@ -26,6 +26,7 @@ type LockMetadata struct {
 	FrontmatterHash string            `json:"frontmatter_hash,omitempty"`
 	StopTime        string            `json:"stop_time,omitempty"`
 	CompilerVersion string            `json:"compiler_version,omitempty"`
+	Imports         map[string]string `json:"imports,omitempty"` // remote import workflowspec (owner/repo/path@ref) -> commit SHA
 }
 
 // SupportedSchemaVersions lists all schema versions this build can consume
@@ -3,8 +3,10 @@
 package workflow
 
 import (
+	"strings"
 	"testing"
 
+	"github.com/github/gh-aw/pkg/parser"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -322,6 +324,17 @@ func TestLockMetadataToJSON(t *testing.T) {
 				`"compiler_version":"v0.1.2"`,
 			},
 		},
+		{
+			name: "metadata with remote imports",
+			metadata: &LockMetadata{
+				SchemaVersion:   LockSchemaV1,
+				FrontmatterHash: "test123",
+				Imports:         map[string]string{"githubnext/agentics/shared/mcp.md@v1": "0123456789abcdef0123456789abcdef01234567"},
+			},
+			contains: []string{
+				`"imports":{"githubnext/agentics/shared/mcp.md@v1":"0123456789abcdef0123456789abcdef01234567"}`,
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -520,3 +533,19 @@ func TestLockMetadataToJSONWithoutStopTime(t *testing.T) {
 	// Should not contain stop_time field when empty due to omitempty
 	assert.NotContains(t, json, `"stop_time"`)
 }
+
+func TestLockMetadataRecordsRemoteImports(t *testing.T) {
+	compiler := NewCompiler()
+	var yaml strings.Builder
+	compiler.generateWorkflowHeader(&yaml, &WorkflowData{
+		RemoteImports: []parser.ImportRef{
+			{Owner: "githubnext", Repo: "agentics", Path: "shared/mcp.md", Ref: "v1", SHA: "0123456789abcdef0123456789abcdef01234567"},
+		},
+	}, "abcd1234")
+
+	metadata, isLegacy, err := ExtractMetadataFromLockFile(yaml.String())
+	require.NoError(t, err)
+	require.NotNil(t, metadata)
+	assert.False(t, isLegacy)
+	assert.Equal(t, map[string]string{"githubnext/agentics/shared/mcp.md@v1": "0123456789abcdef0123456789abcdef01234567"}, metadata.Imports)
+}
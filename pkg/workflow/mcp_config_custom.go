@@ -581,6 +581,7 @@ func getMCPConfig(toolConfig map[string]any, toolName string) (*parser.MCPServer
 		"registry":       true,
 		"allowed":        true,
 		"toolsets":       true, // Added for MCPServerConfig struct
+		"constraints":    true, // per-tool argument constraints (enforced by the gateway)
 	}
 
 	for key := range toolConfig {
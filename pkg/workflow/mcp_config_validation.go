@@ -138,6 +138,7 @@ func getRawMCPConfig(toolConfig map[string]any) (map[string]any, error) {
 		"proxy-args":     true,
 		"registry":       true,
 		"allowed":        true,
+		"constraints":    true, // per-tool argument constraints
 		"mode":           true, // for github tool
 		"github-token":   true, // for github tool
 		"read-only":      true, // for github tool
@@ -22,7 +22,8 @@
 // Configuration flow:
 //  1. ensureDefaultMCPGatewayConfig: Sets defaults if not provided
 //  2. buildMCPGatewayConfig: Builds gateway config for MCP files
-//  3. isSandboxDisabled: Checks if sandbox features are disabled
+//  3. requiredMCPGatewaySpecVersion: Minimum specification version for the features in use
+//  4. isSandboxDisabled: Checks if sandbox features are disabled
 //
 // When sandbox is disabled (sandbox: false), the gateway is skipped entirely
 // and MCP servers communicate directly without the gateway proxy.
@@ -122,12 +123,37 @@ func buildMCPGatewayConfig(workflowData *WorkflowData) *MCPGatewayRuntimeConfig
 	// Return gateway config with required fields populated
 	// Use ${...} syntax for environment variable references that will be resolved by the gateway at runtime
 	// Per MCP Gateway Specification v1.0.0 section 4.2, variable expressions use "${VARIABLE_NAME}" syntax
-	return &MCPGatewayRuntimeConfig{
+	gatewayConfig := &MCPGatewayRuntimeConfig{
 		Port:       int(DefaultMCPGatewayPort),   // Will be formatted as "${MCP_GATEWAY_PORT}" in renderer
 		Domain:     "${MCP_GATEWAY_DOMAIN}",      // Gateway variable expression
 		APIKey:     "${MCP_GATEWAY_API_KEY}",     // Gateway variable expression
 		PayloadDir: "${MCP_GATEWAY_PAYLOAD_DIR}", // Gateway variable expression for payload directory
+		Record:     workflowData.SandboxConfig.MCP.Record,
+		// Per MCP Gateway Specification v1.9.0 section 5.5, the gateway enforces argument constraints
+		ToolConstraints: workflowData.MCPToolConstraints,
 	}
+	// The replay cassette is copied out of the workspace before the gateway starts
+	// (see generateMCPSetup), so the agent cannot change the recorded responses
+	if workflowData.SandboxConfig.MCP.Replay != "" {
+		gatewayConfig.Replay = constants.DefaultMCPGatewayReplayPath
+	}
+	return gatewayConfig
+}
+
+// requiredMCPGatewaySpecVersion returns the MCP Gateway Specification version the gateway
+// must implement for the features the workflow uses, or "" when any version will do.
+// start_mcp_gateway.sh compares it with the specVersion reported by /health (section 8.1.1).
+func requiredMCPGatewaySpecVersion(workflowData *WorkflowData) string {
+	if workflowData == nil {
+		return ""
+	}
+	if sandbox := workflowData.SandboxConfig; sandbox != nil && sandbox.MCP != nil && (sandbox.MCP.Record || sandbox.MCP.Replay != "") {
+		return string(constants.MCPGatewayCassetteSpecVersion)
+	}
+	if len(workflowData.MCPToolConstraints) > 0 {
+		return string(constants.MCPGatewayToolConstraintsSpecVersion)
+	}
+	return ""
 }
 
 // isSandboxDisabled checks if sandbox features are completely disabled (sandbox: false)
@@ -3,9 +3,13 @@
 package workflow
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/github/gh-aw/pkg/constants"
+	"github.com/github/gh-aw/pkg/stringutil"
+	"github.com/github/gh-aw/pkg/testutil"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -228,6 +232,36 @@ func TestBuildMCPGatewayConfig(t *testing.T) {
 				PayloadDir: "${MCP_GATEWAY_PAYLOAD_DIR}",
 			},
 		},
+		{
+			name: "with recording enabled",
+			workflowData: &WorkflowData{
+				SandboxConfig: &SandboxConfig{
+					MCP: &MCPGatewayRuntimeConfig{Record: true},
+				},
+			},
+			expected: &MCPGatewayRuntimeConfig{
+				Port:       int(DefaultMCPGatewayPort),
+				Domain:     "${MCP_GATEWAY_DOMAIN}",
+				APIKey:     "${MCP_GATEWAY_API_KEY}",
+				PayloadDir: "${MCP_GATEWAY_PAYLOAD_DIR}",
+				Record:     true,
+			},
+		},
+		{
+			name: "with replay cassette",
+			workflowData: &WorkflowData{
+				SandboxConfig: &SandboxConfig{
+					MCP: &MCPGatewayRuntimeConfig{Replay: "./.github/cassettes/triage.jsonl"},
+				},
+			},
+			expected: &MCPGatewayRuntimeConfig{
+				Port:       int(DefaultMCPGatewayPort),
+				Domain:     "${MCP_GATEWAY_DOMAIN}",
+				APIKey:     "${MCP_GATEWAY_API_KEY}",
+				PayloadDir: "${MCP_GATEWAY_PAYLOAD_DIR}",
+				Replay:     "/opt/gh-aw/mcp-cassettes/replay.jsonl",
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -241,6 +275,8 @@ func TestBuildMCPGatewayConfig(t *testing.T) {
 				assert.Equal(t, tt.expected.Domain, result.Domain, "Domain should match")
 				assert.Equal(t, tt.expected.APIKey, result.APIKey, "APIKey should match")
 				assert.Equal(t, tt.expected.PayloadDir, result.PayloadDir, "PayloadDir should match")
+				assert.Equal(t, tt.expected.Record, result.Record, "Record should match")
+				assert.Equal(t, tt.expected.Replay, result.Replay, "Replay should match")
 			}
 		})
 	}
@@ -355,3 +391,114 @@ func TestIsAgentSandboxDisabled(t *testing.T) {
 		})
 	}
 }
+
+func TestRequiredMCPGatewaySpecVersion(t *testing.T) {
+	constraints := MCPToolConstraints{"github": {"issue_read": {"repo": {Pattern: "docs-.*"}}}}
+
+	tests := []struct {
+		name         string
+		workflowData *WorkflowData
+		expected     string
+	}{
+		{
+			name:         "nil workflow data",
+			workflowData: nil,
+			expected:     "",
+		},
+		{
+			name:         "no features",
+			workflowData: &WorkflowData{SandboxConfig: &SandboxConfig{MCP: &MCPGatewayRuntimeConfig{}}},
+			expected:     "",
+		},
+		{
+			name:         "tool constraints",
+			workflowData: &WorkflowData{MCPToolConstraints: constraints},
+			expected:     string(constants.MCPGatewayToolConstraintsSpecVersion),
+		},
+		{
+			name:         "recording",
+			workflowData: &WorkflowData{SandboxConfig: &SandboxConfig{MCP: &MCPGatewayRuntimeConfig{Record: true}}},
+			expected:     string(constants.MCPGatewayCassetteSpecVersion),
+		},
+		{
+			name: "replay and tool constraints",
+			workflowData: &WorkflowData{
+				SandboxConfig:      &SandboxConfig{MCP: &MCPGatewayRuntimeConfig{Replay: "a.jsonl"}},
+				MCPToolConstraints: constraints,
+			},
+			expected: string(constants.MCPGatewayCassetteSpecVersion),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := requiredMCPGatewaySpecVersion(tt.workflowData)
+			assert.Equal(t, tt.expected, result, "requiredMCPGatewaySpecVersion result should match expected")
+		})
+	}
+}
+
+func TestMCPGatewayRecordKeepsPinnedVersion(t *testing.T) {
+	tmpDir := testutil.TempDir(t, "mcp-gateway-record-pinned")
+
+	workflow := `---
+on: workflow_dispatch
+permissions:
+  contents: read
+engine: copilot
+sandbox:
+  mcp:
+    version: v0.1.4
+    record: true
+---
+
+# Triage
+
+Read the repository.
+`
+	workflowPath := filepath.Join(tmpDir, "record.md")
+	require.NoError(t, os.WriteFile(workflowPath, []byte(workflow), 0644))
+	require.NoError(t, NewCompiler().CompileWorkflow(workflowPath), "Workflow with record should compile")
+
+	lockContent, err := os.ReadFile(stringutil.MarkdownToLockFile(workflowPath))
+	require.NoError(t, err, "Lock file should be written")
+	lock := string(lockContent)
+
+	assert.Contains(t, lock, "gh-aw-mcpg:v0.1.4", "Pinned gateway version should be kept")
+	assert.Contains(t, lock, `export MCP_GATEWAY_MIN_SPEC_VERSION="`+string(constants.MCPGatewayCassetteSpecVersion)+`"`, "Gateway start should check the specification version")
+}
+
+func TestMCPGatewayReplayCompile(t *testing.T) {
+	tmpDir := testutil.TempDir(t, "mcp-gateway-replay")
+
+	workflow := `---
+on: workflow_dispatch
+permissions:
+  contents: read
+engine: copilot
+sandbox:
+  mcp:
+    replay: .github/cassettes/triage.jsonl
+tools:
+  github:
+    toolsets: [repos]
+---
+
+# Triage
+
+Read the repository.
+`
+	workflowPath := filepath.Join(tmpDir, "replay.md")
+	require.NoError(t, os.WriteFile(workflowPath, []byte(workflow), 0644))
+	require.NoError(t, NewCompiler().CompileWorkflow(workflowPath), "Workflow with replay should compile")
+
+	lockContent, err := os.ReadFile(stringutil.MarkdownToLockFile(workflowPath))
+	require.NoError(t, err, "Lock file should be written")
+	lock := string(lockContent)
+
+	assert.Contains(t, lock, "gh-aw-mcpg:"+string(constants.DefaultMCPGatewayVersion), "Replay should not change the gateway version")
+	assert.Contains(t, lock, `export MCP_GATEWAY_MIN_SPEC_VERSION="`+string(constants.MCPGatewayCassetteSpecVersion)+`"`, "Gateway start should require a specification version with replay")
+	assert.Contains(t, lock, `cp "${GITHUB_WORKSPACE}/.github/cassettes/triage.jsonl" `+constants.DefaultMCPGatewayReplayPath, "Cassette should be copied out of the workspace")
+	assert.Contains(t, lock, `"replay": "`+constants.DefaultMCPGatewayReplayPath+`"`, "Gateway should read the copied cassette")
+	assert.NotContains(t, lock, `"replay": "${GITHUB_WORKSPACE}`, "Gateway should not read the cassette from the workspace")
+}
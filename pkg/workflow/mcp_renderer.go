@@ -76,6 +76,7 @@
 package workflow
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
 	"sort"
@@ -185,6 +186,7 @@ func (r *MCPConfigRendererUnified) RenderGitHubMCP(yaml *strings.Builder, github
 			IncludeTypeField:   r.options.IncludeCopilotFields,
 			AllowedTools:       getGitHubAllowedTools(githubTool),
 			EffectiveToken:     "", // Token passed via env
+			Host:               compileTargetGitHubHost(workflowData),
 		})
 	}
 
@@ -562,6 +564,10 @@ func (r *MCPConfigRendererUnified) renderGitHubTOML(yaml *strings.Builder, githu
 
 		envVars["GITHUB_TOOLSETS"] = toolsets
 
+		if host := compileTargetGitHubHost(workflowData); host != "" {
+			envVars["GITHUB_HOST"] = host
+		}
+
 		// Write environment variables in sorted order for deterministic output
 		envKeys := make([]string, 0, len(envVars))
 		for key := range envVars {
@@ -675,6 +681,8 @@ type GitHubMCPDockerOptions struct {
 	EffectiveToken string
 	// Mounts specifies volume mounts for the GitHub MCP server container (format: "host:container:mode")
 	Mounts []string
+	// Host is the GitHub Enterprise Server URL the server connects to (empty for github.com)
+	Host string
 }
 
 // RenderGitHubMCPDockerConfig renders the GitHub MCP server configuration for Docker (local mode).
@@ -697,8 +705,14 @@ func RenderGitHubMCPDockerConfig(yaml *strings.Builder, options GitHubMCPDockerO
 	// Append custom args if present (these are Docker runtime args, go before container image)
 	if len(options.CustomArgs) > 0 {
 		yaml.WriteString("                \"args\": [\n")
-		for _, arg := range options.CustomArgs {
-			yaml.WriteString("                  \"" + arg + "\",\n")
+		for i, arg := range options.CustomArgs {
+			// Use json.Marshal to properly quote and escape the argument
+			quotedArg, _ := json.Marshal(arg)
+			yaml.WriteString("                  " + string(quotedArg))
+			if i < len(options.CustomArgs)-1 {
+				yaml.WriteString(",")
+			}
+			yaml.WriteString("\n")
 		}
 		yaml.WriteString("                ],\n")
 	}
@@ -754,6 +768,11 @@ func RenderGitHubMCPDockerConfig(yaml *strings.Builder, options GitHubMCPDockerO
 	// Toolsets (always configured, defaults to "default")
 	envVars["GITHUB_TOOLSETS"] = options.Toolsets
 
+	// GitHub Enterprise Server host
+	if options.Host != "" {
+		envVars["GITHUB_HOST"] = options.Host
+	}
+
 	// Write environment variables in sorted order for deterministic output
 	envKeys := make([]string, 0, len(envVars))
 	for key := range envVars {
@@ -944,6 +963,8 @@ func RenderJSONMCPConfig(
 	// Write config file footer - but don't add newline yet if we need to add gateway
 	if options.GatewayConfig != nil {
 		configBuilder.WriteString("            },\n")
+		// Add argument constraints enforced by the gateway at call time
+		renderToolConstraintsJSON(&configBuilder, options.GatewayConfig.ToolConstraints, filteredTools)
 		// Add gateway section (needed for gateway to process)
 		// Per MCP Gateway Specification v1.0.0 section 4.2, use "${VARIABLE_NAME}" syntax for variable expressions
 		configBuilder.WriteString("            \"gateway\": {\n")
@@ -953,10 +974,18 @@ func RenderJSONMCPConfig(
 		fmt.Fprintf(&configBuilder, "              \"apiKey\": \"%s\"", options.GatewayConfig.APIKey)
 		// Add payloadDir if specified
 		if options.GatewayConfig.PayloadDir != "" {
-			fmt.Fprintf(&configBuilder, ",\n              \"payloadDir\": \"%s\"\n", options.GatewayConfig.PayloadDir)
-		} else {
-			configBuilder.WriteString("\n")
+			fmt.Fprintf(&configBuilder, ",\n              \"payloadDir\": \"%s\"", options.GatewayConfig.PayloadDir)
+		}
+		// Add cassette recording or replay if enabled
+		// Per MCP Gateway Specification v1.10.0 section 5.6; the gateway start step checks the
+		// specVersion the gateway reports (see requiredMCPGatewaySpecVersion)
+		if options.GatewayConfig.Record {
+			fmt.Fprintf(&configBuilder, ",\n              \"record\": \"%s\"", constants.DefaultMCPGatewayCassettePath)
+		}
+		if options.GatewayConfig.Replay != "" {
+			fmt.Fprintf(&configBuilder, ",\n              \"replay\": \"%s\"", options.GatewayConfig.Replay)
 		}
+		configBuilder.WriteString("\n")
 		configBuilder.WriteString("            }\n")
 	} else {
 		configBuilder.WriteString("            }\n")
@@ -62,6 +62,7 @@ package workflow
 import (
 	"encoding/json"
 	"fmt"
+	"path"
 	"slices"
 	"sort"
 	"strconv"
@@ -513,6 +514,19 @@ func (c *Compiler) generateMCPSetup(yaml *strings.Builder, tools map[string]any,
 	yaml.WriteString("          export MCP_GATEWAY_PAYLOAD_DIR=\"" + payloadDir + "\"\n")
 	yaml.WriteString("          mkdir -p \"${MCP_GATEWAY_PAYLOAD_DIR}\"\n")
 
+	// Copy the replay cassette out of the writable workspace before the gateway reads it
+	if gatewayConfig.Replay != "" {
+		replayDir := path.Dir(constants.DefaultMCPGatewayReplayPath)
+		yaml.WriteString("          mkdir -p " + replayDir + "\n")
+		yaml.WriteString("          cp \"${GITHUB_WORKSPACE}/" + strings.TrimPrefix(gatewayConfig.Replay, "./") + "\" " + constants.DefaultMCPGatewayReplayPath + "\n")
+		yaml.WriteString("          chmod 0444 " + constants.DefaultMCPGatewayReplayPath + "\n")
+	}
+
+	// Fail at startup if the gateway does not implement the features the configuration relies on
+	if minSpecVersion := requiredMCPGatewaySpecVersion(workflowData); minSpecVersion != "" {
+		yaml.WriteString("          export MCP_GATEWAY_MIN_SPEC_VERSION=\"" + minSpecVersion + "\"\n")
+	}
+
 	yaml.WriteString("          export DEBUG=\"*\"\n")
 	yaml.WriteString("          \n")
 
@@ -0,0 +1,529 @@
+package workflow
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/github/gh-aw/pkg/logger"
+	"github.com/github/gh-aw/pkg/parser"
+)
+
+var mergeDirectivesLog = logger.New("workflow:merge_directives")
+
+const (
+	// mergeDirectiveRemove drops a setting, or a list entry when written as "!remove:<value>"
+	mergeDirectiveRemove = "!remove"
+	// mergeDirectiveReplace makes the importing workflow's object or list replace the imported one
+	mergeDirectiveReplace = "!replace"
+)
+
+// mergeDirectiveSections are the frontmatter sections whose settings accept merge directives
+var mergeDirectiveSections = []string{"tools", "mcp-servers", "network", "safe-outputs", "permissions"}
+
+// MergeDirectives holds the merge directives declared in the frontmatter of an importing workflow.
+// Directives are keyed by the dotted path of the setting they apply to (e.g., "tools.playwright").
+//
+// Supported forms:
+//
+//	tools:
+//	  playwright: "!remove"          # drop a setting contributed by imports
+//	  github:
+//	    "!replace": true             # use this object instead of merging it with imported ones
+//	    toolsets: [issues]
+//	network:
+//	  allowed:
+//	    - "!replace"                 # ignore the domains of imports
+//	    - "!remove:api.example.com"  # drop a single imported list entry
+type MergeDirectives struct {
+	Remove       map[string]bool     // Settings removed with "!remove"
+	Replace      map[string]bool     // Objects and lists whose imported values are ignored
+	RemoveValues map[string][]string // List entries removed with "!remove:<value>", keyed by list path
+}
+
+// IsEmpty returns true if no merge directives are declared
+func (d *MergeDirectives) IsEmpty() bool {
+	return d == nil || (len(d.Remove) == 0 && len(d.Replace) == 0 && len(d.RemoveValues) == 0)
+}
+
+// extractMergeDirectives removes the merge directives from the tools, mcp-servers, network, safe-outputs
+// and permissions sections of an importing workflow's frontmatter and returns them. The frontmatter is
+// modified in place so that schema validation and configuration parsing only see regular settings.
+// Sections that contained nothing but directives are removed.
+func extractMergeDirectives(frontmatter map[string]any) (*MergeDirectives, error) {
+	directives := &MergeDirectives{
+		Remove:       make(map[string]bool),
+		Replace:      make(map[string]bool),
+		RemoveValues: make(map[string][]string),
+	}
+
+	for _, section := range mergeDirectiveSections {
+		sectionMap, ok := frontmatter[section].(map[string]any)
+		if !ok || len(sectionMap) == 0 {
+			continue
+		}
+		if err := directives.extractFromMap(section, sectionMap); err != nil {
+			return nil, err
+		}
+		if len(sectionMap) == 0 {
+			delete(frontmatter, section)
+		}
+	}
+
+	if directives.IsEmpty() {
+		return nil, nil
+	}
+	mergeDirectivesLog.Printf("Extracted merge directives: remove=%d, replace=%d, remove-values=%d",
+		len(directives.Remove), len(directives.Replace), len(directives.RemoveValues))
+	return directives, nil
+}
+
+// extractFromMap removes the directives of an object and its children, recording them under path
+func (d *MergeDirectives) extractFromMap(path string, values map[string]any) error {
+	for key, value := range values {
+		childPath := path + "." + key
+		if key == mergeDirectiveReplace {
+			if enabled, ok := value.(bool); !ok || !enabled {
+				return fmt.Errorf("%s: '%s' must be set to true", childPath, mergeDirectiveReplace)
+			}
+			d.Replace[path] = true
+			delete(values, key)
+			continue
+		}
+
+		switch typed := value.(type) {
+		case string:
+			switch {
+			case typed == mergeDirectiveRemove:
+				d.Remove[childPath] = true
+				delete(values, key)
+			case typed == mergeDirectiveReplace:
+				return fmt.Errorf("%s: '%s' is only valid as an object key or list entry; use '%s' to drop the setting", childPath, mergeDirectiveReplace, mergeDirectiveRemove)
+			case strings.HasPrefix(typed, mergeDirectiveRemove+":"):
+				return fmt.Errorf("%s: '%s' is only valid as a list entry", childPath, typed)
+			}
+		case map[string]any:
+			if err := d.extractFromMap(childPath, typed); err != nil {
+				return err
+			}
+		case []any:
+			cleaned, err := d.extractFromList(childPath, typed)
+			if err != nil {
+				return err
+			}
+			values[key] = cleaned
+		}
+	}
+	return nil
+}
+
+// extractFromList removes the "!replace" and "!remove:<value>" entries of a list, recording them under path
+func (d *MergeDirectives) extractFromList(path string, items []any) ([]any, error) {
+	cleaned := make([]any, 0, len(items))
+	for _, item := range items {
+		text, ok := item.(string)
+		switch {
+		case ok && text == mergeDirectiveReplace:
+			d.Replace[path] = true
+		case ok && text == mergeDirectiveRemove:
+			return nil, fmt.Errorf("%s: list entries are removed with '%s:<value>'", path, mergeDirectiveRemove)
+		case ok && strings.HasPrefix(text, mergeDirectiveRemove+":"):
+			value := strings.TrimSpace(strings.TrimPrefix(text, mergeDirectiveRemove+":"))
+			if value == "" {
+				return nil, fmt.Errorf("%s: '%s' is missing the value to remove", path, text)
+			}
+			d.RemoveValues[path] = append(d.RemoveValues[path], value)
+		default:
+			cleaned = append(cleaned, item)
+		}
+	}
+	return cleaned, nil
+}
+
+// isRemoved reports whether the setting at path is removed. Tools and mcp-servers share one
+// namespace after merging, so a directive on either section applies to both.
+func (d *MergeDirectives) isRemoved(path string) bool {
+	return d != nil && (d.Remove[path] || d.Remove[toolsAliasPath(path)])
+}
+
+// isReplaced reports whether the imported values of the setting at path are ignored
+func (d *MergeDirectives) isReplaced(path string) bool {
+	return d != nil && (d.Replace[path] || d.Replace[toolsAliasPath(path)])
+}
+
+// removedValues returns the list entries removed from the list at path
+func (d *MergeDirectives) removedValues(path string) []string {
+	if d == nil {
+		return nil
+	}
+	return slices.Concat(d.RemoveValues[path], d.RemoveValues[toolsAliasPath(path)])
+}
+
+// toolsAliasPath maps a tools.* path to the matching mcp-servers.* path and vice versa
+func toolsAliasPath(path string) string {
+	if rest, ok := strings.CutPrefix(path, "tools."); ok {
+		return "mcp-servers." + rest
+	}
+	if rest, ok := strings.CutPrefix(path, "mcp-servers."); ok {
+		return "tools." + rest
+	}
+	return ""
+}
+
+// MergeProvenance lists the file that contributed each setting of a workflow after imports are merged
+type MergeProvenance struct {
+	Workflow string                 `json:"workflow"`
+	Entries  []MergeProvenanceEntry `json:"entries"`
+
+	paths  map[string]string          // File path of each source, keyed by display path
+	owners map[string][]settingOwner  // Files that set each setting while imports were merged, in merge order
+	merged map[string]map[string]bool // Merged settings of each section ("setting\x00value" keys)
+	steps  int                        // Number of recorded merge steps, to order owners
+}
+
+// MergeProvenanceEntry records where a single merged setting came from
+type MergeProvenanceEntry struct {
+	Setting string `json:"setting" console:"header:Setting"`
+	Value   string `json:"value,omitempty" console:"header:Value,maxlen:50"`
+	Source  string `json:"source" console:"header:Source"`
+	Status  string `json:"status" console:"header:Status"`
+}
+
+// Provenance statuses
+const (
+	provenanceEffective  = "effective"
+	provenanceRequired   = "required" // permission required by an import, granted by the workflow
+	provenanceOverridden = "overridden by "
+	provenanceReplaced   = "replaced by "
+	provenanceRemoved    = "removed by "
+	provenanceNotMerged  = "not merged"
+)
+
+// add records the leaf settings of value under path, one entry per list item
+func (p *MergeProvenance) add(path string, value any, source, status string) {
+	walkSettings(path, value, func(setting, formatted string) {
+		p.Entries = append(p.Entries, MergeProvenanceEntry{Setting: setting, Value: formatted, Source: source, Status: status})
+	})
+}
+
+// walkSettings calls fn with the dotted path and formatted value of each leaf setting of value,
+// once per list item. Empty objects are reported as "{}".
+func walkSettings(path string, value any, fn func(setting, value string)) {
+	walkSettingLeaves(path, value, func(setting, formatted string, _ bool) {
+		fn(setting, formatted)
+	})
+}
+
+// walkSettingLeaves is walkSettings that also reports whether each value is a list item
+func walkSettingLeaves(path string, value any, fn func(setting, value string, listItem bool)) {
+	switch typed := value.(type) {
+	case map[string]any:
+		if len(typed) == 0 {
+			fn(path, "{}", false)
+			return
+		}
+		for _, key := range slices.Sorted(maps.Keys(typed)) {
+			walkSettingLeaves(path+"."+key, typed[key], fn)
+		}
+	case []any:
+		for _, item := range typed {
+			fn(path, formatProvenanceValue(item), true)
+		}
+	default:
+		fn(path, formatProvenanceValue(value), false)
+	}
+}
+
+// formatProvenanceValue formats a setting value for display
+func formatProvenanceValue(value any) string {
+	switch typed := value.(type) {
+	case nil:
+		return "(defaults)"
+	case string:
+		return typed
+	case map[string]any, []any:
+		data, err := json.Marshal(typed)
+		if err != nil {
+			return fmt.Sprint(typed)
+		}
+		return string(data)
+	default:
+		return fmt.Sprint(typed)
+	}
+}
+
+// finalize sorts the entries by setting and combines identical entries contributed by several files
+func (p *MergeProvenance) finalize() {
+	var combined []MergeProvenanceEntry
+	index := make(map[string]int)
+	for _, entry := range p.Entries {
+		key := entry.Setting + "\x00" + entry.Value + "\x00" + entry.Status
+		if i, exists := index[key]; exists {
+			if !slices.Contains(strings.Split(combined[i].Source, ", "), entry.Source) {
+				combined[i].Source += ", " + entry.Source
+			}
+			continue
+		}
+		index[key] = len(combined)
+		combined = append(combined, entry)
+	}
+	sort.SliceStable(combined, func(i, j int) bool {
+		return combined[i].Setting < combined[j].Setting
+	})
+	p.Entries = combined
+}
+
+// importFilter applies the importing workflow's directives to one imported section
+type importFilter struct {
+	directives *MergeDirectives
+	provenance *MergeProvenance
+	workflow   string // Display path of the importing workflow
+	file       string // Display path of the imported file
+}
+
+// apply removes the imported settings that are dropped or replaced by the importing workflow's directives
+func (f *importFilter) apply(path string, imported map[string]any) {
+	for key, value := range imported {
+		childPath := path + "." + key
+		if f.directives.isRemoved(childPath) {
+			f.provenance.add(childPath, value, f.file, provenanceRemoved+f.workflow)
+			delete(imported, key)
+			continue
+		}
+		if f.directives.isReplaced(childPath) {
+			f.provenance.add(childPath, value, f.file, provenanceReplaced+f.workflow)
+			delete(imported, key)
+			continue
+		}
+
+		switch typed := value.(type) {
+		case map[string]any:
+			f.apply(childPath, typed)
+		case []any:
+			imported[key] = f.filterList(childPath, typed)
+		}
+	}
+}
+
+// filterList drops the list entries removed with "!remove:<value>"
+func (f *importFilter) filterList(path string, items []any) []any {
+	removed := f.directives.removedValues(path)
+	if len(removed) == 0 {
+		return items
+	}
+	kept := make([]any, 0, len(items))
+	for _, item := range items {
+		if text, ok := item.(string); ok && slices.Contains(removed, text) {
+			f.provenance.add(path, item, f.file, provenanceRemoved+f.workflow)
+			continue
+		}
+		kept = append(kept, item)
+	}
+	return kept
+}
+
+// applyMergeDirectives applies the importing workflow's merge directives to the tools, mcp-servers,
+// network, permissions and safe-outputs contributed by imports, and rebuilds the corresponding merged
+// fields of importsResult. Without directives, importsResult is left untouched so that imports are merged
+// exactly as before.
+//
+// It returns the provenance of every merged setting, for 'gh aw compile --explain-merge'.
+func (c *Compiler) applyMergeDirectives(frontmatter map[string]any, importsResult *parser.ImportsResult, directives *MergeDirectives, workflowPath string) (*MergeProvenance, error) {
+	workflow := provenanceDisplayPath(workflowPath)
+	provenance := &MergeProvenance{Workflow: workflow, paths: map[string]string{workflow: workflowPath}}
+	for _, contribution := range importsResult.Contributions {
+		provenance.paths[provenanceDisplayPath(contribution.File)] = contribution.Path
+	}
+
+	for _, section := range mergeDirectiveSections {
+		if value, exists := frontmatter[section]; exists {
+			provenance.add(section, value, workflow, provenanceEffective)
+		}
+	}
+	provenance.addRuntimesAndFeatures(frontmatter, importsResult.Contributions, workflow)
+
+	if directives == nil {
+		directives = &MergeDirectives{}
+	}
+
+	contributions := slices.Clone(importsResult.Contributions)
+	var toolsBuilder, mcpServersBuilder, networkBuilder, permissionsBuilder strings.Builder
+	var safeOutputs []string
+
+	for i := range contributions {
+		contribution := &contributions[i]
+		file := provenanceDisplayPath(contribution.File)
+		filter := &importFilter{directives: directives, provenance: provenance, workflow: workflow, file: file}
+		filterSection := func(section, status string) func(map[string]any) {
+			return func(values map[string]any) {
+				filter.apply(section, values)
+				provenance.add(section, values, file, status)
+			}
+		}
+
+		// Tools may hold several JSON objects when the import uses @include directives
+		var toolLines []string
+		for line := range strings.SplitSeq(contribution.Tools, "\n") {
+			filtered, err := filterImportedSection(line, filterSection("tools", provenanceEffective))
+			if err != nil {
+				return nil, fmt.Errorf("failed to merge tools from '%s': %w", file, err)
+			}
+			if filtered != "" {
+				toolLines = append(toolLines, filtered)
+			}
+		}
+		contribution.Tools = strings.Join(toolLines, "\n")
+
+		var err error
+		contribution.MCPServers, err = filterImportedSection(contribution.MCPServers, filterSection("mcp-servers", provenanceEffective))
+		if err != nil {
+			return nil, fmt.Errorf("failed to merge mcp-servers from '%s': %w", file, err)
+		}
+		contribution.Network, err = filterImportedSection(contribution.Network, filterSection("network", provenanceEffective))
+		if err != nil {
+			return nil, fmt.Errorf("failed to merge network from '%s': %w", file, err)
+		}
+		// Imported permissions are requirements the workflow must grant
+		contribution.Permissions, err = filterImportedSection(contribution.Permissions, filterSection("permissions", provenanceRequired))
+		if err != nil {
+			return nil, fmt.Errorf("failed to merge permissions from '%s': %w", file, err)
+		}
+		contribution.SafeOutputs, err = filterImportedSection(contribution.SafeOutputs, filterSection("safe-outputs", provenanceEffective))
+		if err != nil {
+			return nil, fmt.Errorf("failed to merge safe-outputs from '%s': %w", file, err)
+		}
+
+		if contribution.Tools != "" {
+			toolsBuilder.WriteString(contribution.Tools + "\n")
+		}
+		if contribution.MCPServers != "" {
+			mcpServersBuilder.WriteString(contribution.MCPServers + "\n")
+		}
+		if contribution.Network != "" {
+			networkBuilder.WriteString(contribution.Network + "\n")
+		}
+		if contribution.Permissions != "" {
+			permissionsBuilder.WriteString(contribution.Permissions + "\n")
+		}
+		if contribution.SafeOutputs != "" {
+			safeOutputs = append(safeOutputs, contribution.SafeOutputs)
+		}
+	}
+
+	// Replay the merge to find the file behind each merged value
+	c.recordMergedSettings(frontmatter, contributions, provenance)
+	if permissions, exists := frontmatter["permissions"]; exists {
+		provenance.own("permissions", permissions, workflow)
+	}
+	for i := range provenance.Entries {
+		entry := &provenance.Entries[i]
+		section, _, _ := strings.Cut(entry.Setting, ".")
+		if entry.Status == provenanceEffective && section != "permissions" && section != "runtimes" && section != "features" {
+			entry.Status = provenance.mergedStatus(*entry)
+		}
+	}
+	provenance.finalize()
+
+	if directives.IsEmpty() {
+		return provenance, nil
+	}
+
+	mergeDirectivesLog.Printf("Applied merge directives to %d imports", len(contributions))
+	importsResult.Contributions = contributions
+	importsResult.MergedTools = toolsBuilder.String()
+	importsResult.MergedMCPServers = mcpServersBuilder.String()
+	importsResult.MergedNetwork = networkBuilder.String()
+	importsResult.MergedPermissions = permissionsBuilder.String()
+	importsResult.MergedSafeOutputs = safeOutputs
+
+	return provenance, nil
+}
+
+// addRuntimesAndFeatures records the provenance of runtimes, where imports override the workflow and later
+// imports override earlier ones, and of features, where the workflow and then earlier imports win
+func (p *MergeProvenance) addRuntimesAndFeatures(frontmatter map[string]any, contributions []parser.ImportContribution, workflow string) {
+	type source struct {
+		file     string
+		runtimes map[string]any
+		features map[string]any
+	}
+	mainRuntimes, _ := frontmatter["runtimes"].(map[string]any)
+	mainFeatures, _ := frontmatter["features"].(map[string]any)
+	sources := []source{{file: workflow, runtimes: mainRuntimes, features: mainFeatures}}
+	for _, contribution := range contributions {
+		imported := source{file: provenanceDisplayPath(contribution.File)}
+		// Malformed sections are skipped by the merge functions as well
+		_ = json.Unmarshal([]byte(contribution.Runtimes), &imported.runtimes)
+		_ = json.Unmarshal([]byte(contribution.Features), &imported.features)
+		sources = append(sources, imported)
+	}
+
+	runtimeWinners := make(map[string]string)
+	featureWinners := make(map[string]string)
+	for _, src := range sources {
+		for name := range src.runtimes {
+			runtimeWinners[name] = src.file
+		}
+		for name := range src.features {
+			if _, exists := featureWinners[name]; !exists {
+				featureWinners[name] = src.file
+			}
+		}
+	}
+
+	status := func(file, winner string) string {
+		if file == winner {
+			return provenanceEffective
+		}
+		return provenanceOverridden + winner
+	}
+	for _, src := range sources {
+		for name, value := range src.runtimes {
+			p.add("runtimes."+name, value, src.file, status(src.file, runtimeWinners[name]))
+			if src.file == runtimeWinners[name] {
+				p.own("runtimes."+name, value, src.file)
+			}
+		}
+		for name, value := range src.features {
+			p.add("features."+name, value, src.file, status(src.file, featureWinners[name]))
+			if src.file == featureWinners[name] {
+				p.own("features."+name, value, src.file)
+			}
+		}
+	}
+}
+
+// filterImportedSection decodes a JSON object, applies fn to it and re-encodes it.
+// It returns an empty string for empty sections.
+func filterImportedSection(sectionJSON string, fn func(map[string]any)) (string, error) {
+	sectionJSON = strings.TrimSpace(sectionJSON)
+	if sectionJSON == "" || sectionJSON == "{}" {
+		return "", nil
+	}
+	var section map[string]any
+	if err := json.Unmarshal([]byte(sectionJSON), &section); err != nil {
+		// Malformed sections are skipped by the merge functions, so leave them untouched
+		mergeDirectivesLog.Printf("Skipping malformed imported section: %v", err)
+		return sectionJSON, nil
+	}
+	fn(section)
+	if len(section) == 0 {
+		return "", nil
+	}
+	data, err := json.Marshal(section)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// provenanceDisplayPath returns path relative to the repository root when it is inside .github/
+func provenanceDisplayPath(path string) string {
+	if idx := strings.Index(path, "/.github/"); idx >= 0 {
+		return path[idx+1:]
+	}
+	return path
+}
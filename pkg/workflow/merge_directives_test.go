@@ -0,0 +1,353 @@
+//go:build !integration
+
+package workflow
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/github/gh-aw/pkg/parser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExtractMergeDirectives(t *testing.T) {
+	frontmatter := map[string]any{
+		"on": "daily",
+		"tools": map[string]any{
+			"playwright": "!remove",
+			"github":     map[string]any{"!replace": true, "toolsets": []any{"issues"}},
+			"bash":       []any{"!remove:rm", "echo"},
+		},
+		"network": map[string]any{
+			"allowed": []any{"!replace", "defaults"},
+		},
+		"safe-outputs": map[string]any{
+			"create-issue": map[string]any{"labels": "!remove"},
+		},
+		"permissions": map[string]any{"pull-requests": "!remove"},
+	}
+
+	directives, err := extractMergeDirectives(frontmatter)
+	require.NoError(t, err, "directives should be extracted")
+	assert.Equal(t, map[string]bool{
+		"tools.playwright":                 true,
+		"safe-outputs.create-issue.labels": true,
+		"permissions.pull-requests":        true,
+	}, directives.Remove, "removed settings")
+	assert.Equal(t, map[string]bool{"tools.github": true, "network.allowed": true}, directives.Replace, "replaced settings")
+	assert.Equal(t, map[string][]string{"tools.bash": {"rm"}}, directives.RemoveValues, "removed list entries")
+
+	assert.Equal(t, map[string]any{
+		"github": map[string]any{"toolsets": []any{"issues"}},
+		"bash":   []any{"echo"},
+	}, frontmatter["tools"], "directives should be stripped from tools")
+	assert.Equal(t, map[string]any{"allowed": []any{"defaults"}}, frontmatter["network"], "directives should be stripped from network")
+	assert.Equal(t, map[string]any{"create-issue": map[string]any{}}, frontmatter["safe-outputs"], "safe output type should be kept")
+	assert.NotContains(t, frontmatter, "permissions", "sections with only directives should be removed")
+
+	directives, err = extractMergeDirectives(map[string]any{"tools": map[string]any{"github": nil}})
+	require.NoError(t, err)
+	assert.Nil(t, directives, "frontmatter without directives should return nil")
+}
+
+func TestExtractMergeDirectivesErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		section map[string]any
+		wantErr string
+	}{
+		{
+			name:    "replace as value",
+			section: map[string]any{"github": "!replace"},
+			wantErr: "tools.github: '!replace' is only valid as an object key or list entry",
+		},
+		{
+			name:    "replace not true",
+			section: map[string]any{"github": map[string]any{"!replace": "yes"}},
+			wantErr: "tools.github.!replace: '!replace' must be set to true",
+		},
+		{
+			name:    "remove value outside a list",
+			section: map[string]any{"bash": "!remove:rm"},
+			wantErr: "'!remove:rm' is only valid as a list entry",
+		},
+		{
+			name:    "bare remove in a list",
+			section: map[string]any{"bash": []any{"!remove"}},
+			wantErr: "list entries are removed with '!remove:<value>'",
+		},
+		{
+			name:    "remove without value",
+			section: map[string]any{"bash": []any{"!remove: "}},
+			wantErr: "is missing the value to remove",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := extractMergeDirectives(map[string]any{"tools": tt.section})
+			require.Error(t, err, "directive should be rejected")
+			assert.Contains(t, err.Error(), tt.wantErr, "error should describe the problem")
+		})
+	}
+}
+
+func TestApplyMergeDirectives(t *testing.T) {
+	frontmatter := map[string]any{
+		"on": "daily",
+		"tools": map[string]any{
+			"github":     map[string]any{"read-only": false},
+			"playwright": "!remove",
+			"bash":       []any{"!remove:rm", "echo"},
+			"edit":       map[string]any{"!replace": true},
+		},
+		"network":      map[string]any{"allowed": []any{"defaults", "!remove:api.example.com"}},
+		"permissions":  map[string]any{"issues": "read", "pull-requests": "!remove"},
+		"safe-outputs": map[string]any{"add-comment": "!remove", "create-issue": map[string]any{"labels": "!remove"}},
+	}
+	directives, err := extractMergeDirectives(frontmatter)
+	require.NoError(t, err, "directives should be extracted")
+
+	importsResult := &parser.ImportsResult{
+		Contributions: []parser.ImportContribution{{
+			File:        ".github/workflows/shared/base.md",
+			Tools:       `{"github":{"read-only":true,"toolsets":["issues"]},"playwright":null,"bash":["ls","rm"],"edit":{"mode":"patch"}}`,
+			Network:     `{"allowed":["api.example.com","slack.com"],"blocked":["evil.example.com"]}`,
+			Permissions: `{"issues":"read","pull-requests":"read"}`,
+			SafeOutputs: `{"create-issue":{"title-prefix":"[bot] ","labels":["automation"],"max":5},"add-comment":{"max":2}}`,
+		}},
+	}
+
+	compiler := NewCompiler()
+	provenance, err := compiler.applyMergeDirectives(frontmatter, importsResult, directives, "/repo/.github/workflows/daily.md")
+	require.NoError(t, err, "directives should be applied")
+
+	assertJSONLine := func(expected, actual, msg string) {
+		t.Helper()
+		assert.JSONEq(t, expected, actual[:len(actual)-1], msg)
+		assert.Equal(t, "\n", actual[len(actual)-1:], "merged sections should be newline-terminated")
+	}
+	assertJSONLine(`{"github":{"read-only":true,"toolsets":["issues"]},"bash":["ls"]}`, importsResult.MergedTools,
+		"removed and replaced tools should be dropped")
+	assertJSONLine(`{"allowed":["slack.com"],"blocked":["evil.example.com"]}`, importsResult.MergedNetwork,
+		"removed domains should be dropped and other network settings kept")
+	assertJSONLine(`{"issues":"read"}`, importsResult.MergedPermissions, "removed permissions should not be required")
+	require.Len(t, importsResult.MergedSafeOutputs, 1)
+	assert.JSONEq(t, `{"create-issue":{"title-prefix":"[bot] ","max":5}}`, importsResult.MergedSafeOutputs[0],
+		"removed safe outputs and fields should be dropped")
+
+	assert.Equal(t, ".github/workflows/daily.md", provenance.Workflow, "workflow should be shown relative to the repository")
+	statuses := make(map[string]string)
+	for _, entry := range provenance.Entries {
+		statuses[entry.Setting+"="+entry.Value+"@"+entry.Source] = entry.Status
+	}
+	const main, shared = ".github/workflows/daily.md", ".github/workflows/shared/base.md"
+	for key, status := range map[string]string{
+		"tools.github.read-only=false@" + main:                    provenanceOverridden + shared,
+		"tools.github.read-only=true@" + shared:                   provenanceEffective,
+		"network.blocked=evil.example.com@" + shared:              provenanceNotMerged,
+		"safe-outputs.create-issue.title-prefix=[bot] @" + shared: provenanceOverridden + main,
+		"tools.github.toolsets=issues@" + shared:                  provenanceEffective,
+		"tools.playwright=(defaults)@" + shared:                   provenanceRemoved + main,
+		"tools.bash=rm@" + shared:                                 provenanceRemoved + main,
+		"tools.edit.mode=patch@" + shared:                         provenanceReplaced + main,
+		"network.allowed=api.example.com@" + shared:               provenanceRemoved + main,
+		"network.allowed=slack.com@" + shared:                     provenanceEffective,
+		"permissions.issues=read@" + shared:                       provenanceRequired,
+		"permissions.pull-requests=read@" + shared:                provenanceRemoved + main,
+		"safe-outputs.add-comment.max=2@" + shared:                provenanceRemoved + main,
+		"safe-outputs.create-issue.labels=automation@" + shared:   provenanceRemoved + main,
+	} {
+		assert.Equal(t, status, statuses[key], "status of %s", key)
+	}
+}
+
+func TestApplyMergeDirectivesWithoutDirectives(t *testing.T) {
+	frontmatter := map[string]any{
+		"mcp-servers":  map[string]any{"slack": map[string]any{"container": "mcp/slack:v2"}},
+		"safe-outputs": map[string]any{"create-issue": map[string]any{"max": 1}},
+	}
+	contribution := parser.ImportContribution{
+		File:        "shared/slack.md",
+		Tools:       "",
+		MCPServers:  `{"slack":{"container":"mcp/slack","allowed":["post_message"]}}`,
+		Network:     `{"allowed":["slack.com"],"firewall":true}`,
+		SafeOutputs: `{"create-issue":{"title-prefix":"[bot] "}}`,
+	}
+	importsResult := &parser.ImportsResult{
+		MergedTools:       "\n",
+		MergedMCPServers:  contribution.MCPServers + "\n",
+		MergedNetwork:     contribution.Network + "\n",
+		MergedSafeOutputs: []string{contribution.SafeOutputs},
+		Contributions:     []parser.ImportContribution{contribution},
+	}
+	expected := *importsResult
+
+	provenance, err := NewCompiler().applyMergeDirectives(frontmatter, importsResult, nil, "daily.md")
+	require.NoError(t, err, "imports should be merged without directives")
+
+	assert.Equal(t, expected, *importsResult, "imports should be left untouched without directives")
+	assert.Equal(t, map[string]any{"create-issue": map[string]any{"max": 1}}, frontmatter["safe-outputs"],
+		"the workflow's safe outputs should be left untouched")
+	assert.NotEmpty(t, provenance.Entries, "provenance should still be recorded")
+}
+
+func TestMergeDirectivesCompile(t *testing.T) {
+	workflowsDir := filepath.Join(t.TempDir(), ".github", "workflows")
+	require.NoError(t, os.MkdirAll(filepath.Join(workflowsDir, "shared"), 0755))
+
+	shared := `---
+tools:
+  github:
+    toolsets: [issues]
+  playwright:
+network:
+  allowed:
+    - api.example.com
+    - slack.com
+permissions:
+  issues: read
+  pull-requests: read
+safe-outputs:
+  create-issue:
+    title-prefix: "[bot] "
+    labels: [automation]
+    max: 5
+  add-comment:
+    max: 2
+---
+Shared instructions.
+`
+	main := `---
+on: issues
+permissions:
+  contents: read
+  issues: read
+  pull-requests: "!remove"
+imports:
+  - shared/base.md
+tools:
+  playwright: "!remove"
+network:
+  allowed:
+    - defaults
+    - "!remove:api.example.com"
+safe-outputs:
+  add-comment: "!remove"
+---
+# Daily
+`
+	require.NoError(t, os.WriteFile(filepath.Join(workflowsDir, "shared", "base.md"), []byte(shared), 0644))
+	mainFile := filepath.Join(workflowsDir, "daily.md")
+	require.NoError(t, os.WriteFile(mainFile, []byte(main), 0644))
+
+	workflowData, err := NewCompiler().ParseWorkflowFile(mainFile)
+	require.NoError(t, err, "workflow with merge directives should compile")
+
+	assert.Contains(t, workflowData.Tools, "github", "imported tools should be merged")
+	assert.NotContains(t, workflowData.Tools, "playwright", "removed tools should not be merged")
+	require.NotNil(t, workflowData.NetworkPermissions)
+	assert.Equal(t, []string{"defaults", "slack.com"}, workflowData.NetworkPermissions.Allowed, "removed domains should not be merged")
+	require.NotNil(t, workflowData.SafeOutputs)
+	require.NotNil(t, workflowData.SafeOutputs.CreateIssues)
+	assert.Equal(t, "[bot] ", workflowData.SafeOutputs.CreateIssues.TitlePrefix, "imported safe outputs should be merged")
+	assert.Nil(t, workflowData.SafeOutputs.AddComments, "removed safe outputs should not be merged")
+	assert.NotContains(t, workflowData.Permissions, "pull-requests", "removed permissions should not be granted")
+}
+
+// TestMergeDirectivesGolden_RepoWorkflows compiles repository workflows that import shared configuration
+// and compares their merged tools, network, permissions and safe outputs against golden files, so that
+// changes to import merging show up as golden diffs.
+//
+// To update golden files:
+//
+//	go test -v ./pkg/workflow -run='^TestMergeDirectivesGolden_' -update
+func TestMergeDirectivesGolden_RepoWorkflows(t *testing.T) {
+	workflowsDir := filepath.Join("..", "..", ".github", "workflows")
+	goldenDir := filepath.Join("testdata", "merge_golden")
+
+	for _, name := range []string{
+		"cloclo",
+		"copilot-pr-merged-report",
+		"github-mcp-structural-analysis",
+		"prompt-clustering-analysis",
+		"stale-repo-identifier",
+		"video-analyzer",
+	} {
+		t.Run(name, func(t *testing.T) {
+			compiler := NewCompiler(WithNoEmit(true), WithSkipValidation(true), WithWorkflowIdentifier(name))
+			workflowData, err := compiler.ParseWorkflowFile(filepath.Join(workflowsDir, name+".md"))
+			require.NoError(t, err, "workflow should compile")
+
+			encoded, err := json.Marshal(map[string]any{
+				"tools":        workflowData.Tools,
+				"network":      workflowData.NetworkPermissions,
+				"permissions":  workflowData.Permissions,
+				"safe-outputs": workflowData.SafeOutputs,
+			})
+			require.NoError(t, err, "merged configuration should be encoded")
+			var decoded any
+			require.NoError(t, json.Unmarshal(encoded, &decoded))
+			merged, err := json.MarshalIndent(pruneEmptyGoldenValues(decoded), "", "  ")
+			require.NoError(t, err, "merged configuration should be encoded")
+			merged = append(merged, '\n')
+
+			goldenPath := filepath.Join(goldenDir, name+".golden")
+			if isUpdateMode() {
+				require.NoError(t, os.MkdirAll(goldenDir, 0o755))
+				require.NoError(t, os.WriteFile(goldenPath, merged, 0o644))
+				return
+			}
+			expected, err := os.ReadFile(goldenPath)
+			require.NoError(t, err, "golden file not found for %s (run with -update to create)", name)
+			require.Equal(t, string(expected), string(merged), "merged configuration differs from golden for %s", name) //nolint:testifylint // golden test requires exact string comparison
+		})
+	}
+}
+
+// pruneEmptyGoldenValues drops null, false, zero and empty values from decoded JSON so that golden files
+// only list the settings that are actually configured
+func pruneEmptyGoldenValues(value any) any {
+	switch typed := value.(type) {
+	case map[string]any:
+		for key, child := range typed {
+			pruned := pruneEmptyGoldenValues(child)
+			if isEmptyGoldenValue(pruned) {
+				delete(typed, key)
+				continue
+			}
+			typed[key] = pruned
+		}
+		return typed
+	case []any:
+		for i, child := range typed {
+			typed[i] = pruneEmptyGoldenValues(child)
+		}
+		return typed
+	default:
+		return value
+	}
+}
+
+// isEmptyGoldenValue reports whether a decoded JSON value is null, false, zero or empty
+func isEmptyGoldenValue(value any) bool {
+	switch typed := value.(type) {
+	case nil:
+		return true
+	case bool:
+		return !typed
+	case float64:
+		return typed == 0
+	case string:
+		return typed == ""
+	case map[string]any:
+		return len(typed) == 0
+	case []any:
+		return len(typed) == 0
+	default:
+		return false
+	}
+}
@@ -0,0 +1,271 @@
+package workflow
+
+import (
+	"encoding/json"
+	"maps"
+	"strings"
+
+	"github.com/github/gh-aw/pkg/parser"
+)
+
+// settingOwner records a file that set a setting while the workflow and its imports were merged
+type settingOwner struct {
+	value    string // Formatted value, as produced by the merge
+	file     string // Display path of the file
+	listItem bool   // Whether the value is an entry of a list
+	seq      int    // Merge order of the step that set the value
+}
+
+// own records file as the owner of the leaf settings of value under path
+func (p *MergeProvenance) own(path string, value any, file string) {
+	p.recordOwners(path, value, file, nil, nil)
+}
+
+// recordMergeStep records the settings of after that are not in before as set by file, and after as
+// the merged state of the section at path. When declared is not nil, only settings the file declares
+// itself (or that lie under a setting it declares, such as the defaults of "create-issue:") are
+// recorded, so that compiler defaults filled in by the merge step are not attributed to the file.
+func (p *MergeProvenance) recordMergeStep(path string, before, after any, file string, declared map[string]bool) {
+	if p.merged == nil {
+		p.merged = make(map[string]map[string]bool)
+	}
+	p.merged[path] = settingKeys(path, after)
+	p.recordOwners(path, after, file, settingKeys(path, before), declared)
+}
+
+// recordOwners records file as the owner of the leaf settings of value under path that are not in
+// previous and are declared (all settings when declared is nil)
+func (p *MergeProvenance) recordOwners(path string, value any, file string, previous, declared map[string]bool) {
+	if p.owners == nil {
+		p.owners = make(map[string][]settingOwner)
+	}
+	p.steps++
+	walkSettingLeaves(path, value, func(setting, formatted string, listItem bool) {
+		if previous[setting+"\x00"+formatted] || (declared != nil && !declaresSetting(declared, setting)) {
+			return
+		}
+		p.owners[setting] = append(p.owners[setting], settingOwner{value: formatted, file: file, listItem: listItem, seq: p.steps})
+	})
+}
+
+// settingKeys returns the "setting\x00value" keys of the leaf settings of value under path
+func settingKeys(path string, value any) map[string]bool {
+	keys := make(map[string]bool)
+	walkSettings(path, value, func(setting, formatted string) {
+		keys[setting+"\x00"+formatted] = true
+	})
+	return keys
+}
+
+// declaredSettings returns the paths of the leaf settings of value under path
+func declaredSettings(path string, value any) map[string]bool {
+	declared := make(map[string]bool)
+	walkSettings(path, value, func(setting, _ string) {
+		declared[setting] = true
+	})
+	return declared
+}
+
+// declaresSetting reports whether setting or one of its parents is declared
+func declaresSetting(declared map[string]bool, setting string) bool {
+	for path := setting; path != ""; path = parentSetting(path) {
+		if declared[path] {
+			return true
+		}
+	}
+	return false
+}
+
+// parentSetting returns the parent path of a dotted setting path, or "" for a section
+func parentSetting(setting string) string {
+	if idx := strings.LastIndex(setting, "."); idx >= 0 {
+		return setting[:idx]
+	}
+	return ""
+}
+
+// ownerPath returns the path under which the owners of a setting are recorded. Tools and mcp-servers
+// are merged into a single tools map, so both are recorded under tools.
+func ownerPath(setting string) string {
+	if rest, ok := strings.CutPrefix(setting, "mcp-servers."); ok {
+		return "tools." + rest
+	}
+	return setting
+}
+
+// latestOwner returns the owner of setting that was recorded last, whose value was kept by the merge
+// and that satisfies match, or nil
+func (p *MergeProvenance) latestOwner(setting string, match func(settingOwner) bool) *settingOwner {
+	path := ownerPath(setting)
+	section, _, _ := strings.Cut(path, ".")
+	merged, recorded := p.merged[section]
+	var latest *settingOwner
+	for i, owner := range p.owners[path] {
+		if recorded && !merged[path+"\x00"+owner.value] {
+			continue
+		}
+		if match(owner) && (latest == nil || owner.seq >= latest.seq) {
+			latest = &p.owners[path][i]
+		}
+	}
+	return latest
+}
+
+// latestOwnerUnder returns the owner recorded last for setting or any setting below it, ignoring
+// the settings of exclude, or nil
+func (p *MergeProvenance) latestOwnerUnder(setting, exclude string) *settingOwner {
+	path := ownerPath(setting)
+	var latest *settingOwner
+	for ownedPath, owners := range p.owners {
+		if ownedPath != path && !strings.HasPrefix(ownedPath, path+".") {
+			continue
+		}
+		for i, owner := range owners {
+			if owner.file != exclude && (latest == nil || owner.seq > latest.seq) {
+				latest = &p.owners[ownedPath][i]
+			}
+		}
+	}
+	return latest
+}
+
+// mergedStatus returns the status of a setting contributed by the workflow or an import: effective
+// when the merge kept its value or the file was the last to set it, overridden by the file that set
+// it last otherwise, and not merged when the merge ignored it.
+func (p *MergeProvenance) mergedStatus(entry MergeProvenanceEntry) string {
+	section, _, _ := strings.Cut(ownerPath(entry.Setting), ".")
+	if p.merged[section][ownerPath(entry.Setting)+"\x00"+entry.Value] {
+		return provenanceEffective
+	}
+	if owner := p.latestOwnerUnder(entry.Setting, ""); owner != nil {
+		switch {
+		case owner.file == entry.Source:
+			return provenanceEffective
+		case owner.listItem && entry.Source != p.Workflow:
+			// Lists are combined, so a missing imported entry was not merged rather than overridden
+			return provenanceNotMerged
+		}
+		return provenanceOverridden + owner.file
+	}
+	for path := parentSetting(entry.Setting); strings.Contains(path, "."); path = parentSetting(path) {
+		if owner := p.latestOwnerUnder(path, entry.Source); owner != nil {
+			return provenanceOverridden + owner.file
+		}
+	}
+	if entry.Source == p.Workflow {
+		return provenanceEffective
+	}
+	return provenanceNotMerged
+}
+
+// recordMergedSettings replays the merge of the tools, mcp-servers, network and safe-outputs of the
+// workflow and its imports with the compiler's merge functions, one file at a time, and records the
+// file that set each merged value. Merge errors are reported when the configuration is merged for
+// compilation, so they only stop the recording here.
+func (c *Compiler) recordMergedSettings(frontmatter map[string]any, contributions []parser.ImportContribution, provenance *MergeProvenance) {
+	workflow := provenance.Workflow
+	// Parsing rewrites some settings in place (e.g., "expires: 2d"), so replay on a copy
+	frontmatter = cloneSetting(frontmatter).(map[string]any)
+
+	topTools, _ := cloneSetting(frontmatter["tools"]).(map[string]any)
+	mcpServers, _ := cloneSetting(frontmatter["mcp-servers"]).(map[string]any)
+	combineTools := func(servers map[string]any) map[string]any {
+		combined := make(map[string]any, len(topTools)+len(servers))
+		maps.Copy(combined, cloneSetting(topTools).(map[string]any))
+		maps.Copy(combined, cloneSetting(servers).(map[string]any))
+		return combined
+	}
+	tools := combineTools(mcpServers)
+	provenance.recordMergeStep("tools", nil, tools, workflow, nil)
+	for _, contribution := range contributions {
+		if contribution.MCPServers == "" {
+			continue
+		}
+		merged, err := c.MergeMCPServers(mcpServers, contribution.MCPServers)
+		if err != nil {
+			mergeDirectivesLog.Printf("Stopped recording mcp-servers provenance: %v", err)
+			break
+		}
+		mcpServers = merged
+		next := combineTools(mcpServers)
+		provenance.recordMergeStep("tools", tools, next, provenanceDisplayPath(contribution.File), nil)
+		tools = next
+	}
+	for _, contribution := range contributions {
+		if contribution.Tools == "" {
+			continue
+		}
+		merged, err := c.MergeTools(cloneSetting(tools).(map[string]any), contribution.Tools)
+		if err != nil {
+			mergeDirectivesLog.Printf("Stopped recording tools provenance: %v", err)
+			break
+		}
+		provenance.recordMergeStep("tools", tools, merged, provenanceDisplayPath(contribution.File), nil)
+		tools = merged
+	}
+
+	// Network and safe outputs are merged in their parsed form, which may differ from the frontmatter
+	// (e.g., "expires: 2d" is stored in hours), so only settings declared by each file are recorded
+	network := c.extractNetworkPermissions(frontmatter)
+	provenance.recordMergeStep("network", nil, normalizeSetting(network), workflow, declaredSettings("network", frontmatter["network"]))
+	for _, contribution := range contributions {
+		if contribution.Network == "" {
+			continue
+		}
+		merged, err := c.MergeNetworkPermissions(network, contribution.Network)
+		if err != nil {
+			mergeDirectivesLog.Printf("Stopped recording network provenance: %v", err)
+			break
+		}
+		provenance.recordMergeStep("network", normalizeSetting(network), normalizeSetting(merged),
+			provenanceDisplayPath(contribution.File), declaredSettings("network", decodeSection(contribution.Network)))
+		network = merged
+	}
+
+	safeOutputs := c.extractSafeOutputsConfig(frontmatter)
+	provenance.recordMergeStep("safe-outputs", nil, normalizeSetting(safeOutputs), workflow, declaredSettings("safe-outputs", frontmatter["safe-outputs"]))
+	for _, contribution := range contributions {
+		if contribution.SafeOutputs == "" {
+			continue
+		}
+		// Merging updates the configuration in place, so normalize it first
+		before := normalizeSetting(safeOutputs)
+		merged, err := c.MergeSafeOutputs(safeOutputs, []string{contribution.SafeOutputs})
+		if err != nil {
+			mergeDirectivesLog.Printf("Stopped recording safe-outputs provenance: %v", err)
+			break
+		}
+		provenance.recordMergeStep("safe-outputs", before, normalizeSetting(merged),
+			provenanceDisplayPath(contribution.File), declaredSettings("safe-outputs", decodeSection(contribution.SafeOutputs)))
+		safeOutputs = merged
+	}
+}
+
+// cloneSetting returns a deep copy of a frontmatter value
+func cloneSetting(value any) any {
+	switch typed := value.(type) {
+	case map[string]any:
+		cloned := make(map[string]any, len(typed))
+		for key, child := range typed {
+			cloned[key] = cloneSetting(child)
+		}
+		return cloned
+	case []any:
+		cloned := make([]any, len(typed))
+		for i, child := range typed {
+			cloned[i] = cloneSetting(child)
+		}
+		return cloned
+	default:
+		return value
+	}
+}
+
+// decodeSection decodes the JSON encoding of an imported frontmatter section
+func decodeSection(sectionJSON string) any {
+	var section any
+	if err := json.Unmarshal([]byte(sectionJSON), &section); err != nil {
+		mergeDirectivesLog.Printf("Failed to decode imported section: %v", err)
+	}
+	return section
+}
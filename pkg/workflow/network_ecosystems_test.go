@@ -0,0 +1,149 @@
+//go:build !integration
+
+package workflow
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/github/gh-aw/pkg/stringutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCustomEcosystemsFromImports(t *testing.T) {
+	workflowsDir := filepath.Join(t.TempDir(), ".github", "workflows")
+	require.NoError(t, os.MkdirAll(filepath.Join(workflowsDir, "shared"), 0755))
+
+	shared := `---
+network:
+  ecosystems:
+    corp-artifactory:
+      - artifactory.example.com
+      - "*.mirror.example.com"
+---
+Internal mirrors.
+`
+	main := `---
+on: issues
+engine: copilot
+permissions:
+  contents: read
+imports:
+  - shared/ecosystems.md
+network:
+  allowed:
+    - defaults
+    - corp-artifactory
+---
+# Build
+`
+	require.NoError(t, os.WriteFile(filepath.Join(workflowsDir, "shared", "ecosystems.md"), []byte(shared), 0644))
+	mainFile := filepath.Join(workflowsDir, "build.md")
+	require.NoError(t, os.WriteFile(mainFile, []byte(main), 0644))
+
+	compiler := NewCompiler()
+	workflowData, err := compiler.ParseWorkflowFile(mainFile)
+	require.NoError(t, err, "workflow should be parsed")
+	require.NotNil(t, workflowData.NetworkPermissions, "network permissions should be set")
+	assert.Equal(t, []string{"artifactory.example.com", "*.mirror.example.com"}, workflowData.NetworkPermissions.Ecosystems["corp-artifactory"], "imported ecosystem should be merged")
+
+	allowed := GetAllowedDomains(workflowData.NetworkPermissions)
+	assert.Contains(t, allowed, "artifactory.example.com", "custom ecosystem should be expanded")
+	assert.Contains(t, allowed, "*.mirror.example.com", "custom ecosystem wildcard should be expanded")
+	assert.NotContains(t, allowed, "corp-artifactory", "custom ecosystem identifier should not be used as a domain")
+
+	blockedNetwork := &NetworkPermissions{Blocked: []string{"corp-artifactory"}, Ecosystems: workflowData.NetworkPermissions.Ecosystems}
+	assert.Equal(t, []string{"*.mirror.example.com", "artifactory.example.com"}, GetBlockedDomains(blockedNetwork), "custom ecosystems should be expanded in blocked")
+
+	require.NoError(t, compiler.CompileWorkflow(mainFile), "workflow should compile")
+	lockContent, err := os.ReadFile(stringutil.MarkdownToLockFile(mainFile))
+	require.NoError(t, err, "lock file should be written")
+	assert.Contains(t, string(lockContent), "artifactory.example.com", "lock file should allow the custom ecosystem domains")
+
+	explanation := explainWorkflowData(workflowData, mainFile)
+	var origins []string
+	for _, setting := range explanation.Settings {
+		if setting.Section == "network" && setting.Value == "artifactory.example.com" {
+			origins = append(origins, setting.Origin+":"+setting.File)
+		}
+	}
+	assert.Equal(t, []string{ExplainOriginImport + ":.github/workflows/shared/ecosystems.md"}, origins, "explain should attribute the ecosystem to its import")
+}
+
+func TestMergeNetworkPermissionsEcosystems(t *testing.T) {
+	compiler := NewCompiler()
+
+	tests := []struct {
+		name        string
+		top         *NetworkPermissions
+		imported    string
+		expected    *NetworkPermissions
+		expectError string
+	}{
+		{
+			name:     "ecosystem-only imports keep the default network",
+			imported: `{"ecosystems":{"corp":["a.example.com"]}}`,
+			expected: nil,
+		},
+		{
+			name: "workflow definition overrides imports",
+			top: &NetworkPermissions{
+				Allowed:    []string{"corp"},
+				Ecosystems: map[string][]string{"corp": {"b.example.com"}},
+			},
+			imported: `{"ecosystems":{"corp":["a.example.com"]}}`,
+			expected: &NetworkPermissions{
+				Allowed:    []string{"corp"},
+				Ecosystems: map[string][]string{"corp": {"b.example.com"}},
+			},
+		},
+		{
+			name:        "conflicting imports are rejected",
+			top:         &NetworkPermissions{Allowed: []string{"corp"}},
+			imported:    "{\"ecosystems\":{\"corp\":[\"a.example.com\"]}}\n{\"ecosystems\":{\"corp\":[\"b.example.com\"]}}",
+			expectError: "network ecosystem 'corp' is defined differently by multiple imports",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := compiler.MergeNetworkPermissions(tt.top, tt.imported)
+			if tt.expectError != "" {
+				require.Error(t, err, "merge should fail")
+				assert.Contains(t, err.Error(), tt.expectError, "error should explain the conflict")
+				return
+			}
+			require.NoError(t, err, "merge should succeed")
+			assert.Equal(t, tt.expected, result, "merged network should match")
+		})
+	}
+}
+
+func TestValidateCustomEcosystem(t *testing.T) {
+	tests := []struct {
+		name        string
+		ecosystem   string
+		domains     []string
+		expectError string
+	}{
+		{name: "valid domains", ecosystem: "corp-artifactory", domains: []string{"artifactory.example.com", "*.mirror.example.com"}},
+		{name: "shadows built-in", ecosystem: "python", domains: []string{"pypi.example.com"}, expectError: "shadows a built-in ecosystem"},
+		{name: "no domains", ecosystem: "corp", domains: nil, expectError: "has no domains"},
+		{name: "wildcard only", ecosystem: "corp", domains: []string{"*"}, expectError: "wildcard-only domain"},
+		{name: "top-level wildcard", ecosystem: "corp", domains: []string{"*.com"}, expectError: "must include a registrable domain"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateCustomEcosystem(tt.ecosystem, tt.domains)
+			if tt.expectError == "" {
+				assert.NoError(t, err, "ecosystem should be valid")
+				return
+			}
+			require.Error(t, err, "ecosystem should be rejected")
+			assert.Contains(t, err.Error(), tt.expectError, "error should explain the problem")
+		})
+	}
+}
@@ -0,0 +1,236 @@
+package workflow
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/github/gh-aw/pkg/constants"
+	"github.com/github/gh-aw/pkg/logger"
+)
+
+var networkSuggestLog = logger.New("workflow:network_suggest")
+
+// ObservedDomain is a domain requested by workflow runs, with the number of allowed and blocked requests
+type ObservedDomain struct {
+	Domain  string
+	Allowed int
+	Blocked int
+}
+
+// NetworkChange is an entry of the suggested network.allowed list and how it differs from the declared list
+type NetworkChange struct {
+	Entry   string `json:"entry" console:"header:Entry"`
+	Change  string `json:"change" console:"header:Change"`
+	Domains string `json:"domains,omitempty" console:"header:Domains"`
+	Reason  string `json:"reason" console:"header:Reason"`
+}
+
+// NetworkSuggestion compares the network.allowed list of a workflow with the domains its runs requested
+type NetworkSuggestion struct {
+	Workflow  string          `json:"workflow"`
+	Runs      int             `json:"runs"`
+	Declared  []string        `json:"declared"`           // Effective entries, including those of imports
+	Imported  []string        `json:"imported,omitempty"` // Declared entries contributed by imports
+	Suggested []string        `json:"suggested"`
+	Changes   []NetworkChange `json:"changes"`
+}
+
+// HasChanges returns true if the suggested allow-list adds or removes entries
+func (s *NetworkSuggestion) HasChanges() bool {
+	return slices.ContainsFunc(s.Changes, func(change NetworkChange) bool { return change.Change == "add" || change.Change == "remove" })
+}
+
+// FrontmatterAllowed returns the suggested entries that belong in the workflow's own network.allowed,
+// leaving out the entries contributed by imports
+func (s *NetworkSuggestion) FrontmatterAllowed() []string {
+	allowed := []string{}
+	for _, entry := range s.Suggested {
+		if !slices.Contains(s.Imported, entry) {
+			allowed = append(allowed, entry)
+		}
+	}
+	return allowed
+}
+
+// SuggestNetworkAllowed proposes a minimal network.allowed list for a workflow from the domains
+// requested by its runs. Domains that are allowed automatically (engine defaults, HTTP MCP servers,
+// Playwright and runtimes) are ignored. Other domains are covered by a declared entry when possible,
+// then by the custom or built-in ecosystem that contains them, and otherwise listed literally. Declared
+// entries that cover no requested domain are removed, except entries contributed by imports, which
+// cannot be removed from the workflow. Entries whose domains were only ever blocked are added when
+// includeBlocked is set, and reported without being added otherwise.
+func SuggestNetworkAllowed(workflowData *WorkflowData, markdownPath string, observed []ObservedDomain, runs int, includeBlocked bool) *NetworkSuggestion {
+	suggestion := &NetworkSuggestion{
+		Workflow: provenanceDisplayPath(markdownPath),
+		Runs:     runs,
+		Declared: []string{"defaults"}, // Default allow-list when network is not set
+	}
+	if workflowData.NetworkPermissions != nil {
+		suggestion.Declared = append([]string{}, workflowData.NetworkPermissions.Allowed...)
+		suggestion.Imported = importedNetworkAllowed(workflowData, suggestion.Declared)
+	}
+
+	var engineID constants.EngineName
+	if workflowData.EngineConfig != nil {
+		engineID = constants.EngineName(workflowData.EngineConfig.ID)
+	}
+	var automatic []string
+	if merged := mergeDomainsWithNetworkToolsAndRuntimes(engineDefaultDomains[engineID], nil, workflowData.Tools, workflowData.Runtimes); merged != "" {
+		automatic = strings.Split(merged, ",")
+	}
+
+	domainsByEntry := make(map[string][]string)
+	allowedByEntry := make(map[string]int)
+	blockedByEntry := make(map[string]int)
+	for _, domain := range observed {
+		if slices.ContainsFunc(automatic, func(pattern string) bool { return matchesDomain(domain.Domain, pattern) }) {
+			networkSuggestLog.Printf("Domain %s is allowed automatically", domain.Domain)
+			continue
+		}
+		entry := declaredEntryFor(workflowData.NetworkPermissions, suggestion.Declared, domain.Domain)
+		if entry == "" {
+			entry = customEcosystemFor(workflowData.NetworkPermissions, domain.Domain)
+		}
+		if entry == "" {
+			entry = GetDomainEcosystem(domain.Domain)
+		}
+		if entry == "" {
+			entry = domain.Domain
+		}
+		domainsByEntry[entry] = append(domainsByEntry[entry], domain.Domain)
+		allowedByEntry[entry] += domain.Allowed
+		blockedByEntry[entry] += domain.Blocked
+	}
+
+	var added []string
+	for entry := range domainsByEntry {
+		if !slices.Contains(suggestion.Declared, entry) {
+			added = append(added, entry)
+		}
+	}
+	sort.Strings(added)
+
+	suggestion.Suggested = []string{}
+	for _, entry := range suggestion.Declared {
+		domains := domainsByEntry[entry]
+		imported := slices.Contains(suggestion.Imported, entry)
+		switch {
+		case len(domains) == 0 && imported:
+			// Imported entries are kept, since only the import can remove them
+			suggestion.Suggested = append(suggestion.Suggested, entry)
+			suggestion.Changes = append(suggestion.Changes, NetworkChange{
+				Entry:  entry,
+				Change: "keep",
+				Reason: fmt.Sprintf("not requested in %d runs, declared by an import", runs),
+			})
+			continue
+		case len(domains) == 0:
+			suggestion.Changes = append(suggestion.Changes, NetworkChange{
+				Entry:  entry,
+				Change: "remove",
+				Reason: fmt.Sprintf("not requested in %d runs", runs),
+			})
+			continue
+		}
+		reason := "requested"
+		if imported {
+			reason = "requested, declared by an import"
+		}
+		suggestion.Suggested = append(suggestion.Suggested, entry)
+		suggestion.Changes = append(suggestion.Changes, NetworkChange{
+			Entry:   entry,
+			Change:  "keep",
+			Domains: strings.Join(domains, ", "),
+			Reason:  reason,
+		})
+	}
+	for _, entry := range added {
+		reason := "requested"
+		if blocked := blockedByEntry[entry]; blocked > 0 {
+			reason = fmt.Sprintf("%d requests blocked", blocked)
+		}
+		if allowedByEntry[entry] == 0 && !includeBlocked {
+			suggestion.Changes = append(suggestion.Changes, NetworkChange{
+				Entry:   entry,
+				Change:  "blocked",
+				Domains: strings.Join(domainsByEntry[entry], ", "),
+				Reason:  reason + ", not added without --include-blocked",
+			})
+			continue
+		}
+		suggestion.Suggested = append(suggestion.Suggested, entry)
+		suggestion.Changes = append(suggestion.Changes, NetworkChange{
+			Entry:   entry,
+			Change:  "add",
+			Domains: strings.Join(domainsByEntry[entry], ", "),
+			Reason:  reason,
+		})
+	}
+
+	networkSuggestLog.Printf("Suggested %d network entries for %s from %d domains", len(suggestion.Suggested), suggestion.Workflow, len(observed))
+	return suggestion
+}
+
+// importedNetworkAllowed returns the declared entries that imports contributed and the workflow's
+// own frontmatter does not declare
+func importedNetworkAllowed(workflowData *WorkflowData, declared []string) []string {
+	provenance := workflowData.MergeProvenance
+	if provenance == nil {
+		return nil
+	}
+	own := make(map[string]bool)
+	fromImports := make(map[string]bool)
+	for _, entry := range provenance.Entries {
+		if entry.Setting != "network.allowed" {
+			continue
+		}
+		if entry.Source == provenance.Workflow {
+			own[entry.Value] = true
+		} else {
+			fromImports[entry.Value] = true
+		}
+	}
+	var imported []string
+	for _, entry := range declared {
+		if fromImports[entry] && !own[entry] {
+			imported = append(imported, entry)
+		}
+	}
+	return imported
+}
+
+// declaredEntryFor returns the declared network entry (ecosystem identifier or domain) that
+// covers a domain, or an empty string if none does
+func declaredEntryFor(network *NetworkPermissions, declared []string, domain string) string {
+	for _, entry := range declared {
+		patterns := resolveEcosystemDomains(network, entry)
+		if len(patterns) == 0 {
+			patterns = []string{entry}
+		}
+		if slices.ContainsFunc(patterns, func(pattern string) bool { return matchesDomain(domain, pattern) }) {
+			return entry
+		}
+	}
+	return ""
+}
+
+// customEcosystemFor returns the custom ecosystem of network.ecosystems that contains a domain,
+// or an empty string if none does
+func customEcosystemFor(network *NetworkPermissions, domain string) string {
+	if network == nil {
+		return ""
+	}
+	names := make([]string, 0, len(network.Ecosystems))
+	for name := range network.Ecosystems {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if slices.ContainsFunc(network.Ecosystems[name], func(pattern string) bool { return matchesDomain(domain, pattern) }) {
+			return name
+		}
+	}
+	return ""
+}
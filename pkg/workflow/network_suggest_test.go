@@ -0,0 +1,98 @@
+//go:build !integration
+
+package workflow
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSuggestNetworkAllowed(t *testing.T) {
+	workflowData := &WorkflowData{
+		EngineConfig: &EngineConfig{ID: "copilot"},
+		NetworkPermissions: &NetworkPermissions{
+			Allowed:           []string{"python", "unused.example.com", "*.internal.example.com"},
+			ExplicitlyDefined: true,
+		},
+	}
+	observed := []ObservedDomain{
+		{Domain: "api.githubcopilot.com", Allowed: 12},    // Engine default
+		{Domain: "pypi.org", Allowed: 3},                  // Declared ecosystem
+		{Domain: "registry.yarnpkg.com", Blocked: 2},      // Blocked, in the node ecosystem
+		{Domain: "docs.internal.example.com", Allowed: 1}, // Declared wildcard
+		{Domain: "api.custom.dev", Blocked: 1},            // Blocked, no ecosystem
+	}
+
+	suggestion := SuggestNetworkAllowed(workflowData, ".github/workflows/test.md", observed, 5, true)
+
+	assert.Equal(t, []string{"python", "unused.example.com", "*.internal.example.com"}, suggestion.Declared, "declared entries should be reported")
+	assert.Equal(t, []string{"python", "*.internal.example.com", "api.custom.dev", "node"}, suggestion.Suggested, "used entries should be kept and missing entries added")
+	assert.True(t, suggestion.HasChanges(), "suggestion should have changes")
+
+	changes := make(map[string]NetworkChange)
+	for _, change := range suggestion.Changes {
+		changes[change.Entry] = change
+	}
+	assert.Equal(t, "keep", changes["python"].Change, "used ecosystem should be kept")
+	assert.Equal(t, "pypi.org", changes["python"].Domains, "ecosystem should list the observed domains")
+	assert.Equal(t, "remove", changes["unused.example.com"].Change, "unused domain should be removed")
+	assert.Equal(t, "not requested in 5 runs", changes["unused.example.com"].Reason, "removal should mention the runs")
+	assert.Equal(t, "add", changes["node"].Change, "blocked ecosystem domain should be added as an ecosystem")
+	assert.Equal(t, "2 requests blocked", changes["node"].Reason, "addition should count blocked requests")
+	assert.Equal(t, "add", changes["api.custom.dev"].Change, "blocked domain without ecosystem should be added literally")
+	assert.NotContains(t, changes, "api.githubcopilot.com", "engine default domains should be ignored")
+}
+
+func TestSuggestNetworkAllowedWithoutNetwork(t *testing.T) {
+	observed := []ObservedDomain{{Domain: "crl3.digicert.com", Allowed: 4}}
+
+	suggestion := SuggestNetworkAllowed(&WorkflowData{}, ".github/workflows/test.md", observed, 2, false)
+
+	assert.Equal(t, []string{"defaults"}, suggestion.Declared, "missing network should default to the defaults ecosystem")
+	assert.Equal(t, []string{"defaults"}, suggestion.Suggested, "defaults should be kept when its domains are used")
+	assert.False(t, suggestion.HasChanges(), "suggestion should have no changes")
+}
+
+func TestSuggestNetworkAllowedBlockedAndImported(t *testing.T) {
+	workflowData := &WorkflowData{
+		EngineConfig: &EngineConfig{ID: "copilot"},
+		NetworkPermissions: &NetworkPermissions{
+			Allowed:           []string{"python", "slack.com", "unused.example.com"},
+			ExplicitlyDefined: true,
+		},
+		MergeProvenance: &MergeProvenance{
+			Workflow: ".github/workflows/test.md",
+			Entries: []MergeProvenanceEntry{
+				{Setting: "network.allowed", Value: "python", Source: ".github/workflows/test.md", Status: provenanceEffective},
+				{Setting: "network.allowed", Value: "slack.com", Source: ".github/workflows/shared/slack.md", Status: provenanceEffective},
+				{Setting: "network.allowed", Value: "unused.example.com", Source: ".github/workflows/shared/slack.md", Status: provenanceEffective},
+			},
+		},
+	}
+	observed := []ObservedDomain{
+		{Domain: "pypi.org", Allowed: 3},             // Declared ecosystem
+		{Domain: "slack.com", Allowed: 2},            // Declared by an import
+		{Domain: "api.custom.dev", Blocked: 4},       // Blocked only
+		{Domain: "registry.yarnpkg.com", Blocked: 1}, // Blocked only, in the node ecosystem
+	}
+
+	suggestion := SuggestNetworkAllowed(workflowData, ".github/workflows/test.md", observed, 3, false)
+
+	assert.Equal(t, []string{"slack.com", "unused.example.com"}, suggestion.Imported, "entries declared by imports should be reported")
+	assert.Equal(t, []string{"python", "slack.com", "unused.example.com"}, suggestion.Suggested, "blocked domains should not be added and imported entries should be kept")
+	assert.Equal(t, []string{"python"}, suggestion.FrontmatterAllowed(), "imported entries should not be written to the frontmatter")
+	assert.False(t, suggestion.HasChanges(), "blocked domains alone should not change the allow-list")
+
+	changes := make(map[string]NetworkChange)
+	for _, change := range suggestion.Changes {
+		changes[change.Entry] = change
+	}
+	assert.Equal(t, "blocked", changes["api.custom.dev"].Change, "blocked domain should only be reported")
+	assert.Contains(t, changes["api.custom.dev"].Reason, "--include-blocked", "blocked domain should mention the opt-in")
+	assert.Equal(t, "keep", changes["unused.example.com"].Change, "unused imported entry should be kept")
+
+	suggestion = SuggestNetworkAllowed(workflowData, ".github/workflows/test.md", observed, 3, true)
+	assert.Equal(t, []string{"python", "api.custom.dev", "node"}, suggestion.FrontmatterAllowed(), "blocked domains should be added with includeBlocked")
+	assert.True(t, suggestion.HasChanges(), "added blocked domains should be changes")
+}
@@ -124,17 +124,19 @@ func (c *Compiler) buildConclusionJob(data *WorkflowData, mainJobName string, sa
 	var agentFailureEnvVars []string
 	agentFailureEnvVars = append(agentFailureEnvVars, buildWorkflowMetadataEnvVarsWithTrackerID(data.Name, data.Source, data.TrackerID)...)
 	agentFailureEnvVars = append(agentFailureEnvVars, "          GH_AW_RUN_URL: ${{ github.server_url }}/${{ github.repository }}/actions/runs/${{ github.run_id }}\n")
-	agentFailureEnvVars = append(agentFailureEnvVars, fmt.Sprintf("          GH_AW_AGENT_CONCLUSION: ${{ needs.%s.result }}\n", mainJobName))
+	// A failed agent stage skips the agent job, so it is reported as an agent failure
+	agentFailureEnvVars = append(agentFailureEnvVars, fmt.Sprintf("          GH_AW_AGENT_CONCLUSION: %s\n", agentConclusionExpression(data, mainJobName)))
 	agentFailureEnvVars = append(agentFailureEnvVars, fmt.Sprintf("          GH_AW_WORKFLOW_ID: %q\n", data.WorkflowID))
 
 	// Only add secret_verification_result if the engine adds the validate-secret step
 	// The validate-secret step is only added by engines that include it in GetInstallationSteps()
-	engine, err := c.getAgenticEngine(data.AI)
+	// Agent stages validate the secrets of their own engines
+	secretVerification, err := c.secretVerificationExpression(data, mainJobName)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get agentic engine: %w", err)
+		return nil, err
 	}
-	if EngineHasValidateSecretStep(engine, data) {
-		agentFailureEnvVars = append(agentFailureEnvVars, fmt.Sprintf("          GH_AW_SECRET_VERIFICATION_RESULT: ${{ needs.%s.outputs.secret_verification_result }}\n", mainJobName))
+	if secretVerification != "" {
+		agentFailureEnvVars = append(agentFailureEnvVars, fmt.Sprintf("          GH_AW_SECRET_VERIFICATION_RESULT: %s\n", secretVerification))
 	}
 
 	// Add checkout_pr_success to detect PR checkout failures (e.g., PR merged and branch deleted)
@@ -332,10 +334,14 @@ func (c *Compiler) buildConclusionJob(data *WorkflowData, mainJobName string, sa
 	alwaysFunc := BuildFunctionCall("always")
 
 	// Check that agent job was activated (not skipped)
-	agentNotSkipped := BuildNotEquals(
+	var agentNotSkipped ConditionNode = BuildNotEquals(
 		BuildPropertyAccess(fmt.Sprintf("needs.%s.result", constants.AgentJobName)),
 		BuildStringLiteral("skipped"),
 	)
+	// The agent job is also skipped when an agent stage failed, which must still be reported
+	if stageFailed := agentStageFailedCondition(data); stageFailed != nil {
+		agentNotSkipped = BuildOr(agentNotSkipped, stageFailed)
+	}
 
 	// Check if add_comment job exists in the safe output jobs
 	hasAddCommentJob := slices.Contains(safeOutputJobNames, "add_comment")
@@ -359,6 +365,7 @@ func (c *Compiler) buildConclusionJob(data *WorkflowData, mainJobName string, sa
 
 	// Build dependencies - this job depends on all safe output jobs to ensure it runs last
 	needs := []string{mainJobName, string(constants.ActivationJobName)}
+	needs = append(needs, agentStageJobNames(data)...)
 	needs = append(needs, safeOutputJobNames...)
 
 	// Add detection job to dependencies if threat detection is enabled
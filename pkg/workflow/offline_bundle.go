@@ -0,0 +1,157 @@
+// This file provides offline compilation from a bundle for air-gapped environments.
+//
+// Compilation can reach the network to resolve action SHAs, download remote imports,
+// and (with --validate) check npm/PyPI packages and container images. An offline bundle
+// is a snapshot of everything a set of workflows needs, laid out like a repository:
+//
+//	manifest.json                       - bundle metadata (BundleManifest)
+//	.github/aw/actions-lock.json        - action pins
+//	.github/aw/actions-lock.ghes.json   - GHES mirror pins (optional)
+//	.github/aw/imports/...              - remote import cache
+//
+// When compiling offline, the compiler reads action pins and imports only from the
+// bundle, checks packages and container images against the manifest, and fails on
+// anything the bundle does not contain instead of reaching the network.
+
+package workflow
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/github/gh-aw/pkg/logger"
+	"github.com/github/gh-aw/pkg/parser"
+)
+
+var offlineBundleLog = logger.New("workflow:offline_bundle")
+
+const (
+	// BundleManifestFileName is the name of the manifest file at the root of an offline bundle
+	BundleManifestFileName = "manifest.json"
+	// BundleFormatVersion is the current offline bundle format version
+	BundleFormatVersion = 1
+)
+
+// BundleManifest describes the contents of an offline compilation bundle
+type BundleManifest struct {
+	Version         int                 `json:"version"`
+	CompilerVersion string              `json:"compiler_version"`
+	CreatedAt       string              `json:"created_at"`
+	Workflows       []string            `json:"workflows"`
+	Imports         []parser.ImportRef  `json:"imports,omitempty"`
+	Packages        map[string][]string `json:"packages,omitempty"`         // package manager (npm, pip, uv) -> package names
+	ContainerImages []string            `json:"container_images,omitempty"` // container images used by MCP servers
+}
+
+// importRefs returns the ref to commit SHA mappings of the bundled remote imports
+func (m *BundleManifest) importRefs() map[string]string {
+	refs := make(map[string]string, len(m.Imports))
+	for _, ref := range m.Imports {
+		refs[ref.Key()] = ref.SHA
+	}
+	return refs
+}
+
+// AddWorkflowRequirements records the packages and container images used by a workflow
+func (m *BundleManifest) AddWorkflowRequirements(workflowData *WorkflowData) {
+	if m.Packages == nil {
+		m.Packages = make(map[string][]string)
+	}
+	for manager, packages := range workflowPackages(workflowData) {
+		for _, pkg := range packages {
+			if !slices.Contains(m.Packages[manager], pkg) {
+				m.Packages[manager] = append(m.Packages[manager], pkg)
+			}
+		}
+		slices.Sort(m.Packages[manager])
+	}
+
+	for _, image := range extractContainerImages(workflowData) {
+		if !slices.Contains(m.ContainerImages, image) {
+			m.ContainerImages = append(m.ContainerImages, image)
+		}
+	}
+	slices.Sort(m.ContainerImages)
+}
+
+// workflowPackages returns the packages a workflow installs, keyed by package manager
+func workflowPackages(workflowData *WorkflowData) map[string][]string {
+	packages := make(map[string][]string)
+	if npx := extractNpxPackages(workflowData); len(npx) > 0 {
+		packages["npm"] = npx
+	}
+	if pip := extractPipPackages(workflowData); len(pip) > 0 {
+		packages["pip"] = pip
+	}
+	if uv := extractUvPackages(workflowData); len(uv) > 0 {
+		packages["uv"] = uv
+	}
+	return packages
+}
+
+// SetOfflineBundle compiles from an extracted offline bundle without network access.
+// Action pins and remote imports are read from bundleDir.
+func (c *Compiler) SetOfflineBundle(manifest *BundleManifest, bundleDir string) {
+	offlineBundleLog.Printf("Enabling offline compilation from bundle: %s", bundleDir)
+	c.offlineBundle = manifest
+	c.bundleDir = bundleDir
+
+	c.importCache = parser.NewImportCache(bundleDir)
+	c.importCache.SetOffline(manifest.importRefs())
+}
+
+// IsOffline returns true when compiling from an offline bundle
+func (c *Compiler) IsOffline() bool {
+	return c.offlineBundle != nil
+}
+
+// GetSharedImportCache returns the shared import cache used by this compiler instance
+func (c *Compiler) GetSharedImportCache() *parser.ImportCache {
+	return c.getSharedImportCache()
+}
+
+// validateOfflineActions fails when actions used by the workflow are not pinned in the bundle
+func (c *Compiler) validateOfflineActions(workflowData *WorkflowData) error {
+	if !c.IsOffline() || len(workflowData.MissingBundleActions) == 0 {
+		return nil
+	}
+	missing := slices.Sorted(slices.Values(workflowData.MissingBundleActions))
+	return fmt.Errorf("actions are not in the offline bundle: %s. Re-export the bundle with 'gh aw bundle export' while online",
+		strings.Join(slices.Compact(missing), ", "))
+}
+
+// validateRuntimePackagesOffline checks the workflow's packages against the bundle manifest
+func (c *Compiler) validateRuntimePackagesOffline(workflowData *WorkflowData) error {
+	var missing []string
+	packages := workflowPackages(workflowData)
+	for _, manager := range slices.Sorted(maps.Keys(packages)) {
+		for _, pkg := range packages[manager] {
+			if !slices.Contains(c.offlineBundle.Packages[manager], pkg) {
+				missing = append(missing, fmt.Sprintf("%s package '%s'", manager, pkg))
+			}
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("packages are not in the offline bundle: %s", strings.Join(missing, ", "))
+	}
+	offlineBundleLog.Print("All runtime packages found in offline bundle")
+	return nil
+}
+
+// validateContainerImagesOffline checks the workflow's container images against the bundle manifest
+func (c *Compiler) validateContainerImagesOffline(workflowData *WorkflowData) error {
+	var missing []string
+	images := extractContainerImages(workflowData)
+	for _, toolName := range slices.Sorted(maps.Keys(images)) {
+		if !slices.Contains(c.offlineBundle.ContainerImages, images[toolName]) {
+			missing = append(missing, fmt.Sprintf("tool '%s': %s", toolName, images[toolName]))
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("container images are not in the offline bundle: %s", strings.Join(missing, ", "))
+	}
+	offlineBundleLog.Print("All container images found in offline bundle")
+	return nil
+}
@@ -0,0 +1,90 @@
+//go:build !integration
+
+package workflow
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newOfflineTestData(t *testing.T) *WorkflowData {
+	cache := NewActionCache(t.TempDir())
+	cache.Set("owner/bundled", "v2", "1111111111111111111111111111111111111111")
+	resolver := NewActionResolver(cache)
+	resolver.offline = true
+
+	return &WorkflowData{ActionCache: cache, ActionResolver: resolver}
+}
+
+func TestBundleManifestAddWorkflowRequirements(t *testing.T) {
+	manifest := &BundleManifest{Version: BundleFormatVersion}
+	manifest.AddWorkflowRequirements(&WorkflowData{
+		CustomSteps: "steps:\n  - run: npx -y prettier@3 --check . && pip install requests",
+		Tools: map[string]any{
+			"fetcher": map[string]any{"container": "ghcr.io/example/fetcher", "version": "v1"},
+		},
+	})
+	manifest.AddWorkflowRequirements(&WorkflowData{
+		CustomSteps: "steps:\n  - run: npx -y eslint && npx -y prettier@3",
+		Tools: map[string]any{
+			"fetcher": map[string]any{"container": "ghcr.io/example/fetcher", "version": "v1"},
+		},
+	})
+
+	assert.Equal(t, []string{"eslint", "prettier@3"}, manifest.Packages["npm"], "packages should be sorted and deduplicated")
+	assert.Equal(t, []string{"requests"}, manifest.Packages["pip"])
+	assert.Equal(t, []string{"ghcr.io/example/fetcher:v1"}, manifest.ContainerImages)
+}
+
+func TestGetActionPinWithDataOffline(t *testing.T) {
+	data := newOfflineTestData(t)
+
+	pin, err := GetActionPinWithData("owner/bundled", "v2", data)
+	require.NoError(t, err)
+	assert.Equal(t, "owner/bundled@1111111111111111111111111111111111111111 # v2", pin)
+
+	embedded := getActionPins()[0]
+	pin, err = GetActionPinWithData(embedded.Repo, embedded.Version, data)
+	require.NoError(t, err)
+	assert.Equal(t, formatActionReference(embedded.Repo, embedded.SHA, embedded.Version), pin, "pins embedded in the binary should be used offline")
+
+	pin, err = GetActionPinWithData("owner/missing", "v1", data)
+	require.NoError(t, err)
+	assert.Empty(t, pin)
+	_, err = GetActionPinWithData("owner/missing", "v1", data)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"owner/missing@v1"}, data.MissingBundleActions, "missing actions should be recorded once")
+
+	compiler := NewCompiler()
+	compiler.SetOfflineBundle(&BundleManifest{Version: BundleFormatVersion}, t.TempDir())
+	err = compiler.validateOfflineActions(data)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "actions are not in the offline bundle: owner/missing@v1")
+}
+
+func TestValidateOfflineRequirements(t *testing.T) {
+	compiler := NewCompiler()
+	compiler.SetOfflineBundle(&BundleManifest{
+		Version:         BundleFormatVersion,
+		Packages:        map[string][]string{"npm": {"prettier"}},
+		ContainerImages: []string{"ghcr.io/example/fetcher:v1"},
+	}, t.TempDir())
+
+	data := &WorkflowData{
+		CustomSteps: "steps:\n  - run: npx -y prettier && npx -y eslint",
+		Tools: map[string]any{
+			"fetcher": map[string]any{"container": "ghcr.io/example/fetcher", "version": "v1"},
+			"scanner": map[string]any{"container": "ghcr.io/example/scanner", "version": "v2"},
+		},
+	}
+
+	err := compiler.validateRuntimePackagesOffline(data)
+	require.Error(t, err)
+	assert.Equal(t, "packages are not in the offline bundle: npm package 'eslint'", err.Error())
+
+	err = compiler.validateContainerImagesOffline(data)
+	require.Error(t, err)
+	assert.Equal(t, "container images are not in the offline bundle: tool 'scanner': ghcr.io/example/scanner:v2", err.Error())
+}
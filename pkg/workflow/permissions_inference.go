@@ -0,0 +1,385 @@
+package workflow
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/github/gh-aw/pkg/logger"
+)
+
+var permissionsInferenceLog = logger.New("workflow:permissions_inference")
+
+// PermissionNeed is a permission the agent job needs, and why
+type PermissionNeed struct {
+	Scope  PermissionScope
+	Level  PermissionLevel
+	Reason string
+	// Optional needs justify a declared permission but are not reported as missing
+	// (e.g., the toolsets of the GitHub tool that is added by default)
+	Optional bool
+}
+
+// PermissionChange is a difference between the declared and the least-privilege permissions
+type PermissionChange struct {
+	Scope     string `json:"scope" console:"header:Scope"`
+	Declared  string `json:"declared,omitempty" console:"header:Declared"`
+	Suggested string `json:"suggested,omitempty" console:"header:Suggested"`
+	Reason    string `json:"reason" console:"header:Reason"`
+}
+
+// PermissionSuggestion compares the permissions a workflow declares with the least-privilege set
+// that its GitHub tool, custom steps and other tools need
+type PermissionSuggestion struct {
+	Workflow  string                              `json:"workflow"`
+	Declared  map[PermissionScope]PermissionLevel `json:"declared"`
+	Suggested map[PermissionScope]PermissionLevel `json:"suggested"`
+	Changes   []PermissionChange                  `json:"changes"`
+	Notes     []string                            `json:"notes,omitempty"`
+}
+
+// HasChanges returns true if the declared permissions differ from the suggested ones
+func (s *PermissionSuggestion) HasChanges() bool {
+	return len(s.Changes) > 0
+}
+
+// RenderSuggestedYAML renders the suggested permissions as the lines of a frontmatter
+// permissions block, without the "permissions:" key
+func (s *PermissionSuggestion) RenderSuggestedYAML() []string {
+	scopes := make([]PermissionScope, 0, len(s.Suggested))
+	for scope := range s.Suggested {
+		scopes = append(scopes, scope)
+	}
+	SortPermissionScopes(scopes)
+	lines := make([]string, 0, len(scopes))
+	for _, scope := range scopes {
+		lines = append(lines, fmt.Sprintf("%s: %s", scope, s.Suggested[scope]))
+	}
+	return lines
+}
+
+// stepPermissionPatterns map commands and actions used in custom steps to the permissions they need.
+// More specific patterns come first so that their reason is reported.
+var stepPermissionPatterns = []struct {
+	pattern *regexp.Regexp
+	scope   PermissionScope
+	level   PermissionLevel
+}{
+	{regexp.MustCompile(`\bgit\s+push\b`), PermissionContents, PermissionWrite},
+	{regexp.MustCompile(`\bgh\s+release\s+(create|upload|edit|delete)\b`), PermissionContents, PermissionWrite},
+	{regexp.MustCompile(`\bgh\s+issue\s+(create|comment|edit|close|reopen|delete|lock|unlock|pin|unpin|transfer)\b`), PermissionIssues, PermissionWrite},
+	{regexp.MustCompile(`\bgh\s+label\s+(create|edit|delete|clone)\b`), PermissionIssues, PermissionWrite},
+	{regexp.MustCompile(`\bgh\s+issue\b`), PermissionIssues, PermissionRead},
+	{regexp.MustCompile(`\bgh\s+pr\s+(create|comment|edit|close|reopen|merge|ready|review)\b`), PermissionPullRequests, PermissionWrite},
+	{regexp.MustCompile(`\bgh\s+pr\b`), PermissionPullRequests, PermissionRead},
+	{regexp.MustCompile(`\bgh\s+(run|workflow|cache)\b`), PermissionActions, PermissionRead},
+	{regexp.MustCompile(`uses:\s*['"]?(aws-actions/configure-aws-credentials|azure/login|google-github-actions/auth)@`), PermissionIdToken, PermissionWrite},
+}
+
+// unresolvedStepPattern matches steps whose permission needs cannot be inferred
+var unresolvedStepPattern = regexp.MustCompile(`\bgh\s+api\b|uses:\s*['"]?actions/github-script@|\bGITHUB_TOKEN\b|\bgithub\.token\b`)
+
+// workflowTokenPattern matches references to the workflow's GITHUB_TOKEN
+var workflowTokenPattern = regexp.MustCompile(`\bGITHUB_TOKEN\b|\bgithub\.token\b`)
+
+// InferAgentPermissions returns the permissions the agent job of a workflow needs, computed from the
+// configured GitHub toolsets and allowed tools, the custom steps and the other tools. Safe outputs and
+// repo-memory pushes run in separate jobs that are granted their own permissions, so they add none.
+// Notes describe needs that could not be inferred. The permissions of workflows with unresolved needs
+// are kept as declared rather than narrowed.
+func InferAgentPermissions(workflowData *WorkflowData) ([]PermissionNeed, []string) {
+	needs := []PermissionNeed{{Scope: PermissionContents, Level: PermissionRead, Reason: "repository checkout"}}
+	var notes []string
+
+	if tools := workflowData.ParsedTools; tools != nil && tools.GitHub != nil {
+		github := tools.GitHub
+		switch {
+		case github.GitHubToken != "" || github.App != nil:
+			notes = append(notes, "The GitHub tool authenticates with its own token, so its toolsets need no workflow permissions")
+		default:
+			toolsetNeeds := githubToolsetNeeds(github)
+			if !workflowData.HasExplicitGitHubTool {
+				for i := range toolsetNeeds {
+					toolsetNeeds[i].Optional = true
+				}
+			}
+			needs = append(needs, toolsetNeeds...)
+		}
+	}
+
+	if workflowData.ParsedTools != nil && workflowData.ParsedTools.AgenticWorkflows != nil {
+		needs = append(needs, PermissionNeed{Scope: PermissionActions, Level: PermissionRead, Reason: "agentic-workflows tool"})
+	}
+
+	for _, steps := range []string{workflowData.CustomSteps, workflowData.PostSteps} {
+		for line := range strings.SplitSeq(steps, "\n") {
+			for _, candidate := range stepPermissionPatterns {
+				if match := candidate.pattern.FindString(line); match != "" {
+					needs = append(needs, PermissionNeed{Scope: candidate.scope, Level: candidate.level, Reason: "custom step: " + strings.TrimSpace(match)})
+					break
+				}
+			}
+			if match := unresolvedStepPattern.FindString(line); match != "" {
+				note := fmt.Sprintf("A custom step uses '%s'; its permissions are kept as declared, review them manually", strings.TrimSpace(match))
+				if !slices.Contains(notes, note) {
+					notes = append(notes, note)
+					needs = append(needs, declaredPermissionNeeds(workflowData, "custom step: "+strings.TrimSpace(match))...)
+				}
+			}
+		}
+	}
+
+	if workflowData.SafeInputs != nil {
+		names := make([]string, 0, len(workflowData.SafeInputs.Tools))
+		for name := range workflowData.SafeInputs.Tools {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			if !safeInputUsesWorkflowToken(workflowData.SafeInputs.Tools[name]) {
+				continue
+			}
+			notes = append(notes, fmt.Sprintf("The safe-input tool '%s' uses GITHUB_TOKEN; its permissions are kept as declared, review them manually", name))
+			needs = append(needs, declaredPermissionNeeds(workflowData, "safe-input "+name+" uses GITHUB_TOKEN")...)
+		}
+	}
+
+	if workflowData.ParsedTools != nil {
+		names := make([]string, 0, len(workflowData.ParsedTools.Custom))
+		for name := range workflowData.ParsedTools.Custom {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			if !mcpServerUsesWorkflowToken(workflowData.ParsedTools.Custom[name]) {
+				continue
+			}
+			notes = append(notes, fmt.Sprintf("The MCP server '%s' uses GITHUB_TOKEN; its permissions are kept as declared, review them manually", name))
+			needs = append(needs, declaredPermissionNeeds(workflowData, "MCP server "+name+" uses GITHUB_TOKEN")...)
+		}
+	}
+
+	permissionsInferenceLog.Printf("Inferred %d permission needs, %d notes", len(needs), len(notes))
+	return needs, notes
+}
+
+// safeInputUsesWorkflowToken reports whether a safe-input tool passes the workflow's GITHUB_TOKEN to
+// its implementation
+func safeInputUsesWorkflowToken(tool *SafeInputToolConfig) bool {
+	if tool == nil {
+		return false
+	}
+	for _, value := range tool.Env {
+		if workflowTokenPattern.MatchString(value) {
+			return true
+		}
+	}
+	return slices.ContainsFunc([]string{tool.Script, tool.Run, tool.Py, tool.Go}, workflowTokenPattern.MatchString)
+}
+
+// mcpServerUsesWorkflowToken reports whether an MCP server receives the workflow's GITHUB_TOKEN
+// through its environment, headers or arguments
+func mcpServerUsesWorkflowToken(server MCPServerConfig) bool {
+	for _, values := range []map[string]string{server.Env, server.Headers} {
+		for _, value := range values {
+			if workflowTokenPattern.MatchString(value) {
+				return true
+			}
+		}
+	}
+	return slices.ContainsFunc(server.Args, workflowTokenPattern.MatchString) ||
+		slices.ContainsFunc(server.EntrypointArgs, workflowTokenPattern.MatchString)
+}
+
+// declaredPermissionNeeds returns optional needs for the declared permissions of a workflow, so that
+// they are kept when something uses the workflow token in a way that cannot be inferred
+func declaredPermissionNeeds(workflowData *WorkflowData, reason string) []PermissionNeed {
+	declared := NewPermissionsParser(workflowData.Permissions).ToPermissions()
+	if declared == nil {
+		return nil
+	}
+	var needs []PermissionNeed
+	for _, scope := range GetAllPermissionScopes() {
+		if level, exists := declared.Get(scope); exists && level != PermissionNone && scope != PermissionMetadata {
+			needs = append(needs, PermissionNeed{Scope: scope, Level: level, Reason: reason, Optional: true})
+		}
+	}
+	return needs
+}
+
+// githubToolsetNeeds returns the permissions of the enabled GitHub toolsets. When the tool lists
+// allowed tools, only the toolsets that provide them are counted.
+func githubToolsetNeeds(github *GitHubToolConfig) []PermissionNeed {
+	toolsets := ParseGitHubToolsets(github.GetToolsets())
+	allowed := github.Allowed.ToStringSlice()
+	var needs []PermissionNeed
+	for _, toolset := range toolsets {
+		perms, exists := toolsetPermissionsMap[toolset]
+		if !exists {
+			continue
+		}
+		if len(allowed) > 0 && !slices.ContainsFunc(allowed, func(tool string) bool { return slices.Contains(perms.Tools, tool) }) {
+			permissionsInferenceLog.Printf("Skipping toolset %s: none of its tools are allowed", toolset)
+			continue
+		}
+		reason := "GitHub toolset " + toolset
+		for _, scope := range perms.ReadPermissions {
+			needs = append(needs, PermissionNeed{Scope: scope, Level: PermissionRead, Reason: reason})
+		}
+		if !github.IsReadOnly() {
+			for _, scope := range perms.WritePermissions {
+				needs = append(needs, PermissionNeed{Scope: scope, Level: PermissionWrite, Reason: reason + " (read-only: false)"})
+			}
+		}
+	}
+	return needs
+}
+
+// permissionRank orders permission levels from no access to write access
+func permissionRank(level PermissionLevel) int {
+	switch level {
+	case PermissionWrite:
+		return 2
+	case PermissionRead:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// SuggestPermissions computes the least-privilege permissions of a workflow and compares them with
+// the permissions it declares
+func SuggestPermissions(workflowData *WorkflowData, markdownPath string) *PermissionSuggestion {
+	suggestion := &PermissionSuggestion{
+		Workflow:  provenanceDisplayPath(markdownPath),
+		Declared:  make(map[PermissionScope]PermissionLevel),
+		Suggested: make(map[PermissionScope]PermissionLevel),
+	}
+
+	declared := NewPermissionsParser(workflowData.Permissions).ToPermissions()
+	for _, scope := range GetAllPermissionScopes() {
+		if scope == PermissionMetadata || declared == nil {
+			continue
+		}
+		if level, exists := declared.Get(scope); exists && level != PermissionNone {
+			suggestion.Declared[scope] = level
+		}
+	}
+
+	needs, notes := InferAgentPermissions(workflowData)
+	suggestion.Notes = notes
+
+	required := make(map[PermissionScope]PermissionLevel)  // Needs that must be granted
+	justified := make(map[PermissionScope]PermissionLevel) // Needs that justify a declared permission
+	reasons := make(map[PermissionScope][]string)
+	for _, need := range needs {
+		if permissionRank(need.Level) > permissionRank(justified[need.Scope]) {
+			justified[need.Scope] = need.Level
+		}
+		if !need.Optional && permissionRank(need.Level) > permissionRank(required[need.Scope]) {
+			required[need.Scope] = need.Level
+		}
+		if !slices.Contains(reasons[need.Scope], need.Reason) {
+			reasons[need.Scope] = append(reasons[need.Scope], need.Reason)
+		}
+	}
+
+	// Permissions of the jobs that apply safe outputs and push repo memory
+	otherJobs := make(map[PermissionScope]string)
+	if safeOutputPerms := ComputePermissionsForSafeOutputs(workflowData.SafeOutputs); safeOutputPerms != nil {
+		for _, scope := range GetAllPermissionScopes() {
+			if level, exists := safeOutputPerms.Get(scope); exists && level == PermissionWrite {
+				otherJobs[scope] = "safe_outputs"
+			}
+		}
+	}
+	if workflowData.RepoMemoryConfig != nil && len(workflowData.RepoMemoryConfig.Memories) > 0 {
+		if _, exists := otherJobs[PermissionContents]; !exists {
+			otherJobs[PermissionContents] = "push_repo_memory"
+		}
+	}
+
+	scopes := make([]PermissionScope, 0, len(suggestion.Declared)+len(justified))
+	for _, scope := range GetAllPermissionScopes() {
+		_, isDeclared := suggestion.Declared[scope]
+		_, isJustified := justified[scope]
+		if isDeclared || isJustified {
+			scopes = append(scopes, scope)
+		}
+	}
+
+	for _, scope := range scopes {
+		declaredLevel := suggestion.Declared[scope]
+		suggestedLevel := declaredLevel
+		if permissionRank(declaredLevel) > permissionRank(justified[scope]) {
+			suggestedLevel = justified[scope]
+		}
+		if permissionRank(declaredLevel) < permissionRank(required[scope]) {
+			suggestedLevel = required[scope]
+		}
+		if suggestedLevel != "" {
+			suggestion.Suggested[scope] = suggestedLevel
+		}
+		if suggestedLevel == declaredLevel {
+			continue
+		}
+
+		reason := strings.Join(reasons[scope], ", ")
+		if permissionRank(suggestedLevel) < permissionRank(declaredLevel) {
+			switch {
+			case declaredLevel == PermissionWrite && otherJobs[scope] != "":
+				reason = fmt.Sprintf("write access is granted to the %s job", otherJobs[scope])
+				if suggestedLevel != "" {
+					reason += "; " + strings.Join(reasons[scope], ", ") + " only reads"
+				}
+			case suggestedLevel == "":
+				reason = "not used by the agent job"
+			default:
+				reason += " only reads"
+			}
+		}
+		suggestion.Changes = append(suggestion.Changes, PermissionChange{
+			Scope:     string(scope),
+			Declared:  string(declaredLevel),
+			Suggested: string(suggestedLevel),
+			Reason:    reason,
+		})
+	}
+
+	sort.SliceStable(suggestion.Changes, func(i, j int) bool {
+		return suggestion.Changes[i].Scope < suggestion.Changes[j].Scope
+	})
+	permissionsInferenceLog.Printf("Suggested permissions for %s: %d changes", suggestion.Workflow, len(suggestion.Changes))
+	return suggestion
+}
+
+// SuggestWorkflowPermissions parses a workflow and returns its least-privilege permission suggestion
+func (c *Compiler) SuggestWorkflowPermissions(markdownPath string) (*PermissionSuggestion, error) {
+	workflowData, err := c.ParseWorkflowFile(markdownPath)
+	if err != nil {
+		return nil, err
+	}
+	return SuggestPermissions(workflowData, markdownPath), nil
+}
+
+// checkExcessWritePermissions warns when the workflow grants write permissions that the agent job
+// does not need, for example write scopes that only safe outputs use
+func (c *Compiler) checkExcessWritePermissions(workflowData *WorkflowData, markdownPath string) {
+	suggestion := SuggestPermissions(workflowData, markdownPath)
+	var excess []string
+	for _, change := range suggestion.Changes {
+		if change.Declared == string(PermissionWrite) && change.Suggested != string(PermissionWrite) && change.Scope != string(PermissionIdToken) {
+			excess = append(excess, fmt.Sprintf("  - %s: write (%s)", change.Scope, change.Reason))
+		}
+	}
+	if len(excess) == 0 {
+		return
+	}
+	message := "This workflow grants write permissions that the agent job does not need:\n" + strings.Join(excess, "\n") +
+		"\n\nRun 'gh aw permissions suggest " + GetWorkflowIDFromPath(markdownPath) + "' to see the least-privilege permissions."
+	fmt.Fprintln(os.Stderr, formatCompilerMessage(markdownPath, "warning", message))
+	c.IncrementWarningCount()
+}
@@ -0,0 +1,186 @@
+//go:build !integration
+
+package workflow
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSuggestWorkflowPermissions(t *testing.T) {
+	tests := []struct {
+		name            string
+		frontmatter     string
+		wantSuggested   map[PermissionScope]PermissionLevel
+		wantChanges     []string
+		wantNoteContain string
+	}{
+		{
+			name: "write scopes used only by safe outputs are dropped",
+			frontmatter: `on: issues
+strict: false
+permissions:
+  contents: read
+  issues: write
+tools:
+  github:
+    toolsets: [issues]
+safe-outputs:
+  add-comment:`,
+			wantSuggested: map[PermissionScope]PermissionLevel{
+				PermissionContents: PermissionRead,
+				PermissionIssues:   PermissionRead,
+			},
+			wantChanges: []string{"issues"},
+		},
+		{
+			name: "allowed tools narrow the toolsets",
+			frontmatter: `on: issues
+permissions:
+  contents: read
+  issues: read
+  pull-requests: read
+tools:
+  github:
+    toolsets: [issues, pull_requests]
+    allowed: [issue_read]`,
+			wantSuggested: map[PermissionScope]PermissionLevel{
+				PermissionContents: PermissionRead,
+				PermissionIssues:   PermissionRead,
+			},
+			wantChanges: []string{"pull-requests"},
+		},
+		{
+			name: "custom steps add missing permissions",
+			frontmatter: `on: workflow_dispatch
+permissions:
+  contents: read
+steps:
+  - name: Comment
+    run: gh issue comment 1 --body done
+  - name: Query
+    run: gh api repos/{owner}/{repo}`,
+			wantSuggested: map[PermissionScope]PermissionLevel{
+				PermissionContents: PermissionRead,
+				PermissionIssues:   PermissionWrite,
+			},
+			wantChanges:     []string{"issues"},
+			wantNoteContain: "gh api",
+		},
+		{
+			name: "safe inputs using GITHUB_TOKEN keep the declared permissions",
+			frontmatter: `on: issues
+permissions:
+  contents: read
+  actions: read
+  discussions: read
+safe-inputs:
+  gh:
+    description: Run gh
+    inputs:
+      args:
+        type: string
+        required: true
+    env:
+      GH_TOKEN: ${{ secrets.GITHUB_TOKEN }}
+    run: gh $INPUT_ARGS`,
+			wantSuggested: map[PermissionScope]PermissionLevel{
+				PermissionContents:    PermissionRead,
+				PermissionActions:     PermissionRead,
+				PermissionDiscussions: PermissionRead,
+			},
+			wantNoteContain: "safe-input tool 'gh'",
+		},
+		{
+			name: "MCP servers using GITHUB_TOKEN keep the declared permissions",
+			frontmatter: `on: issues
+permissions:
+  contents: read
+  issues: read
+  discussions: read
+mcp-servers:
+  tracker:
+    container: example/tracker-mcp
+    env:
+      GITHUB_TOKEN: ${{ secrets.GITHUB_TOKEN }}
+    allowed: ["*"]`,
+			wantSuggested: map[PermissionScope]PermissionLevel{
+				PermissionContents:    PermissionRead,
+				PermissionIssues:      PermissionRead,
+				PermissionDiscussions: PermissionRead,
+			},
+			wantNoteContain: "MCP server 'tracker'",
+		},
+		{
+			name: "custom steps using the workflow token keep the declared permissions",
+			frontmatter: `on: workflow_dispatch
+permissions:
+  contents: read
+  discussions: read
+steps:
+  - name: Query
+    env:
+      GH_TOKEN: ${{ github.token }}
+    run: ./scripts/report.sh`,
+			wantSuggested: map[PermissionScope]PermissionLevel{
+				PermissionContents:    PermissionRead,
+				PermissionDiscussions: PermissionRead,
+			},
+			wantNoteContain: "github.token",
+		},
+		{
+			name: "least-privilege permissions are unchanged",
+			frontmatter: `on: issues
+permissions:
+  contents: read
+  issues: read
+tools:
+  github:
+    toolsets: [issues]`,
+			wantSuggested: map[PermissionScope]PermissionLevel{
+				PermissionContents: PermissionRead,
+				PermissionIssues:   PermissionRead,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			workflowsDir := filepath.Join(t.TempDir(), ".github", "workflows")
+			require.NoError(t, os.MkdirAll(workflowsDir, 0755))
+			workflowFile := filepath.Join(workflowsDir, "test.md")
+			content := "---\n" + tt.frontmatter + "\nengine: copilot\n---\n# Test\n"
+			require.NoError(t, os.WriteFile(workflowFile, []byte(content), 0644))
+
+			suggestion, err := NewCompiler().SuggestWorkflowPermissions(workflowFile)
+			require.NoError(t, err, "permissions should be suggested")
+			assert.Equal(t, tt.wantSuggested, suggestion.Suggested, "suggested permissions should match")
+
+			var changedScopes []string
+			for _, change := range suggestion.Changes {
+				changedScopes = append(changedScopes, change.Scope)
+			}
+			assert.Equal(t, tt.wantChanges, changedScopes, "changed scopes should match")
+			assert.Equal(t, len(tt.wantChanges) > 0, suggestion.HasChanges(), "HasChanges should reflect the changes")
+
+			if tt.wantNoteContain != "" {
+				require.NotEmpty(t, suggestion.Notes, "a note should be reported")
+				assert.Contains(t, suggestion.Notes[0], tt.wantNoteContain, "note should name the unresolved command")
+			}
+		})
+	}
+}
+
+func TestRenderSuggestedYAML(t *testing.T) {
+	suggestion := &PermissionSuggestion{
+		Suggested: map[PermissionScope]PermissionLevel{
+			PermissionIssues:   PermissionRead,
+			PermissionContents: PermissionRead,
+		},
+	}
+	assert.Equal(t, []string{"contents: read", "issues: read"}, suggestion.RenderSuggestedYAML(), "scopes should be rendered in order")
+}
@@ -0,0 +1,318 @@
+package workflow
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/github/gh-aw/pkg/console"
+	"github.com/github/gh-aw/pkg/logger"
+	"github.com/github/gh-aw/pkg/parser"
+)
+
+var promptBudgetLog = logger.New("workflow:prompt_budget")
+
+const (
+	// DefaultContextTokenLimit is the context window assumed for engines that do not declare one
+	DefaultContextTokenLimit = 128000
+
+	// promptCharsPerToken is the characters-per-token ratio used to estimate prompt sizes.
+	// It matches the estimate used by the MCP logs guardrail.
+	promptCharsPerToken = 4
+
+	// promptFullPath is where the trim step keeps the untrimmed prompt
+	promptFullPath = "/tmp/gh-aw/aw-prompts/prompt-full.txt"
+
+	// promptSectionNonceFile is where the seal step writes the per-run nonce of the section markers
+	promptSectionNonceFile = "/tmp/gh-aw/aw-prompts/section-nonce.txt"
+)
+
+// Prompt section priorities. When the prompt is trimmed at runtime, sections with the
+// highest priority value are summarized first. Required sections are never trimmed.
+const (
+	PromptPriorityRequired = 0 // security notice, temporary folder, safe outputs, workflow body
+	PromptPriorityTools    = 1 // tool instructions (playwright, cache memory, repo memory)
+	PromptPriorityContext  = 2 // GitHub and pull request context
+	PromptPriorityImport   = 3 // imported markdown
+)
+
+// builtinPromptFileSizes holds the approximate size in bytes of the built-in prompt files
+// in actions/setup/md. The files are installed by the setup action at runtime and are not
+// available to the compiler, so their sizes are recorded here for prompt budgeting.
+var builtinPromptFileSizes = map[string]int{
+	xpiaPromptFile:                 1326,
+	tempFolderPromptFile:           440,
+	markdownPromptFile:             175,
+	playwrightPromptFile:           376,
+	cacheMemoryPromptFile:          203,
+	cacheMemoryPromptMultiFile:     212,
+	repoMemoryPromptFile:           1465,
+	repoMemoryPromptMultiFile:      1370,
+	safeOutputsPromptFile:          372,
+	safeOutputsCreatePRFile:        560,
+	safeOutputsPushToBranchFile:    450,
+	safeOutputsAutoCreateIssueFile: 177,
+	prContextPromptFile:            547,
+}
+
+// PromptBudgetConfig holds the prompt-budget frontmatter configuration
+type PromptBudgetConfig struct {
+	MaxTokens int  // maximum estimated prompt size in tokens (0 = engine context window)
+	Trim      bool // trim lower-priority sections at runtime when the prompt exceeds the budget
+}
+
+// PromptSectionEstimate is the estimated size of one prompt section
+type PromptSectionEstimate struct {
+	Name     string `json:"name"`
+	Kind     string `json:"kind"` // built-in, import or workflow
+	Tokens   int    `json:"tokens"`
+	Priority int    `json:"priority"`
+}
+
+// PromptBudgetReport is the per-section prompt size estimate of a workflow
+type PromptBudgetReport struct {
+	Sections     []PromptSectionEstimate `json:"sections"`
+	TotalTokens  int                     `json:"total_tokens"`
+	MaxTokens    int                     `json:"max_tokens,omitempty"`
+	ContextLimit int                     `json:"context_limit"`
+}
+
+// Budget returns the effective prompt budget: max-tokens when configured, otherwise the engine context window
+func (r *PromptBudgetReport) Budget() int {
+	if r.MaxTokens > 0 {
+		return r.MaxTokens
+	}
+	return r.ContextLimit
+}
+
+// Exceeded reports whether the estimated prompt size is larger than the budget
+func (r *PromptBudgetReport) Exceeded() bool {
+	return r.TotalTokens > r.Budget()
+}
+
+// extractPromptBudget extracts the prompt-budget section from frontmatter
+func (c *Compiler) extractPromptBudget(frontmatter map[string]any) *PromptBudgetConfig {
+	budgetValue, exists := frontmatter["prompt-budget"]
+	if !exists || budgetValue == nil {
+		return nil
+	}
+
+	budgetMap, ok := budgetValue.(map[string]any)
+	if !ok {
+		return nil
+	}
+
+	config := &PromptBudgetConfig{}
+	if maxTokens, ok := parseIntValue(budgetMap["max-tokens"]); ok {
+		config.MaxTokens = maxTokens
+	}
+	if trim, ok := budgetMap["trim"].(bool); ok {
+		config.Trim = trim
+	}
+
+	promptBudgetLog.Printf("Extracted prompt budget: max-tokens=%d, trim=%v", config.MaxTokens, config.Trim)
+	return config
+}
+
+// estimatePromptTokens estimates the number of tokens in a text
+func estimatePromptTokens(text string) int {
+	return (len(text) + promptCharsPerToken - 1) / promptCharsPerToken
+}
+
+// promptSectionPriority returns the trimming priority of a built-in prompt section
+func promptSectionPriority(name string) int {
+	switch name {
+	case "playwright", "cache-memory", "repo-memory":
+		return PromptPriorityTools
+	case "github-context", "pr-context":
+		return PromptPriorityContext
+	default:
+		return PromptPriorityRequired
+	}
+}
+
+// promptSectionMarker returns the marker line written before a prompt section when
+// runtime trimming is enabled. The seal step adds a per-run nonce to the markers before
+// expressions are interpolated; the trim step then uses the sealed markers to split the
+// rendered prompt into sections and removes them from the final prompt.
+func promptSectionMarker(name string, priority int) string {
+	return fmt.Sprintf("<!-- gh-aw-prompt-section name=%q priority=\"%d\" -->", name, priority)
+}
+
+// isPromptTrimEnabled reports whether the runtime trim step is enabled for the workflow
+func isPromptTrimEnabled(data *WorkflowData) bool {
+	return data.PromptBudget != nil && data.PromptBudget.Trim
+}
+
+// promptContextTokenLimit returns the context window of the workflow's engine
+func (c *Compiler) promptContextTokenLimit(data *WorkflowData) int {
+	engine, err := c.getAgenticEngine(data.AI)
+	if err != nil {
+		return DefaultContextTokenLimit
+	}
+	return engine.GetContextTokenLimit()
+}
+
+// promptTokenBudget returns the effective prompt budget of a workflow
+func (c *Compiler) promptTokenBudget(data *WorkflowData) int {
+	if data.PromptBudget != nil && data.PromptBudget.MaxTokens > 0 {
+		return data.PromptBudget.MaxTokens
+	}
+	return c.promptContextTokenLimit(data)
+}
+
+// estimatePromptBudget estimates the size of each prompt section: the built-in instructions,
+// imported markdown and the workflow body. Imports and the workflow body are read at compile
+// time, so the estimate reflects the files as they are now; runtime imports edited later may differ.
+func (c *Compiler) estimatePromptBudget(data *WorkflowData, markdownPath string) *PromptBudgetReport {
+	report := &PromptBudgetReport{
+		ContextLimit: c.promptContextTokenLimit(data),
+	}
+	if data.PromptBudget != nil {
+		report.MaxTokens = data.PromptBudget.MaxTokens
+	}
+
+	add := func(name, kind string, tokens, priority int) {
+		for i := range report.Sections {
+			if report.Sections[i].Name == name && report.Sections[i].Kind == kind {
+				report.Sections[i].Tokens += tokens
+				report.TotalTokens += tokens
+				return
+			}
+		}
+		report.Sections = append(report.Sections, PromptSectionEstimate{Name: name, Kind: kind, Tokens: tokens, Priority: priority})
+		report.TotalTokens += tokens
+	}
+
+	for _, section := range c.collectPromptSections(data) {
+		tokens := estimatePromptTokens(section.Content)
+		if section.IsFile {
+			tokens = (builtinPromptFileSizes[section.Content] + promptCharsPerToken - 1) / promptCharsPerToken
+		}
+		add(section.Name, "built-in", tokens, promptSectionPriority(section.Name))
+	}
+
+	if data.ImportedMarkdown != "" {
+		imported := removeXMLComments(data.ImportedMarkdown)
+		if len(data.ImportInputs) > 0 {
+			imported = SubstituteImportInputs(imported, data.ImportInputs)
+		}
+		add("imports with inputs", "import", estimatePromptTokens(imported), PromptPriorityImport)
+	}
+
+	if len(data.ImportPaths) > 0 {
+		workspaceRoot := resolveWorkspaceRoot(markdownPath)
+		for _, importPath := range data.ImportPaths {
+			importPath = filepath.ToSlash(importPath)
+			rawContent, err := os.ReadFile(filepath.Join(workspaceRoot, importPath))
+			if err != nil {
+				promptBudgetLog.Printf("Skipping import %s in prompt estimate: %v", importPath, err)
+				continue
+			}
+			body, err := parser.ExtractMarkdownContent(string(rawContent))
+			if err != nil {
+				body = string(rawContent)
+			}
+			add(importPath, "import", estimatePromptTokens(removeXMLComments(body)), PromptPriorityImport)
+		}
+	}
+
+	if data.MainWorkflowMarkdown != "" {
+		add(filepath.Base(markdownPath), "workflow", estimatePromptTokens(removeXMLComments(data.MainWorkflowMarkdown)), PromptPriorityRequired)
+	}
+
+	promptBudgetLog.Printf("Estimated prompt size: %d tokens in %d sections (budget %d)", report.TotalTokens, len(report.Sections), report.Budget())
+	return report
+}
+
+// EstimatePromptBudget parses a workflow and returns its per-section prompt size estimate
+func (c *Compiler) EstimatePromptBudget(markdownPath string) (*PromptBudgetReport, error) {
+	data, err := c.ParseWorkflowFile(markdownPath)
+	if err != nil {
+		return nil, err
+	}
+	return c.estimatePromptBudget(data, markdownPath), nil
+}
+
+// checkPromptBudget warns when the estimated prompt size exceeds the configured budget
+// or, when no budget is configured, the engine context window
+func (c *Compiler) checkPromptBudget(data *WorkflowData, markdownPath string) {
+	report := c.estimatePromptBudget(data, markdownPath)
+	if !report.Exceeded() {
+		return
+	}
+
+	largest := make([]PromptSectionEstimate, len(report.Sections))
+	copy(largest, report.Sections)
+	sort.SliceStable(largest, func(i, j int) bool {
+		return largest[i].Tokens > largest[j].Tokens
+	})
+	if len(largest) > 3 {
+		largest = largest[:3]
+	}
+	var parts []string
+	for _, section := range largest {
+		parts = append(parts, fmt.Sprintf("%s ~%d", section.Name, section.Tokens))
+	}
+
+	budgetName := "prompt-budget.max-tokens"
+	if report.MaxTokens == 0 {
+		budgetName = "engine context window"
+	}
+	message := fmt.Sprintf("Estimated prompt size (~%d tokens) exceeds the %s of %d tokens. Largest sections: %s.",
+		report.TotalTokens, budgetName, report.Budget(), strings.Join(parts, ", "))
+	if !isPromptTrimEnabled(data) {
+		message += " Reduce imported content or set prompt-budget.trim: true to trim lower-priority sections at runtime."
+	}
+	fmt.Fprintln(os.Stderr, console.FormatWarningMessage(message))
+	c.IncrementWarningCount()
+}
+
+// generatePromptSealStep generates the step that adds a random per-run nonce to the section
+// markers. It runs before interpolation, so marker text in issue bodies, titles or other
+// interpolated values cannot start or end a section.
+func (c *Compiler) generatePromptSealStep(yaml *strings.Builder, data *WorkflowData) {
+	if !isPromptTrimEnabled(data) {
+		return
+	}
+
+	yaml.WriteString("      - name: Seal prompt sections\n")
+	fmt.Fprintf(yaml, "        uses: %s\n", GetActionPin("actions/github-script"))
+	yaml.WriteString("        env:\n")
+	yaml.WriteString("          GH_AW_PROMPT: /tmp/gh-aw/aw-prompts/prompt.txt\n")
+	yaml.WriteString("          GH_AW_PROMPT_SECTION_NONCE_FILE: " + promptSectionNonceFile + "\n")
+	yaml.WriteString("        with:\n")
+	yaml.WriteString("          script: |\n")
+	yaml.WriteString("            const { setupGlobals } = require('" + SetupActionDestination + "/setup_globals.cjs');\n")
+	yaml.WriteString("            setupGlobals(core, github, context, exec, io);\n")
+	yaml.WriteString("            const { seal } = require('" + SetupActionDestination + "/trim_prompt.cjs');\n")
+	yaml.WriteString("            await seal();\n")
+}
+
+// generatePromptTrimStep generates the step that trims lower-priority prompt sections
+// when the rendered prompt exceeds the budget. It runs after placeholder substitution,
+// when the prompt is complete, and always removes the section markers.
+func (c *Compiler) generatePromptTrimStep(yaml *strings.Builder, data *WorkflowData) {
+	if !isPromptTrimEnabled(data) {
+		return
+	}
+
+	budget := c.promptTokenBudget(data)
+	promptBudgetLog.Printf("Generating prompt trim step: budget=%d tokens", budget)
+
+	yaml.WriteString("      - name: Trim prompt to budget\n")
+	fmt.Fprintf(yaml, "        uses: %s\n", GetActionPin("actions/github-script"))
+	yaml.WriteString("        env:\n")
+	yaml.WriteString("          GH_AW_PROMPT: /tmp/gh-aw/aw-prompts/prompt.txt\n")
+	yaml.WriteString("          GH_AW_PROMPT_FULL: " + promptFullPath + "\n")
+	yaml.WriteString("          GH_AW_PROMPT_SECTION_NONCE_FILE: " + promptSectionNonceFile + "\n")
+	fmt.Fprintf(yaml, "          GH_AW_PROMPT_MAX_TOKENS: %d\n", budget)
+	yaml.WriteString("        with:\n")
+	yaml.WriteString("          script: |\n")
+	yaml.WriteString("            const { setupGlobals } = require('" + SetupActionDestination + "/setup_globals.cjs');\n")
+	yaml.WriteString("            setupGlobals(core, github, context, exec, io);\n")
+	yaml.WriteString("            const { main } = require('" + SetupActionDestination + "/trim_prompt.cjs');\n")
+	yaml.WriteString("            await main();\n")
+}
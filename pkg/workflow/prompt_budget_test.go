@@ -0,0 +1,135 @@
+//go:build !integration
+
+package workflow
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/github/gh-aw/pkg/stringutil"
+	"github.com/github/gh-aw/pkg/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExtractPromptBudget(t *testing.T) {
+	compiler := NewCompiler()
+
+	config := compiler.extractPromptBudget(map[string]any{
+		"prompt-budget": map[string]any{"max-tokens": 16000, "trim": true},
+	})
+	require.NotNil(t, config, "Prompt budget should be extracted")
+	assert.Equal(t, 16000, config.MaxTokens, "max-tokens should be extracted")
+	assert.True(t, config.Trim, "trim should be extracted")
+
+	assert.Nil(t, compiler.extractPromptBudget(map[string]any{}), "Missing section should yield no budget")
+}
+
+func TestBuiltinPromptFileSizes(t *testing.T) {
+	// The recorded sizes are estimates; they only need to stay close to the real files
+	for name, size := range builtinPromptFileSizes {
+		content, err := os.ReadFile(filepath.Join("..", "..", "actions", "setup", "md", name))
+		require.NoError(t, err, "Built-in prompt file %s should exist", name)
+		assert.InDelta(t, len(content), size, float64(len(content))*0.25+50, "Recorded size of %s should be close to the actual file size", name)
+	}
+}
+
+func TestEngineContextTokenLimit(t *testing.T) {
+	assert.Equal(t, 200000, NewClaudeEngine().GetContextTokenLimit(), "Claude should declare its context window")
+	assert.Equal(t, DefaultContextTokenLimit, NewCopilotEngine().GetContextTokenLimit(), "Copilot should use the default context window")
+}
+
+func TestEstimatePromptBudget(t *testing.T) {
+	tmpDir := testutil.TempDir(t, "prompt-budget-estimate")
+	workflowsDir := filepath.Join(tmpDir, ".github", "workflows")
+	require.NoError(t, os.MkdirAll(filepath.Join(workflowsDir, "shared"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(workflowsDir, "shared", "reference.md"), []byte("---\n---\n\n"+strings.Repeat("r", 4000)+"\n"), 0644))
+
+	data := &WorkflowData{
+		AI:                   "claude",
+		ImportPaths:          []string{".github/workflows/shared/reference.md"},
+		MainWorkflowMarkdown: strings.Repeat("w", 400),
+		PromptBudget:         &PromptBudgetConfig{MaxTokens: 1000},
+	}
+
+	compiler := NewCompiler()
+	report := compiler.estimatePromptBudget(data, filepath.Join(workflowsDir, "review.md"))
+
+	byName := make(map[string]PromptSectionEstimate)
+	total := 0
+	for _, section := range report.Sections {
+		byName[section.Name] = section
+		total += section.Tokens
+	}
+
+	assert.Equal(t, total, report.TotalTokens, "Total should be the sum of the sections")
+	assert.Equal(t, 200000, report.ContextLimit, "Context limit should come from the engine")
+	assert.Equal(t, 1000, report.Budget(), "max-tokens should be the budget")
+	assert.True(t, report.Exceeded(), "Estimate should exceed the budget")
+
+	assert.Equal(t, PromptSectionEstimate{Name: ".github/workflows/shared/reference.md", Kind: "import", Tokens: 1000, Priority: PromptPriorityImport}, byName[".github/workflows/shared/reference.md"], "Import should be estimated from disk")
+	assert.Equal(t, PromptSectionEstimate{Name: "review.md", Kind: "workflow", Tokens: 100, Priority: PromptPriorityRequired}, byName["review.md"], "Workflow body should be estimated")
+	assert.Equal(t, 332, byName["xpia"].Tokens, "Built-in file sections should use the recorded sizes")
+}
+
+func TestPromptBudgetCompile(t *testing.T) {
+	tmpDir := testutil.TempDir(t, "prompt-budget-compile")
+	workflowsDir := filepath.Join(tmpDir, ".github", "workflows")
+	require.NoError(t, os.MkdirAll(filepath.Join(workflowsDir, "shared"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(workflowsDir, "shared", "reference.md"), []byte("---\n---\n\n# Reference\n\n"+strings.Repeat("Background material. ", 400)+"\n"), 0644))
+
+	workflow := `---
+on: workflow_dispatch
+permissions:
+  contents: read
+engine: copilot
+imports:
+  - shared/reference.md
+prompt-budget:
+  max-tokens: 1500
+  trim: true
+---
+
+# Review
+
+Review the repository for ${{ github.actor }}.
+`
+	workflowPath := filepath.Join(workflowsDir, "review.md")
+	require.NoError(t, os.WriteFile(workflowPath, []byte(workflow), 0644))
+
+	compiler := NewCompiler()
+	require.NoError(t, compiler.CompileWorkflow(workflowPath), "Workflow with a prompt budget should compile")
+	assert.Positive(t, compiler.GetWarningCount(), "Exceeding the prompt budget should emit a warning")
+
+	lockContent, err := os.ReadFile(stringutil.MarkdownToLockFile(workflowPath))
+	require.NoError(t, err, "Lock file should be written")
+	lock := string(lockContent)
+
+	assert.Contains(t, lock, "- name: Trim prompt to budget", "Trim step should be generated")
+	assert.Contains(t, lock, "GH_AW_PROMPT_MAX_TOKENS: 1500", "Trim step should use max-tokens")
+	assert.Contains(t, lock, `echo '<!-- gh-aw-prompt-section name="xpia" priority="0" -->'`, "Built-in sections should be marked")
+	assert.Contains(t, lock, `<!-- gh-aw-prompt-section name=".github/workflows/shared/reference.md" priority="3" -->`, "Imports should be marked")
+	assert.Contains(t, lock, `<!-- gh-aw-prompt-section name="review.md" priority="0" -->`, "Workflow body should be marked")
+	assert.Contains(t, lock, "            /tmp/gh-aw/aw-prompts/prompt-full.txt", "Untrimmed prompt should be uploaded")
+	assert.Less(t, strings.Index(lock, "- name: Trim prompt to budget"), strings.Index(lock, "- name: Validate prompt placeholders"), "Trim step should run before the prompt is validated")
+
+	// Markers are sealed with a per-run nonce before expressions are interpolated into the prompt
+	sealIndex := strings.Index(lock, "- name: Seal prompt sections")
+	require.NotEqual(t, -1, sealIndex, "Seal step should be generated")
+	assert.Less(t, sealIndex, strings.Index(lock, "- name: Interpolate variables and render templates"), "Markers should be sealed before interpolation")
+	assert.Less(t, sealIndex, strings.Index(lock, "- name: Substitute placeholders"), "Markers should be sealed before placeholder substitution")
+	assert.Contains(t, lock, "await seal();", "Seal step should run the seal entry point")
+	assert.Equal(t, 2, strings.Count(lock, "GH_AW_PROMPT_SECTION_NONCE_FILE: /tmp/gh-aw/aw-prompts/section-nonce.txt"), "Seal and trim steps should share the nonce file")
+
+	// Without trim, no markers or trim step are generated
+	workflow = strings.Replace(workflow, "  trim: true\n", "", 1)
+	require.NoError(t, os.WriteFile(workflowPath, []byte(workflow), 0644))
+	require.NoError(t, compiler.CompileWorkflow(workflowPath), "Workflow without trimming should compile")
+	lockContent, err = os.ReadFile(stringutil.MarkdownToLockFile(workflowPath))
+	require.NoError(t, err, "Lock file should be written")
+	assert.NotContains(t, string(lockContent), "gh-aw-prompt-section", "Markers should only be written when trimming is enabled")
+	assert.NotContains(t, string(lockContent), "Trim prompt to budget", "Trim step should only be generated when trimming is enabled")
+	assert.NotContains(t, string(lockContent), "Seal prompt sections", "Seal step should only be generated when trimming is enabled")
+}
@@ -0,0 +1,344 @@
+// This file provides compile-time validation for prompt templates.
+//
+// # Prompt Template Validation
+//
+// In addition to {{#if}} conditionals, the prompt body supports a small,
+// logic-less template layer that is rendered by the interpolation step at runtime:
+//
+//   - {{var.name}} inserts a variable declared under prompt-variables
+//   - {{#each var.name}} ... {{/each}} repeats a block for every item of an array variable
+//   - {{#partial name}} ... {{/partial}} defines a named partial (typically in a shared file)
+//   - {{> name}} inserts a partial
+//
+// Templates never evaluate code: variables are data, and the only operations are
+// substitution, iteration and inclusion. This file checks at compile time that:
+//
+//   - Every referenced variable is declared and every iterated variable is an array
+//   - Every referenced partial is defined exactly once and partials do not include themselves
+//   - {{#each}} and {{#partial}} blocks are balanced and not nested
+//   - Variable declarations have a supported type and a default matching that type
+//
+// Partials are collected from the workflow markdown and from imported files,
+// including imports that are loaded at runtime.
+
+package workflow
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/github/gh-aw/pkg/logger"
+	"github.com/github/gh-aw/pkg/parser"
+)
+
+var promptTemplateValidationLog = logger.New("workflow:prompt_template_validation")
+
+var (
+	// promptVariableNamePattern matches valid prompt variable names
+	promptVariableNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_-]*$`)
+
+	// promptTemplateTagPattern matches block tags that must be balanced
+	promptTemplateTagPattern = regexp.MustCompile(`\{\{(#each|/each|#partial|/partial)\b\s*([^}]*?)\s*\}\}`)
+
+	// promptPartialBlockPattern matches a partial definition and its body
+	promptPartialBlockPattern = regexp.MustCompile(`(?s)\{\{#partial\s+([A-Za-z0-9_-]+)\s*\}\}(.*?)\{\{/partial\}\}`)
+
+	// promptPartialRefPattern matches a partial reference
+	promptPartialRefPattern = regexp.MustCompile(`\{\{>\s*([^}]*?)\s*\}\}`)
+
+	// promptVariableRefPattern matches {{var.x}}, {{var.x.field}}, {{#if var.x}} and {{#each var.x}}
+	promptVariableRefPattern = regexp.MustCompile(`\{\{(#if\s+|#each\s+|\s*)var\.([A-Za-z0-9_-]+)((?:\.[A-Za-z0-9_-]+)*)\s*\}\}`)
+
+	// promptPartialNamePattern matches valid partial names
+	promptPartialNamePattern = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+)
+
+// promptTemplateSource is a piece of prompt markdown and where it came from
+type promptTemplateSource struct {
+	Name    string
+	Content string
+}
+
+// validatePromptTemplates validates prompt variable declarations and the template
+// constructs used in the workflow markdown and its imports
+func validatePromptTemplates(workflowData *WorkflowData, markdownPath string) error {
+	if err := validatePromptVariables(workflowData.PromptVariables); err != nil {
+		return err
+	}
+
+	sources := collectPromptTemplateSources(workflowData, markdownPath)
+
+	hasTemplates := len(workflowData.PromptVariables) > 0
+	for _, source := range sources {
+		if strings.Contains(source.Content, "{{#each") || strings.Contains(source.Content, "{{#partial") ||
+			strings.Contains(source.Content, "{{>") || strings.Contains(source.Content, "var.") {
+			hasTemplates = true
+			break
+		}
+	}
+	if !hasTemplates {
+		return nil
+	}
+
+	promptTemplateValidationLog.Printf("Validating prompt templates in %d source(s)", len(sources))
+
+	for _, source := range sources {
+		if err := validatePromptTemplateBlocks(source); err != nil {
+			return err
+		}
+	}
+
+	partials, err := collectPromptPartials(sources)
+	if err != nil {
+		return err
+	}
+	if err := validatePromptPartialReferences(sources, partials); err != nil {
+		return err
+	}
+
+	return validatePromptVariableReferences(sources, workflowData.PromptVariables)
+}
+
+// validatePromptVariables validates the prompt-variables declarations
+func validatePromptVariables(variables []*PromptVariable) error {
+	envNames := make(map[string]string)
+	for _, variable := range variables {
+		path := "prompt-variables." + variable.Name
+
+		if !promptVariableNamePattern.MatchString(variable.Name) {
+			return fmt.Errorf("%s: invalid variable name, names must start with a letter or underscore and contain only letters, digits, '_' and '-'", path)
+		}
+		if !slices.Contains(promptVariableTypes, variable.Type) {
+			return fmt.Errorf("%s: unsupported type '%s', expected one of: %s", path, variable.Type, strings.Join(promptVariableTypes, ", "))
+		}
+
+		envName := promptVariableEnvName(variable.Name)
+		if other, exists := envNames[envName]; exists {
+			return fmt.Errorf("%s: conflicts with prompt variable '%s' (both map to %s)", path, other, envName)
+		}
+		envNames[envName] = variable.Name
+
+		if variable.Value != "" {
+			trimmed := strings.TrimSpace(variable.Value)
+			if !strings.HasPrefix(trimmed, "${{") || !strings.HasSuffix(trimmed, "}}") {
+				return fmt.Errorf("%s: 'value' must be a GitHub Actions expression such as ${{ inputs.%s }}; use 'default' for literal values", path, variable.Name)
+			}
+			if err := validateExpressionSafety(trimmed); err != nil {
+				return fmt.Errorf("%s: %w", path, err)
+			}
+		}
+
+		if variable.Default == nil {
+			if variable.Value == "" {
+				return fmt.Errorf("%s: declare a 'default' or a 'value' expression", path)
+			}
+			continue
+		}
+		if actual := inferPromptVariableType(variable.Default); actual != variable.Type {
+			return fmt.Errorf("%s: default value has type '%s' but the variable is declared as '%s'", path, actual, variable.Type)
+		}
+		if containsExpressionMarker(variable.Default) {
+			return fmt.Errorf("%s: default value must not contain GitHub Actions expressions; use 'value' instead", path)
+		}
+	}
+	return nil
+}
+
+// containsExpressionMarker reports whether a default value contains "${{" anywhere
+func containsExpressionMarker(value any) bool {
+	switch v := value.(type) {
+	case string:
+		return strings.Contains(v, "${{")
+	case []any:
+		return slices.ContainsFunc(v, containsExpressionMarker)
+	case map[string]any:
+		for _, item := range v {
+			if containsExpressionMarker(item) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// collectPromptTemplateSources returns the workflow markdown and the markdown of imported files.
+// Imports without inputs are not part of MarkdownContent, so their content is read from disk here.
+// Files that cannot be read are skipped; the runtime import reports them.
+func collectPromptTemplateSources(workflowData *WorkflowData, markdownPath string) []promptTemplateSource {
+	sources := []promptTemplateSource{{Name: filepath.Base(markdownPath), Content: workflowData.MarkdownContent}}
+
+	workspaceRoot := resolveWorkspaceRoot(markdownPath)
+	for _, importPath := range workflowData.ImportPaths {
+		rawContent, err := os.ReadFile(filepath.Join(workspaceRoot, importPath))
+		if err != nil {
+			promptTemplateValidationLog.Printf("Skipping unreadable import %s: %v", importPath, err)
+			continue
+		}
+		body, err := parser.ExtractMarkdownContent(string(rawContent))
+		if err != nil {
+			body = string(rawContent)
+		}
+		sources = append(sources, promptTemplateSource{Name: filepath.ToSlash(importPath), Content: body})
+	}
+	return sources
+}
+
+// validatePromptTemplateBlocks checks that {{#each}} and {{#partial}} blocks are balanced and not nested
+func validatePromptTemplateBlocks(source promptTemplateSource) error {
+	var open []string
+	for _, match := range promptTemplateTagPattern.FindAllStringSubmatch(source.Content, -1) {
+		tag, argument := match[1], match[2]
+		switch tag {
+		case "#each":
+			if argument == "" {
+				return fmt.Errorf("%s: {{#each}} requires a prompt variable, e.g. {{#each var.items}}", source.Name)
+			}
+			if !strings.HasPrefix(argument, "var.") {
+				return fmt.Errorf("%s: {{#each %s}} must iterate over a prompt variable (var.<name>); declare the array under prompt-variables, using 'value' to bind it to an input or expression", source.Name, argument)
+			}
+			if slices.Contains(open, "each") {
+				return fmt.Errorf("%s: nested {{#each}} blocks are not supported", source.Name)
+			}
+			open = append(open, "each")
+		case "#partial":
+			if !promptPartialNamePattern.MatchString(argument) {
+				return fmt.Errorf("%s: invalid partial name '%s' in {{#partial}}", source.Name, argument)
+			}
+			if len(open) > 0 {
+				return fmt.Errorf("%s: {{#partial %s}} must be defined at the top level, not inside another block", source.Name, argument)
+			}
+			open = append(open, "partial")
+		case "/each", "/partial":
+			name := tag[1:]
+			if len(open) == 0 || open[len(open)-1] != name {
+				return fmt.Errorf("%s: unexpected {{/%s}} without a matching {{#%s}}", source.Name, name, name)
+			}
+			open = open[:len(open)-1]
+		}
+	}
+	if len(open) > 0 {
+		name := open[len(open)-1]
+		return fmt.Errorf("%s: unclosed {{#%s}} block, expected {{/%s}}", source.Name, name, name)
+	}
+	return nil
+}
+
+// collectPromptPartials returns the body of every partial defined across the sources
+func collectPromptPartials(sources []promptTemplateSource) (map[string]string, error) {
+	partials := make(map[string]string)
+	definedIn := make(map[string]string)
+	for _, source := range sources {
+		for _, match := range promptPartialBlockPattern.FindAllStringSubmatch(source.Content, -1) {
+			name := match[1]
+			if previous, exists := definedIn[name]; exists {
+				return nil, fmt.Errorf("%s: partial '%s' is already defined in %s", source.Name, name, previous)
+			}
+			definedIn[name] = source.Name
+			partials[name] = match[2]
+		}
+	}
+	return partials, nil
+}
+
+// validatePromptPartialReferences checks that referenced partials exist and do not include themselves
+func validatePromptPartialReferences(sources []promptTemplateSource, partials map[string]string) error {
+	for _, source := range sources {
+		for _, match := range promptPartialRefPattern.FindAllStringSubmatch(source.Content, -1) {
+			name := match[1]
+			if _, exists := partials[name]; !exists {
+				return fmt.Errorf("%s: partial '%s' is not defined. %s", source.Name, name, availablePromptNames("partials", sortedPromptPartialNames(partials)))
+			}
+		}
+	}
+
+	// Detect cycles between partials with a depth-first search
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	state := make(map[string]int)
+	var visit func(name string, chain []string) error
+	visit = func(name string, chain []string) error {
+		switch state[name] {
+		case visiting:
+			return fmt.Errorf("partial '%s' includes itself: %s", name, strings.Join(append(chain, name), " -> "))
+		case visited:
+			return nil
+		}
+		state[name] = visiting
+		for _, match := range promptPartialRefPattern.FindAllStringSubmatch(partials[name], -1) {
+			if err := visit(match[1], append(chain, name)); err != nil {
+				return err
+			}
+		}
+		state[name] = visited
+		return nil
+	}
+	for _, name := range sortedPromptPartialNames(partials) {
+		if err := visit(name, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validatePromptVariableReferences checks that referenced variables are declared
+// and that {{#each}} only iterates over array variables
+func validatePromptVariableReferences(sources []promptTemplateSource, variables []*PromptVariable) error {
+	declared := make(map[string]*PromptVariable, len(variables))
+	names := make([]string, 0, len(variables))
+	for _, variable := range variables {
+		declared[variable.Name] = variable
+		names = append(names, variable.Name)
+	}
+
+	var errs []error
+	reported := make(map[string]bool)
+	for _, source := range sources {
+		for _, match := range promptVariableRefPattern.FindAllStringSubmatch(source.Content, -1) {
+			kind, name := strings.TrimSpace(match[1]), match[2]
+			variable, exists := declared[name]
+			if !exists {
+				if reported[source.Name+"\x00"+name] {
+					continue
+				}
+				reported[source.Name+"\x00"+name] = true
+				errs = append(errs, fmt.Errorf("%s: prompt variable '%s' is not declared in prompt-variables. %s", source.Name, name, availablePromptNames("variables", names)))
+				continue
+			}
+			if kind == "#each" && match[3] != "" {
+				errs = append(errs, fmt.Errorf("%s: {{#each var.%s%s}} must iterate over a variable, not a field of one", source.Name, name, match[3]))
+				continue
+			}
+			if kind == "#each" && variable.Type != "array" {
+				errs = append(errs, fmt.Errorf("%s: {{#each var.%s}} requires an array variable, but '%s' has type '%s'", source.Name, name, name, variable.Type))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// availablePromptNames formats a hint listing the defined variables or partials
+func availablePromptNames(kind string, names []string) string {
+	if len(names) == 0 {
+		return fmt.Sprintf("No %s are defined", kind)
+	}
+	return fmt.Sprintf("Available %s: %s", kind, strings.Join(names, ", "))
+}
+
+// sortedPromptPartialNames returns the partial names in sorted order
+func sortedPromptPartialNames(partials map[string]string) []string {
+	names := make([]string, 0, len(partials))
+	for name := range partials {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,266 @@
+//go:build !integration
+
+package workflow
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/github/gh-aw/pkg/stringutil"
+	"github.com/github/gh-aw/pkg/testutil"
+	"github.com/goccy/go-yaml"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExtractPromptVariables(t *testing.T) {
+	compiler := NewCompiler()
+	frontmatter := map[string]any{
+		"prompt-variables": map[string]any{
+			"labels": map[string]any{
+				"type":        "array",
+				"description": "Labels to review",
+				"default":     []any{"bug"},
+				"value":       "${{ inputs.labels }}",
+			},
+			"focus":   "security",
+			"limit":   5,
+			"verbose": false,
+			"repo":    map[string]any{"owner": "octo"},
+		},
+	}
+
+	variables := compiler.extractPromptVariables(frontmatter)
+	require.Len(t, variables, 5, "All variables should be extracted")
+
+	byName := make(map[string]*PromptVariable)
+	for _, variable := range variables {
+		byName[variable.Name] = variable
+	}
+	assert.Equal(t, []string{"focus", "labels", "limit", "repo", "verbose"}, []string{variables[0].Name, variables[1].Name, variables[2].Name, variables[3].Name, variables[4].Name}, "Variables should be sorted by name")
+	assert.Equal(t, "array", byName["labels"].Type, "Declared type should be kept")
+	assert.Equal(t, "${{ inputs.labels }}", byName["labels"].Value, "Value expression should be kept")
+	assert.Equal(t, "string", byName["focus"].Type, "Shorthand string should infer type")
+	assert.Equal(t, "number", byName["limit"].Type, "Shorthand number should infer type")
+	assert.Equal(t, "boolean", byName["verbose"].Type, "Shorthand boolean should infer type")
+	assert.Equal(t, "object", byName["repo"].Type, "Shorthand object should infer type")
+	assert.Equal(t, map[string]any{"owner": "octo"}, byName["repo"].Default, "Shorthand object should be the default value")
+
+	assert.Nil(t, compiler.extractPromptVariables(map[string]any{}), "Missing section should yield no variables")
+}
+
+func TestValidatePromptVariables(t *testing.T) {
+	tests := []struct {
+		name      string
+		variables []*PromptVariable
+		wantErr   string
+	}{
+		{
+			name: "valid declarations",
+			variables: []*PromptVariable{
+				{Name: "labels", Type: "array", Default: []any{"bug"}, Value: "${{ inputs.labels }}"},
+				{Name: "focus", Type: "string", Default: "security"},
+				{Name: "count", Type: "number", Value: "${{ inputs.count }}"},
+			},
+		},
+		{
+			name:      "unsupported type",
+			variables: []*PromptVariable{{Name: "x", Type: "date", Default: "today"}},
+			wantErr:   "prompt-variables.x: unsupported type 'date'",
+		},
+		{
+			name:      "default does not match type",
+			variables: []*PromptVariable{{Name: "labels", Type: "array", Default: "bug"}},
+			wantErr:   "default value has type 'string' but the variable is declared as 'array'",
+		},
+		{
+			name:      "missing default and value",
+			variables: []*PromptVariable{{Name: "focus", Type: "string"}},
+			wantErr:   "declare a 'default' or a 'value' expression",
+		},
+		{
+			name:      "literal value",
+			variables: []*PromptVariable{{Name: "focus", Type: "string", Value: "security"}},
+			wantErr:   "'value' must be a GitHub Actions expression",
+		},
+		{
+			name:      "expression in default",
+			variables: []*PromptVariable{{Name: "focus", Type: "string", Default: "${{ github.actor }}"}},
+			wantErr:   "default value must not contain GitHub Actions expressions",
+		},
+		{
+			name:      "unsafe value expression",
+			variables: []*PromptVariable{{Name: "token", Type: "string", Value: "${{ secrets.GITHUB_TOKEN }}"}},
+			wantErr:   "prompt-variables.token:",
+		},
+		{
+			name: "names mapping to the same environment variable",
+			variables: []*PromptVariable{
+				{Name: "my-var", Type: "string", Default: "a"},
+				{Name: "my_var", Type: "string", Default: "b"},
+			},
+			wantErr: "conflicts with prompt variable 'my-var'",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validatePromptVariables(tt.variables)
+			if tt.wantErr == "" {
+				assert.NoError(t, err, "Declarations should be valid")
+				return
+			}
+			require.Error(t, err, "Declarations should be rejected")
+			assert.Contains(t, err.Error(), tt.wantErr, "Error should describe the problem")
+		})
+	}
+}
+
+func TestValidatePromptTemplates(t *testing.T) {
+	variables := []*PromptVariable{
+		{Name: "focus", Type: "string", Default: "security"},
+		{Name: "labels", Type: "array", Default: []any{"bug"}},
+	}
+
+	tests := []struct {
+		name     string
+		markdown string
+		wantErr  string
+	}{
+		{
+			name:     "declared variables, loops and partials",
+			markdown: "{{#partial intro}}\nFocus on {{var.focus}}.\n{{/partial}}\n{{> intro}}\n{{#if var.focus}}\nx\n{{/if}}\n{{#each var.labels}}\n- {{this}}\n{{/each}}\n",
+		},
+		{
+			name:     "undeclared variable",
+			markdown: "Focus on {{var.area}}.",
+			wantErr:  "prompt variable 'area' is not declared in prompt-variables. Available variables: focus, labels",
+		},
+		{
+			name:     "loop over non-array variable",
+			markdown: "{{#each var.focus}}x{{/each}}",
+			wantErr:  "{{#each var.focus}} requires an array variable",
+		},
+		{
+			name:     "loop over expression",
+			markdown: "{{#each github.event.issue.labels}}x{{/each}}",
+			wantErr:  "must iterate over a prompt variable",
+		},
+		{
+			name:     "nested loops",
+			markdown: "{{#each var.labels}}{{#each var.labels}}x{{/each}}{{/each}}",
+			wantErr:  "nested {{#each}} blocks are not supported",
+		},
+		{
+			name:     "unclosed loop",
+			markdown: "{{#each var.labels}}\n- {{this}}\n",
+			wantErr:  "unclosed {{#each}} block",
+		},
+		{
+			name:     "undefined partial",
+			markdown: "{{> checklist}}",
+			wantErr:  "partial 'checklist' is not defined. No partials are defined",
+		},
+		{
+			name:     "recursive partials",
+			markdown: "{{#partial a}}\n{{> b}}\n{{/partial}}\n{{#partial b}}\n{{> a}}\n{{/partial}}\n",
+			wantErr:  "partial 'a' includes itself: a -> b -> a",
+		},
+		{
+			name:     "duplicate partial",
+			markdown: "{{#partial a}}\nx\n{{/partial}}\n{{#partial a}}\ny\n{{/partial}}\n",
+			wantErr:  "partial 'a' is already defined in workflow.md",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := &WorkflowData{MarkdownContent: tt.markdown, PromptVariables: variables}
+			err := validatePromptTemplates(data, filepath.Join(t.TempDir(), "workflow.md"))
+			if tt.wantErr == "" {
+				assert.NoError(t, err, "Templates should be valid")
+				return
+			}
+			require.Error(t, err, "Templates should be rejected")
+			assert.Contains(t, err.Error(), tt.wantErr, "Error should describe the problem")
+		})
+	}
+}
+
+func TestPromptTemplatePartialsFromSharedImport(t *testing.T) {
+	tmpDir := testutil.TempDir(t, "prompt-template-partials")
+	workflowsDir := filepath.Join(tmpDir, ".github", "workflows")
+	require.NoError(t, os.MkdirAll(filepath.Join(workflowsDir, "shared"), 0755))
+
+	shared := `---
+---
+
+{{#partial review-checklist}}
+- Check {{var.focus}} issues
+{{/partial}}
+`
+	require.NoError(t, os.WriteFile(filepath.Join(workflowsDir, "shared", "checklist.md"), []byte(shared), 0644))
+
+	workflow := `---
+on:
+  workflow_dispatch:
+    inputs:
+      labels:
+        description: Labels to review
+        required: false
+permissions:
+  contents: read
+engine: copilot
+imports:
+  - shared/checklist.md
+prompt-variables:
+  focus: security
+  labels:
+    type: array
+    default: [bug]
+    value: ${{ inputs.labels }}
+---
+
+# Review
+
+{{> review-checklist}}
+
+{{#each var.labels}}
+- Label {{this}}
+{{/each}}
+`
+	workflowPath := filepath.Join(workflowsDir, "review.md")
+	require.NoError(t, os.WriteFile(workflowPath, []byte(workflow), 0644))
+
+	compiler := NewCompiler()
+	require.NoError(t, compiler.CompileWorkflow(workflowPath), "Workflow using shared partials should compile")
+
+	lockContent, err := os.ReadFile(stringutil.MarkdownToLockFile(workflowPath))
+	require.NoError(t, err, "Lock file should be written")
+	lock := string(lockContent)
+
+	assert.Contains(t, lock, "- name: Interpolate variables and render templates", "Template rendering step should be generated")
+	assert.Contains(t, lock, `GH_AW_PROMPT_VARIABLES: "{\"focus\":{\"type\":\"string\",\"default\":\"security\"},\"labels\":{\"type\":\"array\",\"default\":[\"bug\"],\"env\":\"GH_AW_PROMPT_VAR_LABELS\"}}"`, "Variable declarations should be passed to the step")
+	assert.Contains(t, lock, `GH_AW_PROMPT_VAR_LABELS: "${{ inputs.labels }}"`, "Variable value expression should be passed to the step")
+
+	// A value spanning several lines stays inside its quoted scalar instead of adding keys to the step
+	injected := strings.Replace(workflow, "value: ${{ inputs.labels }}", "value: \"${{ inputs.labels }}\\nGH_AW_INJECTED: ${{ inputs.labels }}\"", 1)
+	require.NoError(t, os.WriteFile(workflowPath, []byte(injected), 0644))
+	require.NoError(t, compiler.CompileWorkflow(workflowPath), "Workflow with a multi-line value should compile")
+	lockContent, err = os.ReadFile(stringutil.MarkdownToLockFile(workflowPath))
+	require.NoError(t, err, "Lock file should be written")
+	assert.Contains(t, string(lockContent), `GH_AW_PROMPT_VAR_LABELS: "${{ inputs.labels }}\nGH_AW_INJECTED: ${{ inputs.labels }}"`, "Multi-line value should be escaped")
+	assert.NotContains(t, string(lockContent), "\n          GH_AW_INJECTED:", "Multi-line value should not add environment variables")
+	var parsedLock map[string]any
+	require.NoError(t, yaml.Unmarshal(lockContent, &parsedLock), "Lock file should be valid YAML")
+	require.NoError(t, os.WriteFile(workflowPath, []byte(workflow), 0644))
+
+	// A reference to a variable that is not declared is a compile error, even inside a shared partial
+	shared = strings.Replace(shared, "{{var.focus}}", "{{var.area}}", 1)
+	require.NoError(t, os.WriteFile(filepath.Join(workflowsDir, "shared", "checklist.md"), []byte(shared), 0644))
+	err = compiler.CompileWorkflow(workflowPath)
+	require.Error(t, err, "Undeclared variable in shared partial should fail compilation")
+	assert.Contains(t, err.Error(), "shared/checklist.md: prompt variable 'area' is not declared", "Error should point at the shared file")
+}
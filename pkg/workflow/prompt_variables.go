@@ -0,0 +1,138 @@
+package workflow
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/github/gh-aw/pkg/logger"
+)
+
+var promptVariablesLog = logger.New("workflow:prompt_variables")
+
+// PromptVariable is a typed variable declared under prompt-variables in the frontmatter.
+// Variables are referenced from the prompt body as {{var.<name>}}, iterated with
+// {{#each var.<name>}} and tested with {{#if var.<name>}}.
+type PromptVariable struct {
+	Name        string
+	Type        string // string, number, boolean, array or object (default: string)
+	Description string
+	Default     any    // value used when Value is unset or evaluates to an empty string
+	Value       string // optional GitHub Actions expression evaluated at runtime (e.g. ${{ inputs.labels }})
+}
+
+// promptVariableTypes lists the supported prompt variable types
+var promptVariableTypes = []string{"string", "number", "boolean", "array", "object"}
+
+// extractPromptVariables extracts the prompt-variables section from frontmatter.
+// A variable can be declared as an object with type/description/default/value or,
+// as a shorthand, directly as its default value (the type is inferred).
+// Type errors are reported by validatePromptVariables.
+func (c *Compiler) extractPromptVariables(frontmatter map[string]any) []*PromptVariable {
+	variablesValue, exists := frontmatter["prompt-variables"]
+	if !exists || variablesValue == nil {
+		return nil
+	}
+
+	variablesMap, ok := variablesValue.(map[string]any)
+	if !ok {
+		return nil
+	}
+
+	variables := make([]*PromptVariable, 0, len(variablesMap))
+	for name, value := range variablesMap {
+		variable := &PromptVariable{Name: name}
+
+		if config, ok := value.(map[string]any); ok && isPromptVariableDeclaration(config) {
+			if typeStr, ok := config["type"].(string); ok {
+				variable.Type = typeStr
+			}
+			if description, ok := config["description"].(string); ok {
+				variable.Description = description
+			}
+			if valueStr, ok := config["value"].(string); ok {
+				variable.Value = valueStr
+			}
+			variable.Default = config["default"]
+		} else {
+			variable.Default = value
+			variable.Type = inferPromptVariableType(value)
+		}
+
+		if variable.Type == "" {
+			variable.Type = "string"
+		}
+		variables = append(variables, variable)
+	}
+
+	sort.Slice(variables, func(i, j int) bool {
+		return variables[i].Name < variables[j].Name
+	})
+
+	promptVariablesLog.Printf("Extracted %d prompt variable(s)", len(variables))
+	return variables
+}
+
+// isPromptVariableDeclaration reports whether an object is a variable declaration
+// rather than the default value of an object variable given in shorthand form
+func isPromptVariableDeclaration(config map[string]any) bool {
+	for key := range config {
+		switch key {
+		case "type", "description", "default", "value":
+		default:
+			return false
+		}
+	}
+	return len(config) > 0
+}
+
+// inferPromptVariableType returns the prompt variable type matching a YAML value
+func inferPromptVariableType(value any) string {
+	switch value.(type) {
+	case bool:
+		return "boolean"
+	case int, int64, uint64, float64:
+		return "number"
+	case []any:
+		return "array"
+	case map[string]any:
+		return "object"
+	default:
+		return "string"
+	}
+}
+
+// promptVariableEnvName returns the environment variable that carries the runtime value of a variable
+func promptVariableEnvName(name string) string {
+	return "GH_AW_PROMPT_VAR_" + strings.ToUpper(strings.ReplaceAll(name, "-", "_"))
+}
+
+// promptVariablesJSON encodes the variable declarations for the interpolation step.
+// Each entry carries the type, the default value and, when the variable has a runtime
+// value expression, the name of the environment variable holding the evaluated value.
+func promptVariablesJSON(variables []*PromptVariable) (string, error) {
+	type promptVariableJSON struct {
+		Type    string `json:"type"`
+		Default any    `json:"default,omitempty"`
+		Env     string `json:"env,omitempty"`
+	}
+
+	declarations := make(map[string]promptVariableJSON, len(variables))
+	for _, variable := range variables {
+		declaration := promptVariableJSON{
+			Type:    variable.Type,
+			Default: variable.Default,
+		}
+		if variable.Value != "" {
+			declaration.Env = promptVariableEnvName(variable.Name)
+		}
+		declarations[variable.Name] = declaration
+	}
+
+	encoded, err := json.Marshal(declarations)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode prompt variables: %w", err)
+	}
+	return string(encoded), nil
+}
@@ -0,0 +1,395 @@
+// This file provides signed provenance statements for compiled lock files.
+//
+// When a signing key is configured, the compiler writes an in-toto statement with a
+// SLSA provenance predicate next to each lock file. The statement binds the SHA-256
+// digest of the lock file to the inputs it was compiled from:
+//
+//   - the source markdown and local imports (SHA-256 of their content)
+//   - remote imports (SHA-256 of their content and the commit they were resolved to)
+//   - the actions pinned in the lock file (commit SHA)
+//   - the gh-aw version that compiled it
+//
+// The statement is wrapped in a DSSE envelope signed with an Ed25519 key. It contains no
+// timestamps and Ed25519 signatures are deterministic, so recompiling unchanged inputs
+// produces an identical provenance file.
+
+package workflow
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/github/gh-aw/pkg/logger"
+)
+
+var provenanceLog = logger.New("workflow:provenance")
+
+const (
+	// ProvenanceStatementType is the in-toto statement type of provenance statements
+	ProvenanceStatementType = "https://in-toto.io/Statement/v1"
+	// ProvenancePredicateType is the SLSA provenance predicate type
+	ProvenancePredicateType = "https://slsa.dev/provenance/v1"
+	// ProvenanceBuildType identifies gh-aw compilation as the build that produced a lock file
+	ProvenanceBuildType = "https://github.com/github/gh-aw/compile/v1"
+	// ProvenanceBuilderID identifies the gh-aw compiler as the builder
+	ProvenanceBuilderID = "https://github.com/github/gh-aw"
+	// ProvenancePayloadType is the DSSE payload type of in-toto statements
+	ProvenancePayloadType = "application/vnd.in-toto+json"
+
+	// Kinds of resolved dependencies recorded in the provenance predicate
+	ProvenanceKindSource = "source"
+	ProvenanceKindImport = "import"
+	ProvenanceKindAction = "action"
+)
+
+// ProvenanceStatement is an in-toto statement describing how a lock file was compiled
+type ProvenanceStatement struct {
+	Type          string               `json:"_type"`
+	Subject       []ProvenanceResource `json:"subject"`
+	PredicateType string               `json:"predicateType"`
+	Predicate     ProvenancePredicate  `json:"predicate"`
+}
+
+// ProvenanceResource is an in-toto resource descriptor
+type ProvenanceResource struct {
+	Name        string            `json:"name"`
+	URI         string            `json:"uri,omitempty"`
+	Digest      map[string]string `json:"digest"`
+	Annotations map[string]string `json:"annotations,omitempty"` // "kind" is source, import or action
+}
+
+// ProvenancePredicate is a SLSA v1 provenance predicate
+type ProvenancePredicate struct {
+	BuildDefinition ProvenanceBuildDefinition `json:"buildDefinition"`
+	RunDetails      ProvenanceRunDetails      `json:"runDetails"`
+}
+
+// ProvenanceBuildDefinition describes the inputs of the compilation
+type ProvenanceBuildDefinition struct {
+	BuildType            string                    `json:"buildType"`
+	ExternalParameters   ProvenanceBuildParameters `json:"externalParameters"`
+	ResolvedDependencies []ProvenanceResource      `json:"resolvedDependencies"`
+}
+
+// ProvenanceBuildParameters are the parameters the compilation was invoked with
+type ProvenanceBuildParameters struct {
+	Workflow string `json:"workflow"`         // Source markdown path relative to the repository root
+	Target   string `json:"target,omitempty"` // Compile target (ghes:<version>), empty for github.com
+}
+
+// ProvenanceRunDetails describes the compiler that produced the lock file
+type ProvenanceRunDetails struct {
+	Builder ProvenanceBuilder `json:"builder"`
+}
+
+// ProvenanceBuilder identifies the compiler and its version
+type ProvenanceBuilder struct {
+	ID      string            `json:"id"`
+	Version map[string]string `json:"version"`
+}
+
+// ProvenanceEnvelope is a DSSE envelope holding a signed provenance statement
+type ProvenanceEnvelope struct {
+	PayloadType string                `json:"payloadType"`
+	Payload     string                `json:"payload"` // base64-encoded statement
+	Signatures  []ProvenanceSignature `json:"signatures"`
+}
+
+// ProvenanceSignature is a DSSE signature
+type ProvenanceSignature struct {
+	KeyID string `json:"keyid"`
+	Sig   string `json:"sig"` // base64-encoded Ed25519 signature
+}
+
+// ProvenanceFileForLockFile returns the path of the provenance file written next to a lock file
+// (e.g., "triage.lock.yml" -> "triage.provenance.json")
+func ProvenanceFileForLockFile(lockFile string) string {
+	return strings.TrimSuffix(lockFile, ".lock.yml") + ".provenance.json"
+}
+
+// LoadProvenanceSigningKey reads an Ed25519 private key from a PKCS#8 PEM file
+// (as generated by `openssl genpkey -algorithm ed25519`)
+func LoadProvenanceSigningKey(path string) (ed25519.PrivateKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read signing key: %w", err)
+	}
+	block, _ := pem.Decode(data)
+	if block == nil || block.Type != "PRIVATE KEY" {
+		return nil, fmt.Errorf("signing key %s is not a PEM-encoded PKCS#8 private key", path)
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse signing key %s: %w", path, err)
+	}
+	privateKey, ok := key.(ed25519.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("signing key %s is not an Ed25519 key", path)
+	}
+	return privateKey, nil
+}
+
+// LoadProvenanceVerificationKey reads an Ed25519 public key from a PEM file. A private key
+// file is also accepted, in which case its public key is used.
+func LoadProvenanceVerificationKey(path string) (ed25519.PublicKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read verification key: %w", err)
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("verification key %s is not PEM-encoded", path)
+	}
+	if block.Type == "PRIVATE KEY" {
+		privateKey, err := LoadProvenanceSigningKey(path)
+		if err != nil {
+			return nil, err
+		}
+		return privateKey.Public().(ed25519.PublicKey), nil
+	}
+	if block.Type != "PUBLIC KEY" {
+		return nil, fmt.Errorf("verification key %s is not a PEM-encoded public key", path)
+	}
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse verification key %s: %w", path, err)
+	}
+	publicKey, ok := key.(ed25519.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("verification key %s is not an Ed25519 key", path)
+	}
+	return publicKey, nil
+}
+
+// ProvenanceKeyID returns the identifier of a public key: the SHA-256 of its PKIX encoding
+func ProvenanceKeyID(publicKey ed25519.PublicKey) string {
+	der, err := x509.MarshalPKIXPublicKey(publicKey)
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(der)
+	return hex.EncodeToString(sum[:])
+}
+
+// SignProvenance signs a provenance statement and wraps it in a DSSE envelope
+func SignProvenance(statement *ProvenanceStatement, key ed25519.PrivateKey) (*ProvenanceEnvelope, error) {
+	payload, err := json.Marshal(statement)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal provenance statement: %w", err)
+	}
+	signature := ed25519.Sign(key, dssePreAuthEncoding(ProvenancePayloadType, payload))
+	return &ProvenanceEnvelope{
+		PayloadType: ProvenancePayloadType,
+		Payload:     base64.StdEncoding.EncodeToString(payload),
+		Signatures: []ProvenanceSignature{{
+			KeyID: ProvenanceKeyID(key.Public().(ed25519.PublicKey)),
+			Sig:   base64.StdEncoding.EncodeToString(signature),
+		}},
+	}, nil
+}
+
+// VerifyProvenance checks the envelope signature with the given public key and returns the signed statement
+func VerifyProvenance(envelope *ProvenanceEnvelope, publicKey ed25519.PublicKey) (*ProvenanceStatement, error) {
+	if envelope.PayloadType != ProvenancePayloadType {
+		return nil, fmt.Errorf("unsupported payload type %q", envelope.PayloadType)
+	}
+	payload, err := base64.StdEncoding.DecodeString(envelope.Payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode provenance payload: %w", err)
+	}
+
+	keyID := ProvenanceKeyID(publicKey)
+	message := dssePreAuthEncoding(envelope.PayloadType, payload)
+	verified := false
+	for _, signature := range envelope.Signatures {
+		if signature.KeyID != "" && signature.KeyID != keyID {
+			continue
+		}
+		sig, err := base64.StdEncoding.DecodeString(signature.Sig)
+		if err == nil && ed25519.Verify(publicKey, message, sig) {
+			verified = true
+			break
+		}
+	}
+	if !verified {
+		return nil, errors.New("no valid signature for the verification key")
+	}
+
+	var statement ProvenanceStatement
+	if err := json.Unmarshal(payload, &statement); err != nil {
+		return nil, fmt.Errorf("failed to parse provenance statement: %w", err)
+	}
+	if statement.Type != ProvenanceStatementType || statement.PredicateType != ProvenancePredicateType {
+		return nil, fmt.Errorf("unsupported provenance statement type %q with predicate %q", statement.Type, statement.PredicateType)
+	}
+	return &statement, nil
+}
+
+// dssePreAuthEncoding returns the DSSE pre-authentication encoding that is signed
+func dssePreAuthEncoding(payloadType string, payload []byte) []byte {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "DSSEv1 %d %s %d ", len(payloadType), payloadType, len(payload))
+	buf.Write(payload)
+	return buf.Bytes()
+}
+
+// SHA256Digest returns the hex-encoded SHA-256 digest of content
+func SHA256Digest(content []byte) string {
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
+
+// SetProvenanceKey configures the key used to sign a provenance statement for each lock file.
+// When nil (the default), no provenance is written.
+func (c *Compiler) SetProvenanceKey(key ed25519.PrivateKey) {
+	c.provenanceKey = key
+}
+
+// buildProvenanceStatement describes the compilation of a lock file from its inputs
+func (c *Compiler) buildProvenanceStatement(data *WorkflowData, markdownPath, lockFile, yamlContent string) (*ProvenanceStatement, error) {
+	source := []byte(c.contentOverride)
+	if c.contentOverride == "" {
+		content, err := os.ReadFile(markdownPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read source workflow: %w", err)
+		}
+		source = content
+	}
+
+	sourceName := c.repositoryRelativePath(markdownPath)
+	dependencies := []ProvenanceResource{{
+		Name:        sourceName,
+		Digest:      map[string]string{"sha256": SHA256Digest(source)},
+		Annotations: map[string]string{"kind": ProvenanceKindSource},
+	}}
+
+	for _, importPath := range data.ImportedFiles {
+		resolvedPath, ok := data.ImportedFilePaths[importPath]
+		if !ok {
+			continue
+		}
+		content, err := os.ReadFile(resolvedPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read import %s: %w", importPath, err)
+		}
+		dependency := ProvenanceResource{
+			Name:        c.repositoryRelativePath(resolvedPath),
+			Digest:      map[string]string{"sha256": SHA256Digest(content)},
+			Annotations: map[string]string{"kind": ProvenanceKindImport},
+		}
+		if c.importCache != nil {
+			if ref, ok := c.importCache.RefForCachedPath(resolvedPath); ok {
+				dependency.Name = ref.Spec()
+				dependency.URI = fmt.Sprintf("git+https://github.com/%s/%s@%s#%s", ref.Owner, ref.Repo, ref.SHA, ref.Path)
+				dependency.Digest["gitCommit"] = ref.SHA
+			}
+		}
+		dependencies = append(dependencies, dependency)
+	}
+
+	var actions []ProvenanceResource
+	seen := make(map[string]bool)
+	for _, match := range pinnedActionPattern.FindAllStringSubmatch(yamlContent, -1) {
+		repo, sha, version := match[1], match[2], match[3]
+		if seen[repo+"@"+sha] {
+			continue
+		}
+		seen[repo+"@"+sha] = true
+		name := repo
+		if version != "" {
+			name = repo + "@" + version
+		}
+		actions = append(actions, ProvenanceResource{
+			Name:        name,
+			URI:         fmt.Sprintf("git+https://github.com/%s@%s", repo, sha),
+			Digest:      map[string]string{"gitCommit": sha},
+			Annotations: map[string]string{"kind": ProvenanceKindAction},
+		})
+	}
+	sort.Slice(actions, func(i, j int) bool { return actions[i].URI < actions[j].URI })
+	dependencies = append(dependencies, actions...)
+
+	parameters := ProvenanceBuildParameters{Workflow: sourceName}
+	if c.target.IsGHES() {
+		parameters.Target = c.target.String()
+	}
+
+	provenanceLog.Printf("Built provenance for %s: %d dependencies", lockFile, len(dependencies))
+	return &ProvenanceStatement{
+		Type: ProvenanceStatementType,
+		Subject: []ProvenanceResource{{
+			Name:   c.repositoryRelativePath(lockFile),
+			Digest: map[string]string{"sha256": SHA256Digest([]byte(yamlContent))},
+		}},
+		PredicateType: ProvenancePredicateType,
+		Predicate: ProvenancePredicate{
+			BuildDefinition: ProvenanceBuildDefinition{
+				BuildType:            ProvenanceBuildType,
+				ExternalParameters:   parameters,
+				ResolvedDependencies: dependencies,
+			},
+			RunDetails: ProvenanceRunDetails{
+				Builder: ProvenanceBuilder{
+					ID:      ProvenanceBuilderID,
+					Version: map[string]string{"gh-aw": c.version},
+				},
+			},
+		},
+	}, nil
+}
+
+// writeProvenance signs and writes the provenance statement of a lock file
+func (c *Compiler) writeProvenance(data *WorkflowData, markdownPath, lockFile, yamlContent string) error {
+	statement, err := c.buildProvenanceStatement(data, markdownPath, lockFile, yamlContent)
+	if err != nil {
+		return formatCompilerError(markdownPath, "error", fmt.Sprintf("failed to build provenance: %v", err), err)
+	}
+	envelope, err := SignProvenance(statement, c.provenanceKey)
+	if err != nil {
+		return formatCompilerError(markdownPath, "error", fmt.Sprintf("failed to sign provenance: %v", err), err)
+	}
+	content, err := json.MarshalIndent(envelope, "", "  ")
+	if err != nil {
+		return formatCompilerError(markdownPath, "error", fmt.Sprintf("failed to marshal provenance: %v", err), err)
+	}
+	content = append(content, '\n')
+
+	provenanceFile := ProvenanceFileForLockFile(lockFile)
+	if existing, err := os.ReadFile(provenanceFile); err == nil && bytes.Equal(existing, content) {
+		provenanceLog.Print("Provenance unchanged - skipping write to preserve timestamp")
+		return nil
+	}
+	if err := os.WriteFile(provenanceFile, content, 0644); err != nil {
+		return formatCompilerError(provenanceFile, "error", fmt.Sprintf("failed to write provenance: %v", err), err)
+	}
+	if c.fileTracker != nil {
+		c.fileTracker.TrackCreated(provenanceFile)
+	}
+	provenanceLog.Printf("Wrote provenance to %s", provenanceFile)
+	return nil
+}
+
+// repositoryRelativePath returns path relative to the git root with forward slashes,
+// or the cleaned path when it is outside the repository
+func (c *Compiler) repositoryRelativePath(path string) string {
+	if c.gitRoot != "" {
+		if absPath, err := filepath.Abs(path); err == nil {
+			if relPath, err := filepath.Rel(c.gitRoot, absPath); err == nil && !strings.HasPrefix(relPath, "..") {
+				return filepath.ToSlash(relPath)
+			}
+		}
+	}
+	return filepath.ToSlash(filepath.Clean(path))
+}
@@ -0,0 +1,164 @@
+//go:build !integration
+
+package workflow
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeTestProvenanceKey generates an Ed25519 key and writes it as PKCS#8 and PKIX PEM files
+func writeTestProvenanceKey(t *testing.T) (ed25519.PrivateKey, string, string) {
+	t.Helper()
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	dir := t.TempDir()
+	privateDER, err := x509.MarshalPKCS8PrivateKey(privateKey)
+	require.NoError(t, err)
+	privatePath := filepath.Join(dir, "provenance.key")
+	require.NoError(t, os.WriteFile(privatePath, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: privateDER}), 0600))
+
+	publicDER, err := x509.MarshalPKIXPublicKey(publicKey)
+	require.NoError(t, err)
+	publicPath := filepath.Join(dir, "provenance.pub")
+	require.NoError(t, os.WriteFile(publicPath, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicDER}), 0644))
+
+	return privateKey, privatePath, publicPath
+}
+
+func TestProvenanceFileForLockFile(t *testing.T) {
+	assert.Equal(t, ".github/workflows/triage.provenance.json", ProvenanceFileForLockFile(".github/workflows/triage.lock.yml"))
+}
+
+func TestLoadProvenanceKeys(t *testing.T) {
+	privateKey, privatePath, publicPath := writeTestProvenanceKey(t)
+
+	loaded, err := LoadProvenanceSigningKey(privatePath)
+	require.NoError(t, err)
+	assert.Equal(t, privateKey, loaded)
+
+	publicKey, err := LoadProvenanceVerificationKey(publicPath)
+	require.NoError(t, err)
+	assert.Equal(t, privateKey.Public(), publicKey)
+
+	publicKey, err = LoadProvenanceVerificationKey(privatePath)
+	require.NoError(t, err)
+	assert.Equal(t, privateKey.Public(), publicKey, "the public key should be derived from a private key file")
+
+	_, err = LoadProvenanceSigningKey(publicPath)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "is not a PEM-encoded PKCS#8 private key")
+
+	notPEM := filepath.Join(t.TempDir(), "key.pem")
+	require.NoError(t, os.WriteFile(notPEM, []byte("not a key"), 0600))
+	_, err = LoadProvenanceVerificationKey(notPEM)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "is not PEM-encoded")
+}
+
+func TestSignAndVerifyProvenance(t *testing.T) {
+	privateKey, _, _ := writeTestProvenanceKey(t)
+	statement := &ProvenanceStatement{
+		Type:          ProvenanceStatementType,
+		Subject:       []ProvenanceResource{{Name: "triage.lock.yml", Digest: map[string]string{"sha256": "abc"}}},
+		PredicateType: ProvenancePredicateType,
+	}
+
+	envelope, err := SignProvenance(statement, privateKey)
+	require.NoError(t, err)
+	assert.Equal(t, ProvenancePayloadType, envelope.PayloadType)
+	require.Len(t, envelope.Signatures, 1)
+	assert.Equal(t, ProvenanceKeyID(privateKey.Public().(ed25519.PublicKey)), envelope.Signatures[0].KeyID)
+
+	verified, err := VerifyProvenance(envelope, privateKey.Public().(ed25519.PublicKey))
+	require.NoError(t, err)
+	assert.Equal(t, statement.Subject, verified.Subject)
+
+	otherKey, _, _ := writeTestProvenanceKey(t)
+	_, err = VerifyProvenance(envelope, otherKey.Public().(ed25519.PublicKey))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "no valid signature")
+
+	tampered := *envelope
+	statement.Subject[0].Digest["sha256"] = "def"
+	payload, err := json.Marshal(statement)
+	require.NoError(t, err)
+	tampered.Payload = base64.StdEncoding.EncodeToString(payload)
+	_, err = VerifyProvenance(&tampered, privateKey.Public().(ed25519.PublicKey))
+	require.Error(t, err, "a modified statement should not verify")
+}
+
+func TestCompileWritesProvenance(t *testing.T) {
+	privateKey, _, _ := writeTestProvenanceKey(t)
+	repoDir := t.TempDir()
+	workflowsDir := filepath.Join(repoDir, ".github", "workflows")
+	require.NoError(t, os.MkdirAll(filepath.Join(workflowsDir, "shared"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(workflowsDir, "shared", "tools.md"), []byte("---\ntools:\n  bash: [\"ls\"]\n---\n"), 0644))
+
+	markdownPath := filepath.Join(workflowsDir, "triage.md")
+	source := []byte(`---
+on:
+  issues:
+    types: [opened]
+permissions:
+  contents: read
+  issues: read
+engine: copilot
+imports:
+  - shared/tools.md
+---
+Triage the issue.
+`)
+	require.NoError(t, os.WriteFile(markdownPath, source, 0644))
+
+	compiler := NewCompiler(WithGitRoot(repoDir), WithVersion("v1.2.3"))
+	compiler.SetQuiet(true)
+	compiler.SetProvenanceKey(privateKey)
+	require.NoError(t, compiler.CompileWorkflow(markdownPath))
+
+	data, err := os.ReadFile(filepath.Join(workflowsDir, "triage.provenance.json"))
+	require.NoError(t, err)
+	var envelope ProvenanceEnvelope
+	require.NoError(t, json.Unmarshal(data, &envelope))
+	statement, err := VerifyProvenance(&envelope, privateKey.Public().(ed25519.PublicKey))
+	require.NoError(t, err)
+
+	lockContent, err := os.ReadFile(filepath.Join(workflowsDir, "triage.lock.yml"))
+	require.NoError(t, err)
+	require.Len(t, statement.Subject, 1)
+	assert.Equal(t, ".github/workflows/triage.lock.yml", statement.Subject[0].Name)
+	assert.Equal(t, SHA256Digest(lockContent), statement.Subject[0].Digest["sha256"])
+	assert.Equal(t, ".github/workflows/triage.md", statement.Predicate.BuildDefinition.ExternalParameters.Workflow)
+	assert.Equal(t, "v1.2.3", statement.Predicate.RunDetails.Builder.Version["gh-aw"])
+
+	dependencies := statement.Predicate.BuildDefinition.ResolvedDependencies
+	require.GreaterOrEqual(t, len(dependencies), 3)
+	assert.Equal(t, ProvenanceResource{
+		Name:        ".github/workflows/triage.md",
+		Digest:      map[string]string{"sha256": SHA256Digest(source)},
+		Annotations: map[string]string{"kind": ProvenanceKindSource},
+	}, dependencies[0])
+	assert.Equal(t, ".github/workflows/shared/tools.md", dependencies[1].Name)
+	assert.Equal(t, ProvenanceKindImport, dependencies[1].Annotations["kind"])
+	for _, dependency := range dependencies[2:] {
+		assert.Equal(t, ProvenanceKindAction, dependency.Annotations["kind"])
+		assert.Len(t, dependency.Digest["gitCommit"], 40, "action pins should record the commit SHA")
+	}
+
+	// Recompiling unchanged inputs produces an identical provenance file
+	require.NoError(t, compiler.CompileWorkflow(markdownPath))
+	recompiled, err := os.ReadFile(filepath.Join(workflowsDir, "triage.provenance.json"))
+	require.NoError(t, err)
+	assert.Equal(t, string(data), string(recompiled))
+}
@@ -180,7 +180,7 @@ func generateSafeOutputsAssetsArtifactUpload(builder *strings.Builder, data *Wor
 	builder.WriteString("        if: always()\n")
 	fmt.Fprintf(builder, "        uses: %s\n", GetActionPin("actions/upload-artifact"))
 	builder.WriteString("        with:\n")
-	builder.WriteString("          name: safe-outputs-assets\n")
+	builder.WriteString("          name: " + jobArtifactName(data, "safe-outputs-assets") + "\n")
 	builder.WriteString("          path: /tmp/gh-aw/safeoutputs/assets/\n")
 	builder.WriteString("          retention-days: 1\n")
 	builder.WriteString("          if-no-files-found: ignore\n")
@@ -99,6 +99,12 @@ func (c *Compiler) validateRepositoryFeatures(workflowData *WorkflowData) error
 		return nil
 	}
 
+	// Repository settings are not part of an offline bundle and require the GitHub API
+	if c.IsOffline() {
+		repositoryFeaturesLog.Print("Offline compilation, skipping repository feature validation")
+		return nil
+	}
+
 	repositoryFeaturesLog.Print("Validating repository features for safe-outputs")
 
 	// Get the repository from the current git context
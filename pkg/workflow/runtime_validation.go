@@ -89,44 +89,27 @@ func (c *Compiler) validateExpressionSizes(yamlContent string) error {
 
 // validateContainerImages validates that container images specified in MCP configs exist and are accessible
 func (c *Compiler) validateContainerImages(workflowData *WorkflowData) error {
-	if workflowData.Tools == nil {
-		runtimeValidationLog.Print("No tools configured, skipping container validation")
+	images := extractContainerImages(workflowData)
+	if len(images) == 0 {
+		runtimeValidationLog.Print("No container images configured, skipping container validation")
 		return nil
 	}
 
-	runtimeValidationLog.Printf("Validating container images for %d tools", len(workflowData.Tools))
-	var errors []string
-	for toolName, toolConfig := range workflowData.Tools {
-		if config, ok := toolConfig.(map[string]any); ok {
-			// Get the MCP configuration to extract container info
-			mcpConfig, err := getMCPConfig(config, toolName)
-			if err != nil {
-				// If we can't parse the MCP config, skip validation (will be caught elsewhere)
-				continue
-			}
-
-			// Check if this tool originally had a container field (before transformation)
-			if containerName, hasContainer := config["container"]; hasContainer && mcpConfig.Type == "stdio" {
-				// Build the full container image name with version
-				containerStr, ok := containerName.(string)
-				if !ok {
-					continue
-				}
+	// Offline bundles record the images that were available when the bundle was exported
+	if c.IsOffline() {
+		return c.validateContainerImagesOffline(workflowData)
+	}
 
-				containerImage := containerStr
-				if version, hasVersion := config["version"]; hasVersion {
-					if versionStr, ok := version.(string); ok && versionStr != "" {
-						containerImage = containerImage + ":" + versionStr
-					}
-				}
+	runtimeValidationLog.Printf("Validating %d container images", len(images))
+	var errors []string
+	for _, toolName := range sortedMapKeys(images) {
+		containerImage := images[toolName]
 
-				// Validate the container image exists using docker
-				if err := validateDockerImage(containerImage, c.verbose); err != nil {
-					errors = append(errors, fmt.Sprintf("tool '%s': %v", toolName, err))
-				} else if c.verbose {
-					fmt.Fprintln(os.Stderr, console.FormatInfoMessage("✓ Container image validated: "+containerImage))
-				}
-			}
+		// Validate the container image exists using docker
+		if err := validateDockerImage(containerImage, c.verbose); err != nil {
+			errors = append(errors, fmt.Sprintf("tool '%s': %v", toolName, err))
+		} else if c.verbose {
+			fmt.Fprintln(os.Stderr, console.FormatInfoMessage("✓ Container image validated: "+containerImage))
 		}
 	}
 
@@ -143,9 +126,52 @@ func (c *Compiler) validateContainerImages(workflowData *WorkflowData) error {
 	return nil
 }
 
+// extractContainerImages returns the container images of stdio MCP servers, keyed by tool name
+func extractContainerImages(workflowData *WorkflowData) map[string]string {
+	images := make(map[string]string)
+	for toolName, toolConfig := range workflowData.Tools {
+		config, ok := toolConfig.(map[string]any)
+		if !ok {
+			continue
+		}
+
+		// Get the MCP configuration to extract container info
+		mcpConfig, err := getMCPConfig(config, toolName)
+		if err != nil {
+			// If we can't parse the MCP config, skip validation (will be caught elsewhere)
+			continue
+		}
+
+		// Check if this tool originally had a container field (before transformation)
+		containerName, hasContainer := config["container"]
+		if !hasContainer || mcpConfig.Type != "stdio" {
+			continue
+		}
+		containerStr, ok := containerName.(string)
+		if !ok {
+			continue
+		}
+
+		// Build the full container image name with version
+		containerImage := containerStr
+		if version, hasVersion := config["version"]; hasVersion {
+			if versionStr, ok := version.(string); ok && versionStr != "" {
+				containerImage = containerImage + ":" + versionStr
+			}
+		}
+		images[toolName] = containerImage
+	}
+	return images
+}
+
 // validateRuntimePackages validates that packages required by npx, pip, and uv are available
 func (c *Compiler) validateRuntimePackages(workflowData *WorkflowData) error {
 	// Detect runtime requirements
+	// Offline bundles record the packages that were used when the bundle was exported
+	if c.IsOffline() {
+		return c.validateRuntimePackagesOffline(workflowData)
+	}
+
 	requirements := DetectRuntimeRequirements(workflowData)
 	runtimeValidationLog.Printf("Validating runtime packages: found %d runtime requirements", len(requirements))
 
@@ -15,12 +15,13 @@ var safeInputsGeneratorLog = logger.New("workflow:safe_inputs_generator")
 
 // SafeInputsToolJSON represents a tool configuration for the tools.json file
 type SafeInputsToolJSON struct {
-	Name        string            `json:"name"`
-	Description string            `json:"description"`
-	InputSchema map[string]any    `json:"inputSchema"`
-	Handler     string            `json:"handler,omitempty"`
-	Env         map[string]string `json:"env,omitempty"`
-	Timeout     int               `json:"timeout,omitempty"`
+	Name         string            `json:"name"`
+	Description  string            `json:"description"`
+	InputSchema  map[string]any    `json:"inputSchema"`
+	OutputSchema map[string]any    `json:"outputSchema,omitempty"`
+	Handler      string            `json:"handler,omitempty"`
+	Env          map[string]string `json:"env,omitempty"`
+	Timeout      int               `json:"timeout,omitempty"`
 }
 
 // SafeInputsConfigJSON represents the tools.json configuration file structure
@@ -52,41 +53,7 @@ func generateSafeInputsToolsConfig(safeInputs *SafeInputsConfig) string {
 	for _, toolName := range toolNames {
 		toolConfig := safeInputs.Tools[toolName]
 
-		// Build input schema
-		inputSchema := map[string]any{
-			"type":       "object",
-			"properties": make(map[string]any),
-		}
-
-		props := inputSchema["properties"].(map[string]any)
-		var required []string
-
-		// Sort input names for stable output
-		inputNames := make([]string, 0, len(toolConfig.Inputs))
-		for paramName := range toolConfig.Inputs {
-			inputNames = append(inputNames, paramName)
-		}
-		sort.Strings(inputNames)
-
-		for _, paramName := range inputNames {
-			param := toolConfig.Inputs[paramName]
-			propDef := map[string]any{
-				"type":        param.Type,
-				"description": param.Description,
-			}
-			if param.Default != nil {
-				propDef["default"] = param.Default
-			}
-			props[paramName] = propDef
-			if param.Required {
-				required = append(required, paramName)
-			}
-		}
-
-		sort.Strings(required)
-		if len(required) > 0 {
-			inputSchema["required"] = required
-		}
+		inputSchema := buildSafeInputToolInputSchema(toolConfig)
 
 		// Determine handler path based on script type
 		var handler string
@@ -121,12 +88,13 @@ func generateSafeInputsToolsConfig(safeInputs *SafeInputsConfig) string {
 		}
 
 		config.Tools = append(config.Tools, SafeInputsToolJSON{
-			Name:        toolName,
-			Description: toolConfig.Description,
-			InputSchema: inputSchema,
-			Handler:     handler,
-			Env:         envRefs,
-			Timeout:     toolConfig.Timeout,
+			Name:         toolName,
+			Description:  toolConfig.Description,
+			InputSchema:  inputSchema,
+			OutputSchema: toolConfig.Output,
+			Handler:      handler,
+			Env:          envRefs,
+			Timeout:      toolConfig.Timeout,
 		})
 	}
 
@@ -139,6 +107,100 @@ func generateSafeInputsToolsConfig(safeInputs *SafeInputsConfig) string {
 	return string(jsonBytes)
 }
 
+// buildSafeInputToolInputSchema builds the JSON Schema object describing a tool's inputs
+func buildSafeInputToolInputSchema(toolConfig *SafeInputToolConfig) map[string]any {
+	inputSchema := map[string]any{
+		"type":       "object",
+		"properties": make(map[string]any),
+	}
+
+	props := inputSchema["properties"].(map[string]any)
+	var required []string
+
+	// Sort input names for stable output
+	inputNames := make([]string, 0, len(toolConfig.Inputs))
+	for paramName := range toolConfig.Inputs {
+		inputNames = append(inputNames, paramName)
+	}
+	sort.Strings(inputNames)
+
+	for _, paramName := range inputNames {
+		param := toolConfig.Inputs[paramName]
+		props[paramName] = param.toJSONSchema()
+		if param.Required {
+			required = append(required, paramName)
+		}
+	}
+
+	sort.Strings(required)
+	if len(required) > 0 {
+		inputSchema["required"] = required
+	}
+
+	return inputSchema
+}
+
+// toJSONSchema converts a safe-input parameter into its JSON Schema representation.
+// Nested object properties and array items are converted recursively, and required
+// flags on nested properties are collected into the parent's "required" list.
+func (p *SafeInputParam) toJSONSchema() map[string]any {
+	schema := map[string]any{
+		"type":        p.Type,
+		"description": p.Description,
+	}
+	if p.Default != nil {
+		schema["default"] = p.Default
+	}
+	if len(p.Enum) > 0 {
+		schema["enum"] = p.Enum
+	}
+	if p.Pattern != "" {
+		schema["pattern"] = p.Pattern
+	}
+	if p.Minimum != nil {
+		schema["minimum"] = *p.Minimum
+	}
+	if p.Maximum != nil {
+		schema["maximum"] = *p.Maximum
+	}
+	if p.MinLength != nil {
+		schema["minLength"] = *p.MinLength
+	}
+	if p.MaxLength != nil {
+		schema["maxLength"] = *p.MaxLength
+	}
+	if p.MinItems != nil {
+		schema["minItems"] = *p.MinItems
+	}
+	if p.MaxItems != nil {
+		schema["maxItems"] = *p.MaxItems
+	}
+	if p.Items != nil {
+		items := p.Items.toJSONSchema()
+		// Item schemas have no name, so an empty description adds no information
+		if p.Items.Description == "" {
+			delete(items, "description")
+		}
+		schema["items"] = items
+	}
+	if len(p.Properties) > 0 {
+		props := make(map[string]any, len(p.Properties))
+		var required []string
+		for name, prop := range p.Properties {
+			props[name] = prop.toJSONSchema()
+			if prop.Required {
+				required = append(required, name)
+			}
+		}
+		schema["properties"] = props
+		if len(required) > 0 {
+			sort.Strings(required)
+			schema["required"] = required
+		}
+	}
+	return schema
+}
+
 // generateSafeInputsMCPServerScript generates the entry point script for the safe-inputs MCP server
 // This script uses HTTP transport exclusively
 func generateSafeInputsMCPServerScript(safeInputs *SafeInputsConfig) string {
@@ -356,6 +418,12 @@ func GenerateSafeInputsToolsConfigForInspector(safeInputs *SafeInputsConfig) str
 	return generateSafeInputsToolsConfig(safeInputs)
 }
 
+// GenerateSafeInputToolInputSchemaForInspector builds the JSON Schema for a tool's inputs
+// This is a public wrapper for use by the CLI safe-inputs test command
+func GenerateSafeInputToolInputSchemaForInspector(toolConfig *SafeInputToolConfig) map[string]any {
+	return buildSafeInputToolInputSchema(toolConfig)
+}
+
 // GenerateSafeInputsMCPServerScriptForInspector generates the MCP server entry point script
 // This is a public wrapper for use by the CLI inspector command
 func GenerateSafeInputsMCPServerScriptForInspector(safeInputs *SafeInputsConfig) string {
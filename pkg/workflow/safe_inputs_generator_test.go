@@ -3,6 +3,7 @@
 package workflow
 
 import (
+	"encoding/json"
 	"strings"
 	"testing"
 )
@@ -481,3 +482,65 @@ func TestGenerateSafeInputGoToolScript(t *testing.T) {
 		t.Error("Script should document count parameter access")
 	}
 }
+
+func TestGenerateSafeInputsToolsConfigNestedSchema(t *testing.T) {
+	minimum := 1.0
+	maxItems := 3
+	config := &SafeInputsConfig{
+		Tools: map[string]*SafeInputToolConfig{
+			"triage": {
+				Name:        "triage",
+				Description: "Triage an issue",
+				Script:      "return {};",
+				Inputs: map[string]*SafeInputParam{
+					"labels": {
+						Type:     "array",
+						MaxItems: &maxItems,
+						Items:    &SafeInputParam{Type: "string", Pattern: "^[a-z]+$"},
+					},
+					"options": {
+						Type: "object",
+						Properties: map[string]*SafeInputParam{
+							"limit": {Type: "integer", Minimum: &minimum, Required: true},
+						},
+					},
+				},
+				Output: map[string]any{"type": "object", "required": []any{"count"}},
+			},
+		},
+	}
+
+	toolsJSON := generateSafeInputsToolsConfig(config)
+
+	var parsed SafeInputsConfigJSON
+	if err := json.Unmarshal([]byte(toolsJSON), &parsed); err != nil {
+		t.Fatalf("Failed to parse tools.json: %v", err)
+	}
+	tool := parsed.Tools[0]
+
+	props := tool.InputSchema["properties"].(map[string]any)
+	labels := props["labels"].(map[string]any)
+	if labels["maxItems"] != float64(3) {
+		t.Errorf("Expected maxItems 3, got %v", labels["maxItems"])
+	}
+	items := labels["items"].(map[string]any)
+	if items["pattern"] != "^[a-z]+$" {
+		t.Errorf("Expected items pattern, got %v", items["pattern"])
+	}
+	if _, hasDescription := items["description"]; hasDescription {
+		t.Error("Expected empty item description to be omitted")
+	}
+
+	options := props["options"].(map[string]any)
+	limit := options["properties"].(map[string]any)["limit"].(map[string]any)
+	if limit["minimum"] != float64(1) {
+		t.Errorf("Expected minimum 1, got %v", limit["minimum"])
+	}
+	if required, ok := options["required"].([]any); !ok || len(required) != 1 || required[0] != "limit" {
+		t.Errorf("Expected nested required [limit], got %v", options["required"])
+	}
+
+	if tool.OutputSchema["type"] != "object" {
+		t.Errorf("Expected outputSchema to be emitted, got %v", tool.OutputSchema)
+	}
+}
@@ -36,14 +36,27 @@ type SafeInputToolConfig struct {
 	Go          string                     // Go script implementation (mutually exclusive with Script, Run, and Py)
 	Env         map[string]string          // Environment variables (typically for secrets)
 	Timeout     int                        // Timeout in seconds for tool execution (default: 60)
+	Output      map[string]any             // Optional: JSON Schema the tool result must conform to
 }
 
-// SafeInputParam holds the configuration for a tool input parameter
+// SafeInputParam holds the configuration for a tool input parameter.
+// Object and array parameters may nest further parameters through Properties and Items,
+// so a parameter describes a full JSON Schema rather than a flat scalar.
 type SafeInputParam struct {
-	Type        string // JSON schema type (string, number, boolean, array, object)
-	Description string // Description of the parameter
-	Required    bool   // Whether the parameter is required
-	Default     any    // Default value
+	Type        string                     // JSON schema type (string, number, integer, boolean, array, object)
+	Description string                     // Description of the parameter
+	Required    bool                       // Whether the parameter is required
+	Default     any                        // Default value
+	Enum        []any                      // Allowed values
+	Pattern     string                     // Regular expression string values must match
+	Minimum     *float64                   // Inclusive lower bound for numeric values
+	Maximum     *float64                   // Inclusive upper bound for numeric values
+	MinLength   *int                       // Minimum length for string values
+	MaxLength   *int                       // Maximum length for string values
+	MinItems    *int                       // Minimum number of items for array values
+	MaxItems    *int                       // Maximum number of items for array values
+	Items       *SafeInputParam            // Item schema for array values
+	Properties  map[string]*SafeInputParam // Nested properties for object values
 }
 
 // SafeInputsMode constants define the available transport modes
@@ -95,115 +108,206 @@ func parseSafeInputsMap(safeInputsMap map[string]any) (*SafeInputsConfig, bool)
 			continue
 		}
 
-		toolConfig := &SafeInputToolConfig{
-			Name:    toolName,
-			Inputs:  make(map[string]*SafeInputParam),
-			Env:     make(map[string]string),
-			Timeout: 60, // Default timeout: 60 seconds
+		config.Tools[toolName] = parseSafeInputToolConfig(toolName, toolMap)
+	}
+
+	return config, len(config.Tools) > 0
+}
+
+// parseSafeInputToolConfig parses a single tool definition from the safe-inputs map.
+// It is shared by frontmatter parsing and import merging so both accept the same fields.
+func parseSafeInputToolConfig(toolName string, toolMap map[string]any) *SafeInputToolConfig {
+	toolConfig := &SafeInputToolConfig{
+		Name:    toolName,
+		Inputs:  make(map[string]*SafeInputParam),
+		Env:     make(map[string]string),
+		Timeout: 60, // Default timeout: 60 seconds
+	}
+
+	// Parse description (required)
+	if desc, exists := toolMap["description"]; exists {
+		if descStr, ok := desc.(string); ok {
+			toolConfig.Description = descStr
 		}
+	}
 
-		// Parse description (required)
-		if desc, exists := toolMap["description"]; exists {
-			if descStr, ok := desc.(string); ok {
-				toolConfig.Description = descStr
-			}
+	// Parse inputs (optional)
+	if inputs, exists := toolMap["inputs"]; exists {
+		if inputsMap, ok := inputs.(map[string]any); ok {
+			toolConfig.Inputs = parseSafeInputParams(inputsMap)
+		}
+	}
+
+	// Parse script (JavaScript implementation)
+	if script, exists := toolMap["script"]; exists {
+		if scriptStr, ok := script.(string); ok {
+			toolConfig.Script = scriptStr
 		}
+	}
+
+	// Parse run (shell script implementation)
+	if run, exists := toolMap["run"]; exists {
+		if runStr, ok := run.(string); ok {
+			toolConfig.Run = runStr
+		}
+	}
+
+	// Parse py (Python script implementation)
+	if py, exists := toolMap["py"]; exists {
+		if pyStr, ok := py.(string); ok {
+			toolConfig.Py = pyStr
+		}
+	}
+
+	// Parse go (Go script implementation)
+	if goScript, exists := toolMap["go"]; exists {
+		if goStr, ok := goScript.(string); ok {
+			toolConfig.Go = goStr
+		}
+	}
 
-		// Parse inputs (optional)
-		if inputs, exists := toolMap["inputs"]; exists {
-			if inputsMap, ok := inputs.(map[string]any); ok {
-				for paramName, paramValue := range inputsMap {
-					if paramMap, ok := paramValue.(map[string]any); ok {
-						param := &SafeInputParam{
-							Type: "string", // default type
-						}
-
-						if t, exists := paramMap["type"]; exists {
-							if tStr, ok := t.(string); ok {
-								param.Type = tStr
-							}
-						}
-
-						if desc, exists := paramMap["description"]; exists {
-							if descStr, ok := desc.(string); ok {
-								param.Description = descStr
-							}
-						}
-
-						if req, exists := paramMap["required"]; exists {
-							if reqBool, ok := req.(bool); ok {
-								param.Required = reqBool
-							}
-						}
-
-						if def, exists := paramMap["default"]; exists {
-							param.Default = def
-						}
-
-						toolConfig.Inputs[paramName] = param
-					}
+	// Parse env (environment variables)
+	if env, exists := toolMap["env"]; exists {
+		if envMap, ok := env.(map[string]any); ok {
+			for envName, envValue := range envMap {
+				if envStr, ok := envValue.(string); ok {
+					toolConfig.Env[envName] = envStr
 				}
 			}
 		}
+	}
 
-		// Parse script (JavaScript implementation)
-		if script, exists := toolMap["script"]; exists {
-			if scriptStr, ok := script.(string); ok {
-				toolConfig.Script = scriptStr
-			}
+	// Parse timeout (optional, default is 60 seconds)
+	if timeout, exists := toolMap["timeout"]; exists {
+		switch t := timeout.(type) {
+		case int:
+			toolConfig.Timeout = t
+		case uint64:
+			toolConfig.Timeout = safeUint64ToIntForTimeout(t) // Safe conversion to prevent overflow (alert #414)
+		case float64:
+			toolConfig.Timeout = int(t)
+		case string:
+			// Try to parse string as integer
+			_, _ = fmt.Sscanf(t, "%d", &toolConfig.Timeout)
 		}
+	}
 
-		// Parse run (shell script implementation)
-		if run, exists := toolMap["run"]; exists {
-			if runStr, ok := run.(string); ok {
-				toolConfig.Run = runStr
-			}
+	// Parse output (optional JSON Schema for the tool result)
+	if output, exists := toolMap["output"]; exists {
+		if outputMap, ok := output.(map[string]any); ok {
+			toolConfig.Output = outputMap
 		}
+	}
 
-		// Parse py (Python script implementation)
-		if py, exists := toolMap["py"]; exists {
-			if pyStr, ok := py.(string); ok {
-				toolConfig.Py = pyStr
-			}
+	return toolConfig
+}
+
+// parseSafeInputParams parses a map of parameter definitions (tool inputs or nested object properties)
+func parseSafeInputParams(paramsMap map[string]any) map[string]*SafeInputParam {
+	params := make(map[string]*SafeInputParam)
+	for paramName, paramValue := range paramsMap {
+		if paramMap, ok := paramValue.(map[string]any); ok {
+			params[paramName] = parseSafeInputParam(paramMap)
 		}
+	}
+	return params
+}
 
-		// Parse go (Go script implementation)
-		if goScript, exists := toolMap["go"]; exists {
-			if goStr, ok := goScript.(string); ok {
-				toolConfig.Go = goStr
-			}
+// parseSafeInputParam parses a single parameter definition, recursing into
+// object properties and array items
+func parseSafeInputParam(paramMap map[string]any) *SafeInputParam {
+	param := &SafeInputParam{
+		Type: "string", // default type
+	}
+
+	if t, exists := paramMap["type"]; exists {
+		if tStr, ok := t.(string); ok {
+			param.Type = tStr
 		}
+	}
 
-		// Parse env (environment variables)
-		if env, exists := toolMap["env"]; exists {
-			if envMap, ok := env.(map[string]any); ok {
-				for envName, envValue := range envMap {
-					if envStr, ok := envValue.(string); ok {
-						toolConfig.Env[envName] = envStr
-					}
-				}
-			}
+	if desc, exists := paramMap["description"]; exists {
+		if descStr, ok := desc.(string); ok {
+			param.Description = descStr
 		}
+	}
 
-		// Parse timeout (optional, default is 60 seconds)
-		if timeout, exists := toolMap["timeout"]; exists {
-			switch t := timeout.(type) {
-			case int:
-				toolConfig.Timeout = t
-			case uint64:
-				toolConfig.Timeout = safeUint64ToIntForTimeout(t) // Safe conversion to prevent overflow (alert #414)
-			case float64:
-				toolConfig.Timeout = int(t)
-			case string:
-				// Try to parse string as integer
-				_, _ = fmt.Sscanf(t, "%d", &toolConfig.Timeout)
-			}
+	if req, exists := paramMap["required"]; exists {
+		if reqBool, ok := req.(bool); ok {
+			param.Required = reqBool
 		}
+	}
 
-		config.Tools[toolName] = toolConfig
+	if def, exists := paramMap["default"]; exists {
+		param.Default = def
 	}
 
-	return config, len(config.Tools) > 0
+	if enum, exists := paramMap["enum"]; exists {
+		if enumSlice, ok := enum.([]any); ok {
+			param.Enum = enumSlice
+		}
+	}
+
+	if pattern, exists := paramMap["pattern"]; exists {
+		if patternStr, ok := pattern.(string); ok {
+			param.Pattern = patternStr
+		}
+	}
+
+	param.Minimum = parseSafeInputFloat(paramMap, "minimum")
+	param.Maximum = parseSafeInputFloat(paramMap, "maximum")
+	param.MinLength = parseSafeInputInt(paramMap, "min-length")
+	param.MaxLength = parseSafeInputInt(paramMap, "max-length")
+	param.MinItems = parseSafeInputInt(paramMap, "min-items")
+	param.MaxItems = parseSafeInputInt(paramMap, "max-items")
+
+	if items, exists := paramMap["items"]; exists {
+		if itemsMap, ok := items.(map[string]any); ok {
+			param.Items = parseSafeInputParam(itemsMap)
+		}
+	}
+
+	if properties, exists := paramMap["properties"]; exists {
+		if propertiesMap, ok := properties.(map[string]any); ok {
+			param.Properties = parseSafeInputParams(propertiesMap)
+		}
+	}
+
+	return param
+}
+
+// parseSafeInputFloat extracts an optional numeric constraint from a parameter definition
+func parseSafeInputFloat(paramMap map[string]any, key string) *float64 {
+	value, exists := paramMap[key]
+	if !exists {
+		return nil
+	}
+	var f float64
+	switch v := value.(type) {
+	case int:
+		f = float64(v)
+	case int64:
+		f = float64(v)
+	case uint64:
+		f = float64(v)
+	case float64:
+		f = v
+	default:
+		return nil
+	}
+	return &f
+}
+
+// parseSafeInputInt extracts an optional integer constraint from a parameter definition
+func parseSafeInputInt(paramMap map[string]any, key string) *int {
+	value, exists := paramMap[key]
+	if !exists {
+		return nil
+	}
+	if i, ok := parseIntValue(value); ok {
+		return &i
+	}
+	return nil
 }
 
 // ParseSafeInputs parses safe-inputs configuration from frontmatter (standalone function for testing)
@@ -291,107 +395,7 @@ func (c *Compiler) mergeSafeInputs(main *SafeInputsConfig, importedConfigs []str
 				continue
 			}
 
-			toolConfig := &SafeInputToolConfig{
-				Name:    toolName,
-				Inputs:  make(map[string]*SafeInputParam),
-				Env:     make(map[string]string),
-				Timeout: 60, // Default timeout: 60 seconds
-			}
-
-			// Parse description
-			if desc, exists := toolMap["description"]; exists {
-				if descStr, ok := desc.(string); ok {
-					toolConfig.Description = descStr
-				}
-			}
-
-			// Parse inputs
-			if inputs, exists := toolMap["inputs"]; exists {
-				if inputsMap, ok := inputs.(map[string]any); ok {
-					for paramName, paramValue := range inputsMap {
-						if paramMap, ok := paramValue.(map[string]any); ok {
-							param := &SafeInputParam{
-								Type: "string",
-							}
-							if t, exists := paramMap["type"]; exists {
-								if tStr, ok := t.(string); ok {
-									param.Type = tStr
-								}
-							}
-							if desc, exists := paramMap["description"]; exists {
-								if descStr, ok := desc.(string); ok {
-									param.Description = descStr
-								}
-							}
-							if req, exists := paramMap["required"]; exists {
-								if reqBool, ok := req.(bool); ok {
-									param.Required = reqBool
-								}
-							}
-							if def, exists := paramMap["default"]; exists {
-								param.Default = def
-							}
-							toolConfig.Inputs[paramName] = param
-						}
-					}
-				}
-			}
-
-			// Parse script
-			if script, exists := toolMap["script"]; exists {
-				if scriptStr, ok := script.(string); ok {
-					toolConfig.Script = scriptStr
-				}
-			}
-
-			// Parse run
-			if run, exists := toolMap["run"]; exists {
-				if runStr, ok := run.(string); ok {
-					toolConfig.Run = runStr
-				}
-			}
-
-			// Parse py
-			if py, exists := toolMap["py"]; exists {
-				if pyStr, ok := py.(string); ok {
-					toolConfig.Py = pyStr
-				}
-			}
-
-			// Parse go
-			if goScript, exists := toolMap["go"]; exists {
-				if goStr, ok := goScript.(string); ok {
-					toolConfig.Go = goStr
-				}
-			}
-
-			// Parse env
-			if env, exists := toolMap["env"]; exists {
-				if envMap, ok := env.(map[string]any); ok {
-					for envName, envValue := range envMap {
-						if envStr, ok := envValue.(string); ok {
-							toolConfig.Env[envName] = envStr
-						}
-					}
-				}
-			}
-
-			// Parse timeout (optional, default is 60 seconds)
-			if timeout, exists := toolMap["timeout"]; exists {
-				switch t := timeout.(type) {
-				case int:
-					toolConfig.Timeout = t
-				case uint64:
-					toolConfig.Timeout = safeUint64ToIntForTimeout(t) // Safe conversion to prevent overflow (alert #413)
-				case float64:
-					toolConfig.Timeout = int(t)
-				case string:
-					// Try to parse string as integer
-					_, _ = fmt.Sscanf(t, "%d", &toolConfig.Timeout)
-				}
-			}
-
-			main.Tools[toolName] = toolConfig
+			main.Tools[toolName] = parseSafeInputToolConfig(toolName, toolMap)
 			safeInputsLog.Printf("Merged imported safe-input tool: %s", toolName)
 		}
 	}
@@ -467,3 +467,90 @@ func TestParseSafeInputsAndExtractSafeInputsConfigConsistency(t *testing.T) {
 		})
 	}
 }
+
+func TestParseSafeInputsNestedParams(t *testing.T) {
+	frontmatter := map[string]any{
+		"safe-inputs": map[string]any{
+			"triage": map[string]any{
+				"description": "Triage an issue",
+				"script":      "return {};",
+				"inputs": map[string]any{
+					"labels": map[string]any{
+						"type":      "array",
+						"min-items": 1,
+						"max-items": uint64(5),
+						"items": map[string]any{
+							"type":    "string",
+							"pattern": "^[a-z-]+$",
+						},
+					},
+					"options": map[string]any{
+						"type": "object",
+						"properties": map[string]any{
+							"priority": map[string]any{
+								"type":     "string",
+								"enum":     []any{"low", "high"},
+								"required": true,
+							},
+							"limit": map[string]any{
+								"type":    "integer",
+								"minimum": 1,
+								"maximum": 10.5,
+							},
+						},
+					},
+				},
+				"output": map[string]any{
+					"type": "object",
+				},
+			},
+		},
+	}
+
+	config := ParseSafeInputs(frontmatter)
+	if config == nil {
+		t.Fatal("Expected config, got nil")
+	}
+	tool := config.Tools["triage"]
+
+	labels := tool.Inputs["labels"]
+	if labels.MinItems == nil || *labels.MinItems != 1 || labels.MaxItems == nil || *labels.MaxItems != 5 {
+		t.Errorf("Expected min-items 1 and max-items 5, got %v and %v", labels.MinItems, labels.MaxItems)
+	}
+	if labels.Items == nil || labels.Items.Pattern != "^[a-z-]+$" {
+		t.Fatalf("Expected items with pattern, got %+v", labels.Items)
+	}
+
+	options := tool.Inputs["options"]
+	priority := options.Properties["priority"]
+	if priority == nil || !priority.Required || len(priority.Enum) != 2 {
+		t.Errorf("Expected required priority enum with 2 values, got %+v", priority)
+	}
+	limit := options.Properties["limit"]
+	if limit.Minimum == nil || *limit.Minimum != 1 || limit.Maximum == nil || *limit.Maximum != 10.5 {
+		t.Errorf("Expected minimum 1 and maximum 10.5, got %v and %v", limit.Minimum, limit.Maximum)
+	}
+
+	if tool.Output["type"] != "object" {
+		t.Errorf("Expected output schema to be parsed, got %v", tool.Output)
+	}
+}
+
+func TestMergeSafeInputsNestedParams(t *testing.T) {
+	compiler := &Compiler{}
+	imported := `{"lookup":{"description":"Lookup","run":"echo","inputs":{"filter":{"type":"object","properties":{"state":{"type":"string","enum":["open","closed"]}}}},"output":{"type":"object"}}}`
+
+	result := compiler.mergeSafeInputs(nil, []string{imported})
+
+	tool := result.Tools["lookup"]
+	if tool == nil {
+		t.Fatal("Expected imported tool to be merged")
+	}
+	state := tool.Inputs["filter"].Properties["state"]
+	if state == nil || len(state.Enum) != 2 {
+		t.Errorf("Expected nested enum to be merged, got %+v", state)
+	}
+	if tool.Output == nil {
+		t.Error("Expected output schema to be merged")
+	}
+}
@@ -0,0 +1,273 @@
+// This file provides validation for typed safe-inputs tool definitions.
+//
+// # Safe Inputs Validation
+//
+// Safe-input tools declare their inputs as (possibly nested) JSON Schema
+// parameters and may declare a JSON Schema for their output. The frontmatter
+// schema only checks the shape of these declarations; this file checks that
+// they are internally consistent before the tools.json file is generated:
+//
+//   - Regular expression patterns compile
+//   - Range constraints are not inverted (minimum > maximum, etc.)
+//   - items/properties are only used with array/object parameters
+//   - Default values satisfy the constraints of their parameter
+//   - Output schemas compile as JSON Schema and only use keywords the runtime validator enforces
+//
+// ValidateSafeInputValue is also used by the CLI `mcp test-safe-inputs`
+// command to check fixture inputs and tool outputs.
+
+package workflow
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/github/gh-aw/pkg/logger"
+	"github.com/santhosh-tekuri/jsonschema/v6"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+)
+
+var safeInputsValidationLog = logger.New("workflow:safe_inputs_validation")
+
+// validateSafeInputs validates the input and output schemas of every safe-input tool
+func validateSafeInputs(safeInputs *SafeInputsConfig) error {
+	if !HasSafeInputs(safeInputs) {
+		return nil
+	}
+
+	toolNames := make([]string, 0, len(safeInputs.Tools))
+	for toolName := range safeInputs.Tools {
+		toolNames = append(toolNames, toolName)
+	}
+	sort.Strings(toolNames)
+
+	for _, toolName := range toolNames {
+		toolConfig := safeInputs.Tools[toolName]
+		safeInputsValidationLog.Printf("Validating safe-input tool schema: %s", toolName)
+
+		inputNames := make([]string, 0, len(toolConfig.Inputs))
+		for inputName := range toolConfig.Inputs {
+			inputNames = append(inputNames, inputName)
+		}
+		sort.Strings(inputNames)
+
+		for _, inputName := range inputNames {
+			path := fmt.Sprintf("safe-inputs.%s.inputs.%s", toolName, inputName)
+			if err := validateSafeInputParam(path, toolConfig.Inputs[inputName]); err != nil {
+				return err
+			}
+		}
+
+		if toolConfig.Output != nil {
+			if _, err := compileSafeInputSchema(toolConfig.Output); err != nil {
+				return fmt.Errorf("safe-inputs.%s.output is not a valid JSON Schema: %w", toolName, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// validateSafeInputParam validates a single parameter definition and its nested parameters
+func validateSafeInputParam(path string, param *SafeInputParam) error {
+	if param.Pattern != "" {
+		if param.Type != "string" {
+			return fmt.Errorf("%s: 'pattern' can only be used with type 'string', got '%s'", path, param.Type)
+		}
+		if _, err := regexp.Compile(param.Pattern); err != nil {
+			return fmt.Errorf("%s: invalid pattern %q: %w", path, param.Pattern, err)
+		}
+	}
+
+	if param.Minimum != nil && param.Maximum != nil && *param.Minimum > *param.Maximum {
+		return fmt.Errorf("%s: minimum (%v) is greater than maximum (%v)", path, *param.Minimum, *param.Maximum)
+	}
+	if param.MinLength != nil && param.MaxLength != nil && *param.MinLength > *param.MaxLength {
+		return fmt.Errorf("%s: min-length (%d) is greater than max-length (%d)", path, *param.MinLength, *param.MaxLength)
+	}
+	if param.MinItems != nil && param.MaxItems != nil && *param.MinItems > *param.MaxItems {
+		return fmt.Errorf("%s: min-items (%d) is greater than max-items (%d)", path, *param.MinItems, *param.MaxItems)
+	}
+
+	if param.Items != nil {
+		if param.Type != "array" {
+			return fmt.Errorf("%s: 'items' can only be used with type 'array', got '%s'", path, param.Type)
+		}
+		if err := validateSafeInputParam(path+".items", param.Items); err != nil {
+			return err
+		}
+	}
+
+	if len(param.Properties) > 0 {
+		if param.Type != "object" {
+			return fmt.Errorf("%s: 'properties' can only be used with type 'object', got '%s'", path, param.Type)
+		}
+		propNames := make([]string, 0, len(param.Properties))
+		for propName := range param.Properties {
+			propNames = append(propNames, propName)
+		}
+		sort.Strings(propNames)
+		for _, propName := range propNames {
+			if err := validateSafeInputParam(path+".properties."+propName, param.Properties[propName]); err != nil {
+				return err
+			}
+		}
+	}
+
+	if param.Default != nil {
+		if err := ValidateSafeInputValue(param.toJSONSchema(), param.Default); err != nil {
+			return fmt.Errorf("%s: default value does not satisfy the parameter schema: %w", path, err)
+		}
+	}
+
+	return nil
+}
+
+// ValidateSafeInputValue validates a value against a JSON Schema declared for a safe-input tool.
+// Values decoded from YAML are normalized through JSON first so that integer types
+// produced by the YAML parser compare the same way they will at runtime.
+func ValidateSafeInputValue(schema map[string]any, value any) error {
+	compiled, err := compileSafeInputSchema(schema)
+	if err != nil {
+		return err
+	}
+
+	normalized, err := normalizeJSONValue(value)
+	if err != nil {
+		return err
+	}
+
+	if err := compiled.Validate(normalized); err != nil {
+		return formatSafeInputSchemaError(err)
+	}
+	return nil
+}
+
+// supportedSchemaKeywords lists the JSON Schema keywords enforced by the runtime validator
+// (validateAgainstSchema in safe_inputs_validation.cjs), which checks tool inputs and outputs,
+// stage hand-offs and webhook payloads. Annotations that never affect validation are accepted too.
+var supportedSchemaKeywords = map[string]bool{
+	"type": true, "enum": true, "const": true, "pattern": true,
+	"minimum": true, "maximum": true, "minLength": true, "maxLength": true,
+	"minItems": true, "maxItems": true, "items": true,
+	"properties": true, "required": true, "additionalProperties": true,
+	"$schema": true, "$id": true, "$comment": true, "title": true, "description": true,
+	"default": true, "examples": true, "deprecated": true, "readOnly": true, "writeOnly": true,
+}
+
+// compileSafeInputSchema compiles a JSON Schema given as a Go map
+func compileSafeInputSchema(schema map[string]any) (*jsonschema.Schema, error) {
+	doc, err := normalizeJSONValue(schema)
+	if err != nil {
+		return nil, err
+	}
+
+	// A keyword the runtime validator ignores would let values through that the schema rejects
+	if err := checkSupportedSchemaKeywords(doc, ""); err != nil {
+		return nil, err
+	}
+
+	const schemaURL = "safe-input-schema.json"
+	compiler := jsonschema.NewCompiler()
+	if err := compiler.AddResource(schemaURL, doc); err != nil {
+		return nil, fmt.Errorf("failed to add schema resource: %w", err)
+	}
+	return compiler.Compile(schemaURL)
+}
+
+// checkSupportedSchemaKeywords returns an error for the first keyword of the schema, or of one of
+// its subschemas, that the runtime validator does not enforce
+func checkSupportedSchemaKeywords(schema any, location string) error {
+	pointer := location
+	if pointer == "" {
+		pointer = "/"
+	}
+	obj, ok := schema.(map[string]any)
+	if !ok {
+		return fmt.Errorf("the schema at '%s' must be an object", pointer)
+	}
+
+	keywords := make([]string, 0, len(obj))
+	for keyword := range obj {
+		keywords = append(keywords, keyword)
+	}
+	sort.Strings(keywords)
+
+	for _, keyword := range keywords {
+		if !supportedSchemaKeywords[keyword] {
+			return fmt.Errorf("keyword '%s' at '%s' is not supported: safe-input, hand-off and webhook schemas can only use type, enum, const, pattern, minimum, maximum, minLength, maxLength, minItems, maxItems, items, properties, required and additionalProperties", keyword, pointer)
+		}
+	}
+
+	if items, ok := obj["items"]; ok {
+		if err := checkSupportedSchemaKeywords(items, location+"/items"); err != nil {
+			return err
+		}
+	}
+	if properties, ok := obj["properties"].(map[string]any); ok {
+		names := make([]string, 0, len(properties))
+		for name := range properties {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			if err := checkSupportedSchemaKeywords(properties[name], location+"/properties/"+name); err != nil {
+				return err
+			}
+		}
+	}
+	// additionalProperties may be a boolean, which the runtime validator enforces
+	if additional, ok := obj["additionalProperties"]; ok {
+		if _, isBool := additional.(bool); !isBool {
+			if err := checkSupportedSchemaKeywords(additional, location+"/additionalProperties"); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// normalizeJSONValue round-trips a value through JSON so it only contains JSON types
+func normalizeJSONValue(value any) (any, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode value as JSON: %w", err)
+	}
+	normalized, err := jsonschema.UnmarshalJSON(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode value as JSON: %w", err)
+	}
+	return normalized, nil
+}
+
+// formatSafeInputSchemaError flattens a JSON Schema validation error into a single line
+// listing the innermost failures, which point at the offending value
+func formatSafeInputSchemaError(err error) error {
+	var ve *jsonschema.ValidationError
+	if !errors.As(err, &ve) {
+		return err
+	}
+
+	printer := message.NewPrinter(language.English)
+	var messages []string
+	var collect func(e *jsonschema.ValidationError)
+	collect = func(e *jsonschema.ValidationError) {
+		if len(e.Causes) == 0 {
+			location := "/" + strings.Join(e.InstanceLocation, "/")
+			messages = append(messages, fmt.Sprintf("at '%s': %s", location, e.ErrorKind.LocalizedString(printer)))
+			return
+		}
+		for _, cause := range e.Causes {
+			collect(cause)
+		}
+	}
+	collect(ve)
+
+	return errors.New(strings.Join(messages, "; "))
+}
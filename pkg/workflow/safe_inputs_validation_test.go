@@ -0,0 +1,187 @@
+//go:build !integration
+
+package workflow
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateSafeInputs(t *testing.T) {
+	intPtr := func(i int) *int { return &i }
+	floatPtr := func(f float64) *float64 { return &f }
+
+	tests := []struct {
+		name     string
+		inputs   map[string]*SafeInputParam
+		output   map[string]any
+		errorMsg string
+	}{
+		{
+			name: "valid nested schema",
+			inputs: map[string]*SafeInputParam{
+				"labels": {Type: "array", MaxItems: intPtr(3), Items: &SafeInputParam{Type: "string", Pattern: "^[a-z]+$"}},
+				"options": {Type: "object", Properties: map[string]*SafeInputParam{
+					"limit": {Type: "integer", Minimum: floatPtr(1), Maximum: floatPtr(10), Default: 5},
+				}},
+			},
+			output: map[string]any{"type": "object"},
+		},
+		{
+			name:     "invalid pattern",
+			inputs:   map[string]*SafeInputParam{"query": {Type: "string", Pattern: "(["}},
+			errorMsg: "safe-inputs.tool.inputs.query: invalid pattern",
+		},
+		{
+			name:     "pattern on non-string",
+			inputs:   map[string]*SafeInputParam{"count": {Type: "number", Pattern: "^1$"}},
+			errorMsg: "'pattern' can only be used with type 'string'",
+		},
+		{
+			name:     "inverted range",
+			inputs:   map[string]*SafeInputParam{"count": {Type: "number", Minimum: floatPtr(5), Maximum: floatPtr(1)}},
+			errorMsg: "minimum (5) is greater than maximum (1)",
+		},
+		{
+			name:     "inverted length",
+			inputs:   map[string]*SafeInputParam{"name": {Type: "string", MinLength: intPtr(5), MaxLength: intPtr(2)}},
+			errorMsg: "min-length (5) is greater than max-length (2)",
+		},
+		{
+			name:     "items on non-array",
+			inputs:   map[string]*SafeInputParam{"name": {Type: "string", Items: &SafeInputParam{Type: "string"}}},
+			errorMsg: "'items' can only be used with type 'array'",
+		},
+		{
+			name:     "properties on non-object",
+			inputs:   map[string]*SafeInputParam{"name": {Type: "string", Properties: map[string]*SafeInputParam{"a": {Type: "string"}}}},
+			errorMsg: "'properties' can only be used with type 'object'",
+		},
+		{
+			name: "nested error reports full path",
+			inputs: map[string]*SafeInputParam{"options": {Type: "object", Properties: map[string]*SafeInputParam{
+				"tags": {Type: "array", Items: &SafeInputParam{Type: "string", Pattern: "("}},
+			}}},
+			errorMsg: "safe-inputs.tool.inputs.options.properties.tags.items: invalid pattern",
+		},
+		{
+			name:     "default outside enum",
+			inputs:   map[string]*SafeInputParam{"state": {Type: "string", Enum: []any{"open", "closed"}, Default: "merged"}},
+			errorMsg: "default value does not satisfy the parameter schema",
+		},
+		{
+			name:     "default of wrong type",
+			inputs:   map[string]*SafeInputParam{"count": {Type: "integer", Default: "ten"}},
+			errorMsg: "default value does not satisfy the parameter schema",
+		},
+		{
+			name:     "invalid output schema",
+			output:   map[string]any{"type": "not-a-type"},
+			errorMsg: "safe-inputs.tool.output is not a valid JSON Schema",
+		},
+		{
+			name: "output schema keyword not enforced at runtime",
+			output: map[string]any{"type": "object", "properties": map[string]any{
+				"id": map[string]any{"anyOf": []any{map[string]any{"type": "string"}, map[string]any{"type": "integer"}}},
+			}},
+			errorMsg: "keyword 'anyOf' at '/properties/id' is not supported",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := &SafeInputsConfig{
+				Tools: map[string]*SafeInputToolConfig{
+					"tool": {Name: "tool", Description: "Test tool", Run: "echo", Inputs: tt.inputs, Output: tt.output},
+				},
+			}
+
+			err := validateSafeInputs(config)
+
+			if tt.errorMsg == "" {
+				assert.NoError(t, err, "Expected schema to be valid")
+				return
+			}
+			require.Error(t, err, "Expected validation error")
+			assert.Contains(t, err.Error(), tt.errorMsg, "Error message should describe the problem")
+		})
+	}
+}
+
+func TestValidateSafeInputsNilConfig(t *testing.T) {
+	assert.NoError(t, validateSafeInputs(nil), "Nil config should be valid")
+}
+
+func TestValidateSafeInputValue(t *testing.T) {
+	schema := map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"count": map[string]any{"type": "integer", "maximum": 10},
+			"name":  map[string]any{"type": "string"},
+		},
+		"required": []string{"count", "name"},
+	}
+
+	require.NoError(t, ValidateSafeInputValue(schema, map[string]any{"count": uint64(3), "name": "x"}), "YAML integer types should validate as integers")
+
+	err := ValidateSafeInputValue(schema, map[string]any{"count": 20})
+	require.Error(t, err, "Expected validation error")
+	assert.Contains(t, err.Error(), "missing property 'name'", "Error should report missing property")
+	assert.Contains(t, err.Error(), "at '/count'", "Error should point to the invalid value")
+}
+
+func TestCompileSafeInputSchemaSupportedKeywords(t *testing.T) {
+	tests := []struct {
+		name     string
+		schema   map[string]any
+		errorMsg string
+	}{
+		{
+			name: "supported keywords and annotations",
+			schema: map[string]any{
+				"$schema":              "https://json-schema.org/draft/2020-12/schema",
+				"title":                "Result",
+				"type":                 "object",
+				"required":             []any{"items"},
+				"properties":           map[string]any{"items": map[string]any{"type": "array", "maxItems": 3, "items": map[string]any{"type": "string", "pattern": "^[a-z]+$"}}},
+				"description":          "The result",
+				"additionalProperties": map[string]any{"type": "string", "maxLength": 10},
+			},
+		},
+		{
+			name:     "top-level keyword",
+			schema:   map[string]any{"type": "object", "not": map[string]any{"required": []any{"token"}}},
+			errorMsg: "keyword 'not' at '/' is not supported",
+		},
+		{
+			name:     "keyword in array items",
+			schema:   map[string]any{"type": "array", "items": map[string]any{"type": "string", "format": "email"}},
+			errorMsg: "keyword 'format' at '/items' is not supported",
+		},
+		{
+			name:     "keyword in additional properties",
+			schema:   map[string]any{"type": "object", "additionalProperties": map[string]any{"type": "number", "exclusiveMinimum": 0}},
+			errorMsg: "keyword 'exclusiveMinimum' at '/additionalProperties' is not supported",
+		},
+		{
+			name:     "boolean subschema",
+			schema:   map[string]any{"type": "object", "properties": map[string]any{"token": false}},
+			errorMsg: "the schema at '/properties/token' must be an object",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := compileSafeInputSchema(tt.schema)
+
+			if tt.errorMsg == "" {
+				assert.NoError(t, err, "Expected schema to be accepted")
+				return
+			}
+			require.Error(t, err, "Expected schema to be rejected")
+			assert.Contains(t, err.Error(), tt.errorMsg, "Error should name the unsupported keyword and its location")
+		})
+	}
+}
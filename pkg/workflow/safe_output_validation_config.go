@@ -342,6 +342,13 @@ var ValidationConfig = map[string]TypeValidationConfig{
 			"repo":       {Type: "string", MaxLength: 256}, // Optional: target repository in format "owner/repo"
 		},
 	},
+	"send_webhook": {
+		DefaultMax: 1,
+		Fields: map[string]FieldValidation{
+			"endpoint": {Required: true, Type: "string", MaxLength: 64},
+			"payload":  {Required: true, Type: "object"}, // Validated against the configured schema by the handler
+		},
+	},
 	"missing_data": {
 		DefaultMax: 20,
 		Fields: map[string]FieldValidation{
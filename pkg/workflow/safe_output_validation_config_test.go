@@ -51,6 +51,7 @@ func TestGetValidationConfigJSON(t *testing.T) {
 		"remove_labels",
 		"unassign_from_user",
 		"hide_comment",
+		"send_webhook",
 		"missing_data",
 		"autofix_code_scanning_alert",
 		"mark_pull_request_as_ready_for_review",
@@ -191,6 +192,7 @@ func TestGetDefaultMaxForType(t *testing.T) {
 		{"autofix_code_scanning_alert", 10},
 		{"link_sub_issue", 5},
 		{"hide_comment", 5},
+		{"send_webhook", 1},
 		{"remove_labels", 5},
 		{"update_discussion", 1},
 		{"unassign_from_user", 1},
@@ -291,6 +291,12 @@ func (c *Compiler) extractSafeOutputsConfig(frontmatter map[string]any) *SafeOut
 				config.DispatchWorkflow = dispatchWorkflowConfig
 			}
 
+			// Handle send-webhook
+			sendWebhookConfig := c.parseSendWebhookConfig(outputMap)
+			if sendWebhookConfig != nil {
+				config.SendWebhook = sendWebhookConfig
+			}
+
 			// Handle missing-tool (parse configuration if present, or enable by default)
 			missingToolConfig := c.parseMissingToolConfig(outputMap)
 			if missingToolConfig != nil {
@@ -450,6 +456,11 @@ func (c *Compiler) extractSafeOutputsConfig(frontmatter map[string]any) *SafeOut
 				}
 			}
 
+			// Handle argument constraints of safe output tools
+			if constraints, exists := outputMap["constraints"]; exists {
+				config.Constraints = parseToolConstraints(constraints)
+			}
+
 			// Handle max-bot-mentions (templatable integer)
 			if err := preprocessIntFieldAsString(outputMap, "max-bot-mentions", safeOutputsConfigLog); err != nil {
 				safeOutputsConfigLog.Printf("max-bot-mentions: %v", err)
@@ -471,6 +471,14 @@ func generateSafeOutputsConfig(data *WorkflowData) string {
 				data.SafeOutputs.HideComment.AllowedReasons,
 			)
 		}
+		if data.SafeOutputs.SendWebhook != nil {
+			// Only endpoint names are exposed to the agent; URLs and secrets stay in the safe-outputs job
+			safeOutputsConfig["send_webhook"] = generateSendWebhookConfig(
+				data.SafeOutputs.SendWebhook.Max,
+				1, // default max
+				data.SafeOutputs.SendWebhook.EndpointNames(),
+			)
+		}
 	}
 
 	// Add safe-jobs configuration from SafeOutputs.Jobs
@@ -582,6 +590,13 @@ func generateSafeOutputsConfig(data *WorkflowData) string {
 		}
 	}
 
+	// Add argument constraints to the configuration of each constrained tool
+	for toolName, constraints := range data.SafeOutputs.Constraints {
+		if toolConfig, ok := safeOutputsConfig[toolName].(map[string]any); ok && len(constraints) > 0 {
+			toolConfig["constraints"] = constraints
+		}
+	}
+
 	configJSON, _ := json.Marshal(safeOutputsConfig)
 	safeOutputsConfigLog.Printf("Safe outputs config generation complete: %d tool types configured", len(safeOutputsConfig))
 	return string(configJSON)
@@ -163,6 +163,13 @@ func generateHideCommentConfig(max *string, defaultMax int, allowedReasons []str
 	return config
 }
 
+// generateSendWebhookConfig creates a config with max and the allowed endpoint names
+func generateSendWebhookConfig(max *string, defaultMax int, endpoints []string) map[string]any {
+	config := generateMaxConfig(max, defaultMax)
+	config["endpoints"] = endpoints
+	return config
+}
+
 // generateTargetConfigWithRepos creates a config with target, target-repo, allowed_repos, and optional fields.
 // Note on naming conventions:
 // - "target-repo" uses hyphen to match frontmatter YAML format (key in config.json)
@@ -48,6 +48,7 @@ var safeOutputFieldMapping = map[string]string{
 	"LinkSubIssue":                    "link_sub_issue",
 	"HideComment":                     "hide_comment",
 	"DispatchWorkflow":                "dispatch_workflow",
+	"SendWebhook":                     "send_webhook",
 	"MissingTool":                     "missing_tool",
 	"NoOp":                            "noop",
 	"MarkPullRequestAsReadyForReview": "mark_pull_request_as_ready_for_review",
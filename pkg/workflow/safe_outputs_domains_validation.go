@@ -3,6 +3,7 @@ package workflow
 import (
 	"fmt"
 	"regexp"
+	"sort"
 	"strings"
 
 	"github.com/github/gh-aw/pkg/logger"
@@ -10,9 +11,9 @@ import (
 
 var safeOutputsDomainsValidationLog = logger.New("workflow:safe_outputs_domains_validation")
 
-// validateNetworkAllowedDomains validates the allowed domains in network configuration
+// validateNetworkAllowedDomains validates the allowed domains and custom ecosystems in network configuration
 func (c *Compiler) validateNetworkAllowedDomains(network *NetworkPermissions) error {
-	if network == nil || len(network.Allowed) == 0 {
+	if network == nil || (len(network.Allowed) == 0 && len(network.Ecosystems) == 0) {
 		return nil
 	}
 
@@ -34,9 +35,60 @@ func (c *Compiler) validateNetworkAllowedDomains(network *NetworkPermissions) er
 		}
 	}
 
+	names := make([]string, 0, len(network.Ecosystems))
+	for name := range network.Ecosystems {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if err := validateCustomEcosystem(name, network.Ecosystems[name]); err != nil {
+			if returnErr := collector.Add(err); returnErr != nil {
+				return returnErr // Fail-fast mode
+			}
+		}
+	}
+
 	return collector.Error()
 }
 
+// validateCustomEcosystem validates a custom ecosystem of network.ecosystems. Its name must not
+// shadow a built-in ecosystem, and its domains must be valid patterns whose wildcards are scoped to
+// a registrable domain, so that a shared ecosystem cannot silently open up a whole top-level domain.
+func validateCustomEcosystem(name string, domains []string) error {
+	field := "network.ecosystems." + name
+	if len(getEcosystemDomains(name)) > 0 {
+		return NewValidationError(
+			field,
+			name,
+			fmt.Sprintf("custom ecosystem '%s' shadows a built-in ecosystem", name),
+			"Choose a different name for the custom ecosystem, e.g. 'corp-"+name+"'",
+		)
+	}
+	if len(domains) == 0 {
+		return NewValidationError(
+			field,
+			"",
+			fmt.Sprintf("custom ecosystem '%s' has no domains", name),
+			"List the domains of the ecosystem. Example:\n  network:\n    ecosystems:\n      "+name+":\n        - artifactory.example.com",
+		)
+	}
+	for i, domain := range domains {
+		if err := validateDomainPattern(domain); err != nil {
+			return fmt.Errorf("%s[%d]: %w", field, i, err)
+		}
+		host := strings.TrimPrefix(strings.TrimPrefix(domain, "https://"), "http://")
+		if baseDomain, isWildcard := strings.CutPrefix(host, "*."); isWildcard && !strings.Contains(baseDomain, ".") {
+			return NewValidationError(
+				fmt.Sprintf("%s[%d]", field, i),
+				domain,
+				"wildcard patterns in custom ecosystems must include a registrable domain, not only a top-level domain",
+				"Scope the wildcard to your organization's domain. Examples:\n  - '*.artifactory.example.com' ✓\n  - '*.com' ✗",
+			)
+		}
+	}
+	return nil
+}
+
 // isEcosystemIdentifier checks if a domain string is actually an ecosystem identifier
 func isEcosystemIdentifier(domain string) bool {
 	// Ecosystem identifiers don't contain dots and don't have protocol prefixes
@@ -169,6 +169,7 @@ func ComputePermissionsForSafeOutputs(safeOutputs *SafeOutputsConfig) *Permissio
 
 	// NoOp and MissingTool don't require write permissions beyond what's already included
 	// They only need to comment if add-comment is already configured
+	// SendWebhook posts to external endpoints and needs no GitHub permissions
 
 	safeOutputsPermissionsLog.Printf("Computed permissions with %d scopes", len(permissions.permissions))
 	return permissions
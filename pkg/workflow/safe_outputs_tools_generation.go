@@ -165,6 +165,9 @@ func generateFilteredToolsJSON(data *WorkflowData, markdownPath string) (string,
 	if data.SafeOutputs.HideComment != nil {
 		enabledTools["hide_comment"] = true
 	}
+	if data.SafeOutputs.SendWebhook != nil {
+		enabledTools["send_webhook"] = true
+	}
 	if data.SafeOutputs.UpdateProjects != nil {
 		enabledTools["update_project"] = true
 	}
@@ -197,6 +200,11 @@ func generateFilteredToolsJSON(data *WorkflowData, markdownPath string) (string,
 			// Add repo parameter to inputSchema if allowed-repos has entries
 			addRepoParameterIfNeeded(enhancedTool, toolName, data.SafeOutputs)
 
+			// Restrict send_webhook to the configured endpoints and payload schema
+			if toolName == "send_webhook" {
+				enhancedTool["inputSchema"] = buildSendWebhookInputSchema(data.SafeOutputs.SendWebhook)
+			}
+
 			filteredTools = append(filteredTools, enhancedTool)
 		}
 	}
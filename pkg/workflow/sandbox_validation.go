@@ -3,6 +3,7 @@
 // This file contains domain-specific validation functions for sandbox configuration:
 //   - validateMountsSyntax() - Validates container mount syntax
 //   - validateSandboxConfig() - Validates complete sandbox configuration
+//   - validateMCPGatewayCassette() - Validates MCP gateway record/replay settings
 //
 // These validation functions are organized in a dedicated file following the validation
 // architecture pattern where domain-specific validation belongs in domain validation files.
@@ -12,6 +13,10 @@ package workflow
 
 import (
 	"fmt"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
 
 	"github.com/github/gh-aw/pkg/constants"
 	"github.com/github/gh-aw/pkg/logger"
@@ -115,6 +120,13 @@ func validateSandboxConfig(workflowData *WorkflowData) error {
 		sandboxValidationLog.Printf("Validated MCP gateway port: %d", sandboxConfig.MCP.Port)
 	}
 
+	// Validate MCP gateway record/replay settings
+	if sandboxConfig.MCP != nil {
+		if err := validateMCPGatewayCassette(sandboxConfig.MCP); err != nil {
+			return err
+		}
+	}
+
 	// Validate that if agent sandbox is enabled, MCP gateway is always enabled
 	// The MCP gateway is enabled when MCP servers are configured (tools that use MCP)
 	// Only validate this when sandbox is explicitly configured (not nil)
@@ -144,3 +156,39 @@ func validateSandboxConfig(workflowData *WorkflowData) error {
 
 	return nil
 }
+
+// replayCassettePathPattern matches repository-relative cassette paths that are safe to
+// copy in a shell step
+var replayCassettePathPattern = regexp.MustCompile(`^[A-Za-z0-9._/-]+\.jsonl$`)
+
+// validateMCPGatewayCassette validates the record and replay settings of the MCP gateway.
+// The replay cassette is copied from the checked out repository, so it must be a
+// repository-relative path. Whether the gateway supports the settings is checked when it
+// starts (see requiredMCPGatewaySpecVersion).
+func validateMCPGatewayCassette(mcpConfig *MCPGatewayRuntimeConfig) error {
+	if mcpConfig.Replay == "" {
+		return nil
+	}
+
+	if mcpConfig.Record {
+		return NewConfigurationError(
+			"sandbox.mcp",
+			"replay: "+mcpConfig.Replay,
+			"record and replay cannot be used together",
+			"Record a run first, export its cassette with 'gh aw audit <run-id> --export-cassette <file>', then replace 'record: true' with 'replay: <file>'.",
+		)
+	}
+
+	replay := mcpConfig.Replay
+	if strings.HasPrefix(replay, "/") || !replayCassettePathPattern.MatchString(replay) || slices.Contains(strings.Split(filepath.ToSlash(replay), "/"), "..") {
+		return NewConfigurationError(
+			"sandbox.mcp.replay",
+			replay,
+			"replay must be a repository-relative path",
+			"Store the cassette in the repository, for example:\n\nsandbox:\n  mcp:\n    replay: .github/cassettes/triage.jsonl",
+		)
+	}
+
+	sandboxValidationLog.Printf("Validated MCP gateway replay cassette: %s", replay)
+	return nil
+}
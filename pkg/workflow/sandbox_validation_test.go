@@ -4,6 +4,9 @@ package workflow
 
 import (
 	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 // TestSandboxTypeEnumValidation tests that sandbox type enum values are correctly validated
@@ -75,3 +78,33 @@ func TestSandboxTypeCaseSensitivity(t *testing.T) {
 		})
 	}
 }
+
+// TestValidateMCPGatewayCassette tests the record and replay settings of the MCP gateway
+func TestValidateMCPGatewayCassette(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  *MCPGatewayRuntimeConfig
+		wantErr string
+	}{
+		{name: "record only", config: &MCPGatewayRuntimeConfig{Record: true}},
+		{name: "relative replay path", config: &MCPGatewayRuntimeConfig{Replay: ".github/cassettes/triage.jsonl"}},
+		{name: "record and replay", config: &MCPGatewayRuntimeConfig{Record: true, Replay: "a.jsonl"}, wantErr: "cannot be used together"},
+		{name: "absolute replay path", config: &MCPGatewayRuntimeConfig{Replay: "/tmp/a.jsonl"}, wantErr: "repository-relative path"},
+		{name: "replay path escaping the repository", config: &MCPGatewayRuntimeConfig{Replay: "../a.jsonl"}, wantErr: "repository-relative path"},
+		{name: "replay path with expression", config: &MCPGatewayRuntimeConfig{Replay: "${{ inputs.cassette }}"}, wantErr: "repository-relative path"},
+		{name: "replay path with shell characters", config: &MCPGatewayRuntimeConfig{Replay: "a$(id).jsonl"}, wantErr: "repository-relative path"},
+		{name: "record with pinned version", config: &MCPGatewayRuntimeConfig{Record: true, Version: "v0.1.5"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateMCPGatewayCassette(tt.config)
+			if tt.wantErr == "" {
+				assert.NoError(t, err, "Cassette settings should be valid")
+				return
+			}
+			require.Error(t, err, "Cassette settings should be rejected")
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
@@ -0,0 +1,184 @@
+package workflow
+
+import (
+	"fmt"
+	"maps"
+	"sort"
+	"strings"
+
+	"github.com/github/gh-aw/pkg/logger"
+)
+
+var sendWebhookLog = logger.New("workflow:send_webhook")
+
+// defaultWebhookRetries is the number of retries used when send-webhook does not configure retries
+const defaultWebhookRetries = 3
+
+// SendWebhookConfig holds configuration for sending JSON payloads to allow-listed webhook endpoints
+type SendWebhookConfig struct {
+	BaseSafeOutputConfig `yaml:",inline"`
+	Endpoints            map[string]*WebhookEndpointConfig `yaml:"endpoints,omitempty"` // Named endpoints the agent may send to
+	Schema               map[string]any                    `yaml:"schema,omitempty"`    // JSON Schema the payload must conform to
+	Retries              *int                              `yaml:"retries,omitempty"`   // Retries on network errors, 429 and 5xx responses (default: 3)
+}
+
+// WebhookEndpointConfig holds the URL and optional signing secret of a webhook endpoint
+type WebhookEndpointConfig struct {
+	URL           string `yaml:"url"`                      // HTTPS URL or secrets expression resolving to one
+	SigningSecret string `yaml:"signing-secret,omitempty"` // Secrets expression for the HMAC-SHA256 signing key
+}
+
+// parseSendWebhookConfig handles send-webhook configuration
+func (c *Compiler) parseSendWebhookConfig(outputMap map[string]any) *SendWebhookConfig {
+	sendWebhookLog.Print("Parsing send-webhook configuration")
+	configData, exists := outputMap["send-webhook"]
+	if !exists {
+		return nil
+	}
+
+	sendWebhookConfig := &SendWebhookConfig{Endpoints: make(map[string]*WebhookEndpointConfig)}
+	configMap, ok := configData.(map[string]any)
+	if !ok {
+		sendWebhookConfig.Max = defaultIntStr(1)
+		return sendWebhookConfig
+	}
+
+	if endpoints, ok := configMap["endpoints"].(map[string]any); ok {
+		for name, endpointData := range endpoints {
+			endpoint := &WebhookEndpointConfig{}
+			if endpointMap, ok := endpointData.(map[string]any); ok {
+				if url, ok := endpointMap["url"].(string); ok {
+					endpoint.URL = url
+				}
+				if secret, ok := endpointMap["signing-secret"].(string); ok {
+					endpoint.SigningSecret = secret
+				}
+			}
+			sendWebhookConfig.Endpoints[name] = endpoint
+		}
+	}
+
+	if schema, ok := configMap["schema"].(map[string]any); ok {
+		sendWebhookConfig.Schema = schema
+	}
+
+	if retries, exists := configMap["retries"]; exists {
+		if retriesInt, ok := parseIntValue(retries); ok {
+			sendWebhookConfig.Retries = &retriesInt
+		}
+	}
+
+	// Parse common base fields with default max of 1
+	c.parseBaseSafeOutputConfig(configMap, &sendWebhookConfig.BaseSafeOutputConfig, 1)
+
+	// Cap max at 50 (absolute maximum allowed) – only for literal integer values
+	if maxVal := templatableIntValue(sendWebhookConfig.Max); maxVal > 50 {
+		sendWebhookLog.Printf("Max value %d exceeds limit, capping at 50", maxVal)
+		sendWebhookConfig.Max = defaultIntStr(50)
+	}
+
+	sendWebhookLog.Printf("Parsed send-webhook config: endpoints=%d, has_schema=%v", len(sendWebhookConfig.Endpoints), sendWebhookConfig.Schema != nil)
+	return sendWebhookConfig
+}
+
+// EndpointNames returns the configured endpoint names in sorted order
+func (c *SendWebhookConfig) EndpointNames() []string {
+	names := make([]string, 0, len(c.Endpoints))
+	for name := range c.Endpoints {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// EffectiveRetries returns the configured number of retries or the default
+func (c *SendWebhookConfig) EffectiveRetries() int {
+	if c.Retries != nil {
+		return *c.Retries
+	}
+	return defaultWebhookRetries
+}
+
+// webhookEndpointEnvPrefix returns the prefix of the environment variables that carry an
+// endpoint's URL and signing secret to the safe-outputs job (e.g. GH_AW_WEBHOOK_CHAT_OPS)
+func webhookEndpointEnvPrefix(name string) string {
+	return "GH_AW_WEBHOOK_" + strings.ToUpper(strings.ReplaceAll(name, "-", "_"))
+}
+
+// buildSendWebhookHandlerConfig builds the handler config for send_webhook. Endpoint URLs and
+// signing secrets are passed as environment variables and only referenced here by name, so they
+// never appear in the handler config or the agent-side config.
+func buildSendWebhookHandlerConfig(cfg *SendWebhookConfig, allowedDomains []string) map[string]any {
+	endpoints := make(map[string]map[string]string, len(cfg.Endpoints))
+	for _, name := range cfg.EndpointNames() {
+		prefix := webhookEndpointEnvPrefix(name)
+		endpoint := map[string]string{"url_env": prefix + "_URL"}
+		if cfg.Endpoints[name].SigningSecret != "" {
+			endpoint["secret_env"] = prefix + "_SECRET"
+		}
+		endpoints[name] = endpoint
+	}
+
+	builder := newHandlerConfigBuilder().
+		AddTemplatableInt("max", cfg.Max).
+		AddDefault("endpoints", endpoints).
+		AddStringSlice("allowed_domains", allowedDomains).
+		AddDefault("retries", cfg.EffectiveRetries())
+	if len(cfg.Schema) > 0 {
+		builder.AddDefault("schema", cfg.Schema)
+	}
+	return builder.Build()
+}
+
+// addSendWebhookEnvVars adds the URL and signing secret of every send-webhook endpoint to the
+// handler manager step environment
+func addSendWebhookEnvVars(steps *[]string, cfg *SendWebhookConfig) {
+	for _, name := range cfg.EndpointNames() {
+		endpoint := cfg.Endpoints[name]
+		prefix := webhookEndpointEnvPrefix(name)
+		*steps = append(*steps, "          "+prefix+"_URL: "+webhookEnvValue(endpoint.URL)+"\n")
+		if endpoint.SigningSecret != "" {
+			*steps = append(*steps, "          "+prefix+"_SECRET: "+endpoint.SigningSecret+"\n")
+		}
+	}
+}
+
+// webhookEnvValue renders an endpoint URL as a YAML value, leaving expressions unquoted
+func webhookEnvValue(value string) string {
+	if strings.HasPrefix(value, "${{") {
+		return value
+	}
+	return fmt.Sprintf("%q", value)
+}
+
+// buildSendWebhookInputSchema builds the send_webhook tool input schema, restricting the endpoint
+// to the configured names and describing the payload with the configured schema
+func buildSendWebhookInputSchema(cfg *SendWebhookConfig) map[string]any {
+	payload := map[string]any{
+		"type":        "object",
+		"description": "JSON payload to send to the endpoint.",
+	}
+	if len(cfg.Schema) > 0 {
+		payload = maps.Clone(cfg.Schema)
+		if _, ok := payload["type"]; !ok {
+			payload["type"] = "object"
+		}
+		if _, ok := payload["description"]; !ok {
+			payload["description"] = "JSON payload to send to the endpoint. Must conform to this schema."
+		}
+	}
+
+	return map[string]any{
+		"type":     "object",
+		"required": []string{"endpoint", "payload"},
+		"properties": map[string]any{
+			"endpoint": map[string]any{
+				"type":        "string",
+				"enum":        cfg.EndpointNames(),
+				"description": "Name of the configured endpoint to send the payload to.",
+			},
+			"payload": payload,
+		},
+		"additionalProperties": false,
+	}
+}
@@ -0,0 +1,155 @@
+//go:build !integration
+
+package workflow
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/github/gh-aw/pkg/stringutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSendWebhookConfig(t *testing.T) {
+	compiler := NewCompiler()
+
+	t.Run("full config", func(t *testing.T) {
+		cfg := compiler.parseSendWebhookConfig(map[string]any{
+			"send-webhook": map[string]any{
+				"max":     3,
+				"retries": 5,
+				"endpoints": map[string]any{
+					"ticketing": map[string]any{"url": "https://tickets.example.com/hook"},
+					"chat-ops":  map[string]any{"url": "${{ secrets.SLACK_WEBHOOK_URL }}", "signing-secret": "${{ secrets.SLACK_SIGNING_SECRET }}"},
+				},
+				"schema": map[string]any{"type": "object"},
+			},
+		})
+		require.NotNil(t, cfg)
+		assert.Equal(t, "3", *cfg.Max)
+		assert.Equal(t, 5, cfg.EffectiveRetries())
+		assert.Equal(t, []string{"chat-ops", "ticketing"}, cfg.EndpointNames())
+		assert.Equal(t, "${{ secrets.SLACK_SIGNING_SECRET }}", cfg.Endpoints["chat-ops"].SigningSecret)
+		assert.Equal(t, map[string]any{"type": "object"}, cfg.Schema)
+	})
+
+	t.Run("defaults", func(t *testing.T) {
+		cfg := compiler.parseSendWebhookConfig(map[string]any{
+			"send-webhook": map[string]any{"endpoints": map[string]any{"chat-ops": map[string]any{"url": "https://hooks.example.com"}}},
+		})
+		require.NotNil(t, cfg)
+		assert.Equal(t, "1", *cfg.Max)
+		assert.Equal(t, defaultWebhookRetries, cfg.EffectiveRetries())
+	})
+
+	t.Run("max is capped", func(t *testing.T) {
+		cfg := compiler.parseSendWebhookConfig(map[string]any{"send-webhook": map[string]any{"max": 500}})
+		require.NotNil(t, cfg)
+		assert.Equal(t, "50", *cfg.Max)
+	})
+
+	t.Run("absent", func(t *testing.T) {
+		assert.Nil(t, compiler.parseSendWebhookConfig(map[string]any{}))
+	})
+}
+
+func TestSendWebhookHandlerConfigAndEnv(t *testing.T) {
+	cfg := &SendWebhookConfig{
+		BaseSafeOutputConfig: BaseSafeOutputConfig{Max: defaultIntStr(2)},
+		Endpoints: map[string]*WebhookEndpointConfig{
+			"chat-ops":  {URL: "${{ secrets.SLACK_WEBHOOK_URL }}", SigningSecret: "${{ secrets.SLACK_SIGNING_SECRET }}"},
+			"ticketing": {URL: "https://tickets.example.com/hook"},
+		},
+		Schema: map[string]any{"type": "object", "required": []any{"text"}},
+	}
+
+	handlerConfig := buildSendWebhookHandlerConfig(cfg, []string{"hooks.slack.com", "tickets.example.com"})
+	data, err := json.Marshal(handlerConfig)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"max": 2,
+		"retries": 3,
+		"allowed_domains": ["hooks.slack.com", "tickets.example.com"],
+		"endpoints": {
+			"chat-ops": {"url_env": "GH_AW_WEBHOOK_CHAT_OPS_URL", "secret_env": "GH_AW_WEBHOOK_CHAT_OPS_SECRET"},
+			"ticketing": {"url_env": "GH_AW_WEBHOOK_TICKETING_URL"}
+		},
+		"schema": {"type": "object", "required": ["text"]}
+	}`, string(data))
+
+	var steps []string
+	addSendWebhookEnvVars(&steps, cfg)
+	assert.Equal(t, []string{
+		"          GH_AW_WEBHOOK_CHAT_OPS_URL: ${{ secrets.SLACK_WEBHOOK_URL }}\n",
+		"          GH_AW_WEBHOOK_CHAT_OPS_SECRET: ${{ secrets.SLACK_SIGNING_SECRET }}\n",
+		"          GH_AW_WEBHOOK_TICKETING_URL: \"https://tickets.example.com/hook\"\n",
+	}, steps)
+}
+
+func TestBuildSendWebhookInputSchema(t *testing.T) {
+	cfg := &SendWebhookConfig{
+		Endpoints: map[string]*WebhookEndpointConfig{"ticketing": {}, "chat-ops": {}},
+		Schema:    map[string]any{"properties": map[string]any{"text": map[string]any{"type": "string"}}},
+	}
+
+	schema := buildSendWebhookInputSchema(cfg)
+	properties := schema["properties"].(map[string]any)
+	assert.Equal(t, []string{"chat-ops", "ticketing"}, properties["endpoint"].(map[string]any)["enum"])
+
+	payload := properties["payload"].(map[string]any)
+	assert.Equal(t, "object", payload["type"], "payload should default to an object")
+	assert.Contains(t, payload, "properties")
+	assert.NotContains(t, cfg.Schema, "type", "configured schema should not be modified")
+}
+
+func TestSendWebhookCompiledWorkflow(t *testing.T) {
+	tmpDir := t.TempDir()
+	workflowPath := filepath.Join(tmpDir, "notify.md")
+	content := `---
+on: issues
+engine: copilot
+permissions:
+  contents: read
+safe-outputs:
+  allowed-domains: [hooks.slack.com]
+  send-webhook:
+    max: 2
+    endpoints:
+      chat-ops:
+        url: ${{ secrets.SLACK_WEBHOOK_URL }}
+        signing-secret: ${{ secrets.SLACK_SIGNING_SECRET }}
+    schema:
+      type: object
+      required: [text]
+      properties:
+        text:
+          type: string
+---
+
+# Notify
+
+Post a summary of the issue to chat.
+`
+	require.NoError(t, os.WriteFile(workflowPath, []byte(content), 0644))
+
+	compiler := NewCompiler()
+	require.NoError(t, compiler.CompileWorkflow(workflowPath))
+
+	lockContent, err := os.ReadFile(stringutil.MarkdownToLockFile(workflowPath))
+	require.NoError(t, err)
+	lock := string(lockContent)
+
+	assert.Contains(t, lock, "GH_AW_WEBHOOK_CHAT_OPS_URL: ${{ secrets.SLACK_WEBHOOK_URL }}")
+	assert.Contains(t, lock, "GH_AW_WEBHOOK_CHAT_OPS_SECRET: ${{ secrets.SLACK_SIGNING_SECRET }}")
+	assert.Contains(t, lock, `\"send_webhook\":{`, "handler config should include send_webhook")
+	assert.Contains(t, lock, `"send_webhook":{"endpoints":["chat-ops"],"max":2}`, "agent config should only expose endpoint names")
+
+	// The endpoint secrets must only be exposed to the safe-outputs job
+	safeOutputsJob := lock[strings.Index(lock, "\n  safe_outputs:\n"):]
+	assert.Equal(t, 1, strings.Count(lock, "secrets.SLACK_WEBHOOK_URL"))
+	assert.Contains(t, safeOutputsJob, "secrets.SLACK_WEBHOOK_URL")
+}
@@ -0,0 +1,158 @@
+package workflow
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/github/gh-aw/pkg/logger"
+)
+
+var sendWebhookValidationLog = logger.New("workflow:send_webhook_validation")
+
+// webhookEndpointNamePattern restricts endpoint names to lowercase identifiers so that each name
+// maps to a distinct environment variable
+var webhookEndpointNamePattern = regexp.MustCompile(`^[a-z][a-z0-9-]*$`)
+
+// maxWebhookRetries is the largest number of retries allowed for send-webhook
+const maxWebhookRetries = 10
+
+// validateSendWebhook validates the send-webhook configuration. Literal endpoint URLs must use
+// HTTPS and target a host in safe-outputs.allowed-domains; URLs supplied through secrets can only be
+// checked at runtime, where the handler enforces the same allow-list.
+func (c *Compiler) validateSendWebhook(config *SafeOutputsConfig) error {
+	if config == nil || config.SendWebhook == nil {
+		return nil
+	}
+
+	cfg := config.SendWebhook
+	sendWebhookValidationLog.Printf("Validating send-webhook with %d endpoints", len(cfg.Endpoints))
+
+	if len(cfg.Endpoints) == 0 {
+		return errors.New("safe-outputs.send-webhook: must specify at least one endpoint\n\nExample configuration in workflow frontmatter:\nsafe-outputs:\n  allowed-domains: [hooks.slack.com]\n  send-webhook:\n    endpoints:\n      chat-ops:\n        url: ${{ secrets.SLACK_WEBHOOK_URL }}")
+	}
+
+	if len(config.AllowedDomains) == 0 {
+		return NewValidationError(
+			"safe-outputs.allowed-domains",
+			"",
+			"send-webhook requires safe-outputs.allowed-domains to list the hosts of its endpoints",
+			"Add the webhook hosts to the allow-list. Example:\n  safe-outputs:\n    allowed-domains: [hooks.slack.com]\n    send-webhook:\n      endpoints:\n        chat-ops:\n          url: ${{ secrets.SLACK_WEBHOOK_URL }}",
+		)
+	}
+
+	if cfg.Retries != nil && (*cfg.Retries < 0 || *cfg.Retries > maxWebhookRetries) {
+		return NewValidationError(
+			"safe-outputs.send-webhook.retries",
+			fmt.Sprintf("%d", *cfg.Retries),
+			fmt.Sprintf("retries must be between 0 and %d", maxWebhookRetries),
+			"Use a small number of retries, e.g. 'retries: 3'",
+		)
+	}
+
+	collector := NewErrorCollector(c.failFast)
+
+	for _, name := range cfg.EndpointNames() {
+		if err := validateWebhookEndpoint(name, cfg.Endpoints[name], config.AllowedDomains); err != nil {
+			if returnErr := collector.Add(err); returnErr != nil {
+				return returnErr // Fail-fast mode
+			}
+		}
+	}
+
+	if cfg.Schema != nil {
+		if schemaType, ok := cfg.Schema["type"].(string); ok && schemaType != "object" {
+			err := NewValidationError(
+				"safe-outputs.send-webhook.schema",
+				schemaType,
+				"the payload schema must describe a JSON object",
+				"Set 'type: object' in the schema and describe the payload fields under 'properties'",
+			)
+			if returnErr := collector.Add(err); returnErr != nil {
+				return returnErr // Fail-fast mode
+			}
+		} else if _, err := compileSafeInputSchema(cfg.Schema); err != nil {
+			wrappedErr := fmt.Errorf("safe-outputs.send-webhook.schema is not a valid JSON Schema: %w", err)
+			if returnErr := collector.Add(wrappedErr); returnErr != nil {
+				return returnErr // Fail-fast mode
+			}
+		}
+	}
+
+	return collector.Error()
+}
+
+// validateWebhookEndpoint validates a single send-webhook endpoint
+func validateWebhookEndpoint(name string, endpoint *WebhookEndpointConfig, allowedDomains []string) error {
+	field := "safe-outputs.send-webhook.endpoints." + name
+	if !webhookEndpointNamePattern.MatchString(name) {
+		return NewValidationError(
+			field,
+			name,
+			"endpoint names must start with a lowercase letter and contain only lowercase letters, digits and hyphens",
+			"Rename the endpoint, e.g. 'chat-ops' or 'ticketing'",
+		)
+	}
+
+	if endpoint.URL == "" {
+		return NewValidationError(field+".url", "", "endpoint url is required", "Set the url to an HTTPS URL or a secrets expression, e.g. '${{ secrets.SLACK_WEBHOOK_URL }}'")
+	}
+
+	if strings.Contains(endpoint.URL, "${{") {
+		if !secretsExpressionPattern.MatchString(endpoint.URL) {
+			return NewValidationError(
+				field+".url",
+				endpoint.URL,
+				"endpoint url expressions must reference secrets",
+				"Store the webhook URL in a repository secret and use '${{ secrets.NAME }}'",
+			)
+		}
+	} else if err := validateWebhookURL(endpoint.URL, allowedDomains); err != nil {
+		return NewValidationError(field+".url", endpoint.URL, err.Error(), "Use an HTTPS URL whose host is listed in safe-outputs.allowed-domains")
+	}
+
+	if endpoint.SigningSecret != "" {
+		if err := validateSecretsExpression(endpoint.SigningSecret); err != nil {
+			return fmt.Errorf("%s.signing-secret: %w", field, err)
+		}
+	}
+
+	return nil
+}
+
+// validateWebhookURL checks that a literal webhook URL uses HTTPS and targets an allowed host
+func validateWebhookURL(rawURL string, allowedDomains []string) error {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return fmt.Errorf("invalid url: %w", err)
+	}
+	if parsed.Scheme != "https" {
+		return errors.New("endpoint url must use https")
+	}
+	if parsed.Hostname() == "" {
+		return errors.New("endpoint url must include a host")
+	}
+	if !isWebhookHostAllowed(parsed.Hostname(), allowedDomains) {
+		return fmt.Errorf("host '%s' is not listed in safe-outputs.allowed-domains", parsed.Hostname())
+	}
+	return nil
+}
+
+// isWebhookHostAllowed reports whether a host matches one of the allowed domain patterns.
+// Patterns match the exact host or, with a '*.' prefix, any subdomain. Patterns restricted to
+// http:// never match since webhooks are only sent over HTTPS.
+func isWebhookHostAllowed(host string, allowedDomains []string) bool {
+	host = strings.ToLower(host)
+	for _, pattern := range allowedDomains {
+		if strings.HasPrefix(pattern, "http://") {
+			continue
+		}
+		pattern = strings.ToLower(strings.TrimPrefix(pattern, "https://"))
+		if matchesDomain(host, pattern) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,142 @@
+//go:build !integration
+
+package workflow
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateSendWebhook(t *testing.T) {
+	retries := func(n int) *int { return &n }
+
+	tests := []struct {
+		name           string
+		allowedDomains []string
+		config         *SendWebhookConfig
+		wantErr        string
+	}{
+		{
+			name:           "secrets-backed url",
+			allowedDomains: []string{"hooks.slack.com"},
+			config: &SendWebhookConfig{Endpoints: map[string]*WebhookEndpointConfig{
+				"chat-ops": {URL: "${{ secrets.SLACK_WEBHOOK_URL }}", SigningSecret: "${{ secrets.SLACK_SIGNING_SECRET }}"},
+			}},
+		},
+		{
+			name:           "literal url matching wildcard domain",
+			allowedDomains: []string{"*.example.com"},
+			config: &SendWebhookConfig{Endpoints: map[string]*WebhookEndpointConfig{
+				"ticketing": {URL: "https://tickets.example.com/hooks/1"},
+			}, Schema: map[string]any{"type": "object", "required": []any{"title"}}},
+		},
+		{
+			name:           "no endpoints",
+			allowedDomains: []string{"hooks.slack.com"},
+			config:         &SendWebhookConfig{},
+			wantErr:        "must specify at least one endpoint",
+		},
+		{
+			name:    "missing allowed-domains",
+			config:  &SendWebhookConfig{Endpoints: map[string]*WebhookEndpointConfig{"chat-ops": {URL: "${{ secrets.URL }}"}}},
+			wantErr: "send-webhook requires safe-outputs.allowed-domains",
+		},
+		{
+			name:           "invalid endpoint name",
+			allowedDomains: []string{"hooks.slack.com"},
+			config:         &SendWebhookConfig{Endpoints: map[string]*WebhookEndpointConfig{"Chat_Ops": {URL: "${{ secrets.URL }}"}}},
+			wantErr:        "endpoint names must start with a lowercase letter",
+		},
+		{
+			name:           "missing url",
+			allowedDomains: []string{"hooks.slack.com"},
+			config:         &SendWebhookConfig{Endpoints: map[string]*WebhookEndpointConfig{"chat-ops": {}}},
+			wantErr:        "endpoint url is required",
+		},
+		{
+			name:           "non-secrets expression",
+			allowedDomains: []string{"hooks.slack.com"},
+			config:         &SendWebhookConfig{Endpoints: map[string]*WebhookEndpointConfig{"chat-ops": {URL: "${{ github.event.issue.body }}"}}},
+			wantErr:        "endpoint url expressions must reference secrets",
+		},
+		{
+			name:           "http url",
+			allowedDomains: []string{"hooks.slack.com"},
+			config:         &SendWebhookConfig{Endpoints: map[string]*WebhookEndpointConfig{"chat-ops": {URL: "http://hooks.slack.com/x"}}},
+			wantErr:        "endpoint url must use https",
+		},
+		{
+			name:           "host not allowed",
+			allowedDomains: []string{"hooks.slack.com"},
+			config:         &SendWebhookConfig{Endpoints: map[string]*WebhookEndpointConfig{"chat-ops": {URL: "https://evil.example.com/x"}}},
+			wantErr:        "host 'evil.example.com' is not listed in safe-outputs.allowed-domains",
+		},
+		{
+			name:           "subdomain of plain domain not allowed",
+			allowedDomains: []string{"slack.com"},
+			config:         &SendWebhookConfig{Endpoints: map[string]*WebhookEndpointConfig{"chat-ops": {URL: "https://hooks.slack.com/x"}}},
+			wantErr:        "host 'hooks.slack.com' is not listed",
+		},
+		{
+			name:           "literal signing secret",
+			allowedDomains: []string{"hooks.slack.com"},
+			config: &SendWebhookConfig{Endpoints: map[string]*WebhookEndpointConfig{
+				"chat-ops": {URL: "${{ secrets.URL }}", SigningSecret: "hunter2"},
+			}},
+			wantErr: "signing-secret: invalid secrets expression",
+		},
+		{
+			name:           "retries out of range",
+			allowedDomains: []string{"hooks.slack.com"},
+			config:         &SendWebhookConfig{Endpoints: map[string]*WebhookEndpointConfig{"chat-ops": {URL: "${{ secrets.URL }}"}}, Retries: retries(11)},
+			wantErr:        "retries must be between 0 and 10",
+		},
+		{
+			name:           "non-object schema",
+			allowedDomains: []string{"hooks.slack.com"},
+			config:         &SendWebhookConfig{Endpoints: map[string]*WebhookEndpointConfig{"chat-ops": {URL: "${{ secrets.URL }}"}}, Schema: map[string]any{"type": "string"}},
+			wantErr:        "the payload schema must describe a JSON object",
+		},
+		{
+			name:           "invalid schema",
+			allowedDomains: []string{"hooks.slack.com"},
+			config:         &SendWebhookConfig{Endpoints: map[string]*WebhookEndpointConfig{"chat-ops": {URL: "${{ secrets.URL }}"}}, Schema: map[string]any{"type": "object", "required": "title"}},
+			wantErr:        "safe-outputs.send-webhook.schema is not a valid JSON Schema",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			compiler := NewCompiler()
+			err := compiler.validateSendWebhook(&SafeOutputsConfig{AllowedDomains: tt.allowedDomains, SendWebhook: tt.config})
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestIsWebhookHostAllowed(t *testing.T) {
+	tests := []struct {
+		host    string
+		allowed []string
+		want    bool
+	}{
+		{"hooks.slack.com", []string{"hooks.slack.com"}, true},
+		{"HOOKS.slack.com", []string{"https://hooks.slack.com"}, true},
+		{"a.b.example.com", []string{"*.example.com"}, true},
+		{"hooks.slack.com", []string{"http://hooks.slack.com"}, false},
+		{"evilexample.com", []string{"*.example.com"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.host, func(t *testing.T) {
+			assert.Equal(t, tt.want, isWebhookHostAllowed(tt.host, tt.allowed))
+		})
+	}
+}
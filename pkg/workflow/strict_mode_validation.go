@@ -20,6 +20,9 @@
 //  3. validateStrictNetwork() - Requires explicit network configuration
 //  4. validateStrictMCPNetwork() - Requires top-level network config for container-based MCP servers
 //
+// Agent stages run in their own jobs with their own permissions, network and tools, so
+// validateAgentStages applies the same checks to every stage.
+//
 // # Integration with Security Scanners
 //
 // Strict mode also affects the zizmor security scanner behavior (see pkg/cli/zizmor.go).
@@ -53,6 +56,19 @@ import (
 
 var strictModeValidationLog = logger.New("workflow:strict_mode_validation")
 
+// isStrictModeEnabled returns whether a workflow is compiled in strict mode.
+// Priority: CLI flag > frontmatter > schema default (true)
+func (c *Compiler) isStrictModeEnabled(frontmatter map[string]any) bool {
+	if c.strictMode {
+		return true
+	}
+	if strictValue, exists := frontmatter["strict"]; exists {
+		strictBool, ok := strictValue.(bool)
+		return ok && strictBool
+	}
+	return true
+}
+
 // validateStrictPermissions refuses write permissions in strict mode
 func (c *Compiler) validateStrictPermissions(frontmatter map[string]any) error {
 	permissionsValue, exists := frontmatter["permissions"]
@@ -63,8 +79,11 @@ func (c *Compiler) validateStrictPermissions(frontmatter map[string]any) error {
 	}
 
 	// Parse permissions using the PermissionsParser
-	perms := NewPermissionsParserFromValue(permissionsValue)
+	return validateStrictWritePermissions(NewPermissionsParserFromValue(permissionsValue))
+}
 
+// validateStrictWritePermissions refuses write permissions on sensitive scopes
+func validateStrictWritePermissions(perms *PermissionsParser) error {
 	// Check for write permissions on sensitive scopes
 	writePermissions := []string{"contents", "issues", "pull-requests"}
 	for _, scope := range writePermissions {
@@ -489,8 +508,8 @@ func (c *Compiler) validateStrictFirewall(engineID string, networkPermissions *N
 				continue
 			}
 
-			// Check if this is a known ecosystem identifier
-			ecosystemDomains := getEcosystemDomains(domain)
+			// Check if this is a known or custom ecosystem identifier
+			ecosystemDomains := resolveEcosystemDomains(networkPermissions, domain)
 			if len(ecosystemDomains) > 0 {
 				// This is a known ecosystem identifier - allowed in strict mode
 				strictModeValidationLog.Printf("Domain '%s' is a known ecosystem identifier", domain)
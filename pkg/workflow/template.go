@@ -13,6 +13,7 @@ var templateLog = logger.New("workflow:template")
 // wrapExpressionsInTemplateConditionals transforms template conditionals by wrapping
 // expressions in ${{ }}. For example:
 // {{#if github.event.issue.number}} becomes {{#if ${{ github.event.issue.number }} }}
+// Prompt variable references such as {{#if var.verbose}} are left unchanged.
 func wrapExpressionsInTemplateConditionals(markdown string) string {
 	// Pattern to match {{#if expression}} where expression is not already wrapped in ${{ }}
 	// This regex captures the entire {{#if ...}} block and handles nested }} within ${{ }} expressions
@@ -62,6 +63,13 @@ func wrapExpressionsInTemplateConditionals(markdown string) string {
 			return match // Placeholder reference, return as-is
 		}
 
+		// Check if expression is a prompt variable reference (starts with var.)
+		// These are resolved by the prompt template renderer, not by GitHub Actions
+		if strings.HasPrefix(expr, "var.") {
+			templateLog.Print("Prompt variable reference detected, skipping wrap")
+			return match // Prompt variable reference, return as-is
+		}
+
 		// Always wrap expressions that don't start with ${{ or ${ or __
 		templateLog.Printf("Wrapping expression: %s", expr)
 		return "{{#if ${{ " + expr + " }} }}"
@@ -70,6 +78,14 @@ func wrapExpressionsInTemplateConditionals(markdown string) string {
 	return result
 }
 
+// hasPromptTemplateSyntax reports whether markdown uses prompt template loops, partials or variables
+func hasPromptTemplateSyntax(markdown string) bool {
+	return strings.Contains(markdown, "{{#each ") ||
+		strings.Contains(markdown, "{{#partial ") ||
+		strings.Contains(markdown, "{{>") ||
+		promptVariableRefPattern.MatchString(markdown)
+}
+
 // generateInterpolationAndTemplateStep generates a step that interpolates GitHub expression variables
 // and renders template conditionals in the prompt file.
 // This combines both variable interpolation and template filtering into a single step.
@@ -83,15 +99,17 @@ func wrapExpressionsInTemplateConditionals(markdown string) string {
 //   - Uses actions/github-script action
 //   - Sets GH_AW_PROMPT environment variable to the prompt file path
 //   - Sets GH_AW_EXPR_* environment variables with the actual GitHub expressions (${{ ... }})
+//   - Sets GH_AW_PROMPT_VARIABLES and GH_AW_PROMPT_VAR_* when prompt-variables are declared
 //   - Runs interpolate_prompt.cjs script to replace placeholders and render template conditionals
 func (c *Compiler) generateInterpolationAndTemplateStep(yaml *strings.Builder, expressionMappings []*ExpressionMapping, data *WorkflowData) {
 	// Check if we need interpolation
 	hasExpressions := len(expressionMappings) > 0
 
 	// Check if we need template rendering
-	hasTemplatePattern := strings.Contains(data.MarkdownContent, "{{#if ")
+	hasTemplatePattern := strings.Contains(data.MarkdownContent, "{{#if ") || hasPromptTemplateSyntax(data.MarkdownContent)
 	hasGitHubContext := hasGitHubTool(data.ParsedTools)
-	hasTemplates := hasTemplatePattern || hasGitHubContext
+	hasPromptVariables := len(data.PromptVariables) > 0
+	hasTemplates := hasTemplatePattern || hasGitHubContext || hasPromptVariables
 
 	// Skip if neither interpolation nor template rendering is needed
 	if !hasExpressions && !hasTemplates {
@@ -113,6 +131,21 @@ func (c *Compiler) generateInterpolationAndTemplateStep(yaml *strings.Builder, e
 		fmt.Fprintf(yaml, "          %s: ${{ %s }}\n", mapping.EnvVar, mapping.Content)
 	}
 
+	// Add prompt variable declarations and their runtime value expressions
+	if hasPromptVariables {
+		variablesJSON, err := promptVariablesJSON(data.PromptVariables)
+		if err != nil {
+			templateLog.Printf("Failed to encode prompt variables: %v", err)
+		} else {
+			fmt.Fprintf(yaml, "          GH_AW_PROMPT_VARIABLES: %s\n", QuoteYAMLString(variablesJSON))
+		}
+		for _, variable := range data.PromptVariables {
+			if variable.Value != "" {
+				fmt.Fprintf(yaml, "          %s: %s\n", promptVariableEnvName(variable.Name), QuoteYAMLString(strings.TrimSpace(variable.Value)))
+			}
+		}
+	}
+
 	yaml.WriteString("        with:\n")
 	yaml.WriteString("          script: |\n")
 
@@ -122,6 +122,11 @@ Normal content here.`,
 			input:    "{{#if ${GH_AW_EXPR_ABC123}}}first{{/if}}\n{{#if ${GH_AW_EXPR_DEF456}}}second{{/if}}",
 			expected: "{{#if ${GH_AW_EXPR_ABC123}}}first{{/if}}\n{{#if ${GH_AW_EXPR_DEF456}}}second{{/if}}",
 		},
+		{
+			name:     "prompt variable reference (should not be wrapped)",
+			input:    "{{#if var.verbose}}content{{/if}}",
+			expected: "{{#if var.verbose}}content{{/if}}",
+		},
 		{
 			name:     "mixed github expression and env var reference",
 			input:    "{{#if github.actor}}first{{/if}}\n{{#if ${GH_AW_EXPR_ABC123}}}second{{/if}}",
@@ -178,7 +178,12 @@ func (c *Compiler) buildThreatDetectionSteps(data *WorkflowData, mainJobName str
 	}
 
 	// Step 1: Download agent artifacts
-	steps = append(steps, c.buildDownloadArtifactStep(mainJobName)...)
+	steps = append(steps, c.buildDownloadArtifactStep(data, mainJobName)...)
+
+	// Hand-offs of agent stages are part of the agent's input and are analyzed too
+	if len(data.AgentStages) > 0 {
+		steps = append(steps, buildHandoffDetectionDownloadStep()...)
+	}
 
 	// Step 2: Echo agent outputs for debugging
 	steps = append(steps, c.buildEchoAgentOutputsStep(mainJobName)...)
@@ -195,19 +200,19 @@ func (c *Compiler) buildThreatDetectionSteps(data *WorkflowData, mainJobName str
 	steps = append(steps, c.buildParsingStep()...)
 
 	// Step 6: Upload detection log artifact
-	steps = append(steps, c.buildUploadDetectionLogStep()...)
+	steps = append(steps, c.buildUploadDetectionLogStep(data)...)
 
 	return steps
 }
 
 // buildDownloadArtifactStep creates the artifact download step
 // Downloads from unified agent-artifacts (contains prompt, patch, etc.) and separate agent-output
-func (c *Compiler) buildDownloadArtifactStep(mainJobName string) []string {
+func (c *Compiler) buildDownloadArtifactStep(data *WorkflowData, mainJobName string) []string {
 	var steps []string
 
 	// Download unified agent-artifacts (contains prompt, patch, logs, etc.)
 	steps = append(steps, buildArtifactDownloadSteps(ArtifactDownloadConfig{
-		ArtifactName: "agent-artifacts",
+		ArtifactName: jobArtifactName(data, "agent-artifacts"),
 		DownloadPath: "/tmp/gh-aw/threat-detection/",
 		SetupEnvStep: false,
 		StepName:     "Download agent artifacts",
@@ -215,7 +220,7 @@ func (c *Compiler) buildDownloadArtifactStep(mainJobName string) []string {
 
 	// Download agent output artifact (still separate)
 	steps = append(steps, buildArtifactDownloadSteps(ArtifactDownloadConfig{
-		ArtifactName: constants.AgentOutputArtifactName,
+		ArtifactName: jobArtifactName(data, constants.AgentOutputArtifactName),
 		DownloadPath: "/tmp/gh-aw/threat-detection/",
 		SetupEnvStep: false,
 		StepName:     "Download agent output artifact",
@@ -450,13 +455,13 @@ func (c *Compiler) buildCustomThreatDetectionSteps(steps []any) []string {
 }
 
 // buildUploadDetectionLogStep creates the step to upload the detection log
-func (c *Compiler) buildUploadDetectionLogStep() []string {
+func (c *Compiler) buildUploadDetectionLogStep(data *WorkflowData) []string {
 	return []string{
 		"      - name: Upload threat detection log\n",
 		"        if: always()\n",
 		fmt.Sprintf("        uses: %s\n", GetActionPin("actions/upload-artifact")),
 		"        with:\n",
-		"          name: threat-detection.log\n",
+		"          name: " + jobArtifactName(data, "threat-detection.log") + "\n",
 		"          path: /tmp/gh-aw/threat-detection/detection.log\n",
 		"          if-no-files-found: ignore\n",
 	}
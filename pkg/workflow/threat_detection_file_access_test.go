@@ -175,7 +175,7 @@ func TestThreatDetectionTemplate_UsesFilePathPlaceholder(t *testing.T) {
 // TestBuildDownloadArtifactStep_IncludesRequiredArtifacts tests artifact download step generation
 func TestBuildDownloadArtifactStep_IncludesRequiredArtifacts(t *testing.T) {
 	compiler := createTestCompiler(t)
-	steps := compiler.buildDownloadArtifactStep("agent")
+	steps := compiler.buildDownloadArtifactStep(&WorkflowData{}, "agent")
 	stepsString := strings.Join(steps, "")
 
 	tests := []struct {
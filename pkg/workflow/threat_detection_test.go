@@ -679,7 +679,7 @@ func TestBuildUploadDetectionLogStep(t *testing.T) {
 	compiler := NewCompiler()
 
 	// Test that upload detection log step is created with correct properties
-	steps := compiler.buildUploadDetectionLogStep()
+	steps := compiler.buildUploadDetectionLogStep(&WorkflowData{})
 
 	if len(steps) == 0 {
 		t.Fatal("Expected non-empty steps for upload detection log")
@@ -823,7 +823,7 @@ func TestDownloadArtifactStepIncludesPrompt(t *testing.T) {
 	compiler := NewCompiler()
 
 	// Test that the download artifact step includes unified agent-artifacts download
-	steps := compiler.buildDownloadArtifactStep("agent")
+	steps := compiler.buildDownloadArtifactStep(&WorkflowData{}, "agent")
 
 	if len(steps) == 0 {
 		t.Fatal("Expected non-empty steps for download artifact")
@@ -0,0 +1,314 @@
+// Package workflow provides argument constraints for safe output and MCP server tools.
+//
+// # Tool Constraints
+//
+// Safe outputs and MCP tool allow-lists restrict which tools the agent can call.
+// Constraints additionally restrict the arguments of individual tools, for example
+// only allowing issue titles with a prefix and a fixed set of labels:
+//
+//	safe-outputs:
+//	  create-issue:
+//	  add-labels:
+//	  constraints:
+//	    create_issue:
+//	      title:
+//	        pattern: "\\[bot\\] .+"
+//	    add_labels:
+//	      labels:
+//	        enum: [bug, enhancement]
+//
+// Constraints are written to the safe outputs configuration (config.json). The safe
+// outputs MCP server checks every call of a constrained tool and rejects violations with
+// JSON-RPC error -32004, so the agent gets immediate feedback and the rejected call shows
+// up in the gateway's rpc-messages.jsonl. The ingestion step (collect_ndjson_output.cjs)
+// checks the collected items again, so items written around the MCP server are dropped.
+//
+// The github tool and custom MCP servers take the same rules under their own
+// constraints field:
+//
+//	tools:
+//	  github:
+//	    toolsets: [issues]
+//	    constraints:
+//	      create_issue:
+//	        owner:
+//	          enum: [my-org]
+//	        repo:
+//	          pattern: "docs-.*"
+//
+// These are rendered into the toolConstraints section of the MCP gateway configuration
+// (MCP Gateway Specification section 5.5). The gateway rejects violating calls with the
+// same error code before they reach the server and logs a constraint_violation event.
+// Gateways older than specification 1.9.0 reject the unknown field at startup, and
+// start_mcp_gateway.sh additionally checks the specVersion reported by /health, so the
+// constraints are never silently ignored.
+//
+// Bash commands and built-in engine tools such as web-fetch and edit run inside the
+// engine and never reach the gateway, so they cannot be constrained.
+//
+// Related files:
+//   - safe_outputs_config_generation.go: writes safe output constraints to config.json
+//   - actions/setup/js/safe_output_constraints.cjs: enforces safe output constraints
+//   - mcp_gateway_config.go: adds MCP server constraints to the gateway configuration
+//   - pkg/cli/gateway_logs.go: surfaces rejected calls in logs and audit
+package workflow
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/github/gh-aw/pkg/logger"
+	"github.com/github/gh-aw/pkg/stringutil"
+)
+
+var toolConstraintsLog = logger.New("workflow:tool_constraints")
+
+// ToolArgumentConstraint restricts the value of a single tool argument. Every rule that is
+// set must match; array values must match for every element.
+type ToolArgumentConstraint struct {
+	Pattern string   `json:"pattern,omitempty"` // regular expression the whole value must match
+	Domains []string `json:"domains,omitempty"` // allowed URL hosts (supports *.example.com)
+	Enum    []string `json:"enum,omitempty"`    // allowed values
+}
+
+// ToolConstraints maps tool name -> argument name -> constraint
+type ToolConstraints map[string]map[string]*ToolArgumentConstraint
+
+// MCPToolConstraints maps MCP server name -> tool name -> argument name -> constraint
+type MCPToolConstraints map[string]ToolConstraints
+
+// parseToolConstraints parses the safe-outputs constraints section. Tool names are
+// normalized to the underscore form used by the safe outputs MCP server.
+func parseToolConstraints(value any) ToolConstraints {
+	constraints := parseToolArgumentConstraints(value, stringutil.NormalizeSafeOutputIdentifier)
+	if len(constraints) > 0 {
+		toolConstraintsLog.Printf("Parsed argument constraints for %d safe output tools", len(constraints))
+	}
+	return constraints
+}
+
+// extractMCPToolConstraints collects the constraints sections of the github tool and
+// custom MCP servers. MCP tool names are kept as written, since servers may use dashes.
+func extractMCPToolConstraints(tools map[string]any) MCPToolConstraints {
+	constraints := make(MCPToolConstraints)
+	for serverName, toolValue := range tools {
+		toolConfig, ok := toolValue.(map[string]any)
+		if !ok {
+			continue
+		}
+		constraintsValue, hasConstraints := toolConfig["constraints"]
+		if !hasConstraints {
+			continue
+		}
+		// Keep servers whose constraints are empty or malformed so that validation reports them
+		serverConstraints := parseToolArgumentConstraints(constraintsValue, func(name string) string { return name })
+		if serverConstraints == nil {
+			serverConstraints = ToolConstraints{}
+		}
+		constraints[serverName] = serverConstraints
+	}
+
+	if len(constraints) == 0 {
+		return nil
+	}
+	toolConstraintsLog.Printf("Extracted argument constraints for %d MCP servers", len(constraints))
+	return constraints
+}
+
+// parseToolArgumentConstraints parses a tool name -> argument name -> rule map
+func parseToolArgumentConstraints(value any, normalizeToolName func(string) string) ToolConstraints {
+	constraintsMap, ok := value.(map[string]any)
+	if !ok {
+		return nil
+	}
+
+	constraints := make(ToolConstraints)
+	for toolName, argumentsValue := range constraintsMap {
+		toolConstraints := make(map[string]*ToolArgumentConstraint)
+		if argumentsMap, ok := argumentsValue.(map[string]any); ok {
+			for argumentName, ruleValue := range argumentsMap {
+				constraint := &ToolArgumentConstraint{}
+				if ruleMap, ok := ruleValue.(map[string]any); ok {
+					if pattern, ok := ruleMap["pattern"].(string); ok {
+						constraint.Pattern = pattern
+					}
+					constraint.Domains = extractStringSlice(ruleMap["domains"])
+					constraint.Enum = extractStringSlice(ruleMap["enum"])
+				}
+				toolConstraints[argumentName] = constraint
+			}
+		}
+		// Tools without arguments are kept so that validation reports them
+		constraints[normalizeToolName(toolName)] = toolConstraints
+	}
+
+	if len(constraints) == 0 {
+		return nil
+	}
+	return constraints
+}
+
+// extractStringSlice converts a YAML list to a string slice, skipping non-string items
+func extractStringSlice(value any) []string {
+	items, ok := value.([]any)
+	if !ok {
+		return nil
+	}
+	var result []string
+	for _, item := range items {
+		if str, ok := item.(string); ok {
+			result = append(result, str)
+		}
+	}
+	return result
+}
+
+// validateToolConstraints checks that every constrained tool is an enabled safe output,
+// that its constrained arguments exist and that every rule is valid
+func validateToolConstraints(data *WorkflowData, markdownPath string) error {
+	if data.SafeOutputs == nil || len(data.SafeOutputs.Constraints) == 0 {
+		return nil
+	}
+
+	toolsJSON, err := generateFilteredToolsJSON(data, markdownPath)
+	if err != nil {
+		return err
+	}
+	var tools []struct {
+		Name        string `json:"name"`
+		InputSchema struct {
+			Properties           map[string]any `json:"properties"`
+			AdditionalProperties any            `json:"additionalProperties"`
+		} `json:"inputSchema"`
+	}
+	if err := json.Unmarshal([]byte(toolsJSON), &tools); err != nil {
+		return fmt.Errorf("failed to parse safe output tools: %w", err)
+	}
+	enabledTools := make(map[string]map[string]any)
+	openTools := make(map[string]bool)
+	for _, tool := range tools {
+		enabledTools[tool.Name] = tool.InputSchema.Properties
+		openTools[tool.Name] = tool.InputSchema.AdditionalProperties == true
+	}
+
+	constraints := data.SafeOutputs.Constraints
+	for _, toolName := range sortedMapKeys(constraints) {
+		properties, enabled := enabledTools[toolName]
+		if !enabled {
+			return fmt.Errorf("safe-outputs.constraints.%s: '%s' is not an enabled safe output tool", toolName, toolName)
+		}
+		arguments := constraints[toolName]
+		if len(arguments) == 0 {
+			return fmt.Errorf("safe-outputs.constraints.%s: at least one argument constraint is required", toolName)
+		}
+		for _, argumentName := range sortedMapKeys(arguments) {
+			if _, known := properties[argumentName]; !known && !openTools[toolName] {
+				return fmt.Errorf("safe-outputs.constraints.%s.%s: '%s' has no argument '%s'. Arguments: %s", toolName, argumentName, toolName, argumentName, strings.Join(sortedMapKeys(properties), ", "))
+			}
+			if err := validateToolArgumentConstraint(arguments[argumentName]); err != nil {
+				return fmt.Errorf("safe-outputs.constraints.%s.%s: %w", toolName, argumentName, err)
+			}
+		}
+	}
+	return nil
+}
+
+// validateMCPToolConstraints checks that constraints are only set on the github tool and
+// custom MCP servers, that constrained tools are not excluded by the server's allowed list
+// and that every rule is valid
+func validateMCPToolConstraints(data *WorkflowData) error {
+	for _, serverName := range sortedMapKeys(data.MCPToolConstraints) {
+		toolConfig, _ := data.Tools[serverName].(map[string]any)
+		if isMCP, _ := hasMCPConfig(toolConfig); serverName != "github" && !isMCP {
+			return fmt.Errorf("%s.constraints: argument constraints are only supported on the github tool and mcp-servers entries, whose calls go through the MCP gateway", serverName)
+		}
+
+		allowed := extractStringSlice(toolConfig["allowed"])
+		serverConstraints := data.MCPToolConstraints[serverName]
+		if len(serverConstraints) == 0 {
+			return fmt.Errorf("%s.constraints: at least one tool constraint is required", serverName)
+		}
+		for _, toolName := range sortedMapKeys(serverConstraints) {
+			if len(allowed) > 0 && !slices.Contains(allowed, "*") && !slices.Contains(allowed, toolName) {
+				return fmt.Errorf("%s.constraints.%s: '%s' is not in the allowed list of '%s'", serverName, toolName, toolName, serverName)
+			}
+			arguments := serverConstraints[toolName]
+			if len(arguments) == 0 {
+				return fmt.Errorf("%s.constraints.%s: at least one argument constraint is required", serverName, toolName)
+			}
+			for _, argumentName := range sortedMapKeys(arguments) {
+				if err := validateToolArgumentConstraint(arguments[argumentName]); err != nil {
+					return fmt.Errorf("%s.constraints.%s.%s: %w", serverName, toolName, argumentName, err)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// unsupportedPatternGroup matches groups with inline flags or names, which Go accepts but
+// the JavaScript regular expressions used for enforcement do not (or interpret differently)
+var unsupportedPatternGroup = regexp.MustCompile(`\(\?[^:]`)
+
+// validateToolArgumentConstraint validates the rules of a single argument constraint
+func validateToolArgumentConstraint(constraint *ToolArgumentConstraint) error {
+	if constraint.Pattern == "" && len(constraint.Domains) == 0 && len(constraint.Enum) == 0 {
+		return errors.New("at least one of pattern, domains or enum is required")
+	}
+	if constraint.Pattern != "" {
+		if strings.Contains(constraint.Pattern, "${{") {
+			return errors.New("pattern must not contain GitHub Actions expressions")
+		}
+		if unsupportedPatternGroup.MatchString(constraint.Pattern) {
+			return errors.New("pattern must not use inline flags or named groups")
+		}
+		if _, err := regexp.Compile(constraint.Pattern); err != nil {
+			return fmt.Errorf("invalid pattern: %w", err)
+		}
+	}
+	for _, domain := range constraint.Domains {
+		if domain == "" || strings.Contains(domain, "/") || strings.Contains(domain, "${{") {
+			return fmt.Errorf("invalid domain %q: use a host name such as example.com or *.example.com", domain)
+		}
+	}
+	return nil
+}
+
+// sortedMapKeys returns the keys of a string-keyed map in sorted order
+func sortedMapKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// renderToolConstraintsJSON writes the toolConstraints section of the gateway configuration
+// for the servers in the configuration. The configuration is piped through an unquoted
+// heredoc, so shell metacharacters in patterns are escaped.
+func renderToolConstraintsJSON(builder *strings.Builder, constraints MCPToolConstraints, servers []string) {
+	rendered := make(MCPToolConstraints)
+	for _, server := range servers {
+		if serverConstraints, ok := constraints[server]; ok {
+			rendered[server] = serverConstraints
+		}
+	}
+	if len(rendered) == 0 {
+		return
+	}
+
+	// encoding/json sorts map keys, which keeps the lock file stable
+	content, err := json.MarshalIndent(rendered, "            ", "  ")
+	if err != nil {
+		toolConstraintsLog.Printf("Failed to marshal tool constraints: %v", err)
+		return
+	}
+	escaped := strings.NewReplacer(`\`, `\\`, "$", `\$`, "`", "\\`").Replace(string(content))
+	builder.WriteString("            \"toolConstraints\": " + escaped + ",\n")
+}
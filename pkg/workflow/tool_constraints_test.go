@@ -0,0 +1,353 @@
+//go:build !integration
+
+package workflow
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/github/gh-aw/pkg/stringutil"
+	"github.com/github/gh-aw/pkg/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseToolConstraints(t *testing.T) {
+	constraints := parseToolConstraints(map[string]any{
+		"create-issue": map[string]any{
+			"title": map[string]any{"pattern": `\[bot\] .+`},
+		},
+		"add_labels": map[string]any{
+			"labels": map[string]any{"enum": []any{"bug", "enhancement"}},
+		},
+		"add_comment": map[string]any{
+			"body": map[string]any{"domains": []any{"docs.example.com"}},
+		},
+	})
+
+	require.Len(t, constraints, 3, "Should parse constraints for every tool")
+	assert.Equal(t, `\[bot\] .+`, constraints["create_issue"]["title"].Pattern, "Tool names should be normalized to underscores")
+	assert.Equal(t, []string{"bug", "enhancement"}, constraints["add_labels"]["labels"].Enum)
+	assert.Equal(t, []string{"docs.example.com"}, constraints["add_comment"]["body"].Domains)
+
+	assert.Nil(t, parseToolConstraints(map[string]any{}), "No constraints should return nil")
+	assert.Nil(t, parseToolConstraints("invalid"), "Non-map value should return nil")
+}
+
+func TestValidateToolConstraints(t *testing.T) {
+	tests := []struct {
+		name        string
+		constraints ToolConstraints
+		wantErr     string
+	}{
+		{
+			name:        "valid pattern on enabled tool",
+			constraints: ToolConstraints{"create_issue": {"title": {Pattern: `\[bot\] .+`}}},
+		},
+		{
+			name:        "valid enum on array argument",
+			constraints: ToolConstraints{"add_labels": {"labels": {Enum: []string{"bug"}}}},
+		},
+		{
+			name:        "tool not enabled",
+			constraints: ToolConstraints{"add_comment": {"body": {Pattern: ".+"}}},
+			wantErr:     "'add_comment' is not an enabled safe output tool",
+		},
+		{
+			name:        "unknown argument",
+			constraints: ToolConstraints{"create_issue": {"repo": {Pattern: "my-org/.+"}}},
+			wantErr:     "'create_issue' has no argument 'repo'",
+		},
+		{
+			name:        "no arguments",
+			constraints: ToolConstraints{"create_issue": {}},
+			wantErr:     "at least one argument constraint is required",
+		},
+		{
+			name:        "empty rule",
+			constraints: ToolConstraints{"create_issue": {"title": {}}},
+			wantErr:     "at least one of pattern, domains or enum is required",
+		},
+		{
+			name:        "invalid pattern",
+			constraints: ToolConstraints{"create_issue": {"title": {Pattern: "(["}}},
+			wantErr:     "invalid pattern",
+		},
+		{
+			name:        "inline flags",
+			constraints: ToolConstraints{"create_issue": {"title": {Pattern: "(?i)bot"}}},
+			wantErr:     "must not use inline flags or named groups",
+		},
+		{
+			name:        "named group",
+			constraints: ToolConstraints{"create_issue": {"title": {Pattern: "(?P<prefix>bot) .+"}}},
+			wantErr:     "must not use inline flags or named groups",
+		},
+		{
+			name:        "non-capturing group",
+			constraints: ToolConstraints{"create_issue": {"title": {Pattern: "(?:bot|ci) .+"}}},
+		},
+		{
+			name:        "expression in pattern",
+			constraints: ToolConstraints{"create_issue": {"title": {Pattern: "${{ github.actor }}.*"}}},
+			wantErr:     "must not contain GitHub Actions expressions",
+		},
+		{
+			name:        "domain with path",
+			constraints: ToolConstraints{"create_issue": {"body": {Domains: []string{"example.com/docs"}}}},
+			wantErr:     "invalid domain",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := &WorkflowData{
+				SafeOutputs: &SafeOutputsConfig{
+					CreateIssues: &CreateIssuesConfig{},
+					AddLabels:    &AddLabelsConfig{},
+					Constraints:  tt.constraints,
+				},
+			}
+			err := validateToolConstraints(data, "test.md")
+			if tt.wantErr == "" {
+				assert.NoError(t, err, "Constraints should be valid")
+				return
+			}
+			require.Error(t, err, "Constraints should be rejected")
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestToolConstraintsCompile(t *testing.T) {
+	tmpDir := testutil.TempDir(t, "tool-constraints-compile")
+
+	workflow := `---
+on: workflow_dispatch
+permissions:
+  contents: read
+engine: copilot
+safe-outputs:
+  create-issue:
+  add-labels:
+  constraints:
+    create-issue:
+      title:
+        pattern: "\\[bot\\] .+"
+    add-labels:
+      labels:
+        enum: [bug, enhancement]
+---
+
+# Triage
+
+File an issue.
+`
+	workflowPath := filepath.Join(tmpDir, "constraints.md")
+	require.NoError(t, os.WriteFile(workflowPath, []byte(workflow), 0644))
+
+	require.NoError(t, NewCompiler().CompileWorkflow(workflowPath), "Workflow with constraints should compile")
+
+	lockContent, err := os.ReadFile(stringutil.MarkdownToLockFile(workflowPath))
+	require.NoError(t, err, "Lock file should be written")
+	lock := string(lockContent)
+	assert.Contains(t, lock, `"constraints":{"title":{"pattern":`, "Safe outputs config should include the create_issue constraints")
+	assert.Contains(t, lock, `"constraints":{"labels":{"enum":["bug","enhancement"]}}`, "Safe outputs config should include the add_labels constraints")
+	assert.NotContains(t, lock, "toolConstraints", "Gateway config should not include safe output constraints")
+	assert.NotContains(t, lock, "MCP_GATEWAY_MIN_SPEC_VERSION", "Safe output constraints should not require a newer gateway")
+}
+
+func TestToolConstraintsCompileRejectsDisabledTool(t *testing.T) {
+	tmpDir := testutil.TempDir(t, "tool-constraints-disabled")
+
+	workflow := `---
+on: workflow_dispatch
+permissions:
+  contents: read
+engine: copilot
+safe-outputs:
+  create-issue:
+  constraints:
+    add-comment:
+      body:
+        pattern: ".+"
+---
+
+# Triage
+
+File an issue.
+`
+	workflowPath := filepath.Join(tmpDir, "constraints.md")
+	require.NoError(t, os.WriteFile(workflowPath, []byte(workflow), 0644))
+
+	err := NewCompiler().CompileWorkflow(workflowPath)
+	require.Error(t, err, "Constraints on a disabled tool should be rejected")
+	assert.Contains(t, err.Error(), "'add_comment' is not an enabled safe output tool")
+}
+
+func TestExtractMCPToolConstraints(t *testing.T) {
+	constraints := extractMCPToolConstraints(map[string]any{
+		"github": map[string]any{
+			"toolsets": []any{"issues"},
+			"constraints": map[string]any{
+				"create_issue": map[string]any{
+					"repo": map[string]any{"pattern": "docs-.*"},
+				},
+			},
+		},
+		"fetch": map[string]any{
+			"container": "mcp/fetch",
+			"constraints": map[string]any{
+				"fetch-page": map[string]any{
+					"url": map[string]any{"domains": []any{"docs.example.com"}},
+				},
+			},
+		},
+		"notion":    map[string]any{"container": "mcp/notion"},
+		"bash":      []any{"echo"},
+		"malformed": map[string]any{"container": "mcp/x", "constraints": "invalid"},
+	})
+
+	require.Len(t, constraints, 3, "Should collect every server with a constraints field")
+	assert.Equal(t, "docs-.*", constraints["github"]["create_issue"]["repo"].Pattern)
+	assert.Equal(t, []string{"docs.example.com"}, constraints["fetch"]["fetch-page"]["url"].Domains, "MCP tool names should be kept as written")
+	assert.Empty(t, constraints["malformed"], "Malformed constraints should be kept for validation")
+
+	assert.Nil(t, extractMCPToolConstraints(map[string]any{"github": nil}), "No constraints should return nil")
+}
+
+func TestValidateMCPToolConstraints(t *testing.T) {
+	tools := map[string]any{
+		"github":     map[string]any{"allowed": []any{"create_issue", "issue_read"}},
+		"fetch":      map[string]any{"container": "mcp/fetch"},
+		"playwright": map[string]any{"allowed_domains": []any{"example.com"}},
+	}
+
+	tests := []struct {
+		name        string
+		constraints MCPToolConstraints
+		wantErr     string
+	}{
+		{
+			name:        "github tool in allowed list",
+			constraints: MCPToolConstraints{"github": {"create_issue": {"repo": {Pattern: "docs-.*"}}}},
+		},
+		{
+			name:        "custom MCP server without allowed list",
+			constraints: MCPToolConstraints{"fetch": {"fetch": {"url": {Domains: []string{"*.example.com"}}}}},
+		},
+		{
+			name:        "github tool not in allowed list",
+			constraints: MCPToolConstraints{"github": {"create_pull_request": {"head": {Pattern: "bot/.+"}}}},
+			wantErr:     "'create_pull_request' is not in the allowed list of 'github'",
+		},
+		{
+			name:        "built-in tool",
+			constraints: MCPToolConstraints{"playwright": {"browser_navigate": {"url": {Domains: []string{"example.com"}}}}},
+			wantErr:     "only supported on the github tool and mcp-servers entries",
+		},
+		{
+			name:        "no tools",
+			constraints: MCPToolConstraints{"fetch": {}},
+			wantErr:     "at least one tool constraint is required",
+		},
+		{
+			name:        "no arguments",
+			constraints: MCPToolConstraints{"fetch": {"fetch": {}}},
+			wantErr:     "at least one argument constraint is required",
+		},
+		{
+			name:        "invalid pattern",
+			constraints: MCPToolConstraints{"github": {"issue_read": {"repo": {Pattern: "(["}}}},
+			wantErr:     "github.constraints.issue_read.repo: invalid pattern",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateMCPToolConstraints(&WorkflowData{Tools: tools, MCPToolConstraints: tt.constraints})
+			if tt.wantErr == "" {
+				assert.NoError(t, err, "Constraints should be valid")
+				return
+			}
+			require.Error(t, err, "Constraints should be rejected")
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestMCPToolConstraintsCompile(t *testing.T) {
+	tmpDir := testutil.TempDir(t, "mcp-tool-constraints-compile")
+
+	workflow := `---
+on: workflow_dispatch
+permissions:
+  contents: read
+  issues: read
+engine: copilot
+tools:
+  github:
+    toolsets: [issues]
+    constraints:
+      issue_read:
+        owner:
+          enum: [my-org]
+        repo:
+          pattern: "docs-\\w+"
+mcp-servers:
+  fetch:
+    container: "mcp/fetch"
+    allowed: ["fetch"]
+    constraints:
+      fetch:
+        url:
+          domains: ["docs.example.com"]
+---
+
+# Triage
+
+Read the issue.
+`
+	workflowPath := filepath.Join(tmpDir, "constraints.md")
+	require.NoError(t, os.WriteFile(workflowPath, []byte(workflow), 0644))
+
+	require.NoError(t, NewCompiler().CompileWorkflow(workflowPath), "Workflow with MCP constraints should compile")
+
+	lockContent, err := os.ReadFile(stringutil.MarkdownToLockFile(workflowPath))
+	require.NoError(t, err, "Lock file should be written")
+	lock := string(lockContent)
+	assert.Contains(t, lock, `"toolConstraints": {`, "Gateway config should include the constraints")
+	assert.Contains(t, lock, `"pattern": "docs-\\\\w+"`, "Backslashes should be escaped for the unquoted heredoc")
+	assert.Contains(t, lock, `"enum": [`, "Gateway config should include the github constraints")
+	assert.Contains(t, lock, `"docs.example.com"`, "Gateway config should include the fetch constraints")
+	assert.Contains(t, lock, `export MCP_GATEWAY_MIN_SPEC_VERSION="1.9.0"`, "Gateway start should require a specification version with constraints")
+	assert.NotContains(t, lock, `"constraints":`, "Constraints should not leak into the server configuration")
+}
+
+func TestMCPToolConstraintsCompileRejectsBuiltinTool(t *testing.T) {
+	tmpDir := testutil.TempDir(t, "mcp-tool-constraints-builtin")
+
+	workflow := `---
+on: workflow_dispatch
+permissions:
+  contents: read
+engine: copilot
+tools:
+  playwright:
+    constraints:
+      browser_navigate:
+        url:
+          domains: ["example.com"]
+---
+
+# Browse
+
+Open the page.
+`
+	workflowPath := filepath.Join(tmpDir, "constraints.md")
+	require.NoError(t, os.WriteFile(workflowPath, []byte(workflow), 0644))
+
+	err := NewCompiler().CompileWorkflow(workflowPath)
+	require.Error(t, err, "Constraints on a built-in tool should be rejected")
+}
@@ -293,6 +293,14 @@ func enhanceToolDescription(toolName, baseDescription string, safeOutputs *SafeO
 			}
 		}
 
+	case "send_webhook":
+		if config := safeOutputs.SendWebhook; config != nil {
+			if templatableIntValue(config.Max) > 0 {
+				constraints = append(constraints, fmt.Sprintf("Maximum %d webhook(s) can be sent.", templatableIntValue(config.Max)))
+			}
+			constraints = append(constraints, fmt.Sprintf("Available endpoints: %v.", config.EndpointNames()))
+		}
+
 	case "assign_milestone":
 		if config := safeOutputs.AssignMilestone; config != nil {
 			if templatableIntValue(config.Max) > 0 {
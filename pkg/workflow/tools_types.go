@@ -359,6 +359,10 @@ type MCPGatewayRuntimeConfig struct {
 	Domain         string            `yaml:"domain,omitempty"`         // Domain for gateway URL (localhost or host.docker.internal)
 	Mounts         []string          `yaml:"mounts,omitempty"`         // Volume mounts for the gateway container (format: "source:dest:mode")
 	PayloadDir     string            `yaml:"payload-dir,omitempty"`    // Directory path for storing large payload JSON files (must be absolute path)
+	Record         bool              `yaml:"record,omitempty"`         // Record tool calls and responses to a cassette
+	Replay         string            `yaml:"replay,omitempty"`         // Cassette to serve recorded tool responses from (repository-relative path)
+
+	ToolConstraints MCPToolConstraints `yaml:"-"` // Argument constraints of the github tool and MCP servers (tools.<server>.constraints)
 }
 
 // HasTool checks if a tool is present in the configuration
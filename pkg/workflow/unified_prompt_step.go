@@ -13,6 +13,8 @@ var unifiedPromptLog = logger.New("workflow:unified_prompt_step")
 
 // PromptSection represents a section of prompt text to be appended
 type PromptSection struct {
+	// Name identifies the section in prompt budget reports and trimming markers (e.g. "safe-outputs")
+	Name string
 	// Content is the actual prompt text or a reference to a file
 	Content string
 	// IsFile indicates if Content is a filename (true) or inline text (false)
@@ -225,6 +227,7 @@ func (c *Compiler) collectPromptSections(data *WorkflowData) []PromptSection {
 	if !isFeatureEnabled(constants.DisableXPIAPromptFeatureFlag, data) {
 		unifiedPromptLog.Print("Adding XPIA section")
 		sections = append(sections, PromptSection{
+			Name:    "xpia",
 			Content: xpiaPromptFile,
 			IsFile:  true,
 		})
@@ -235,6 +238,7 @@ func (c *Compiler) collectPromptSections(data *WorkflowData) []PromptSection {
 	// 1. Temporary folder instructions (always included)
 	unifiedPromptLog.Print("Adding temp folder section")
 	sections = append(sections, PromptSection{
+		Name:    "temp-folder",
 		Content: tempFolderPromptFile,
 		IsFile:  true,
 	})
@@ -242,6 +246,7 @@ func (c *Compiler) collectPromptSections(data *WorkflowData) []PromptSection {
 	// 2. Markdown generation instructions (always included)
 	unifiedPromptLog.Print("Adding markdown section")
 	sections = append(sections, PromptSection{
+		Name:    "markdown",
 		Content: markdownPromptFile,
 		IsFile:  true,
 	})
@@ -250,6 +255,7 @@ func (c *Compiler) collectPromptSections(data *WorkflowData) []PromptSection {
 	if hasPlaywrightTool(data.ParsedTools) {
 		unifiedPromptLog.Print("Adding playwright section")
 		sections = append(sections, PromptSection{
+			Name:    "playwright",
 			Content: playwrightPromptFile,
 			IsFile:  true,
 		})
@@ -260,6 +266,7 @@ func (c *Compiler) collectPromptSections(data *WorkflowData) []PromptSection {
 		unifiedPromptLog.Print("Adding trial mode section")
 		trialContent := fmt.Sprintf("## Note\nThis workflow is running in directory $GITHUB_WORKSPACE, but that directory actually contains the contents of the repository '%s'.", c.trialLogicalRepoSlug)
 		sections = append(sections, PromptSection{
+			Name:    "trial-mode",
 			Content: trialContent,
 			IsFile:  false,
 		})
@@ -270,6 +277,7 @@ func (c *Compiler) collectPromptSections(data *WorkflowData) []PromptSection {
 		unifiedPromptLog.Printf("Adding cache memory section: caches=%d", len(data.CacheMemoryConfig.Caches))
 		section := buildCacheMemoryPromptSection(data.CacheMemoryConfig)
 		if section != nil {
+			section.Name = "cache-memory"
 			sections = append(sections, *section)
 		}
 	}
@@ -279,6 +287,7 @@ func (c *Compiler) collectPromptSections(data *WorkflowData) []PromptSection {
 		unifiedPromptLog.Printf("Adding repo memory section: memories=%d", len(data.RepoMemoryConfig.Memories))
 		section := buildRepoMemoryPromptSection(data.RepoMemoryConfig)
 		if section != nil {
+			section.Name = "repo-memory"
 			sections = append(sections, *section)
 		}
 	}
@@ -288,11 +297,15 @@ func (c *Compiler) collectPromptSections(data *WorkflowData) []PromptSection {
 		unifiedPromptLog.Print("Adding safe outputs section")
 		// Static intro from file (gh CLI warning, temporary ID rules, noop note)
 		sections = append(sections, PromptSection{
+			Name:    "safe-outputs",
 			Content: safeOutputsPromptFile,
 			IsFile:  true,
 		})
 		// Per-tool sections: opening tag + tools list (inline), tool instruction files, closing tag
-		sections = append(sections, buildSafeOutputsSections(data.SafeOutputs)...)
+		for _, section := range buildSafeOutputsSections(data.SafeOutputs) {
+			section.Name = "safe-outputs"
+			sections = append(sections, section)
+		}
 	}
 	// 8. GitHub context (if GitHub tool is enabled)
 	if hasGitHubTool(data.ParsedTools) {
@@ -311,6 +324,7 @@ func (c *Compiler) collectPromptSections(data *WorkflowData) []PromptSection {
 			}
 
 			sections = append(sections, PromptSection{
+				Name:    "github-context",
 				Content: modifiedPromptText,
 				IsFile:  false,
 				EnvVars: envVars,
@@ -318,7 +332,17 @@ func (c *Compiler) collectPromptSections(data *WorkflowData) []PromptSection {
 		}
 	}
 
-	// 9. PR context (if comment-related triggers and checkout is needed)
+	// 9. Hand-off files of agent stages (final agent job only; stage jobs write their own prompt)
+	if data.CurrentStage == nil && len(data.AgentStages) > 0 {
+		unifiedPromptLog.Printf("Adding hand-off section for %d agent stages", len(data.AgentStages))
+		sections = append(sections, PromptSection{
+			Name:    "handoff",
+			Content: buildHandoffPromptText(data.AgentStages),
+			IsFile:  false,
+		})
+	}
+
+	// 10. PR context (if comment-related triggers and checkout is needed)
 	hasCommentTriggers := c.hasCommentRelatedTriggers(data)
 	needsCheckout := c.shouldAddCheckoutStep(data)
 	permParser := NewPermissionsParser(data.Permissions)
@@ -338,6 +362,7 @@ func (c *Compiler) collectPromptSections(data *WorkflowData) []PromptSection {
 		}
 
 		sections = append(sections, PromptSection{
+			Name:           "pr-context",
 			Content:        prContextPromptFile,
 			IsFile:         true,
 			ShellCondition: shellCondition,
@@ -446,6 +471,10 @@ func (c *Compiler) generateUnifiedPromptCreationStep(yaml *strings.Builder, buil
 	// Track if we're inside a heredoc
 	inHeredoc := false
 
+	// When runtime trimming is enabled, each built-in section is preceded by a section marker
+	trimPrompt := isPromptTrimEnabled(data)
+	lastSectionName := ""
+
 	// 1. Write built-in sections first (prepended), wrapped in <system> tags
 	if len(builtinSections) > 0 {
 		// Open system tag for built-in prompts
@@ -458,6 +487,15 @@ func (c *Compiler) generateUnifiedPromptCreationStep(yaml *strings.Builder, buil
 		unifiedPromptLog.Printf("Writing built-in section %d/%d: hasCondition=%v, isFile=%v",
 			i+1, len(builtinSections), section.ShellCondition != "", section.IsFile)
 
+		if trimPrompt && section.Name != "" && section.Name != lastSectionName {
+			if inHeredoc {
+				yaml.WriteString("          " + delimiter + "\n")
+				inHeredoc = false
+			}
+			fmt.Fprintf(yaml, "          echo '%s'\n", promptSectionMarker(section.Name, promptSectionPriority(section.Name)))
+			lastSectionName = section.Name
+		}
+
 		if section.ShellCondition != "" {
 			// Close heredoc if open, add conditional
 			if inHeredoc {
@@ -518,6 +556,10 @@ func (c *Compiler) generateUnifiedPromptCreationStep(yaml *strings.Builder, buil
 			yaml.WriteString("          " + delimiter + "\n")
 			inHeredoc = false
 		}
+		// The closing tag starts a required section so that it is never trimmed with the last built-in section
+		if trimPrompt {
+			fmt.Fprintf(yaml, "          echo '%s'\n", promptSectionMarker("system", PromptPriorityRequired))
+		}
 		yaml.WriteString("          cat << '" + delimiter + "'\n")
 		yaml.WriteString("          </system>\n")
 		yaml.WriteString("          " + delimiter + "\n")
@@ -699,6 +741,9 @@ func buildSafeOutputsSections(safeOutputs *SafeOutputsConfig) []PromptSection {
 	if safeOutputs.DispatchWorkflow != nil {
 		tools = append(tools, "dispatch_workflow")
 	}
+	if safeOutputs.SendWebhook != nil {
+		tools = append(tools, "send_webhook")
+	}
 	if safeOutputs.MissingTool != nil {
 		tools = append(tools, "missing_tool")
 	}
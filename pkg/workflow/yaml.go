@@ -84,6 +84,8 @@
 package workflow
 
 import (
+	"bytes"
+	"encoding/json"
 	"regexp"
 	"slices"
 	"sort"
@@ -304,3 +306,15 @@ func CleanYAMLNullValues(yamlStr string) string {
 
 	return strings.Join(lines, "\n")
 }
+
+// QuoteYAMLString returns value as a double-quoted YAML scalar that is safe to write
+// after "key: " on a single line. A JSON string is a valid YAML double-quoted scalar,
+// so newlines, quotes and control characters are escaped and cannot start a new key.
+func QuoteYAMLString(value string) string {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	// Encoding a string cannot fail
+	_ = encoder.Encode(value)
+	return strings.TrimSuffix(buf.String(), "\n")
+}
@@ -406,3 +406,30 @@ func TestExtractTopLevelYAMLSectionWithOrdering(t *testing.T) {
 		})
 	}
 }
+
+func TestQuoteYAMLString(t *testing.T) {
+	tests := []string{
+		"plain",
+		"${{ inputs.labels }}",
+		"${{ inputs.a }}\ninjected: true",
+		`{"focus":{"type":"string","default":"a \"quoted\" <value>"}}`,
+		"tab\tand\r\ncontrol\x01",
+		"unicode ✓ and  ",
+	}
+
+	for _, value := range tests {
+		quoted := QuoteYAMLString(value)
+		if strings.Contains(quoted, "\n") {
+			t.Errorf("QuoteYAMLString(%q) = %s, want a single line", value, quoted)
+		}
+
+		var parsed map[string]string
+		if err := yaml.Unmarshal([]byte("key: "+quoted+"\n"), &parsed); err != nil {
+			t.Errorf("QuoteYAMLString(%q) = %s is not valid YAML: %v", value, quoted, err)
+			continue
+		}
+		if len(parsed) != 1 || parsed["key"] != value {
+			t.Errorf("QuoteYAMLString(%q) round-tripped to %q", value, parsed)
+		}
+	}
+}